@@ -1,17 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"finance-management/internal/config"
 	"finance-management/internal/handlers"
+	"finance-management/internal/migrate"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Cancelled on SIGINT or SIGTERM to shut the server and background jobs down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Set Gin mode based on environment
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.DebugMode)
@@ -23,6 +34,11 @@ func main() {
 	}
 	defer config.CloseDatabase()
 
+	// Apply or verify the embedded migrations before serving requests
+	if err := prepareSchema(ctx); err != nil {
+		log.Fatal("Database schema check failed: ", err)
+	}
+
 	// Create Gin router
 	r := gin.Default()
 
@@ -30,23 +46,71 @@ func main() {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://127.0.0.1:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", handlers.ActorHeader, handlers.IfMatchHeader, handlers.IdempotencyKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", "ETag", handlers.IdempotentReplayedHeader},
 		AllowCredentials: true,
 	}))
 
 	// Setup routes
-	handlers.SetupRoutes(r)
+	jobs := handlers.SetupRoutes(r)
 
 	// Get port from environment or use default
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
+	srv := &http.Server{Addr: ":" + port, Handler: r}
+
+	// Run the background jobs until shutdown unless BACKGROUND_JOBS=false
+	if config.BackgroundJobsEnabled() {
+		jobs.Start(ctx, time.Hour)
+	} else {
+		log.Printf("Background jobs disabled")
+	}
 
 	// Start server
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️  Server shutdown failed: %v", err)
+	}
+	// Let a job run in progress finish before the database is closed
+	jobs.Wait()
+}
+
+// prepareSchema applies pending migrations when AUTO_MIGRATE=true and refuses
+// to start when the schema does not match the migrations embedded in the binary
+func prepareSchema(ctx context.Context) error {
+	migrations, err := migrate.Embedded()
+	if err != nil {
+		return err
+	}
+	sqlDB, err := config.GetDB().DB()
+	if err != nil {
+		return err
+	}
+	m := migrate.New(sqlDB, migrations)
+
+	if os.Getenv("AUTO_MIGRATE") == "true" {
+		n, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("✅ Auto-migrate applied %d migration(s)", n)
+	}
+
+	if err := m.Check(ctx); err != nil {
+		return err
 	}
+	log.Printf("✅ Database schema at version %s", migrate.LatestVersion(migrations))
+	return nil
 }
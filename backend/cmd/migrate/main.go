@@ -1,27 +1,71 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
-	"sort"
+	"strconv"
+	"text/tabwriter"
 
 	"finance-management/internal/config"
+	"finance-management/internal/migrate"
 
 	_ "github.com/lib/pq"
 )
 
+const usage = `Usage: migrate [-dir DIR] [command]
+
+Uses the migrations embedded in the binary unless -dir is given.
+
+Commands:
+  up          Apply all pending migrations (default)
+  down [N]    Roll back the last N migrations (default 1)
+  status      Show applied and pending migrations
+  redo        Roll back and re-apply the last migration
+  to VERSION  Migrate up or down to VERSION (0 rolls back everything)
+`
+
 func main() {
+	dir := flag.String("dir", "", "read {version}_{name}.up.sql/.down.sql files from this directory instead of the embedded copy")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	// Backwards compatibility: `migrate <dir>` used to take the directory positionally
+	if len(args) == 1 {
+		if info, err := os.Stat(args[0]); err == nil && info.IsDir() {
+			*dir = args[0]
+			args = nil
+		}
+	}
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	// Load migrations before connecting so file errors surface immediately
+	var migrations []migrate.Migration
+	var err error
+	if *dir != "" {
+		migrations, err = migrate.Load(os.DirFS(*dir))
+	} else {
+		migrations, err = migrate.Embedded()
+	}
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+
 	// Get database configuration
 	dbConfig := config.GetDatabaseConfig()
 
-	// Create connection string
-	dsn := dbConfig.GetDSN()
-
 	// Connect to database
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open("postgres", dbConfig.GetDSN())
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -32,75 +76,71 @@ func main() {
 		log.Fatal("Failed to ping database:", err)
 	}
 
-	// Get migration directory
-	migrationDir := "db/migrations"
-	if len(os.Args) > 1 {
-		migrationDir = os.Args[1]
-	}
-
-	// Run migrations
-	if err := runMigrations(db, migrationDir); err != nil {
-		log.Fatal("Migration failed:", err)
+	if err := run(context.Background(), migrate.New(db, migrations), command, args); err != nil {
+		log.Fatal("Migration failed: ", err)
 	}
-
-	log.Println("✅ Migrations completed successfully")
 }
 
-func runMigrations(db *sql.DB, migrationDir string) error {
-	// Read migration files
-	files, err := filepath.Glob(filepath.Join(migrationDir, "*.sql"))
-	if err != nil {
-		return fmt.Errorf("failed to read migration files: %w", err)
-	}
-
-	// Use an application-owned migrations table to avoid conflicts with other tools
-	createMigrationsTable := `
-        CREATE TABLE IF NOT EXISTS app_schema_migrations (
-            version VARCHAR(255) PRIMARY KEY,
-            applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-        );
-    `
-	if _, err := db.Exec(createMigrationsTable); err != nil {
-		return fmt.Errorf("failed to create migrations table: %w", err)
-	}
-
-	// Sort files to ensure proper order
-	sort.Strings(files)
-
-	// Apply each migration
-	for _, file := range files {
-		version := filepath.Base(file)
-
-		// Check if migration already applied
-		var count int
-		err := db.QueryRow("SELECT COUNT(*) FROM app_schema_migrations WHERE version = $1", version).Scan(&count)
+func run(ctx context.Context, m *migrate.Migrator, command string, args []string) error {
+	switch command {
+	case "up":
+		n, err := m.Up(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to check migration status: %w", err)
+			return err
 		}
-
-		if count > 0 {
-			log.Printf("⏭️  Skipping %s (already applied)", version)
-			continue
+		if n == 0 {
+			log.Println("⏭️  No pending migrations")
 		}
-
-		// Read and execute migration
-		content, err := os.ReadFile(file)
+		log.Println("✅ Migrations completed successfully")
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid number of migrations %q", args[1])
+			}
+			steps = parsed
+		}
+		n, err := m.Down(ctx, steps)
 		if err != nil {
-			return fmt.Errorf("failed to read migration file %s: %w", file, err)
+			return err
 		}
-
-		log.Printf("🔄 Applying migration: %s", version)
-		if _, err := db.Exec(string(content)); err != nil {
-			return fmt.Errorf("failed to apply migration %s: %w", version, err)
+		log.Printf("✅ Rolled back %d migration(s)", n)
+	case "redo":
+		if err := m.Redo(ctx); err != nil {
+			return err
 		}
-
-		// Record migration as applied
-		if _, err := db.Exec("INSERT INTO app_schema_migrations (version) VALUES ($1)", version); err != nil {
-			return fmt.Errorf("failed to record migration %s: %w", version, err)
+		log.Println("✅ Redo completed successfully")
+	case "to":
+		if len(args) < 2 {
+			return fmt.Errorf("to requires a target version")
 		}
-
-		log.Printf("✅ Applied migration: %s", version)
+		if err := m.To(ctx, args[1]); err != nil {
+			return err
+		}
+		log.Printf("✅ Database migrated to version %s", args[1])
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printStatus(statuses)
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command %q", command)
 	}
-
 	return nil
 }
+
+func printStatus(statuses []migrate.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range statuses {
+		appliedAt := "-"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Version, s.Name, s.State, appliedAt)
+	}
+	w.Flush()
+}
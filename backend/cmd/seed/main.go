@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"finance-management/internal/config"
+	"finance-management/internal/repository"
+	"finance-management/internal/seed"
+)
+
+func main() {
+	seedValue := flag.Int64("seed", time.Now().UnixNano(), "random seed; reuse it together with -end to reproduce the same data")
+	users := flag.Int("users", 1, "number of users to generate")
+	years := flag.Int("years", 2, "years of history per user")
+	notes := flag.Int("notes", 12, "notes per user")
+	end := flag.String("end", time.Now().UTC().Format("2006-01"), "generate history up to (not including) this month, YYYY-MM")
+	defaultUser := flag.Bool("default-user", true, "make the first user the default user the API serves")
+	workers := flag.Int("workers", 4, "users to seed concurrently")
+	load := flag.String("load", "", "instead of seeding, send synthetic load to the API at this URL, e.g. http://localhost:8080")
+	rate := flag.Float64("rate", 20, "load mode: requests started per second")
+	duration := flag.Duration("duration", 30*time.Second, "load mode: how long to send requests")
+	mix := flag.String("mix", "", "load mode: scenario weights as name=weight pairs, e.g. list_expenses=60,create_expense=40 (default: a read-heavy mix)")
+	flag.Parse()
+
+	if *load != "" {
+		runLoad(*load, *rate, *duration, *mix, *seedValue)
+		return
+	}
+
+	endMonth, err := time.Parse("2006-01", *end)
+	if err != nil {
+		log.Fatal("Invalid -end, expected YYYY-MM: ", err)
+	}
+
+	// Initialize database connection
+	if err := config.InitDatabase(); err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+	defer config.CloseDatabase()
+
+	db := config.GetDB()
+	generator := seed.New(repository.NewFinanceRepository(db), repository.NewNotesRepository(db), seed.Config{
+		Seed:               *seedValue,
+		Users:              *users,
+		Years:              *years,
+		End:                endMonth,
+		NotesPerUser:       *notes,
+		IncludeDefaultUser: *defaultUser,
+		Workers:            *workers,
+	})
+
+	log.Printf("🌱 Seeding %d user(s) with %d year(s) of history up to %s (seed %d)", *users, *years, *end, *seedValue)
+	started := time.Now()
+	stats, err := generator.Run(func(s seed.Stats) {
+		log.Printf("👤 %d/%d users seeded", s.Users, *users)
+	})
+	if err != nil {
+		log.Fatal("Seeding failed: ", err)
+	}
+
+	log.Printf("✅ Seeded %d incomes, %d expenses, %d categories, %d goals, %d contributions and %d notes in %s",
+		stats.Incomes, stats.Expenses, stats.Categories, stats.Goals, stats.Contributions, stats.Notes, time.Since(started).Round(time.Millisecond))
+}
+
+// runLoad sends synthetic load to the API at baseURL and logs a latency summary per scenario
+func runLoad(baseURL string, rate float64, duration time.Duration, mix string, seedValue int64) {
+	cfg := seed.LoadConfig{BaseURL: baseURL, Rate: rate, Duration: duration, Seed: seedValue, Client: &http.Client{Timeout: 30 * time.Second}}
+	if mix != "" {
+		parsed, err := seed.ParseMix(mix)
+		if err != nil {
+			log.Fatal("Invalid -mix: ", err)
+		}
+		cfg.Mix = parsed
+	}
+
+	// Ctrl-C ends the run early and still prints the summary
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("🚦 Sending %.1f requests/s to %s for %s (seed %d)", rate, baseURL, duration, seedValue)
+	report, err := seed.RunLoad(ctx, cfg)
+	if err != nil {
+		log.Fatal("Load run failed: ", err)
+	}
+
+	names := make([]string, 0, len(report.Scenarios))
+	for name := range report.Scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		logLatency(name, report.Scenarios[name])
+	}
+	logLatency("total", report.Total)
+	log.Printf("✅ Sent %d requests in %s (%.1f/s), %d failed",
+		report.Total.Requests, report.Elapsed.Round(time.Millisecond), float64(report.Total.Requests)/report.Elapsed.Seconds(), report.Total.Errors)
+}
+
+func logLatency(name string, s seed.LatencySummary) {
+	round := func(d time.Duration) time.Duration { return d.Round(10 * time.Microsecond) }
+	log.Printf("%-15s %6d requests %5d errors  min %-9s p50 %-9s p90 %-9s p99 %-9s max %-9s mean %s",
+		name, s.Requests, s.Errors, round(s.Min), round(s.P50), round(s.P90), round(s.P99), round(s.Max), round(s.Mean))
+}
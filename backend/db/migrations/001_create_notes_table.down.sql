@@ -0,0 +1,7 @@
+-- Migration: Drop notes table
+-- Description: Reverts 001_create_notes_table
+
+DROP INDEX IF EXISTS idx_notes_tags;
+DROP INDEX IF EXISTS idx_notes_created_at;
+DROP INDEX IF EXISTS idx_notes_user_id;
+DROP TABLE IF EXISTS notes;
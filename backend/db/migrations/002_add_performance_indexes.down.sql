@@ -0,0 +1,8 @@
+-- Migration: Revert performance indexes
+-- Description: 002 only documents candidate indexes, so there is nothing to drop
+
+-- DROP INDEX IF EXISTS idx_notes_category;
+-- DROP INDEX IF EXISTS idx_notes_is_archived;
+-- DROP INDEX IF EXISTS idx_notes_is_favorite;
+-- DROP INDEX IF EXISTS idx_notes_user_archived;
+-- DROP INDEX IF EXISTS idx_notes_user_favorite;
@@ -0,0 +1,11 @@
+-- Migration: Drop finance tables
+-- Description: Reverts 003_create_finance_tables
+
+DROP TRIGGER IF EXISTS update_goals_updated_at ON goals;
+
+DROP TABLE IF EXISTS goal_contributions;
+DROP TABLE IF EXISTS expenses;
+DROP TABLE IF EXISTS incomes;
+DROP TABLE IF EXISTS goals;
+
+-- update_updated_at_column() is kept: scripts/init.sql also uses it for the notes trigger
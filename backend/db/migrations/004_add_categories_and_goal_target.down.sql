@@ -0,0 +1,7 @@
+-- Migration: Drop categories table and goal target_date
+-- Description: Reverts 004_add_categories_and_goal_target
+
+ALTER TABLE goals DROP COLUMN IF EXISTS target_date;
+
+DROP INDEX IF EXISTS idx_categories_user_name;
+DROP TABLE IF EXISTS categories;
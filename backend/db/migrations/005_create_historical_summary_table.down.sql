@@ -0,0 +1,6 @@
+-- Migration: Drop historical summary table
+-- Description: Reverts 005_create_historical_summary_table
+
+DROP TRIGGER IF EXISTS update_historical_summaries_updated_at ON historical_summaries;
+DROP FUNCTION IF EXISTS update_historical_summaries_updated_at();
+DROP TABLE IF EXISTS historical_summaries;
@@ -0,0 +1,15 @@
+-- Migration: Remove goal categories and hierarchical goals support
+-- Description: Reverts 006_add_goal_categories_and_hierarchy
+
+DROP TABLE IF EXISTS goal_expenses;
+DROP TABLE IF EXISTS goal_categories;
+
+DROP INDEX IF EXISTS idx_goals_is_main_goal;
+DROP INDEX IF EXISTS idx_goals_category;
+DROP INDEX IF EXISTS idx_goals_parent_goal_id;
+
+ALTER TABLE goals DROP CONSTRAINT IF EXISTS fk_goals_parent_goal;
+ALTER TABLE goals DROP COLUMN IF EXISTS description;
+ALTER TABLE goals DROP COLUMN IF EXISTS is_main_goal;
+ALTER TABLE goals DROP COLUMN IF EXISTS parent_goal_id;
+ALTER TABLE goals DROP COLUMN IF EXISTS category;
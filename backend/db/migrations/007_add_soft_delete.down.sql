@@ -0,0 +1,21 @@
+-- Migration: Remove soft delete from user-owned tables
+-- Description: Reverts 007_add_soft_delete. Trashed rows are deleted first so they
+-- do not reappear as live data.
+
+DELETE FROM goal_contributions WHERE deleted_at IS NOT NULL;
+DELETE FROM expenses WHERE deleted_at IS NOT NULL;
+DELETE FROM incomes WHERE deleted_at IS NOT NULL;
+DELETE FROM goals WHERE deleted_at IS NOT NULL;
+DELETE FROM notes WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_notes_deleted_at;
+DROP INDEX IF EXISTS idx_goal_contributions_deleted_at;
+DROP INDEX IF EXISTS idx_goals_deleted_at;
+DROP INDEX IF EXISTS idx_expenses_deleted_at;
+DROP INDEX IF EXISTS idx_incomes_deleted_at;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE goal_contributions DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE goals DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE expenses DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE incomes DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,6 @@
+-- Migration: Drop audit log
+-- Description: Reverts 008_create_audit_entries
+
+DROP TRIGGER IF EXISTS audit_entries_append_only ON audit_entries;
+DROP FUNCTION IF EXISTS reject_audit_entry_change();
+DROP TABLE IF EXISTS audit_entries;
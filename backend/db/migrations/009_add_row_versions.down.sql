@@ -0,0 +1,13 @@
+-- Migration: Remove row versions
+-- Description: Reverts 009_add_row_versions
+
+DROP TRIGGER IF EXISTS update_expenses_updated_at ON expenses;
+DROP TRIGGER IF EXISTS update_incomes_updated_at ON incomes;
+
+ALTER TABLE expenses DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE incomes DROP COLUMN IF EXISTS updated_at;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS version;
+ALTER TABLE goals DROP COLUMN IF EXISTS version;
+ALTER TABLE expenses DROP COLUMN IF EXISTS version;
+ALTER TABLE incomes DROP COLUMN IF EXISTS version;
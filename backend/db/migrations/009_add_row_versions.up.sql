@@ -0,0 +1,21 @@
+-- Migration: Add row versions for optimistic concurrency
+-- Description: version is bumped on every API update and exposed as the ETag;
+-- incomes and expenses also get the updated_at column goals and notes already have
+
+ALTER TABLE incomes ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE goals ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE incomes ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP;
+UPDATE incomes SET updated_at = created_at;
+UPDATE expenses SET updated_at = created_at;
+
+DROP TRIGGER IF EXISTS update_incomes_updated_at ON incomes;
+CREATE TRIGGER update_incomes_updated_at BEFORE UPDATE ON incomes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_expenses_updated_at ON expenses;
+CREATE TRIGGER update_expenses_updated_at BEFORE UPDATE ON expenses
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
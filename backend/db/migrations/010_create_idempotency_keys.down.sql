@@ -0,0 +1,4 @@
+-- Migration: Drop idempotency keys
+-- Description: Reverts 010_create_idempotency_keys
+
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,4 @@
+-- Migration: Drop expense rules
+-- Description: Reverts 011_create_expense_rules
+
+DROP TABLE IF EXISTS expense_rules;
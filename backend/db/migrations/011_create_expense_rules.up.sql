@@ -0,0 +1,26 @@
+-- Migration: Create expense rules
+-- Description: User-defined rules that set an expense's category and goal when
+-- all of their conditions match; evaluated in priority order
+
+CREATE TABLE IF NOT EXISTS expense_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- JSON array of {"field", "operator", "value"} objects that must all match
+    conditions JSONB NOT NULL DEFAULT '[]',
+    set_category VARCHAR(100),
+    set_goal_id UUID REFERENCES goals(id) ON DELETE SET NULL,
+    clear_goal BOOLEAN NOT NULL DEFAULT FALSE,
+    match_count INTEGER NOT NULL DEFAULT 0,
+    last_matched_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_rules_user_priority ON expense_rules(user_id, priority, created_at);
+
+DROP TRIGGER IF EXISTS update_expense_rules_updated_at ON expense_rules;
+CREATE TRIGGER update_expense_rules_updated_at BEFORE UPDATE ON expense_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+-- Migration: Drop merchants
+-- Description: Reverts 012_create_merchants
+
+DROP INDEX IF EXISTS idx_expenses_merchant;
+ALTER TABLE expenses DROP COLUMN IF EXISTS merchant_id;
+DROP TABLE IF EXISTS merchant_patterns;
+DROP TABLE IF EXISTS merchants;
@@ -0,0 +1,34 @@
+-- Migration: Create merchants
+-- Description: Normalised payees that expenses with differing raw descriptions
+-- (e.g. "AMZN Mktp US*2K3" and "Amazon.com") are linked to, plus the patterns
+-- that map normalised descriptions to a merchant
+
+CREATE TABLE IF NOT EXISTS merchants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_merchants_user_name ON merchants(user_id, LOWER(name));
+
+DROP TRIGGER IF EXISTS update_merchants_updated_at ON merchants;
+CREATE TRIGGER update_merchants_updated_at BEFORE UPDATE ON merchants
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS merchant_patterns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    merchant_id UUID NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+    -- pattern is stored normalised, like the descriptions it is matched against
+    pattern VARCHAR(200) NOT NULL,
+    match_type VARCHAR(10) NOT NULL DEFAULT 'contains' CHECK (match_type IN ('exact', 'prefix', 'contains')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_merchant_patterns_user_pattern ON merchant_patterns(user_id, pattern, match_type);
+CREATE INDEX IF NOT EXISTS idx_merchant_patterns_merchant ON merchant_patterns(merchant_id);
+
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS merchant_id UUID REFERENCES merchants(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_expenses_merchant ON expenses(merchant_id) WHERE merchant_id IS NOT NULL;
@@ -0,0 +1,14 @@
+-- Migration: Drop category hierarchy
+-- Description: Reverts 013_hierarchical_categories. Categories created by the
+-- backfill and removed duplicates are not restored.
+
+DROP INDEX IF EXISTS idx_expenses_category;
+ALTER TABLE expenses DROP COLUMN IF EXISTS category_id;
+
+DROP INDEX IF EXISTS idx_categories_parent;
+DROP INDEX IF EXISTS idx_categories_user_parent_name;
+CREATE INDEX IF NOT EXISTS idx_categories_user_name ON categories(user_id, name);
+
+DROP TRIGGER IF EXISTS update_categories_updated_at ON categories;
+ALTER TABLE categories DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,42 @@
+-- Migration: Hierarchical expense categories referenced by ID
+-- Description: Categories gain an optional parent and a unique name per parent,
+-- and expenses reference their category by ID. The expense category name is
+-- kept as a denormalised copy that renames and merges keep in sync.
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+DROP TRIGGER IF EXISTS update_categories_updated_at ON categories;
+CREATE TRIGGER update_categories_updated_at BEFORE UPDATE ON categories
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Migration 004 allowed duplicate names; keep the oldest of each
+DELETE FROM categories c
+USING categories keep
+WHERE c.user_id = keep.user_id
+  AND LOWER(c.name) = LOWER(keep.name)
+  AND (keep.created_at, keep.id) < (c.created_at, c.id);
+
+-- Every name already used by an expense becomes a top-level category
+INSERT INTO categories (user_id, name)
+SELECT DISTINCT ON (e.user_id, LOWER(e.category)) e.user_id, e.category
+FROM expenses e
+WHERE e.category <> ''
+  AND NOT EXISTS (
+      SELECT 1 FROM categories c WHERE c.user_id = e.user_id AND LOWER(c.name) = LOWER(e.category)
+  )
+ORDER BY e.user_id, LOWER(e.category), e.created_at;
+
+DROP INDEX IF EXISTS idx_categories_user_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_user_parent_name
+    ON categories(user_id, COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories(parent_id) WHERE parent_id IS NOT NULL;
+
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES categories(id) ON DELETE SET NULL;
+
+UPDATE expenses e
+SET category_id = c.id
+FROM categories c
+WHERE c.user_id = e.user_id AND LOWER(c.name) = LOWER(e.category) AND e.category_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_category ON expenses(category_id) WHERE category_id IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Migration: Drop transaction tags
+-- Description: Reverts 014_add_transaction_tags
+
+DROP INDEX IF EXISTS idx_expenses_tags;
+DROP INDEX IF EXISTS idx_incomes_tags;
+ALTER TABLE expenses DROP COLUMN IF EXISTS tags;
+ALTER TABLE incomes DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,9 @@
+-- Migration: Add tags to incomes and expenses
+-- Description: Free-form lower-case labels such as "reimbursable" or
+-- "vacation-2026" that cut across categories, indexed like notes.tags
+
+ALTER TABLE incomes ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_incomes_tags ON incomes USING GIN(tags);
+CREATE INDEX IF NOT EXISTS idx_expenses_tags ON expenses USING GIN(tags);
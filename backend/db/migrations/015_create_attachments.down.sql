@@ -0,0 +1,4 @@
+-- Migration: Drop attachments
+-- Description: Reverts 015_create_attachments. Stored files are not removed.
+
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,22 @@
+-- Migration: Create attachments
+-- Description: Receipts and documents attached to expenses, incomes and notes.
+-- The files themselves live in the configured storage backend under
+-- storage_key; rows whose owner has been purged from the trash are removed
+-- together with their files by the trash purger.
+
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    owner_type VARCHAR(20) NOT NULL CHECK (owner_type IN ('expense', 'income', 'note')),
+    owner_id UUID NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    -- content_type is sniffed from the file, not taken from the upload
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    storage_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_owner ON attachments(owner_type, owner_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_user ON attachments(user_id);
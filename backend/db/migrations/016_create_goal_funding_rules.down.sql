@@ -0,0 +1,7 @@
+-- Migration: Drop goal funding rules
+-- Description: Reverts 016_create_goal_funding_rules. Generated contributions are kept.
+
+DROP INDEX IF EXISTS idx_goal_contributions_funding_rule;
+ALTER TABLE goal_contributions DROP COLUMN IF EXISTS source_id;
+ALTER TABLE goal_contributions DROP COLUMN IF EXISTS funding_rule_id;
+DROP TABLE IF EXISTS goal_funding_rules;
@@ -0,0 +1,37 @@
+-- Migration: Create goal funding rules
+-- Description: Per-goal rules that generate goal contributions automatically:
+-- a percentage of matching incomes, the round-up of each expense, or a fixed
+-- amount on a day of every month. Generated contributions record the rule and
+-- the income or expense that triggered them.
+
+CREATE TABLE IF NOT EXISTS goal_funding_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    goal_id UUID NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('percent_of_income', 'round_up', 'fixed_monthly')),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- percent_of_income: share of each income, optionally only from one source
+    percent NUMERIC(5,2) CHECK (percent > 0 AND percent <= 100),
+    income_source VARCHAR(100),
+    -- round_up: expenses are rounded up to the next multiple of round_to
+    round_to NUMERIC(14,2) CHECK (round_to > 0),
+    -- fixed_monthly: amount on day_of_month, or the month's last day when shorter
+    amount NUMERIC(14,2) CHECK (amount > 0),
+    day_of_month SMALLINT CHECK (day_of_month BETWEEN 1 AND 31),
+    -- last_funded_on is the last scheduled date a fixed_monthly rule contributed for
+    last_funded_on DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_goal_funding_rules_user ON goal_funding_rules(user_id);
+CREATE INDEX IF NOT EXISTS idx_goal_funding_rules_scheduled ON goal_funding_rules(type) WHERE enabled AND type = 'fixed_monthly';
+
+DROP TRIGGER IF EXISTS update_goal_funding_rules_updated_at ON goal_funding_rules;
+CREATE TRIGGER update_goal_funding_rules_updated_at BEFORE UPDATE ON goal_funding_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE goal_contributions ADD COLUMN IF NOT EXISTS funding_rule_id UUID NULL REFERENCES goal_funding_rules(id) ON DELETE SET NULL;
+ALTER TABLE goal_contributions ADD COLUMN IF NOT EXISTS source_id UUID NULL;
+CREATE INDEX IF NOT EXISTS idx_goal_contributions_funding_rule ON goal_contributions(funding_rule_id) WHERE funding_rule_id IS NOT NULL;
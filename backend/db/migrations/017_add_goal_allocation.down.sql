@@ -0,0 +1,5 @@
+-- Migration: Drop goal allocation priorities
+-- Description: Reverts 017_add_goal_allocation. Contributions already allocated are kept.
+
+ALTER TABLE goals DROP COLUMN IF EXISTS allocation_weight;
+ALTER TABLE goals DROP COLUMN IF EXISTS allocation_priority;
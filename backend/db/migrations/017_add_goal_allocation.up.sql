@@ -0,0 +1,8 @@
+-- Migration: Add goal allocation priorities
+-- Description: Month-end surplus is allocated across goals in order of
+-- allocation_priority (1 first), filling each goal up to its monthly need.
+-- Goals of the same priority share what is left in proportion to their
+-- allocation_weight.
+
+ALTER TABLE goals ADD COLUMN IF NOT EXISTS allocation_priority SMALLINT NOT NULL DEFAULT 1 CHECK (allocation_priority BETWEEN 1 AND 100);
+ALTER TABLE goals ADD COLUMN IF NOT EXISTS allocation_weight NUMERIC(8,2) NOT NULL DEFAULT 1 CHECK (allocation_weight > 0);
@@ -0,0 +1,12 @@
+-- Migration: Drop goal lifecycle and withdrawals
+-- Description: Reverts 018_add_goal_lifecycle. Withdrawals are deleted as the
+-- original check rejects negative contributions.
+
+ALTER TABLE goal_contributions DROP CONSTRAINT IF EXISTS goal_contributions_withdrawal_check;
+DELETE FROM goal_contributions WHERE amount < 0;
+ALTER TABLE goal_contributions ADD CONSTRAINT goal_contributions_amount_check CHECK (amount >= 0);
+ALTER TABLE goal_contributions DROP COLUMN IF EXISTS reason;
+
+DROP TABLE IF EXISTS goal_status_changes;
+ALTER TABLE goals DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE goals DROP COLUMN IF EXISTS status;
@@ -0,0 +1,26 @@
+-- Migration: Add goal lifecycle and withdrawals
+-- Description: Goals move between active, paused, completed, archived and
+-- abandoned, and every change is kept in goal_status_changes. Withdrawals are
+-- goal contributions with a negative amount and a reason, so goal progress and
+-- monthly savings stay plain sums.
+
+ALTER TABLE goals ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'paused', 'completed', 'archived', 'abandoned'));
+ALTER TABLE goals ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ NULL;
+
+CREATE TABLE IF NOT EXISTS goal_status_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    goal_id UUID NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_goal_status_changes_goal ON goal_status_changes(goal_id, changed_at);
+
+ALTER TABLE goal_contributions ADD COLUMN IF NOT EXISTS reason TEXT NULL;
+ALTER TABLE goal_contributions DROP CONSTRAINT IF EXISTS goal_contributions_amount_check;
+ALTER TABLE goal_contributions ADD CONSTRAINT goal_contributions_withdrawal_check
+    CHECK (amount >= 0 OR (reason IS NOT NULL AND reason <> ''));
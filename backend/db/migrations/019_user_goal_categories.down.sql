@@ -0,0 +1,16 @@
+-- Migration: Drop user-defined goal categories
+-- Description: Reverts 019_user_goal_categories. Users' own categories are
+-- deleted; goals keep their category names.
+
+DROP INDEX IF EXISTS idx_goals_category_id;
+ALTER TABLE goals DROP COLUMN IF EXISTS category_id;
+
+DELETE FROM goal_categories WHERE user_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_goal_categories_user_id;
+DROP INDEX IF EXISTS idx_goal_categories_owner_name;
+ALTER TABLE goal_categories ADD CONSTRAINT goal_categories_name_key UNIQUE (name);
+
+DROP TRIGGER IF EXISTS update_goal_categories_updated_at ON goal_categories;
+ALTER TABLE goal_categories DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE goal_categories DROP COLUMN IF EXISTS hidden;
+ALTER TABLE goal_categories DROP COLUMN IF EXISTS user_id;
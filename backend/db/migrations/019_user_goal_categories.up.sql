@@ -0,0 +1,28 @@
+-- Migration: User-defined goal categories referenced by ID
+-- Description: Goal categories without a user are the built-ins seeded by 006;
+-- users add their own, unique by name among the built-ins and their other
+-- categories, and hide them instead of deleting. Goals reference their category
+-- by ID and keep its name as a denormalised copy that renames keep in sync.
+
+ALTER TABLE goal_categories ADD COLUMN IF NOT EXISTS user_id UUID NULL;
+ALTER TABLE goal_categories ADD COLUMN IF NOT EXISTS hidden BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE goal_categories ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+DROP TRIGGER IF EXISTS update_goal_categories_updated_at ON goal_categories;
+CREATE TRIGGER update_goal_categories_updated_at BEFORE UPDATE ON goal_categories
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE goal_categories DROP CONSTRAINT IF EXISTS goal_categories_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_goal_categories_owner_name
+    ON goal_categories(COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid), LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_goal_categories_user_id ON goal_categories(user_id) WHERE user_id IS NOT NULL;
+
+ALTER TABLE goals ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES goal_categories(id) ON DELETE SET NULL;
+
+-- Goals named after a built-in category are linked to it
+UPDATE goals g
+SET category_id = c.id, category = c.name
+FROM goal_categories c
+WHERE c.user_id IS NULL AND LOWER(c.name) = LOWER(g.category) AND g.category_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_goals_category_id ON goals(category_id) WHERE category_id IS NOT NULL;
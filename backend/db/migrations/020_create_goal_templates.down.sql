@@ -0,0 +1,4 @@
+-- Migration: Drop goal templates
+-- Description: Reverts 020_create_goal_templates
+
+DROP TABLE IF EXISTS goal_templates;
@@ -0,0 +1,41 @@
+-- Migration: Create goal templates
+-- Description: Templates describe a main goal and its sub-goals with target
+-- formulas over the user's average monthly expenses and income. Templates
+-- without a user are built-in; users save their own, often from goal trees.
+
+CREATE TABLE IF NOT EXISTS goal_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NULL,
+    name VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    -- JSON object for the main goal: {"name", "description", "category", "target",
+    -- "months", "subgoals": [...]} where subgoals nest the same way
+    definition JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_goal_templates_user_id ON goal_templates(user_id);
+
+DROP TRIGGER IF EXISTS update_goal_templates_updated_at ON goal_templates;
+CREATE TRIGGER update_goal_templates_updated_at BEFORE UPDATE ON goal_templates
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO goal_templates (name, description, definition) VALUES
+('Emergency fund', 'Six months of average expenses set aside within a year',
+ '{"name": "Emergency fund", "description": "Six months of essential expenses", "category": "Emergency", "target": "6 * avg_monthly_expenses", "months": 12}'),
+('Wedding', 'A wedding budget split into the usual costs',
+ '{"name": "Wedding", "description": "Ceremony and reception", "category": "Lifestyle", "target": "4 * avg_monthly_income", "months": 18, "subgoals": [
+   {"name": "Venue", "target": "parent * 0.4", "months": 12},
+   {"name": "Catering", "target": "parent * 0.3", "months": 18},
+   {"name": "Attire", "target": "parent * 0.1", "months": 15},
+   {"name": "Photography", "target": "parent * 0.1", "months": 15},
+   {"name": "Rings", "target": "parent * 0.1", "months": 9}
+ ]}'),
+('Home down payment', 'A deposit and closing costs saved over five years',
+ '{"name": "Home down payment", "description": "Deposit and closing costs", "category": "Investment", "target": "20 * avg_monthly_income", "months": 60, "subgoals": [
+   {"name": "Deposit", "target": "parent * 0.85", "months": 60},
+   {"name": "Closing costs", "target": "parent * 0.15", "months": 60}
+ ]}'),
+('Holiday', 'A month of income put towards next year''s trip',
+ '{"name": "Holiday", "category": "Travel", "target": "avg_monthly_income", "months": 12}');
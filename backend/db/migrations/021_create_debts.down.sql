@@ -0,0 +1,6 @@
+-- Migration: Drop debts
+-- Description: Reverts 021_create_debts. Debt payments are kept as plain expenses.
+
+DROP INDEX IF EXISTS idx_expenses_debt;
+ALTER TABLE expenses DROP COLUMN IF EXISTS debt_id;
+DROP TABLE IF EXISTS debts;
@@ -0,0 +1,30 @@
+-- Migration: Create debts
+-- Description: Loans and credit cards with their APR, minimum payment and due
+-- day. principal is what was owed on started_on; payments are expenses linked
+-- through expenses.debt_id, and interest is worked out from the APR, so the
+-- outstanding balance is derived rather than stored.
+
+CREATE TABLE IF NOT EXISTS debts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('loan', 'credit_card')),
+    principal NUMERIC(14,2) NOT NULL CHECK (principal > 0),
+    -- apr is the nominal annual rate in percent, charged monthly
+    apr NUMERIC(6,3) NOT NULL CHECK (apr >= 0 AND apr <= 100),
+    minimum_payment NUMERIC(14,2) NOT NULL CHECK (minimum_payment > 0),
+    -- due_day falls on the month's last day when the month is shorter
+    due_day SMALLINT NOT NULL CHECK (due_day BETWEEN 1 AND 31),
+    started_on DATE NOT NULL DEFAULT CURRENT_DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_debts_user ON debts(user_id);
+
+DROP TRIGGER IF EXISTS update_debts_updated_at ON debts;
+CREATE TRIGGER update_debts_updated_at BEFORE UPDATE ON debts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS debt_id UUID REFERENCES debts(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_expenses_debt ON expenses(debt_id) WHERE debt_id IS NOT NULL;
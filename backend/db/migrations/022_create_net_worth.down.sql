@@ -0,0 +1,6 @@
+-- Migration: Drop net worth tracking
+-- Description: Reverts 022_create_net_worth
+
+DROP TABLE IF EXISTS net_worth_snapshots;
+DROP TABLE IF EXISTS net_worth_valuations;
+DROP TABLE IF EXISTS net_worth_items;
@@ -0,0 +1,50 @@
+-- Migration: Create net worth tracking
+-- Description: Manually tracked assets (property, vehicles, investments) and
+-- liabilities, each with a history of valuations, and monthly net worth
+-- snapshots. Net worth combines the latest valuations with the ledger balance,
+-- goal savings and debt balances; snapshots keep the figure and its breakdown
+-- by type as it stood at each month's end.
+
+CREATE TABLE IF NOT EXISTS net_worth_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    class VARCHAR(20) NOT NULL CHECK (class IN ('asset', 'liability')),
+    type VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_net_worth_items_user ON net_worth_items(user_id);
+
+DROP TRIGGER IF EXISTS update_net_worth_items_updated_at ON net_worth_items;
+CREATE TRIGGER update_net_worth_items_updated_at BEFORE UPDATE ON net_worth_items
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- An item has at most one valuation a day; valuing it again replaces the value
+CREATE TABLE IF NOT EXISTS net_worth_valuations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    item_id UUID NOT NULL REFERENCES net_worth_items(id) ON DELETE CASCADE,
+    value NUMERIC(14,2) NOT NULL CHECK (value >= 0),
+    valued_on DATE NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (item_id, valued_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_net_worth_valuations_user ON net_worth_valuations(user_id, valued_on);
+
+CREATE TABLE IF NOT EXISTS net_worth_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    taken_on DATE NOT NULL,
+    assets NUMERIC(14,2) NOT NULL,
+    liabilities NUMERIC(14,2) NOT NULL,
+    net_worth NUMERIC(14,2) NOT NULL,
+    -- breakdown lists the amount of every asset and liability type
+    breakdown JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, taken_on)
+);
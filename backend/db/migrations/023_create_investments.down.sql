@@ -0,0 +1,9 @@
+-- Migration: Drop investment holdings
+-- Description: Reverts 023_create_investments. Dividends are kept as plain incomes.
+
+DROP INDEX IF EXISTS idx_incomes_security;
+ALTER TABLE incomes DROP COLUMN IF EXISTS security_id;
+DROP TABLE IF EXISTS security_prices;
+DROP TABLE IF EXISTS investment_sales;
+DROP TABLE IF EXISTS investment_lots;
+DROP TABLE IF EXISTS securities;
@@ -0,0 +1,59 @@
+-- Migration: Create investment holdings
+-- Description: Securities held in brokerage accounts, the lots bought and the
+-- sales made of them, and their daily closing prices imported from CSV files.
+-- Holdings, cost bases and gains are worked out from lots and sales rather than
+-- stored. Dividends are incomes linked through incomes.security_id.
+
+CREATE TABLE IF NOT EXISTS securities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    asset_class VARCHAR(20) NOT NULL CHECK (asset_class IN ('stock', 'bond', 'fund', 'property', 'commodity', 'crypto', 'cash', 'other')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, symbol)
+);
+
+DROP TRIGGER IF EXISTS update_securities_updated_at ON securities;
+CREATE TRIGGER update_securities_updated_at BEFORE UPDATE ON securities
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- cost is the total paid for the lot, fees included
+CREATE TABLE IF NOT EXISTS investment_lots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    security_id UUID NOT NULL REFERENCES securities(id) ON DELETE CASCADE,
+    bought_on DATE NOT NULL,
+    quantity NUMERIC(20,8) NOT NULL CHECK (quantity > 0),
+    cost NUMERIC(14,2) NOT NULL CHECK (cost >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_investment_lots_user ON investment_lots(user_id, bought_on);
+
+-- proceeds is the total received for the sale, net of fees
+CREATE TABLE IF NOT EXISTS investment_sales (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    security_id UUID NOT NULL REFERENCES securities(id) ON DELETE CASCADE,
+    sold_on DATE NOT NULL,
+    quantity NUMERIC(20,8) NOT NULL CHECK (quantity > 0),
+    proceeds NUMERIC(14,2) NOT NULL CHECK (proceeds >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_investment_sales_user ON investment_sales(user_id, sold_on);
+
+CREATE TABLE IF NOT EXISTS security_prices (
+    security_id UUID NOT NULL REFERENCES securities(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    priced_on DATE NOT NULL,
+    close NUMERIC(20,6) NOT NULL CHECK (close >= 0),
+    PRIMARY KEY (security_id, priced_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_security_prices_user ON security_prices(user_id, priced_on);
+
+ALTER TABLE incomes ADD COLUMN IF NOT EXISTS security_id UUID REFERENCES securities(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_incomes_security ON incomes(security_id) WHERE security_id IS NOT NULL;
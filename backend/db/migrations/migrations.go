@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migration files so the API and migrate
+// binaries carry the schema they were built against.
+package migrations
+
+import "embed"
+
+// FS holds every {version}_{name}.up.sql and .down.sql file in this directory
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,56 @@
+package classifier
+
+import (
+	"strings"
+	"unicode"
+)
+
+// amountBuckets are the upper bounds of the amount ranges used as features
+var amountBuckets = []struct {
+	max   float64
+	label string
+}{
+	{10, "amount:<10"},
+	{25, "amount:10-25"},
+	{50, "amount:25-50"},
+	{100, "amount:50-100"},
+	{250, "amount:100-250"},
+	{500, "amount:250-500"},
+	{1000, "amount:500-1000"},
+}
+
+// Features extracts the tokens of an expense description plus its amount bucket.
+// A zero amount adds no bucket, so descriptions can be classified on their own.
+func Features(description string, amount float64) []string {
+	features := Tokenize(description)
+	if amount > 0 {
+		features = append(features, AmountBucket(amount))
+	}
+	return features
+}
+
+// Tokenize lowercases text and splits it into words of at least two letters or
+// digits. Pure numbers are dropped since they are mostly dates and references.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := words[:0]
+	for _, w := range words {
+		if len([]rune(w)) < 2 || strings.IndexFunc(w, unicode.IsLetter) < 0 {
+			continue
+		}
+		tokens = append(tokens, w)
+	}
+	return tokens
+}
+
+// AmountBucket returns the feature naming the range amount falls in
+func AmountBucket(amount float64) string {
+	for _, b := range amountBuckets {
+		if amount < b.max {
+			return b.label
+		}
+	}
+	return "amount:1000+"
+}
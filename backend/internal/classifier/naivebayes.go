@@ -0,0 +1,125 @@
+// Package classifier implements a small multinomial naive Bayes text classifier
+// used to suggest expense categories from a user's own history.
+package classifier
+
+import (
+	"math"
+	"sort"
+)
+
+// Prediction is a category and its posterior probability
+type Prediction struct {
+	Category    string
+	Probability float64
+}
+
+// NaiveBayes is a multinomial naive Bayes model with Laplace smoothing. It can
+// learn and forget documents one at a time, so it is updated incrementally
+// instead of being retrained. It is not safe for concurrent use.
+type NaiveBayes struct {
+	docs      map[string]int            // documents per category
+	counts    map[string]map[string]int // feature counts per category
+	totals    map[string]int            // feature total per category
+	features  map[string]int            // occurrences of each feature across categories
+	documents int
+}
+
+// New creates an empty model
+func New() *NaiveBayes {
+	return &NaiveBayes{
+		docs:     make(map[string]int),
+		counts:   make(map[string]map[string]int),
+		totals:   make(map[string]int),
+		features: make(map[string]int),
+	}
+}
+
+// Documents returns how many documents the model was trained on
+func (m *NaiveBayes) Documents() int {
+	return m.documents
+}
+
+// Learn adds a document with the given features to category
+func (m *NaiveBayes) Learn(category string, features []string) {
+	if category == "" {
+		return
+	}
+	if m.counts[category] == nil {
+		m.counts[category] = make(map[string]int)
+	}
+	m.docs[category]++
+	m.documents++
+	for _, f := range features {
+		m.counts[category][f]++
+		m.totals[category]++
+		m.features[f]++
+	}
+}
+
+// Forget removes a document previously passed to Learn. Forgetting a document
+// that was never learned is ignored.
+func (m *NaiveBayes) Forget(category string, features []string) {
+	if m.docs[category] == 0 {
+		return
+	}
+	m.docs[category]--
+	m.documents--
+	for _, f := range features {
+		if m.counts[category][f] == 0 {
+			continue
+		}
+		m.counts[category][f]--
+		m.totals[category]--
+		if m.counts[category][f] == 0 {
+			delete(m.counts[category], f)
+		}
+		if m.features[f]--; m.features[f] == 0 {
+			delete(m.features, f)
+		}
+	}
+	if m.docs[category] == 0 {
+		delete(m.docs, category)
+		delete(m.counts, category)
+		delete(m.totals, category)
+	}
+}
+
+// Predict returns every known category with its probability for a document
+// with the given features, most likely first
+func (m *NaiveBayes) Predict(features []string) []Prediction {
+	if m.documents == 0 {
+		return nil
+	}
+
+	vocabulary := float64(len(m.features) + 1)
+	scores := make(map[string]float64, len(m.docs))
+	best := math.Inf(-1)
+	for category, docs := range m.docs {
+		score := math.Log(float64(docs) / float64(m.documents))
+		denominator := float64(m.totals[category]) + vocabulary
+		for _, f := range features {
+			score += math.Log((float64(m.counts[category][f]) + 1) / denominator)
+		}
+		scores[category] = score
+		best = math.Max(best, score)
+	}
+
+	// Normalise the log scores into probabilities without underflowing
+	var sum float64
+	predictions := make([]Prediction, 0, len(scores))
+	for category, score := range scores {
+		p := math.Exp(score - best)
+		sum += p
+		predictions = append(predictions, Prediction{Category: category, Probability: p})
+	}
+	for i := range predictions {
+		predictions[i].Probability /= sum
+	}
+	sort.Slice(predictions, func(i, j int) bool {
+		if predictions[i].Probability != predictions[j].Probability {
+			return predictions[i].Probability > predictions[j].Probability
+		}
+		return predictions[i].Category < predictions[j].Category
+	})
+	return predictions
+}
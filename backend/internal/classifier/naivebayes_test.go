@@ -0,0 +1,53 @@
+package classifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("UBER *Trip 2024-05-01 to J.F.K. #42")
+	want := []string{"uber", "trip", "to"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Tokenize: expected %v, got %v", want, got)
+	}
+}
+
+func TestNaiveBayesPredictsFromHistory(t *testing.T) {
+	m := New()
+	m.Learn("Transport", Features("Uber trip home", 18))
+	m.Learn("Transport", Features("Uber to airport", 45))
+	m.Learn("Groceries", Features("Whole Foods market", 80))
+	m.Learn("Groceries", Features("Corner market", 22))
+
+	predictions := m.Predict(Features("uber downtown", 20))
+	if len(predictions) != 2 || predictions[0].Category != "Transport" {
+		t.Fatalf("expected Transport first, got %+v", predictions)
+	}
+	if p := predictions[0].Probability + predictions[1].Probability; p < 0.999 || p > 1.001 {
+		t.Fatalf("expected probabilities to sum to 1, got %f", p)
+	}
+	if predictions[0].Probability < 0.7 {
+		t.Fatalf("expected a confident prediction, got %f", predictions[0].Probability)
+	}
+}
+
+func TestNaiveBayesForget(t *testing.T) {
+	m := New()
+	features := Features("Uber trip", 18)
+	m.Learn("Transport", features)
+	m.Learn("Groceries", Features("market", 30))
+	m.Forget("Transport", features)
+	m.Forget("Transport", features) // already forgotten
+
+	if m.Documents() != 1 {
+		t.Fatalf("expected 1 document, got %d", m.Documents())
+	}
+	predictions := m.Predict(features)
+	if len(predictions) != 1 || predictions[0].Category != "Groceries" {
+		t.Fatalf("expected only Groceries to remain, got %+v", predictions)
+	}
+	if New().Predict(features) != nil {
+		t.Fatalf("expected an empty model to predict nothing")
+	}
+}
@@ -0,0 +1,18 @@
+package config
+
+import (
+	"log"
+	"strconv"
+	"time"
+)
+
+// GetIdempotencyKeyRetention returns how long Idempotency-Key responses are
+// kept for replay, from IDEMPOTENCY_KEY_RETENTION_HOURS (default 24)
+func GetIdempotencyKeyRetention() time.Duration {
+	hours, err := strconv.Atoi(getEnv("IDEMPOTENCY_KEY_RETENTION_HOURS", "24"))
+	if err != nil || hours < 1 {
+		log.Printf("⚠️  Invalid IDEMPOTENCY_KEY_RETENTION_HOURS, using 24 hours")
+		hours = 24
+	}
+	return time.Duration(hours) * time.Hour
+}
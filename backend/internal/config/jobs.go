@@ -0,0 +1,10 @@
+package config
+
+import "strings"
+
+// BackgroundJobsEnabled reports whether the API server runs the background jobs:
+// trash and idempotency key purging, scheduled goal funding and net worth snapshots.
+// Set BACKGROUND_JOBS=false on all but one instance, or to run the API without them.
+func BackgroundJobsEnabled() bool {
+	return !strings.EqualFold(getEnv("BACKGROUND_JOBS", "true"), "false")
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"finance-management/internal/storage"
+)
+
+// NewAttachmentStorage creates the store attachments are kept in. STORAGE_BACKEND
+// selects "local" (the default), which writes below STORAGE_LOCAL_DIR, or "s3",
+// configured by S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY
+// and S3_PATH_STYLE.
+func NewAttachmentStorage() (storage.Storage, error) {
+	switch backend := getEnv("STORAGE_BACKEND", "local"); backend {
+	case "local":
+		return storage.NewLocalStorage(getEnv("STORAGE_LOCAL_DIR", "data/attachments"))
+	case "s3":
+		pathStyle, err := strconv.ParseBool(getEnv("S3_PATH_STYLE", "true"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid S3_PATH_STYLE: %w", err)
+		}
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:  getEnv("S3_ENDPOINT", ""),
+			Region:    getEnv("S3_REGION", "us-east-1"),
+			Bucket:    getEnv("S3_BUCKET", ""),
+			AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			SecretKey: getEnv("S3_SECRET_KEY", ""),
+			PathStyle: pathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+	}
+}
+
+// GetAttachmentMaxBytes returns the largest file that may be uploaded as an
+// attachment, from ATTACHMENT_MAX_MB (default 10)
+func GetAttachmentMaxBytes() int64 {
+	mb, err := strconv.Atoi(getEnv("ATTACHMENT_MAX_MB", "10"))
+	if err != nil || mb < 1 {
+		log.Printf("⚠️  Invalid ATTACHMENT_MAX_MB, using 10 MB")
+		mb = 10
+	}
+	return int64(mb) << 20
+}
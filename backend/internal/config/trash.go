@@ -0,0 +1,18 @@
+package config
+
+import (
+	"log"
+	"strconv"
+	"time"
+)
+
+// GetTrashRetention returns how long deleted records stay in the trash before
+// they are purged, from TRASH_RETENTION_DAYS (default 30)
+func GetTrashRetention() time.Duration {
+	days, err := strconv.Atoi(getEnv("TRASH_RETENTION_DAYS", "30"))
+	if err != nil || days < 1 {
+		log.Printf("⚠️  Invalid TRASH_RETENTION_DAYS, using 30 days")
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -0,0 +1,9 @@
+package request
+
+// AllocateSurplusRequest for distributing a month's surplus across goals. Year and
+// month default to the current month; amount allocates only part of the surplus.
+type AllocateSurplusRequest struct {
+	Year   int      `json:"year" binding:"omitempty,min=1900,max=9999"`
+	Month  int      `json:"month" binding:"omitempty,min=1,max=12"`
+	Amount *float64 `json:"amount" binding:"omitempty,gt=0"`
+}
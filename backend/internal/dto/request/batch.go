@@ -0,0 +1,140 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Batch modes
+const (
+	// BatchAllOrNothing applies every operation or none of them (the default)
+	BatchAllOrNothing = "all_or_nothing"
+	// BatchBestEffort applies the operations that succeed and reports the rest
+	BatchBestEffort = "best_effort"
+)
+
+// Batch operation types
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchOperation holds the fields shared by every batch operation
+type BatchOperation struct {
+	Op string     `json:"op" binding:"required,oneof=create update delete"`
+	ID *uuid.UUID `json:"id"`
+	// Version is checked like an If-Match header on updates of incomes and expenses,
+	// which fail without it; goal contributions are not versioned
+	Version int `json:"version" binding:"min=0"`
+}
+
+// batchEnvelope is the wire format of an operation; data is decoded according to op
+type batchEnvelope struct {
+	BatchOperation
+	Data json.RawMessage `json:"data"`
+}
+
+// decode unmarshals the operation's data into v
+func (e batchEnvelope) decode(v interface{}) error {
+	if len(e.Data) == 0 || bytes.Equal(e.Data, []byte("null")) {
+		return fmt.Errorf("data is required for %s operations", e.Op)
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// IncomeBatchOperation is one create, update or delete in an income batch
+type IncomeBatchOperation struct {
+	BatchOperation
+	Create *CreateIncomeRequest `json:"-"`
+	Update *UpdateIncomeRequest `json:"-"`
+}
+
+// UnmarshalJSON decodes data as a create or update request depending on op
+func (o *IncomeBatchOperation) UnmarshalJSON(b []byte) error {
+	var env batchEnvelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return err
+	}
+	*o = IncomeBatchOperation{BatchOperation: env.BatchOperation}
+	switch env.Op {
+	case BatchCreate:
+		o.Create = &CreateIncomeRequest{}
+		return env.decode(o.Create)
+	case BatchUpdate:
+		o.Update = &UpdateIncomeRequest{}
+		return env.decode(o.Update)
+	}
+	return nil
+}
+
+// IncomeBatchRequest for applying several income changes at once
+type IncomeBatchRequest struct {
+	Mode       string                 `json:"mode" binding:"omitempty,oneof=all_or_nothing best_effort"`
+	Operations []IncomeBatchOperation `json:"operations" binding:"required,dive"`
+}
+
+// ExpenseBatchOperation is one create, update or delete in an expense batch
+type ExpenseBatchOperation struct {
+	BatchOperation
+	Create *CreateExpenseRequest `json:"-"`
+	Update *UpdateExpenseRequest `json:"-"`
+}
+
+// UnmarshalJSON decodes data as a create or update request depending on op
+func (o *ExpenseBatchOperation) UnmarshalJSON(b []byte) error {
+	var env batchEnvelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return err
+	}
+	*o = ExpenseBatchOperation{BatchOperation: env.BatchOperation}
+	switch env.Op {
+	case BatchCreate:
+		o.Create = &CreateExpenseRequest{}
+		return env.decode(o.Create)
+	case BatchUpdate:
+		o.Update = &UpdateExpenseRequest{}
+		return env.decode(o.Update)
+	}
+	return nil
+}
+
+// ExpenseBatchRequest for applying several expense changes at once
+type ExpenseBatchRequest struct {
+	Mode       string                  `json:"mode" binding:"omitempty,oneof=all_or_nothing best_effort"`
+	Operations []ExpenseBatchOperation `json:"operations" binding:"required,dive"`
+}
+
+// GoalContributionBatchOperation is one create, update or delete in a goal contribution batch
+type GoalContributionBatchOperation struct {
+	BatchOperation
+	Create *CreateGoalContributionRequest `json:"-"`
+	Update *UpdateGoalContributionRequest `json:"-"`
+}
+
+// UnmarshalJSON decodes data as a create or update request depending on op
+func (o *GoalContributionBatchOperation) UnmarshalJSON(b []byte) error {
+	var env batchEnvelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return err
+	}
+	*o = GoalContributionBatchOperation{BatchOperation: env.BatchOperation}
+	switch env.Op {
+	case BatchCreate:
+		o.Create = &CreateGoalContributionRequest{}
+		return env.decode(o.Create)
+	case BatchUpdate:
+		o.Update = &UpdateGoalContributionRequest{}
+		return env.decode(o.Update)
+	}
+	return nil
+}
+
+// GoalContributionBatchRequest for applying several goal contribution changes at once
+type GoalContributionBatchRequest struct {
+	Mode       string                           `json:"mode" binding:"omitempty,oneof=all_or_nothing best_effort"`
+	Operations []GoalContributionBatchOperation `json:"operations" binding:"required,dive"`
+}
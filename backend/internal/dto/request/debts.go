@@ -0,0 +1,41 @@
+package request
+
+import "time"
+
+// CreateDebtRequest for adding a loan or credit card
+type CreateDebtRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	Kind string `json:"kind" binding:"required,oneof=loan credit_card"`
+	// Principal is what is owed on started_on, which defaults to today
+	Principal      float64    `json:"principal" binding:"required,gt=0"`
+	APR            float64    `json:"apr" binding:"min=0,max=100"`
+	MinimumPayment float64    `json:"minimum_payment" binding:"required,gt=0"`
+	DueDay         int        `json:"due_day" binding:"required,min=1,max=31"`
+	StartedOn      *time.Time `json:"started_on"`
+}
+
+// UpdateDebtRequest for editing a debt
+type UpdateDebtRequest struct {
+	Name           *string    `json:"name" binding:"omitempty,max=100"`
+	Kind           *string    `json:"kind" binding:"omitempty,oneof=loan credit_card"`
+	Principal      *float64   `json:"principal" binding:"omitempty,gt=0"`
+	APR            *float64   `json:"apr" binding:"omitempty,min=0,max=100"`
+	MinimumPayment *float64   `json:"minimum_payment" binding:"omitempty,gt=0"`
+	DueDay         *int       `json:"due_day" binding:"omitempty,min=1,max=31"`
+	StartedOn      *time.Time `json:"started_on"`
+}
+
+// CreateDebtPaymentRequest for recording a payment towards a debt as an expense.
+// The category defaults to "Debt repayment" and the description to the debt's name.
+type CreateDebtPaymentRequest struct {
+	Amount      float64   `json:"amount" binding:"required,gt=0"`
+	PaidAt      time.Time `json:"paid_at" binding:"required"`
+	Category    string    `json:"category" binding:"max=100"`
+	Description string    `json:"description" binding:"max=500"`
+}
+
+// DebtPayoffRequest for comparing payoff strategies across the user's debts
+type DebtPayoffRequest struct {
+	// ExtraMonthly is paid each month on top of the debts' minimum payments
+	ExtraMonthly float64
+}
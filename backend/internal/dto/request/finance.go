@@ -11,6 +11,9 @@ type CreateIncomeRequest struct {
 	Source     string    `json:"source" binding:"required"`
 	Amount     float64   `json:"amount" binding:"required,min=0"`
 	ReceivedAt time.Time `json:"received_at" binding:"required"`
+	Tags       []string  `json:"tags" binding:"max=20,dive,max=50"`
+	// SecurityID records the income as a dividend paid by the security
+	SecurityID *uuid.UUID `json:"security_id"`
 }
 
 // UpdateIncomeRequest for editing income
@@ -18,46 +21,72 @@ type UpdateIncomeRequest struct {
 	Source     *string    `json:"source"`
 	Amount     *float64   `json:"amount" binding:"omitempty,min=0"`
 	ReceivedAt *time.Time `json:"received_at"`
+	// Tags replaces the income's tags; an empty list clears them
+	Tags *[]string `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+	// SecurityID links the income to the security paying it as a dividend; null unlinks it
+	SecurityID NullableID `json:"security_id"`
 }
 
 // CreateExpenseRequest for adding expense
 type CreateExpenseRequest struct {
-	Category    string     `json:"category" binding:"required"`
+	// Category names the expense's category; CategoryID takes precedence when both are sent
+	Category    string     `json:"category" binding:"required_without=CategoryID"`
+	CategoryID  *uuid.UUID `json:"category_id"`
 	Description string     `json:"description"`
 	Amount      float64    `json:"amount" binding:"required,min=0"`
 	SpentAt     time.Time  `json:"spent_at" binding:"required"`
 	GoalID      *uuid.UUID `json:"goal_id"`
+	// MerchantID links the expense to a merchant; when omitted it is resolved from the description
+	MerchantID *uuid.UUID `json:"merchant_id"`
+	DebtID     *uuid.UUID `json:"debt_id"`
+	Tags       []string   `json:"tags" binding:"max=20,dive,max=50"`
+	// SkipRules keeps the category and goal as sent instead of applying expense rules
+	SkipRules bool `json:"skip_rules"`
 }
 
 // UpdateExpenseRequest for editing expense
 type UpdateExpenseRequest struct {
-	Category    *string     `json:"category"`
-	Description *string     `json:"description"`
-	Amount      *float64    `json:"amount" binding:"omitempty,min=0"`
-	SpentAt     *time.Time  `json:"spent_at"`
-	GoalID      **uuid.UUID `json:"goal_id"`
+	Category    *string    `json:"category"`
+	CategoryID  *uuid.UUID `json:"category_id"`
+	Description *string    `json:"description"`
+	Amount      *float64   `json:"amount" binding:"omitempty,min=0"`
+	SpentAt     *time.Time `json:"spent_at"`
+	GoalID      NullableID `json:"goal_id"`
+	MerchantID  NullableID `json:"merchant_id"`
+	DebtID      NullableID `json:"debt_id"`
+	// Tags replaces the expense's tags; an empty list clears them
+	Tags *[]string `json:"tags" binding:"omitempty,max=20,dive,max=50"`
 }
 
 // CreateGoalRequest for creating a goal
 type CreateGoalRequest struct {
-	Name         string     `json:"name" binding:"required,min=1,max=200"`
-	Description  string     `json:"description" binding:"max=1000"`
+	Name        string `json:"name" binding:"required,min=1,max=200"`
+	Description string `json:"description" binding:"max=1000"`
+	// Category names a goal category; CategoryID takes precedence when both are sent
 	Category     string     `json:"category" binding:"max=100"`
+	CategoryID   *uuid.UUID `json:"category_id"`
 	TargetAmount float64    `json:"target_amount" binding:"required,min=0"`
 	TargetDate   *time.Time `json:"target_date"`
 	ParentGoalID *uuid.UUID `json:"parent_goal_id"`
 	IsMainGoal   bool       `json:"is_main_goal"`
+	// AllocationPriority and AllocationWeight default to 1
+	AllocationPriority *int     `json:"allocation_priority" binding:"omitempty,min=1,max=100"`
+	AllocationWeight   *float64 `json:"allocation_weight" binding:"omitempty,gt=0,max=1000"`
 }
 
-// UpdateGoalRequest for editing goal
+// UpdateGoalRequest for editing goal. An empty category or a null category_id
+// takes the goal out of its category.
 type UpdateGoalRequest struct {
-	Name         *string     `json:"name" binding:"omitempty,min=1,max=200"`
-	Description  *string     `json:"description" binding:"omitempty,max=1000"`
-	Category     *string     `json:"category" binding:"omitempty,max=100"`
-	TargetAmount *float64    `json:"target_amount" binding:"omitempty,min=0"`
-	TargetDate   *time.Time  `json:"target_date"`
-	ParentGoalID **uuid.UUID `json:"parent_goal_id"`
-	IsMainGoal   *bool       `json:"is_main_goal"`
+	Name               *string    `json:"name" binding:"omitempty,min=1,max=200"`
+	Description        *string    `json:"description" binding:"omitempty,max=1000"`
+	Category           *string    `json:"category" binding:"omitempty,max=100"`
+	CategoryID         NullableID `json:"category_id"`
+	TargetAmount       *float64   `json:"target_amount" binding:"omitempty,min=0"`
+	TargetDate         *time.Time `json:"target_date"`
+	ParentGoalID       NullableID `json:"parent_goal_id"`
+	IsMainGoal         *bool      `json:"is_main_goal"`
+	AllocationPriority *int       `json:"allocation_priority" binding:"omitempty,min=1,max=100"`
+	AllocationWeight   *float64   `json:"allocation_weight" binding:"omitempty,gt=0,max=1000"`
 }
 
 // CreateGoalContributionRequest for contributing to a goal
@@ -67,9 +96,37 @@ type CreateGoalContributionRequest struct {
 	ContributedAt time.Time `json:"contributed_at" binding:"required"`
 }
 
+// UpdateGoalContributionRequest for editing a goal contribution
+type UpdateGoalContributionRequest struct {
+	GoalID        *uuid.UUID `json:"goal_id"`
+	Amount        *float64   `json:"amount" binding:"omitempty,min=0"`
+	ContributedAt *time.Time `json:"contributed_at"`
+}
+
 // CreateCategoryRequest for creating a category
 type CreateCategoryRequest struct {
-	Name string `json:"name" binding:"required,min=1,max=100"`
+	Name     string     `json:"name" binding:"required,min=1,max=100"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// UpdateCategoryRequest for renaming a category or moving it under another parent.
+// A null parent_id moves the category to the top level.
+type UpdateCategoryRequest struct {
+	Name     *string    `json:"name" binding:"omitempty,min=1,max=100"`
+	ParentID NullableID `json:"parent_id"`
+}
+
+// MergeCategoriesRequest for folding categories into one
+type MergeCategoriesRequest struct {
+	SourceIDs []uuid.UUID `json:"source_ids" binding:"required,min=1"`
+}
+
+// BulkTagRequest adds and removes tags on several incomes and expenses at once
+type BulkTagRequest struct {
+	IncomeIDs  []uuid.UUID `json:"income_ids"`
+	ExpenseIDs []uuid.UUID `json:"expense_ids"`
+	Add        []string    `json:"add" binding:"max=20,dive,max=50"`
+	Remove     []string    `json:"remove" binding:"max=20,dive,max=50"`
 }
 
 // CreateGoalExpenseRequest for associating expenses with goals
@@ -0,0 +1,15 @@
+package request
+
+// ForecastRequest for projecting income, expenses and balance over the coming months
+type ForecastRequest struct {
+	// Months to project after the current one; defaults to 6
+	Months int
+	// History is the number of complete months the projection is derived from; defaults to 12
+	History int
+	// Exclude lists YYYY-MM months to leave out of the averages, such as a month with a one-off purchase
+	Exclude []string
+	// ExcludeOutliers also leaves out months whose variable spending is far from the median
+	ExcludeOutliers bool
+	// StartingBalance replaces the balance derived from the ledger
+	StartingBalance *float64
+}
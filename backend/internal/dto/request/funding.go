@@ -0,0 +1,30 @@
+package request
+
+import "github.com/google/uuid"
+
+// CreateFundingRuleRequest for adding a rule that funds a goal automatically. Which
+// settings are required depends on the type: percent (and optionally income_source)
+// for percent_of_income, round_to for round_up, amount and day_of_month for fixed_monthly.
+type CreateFundingRuleRequest struct {
+	GoalID       uuid.UUID `json:"goal_id" binding:"required"`
+	Name         string    `json:"name" binding:"required,max=100"`
+	Type         string    `json:"type" binding:"required,oneof=percent_of_income round_up fixed_monthly"`
+	Enabled      *bool     `json:"enabled"`
+	Percent      *float64  `json:"percent"`
+	IncomeSource *string   `json:"income_source"`
+	RoundTo      *float64  `json:"round_to"`
+	Amount       *float64  `json:"amount"`
+	DayOfMonth   *int      `json:"day_of_month"`
+}
+
+// UpdateFundingRuleRequest for editing a funding rule. The goal and type cannot
+// change; an empty income_source makes the rule match every income.
+type UpdateFundingRuleRequest struct {
+	Name         *string  `json:"name" binding:"omitempty,max=100"`
+	Enabled      *bool    `json:"enabled"`
+	Percent      *float64 `json:"percent"`
+	IncomeSource *string  `json:"income_source"`
+	RoundTo      *float64 `json:"round_to"`
+	Amount       *float64 `json:"amount"`
+	DayOfMonth   *int     `json:"day_of_month"`
+}
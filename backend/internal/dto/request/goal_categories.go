@@ -0,0 +1,19 @@
+package request
+
+// CreateGoalCategoryRequest for adding a goal category of the user's own
+type CreateGoalCategoryRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	Icon        string `json:"icon" binding:"max=50"`
+	Color       string `json:"color" binding:"omitempty,hexcolor,max=7"`
+}
+
+// UpdateGoalCategoryRequest for editing or hiding one of the user's goal categories.
+// Hidden categories are left out of listings and take no new goals.
+type UpdateGoalCategoryRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=500"`
+	Icon        *string `json:"icon" binding:"omitempty,max=50"`
+	Color       *string `json:"color" binding:"omitempty,hexcolor,max=7"`
+	Hidden      *bool   `json:"hidden"`
+}
@@ -0,0 +1,40 @@
+package request
+
+import "github.com/google/uuid"
+
+// TemplateGoalRequest is a goal of a template with its sub-goals. Target is a formula
+// over avg_monthly_expenses, avg_monthly_income and, in sub-goals, parent, the
+// target of the parent goal; months sets the target date, 0 leaving it open.
+type TemplateGoalRequest struct {
+	Name        string                `json:"name" binding:"required,min=1,max=200"`
+	Description string                `json:"description" binding:"max=1000"`
+	Category    string                `json:"category" binding:"max=100"`
+	Target      string                `json:"target" binding:"required,max=200"`
+	Months      int                   `json:"months" binding:"min=0,max=600"`
+	Subgoals    []TemplateGoalRequest `json:"subgoals" binding:"max=20,dive"`
+}
+
+// CreateGoalTemplateRequest for adding a goal template, either from a definition
+// or by saving an existing goal with its sub-goals
+type CreateGoalTemplateRequest struct {
+	Name        string               `json:"name" binding:"required,min=1,max=200"`
+	Description string               `json:"description" binding:"max=1000"`
+	Goal        *TemplateGoalRequest `json:"goal" binding:"required_without=GoalID"`
+	GoalID      *uuid.UUID           `json:"goal_id"`
+}
+
+// UpdateGoalTemplateRequest for editing one of the user's goal templates; goal
+// replaces the whole definition
+type UpdateGoalTemplateRequest struct {
+	Name        *string              `json:"name" binding:"omitempty,min=1,max=200"`
+	Description *string              `json:"description" binding:"omitempty,max=1000"`
+	Goal        *TemplateGoalRequest `json:"goal"`
+}
+
+// InstantiateGoalTemplateRequest for creating goals from a template. Name renames
+// the main goal; the averages replace the user's own in the target formulas.
+type InstantiateGoalTemplateRequest struct {
+	Name               string   `json:"name" binding:"max=200"`
+	AvgMonthlyExpenses *float64 `json:"avg_monthly_expenses" binding:"omitempty,min=0"`
+	AvgMonthlyIncome   *float64 `json:"avg_monthly_income" binding:"omitempty,min=0"`
+}
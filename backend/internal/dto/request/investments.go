@@ -0,0 +1,47 @@
+package request
+
+import "time"
+
+// CreateSecurityRequest for adding a security the user holds
+type CreateSecurityRequest struct {
+	// Symbol is stored upper case and must be unique among the user's securities
+	Symbol     string `json:"symbol" binding:"required,max=20"`
+	Name       string `json:"name" binding:"max=100"`
+	AssetClass string `json:"asset_class" binding:"required,oneof=stock bond fund property commodity crypto cash other"`
+}
+
+// UpdateSecurityRequest for editing a security
+type UpdateSecurityRequest struct {
+	Symbol     *string `json:"symbol" binding:"omitempty,max=20"`
+	Name       *string `json:"name" binding:"omitempty,max=100"`
+	AssetClass *string `json:"asset_class" binding:"omitempty,oneof=stock bond fund property commodity crypto cash other"`
+}
+
+// CreateLotRequest for recording a purchase of a security. Cost is the total paid, fees included.
+type CreateLotRequest struct {
+	Quantity float64   `json:"quantity" binding:"required,gt=0"`
+	Cost     float64   `json:"cost" binding:"min=0"`
+	BoughtOn time.Time `json:"bought_on" binding:"required"`
+}
+
+// CreateSaleRequest for recording a sale of part of a holding. Proceeds is the total received, net of fees.
+type CreateSaleRequest struct {
+	Quantity float64   `json:"quantity" binding:"required,gt=0"`
+	Proceeds float64   `json:"proceeds" binding:"min=0"`
+	SoldOn   time.Time `json:"sold_on" binding:"required"`
+}
+
+// CreateDividendRequest for recording a dividend as an income. The source defaults
+// to the security's symbol followed by "dividend".
+type CreateDividendRequest struct {
+	Amount     float64   `json:"amount" binding:"required,gt=0"`
+	ReceivedAt time.Time `json:"received_at" binding:"required"`
+	Source     string    `json:"source" binding:"max=200"`
+	Tags       []string  `json:"tags" binding:"max=20,dive,max=50"`
+}
+
+// PortfolioRequest for valuing the user's holdings
+type PortfolioRequest struct {
+	// Date values the holdings at the end of a day; today when nil
+	Date *time.Time
+}
@@ -0,0 +1,17 @@
+package request
+
+import "time"
+
+// ChangeGoalStatusRequest for moving a goal through its lifecycle; the note is kept in its status history
+type ChangeGoalStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active paused completed archived abandoned"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+// CreateGoalWithdrawalRequest for taking money back out of a goal. The amount is
+// positive and may not exceed what the goal holds.
+type CreateGoalWithdrawalRequest struct {
+	Amount      float64   `json:"amount" binding:"required,gt=0"`
+	Reason      string    `json:"reason" binding:"required,max=500"`
+	WithdrawnAt time.Time `json:"withdrawn_at" binding:"required"`
+}
@@ -0,0 +1,34 @@
+package request
+
+import "github.com/google/uuid"
+
+// MerchantPatternRequest for mapping descriptions to a merchant
+type MerchantPatternRequest struct {
+	Pattern string `json:"pattern" binding:"required"`
+	// MatchType is exact, prefix or contains (the default)
+	MatchType string `json:"match_type"`
+}
+
+// CreateMerchantRequest for adding a merchant. Its normalised name is always
+// added as a pattern, in addition to any listed here.
+type CreateMerchantRequest struct {
+	Name     string                   `json:"name" binding:"required"`
+	Patterns []MerchantPatternRequest `json:"patterns" binding:"dive"`
+}
+
+// UpdateMerchantRequest for renaming a merchant
+type UpdateMerchantRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// MergeMerchantsRequest for folding duplicate merchants into one
+type MergeMerchantsRequest struct {
+	SourceIDs []uuid.UUID `json:"source_ids" binding:"required,min=1"`
+}
+
+// ResolveMerchantsRequest for linking existing expenses to merchants
+type ResolveMerchantsRequest struct {
+	// Overwrite also re-links expenses that already have a merchant
+	Overwrite bool `json:"overwrite"`
+	DryRun    bool `json:"dry_run"`
+}
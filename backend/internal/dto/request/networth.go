@@ -0,0 +1,37 @@
+package request
+
+import "time"
+
+// CreateNetWorthItemRequest for adding an asset or liability valued by hand. The
+// type depends on the class: property, vehicle, investment, cash or other for
+// assets; mortgage, loan, credit_card or other for liabilities. A value records
+// the first valuation, dated valued_on or today.
+type CreateNetWorthItemRequest struct {
+	Name        string     `json:"name" binding:"required,max=100"`
+	Class       string     `json:"class" binding:"required,oneof=asset liability"`
+	Type        string     `json:"type" binding:"required,max=20"`
+	Description string     `json:"description" binding:"max=1000"`
+	Value       *float64   `json:"value" binding:"omitempty,min=0"`
+	ValuedOn    *time.Time `json:"valued_on"`
+}
+
+// UpdateNetWorthItemRequest for editing an asset or liability; its class cannot change
+type UpdateNetWorthItemRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	Type        *string `json:"type" binding:"omitempty,max=20"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+}
+
+// CreateValuationRequest for recording what an asset is worth or a liability owes.
+// valued_on defaults to today; a second valuation on the same day replaces the first.
+type CreateValuationRequest struct {
+	Value    *float64   `json:"value" binding:"required,min=0"`
+	ValuedOn *time.Time `json:"valued_on"`
+	Note     string     `json:"note" binding:"max=500"`
+}
+
+// NetWorthHistoryRequest for the net worth time series
+type NetWorthHistoryRequest struct {
+	// Months of snapshots to return before the current month; defaults to 12
+	Months int
+}
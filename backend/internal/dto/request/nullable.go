@@ -0,0 +1,35 @@
+package request
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// NullableID is an ID field of an update request that can be set, cleared with null or
+// left out. A plain pointer cannot tell null from a missing field, as encoding/json leaves
+// it nil in both cases, so Set records that the field was sent.
+type NullableID struct {
+	Set bool
+	ID  *uuid.UUID // nil when the field was sent as null
+}
+
+// SetID returns a NullableID sent with id, or with null when id is nil
+func SetID(id *uuid.UUID) NullableID {
+	return NullableID{Set: true, ID: id}
+}
+
+// UnmarshalJSON marks the field as sent; encoding/json calls it for null too
+func (n *NullableID) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	n.ID = nil
+	if string(data) == "null" {
+		return nil
+	}
+	var id uuid.UUID
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	n.ID = &id
+	return nil
+}
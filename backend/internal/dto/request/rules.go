@@ -0,0 +1,49 @@
+package request
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RuleConditionRequest is one condition of an expense rule
+type RuleConditionRequest struct {
+	Field    string `json:"field" binding:"required"`
+	Operator string `json:"operator" binding:"required"`
+	Value    string `json:"value"`
+}
+
+// CreateRuleRequest for adding an expense rule
+type CreateRuleRequest struct {
+	Name       string                 `json:"name" binding:"required"`
+	Priority   int                    `json:"priority"`
+	Enabled    *bool                  `json:"enabled"`
+	Conditions []RuleConditionRequest `json:"conditions" binding:"required,min=1,dive"`
+	// Actions; at least one is required
+	SetCategory *string    `json:"set_category"`
+	SetGoalID   *uuid.UUID `json:"set_goal_id"`
+	ClearGoal   bool       `json:"clear_goal"`
+}
+
+// UpdateRuleRequest for editing an expense rule. An empty set_category or a nil
+// set_goal_id UUID removes that action; conditions replace the existing ones.
+type UpdateRuleRequest struct {
+	Name        *string                `json:"name"`
+	Priority    *int                   `json:"priority"`
+	Enabled     *bool                  `json:"enabled"`
+	Conditions  []RuleConditionRequest `json:"conditions" binding:"omitempty,min=1,dive"`
+	SetCategory *string                `json:"set_category"`
+	SetGoalID   *uuid.UUID             `json:"set_goal_id"`
+	ClearGoal   *bool                  `json:"clear_goal"`
+}
+
+// ApplyRulesRequest for re-running rules over existing expenses
+type ApplyRulesRequest struct {
+	// DryRun previews the changes without saving them
+	DryRun bool `json:"dry_run"`
+	// RuleIDs limits evaluation to these rules; all enabled rules are used when empty
+	RuleIDs []uuid.UUID `json:"rule_ids"`
+	// From and To limit the expenses by spent_at; From is inclusive and To exclusive
+	From *time.Time `json:"from"`
+	To   *time.Time `json:"to"`
+}
@@ -0,0 +1,35 @@
+package response
+
+import "github.com/google/uuid"
+
+// SurplusAllocationResponse shows how a month's surplus is, or was, distributed across goals
+type SurplusAllocationResponse struct {
+	Year     int     `json:"year"`
+	Month    int     `json:"month"`
+	Income   float64 `json:"income"`
+	Expenses float64 `json:"expenses"`
+	// Contributed is what was already contributed to goals during the month
+	Contributed float64 `json:"contributed"`
+	// Surplus is income less expenses and contributions; Available is the part allocated from
+	Surplus     float64                  `json:"surplus"`
+	Available   float64                  `json:"available"`
+	Allocated   float64                  `json:"allocated"`
+	Unallocated float64                  `json:"unallocated"`
+	Allocations []GoalAllocationResponse `json:"allocations"`
+	// Committed is set when the contributions were written
+	Committed     bool                       `json:"committed"`
+	Contributions []GoalContributionResponse `json:"contributions,omitempty"`
+}
+
+// GoalAllocationResponse is one goal's place in the allocation waterfall. Parent goals
+// pass their share on to their sub-goals and are listed before them.
+type GoalAllocationResponse struct {
+	GoalID       uuid.UUID  `json:"goal_id"`
+	Name         string     `json:"name"`
+	ParentGoalID *uuid.UUID `json:"parent_goal_id"`
+	Priority     int        `json:"priority"`
+	Weight       float64    `json:"weight"`
+	// MonthlyNeed is what the goal still needs this month; Amount is what it is allocated
+	MonthlyNeed float64 `json:"monthly_need"`
+	Amount      float64 `json:"amount"`
+}
@@ -0,0 +1,20 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttachmentResponse represents attachment metadata in API responses
+type AttachmentResponse struct {
+	ID          uuid.UUID `json:"id"`
+	OwnerType   string    `json:"owner_type"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	// HasThumbnail is set for images, whose thumbnail is served at /api/attachments/:id/thumbnail
+	HasThumbnail bool      `json:"has_thumbnail"`
+	CreatedAt    time.Time `json:"created_at"`
+}
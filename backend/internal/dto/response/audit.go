@@ -0,0 +1,19 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntryResponse represents an audit log entry in API responses
+type AuditEntryResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	Actor      string          `json:"actor"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id"`
+	Action     string          `json:"action"`
+	Changes    json.RawMessage `json:"changes"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
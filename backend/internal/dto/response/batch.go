@@ -0,0 +1,24 @@
+package response
+
+import "github.com/google/uuid"
+
+// BatchItemResult reports the outcome of one operation in a batch request
+type BatchItemResult struct {
+	Index   int         `json:"index"`
+	Op      string      `json:"op"`
+	ID      *uuid.UUID  `json:"id,omitempty"`
+	Status  int         `json:"status"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Details string      `json:"details,omitempty"`
+	// Current carries the row's current representation when a version check failed
+	Current interface{} `json:"current,omitempty"`
+}
+
+// BatchResponse represents the outcome of a batch request
+type BatchResponse struct {
+	Mode      string            `json:"mode"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
@@ -0,0 +1,110 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DebtResponse represents a loan or credit card with its outstanding balance
+type DebtResponse struct {
+	ID             uuid.UUID `json:"id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Name           string    `json:"name"`
+	Kind           string    `json:"kind"`
+	Principal      float64   `json:"principal"`
+	APR            float64   `json:"apr"`
+	MinimumPayment float64   `json:"minimum_payment"`
+	DueDay         int       `json:"due_day"`
+	StartedOn      time.Time `json:"started_on"`
+	// Paid and Interest are the payments made and the interest charged since started_on
+	Paid     float64 `json:"paid"`
+	Interest float64 `json:"interest"`
+	// Balance is what is owed today
+	Balance     float64   `json:"balance"`
+	NextDueDate time.Time `json:"next_due_date"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DebtScheduleResponse is the amortisation schedule of a debt from its current balance
+type DebtScheduleResponse struct {
+	DebtID  uuid.UUID `json:"debt_id"`
+	Balance float64   `json:"balance"`
+	// Payment is the amount paid on every due date, the minimum payment unless another was asked for
+	Payment       float64                     `json:"payment"`
+	Months        int                         `json:"months"`
+	TotalInterest float64                     `json:"total_interest"`
+	TotalPaid     float64                     `json:"total_paid"`
+	PayoffDate    *time.Time                  `json:"payoff_date"`
+	Schedule      []DebtScheduleEntryResponse `json:"schedule"`
+}
+
+// DebtScheduleEntryResponse is one due date in an amortisation schedule
+type DebtScheduleEntryResponse struct {
+	Month     int       `json:"month"`
+	DueDate   time.Time `json:"due_date"`
+	Payment   float64   `json:"payment"`
+	Interest  float64   `json:"interest"`
+	Principal float64   `json:"principal"`
+	// Balance is what is owed after the payment
+	Balance float64 `json:"balance"`
+}
+
+// DebtPayoffResponse compares paying debts off smallest balance first (snowball)
+// with highest APR first (avalanche)
+type DebtPayoffResponse struct {
+	ExtraMonthly float64 `json:"extra_monthly"`
+	// MonthlyBudget is the debts' minimum payments plus the extra amount
+	MonthlyBudget float64                `json:"monthly_budget"`
+	Balance       float64                `json:"balance"`
+	Snowball      DebtPayoffPlanResponse `json:"snowball"`
+	Avalanche     DebtPayoffPlanResponse `json:"avalanche"`
+	// InterestSaved is how much less interest the avalanche pays than the snowball
+	InterestSaved float64 `json:"interest_saved"`
+	// Recommended is the strategy that pays the least interest, snowball on a tie
+	Recommended string `json:"recommended"`
+}
+
+// DebtPayoffPlanResponse is the month-by-month result of one payoff strategy
+type DebtPayoffPlanResponse struct {
+	Strategy string `json:"strategy"`
+	// Order lists the debts in the order extra payments go to them
+	Order         []uuid.UUID                `json:"order"`
+	Months        int                        `json:"months"`
+	TotalInterest float64                    `json:"total_interest"`
+	TotalPaid     float64                    `json:"total_paid"`
+	Debts         []DebtPayoffResultResponse `json:"debts"`
+	Schedule      []DebtPayoffMonthResponse  `json:"schedule"`
+}
+
+// DebtPayoffResultResponse is how a debt fares under a payoff strategy
+type DebtPayoffResultResponse struct {
+	DebtID uuid.UUID `json:"debt_id"`
+	Name   string    `json:"name"`
+	// PaidOffMonth is the index of the plan month the debt is cleared in
+	PaidOffMonth int     `json:"paid_off_month"`
+	Interest     float64 `json:"interest"`
+	Paid         float64 `json:"paid"`
+}
+
+// DebtPayoffMonthResponse is one month of a payoff plan
+type DebtPayoffMonthResponse struct {
+	// Index counts the plan's months from 1
+	Index    int     `json:"index"`
+	Year     int     `json:"year"`
+	Month    int     `json:"month"`
+	Payment  float64 `json:"payment"`
+	Interest float64 `json:"interest"`
+	// Balance is what is owed across the debts at the end of the month
+	Balance float64                     `json:"balance"`
+	Debts   []DebtPayoffBalanceResponse `json:"debts"`
+}
+
+// DebtPayoffBalanceResponse is a debt's payment, interest and balance in a plan month
+type DebtPayoffBalanceResponse struct {
+	DebtID   uuid.UUID `json:"debt_id"`
+	Payment  float64   `json:"payment"`
+	Interest float64   `json:"interest"`
+	Balance  float64   `json:"balance"`
+}
@@ -13,7 +13,14 @@ type IncomeResponse struct {
 	Source     string    `json:"source"`
 	Amount     float64   `json:"amount"`
 	ReceivedAt time.Time `json:"received_at"`
+	Tags       []string  `json:"tags"`
+	Version    int       `json:"version"`
 	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// Funding lists the goal contributions funding rules generated from the new income
+	Funding []GoalContributionResponse `json:"funding,omitempty"`
+	// SecurityID is set on dividends
+	SecurityID *uuid.UUID `json:"security_id"`
 }
 
 // ExpenseResponse represents expense data in API responses
@@ -21,11 +28,21 @@ type ExpenseResponse struct {
 	ID          uuid.UUID  `json:"id"`
 	UserID      uuid.UUID  `json:"user_id"`
 	Category    string     `json:"category"`
+	CategoryID  *uuid.UUID `json:"category_id"`
 	Description string     `json:"description"`
 	Amount      float64    `json:"amount"`
 	SpentAt     time.Time  `json:"spent_at"`
 	GoalID      *uuid.UUID `json:"goal_id"`
+	MerchantID  *uuid.UUID `json:"merchant_id"`
+	DebtID      *uuid.UUID `json:"debt_id"`
+	Tags        []string   `json:"tags"`
+	Version     int        `json:"version"`
 	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// AppliedRules lists the expense rules that matched when the expense was created
+	AppliedRules []uuid.UUID `json:"applied_rules,omitempty"`
+	// Funding lists the goal contributions funding rules generated from the new expense
+	Funding []GoalContributionResponse `json:"funding,omitempty"`
 }
 
 // GoalResponse represents goal data in API responses
@@ -35,12 +52,20 @@ type GoalResponse struct {
 	Name         string     `json:"name"`
 	Description  string     `json:"description"`
 	Category     string     `json:"category"`
+	CategoryID   *uuid.UUID `json:"category_id"`
 	TargetAmount float64    `json:"target_amount"`
 	TargetDate   *time.Time `json:"target_date"`
 	ParentGoalID *uuid.UUID `json:"parent_goal_id"`
 	IsMainGoal   bool       `json:"is_main_goal"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	// AllocationPriority and AllocationWeight order and split month-end surplus allocations
+	AllocationPriority int     `json:"allocation_priority"`
+	AllocationWeight   float64 `json:"allocation_weight"`
+	// Status is the goal's lifecycle status, changed at StatusChangedAt
+	Status          string     `json:"status"`
+	StatusChangedAt *time.Time `json:"status_changed_at"`
+	Version         int        `json:"version"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 // GoalContributionResponse represents goal contribution data in API responses
@@ -50,17 +75,28 @@ type GoalContributionResponse struct {
 	GoalID        uuid.UUID `json:"goal_id"`
 	Amount        float64   `json:"amount"`
 	ContributedAt time.Time `json:"contributed_at"`
-	CreatedAt     time.Time `json:"created_at"`
+	// FundingRuleID is set on contributions generated by a funding rule, and SourceID
+	// on those triggered by an income or expense
+	FundingRuleID *uuid.UUID `json:"funding_rule_id"`
+	SourceID      *uuid.UUID `json:"source_id"`
+	// Reason is set on withdrawals, whose amount is negative
+	Reason    *string   `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
-// GoalCategoryResponse represents goal category data in API responses
+// GoalCategoryResponse represents goal category data in API responses.
+// Built-in categories have no user and cannot be changed.
 type GoalCategoryResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Icon        string    `json:"icon"`
-	Color       string    `json:"color"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          uuid.UUID  `json:"id"`
+	UserID      *uuid.UUID `json:"user_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Icon        string     `json:"icon"`
+	Color       string     `json:"color"`
+	BuiltIn     bool       `json:"built_in"`
+	Hidden      bool       `json:"hidden"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 // GoalExpenseResponse represents goal expense data in API responses
@@ -82,22 +118,55 @@ type GoalWithSubgoalsResponse struct {
 
 // MonthlySummaryResponse represents monthly summary data in API responses
 type MonthlySummaryResponse struct {
-	Year              int                   `json:"year"`
-	Month             int                   `json:"month"`
-	TotalIncome       float64               `json:"total_income"`
-	TotalExpenses     float64               `json:"total_expenses"`
-	TotalSavings      float64               `json:"total_savings"`
-	CategoryBreakdown map[string]float64    `json:"category_breakdown"`
-	GoalSpending      map[uuid.UUID]float64 `json:"goal_spending"`
-	GoalContributions map[uuid.UUID]float64 `json:"goal_contributions"`
+	Year              int                `json:"year"`
+	Month             int                `json:"month"`
+	TotalIncome       float64            `json:"total_income"`
+	TotalExpenses     float64            `json:"total_expenses"`
+	TotalSavings      float64            `json:"total_savings"`
+	CategoryBreakdown map[string]float64 `json:"category_breakdown"`
+	// CategoryTotals rolls spending up the category tree, largest subtotal first
+	CategoryTotals    []CategoryTotalResponse `json:"category_totals"`
+	GoalSpending      map[uuid.UUID]float64   `json:"goal_spending"`
+	GoalContributions map[uuid.UUID]float64   `json:"goal_contributions"`
+	// MerchantSpending is ordered by total, highest first
+	MerchantSpending []MerchantSpendingResponse `json:"merchant_spending"`
+	// TagIncome and TagSpending total each tag; a transaction counts towards all of its tags
+	TagIncome   map[string]float64 `json:"tag_income"`
+	TagSpending map[string]float64 `json:"tag_spending"`
+	// Investments covers the dividends and realised gains of the month and what the holdings were worth at its end
+	Investments InvestmentMonthResponse `json:"investments"`
 }
 
 // CategoryResponse represents category data in API responses
 type CategoryResponse struct {
-	ID        uuid.UUID `json:"id"`
-	UserID    uuid.UUID `json:"user_id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	ParentID  *uuid.UUID `json:"parent_id"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// MergeCategoriesResponse reports the result of merging categories
+type MergeCategoriesResponse struct {
+	Category      CategoryResponse `json:"category"`
+	MovedExpenses int64            `json:"moved_expenses"`
+}
+
+// BulkTagResponse reports how many transactions a bulk tag edit changed
+type BulkTagResponse struct {
+	UpdatedIncomes  int `json:"updated_incomes"`
+	UpdatedExpenses int `json:"updated_expenses"`
+}
+
+// CategoryTotalResponse is a category's spending in a month. Total covers
+// expenses filed directly under the category and Subtotal adds its descendants.
+type CategoryTotalResponse struct {
+	CategoryID uuid.UUID               `json:"category_id"`
+	Name       string                  `json:"name"`
+	Total      float64                 `json:"total"`
+	Subtotal   float64                 `json:"subtotal"`
+	Children   []CategoryTotalResponse `json:"children"`
 }
 
 // HistoricalSummaryResponse represents historical summary data in API responses
@@ -121,4 +190,31 @@ type GoalWithProgressResponse struct {
 	ContributedSum float64      `json:"contributed_sum"`
 	ExpenseSum     float64      `json:"expense_sum"`
 	Progress       float64      `json:"progress"` // percentage of target achieved
+	Remaining      float64      `json:"remaining"`
+	// RequiredMonthly is the monthly contribution that reaches the target by its date;
+	// nil for goals without a target date
+	RequiredMonthly *float64 `json:"required_monthly"`
+	// Pace is the average monthly contribution over the last PaceWindowMonths months
+	Pace             float64 `json:"pace"`
+	PaceWindowMonths int     `json:"pace_window_months"`
+	// ProjectedCompletion is when the target is reached at the current pace; nil when
+	// the goal is complete or nothing was contributed recently
+	ProjectedCompletion *time.Time `json:"projected_completion"`
+	// PaceStatus is completed, ahead, on_track, behind or unscheduled
+	PaceStatus string `json:"pace_status"`
+}
+
+// CategoryScore is a candidate category with the model's confidence in it
+type CategoryScore struct {
+	Category   string  `json:"category"`
+	Confidence float64 `json:"confidence"`
+}
+
+// CategorySuggestionResponse is the suggested category for an expense description.
+// Category is empty when the user has no categorised expenses to learn from.
+type CategorySuggestionResponse struct {
+	Category     string          `json:"category"`
+	Confidence   float64         `json:"confidence"`
+	Alternatives []CategoryScore `json:"alternatives"`
+	TrainedOn    int             `json:"trained_on"`
 }
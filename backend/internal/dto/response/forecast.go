@@ -0,0 +1,56 @@
+package response
+
+import "github.com/google/uuid"
+
+// ForecastResponse projects income, expenses and balance for the coming months
+type ForecastResponse struct {
+	StartingBalance float64 `json:"starting_balance"`
+	HistoryMonths   int     `json:"history_months"`
+	// ExcludedMonths lists the YYYY-MM months left out of the averages, whether
+	// requested or detected as outliers
+	ExcludedMonths []string `json:"excluded_months"`
+	// ConfidenceLevel is the probability the balance falls within each month's band
+	ConfidenceLevel  float64                   `json:"confidence_level"`
+	Recurring        []RecurringItemResponse   `json:"recurring"`
+	CategoryAverages []CategoryAverageResponse `json:"category_averages"`
+	// VariableIncome is the average monthly income that is not recurring
+	VariableIncome float64                 `json:"variable_income"`
+	Months         []ForecastMonthResponse `json:"months"`
+}
+
+// RecurringItemResponse is an income or expense that turns up nearly every month
+type RecurringItemResponse struct {
+	// Type is income or expense
+	Type       string     `json:"type"`
+	Name       string     `json:"name"`
+	MerchantID *uuid.UUID `json:"merchant_id,omitempty"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	Category   string     `json:"category,omitempty"`
+	// Amount is the median monthly total projected for each coming month
+	Amount float64 `json:"amount"`
+	// Occurrences is the number of history months it appeared in
+	Occurrences int `json:"occurrences"`
+}
+
+// CategoryAverageResponse is the average monthly variable spending in a category
+type CategoryAverageResponse struct {
+	CategoryID *uuid.UUID `json:"category_id"`
+	Category   string     `json:"category"`
+	Average    float64    `json:"average"`
+}
+
+// ForecastMonthResponse is the projection for one month
+type ForecastMonthResponse struct {
+	Year        int     `json:"year"`
+	Month       int     `json:"month"`
+	Income      float64 `json:"income"`
+	Expenses    float64 `json:"expenses"`
+	GoalSavings float64 `json:"goal_savings"`
+	Net         float64 `json:"net"`
+	// Balance is the projected balance at the end of the month
+	Balance     float64 `json:"balance"`
+	BalanceLow  float64 `json:"balance_low"`
+	BalanceHigh float64 `json:"balance_high"`
+	// Negative flags months whose projected balance is below zero
+	Negative bool `json:"negative"`
+}
@@ -0,0 +1,30 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FundingRuleResponse represents a goal funding rule in API responses
+type FundingRuleResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	GoalID       uuid.UUID  `json:"goal_id"`
+	Name         string     `json:"name"`
+	Type         string     `json:"type"`
+	Enabled      bool       `json:"enabled"`
+	Percent      *float64   `json:"percent"`
+	IncomeSource *string    `json:"income_source"`
+	RoundTo      *float64   `json:"round_to"`
+	Amount       *float64   `json:"amount"`
+	DayOfMonth   *int       `json:"day_of_month"`
+	LastFundedOn *time.Time `json:"last_funded_on"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// RunFundingRulesResponse lists the contributions generated by scheduled funding rules
+type RunFundingRulesResponse struct {
+	Contributions []GoalContributionResponse `json:"contributions"`
+}
@@ -0,0 +1,39 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateGoalResponse is a goal of a template with its sub-goals
+type TemplateGoalResponse struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Category    string                 `json:"category"`
+	Target      string                 `json:"target"`
+	Months      int                    `json:"months"`
+	Subgoals    []TemplateGoalResponse `json:"subgoals"`
+}
+
+// GoalTemplateResponse represents a goal template in API responses.
+// Built-in templates have no user and cannot be changed.
+type GoalTemplateResponse struct {
+	ID          uuid.UUID            `json:"id"`
+	UserID      *uuid.UUID           `json:"user_id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	BuiltIn     bool                 `json:"built_in"`
+	Goal        TemplateGoalResponse `json:"goal"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// GoalTemplateInstanceResponse lists the goals created from a template, the main
+// goal first, and the averages their targets were worked out from
+type GoalTemplateInstanceResponse struct {
+	TemplateID         uuid.UUID      `json:"template_id"`
+	AvgMonthlyExpenses float64        `json:"avg_monthly_expenses"`
+	AvgMonthlyIncome   float64        `json:"avg_monthly_income"`
+	Goals              []GoalResponse `json:"goals"`
+}
@@ -0,0 +1,108 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SecurityResponse represents a security in API responses
+type SecurityResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Symbol     string    `json:"symbol"`
+	Name       string    `json:"name"`
+	AssetClass string    `json:"asset_class"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// LotResponse represents a purchase of a security
+type LotResponse struct {
+	ID         uuid.UUID `json:"id"`
+	SecurityID uuid.UUID `json:"security_id"`
+	BoughtOn   time.Time `json:"bought_on"`
+	Quantity   float64   `json:"quantity"`
+	Cost       float64   `json:"cost"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SaleResponse represents a sale of part of a holding
+type SaleResponse struct {
+	ID         uuid.UUID `json:"id"`
+	SecurityID uuid.UUID `json:"security_id"`
+	SoldOn     time.Time `json:"sold_on"`
+	Quantity   float64   `json:"quantity"`
+	Proceeds   float64   `json:"proceeds"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SecurityPriceResponse is a security's closing price on a day
+type SecurityPriceResponse struct {
+	PricedOn time.Time `json:"priced_on"`
+	Close    float64   `json:"close"`
+}
+
+// ImportPricesResponse reports a CSV price import. Rows listed in Errors were skipped.
+type ImportPricesResponse struct {
+	Imported int                      `json:"imported"`
+	Errors   []ImportPriceRowResponse `json:"errors"`
+}
+
+// ImportPriceRowResponse explains why a line of a CSV price import was skipped
+type ImportPriceRowResponse struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// GainsResponse is the cost of what is still held and the gains made under one
+// cost basis method
+type GainsResponse struct {
+	CostBasis      float64 `json:"cost_basis"`
+	UnrealisedGain float64 `json:"unrealised_gain"`
+	RealisedGain   float64 `json:"realised_gain"`
+}
+
+// HoldingResponse is what is held of a security on a day. Holdings without an
+// imported price are valued at their FIFO cost basis and show no unrealised gain.
+type HoldingResponse struct {
+	Security    SecurityResponse `json:"security"`
+	Quantity    float64          `json:"quantity"`
+	Price       *float64         `json:"price"`
+	PricedOn    *time.Time       `json:"priced_on"`
+	MarketValue float64          `json:"market_value"`
+	// Dividends is the total of the security's dividends received up to the day
+	Dividends   float64       `json:"dividends"`
+	FIFO        GainsResponse `json:"fifo"`
+	AverageCost GainsResponse `json:"average_cost"`
+}
+
+// AllocationResponse is the share of the portfolio's market value in one asset class
+type AllocationResponse struct {
+	AssetClass  string  `json:"asset_class"`
+	MarketValue float64 `json:"market_value"`
+	// Percent of the portfolio's market value
+	Share float64 `json:"share"`
+}
+
+// PortfolioResponse values the user's holdings at the end of a day, with realised
+// gains under both FIFO and average cost, and their allocation by asset class,
+// largest first
+type PortfolioResponse struct {
+	Date        time.Time            `json:"date"`
+	MarketValue float64              `json:"market_value"`
+	Dividends   float64              `json:"dividends"`
+	FIFO        GainsResponse        `json:"fifo"`
+	AverageCost GainsResponse        `json:"average_cost"`
+	Allocation  []AllocationResponse `json:"allocation"`
+	Holdings    []HoldingResponse    `json:"holdings"`
+}
+
+// InvestmentMonthResponse is a month's investment activity: the market value of the
+// holdings at its end, the dividends received and the gains realised by sales in it
+type InvestmentMonthResponse struct {
+	MarketValue         float64 `json:"market_value"`
+	Dividends           float64 `json:"dividends"`
+	RealisedFIFO        float64 `json:"realised_fifo"`
+	RealisedAverageCost float64 `json:"realised_average_cost"`
+}
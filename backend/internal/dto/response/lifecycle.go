@@ -0,0 +1,17 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GoalStatusChangeResponse represents one entry of a goal's status history in API responses
+type GoalStatusChangeResponse struct {
+	ID         uuid.UUID `json:"id"`
+	GoalID     uuid.UUID `json:"goal_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Note       string    `json:"note"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
@@ -0,0 +1,51 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MerchantPatternResponse represents a merchant pattern in API responses
+type MerchantPatternResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Pattern   string    `json:"pattern"`
+	MatchType string    `json:"match_type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MerchantResponse represents a merchant and its patterns in API responses
+type MerchantResponse struct {
+	ID        uuid.UUID                 `json:"id"`
+	UserID    uuid.UUID                 `json:"user_id"`
+	Name      string                    `json:"name"`
+	Patterns  []MerchantPatternResponse `json:"patterns"`
+	CreatedAt time.Time                 `json:"created_at"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+}
+
+// MergeMerchantsResponse reports the result of merging merchants
+type MergeMerchantsResponse struct {
+	Merchant      MerchantResponse `json:"merchant"`
+	MovedExpenses int64            `json:"moved_expenses"`
+}
+
+// ResolveMerchantsResponse reports how many expenses were linked to merchants
+type ResolveMerchantsResponse struct {
+	DryRun    bool `json:"dry_run"`
+	Evaluated int  `json:"evaluated"`
+	Linked    int  `json:"linked"`
+	// Skipped counts expenses left alone because they were edited or deleted meanwhile
+	Skipped int `json:"skipped"`
+}
+
+// MerchantSpendingResponse is one merchant's spending in a month compared with the month before
+type MerchantSpendingResponse struct {
+	MerchantID    uuid.UUID `json:"merchant_id"`
+	Name          string    `json:"name"`
+	Total         float64   `json:"total"`
+	PreviousTotal float64   `json:"previous_total"`
+	Change        float64   `json:"change"`
+	// ChangePercent is nil when nothing was spent at the merchant the month before
+	ChangePercent *float64 `json:"change_percent"`
+}
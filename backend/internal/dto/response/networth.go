@@ -0,0 +1,62 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NetWorthItemResponse represents an asset or liability with its latest valuation
+type NetWorthItemResponse struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Name        string    `json:"name"`
+	Class       string    `json:"class"`
+	Type        string    `json:"type"`
+	Description string    `json:"description"`
+	// Value and ValuedOn are unset until the item is first valued
+	Value     *float64   `json:"value"`
+	ValuedOn  *time.Time `json:"valued_on"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ValuationResponse represents one valuation of an asset or liability
+type ValuationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	ItemID    uuid.UUID `json:"item_id"`
+	Value     float64   `json:"value"`
+	ValuedOn  time.Time `json:"valued_on"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NetWorthResponse is a user's net worth on a day with its breakdown by type
+type NetWorthResponse struct {
+	Date        time.Time                   `json:"date"`
+	Assets      float64                     `json:"assets"`
+	Liabilities float64                     `json:"liabilities"`
+	NetWorth    float64                     `json:"net_worth"`
+	Breakdown   []NetWorthComponentResponse `json:"breakdown"`
+}
+
+// NetWorthComponentResponse is the total of one type of asset or liability. Besides
+// the types of manually valued items, cash is the ledger balance, savings is what
+// goals hold, and loan and credit_card include the balances of tracked debts.
+type NetWorthComponentResponse struct {
+	Class  string  `json:"class"`
+	Type   string  `json:"type"`
+	Amount float64 `json:"amount"`
+}
+
+// NetWorthHistoryResponse is the net worth time series: the stored month-end
+// snapshots, oldest first, and today's figure
+type NetWorthHistoryResponse struct {
+	Snapshots []NetWorthResponse `json:"snapshots"`
+	Current   NetWorthResponse   `json:"current"`
+}
+
+// RunNetWorthSnapshotsResponse lists the snapshots a run stored
+type RunNetWorthSnapshotsResponse struct {
+	Snapshots []NetWorthResponse `json:"snapshots"`
+}
@@ -15,6 +15,7 @@ type NoteResponse struct {
 	Tags       []string  `json:"tags"`
 	IsFavorite bool      `json:"is_favorite"`
 	IsArchived bool      `json:"is_archived"`
+	Version    int       `json:"version"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
@@ -0,0 +1,67 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RuleConditionResponse represents one condition of an expense rule
+type RuleConditionResponse struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// RuleResponse represents an expense rule in API responses
+type RuleResponse struct {
+	ID            uuid.UUID               `json:"id"`
+	UserID        uuid.UUID               `json:"user_id"`
+	Name          string                  `json:"name"`
+	Priority      int                     `json:"priority"`
+	Enabled       bool                    `json:"enabled"`
+	Conditions    []RuleConditionResponse `json:"conditions"`
+	SetCategory   *string                 `json:"set_category"`
+	SetGoalID     *uuid.UUID              `json:"set_goal_id"`
+	ClearGoal     bool                    `json:"clear_goal"`
+	MatchCount    int                     `json:"match_count"`
+	LastMatchedAt *time.Time              `json:"last_matched_at"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+// RuleOutcome is the category and goal of an expense before or after rules ran
+type RuleOutcome struct {
+	Category string     `json:"category"`
+	GoalID   *uuid.UUID `json:"goal_id"`
+}
+
+// RuleChange describes how rules change one existing expense
+type RuleChange struct {
+	ExpenseID   uuid.UUID   `json:"expense_id"`
+	Description string      `json:"description"`
+	Amount      float64     `json:"amount"`
+	SpentAt     time.Time   `json:"spent_at"`
+	Before      RuleOutcome `json:"before"`
+	After       RuleOutcome `json:"after"`
+	RuleIDs     []uuid.UUID `json:"rule_ids"`
+}
+
+// RuleMatchStats reports how many expenses one rule matched in a run
+type RuleMatchStats struct {
+	RuleID  uuid.UUID `json:"rule_id"`
+	Name    string    `json:"name"`
+	Matches int       `json:"matches"`
+}
+
+// ApplyRulesResponse summarises a re-apply or dry run of expense rules
+type ApplyRulesResponse struct {
+	DryRun    bool `json:"dry_run"`
+	Evaluated int  `json:"evaluated"`
+	Matched   int  `json:"matched"`
+	Changed   int  `json:"changed"`
+	// Skipped counts expenses left alone because they were edited or deleted while rules ran
+	Skipped int              `json:"skipped"`
+	Changes []RuleChange     `json:"changes"`
+	Rules   []RuleMatchStats `json:"rules"`
+}
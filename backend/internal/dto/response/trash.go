@@ -0,0 +1,43 @@
+package response
+
+import "time"
+
+// TrashInfo describes when a trashed record was deleted and when it will be purged
+type TrashInfo struct {
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// TrashedIncomeResponse represents an income in the trash
+type TrashedIncomeResponse struct {
+	IncomeResponse
+	TrashInfo
+}
+
+// TrashedExpenseResponse represents an expense in the trash
+type TrashedExpenseResponse struct {
+	ExpenseResponse
+	TrashInfo
+}
+
+// TrashedGoalResponse represents a goal in the trash; sub-goals deleted with
+// their parent are listed too and come back when the parent is restored
+type TrashedGoalResponse struct {
+	GoalResponse
+	TrashInfo
+}
+
+// TrashedNoteResponse represents a note in the trash
+type TrashedNoteResponse struct {
+	NoteResponse
+	TrashInfo
+}
+
+// TrashResponse lists everything in a user's trash, most recently deleted first
+type TrashResponse struct {
+	RetentionDays int                      `json:"retention_days"`
+	Incomes       []TrashedIncomeResponse  `json:"incomes"`
+	Expenses      []TrashedExpenseResponse `json:"expenses"`
+	Goals         []TrashedGoalResponse    `json:"goals"`
+	Notes         []TrashedNoteResponse    `json:"notes"`
+}
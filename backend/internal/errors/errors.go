@@ -11,6 +11,8 @@ type AppError struct {
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
 	Err     error  `json:"-"`
+	// Current carries the resource's current representation for 412 responses
+	Current interface{} `json:"-"`
 }
 
 // Error implements the error interface
@@ -62,14 +64,80 @@ func WrapWithDetails(err error, code int, message, details string) *AppError {
 	}
 }
 
+// NewPreconditionFailed creates a 412 error carrying the current representation
+// of a resource whose version did not match the request's If-Match header
+func NewPreconditionFailed(current interface{}) *AppError {
+	return &AppError{
+		Code:    http.StatusPreconditionFailed,
+		Message: "Resource has been modified",
+		Details: "Reload the current version and reapply your changes",
+		Current: current,
+	}
+}
+
 // Predefined error types
 var (
 	// Validation errors (400)
-	ErrInvalidInput = New(http.StatusBadRequest, "Invalid input provided")
-	ErrMissingField = New(http.StatusBadRequest, "Required field is missing")
+	ErrInvalidInput          = New(http.StatusBadRequest, "Invalid input provided")
+	ErrMissingField          = New(http.StatusBadRequest, "Required field is missing")
+	ErrInvalidIdempotencyKey = New(http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+	ErrCategoryMismatch      = New(http.StatusBadRequest, "category and category_id name different categories")
+
+	// Forbidden errors (403)
+	ErrGoalCategoryBuiltIn = New(http.StatusForbidden, "Built-in goal categories cannot be changed")
+	ErrGoalTemplateBuiltIn = New(http.StatusForbidden, "Built-in goal templates cannot be changed")
 
 	// Not found errors (404)
-	ErrNoteNotFound = New(http.StatusNotFound, "Note not found")
+	ErrNoteNotFound         = New(http.StatusNotFound, "Note not found")
+	ErrTrashItemNotFound    = New(http.StatusNotFound, "Item not found in trash")
+	ErrIncomeNotFound       = New(http.StatusNotFound, "Income not found")
+	ErrExpenseNotFound      = New(http.StatusNotFound, "Expense not found")
+	ErrGoalNotFound         = New(http.StatusNotFound, "Goal not found")
+	ErrContributionNotFound = New(http.StatusNotFound, "Goal contribution not found")
+	ErrRuleNotFound         = New(http.StatusNotFound, "Rule not found")
+	ErrFundingRuleNotFound  = New(http.StatusNotFound, "Funding rule not found")
+	ErrMerchantNotFound     = New(http.StatusNotFound, "Merchant not found")
+	ErrCategoryNotFound     = New(http.StatusNotFound, "Category not found")
+	ErrGoalCategoryNotFound = New(http.StatusNotFound, "Goal category not found")
+	ErrGoalTemplateNotFound = New(http.StatusNotFound, "Goal template not found")
+	ErrPatternNotFound      = New(http.StatusNotFound, "Merchant pattern not found")
+	ErrAttachmentNotFound   = New(http.StatusNotFound, "Attachment not found")
+	ErrThumbnailNotFound    = New(http.StatusNotFound, "Attachment has no thumbnail")
+	ErrDebtNotFound         = New(http.StatusNotFound, "Debt not found")
+	ErrNetWorthItemNotFound = New(http.StatusNotFound, "Asset or liability not found")
+	ErrValuationNotFound    = New(http.StatusNotFound, "Valuation not found")
+	ErrSecurityNotFound     = New(http.StatusNotFound, "Security not found")
+	ErrLotNotFound          = New(http.StatusNotFound, "Investment lot not found")
+	ErrSaleNotFound         = New(http.StatusNotFound, "Investment sale not found")
+
+	// Conflict errors (409)
+	ErrParentGoalDeleted        = New(http.StatusConflict, "Restore the parent goal first")
+	ErrIdempotencyKeyInProgress = New(http.StatusConflict, "A request with this Idempotency-Key is still being processed")
+	ErrMerchantExists           = New(http.StatusConflict, "A merchant with this name already exists")
+	ErrPatternExists            = New(http.StatusConflict, "This merchant pattern already exists")
+	ErrCategoryExists           = New(http.StatusConflict, "A category with this name already exists here")
+	ErrCategoryInUse            = New(http.StatusConflict, "Category is still used; choose a category to reassign it to")
+	ErrGoalCategoryExists       = New(http.StatusConflict, "A goal category with this name already exists")
+	ErrGoalCategoryHidden       = New(http.StatusConflict, "Goal category is hidden; show it again to file goals under it")
+	ErrGoalNotActive            = New(http.StatusConflict, "Goal is archived or abandoned and takes no contributions")
+	ErrGoalStatusTransition     = New(http.StatusConflict, "Goal cannot move to this status")
+	ErrWithdrawalExceedsBalance = New(http.StatusConflict, "Withdrawal is more than the goal holds")
+	ErrSecurityExists           = New(http.StatusConflict, "A security with this symbol already exists")
+	ErrSaleExceedsHolding       = New(http.StatusConflict, "Sale is more than the holding at the time")
+
+	// Payload errors (413, 415)
+	ErrRequestTooLarge      = New(http.StatusRequestEntityTooLarge, "Request body is too large")
+	ErrUnsupportedMediaType = New(http.StatusUnsupportedMediaType, "File type is not supported")
+
+	// Unprocessable errors (422)
+	ErrIdempotencyKeyReused = New(http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+	ErrDebtNotRepaid        = New(http.StatusUnprocessableEntity, "The payments never repay the debt")
+
+	// Dependency errors (424)
+	ErrBatchAborted = New(http.StatusFailedDependency, "Not applied because another operation in the batch failed")
+
+	// Precondition errors (428)
+	ErrPreconditionRequired = New(http.StatusPreconditionRequired, "If-Match header is required")
 
 	// Server errors (500)
 	ErrDatabaseError = New(http.StatusInternalServerError, "Database operation failed")
@@ -8,10 +8,11 @@ import (
 
 // ErrorResponse represents the structure of error responses
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
-	Details string `json:"details,omitempty"`
-	Code    int    `json:"code"`
+	Error   string      `json:"error"`
+	Message string      `json:"message,omitempty"`
+	Details string      `json:"details,omitempty"`
+	Code    int         `json:"code"`
+	Current interface{} `json:"current,omitempty"`
 }
 
 // HandleError handles application errors and returns appropriate HTTP responses
@@ -22,6 +23,7 @@ func HandleError(c *gin.Context, err error) {
 			Message: appErr.Message,
 			Details: appErr.Details,
 			Code:    appErr.Code,
+			Current: appErr.Current,
 		}
 		c.JSON(appErr.Code, response)
 		return
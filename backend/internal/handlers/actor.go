@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"strings"
+
+	"finance-management/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader names the household member making a request; it is recorded in the audit log
+const ActorHeader = "X-Actor"
+
+const maxActorLength = 100
+
+// actor returns who is making the request, falling back to services.DefaultActor. The
+// name is cut to maxActorLength characters, not bytes, and invalid UTF-8 is dropped, so
+// it always fits the audit log's column.
+func actor(c *gin.Context) string {
+	name := strings.TrimSpace(strings.ToValidUTF8(c.GetHeader(ActorHeader), ""))
+	if name == "" {
+		return services.DefaultActor
+	}
+	if runes := []rune(name); len(runes) > maxActorLength {
+		name = string(runes[:maxActorLength])
+	}
+	return name
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"finance-management/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestActorIsCutOnCharacterBoundaries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	actorOf := func(header string) string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set(ActorHeader, header)
+		return actor(c)
+	}
+
+	if got := actorOf("  "); got != services.DefaultActor {
+		t.Fatalf("expected a blank header to fall back to %q, got %q", services.DefaultActor, got)
+	}
+	long := actorOf("a" + strings.Repeat("é", maxActorLength))
+	if !utf8.ValidString(long) || utf8.RuneCountInString(long) != maxActorLength || !strings.HasSuffix(long, "é") {
+		t.Fatalf("expected %d whole characters, got %q", maxActorLength, long)
+	}
+	if got := actorOf("Sam\xff"); got != "Sam" {
+		t.Fatalf("expected invalid UTF-8 to be dropped, got %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PreviewSurplusAllocation handles GET /api/finance/goals/allocation?year=YYYY&month=M&amount=
+// Shows how the month's surplus would be split across goals without contributing anything
+func (h *FinanceHandler) PreviewSurplusAllocation(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.AllocateSurplusRequest
+	for param, dst := range map[string]*int{"year": &req.Year, "month": &req.Month} {
+		if v := c.Query(param); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errors.HandleError(c, errors.ErrInvalidInput)
+				return
+			}
+			*dst = n
+		}
+	}
+	if req.Month < 0 || req.Month > 12 {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if v := c.Query("amount"); v != "" {
+		amount, err := strconv.ParseFloat(v, 64)
+		if err != nil || amount <= 0 {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		req.Amount = &amount
+	}
+
+	plan, err := h.financeService.PreviewSurplusAllocation(userID, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// CommitSurplusAllocation handles POST /api/finance/goals/allocation
+// Contributes the month's surplus to goals; the body may be omitted for the current month
+func (h *FinanceHandler) CommitSurplusAllocation(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.AllocateSurplusRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	plan, err := h.financeService.WithActor(actor(c)).CommitSurplusAllocation(userID, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// multipartOverhead allows for the multipart framing around an uploaded file
+const multipartOverhead = 1 << 20
+
+// AttachmentHandler handles attachment endpoints
+type AttachmentHandler struct {
+	attachmentService *services.AttachmentService
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(attachmentService *services.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
+
+// UploadLimit is the largest upload request body accepted, file and framing included
+func (h *AttachmentHandler) UploadLimit() int64 {
+	return h.attachmentService.MaxBytes() + multipartOverhead
+}
+
+// UploadExpenseAttachment handles POST /api/finance/expenses/:id/attachments
+func (h *AttachmentHandler) UploadExpenseAttachment(c *gin.Context) {
+	h.upload(c, models.AttachmentOwnerExpense)
+}
+
+// ListExpenseAttachments handles GET /api/finance/expenses/:id/attachments
+func (h *AttachmentHandler) ListExpenseAttachments(c *gin.Context) {
+	h.list(c, models.AttachmentOwnerExpense)
+}
+
+// UploadIncomeAttachment handles POST /api/finance/incomes/:id/attachments
+func (h *AttachmentHandler) UploadIncomeAttachment(c *gin.Context) {
+	h.upload(c, models.AttachmentOwnerIncome)
+}
+
+// ListIncomeAttachments handles GET /api/finance/incomes/:id/attachments
+func (h *AttachmentHandler) ListIncomeAttachments(c *gin.Context) {
+	h.list(c, models.AttachmentOwnerIncome)
+}
+
+// UploadNoteAttachment handles POST /api/notes/:id/attachments
+func (h *AttachmentHandler) UploadNoteAttachment(c *gin.Context) {
+	h.upload(c, models.AttachmentOwnerNote)
+}
+
+// ListNoteAttachments handles GET /api/notes/:id/attachments
+func (h *AttachmentHandler) ListNoteAttachments(c *gin.Context) {
+	h.list(c, models.AttachmentOwnerNote)
+}
+
+// DownloadAttachment handles GET /api/attachments/:id
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	h.serve(c, false)
+}
+
+// DownloadThumbnail handles GET /api/attachments/:id/thumbnail
+func (h *AttachmentHandler) DownloadThumbnail(c *gin.Context) {
+	h.serve(c, true)
+}
+
+// DeleteAttachment handles DELETE /api/attachments/:id
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.attachmentService.WithActor(actor(c)).DeleteAttachment(c.Request.Context(), userID, id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// upload attaches the multipart "file" field to the record named by the id parameter
+func (h *AttachmentHandler) upload(c *gin.Context, ownerType string) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	ownerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		if bodyReadError(err) == errors.ErrRequestTooLarge {
+			errors.HandleError(c, errors.ErrRequestTooLarge)
+			return
+		}
+		errors.HandleError(c, errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"File is required",
+			`Send the file as multipart/form-data in the "file" field`,
+		))
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.WithActor(actor(c)).Upload(c.Request.Context(), userID, ownerType, ownerID, header.Filename, file)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// list returns the attachments of the record named by the id parameter
+func (h *AttachmentHandler) list(c *gin.Context, ownerType string) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	ownerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	attachments, err := h.attachmentService.ListAttachments(userID, ownerType, ownerID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, attachments)
+}
+
+// serve streams an attachment's file, or its thumbnail, to the client
+func (h *AttachmentHandler) serve(c *gin.Context, thumbnail bool) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	attachment, file, err := h.attachmentService.OpenAttachment(c.Request.Context(), userID, id, thumbnail)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	defer file.Close()
+
+	// Files are downloaded rather than rendered so an upload cannot run as a page of this site
+	disposition, name := "attachment", attachment.FileName
+	contentType, length := attachment.ContentType, attachment.Size
+	if thumbnail {
+		disposition, name = "inline", strings.TrimSuffix(name, path.Ext(name))+"-thumbnail.jpg"
+		contentType, length = "image/jpeg", -1
+	}
+	if header := mime.FormatMediaType(disposition, map[string]string{"filename": name}); header != "" {
+		disposition = header
+	}
+	c.DataFromReader(http.StatusOK, length, contentType, file, map[string]string{
+		"Content-Disposition":    disposition,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
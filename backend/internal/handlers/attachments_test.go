@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"finance-management/internal/dto/response"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+	"finance-management/internal/services"
+	"finance-management/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func newTestAttachmentRouter(t *testing.T, maxBytes int64) (*gin.Engine, uuid.UUID) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	notesRepo := repository.NewInMemoryNotesRepository()
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	attachmentRepo := repository.NewInMemoryAttachmentRepository(financeRepo, notesRepo)
+	h := NewAttachmentHandler(services.NewAttachmentService(attachmentRepo, financeRepo, notesRepo, store, maxBytes, nil))
+
+	note := &models.Note{ID: uuid.New(), UserID: uuid.MustParse("00000000-0000-0000-0000-000000000000"), Title: "Lease"}
+	if err := notesRepo.CreateNote(note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	r := gin.New()
+	r.POST("/api/notes/:id/attachments", MaxBodySize(h.UploadLimit()), h.UploadNoteAttachment)
+	r.GET("/api/attachments/:id", h.DownloadAttachment)
+	r.DELETE("/api/attachments/:id", h.DeleteAttachment)
+	return r, note.ID
+}
+
+func multipartUpload(t *testing.T, url, fileName string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	part.Write(content)
+	w.Close()
+	req := httptest.NewRequest(http.MethodPost, url, &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestAttachmentHandlerUploadAndDownload(t *testing.T) {
+	r, noteID := newTestAttachmentRouter(t, 1<<10)
+	url := "/api/notes/" + noteID.String() + "/attachments"
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, multipartUpload(t, url, "lease terms.txt", []byte("Rent is due on the first")))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("upload: expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created response.AttachmentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("upload: invalid body: %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/attachments/"+created.ID.String(), nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "Rent is due on the first" {
+		t.Fatalf("download: expected the file back, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="lease terms.txt"` {
+		t.Fatalf("download: unexpected Content-Disposition %q", got)
+	}
+	if rec.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Fatal("download: expected X-Content-Type-Options: nosniff")
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/attachments/"+created.ID.String(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("delete: expected 200, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/attachments/"+created.ID.String(), nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("download after delete: expected 404, got %d", rec.Code)
+	}
+}
+
+func TestAttachmentHandlerRejectsBadUploads(t *testing.T) {
+	r, noteID := newTestAttachmentRouter(t, 1<<10)
+	url := "/api/notes/" + noteID.String() + "/attachments"
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, multipartUpload(t, url, "big.txt", bytes.Repeat([]byte("a"), 2<<10)))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for a file over the limit, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, multipartUpload(t, url, "tool.exe", []byte("MZ\x90\x00\x03\x00\x00\x00")))
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415 for an executable, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"file":"x"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without a multipart file, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"finance-management/internal/errors"
+	"finance-management/internal/repository"
+	"finance-management/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuditHandler handles audit log endpoints
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListEntries handles GET /api/audit?entity_type=&entity_id=&actor=&from=&to=&limit=
+// from and to accept RFC 3339 timestamps or YYYY-MM-DD dates
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	filter := repository.AuditFilter{
+		EntityType: c.Query("entity_type"),
+		Actor:      c.Query("actor"),
+	}
+	if v := c.Query("entity_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		filter.EntityID = &id
+	}
+	for param, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := c.Query(param); v != "" {
+			t, err := parseTimeParam(v)
+			if err != nil {
+				errors.HandleError(c, errors.ErrInvalidInput)
+				return
+			}
+			*dst = &t
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.auditService.ListEntries(userID, filter)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+func parseTimeParam(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxJSONBodySize bounds the JSON bodies of the API routes, leaving room for a full
+// batch request
+const maxJSONBodySize = 1 << 20
+
+// MaxBodySize rejects request bodies larger than limit bytes with 413 once
+// they are read past the limit
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// bodyReadError maps a failure to read the request body to an API error
+func bodyReadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if stderrors.As(err, &tooLarge) {
+		return errors.ErrRequestTooLarge
+	}
+	return errors.ErrInvalidInput
+}
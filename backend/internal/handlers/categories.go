@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetCategory handles GET /api/finance/categories/:id
+func (h *FinanceHandler) GetCategory(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	category, err := h.financeService.GetCategory(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// UpdateCategory handles PUT /api/finance/categories/:id
+func (h *FinanceHandler) UpdateCategory(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	category, err := h.financeService.WithActor(actor(c)).UpdateCategory(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory handles DELETE /api/finance/categories/:id. A category still in
+// use needs ?reassign_to=<id> naming the category that takes over its expenses.
+func (h *FinanceHandler) DeleteCategory(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var reassignTo *uuid.UUID
+	if raw := c.Query("reassign_to"); raw != "" {
+		target, err := uuid.Parse(raw)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		reassignTo = &target
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteCategory(userID, id, reassignTo); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// MergeCategories handles POST /api/finance/categories/:id/merge
+func (h *FinanceHandler) MergeCategories(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.MergeCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	result, err := h.financeService.WithActor(actor(c)).MergeCategories(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListDebts handles GET /api/finance/debts
+func (h *FinanceHandler) ListDebts(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	debts, err := h.financeService.ListDebts(userID, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, debts)
+}
+
+// CreateDebt handles POST /api/finance/debts
+func (h *FinanceHandler) CreateDebt(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.CreateDebtRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	debt, err := h.financeService.WithActor(actor(c)).CreateDebt(userID, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, debt)
+}
+
+// PlanDebtPayoff handles GET /api/finance/debts/payoff?extra=
+// Compares the snowball and avalanche strategies with extra paid on top of the minimums each month
+func (h *FinanceHandler) PlanDebtPayoff(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.DebtPayoffRequest
+	if v := c.Query("extra"); v != "" {
+		extra, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		req.ExtraMonthly = extra
+	}
+
+	plan, err := h.financeService.PlanDebtPayoff(userID, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// GetDebt handles GET /api/finance/debts/:id
+func (h *FinanceHandler) GetDebt(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	debt, err := h.financeService.GetDebt(userID, id, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, debt)
+}
+
+// UpdateDebt handles PUT /api/finance/debts/:id
+func (h *FinanceHandler) UpdateDebt(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.UpdateDebtRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	debt, err := h.financeService.WithActor(actor(c)).UpdateDebt(userID, id, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, debt)
+}
+
+// DeleteDebt handles DELETE /api/finance/debts/:id
+func (h *FinanceHandler) DeleteDebt(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteDebt(userID, id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// GetDebtSchedule handles GET /api/finance/debts/:id/schedule?payment=
+// payment replaces the minimum payment made on every due date
+func (h *FinanceHandler) GetDebtSchedule(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var payment *float64
+	if v := c.Query("payment"); v != "" {
+		amount, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		payment = &amount
+	}
+
+	schedule, err := h.financeService.GetDebtSchedule(userID, id, payment, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// ListDebtPayments handles GET /api/finance/debts/:id/payments
+func (h *FinanceHandler) ListDebtPayments(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	payments, err := h.financeService.ListDebtPayments(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, payments)
+}
+
+// RecordDebtPayment handles POST /api/finance/debts/:id/payments
+// The payment is stored as an expense linked to the debt
+func (h *FinanceHandler) RecordDebtPayment(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.CreateDebtPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	payment, err := h.financeService.WithActor(actor(c)).RecordDebtPayment(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, payment)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IfMatchHeader carries the version an update was based on
+const IfMatchHeader = "If-Match"
+
+// setETag exposes a row version as the response's ETag
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion reads the version from the If-Match header. It accepts the
+// ETag as sent ("3"), its weak form (W/"3") or a bare number; "*" skips the
+// version check and is returned as 0.
+func ifMatchVersion(c *gin.Context) (int, error) {
+	value := strings.TrimSpace(c.GetHeader(IfMatchHeader))
+	if value == "" {
+		return 0, errors.ErrPreconditionRequired
+	}
+	if value == "*" {
+		return 0, nil
+	}
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, errors.NewWithDetails(http.StatusBadRequest, "Invalid If-Match header", "Expected an ETag such as \"3\"")
+	}
+	return version, nil
+}
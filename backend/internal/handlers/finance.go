@@ -3,9 +3,11 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
 	"finance-management/internal/errors"
 	"finance-management/internal/services"
 
@@ -31,7 +33,7 @@ func (h *FinanceHandler) CreateIncome(c *gin.Context) {
 		return
 	}
 
-	income, err := h.financeService.CreateIncome(userID, &req)
+	income, err := h.financeService.WithActor(actor(c)).CreateIncome(userID, &req)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -40,10 +42,15 @@ func (h *FinanceHandler) CreateIncome(c *gin.Context) {
 	c.JSON(http.StatusCreated, income)
 }
 
-// ListIncomes GET /api/finance/incomes
+// ListIncomes GET /api/finance/incomes?tag=&tag_match=any|all
 func (h *FinanceHandler) ListIncomes(c *gin.Context) {
 	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
-	items, err := h.financeService.ListIncomes(userID, 100)
+	filter, err := tagFilter(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	items, err := h.financeService.ListIncomes(userID, 100, filter)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -60,7 +67,7 @@ func (h *FinanceHandler) CreateExpense(c *gin.Context) {
 		return
 	}
 
-	expense, err := h.financeService.CreateExpense(userID, &req)
+	expense, err := h.financeService.WithActor(actor(c)).CreateExpense(userID, &req)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -69,10 +76,15 @@ func (h *FinanceHandler) CreateExpense(c *gin.Context) {
 	c.JSON(http.StatusCreated, expense)
 }
 
-// ListExpenses GET /api/finance/expenses
+// ListExpenses GET /api/finance/expenses?tag=&tag_match=any|all
 func (h *FinanceHandler) ListExpenses(c *gin.Context) {
 	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
-	items, err := h.financeService.ListExpenses(userID, 100)
+	filter, err := tagFilter(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	items, err := h.financeService.ListExpenses(userID, 100, filter)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -94,12 +106,39 @@ func (h *FinanceHandler) UpdateIncome(c *gin.Context) {
 		return
 	}
 
-	if err := h.financeService.UpdateIncome(userID, id, &req); err != nil {
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	income, err := h.financeService.WithActor(actor(c)).UpdateIncome(userID, id, version, &req)
+	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	setETag(c, income.Version)
+	c.JSON(http.StatusOK, income)
+}
+
+// GetIncome GET /api/finance/incomes/:id
+func (h *FinanceHandler) GetIncome(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	income, err := h.financeService.GetIncome(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	setETag(c, income.Version)
+	c.JSON(http.StatusOK, income)
 }
 
 // UpdateExpense PUT /api/finance/expenses/:id
@@ -116,12 +155,39 @@ func (h *FinanceHandler) UpdateExpense(c *gin.Context) {
 		return
 	}
 
-	if err := h.financeService.UpdateExpense(userID, id, &req); err != nil {
+	version, err := ifMatchVersion(c)
+	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	expense, err := h.financeService.WithActor(actor(c)).UpdateExpense(userID, id, version, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	setETag(c, expense.Version)
+	c.JSON(http.StatusOK, expense)
+}
+
+// GetExpense GET /api/finance/expenses/:id
+func (h *FinanceHandler) GetExpense(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	expense, err := h.financeService.GetExpense(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	setETag(c, expense.Version)
+	c.JSON(http.StatusOK, expense)
 }
 
 // DeleteIncome DELETE /api/finance/incomes/:id
@@ -132,7 +198,7 @@ func (h *FinanceHandler) DeleteIncome(c *gin.Context) {
 		errors.HandleError(c, errors.ErrInvalidInput)
 		return
 	}
-	if err := h.financeService.DeleteIncome(userID, id); err != nil {
+	if err := h.financeService.WithActor(actor(c)).DeleteIncome(userID, id); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
@@ -147,7 +213,7 @@ func (h *FinanceHandler) DeleteExpense(c *gin.Context) {
 		errors.HandleError(c, errors.ErrInvalidInput)
 		return
 	}
-	if err := h.financeService.DeleteExpense(userID, id); err != nil {
+	if err := h.financeService.WithActor(actor(c)).DeleteExpense(userID, id); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
@@ -163,7 +229,7 @@ func (h *FinanceHandler) CreateGoal(c *gin.Context) {
 		return
 	}
 
-	goal, err := h.financeService.CreateGoal(userID, &req)
+	goal, err := h.financeService.WithActor(actor(c)).CreateGoal(userID, &req)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -181,7 +247,7 @@ func (h *FinanceHandler) CreateGoalContribution(c *gin.Context) {
 		return
 	}
 
-	contribution, err := h.financeService.CreateGoalContribution(userID, &req)
+	contribution, err := h.financeService.WithActor(actor(c)).CreateGoalContribution(userID, &req)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -190,6 +256,68 @@ func (h *FinanceHandler) CreateGoalContribution(c *gin.Context) {
 	c.JSON(http.StatusCreated, contribution)
 }
 
+// BatchIncomes handles POST /api/finance/incomes/batch
+func (h *FinanceHandler) BatchIncomes(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.IncomeBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	result, err := h.financeService.WithActor(actor(c)).BatchIncomes(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(batchStatus(result), result)
+}
+
+// BatchExpenses handles POST /api/finance/expenses/batch
+func (h *FinanceHandler) BatchExpenses(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.ExpenseBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	result, err := h.financeService.WithActor(actor(c)).BatchExpenses(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(batchStatus(result), result)
+}
+
+// BatchGoalContributions handles POST /api/finance/goals/contributions/batch
+func (h *FinanceHandler) BatchGoalContributions(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.GoalContributionBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	result, err := h.financeService.WithActor(actor(c)).BatchGoalContributions(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(batchStatus(result), result)
+}
+
+// batchStatus is 200 when every operation succeeded and 207 Multi-Status otherwise
+func batchStatus(result *response.BatchResponse) int {
+	if result.Failed > 0 {
+		return http.StatusMultiStatus
+	}
+	return http.StatusOK
+}
+
 // GetMonthlySummary handles GET /api/finance/summary?year=YYYY&month=M
 func (h *FinanceHandler) GetMonthlySummary(c *gin.Context) {
 	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
@@ -228,7 +356,7 @@ func (h *FinanceHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	category, err := h.financeService.CreateCategory(userID, &req)
+	category, err := h.financeService.WithActor(actor(c)).CreateCategory(userID, &req)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -250,10 +378,30 @@ func (h *FinanceHandler) ListCategories(c *gin.Context) {
 	c.JSON(http.StatusOK, categories)
 }
 
-// ListGoalsWithProgress handles GET /api/finance/goals
+// ListGoalsWithProgress handles GET /api/finance/goals?pace_window=M&status=
+// pace_window is the number of months the contribution pace is averaged over; status
+// may be repeated or hold a comma-separated list, and paused and archived goals are
+// only listed when asked for
 func (h *FinanceHandler) ListGoalsWithProgress(c *gin.Context) {
 	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
-	goals, err := h.financeService.ListGoalsWithProgress(userID)
+	paceWindow := 0
+	if v := c.Query("pace_window"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		paceWindow = n
+	}
+	var statuses []string
+	for _, v := range c.QueryArray("status") {
+		for _, status := range strings.Split(v, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				statuses = append(statuses, status)
+			}
+		}
+	}
+	goals, err := h.financeService.ListGoalsWithProgress(userID, paceWindow, statuses, time.Now().UTC())
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -275,12 +423,39 @@ func (h *FinanceHandler) UpdateGoal(c *gin.Context) {
 		return
 	}
 
-	if err := h.financeService.UpdateGoal(userID, id, &req); err != nil {
+	version, err := ifMatchVersion(c)
+	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	goal, err := h.financeService.WithActor(actor(c)).UpdateGoal(userID, id, version, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	setETag(c, goal.Version)
+	c.JSON(http.StatusOK, goal)
+}
+
+// GetGoal GET /api/finance/goals/:id
+func (h *FinanceHandler) GetGoal(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	goal, err := h.financeService.GetGoal(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	setETag(c, goal.Version)
+	c.JSON(http.StatusOK, goal)
 }
 
 // DeleteGoal DELETE /api/finance/goals/:id
@@ -291,22 +466,56 @@ func (h *FinanceHandler) DeleteGoal(c *gin.Context) {
 		errors.HandleError(c, errors.ErrInvalidInput)
 		return
 	}
-	if err := h.financeService.DeleteGoal(userID, id); err != nil {
+	if err := h.financeService.WithActor(actor(c)).DeleteGoal(userID, id); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
 
-// ListGoalCategories handles GET /api/finance/goals/categories
-func (h *FinanceHandler) ListGoalCategories(c *gin.Context) {
-	categories, err := h.financeService.ListGoalCategories()
+// RestoreIncome handles POST /api/finance/incomes/:id/restore
+func (h *FinanceHandler) RestoreIncome(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).RestoreIncome(userID, id); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
 
-	c.JSON(http.StatusOK, categories)
+// RestoreExpense handles POST /api/finance/expenses/:id/restore
+func (h *FinanceHandler) RestoreExpense(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).RestoreExpense(userID, id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// RestoreGoal handles POST /api/finance/goals/:id/restore
+func (h *FinanceHandler) RestoreGoal(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).RestoreGoal(userID, id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
 
 // ListMainGoalsWithSubgoals handles GET /api/finance/goals/hierarchical
@@ -331,7 +540,7 @@ func (h *FinanceHandler) CreateGoalExpense(c *gin.Context) {
 		return
 	}
 
-	goalExpense, err := h.financeService.CreateGoalExpense(userID, &req)
+	goalExpense, err := h.financeService.WithActor(actor(c)).CreateGoalExpense(userID, &req)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
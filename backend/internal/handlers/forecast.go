@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetForecast handles GET /api/finance/forecast?months=&history=&exclude=YYYY-MM&exclude_outliers=&starting_balance=
+// exclude may be repeated or hold a comma-separated list
+func (h *FinanceHandler) GetForecast(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.ForecastRequest
+	for param, dst := range map[string]*int{"months": &req.Months, "history": &req.History} {
+		if v := c.Query(param); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errors.HandleError(c, errors.ErrInvalidInput)
+				return
+			}
+			*dst = n
+		}
+	}
+	for _, v := range c.QueryArray("exclude") {
+		for _, month := range strings.Split(v, ",") {
+			if month = strings.TrimSpace(month); month != "" {
+				req.Exclude = append(req.Exclude, month)
+			}
+		}
+	}
+	if v := c.Query("exclude_outliers"); v != "" {
+		exclude, err := strconv.ParseBool(v)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		req.ExcludeOutliers = exclude
+	}
+	if v := c.Query("starting_balance"); v != "" {
+		balance, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		req.StartingBalance = &balance
+	}
+
+	forecast, err := h.financeService.Forecast(userID, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, forecast)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListFundingRules handles GET /api/finance/funding-rules
+// ?goal_id= limits the list to the rules of one goal
+func (h *FinanceHandler) ListFundingRules(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var goalID *uuid.UUID
+	if raw := c.Query("goal_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		goalID = &id
+	}
+
+	rules, err := h.financeService.ListFundingRules(userID, goalID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// CreateFundingRule handles POST /api/finance/funding-rules
+func (h *FinanceHandler) CreateFundingRule(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.CreateFundingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	rule, err := h.financeService.WithActor(actor(c)).CreateFundingRule(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// RunFundingRules handles POST /api/finance/funding-rules/run
+// Generates the fixed monthly contributions that are due without waiting for the scheduler
+func (h *FinanceHandler) RunFundingRules(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	contributions, err := h.financeService.WithActor(actor(c)).RunScheduledFunding(&userID, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.RunFundingRulesResponse{Contributions: contributions})
+}
+
+// GetFundingRule handles GET /api/finance/funding-rules/:id
+func (h *FinanceHandler) GetFundingRule(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	rule, err := h.financeService.GetFundingRule(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateFundingRule handles PUT /api/finance/funding-rules/:id
+func (h *FinanceHandler) UpdateFundingRule(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.UpdateFundingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	rule, err := h.financeService.WithActor(actor(c)).UpdateFundingRule(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteFundingRule handles DELETE /api/finance/funding-rules/:id
+func (h *FinanceHandler) DeleteFundingRule(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteFundingRule(userID, id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListGoalCategories handles GET /api/finance/goals/categories. Hidden categories
+// are listed with ?include_hidden=true.
+func (h *FinanceHandler) ListGoalCategories(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	includeHidden := false
+	if v := c.Query("include_hidden"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		includeHidden = include
+	}
+	categories, err := h.financeService.ListGoalCategories(userID, includeHidden)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// CreateGoalCategory handles POST /api/finance/goals/categories
+func (h *FinanceHandler) CreateGoalCategory(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.CreateGoalCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	category, err := h.financeService.WithActor(actor(c)).CreateGoalCategory(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// GetGoalCategory handles GET /api/finance/goals/categories/:id
+func (h *FinanceHandler) GetGoalCategory(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	category, err := h.financeService.GetGoalCategory(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// UpdateGoalCategory handles PUT /api/finance/goals/categories/:id
+func (h *FinanceHandler) UpdateGoalCategory(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.UpdateGoalCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	category, err := h.financeService.WithActor(actor(c)).UpdateGoalCategory(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListGoalTemplates handles GET /api/finance/goals/templates
+func (h *FinanceHandler) ListGoalTemplates(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	templates, err := h.financeService.ListGoalTemplates(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// CreateGoalTemplate handles POST /api/finance/goals/templates. The body carries the
+// template's goal, or a goal_id to save an existing goal and its sub-goals.
+func (h *FinanceHandler) CreateGoalTemplate(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.CreateGoalTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	template, err := h.financeService.WithActor(actor(c)).CreateGoalTemplate(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// GetGoalTemplate handles GET /api/finance/goals/templates/:id
+func (h *FinanceHandler) GetGoalTemplate(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	template, err := h.financeService.GetGoalTemplate(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateGoalTemplate handles PUT /api/finance/goals/templates/:id
+func (h *FinanceHandler) UpdateGoalTemplate(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.UpdateGoalTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	template, err := h.financeService.WithActor(actor(c)).UpdateGoalTemplate(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteGoalTemplate handles DELETE /api/finance/goals/templates/:id
+func (h *FinanceHandler) DeleteGoalTemplate(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteGoalTemplate(userID, id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// InstantiateGoalTemplate handles POST /api/finance/goals/templates/:id/instantiate
+// Creates the template's goals; the body may be omitted to use the user's own averages
+func (h *FinanceHandler) InstantiateGoalTemplate(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.InstantiateGoalTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	result, err := h.financeService.WithActor(actor(c)).InstantiateGoalTemplate(userID, id, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"finance-management/internal/config"
+	"finance-management/internal/migrate"
 
 	"github.com/gin-gonic/gin"
 )
@@ -52,8 +53,24 @@ func (h *HealthHandler) DatabaseHealth(c *gin.Context) {
 		return
 	}
 
+	schemaVersion, err := migrate.CurrentVersion(c.Request.Context(), sqlDB)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "unhealthy",
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	expectedVersion := ""
+	if migrations, err := migrate.Embedded(); err == nil {
+		expectedVersion = migrate.LatestVersion(migrations)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"status":   "healthy",
-		"database": "connected",
+		"status":                  "healthy",
+		"database":                "connected",
+		"schema_version":          schemaVersion,
+		"expected_schema_version": expectedVersion,
 	})
 }
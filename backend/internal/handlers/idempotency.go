@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"finance-management/internal/errors"
+	"finance-management/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyHeader lets clients retry a POST without applying it twice
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed from an earlier request
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const maxIdempotencyKeyLength = 255
+
+// bodyRecorder captures the response body while it is written to the client
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the stored response when a POST is retried with the same
+// Idempotency-Key, and rejects a key reused for a different request with 422.
+// Requests without the header are handled as usual.
+func Idempotency(idempotencyService *services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			errors.HandleError(c, errors.ErrInvalidIdempotencyKey)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			errors.HandleError(c, bodyReadError(err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+		replay, err := idempotencyService.Begin(services.IdempotentRequest{
+			UserID: userID,
+			Key:    key,
+			Method: c.Request.Method,
+			Path:   c.Request.URL.Path,
+			Body:   body,
+		})
+		if err != nil {
+			errors.HandleError(c, err)
+			c.Abort()
+			return
+		}
+		if replay != nil {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(replay.StatusCode, replay.ContentType, replay.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// Release the key if the handler panics so the client can retry
+		completed := false
+		defer func() {
+			if !completed {
+				idempotencyService.Release(userID, key)
+			}
+		}()
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		idempotencyService.Complete(userID, key, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		completed = true
+	}
+}
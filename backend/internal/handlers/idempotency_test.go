@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"finance-management/internal/repository"
+	"finance-management/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func newTestIdempotentRouter(financeRepo repository.FinanceRepositoryInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	idempotency := services.NewIdempotencyService(repository.NewInMemoryIdempotencyRepository(), time.Hour)
+	h := NewFinanceHandler(services.NewFinanceService(financeRepo, nil, nil))
+	r := gin.New()
+	api := r.Group("/api", MaxBodySize(maxJSONBodySize), Idempotency(idempotency))
+	api.POST("/finance/expenses", h.CreateExpense)
+	api.POST("/finance/incomes", h.CreateIncome)
+	return r
+}
+
+func TestIdempotencyReplaysRetriedPost(t *testing.T) {
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	r := newTestIdempotentRouter(financeRepo)
+	body := `{"category":"food","amount":12.5,"spent_at":"2024-05-01T00:00:00Z"}`
+
+	post := func(path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := post("/api/finance/expenses", "retry-1", body)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first: expected 201, got %d: %s", first.Code, first.Body.String())
+	}
+	retry := post("/api/finance/expenses", "retry-1", body)
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("retry: expected the original 201 response, got %d: %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("retry: expected %s header", IdempotentReplayedHeader)
+	}
+
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	expenses, _ := financeRepo.ListExpenses(userID, 0)
+	if len(expenses) != 1 {
+		t.Fatalf("expected a single expense after the retry, got %d", len(expenses))
+	}
+
+	if rec := post("/api/finance/expenses", "retry-1", `{"category":"food","amount":99,"spent_at":"2024-05-01T00:00:00Z"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("different body under the same key: expected 422, got %d", rec.Code)
+	}
+	if rec := post("/api/finance/incomes", "retry-1", body); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("same key on another endpoint: expected 422, got %d", rec.Code)
+	}
+
+	// Without a key every request is applied
+	post("/api/finance/expenses", "", body)
+	expenses, _ = financeRepo.ListExpenses(userID, 0)
+	if len(expenses) != 2 {
+		t.Fatalf("expected a second expense without a key, got %d", len(expenses))
+	}
+}
+
+func TestIdempotencyStoresClientErrors(t *testing.T) {
+	r := newTestIdempotentRouter(repository.NewInMemoryFinanceRepository())
+	invalid := `{"category":"food","amount":-1,"spent_at":"2024-05-01T00:00:00Z"}`
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/finance/expenses", strings.NewReader(invalid))
+		req.Header.Set(IdempotencyKeyHeader, "bad-amount")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("attempt %d: expected 400, got %d", i+1, rec.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/finance/expenses", strings.NewReader(invalid))
+	req.Header.Set(IdempotencyKeyHeader, strings.Repeat("k", 256))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("oversized key: expected 400, got %d", rec.Code)
+	}
+
+	oversized := `{"category":"food","amount":1,"spent_at":"2024-05-01T00:00:00Z","description":"` + strings.Repeat("x", maxJSONBodySize) + `"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/finance/expenses", strings.NewReader(oversized))
+	req.Header.Set(IdempotencyKeyHeader, "too-large")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized body: expected 413, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetPortfolio handles GET /api/finance/investments?date=
+// Values the holdings at the end of the day given, today by default
+func (h *FinanceHandler) GetPortfolio(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.PortfolioRequest
+	if v := c.Query("date"); v != "" {
+		date, err := parseTimeParam(v)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		req.Date = &date
+	}
+
+	portfolio, err := h.financeService.GetPortfolio(userID, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, portfolio)
+}
+
+// ListSecurities handles GET /api/finance/investments/securities
+func (h *FinanceHandler) ListSecurities(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	securities, err := h.financeService.ListSecurities(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, securities)
+}
+
+// CreateSecurity handles POST /api/finance/investments/securities
+func (h *FinanceHandler) CreateSecurity(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.CreateSecurityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	security, err := h.financeService.WithActor(actor(c)).CreateSecurity(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, security)
+}
+
+// GetSecurity handles GET /api/finance/investments/securities/:id
+func (h *FinanceHandler) GetSecurity(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	security, err := h.financeService.GetSecurity(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, security)
+}
+
+// UpdateSecurity handles PUT /api/finance/investments/securities/:id
+func (h *FinanceHandler) UpdateSecurity(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.UpdateSecurityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	security, err := h.financeService.WithActor(actor(c)).UpdateSecurity(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, security)
+}
+
+// DeleteSecurity handles DELETE /api/finance/investments/securities/:id
+// Its lots, sales and prices go with it; its dividends are kept as plain incomes
+func (h *FinanceHandler) DeleteSecurity(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteSecurity(userID, id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// ListLots handles GET /api/finance/investments/securities/:id/lots
+func (h *FinanceHandler) ListLots(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	lots, err := h.financeService.ListLots(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, lots)
+}
+
+// CreateLot handles POST /api/finance/investments/securities/:id/lots
+func (h *FinanceHandler) CreateLot(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.CreateLotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	lot, err := h.financeService.WithActor(actor(c)).CreateLot(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, lot)
+}
+
+// DeleteLot handles DELETE /api/finance/investments/securities/:id/lots/:lotId
+// A lot a later sale needs cannot be deleted
+func (h *FinanceHandler) DeleteLot(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	lotID, err := uuid.Parse(c.Param("lotId"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteLot(userID, id, lotID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// ListSales handles GET /api/finance/investments/securities/:id/sales
+func (h *FinanceHandler) ListSales(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	sales, err := h.financeService.ListSales(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sales)
+}
+
+// CreateSale handles POST /api/finance/investments/securities/:id/sales
+func (h *FinanceHandler) CreateSale(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.CreateSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	sale, err := h.financeService.WithActor(actor(c)).CreateSale(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, sale)
+}
+
+// DeleteSale handles DELETE /api/finance/investments/securities/:id/sales/:saleId
+func (h *FinanceHandler) DeleteSale(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	saleID, err := uuid.Parse(c.Param("saleId"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteSale(userID, id, saleID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// ListDividends handles GET /api/finance/investments/securities/:id/dividends
+func (h *FinanceHandler) ListDividends(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	dividends, err := h.financeService.ListDividends(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dividends)
+}
+
+// RecordDividend handles POST /api/finance/investments/securities/:id/dividends
+// The dividend is stored as an income linked to the security
+func (h *FinanceHandler) RecordDividend(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.CreateDividendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	income, err := h.financeService.WithActor(actor(c)).RecordDividend(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, income)
+}
+
+// ListSecurityPrices handles GET /api/finance/investments/securities/:id/prices
+func (h *FinanceHandler) ListSecurityPrices(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	prices, err := h.financeService.ListSecurityPrices(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, prices)
+}
+
+// ImportSecurityPrices handles POST /api/finance/investments/prices/import?symbol=
+// Reads closing prices from the CSV sent in the multipart "file" field; symbol names
+// the security when the file has no symbol column
+func (h *FinanceHandler) ImportSecurityPrices(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		if bodyReadError(err) == errors.ErrRequestTooLarge {
+			errors.HandleError(c, errors.ErrRequestTooLarge)
+			return
+		}
+		errors.HandleError(c, errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"File is required",
+			`Send the CSV as multipart/form-data in the "file" field`,
+		))
+		return
+	}
+	defer file.Close()
+
+	imported, err := h.financeService.WithActor(actor(c)).ImportSecurityPrices(userID, file, c.Query("symbol"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, imported)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Jobs is the background work of the services SetupRoutes builds: purging the trash and
+// expired idempotency keys, scheduled goal funding and month-end net worth snapshots.
+// Building the routes runs none of it; the server starts the jobs with Start.
+type Jobs struct {
+	jobs []func(ctx context.Context, interval time.Duration)
+	wg   sync.WaitGroup
+}
+
+// Start runs every job immediately and then each interval until ctx is cancelled
+func (j *Jobs) Start(ctx context.Context, interval time.Duration) {
+	for _, job := range j.jobs {
+		j.wg.Add(1)
+		go func() {
+			defer j.wg.Done()
+			job(ctx, interval)
+		}()
+	}
+}
+
+// Wait blocks until every started job has stopped
+func (j *Jobs) Wait() {
+	j.wg.Wait()
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ChangeGoalStatus handles POST /api/finance/goals/:id/status
+// Moves the goal through its lifecycle; If-Match guards against concurrent edits
+func (h *FinanceHandler) ChangeGoalStatus(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.ChangeGoalStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	goal, err := h.financeService.WithActor(actor(c)).ChangeGoalStatus(userID, id, version, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	setETag(c, goal.Version)
+	c.JSON(http.StatusOK, goal)
+}
+
+// ListGoalStatusHistory handles GET /api/finance/goals/:id/status-history
+func (h *FinanceHandler) ListGoalStatusHistory(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	history, err := h.financeService.ListGoalStatusHistory(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// CreateGoalWithdrawal handles POST /api/finance/goals/:id/withdrawals
+func (h *FinanceHandler) CreateGoalWithdrawal(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.CreateGoalWithdrawalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	withdrawal, err := h.financeService.WithActor(actor(c)).WithdrawFromGoal(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, withdrawal)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListMerchants handles GET /api/finance/merchants
+func (h *FinanceHandler) ListMerchants(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	merchants, err := h.financeService.ListMerchants(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, merchants)
+}
+
+// CreateMerchant handles POST /api/finance/merchants
+func (h *FinanceHandler) CreateMerchant(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.CreateMerchantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	merchant, err := h.financeService.WithActor(actor(c)).CreateMerchant(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, merchant)
+}
+
+// GetMerchant handles GET /api/finance/merchants/:id
+func (h *FinanceHandler) GetMerchant(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	merchant, err := h.financeService.GetMerchant(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, merchant)
+}
+
+// UpdateMerchant handles PUT /api/finance/merchants/:id
+func (h *FinanceHandler) UpdateMerchant(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.UpdateMerchantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	merchant, err := h.financeService.WithActor(actor(c)).UpdateMerchant(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, merchant)
+}
+
+// DeleteMerchant handles DELETE /api/finance/merchants/:id
+func (h *FinanceHandler) DeleteMerchant(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteMerchant(userID, id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// AddMerchantPattern handles POST /api/finance/merchants/:id/patterns
+func (h *FinanceHandler) AddMerchantPattern(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.MerchantPatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	merchant, err := h.financeService.WithActor(actor(c)).AddMerchantPattern(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, merchant)
+}
+
+// DeleteMerchantPattern handles DELETE /api/finance/merchants/:id/patterns/:patternId
+func (h *FinanceHandler) DeleteMerchantPattern(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	patternID, err := uuid.Parse(c.Param("patternId"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteMerchantPattern(userID, id, patternID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// MergeMerchants handles POST /api/finance/merchants/:id/merge
+func (h *FinanceHandler) MergeMerchants(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.MergeMerchantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	result, err := h.financeService.WithActor(actor(c)).MergeMerchants(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ResolveMerchants handles POST /api/finance/merchants/resolve
+func (h *FinanceHandler) ResolveMerchants(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.ResolveMerchantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	result, err := h.financeService.WithActor(actor(c)).ResolveMerchants(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetNetWorth handles GET /api/finance/net-worth
+func (h *FinanceHandler) GetNetWorth(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	worth, err := h.financeService.GetNetWorth(userID, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, worth)
+}
+
+// GetNetWorthHistory handles GET /api/finance/net-worth/history?months=
+// Returns the month-end snapshots of the last months (12 by default) and today's net worth
+func (h *FinanceHandler) GetNetWorthHistory(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.NetWorthHistoryRequest
+	if v := c.Query("months"); v != "" {
+		months, err := strconv.Atoi(v)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidInput)
+			return
+		}
+		req.Months = months
+	}
+
+	history, err := h.financeService.GetNetWorthHistory(userID, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// RunNetWorthSnapshots handles POST /api/finance/net-worth/snapshots/run
+// Stores last month's snapshot without waiting for the scheduler
+func (h *FinanceHandler) RunNetWorthSnapshots(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	snapshots, err := h.financeService.RunNetWorthSnapshots(&userID, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.RunNetWorthSnapshotsResponse{Snapshots: snapshots})
+}
+
+// ListNetWorthItems handles GET /api/finance/net-worth/items
+func (h *FinanceHandler) ListNetWorthItems(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	items, err := h.financeService.ListNetWorthItems(userID, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// CreateNetWorthItem handles POST /api/finance/net-worth/items
+func (h *FinanceHandler) CreateNetWorthItem(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.CreateNetWorthItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	item, err := h.financeService.WithActor(actor(c)).CreateNetWorthItem(userID, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// GetNetWorthItem handles GET /api/finance/net-worth/items/:id
+func (h *FinanceHandler) GetNetWorthItem(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	item, err := h.financeService.GetNetWorthItem(userID, id, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// UpdateNetWorthItem handles PUT /api/finance/net-worth/items/:id
+func (h *FinanceHandler) UpdateNetWorthItem(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.UpdateNetWorthItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	item, err := h.financeService.WithActor(actor(c)).UpdateNetWorthItem(userID, id, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// DeleteNetWorthItem handles DELETE /api/finance/net-worth/items/:id
+func (h *FinanceHandler) DeleteNetWorthItem(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteNetWorthItem(userID, id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// ListValuations handles GET /api/finance/net-worth/items/:id/valuations
+func (h *FinanceHandler) ListValuations(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	valuations, err := h.financeService.ListValuations(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, valuations)
+}
+
+// CreateValuation handles POST /api/finance/net-worth/items/:id/valuations
+// A valuation on a day the item was already valued replaces the earlier one
+func (h *FinanceHandler) CreateValuation(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.CreateValuationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	valuation, err := h.financeService.WithActor(actor(c)).CreateValuation(userID, id, &req, time.Now().UTC())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, valuation)
+}
+
+// DeleteValuation handles DELETE /api/finance/net-worth/items/:id/valuations/:valuationId
+func (h *FinanceHandler) DeleteValuation(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	valuationID, err := uuid.Parse(c.Param("valuationId"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.financeService.WithActor(actor(c)).DeleteValuation(userID, id, valuationID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
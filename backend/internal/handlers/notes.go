@@ -53,6 +53,7 @@ func (h *NotesHandler) GetNote(c *gin.Context) {
 		return
 	}
 
+	setETag(c, note.Version)
 	c.JSON(http.StatusOK, note)
 }
 
@@ -66,7 +67,7 @@ func (h *NotesHandler) CreateNote(c *gin.Context) {
 		return
 	}
 
-	note, err := h.notesService.CreateNote(userID, &req)
+	note, err := h.notesService.WithActor(actor(c)).CreateNote(userID, &req)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -92,12 +93,19 @@ func (h *NotesHandler) UpdateNote(c *gin.Context) {
 		return
 	}
 
-	note, err := h.notesService.UpdateNote(userID, noteID, &req)
+	version, err := ifMatchVersion(c)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
+	note, err := h.notesService.WithActor(actor(c)).UpdateNote(userID, noteID, version, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	setETag(c, note.Version)
 	c.JSON(http.StatusOK, note)
 }
 
@@ -112,7 +120,7 @@ func (h *NotesHandler) DeleteNote(c *gin.Context) {
 		return
 	}
 
-	if err := h.notesService.DeleteNote(userID, noteID); err != nil {
+	if err := h.notesService.WithActor(actor(c)).DeleteNote(userID, noteID); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
@@ -121,3 +129,24 @@ func (h *NotesHandler) DeleteNote(c *gin.Context) {
 		"message": "Note deleted successfully",
 	})
 }
+
+// RestoreNote takes a note out of the trash
+func (h *NotesHandler) RestoreNote(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000") // Default system user
+
+	noteIDStr := c.Param("id")
+	noteID, err := uuid.Parse(noteIDStr)
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	if err := h.notesService.WithActor(actor(c)).RestoreNote(userID, noteID); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Note restored successfully",
+	})
+}
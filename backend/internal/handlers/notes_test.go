@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"finance-management/internal/dto/response"
+	"finance-management/internal/repository"
+	"finance-management/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestNotesRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	auditRepo := repository.NewInMemoryAuditRepository()
+	h := NewNotesHandler(services.NewNotesService(repository.NewInMemoryNotesRepository(), auditRepo))
+	audit := NewAuditHandler(services.NewAuditService(auditRepo))
+	r := gin.New()
+	r.GET("/api/notes", h.GetNotes)
+	r.GET("/api/notes/:id", h.GetNote)
+	r.POST("/api/notes", h.CreateNote)
+	r.PUT("/api/notes/:id", h.UpdateNote)
+	r.DELETE("/api/notes/:id", h.DeleteNote)
+	r.GET("/api/audit", audit.ListEntries)
+	return r
+}
+
+func TestNotesHandlerLifecycle(t *testing.T) {
+	r := newTestNotesRouter()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/notes", strings.NewReader(`{"title":"Groceries","tags":["home"]}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created response.NoteResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("create: invalid body: %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/notes/"+created.ID.String(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get: expected 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/notes/"+created.ID.String(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("delete: expected 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/notes/"+created.ID.String(), nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("get after delete: expected 404, got %d", rec.Code)
+	}
+}
+
+func TestNotesHandlerRejectsInvalidInput(t *testing.T) {
+	r := newTestNotesRouter()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/notes", strings.NewReader(`{"content":"no title"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("create without title: expected 400, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/notes/not-a-uuid", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("get with bad id: expected 400, got %d", rec.Code)
+	}
+}
+
+func TestNotesHandlerOptimisticConcurrency(t *testing.T) {
+	r := newTestNotesRouter()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/notes", strings.NewReader(`{"title":"Shopping"}`)))
+	var created response.NoteResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("create: invalid body: %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/notes/"+created.ID.String(), nil))
+	etag := rec.Header().Get("ETag")
+	if etag != `"1"` {
+		t.Fatalf("get: expected ETag \"1\", got %q", etag)
+	}
+
+	put := func(ifMatch, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/notes/"+created.ID.String(), strings.NewReader(body))
+		if ifMatch != "" {
+			req.Header.Set(IfMatchHeader, ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := put("", `{"title":"No precondition"}`); rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("update without If-Match: expected 428, got %d", rec.Code)
+	}
+	if rec := put("soon", `{"title":"Bad precondition"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("update with malformed If-Match: expected 400, got %d", rec.Code)
+	}
+
+	rec = put(etag, `{"title":"Shopping list"}`)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"2"` {
+		t.Fatalf("update: expected 200 with ETag \"2\", got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	// A second tab still holding version 1 loses and receives the current note
+	rec = put(etag, `{"title":"Stale edit"}`)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("stale update: expected 412, got %d", rec.Code)
+	}
+	var conflict struct {
+		Current response.NoteResponse `json:"current"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &conflict); err != nil {
+		t.Fatalf("stale update: invalid body: %v", err)
+	}
+	if conflict.Current.Title != "Shopping list" || conflict.Current.Version != 2 {
+		t.Fatalf("stale update: expected the current note, got %+v", conflict.Current)
+	}
+}
+
+func TestNotesHandlerRecordsAuditEntries(t *testing.T) {
+	r := newTestNotesRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/notes", strings.NewReader(`{"title":"Draft"}`))
+	req.Header.Set(ActorHeader, "sam")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	var created response.NoteResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("create: invalid body: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/notes/"+created.ID.String(), strings.NewReader(`{"title":"Final","category":"`+created.Category+`"}`))
+	req.Header.Set(ActorHeader, "alex")
+	req.Header.Set(IfMatchHeader, "*")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/audit?entity_type=note&entity_id="+created.ID.String(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("audit: expected 200, got %d", rec.Code)
+	}
+	var entries []response.AuditEntryResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("audit: invalid body: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("audit: expected create and update entries, got %d", len(entries))
+	}
+	update := entries[0]
+	if update.Action != services.AuditUpdate || update.Actor != "alex" || entries[1].Actor != "sam" {
+		t.Fatalf("audit: unexpected entries %+v", entries)
+	}
+	var changes map[string]map[string]interface{}
+	if err := json.Unmarshal(update.Changes, &changes); err != nil {
+		t.Fatalf("audit: invalid changes: %v", err)
+	}
+	if len(changes) != 1 || changes["title"]["before"] != "Draft" || changes["title"]["after"] != "Final" {
+		t.Fatalf("audit: expected only the title change, got %s", update.Changes)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/audit?from=yesterday", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("audit with bad from: expected 400, got %d", rec.Code)
+	}
+}
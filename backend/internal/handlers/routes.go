@@ -1,6 +1,10 @@
 package handlers
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"finance-management/internal/config"
 	"finance-management/internal/repository"
 	"finance-management/internal/services"
@@ -8,23 +12,56 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// SetupRoutes configures all the routes for the application
-func SetupRoutes(r *gin.Engine) {
+// SetupRoutes configures all the routes for the application and returns the
+// background jobs of the services behind them, which it does not start
+func SetupRoutes(r *gin.Engine) *Jobs {
 	// Initialize database connection
 	db := config.GetDB()
 
 	// Initialize repositories
 	notesRepo := repository.NewNotesRepository(db)
 	financeRepo := repository.NewFinanceRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	ruleRepo := repository.NewRuleRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+
+	// Attachment files live outside the database
+	attachmentStorage, err := config.NewAttachmentStorage()
+	if err != nil {
+		log.Fatalf("Failed to initialize attachment storage: %v", err)
+	}
 
 	// Initialize services
-	notesService := services.NewNotesService(notesRepo)
-	financeService := services.NewFinanceService(financeRepo)
+	notesService := services.NewNotesService(notesRepo, auditRepo)
+	suggestionService := services.NewSuggestionService(financeRepo)
+	financeService := services.NewFinanceService(financeRepo, ruleRepo, auditRepo).WithSuggestions(suggestionService)
+	auditService := services.NewAuditService(auditRepo)
+	rulesService := services.NewRulesService(ruleRepo, financeRepo, auditRepo)
+	attachmentService := services.NewAttachmentService(attachmentRepo, financeRepo, notesRepo, attachmentStorage, config.GetAttachmentMaxBytes(), auditRepo)
+	trashService := services.NewTrashService(financeRepo, notesRepo, config.GetTrashRetention()).WithAttachments(attachmentService)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, config.GetIdempotencyKeyRetention())
+
+	// Background jobs, started by the server rather than here
+	jobs := &Jobs{jobs: []func(ctx context.Context, interval time.Duration){
+		// Permanently remove records once they have been in the trash past the retention window
+		trashService.RunPurger,
+		idempotencyService.RunPurger,
+		// Generate the contributions of fixed monthly funding rules as they fall due
+		financeService.RunFundingScheduler,
+		// Store each user's net worth at the end of every month
+		financeService.RunNetWorthScheduler,
+	}}
 
 	// Initialize handlers
 	healthHandler := NewHealthHandler()
 	notesHandler := NewNotesHandler(notesService)
 	financeHandler := NewFinanceHandler(financeService)
+	trashHandler := NewTrashHandler(trashService)
+	auditHandler := NewAuditHandler(auditService)
+	rulesHandler := NewRulesHandler(rulesService)
+	suggestionsHandler := NewSuggestionsHandler(suggestionService)
+	attachmentHandler := NewAttachmentHandler(attachmentService)
 
 	// Health check routes
 	api := r.Group("/api")
@@ -33,8 +70,9 @@ func SetupRoutes(r *gin.Engine) {
 		api.GET("/health/db", healthHandler.DatabaseHealth)
 	}
 
-	// Notes routes
-	api = r.Group("/api")
+	// API routes; the body limit applies before the idempotency middleware buffers
+	// the body, and POSTs sent with an Idempotency-Key are replayed on retry
+	api = r.Group("/api", MaxBodySize(maxJSONBodySize), Idempotency(idempotencyService))
 	{
 		// Notes CRUD operations
 		api.GET("/notes", notesHandler.GetNotes)
@@ -42,33 +80,165 @@ func SetupRoutes(r *gin.Engine) {
 		api.POST("/notes", notesHandler.CreateNote)
 		api.PUT("/notes/:id", notesHandler.UpdateNote)
 		api.DELETE("/notes/:id", notesHandler.DeleteNote)
+		api.POST("/notes/:id/restore", notesHandler.RestoreNote)
 
 		// Finance MVP endpoints
 		api.GET("/finance/incomes", financeHandler.ListIncomes)
 		api.POST("/finance/incomes", financeHandler.CreateIncome)
+		api.POST("/finance/incomes/batch", financeHandler.BatchIncomes)
+		api.GET("/finance/incomes/:id", financeHandler.GetIncome)
 		api.PUT("/finance/incomes/:id", financeHandler.UpdateIncome)
 		api.DELETE("/finance/incomes/:id", financeHandler.DeleteIncome)
+		api.POST("/finance/incomes/:id/restore", financeHandler.RestoreIncome)
 		api.GET("/finance/expenses", financeHandler.ListExpenses)
 		api.POST("/finance/expenses", financeHandler.CreateExpense)
+		api.POST("/finance/expenses/batch", financeHandler.BatchExpenses)
+		api.GET("/finance/expenses/suggest", suggestionsHandler.SuggestCategory)
+		api.GET("/finance/expenses/:id", financeHandler.GetExpense)
 		api.PUT("/finance/expenses/:id", financeHandler.UpdateExpense)
 		api.DELETE("/finance/expenses/:id", financeHandler.DeleteExpense)
+		api.POST("/finance/expenses/:id/restore", financeHandler.RestoreExpense)
 		api.POST("/finance/goals", financeHandler.CreateGoal)
+		api.GET("/finance/goals/:id", financeHandler.GetGoal)
 		api.PUT("/finance/goals/:id", financeHandler.UpdateGoal)
 		api.DELETE("/finance/goals/:id", financeHandler.DeleteGoal)
+		api.POST("/finance/goals/:id/restore", financeHandler.RestoreGoal)
 		api.POST("/finance/goals/contributions", financeHandler.CreateGoalContribution)
+		api.POST("/finance/goals/contributions/batch", financeHandler.BatchGoalContributions)
 		api.GET("/finance/summary", financeHandler.GetMonthlySummary)
+		api.GET("/finance/forecast", financeHandler.GetForecast)
 		api.GET("/finance/categories", financeHandler.ListCategories)
 		api.POST("/finance/categories", financeHandler.CreateCategory)
+		api.GET("/finance/categories/:id", financeHandler.GetCategory)
+		api.PUT("/finance/categories/:id", financeHandler.UpdateCategory)
+		api.DELETE("/finance/categories/:id", financeHandler.DeleteCategory)
+		api.POST("/finance/categories/:id/merge", financeHandler.MergeCategories)
 		api.GET("/finance/goals", financeHandler.ListGoalsWithProgress)
 
 		// New goal categories and hierarchical goals endpoints
 		api.GET("/finance/goals/categories", financeHandler.ListGoalCategories)
+		api.POST("/finance/goals/categories", financeHandler.CreateGoalCategory)
+		api.GET("/finance/goals/categories/:id", financeHandler.GetGoalCategory)
+		api.PUT("/finance/goals/categories/:id", financeHandler.UpdateGoalCategory)
 		api.GET("/finance/goals/hierarchical", financeHandler.ListMainGoalsWithSubgoals)
 		api.POST("/finance/goals/expenses", financeHandler.CreateGoalExpense)
 		api.GET("/finance/goals/:id/expenses", financeHandler.ListGoalExpenses)
+
+		// Goal templates create a goal hierarchy with targets worked out from the user's averages
+		api.GET("/finance/goals/templates", financeHandler.ListGoalTemplates)
+		api.POST("/finance/goals/templates", financeHandler.CreateGoalTemplate)
+		api.GET("/finance/goals/templates/:id", financeHandler.GetGoalTemplate)
+		api.PUT("/finance/goals/templates/:id", financeHandler.UpdateGoalTemplate)
+		api.DELETE("/finance/goals/templates/:id", financeHandler.DeleteGoalTemplate)
+		api.POST("/finance/goals/templates/:id/instantiate", financeHandler.InstantiateGoalTemplate)
+
+		// Goal lifecycle: status changes with their history, and withdrawals
+		api.POST("/finance/goals/:id/status", financeHandler.ChangeGoalStatus)
+		api.GET("/finance/goals/:id/status-history", financeHandler.ListGoalStatusHistory)
+		api.POST("/finance/goals/:id/withdrawals", financeHandler.CreateGoalWithdrawal)
+
+		// Expense rules set the category and goal of matching expenses
+		api.GET("/finance/rules", rulesHandler.ListRules)
+		api.POST("/finance/rules", rulesHandler.CreateRule)
+		api.POST("/finance/rules/apply", rulesHandler.ApplyRules)
+		api.GET("/finance/rules/:id", rulesHandler.GetRule)
+		api.PUT("/finance/rules/:id", rulesHandler.UpdateRule)
+		api.DELETE("/finance/rules/:id", rulesHandler.DeleteRule)
+
+		// Funding rules contribute to goals from new incomes, expense round-ups and fixed monthly amounts
+		api.GET("/finance/funding-rules", financeHandler.ListFundingRules)
+		api.POST("/finance/funding-rules", financeHandler.CreateFundingRule)
+		api.POST("/finance/funding-rules/run", financeHandler.RunFundingRules)
+		api.GET("/finance/funding-rules/:id", financeHandler.GetFundingRule)
+		api.PUT("/finance/funding-rules/:id", financeHandler.UpdateFundingRule)
+		api.DELETE("/finance/funding-rules/:id", financeHandler.DeleteFundingRule)
+
+		// Month-end surplus is allocated across goals by priority and weight
+		api.GET("/finance/goals/allocation", financeHandler.PreviewSurplusAllocation)
+		api.POST("/finance/goals/allocation", financeHandler.CommitSurplusAllocation)
+
+		// Merchants group expenses whose descriptions name the same payee
+		api.GET("/finance/merchants", financeHandler.ListMerchants)
+		api.POST("/finance/merchants", financeHandler.CreateMerchant)
+		api.POST("/finance/merchants/resolve", financeHandler.ResolveMerchants)
+		api.GET("/finance/merchants/:id", financeHandler.GetMerchant)
+		api.PUT("/finance/merchants/:id", financeHandler.UpdateMerchant)
+		api.DELETE("/finance/merchants/:id", financeHandler.DeleteMerchant)
+		api.POST("/finance/merchants/:id/patterns", financeHandler.AddMerchantPattern)
+		api.DELETE("/finance/merchants/:id/patterns/:patternId", financeHandler.DeleteMerchantPattern)
+		api.POST("/finance/merchants/:id/merge", financeHandler.MergeMerchants)
+
+		// Debts: loans and credit cards paid by linked expenses, with payoff planning
+		api.GET("/finance/debts", financeHandler.ListDebts)
+		api.POST("/finance/debts", financeHandler.CreateDebt)
+		api.GET("/finance/debts/payoff", financeHandler.PlanDebtPayoff)
+		api.GET("/finance/debts/:id", financeHandler.GetDebt)
+		api.PUT("/finance/debts/:id", financeHandler.UpdateDebt)
+		api.DELETE("/finance/debts/:id", financeHandler.DeleteDebt)
+		api.GET("/finance/debts/:id/schedule", financeHandler.GetDebtSchedule)
+		api.GET("/finance/debts/:id/payments", financeHandler.ListDebtPayments)
+		api.POST("/finance/debts/:id/payments", financeHandler.RecordDebtPayment)
+
+		// Net worth: valued assets and liabilities alongside the ledger, debts and goals, with month-end snapshots
+		api.GET("/finance/net-worth", financeHandler.GetNetWorth)
+		api.GET("/finance/net-worth/history", financeHandler.GetNetWorthHistory)
+		api.POST("/finance/net-worth/snapshots/run", financeHandler.RunNetWorthSnapshots)
+		api.GET("/finance/net-worth/items", financeHandler.ListNetWorthItems)
+		api.POST("/finance/net-worth/items", financeHandler.CreateNetWorthItem)
+		api.GET("/finance/net-worth/items/:id", financeHandler.GetNetWorthItem)
+		api.PUT("/finance/net-worth/items/:id", financeHandler.UpdateNetWorthItem)
+		api.DELETE("/finance/net-worth/items/:id", financeHandler.DeleteNetWorthItem)
+		api.GET("/finance/net-worth/items/:id/valuations", financeHandler.ListValuations)
+		api.POST("/finance/net-worth/items/:id/valuations", financeHandler.CreateValuation)
+		api.DELETE("/finance/net-worth/items/:id/valuations/:valuationId", financeHandler.DeleteValuation)
+
+		// Investments: securities held, their lots, sales, dividends and imported prices, valued as a portfolio
+		api.GET("/finance/investments", financeHandler.GetPortfolio)
+		api.GET("/finance/investments/securities", financeHandler.ListSecurities)
+		api.POST("/finance/investments/securities", financeHandler.CreateSecurity)
+		api.GET("/finance/investments/securities/:id", financeHandler.GetSecurity)
+		api.PUT("/finance/investments/securities/:id", financeHandler.UpdateSecurity)
+		api.DELETE("/finance/investments/securities/:id", financeHandler.DeleteSecurity)
+		api.GET("/finance/investments/securities/:id/lots", financeHandler.ListLots)
+		api.POST("/finance/investments/securities/:id/lots", financeHandler.CreateLot)
+		api.DELETE("/finance/investments/securities/:id/lots/:lotId", financeHandler.DeleteLot)
+		api.GET("/finance/investments/securities/:id/sales", financeHandler.ListSales)
+		api.POST("/finance/investments/securities/:id/sales", financeHandler.CreateSale)
+		api.DELETE("/finance/investments/securities/:id/sales/:saleId", financeHandler.DeleteSale)
+		api.GET("/finance/investments/securities/:id/dividends", financeHandler.ListDividends)
+		api.POST("/finance/investments/securities/:id/dividends", financeHandler.RecordDividend)
+		api.GET("/finance/investments/securities/:id/prices", financeHandler.ListSecurityPrices)
+
+		// Tags label incomes and expenses; ledger listings filter on them with ?tag=
+		api.POST("/finance/tags/bulk", financeHandler.BulkTag)
+
+		// Attachments: receipts and documents kept with expenses, incomes and notes
+		api.GET("/finance/expenses/:id/attachments", attachmentHandler.ListExpenseAttachments)
+		api.GET("/finance/incomes/:id/attachments", attachmentHandler.ListIncomeAttachments)
+		api.GET("/notes/:id/attachments", attachmentHandler.ListNoteAttachments)
+		api.GET("/attachments/:id", attachmentHandler.DownloadAttachment)
+		api.GET("/attachments/:id/thumbnail", attachmentHandler.DownloadThumbnail)
+		api.DELETE("/attachments/:id", attachmentHandler.DeleteAttachment)
+
+		// Trash: deleted records can be restored until they are purged
+		api.GET("/trash", trashHandler.ListTrash)
+
+		// Audit log of every create, update, delete and restore
+		api.GET("/audit", auditHandler.ListEntries)
+	}
+
+	// Uploads; the body limit applies before the idempotency middleware buffers the body
+	uploads := r.Group("/api", MaxBodySize(attachmentHandler.UploadLimit()), Idempotency(idempotencyService))
+	{
+		uploads.POST("/finance/expenses/:id/attachments", attachmentHandler.UploadExpenseAttachment)
+		uploads.POST("/finance/incomes/:id/attachments", attachmentHandler.UploadIncomeAttachment)
+		uploads.POST("/notes/:id/attachments", attachmentHandler.UploadNoteAttachment)
+		uploads.POST("/finance/investments/prices/import", financeHandler.ImportSecurityPrices)
 	}
 
 	// Root health check
 	r.GET("/health", healthHandler.Health)
 	r.GET("/health/db", healthHandler.DatabaseHealth)
+
+	return jobs
 }
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RulesHandler handles expense rule endpoints
+type RulesHandler struct {
+	rulesService *services.RulesService
+}
+
+// NewRulesHandler creates a new rules handler
+func NewRulesHandler(rulesService *services.RulesService) *RulesHandler {
+	return &RulesHandler{rulesService: rulesService}
+}
+
+// ListRules GET /api/finance/rules
+func (h *RulesHandler) ListRules(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	rules, err := h.rulesService.ListRules(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// CreateRule POST /api/finance/rules
+func (h *RulesHandler) CreateRule(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.CreateRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	rule, err := h.rulesService.WithActor(actor(c)).CreateRule(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetRule GET /api/finance/rules/:id
+func (h *RulesHandler) GetRule(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+
+	rule, err := h.rulesService.GetRule(userID, id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateRule PUT /api/finance/rules/:id
+func (h *RulesHandler) UpdateRule(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	var req request.UpdateRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	rule, err := h.rulesService.WithActor(actor(c)).UpdateRule(userID, id, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule DELETE /api/finance/rules/:id
+func (h *RulesHandler) DeleteRule(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.rulesService.WithActor(actor(c)).DeleteRule(userID, id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// ApplyRules POST /api/finance/rules/apply
+// Re-runs rules over existing expenses; {"dry_run": true} previews the changes
+func (h *RulesHandler) ApplyRules(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.ApplyRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+
+	result, err := h.rulesService.WithActor(actor(c)).ApplyRules(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"finance-management/internal/errors"
+	"finance-management/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SuggestionsHandler handles category suggestion endpoints
+type SuggestionsHandler struct {
+	suggestionService *services.SuggestionService
+}
+
+// NewSuggestionsHandler creates a new suggestions handler
+func NewSuggestionsHandler(suggestionService *services.SuggestionService) *SuggestionsHandler {
+	return &SuggestionsHandler{suggestionService: suggestionService}
+}
+
+// SuggestCategory handles GET /api/finance/expenses/suggest?description=&amount=
+func (h *SuggestionsHandler) SuggestCategory(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var amount float64
+	if amountStr := c.Query("amount"); amountStr != "" {
+		parsed, err := strconv.ParseFloat(amountStr, 64)
+		if err != nil {
+			errors.HandleError(c, errors.ErrInvalidAmount)
+			return
+		}
+		amount = parsed
+	}
+
+	suggestion, err := h.suggestionService.SuggestCategory(userID, c.Query("description"), amount)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, suggestion)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BulkTag handles POST /api/finance/tags/bulk
+func (h *FinanceHandler) BulkTag(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	var req request.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleValidationError(c, err)
+		return
+	}
+	result, err := h.financeService.WithActor(actor(c)).BulkTag(userID, &req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// tagFilter reads the tag filter of a ledger listing. tag may be repeated or hold a
+// comma-separated list; tag_match=any matches transactions with at least one of the
+// tags instead of all of them.
+func tagFilter(c *gin.Context) (repository.TagFilter, error) {
+	var filter repository.TagFilter
+	for _, v := range c.QueryArray("tag") {
+		filter.Tags = append(filter.Tags, strings.Split(v, ",")...)
+	}
+	switch c.DefaultQuery("tag_match", "all") {
+	case "all":
+	case "any":
+		filter.Any = true
+	default:
+		return filter, errors.ErrInvalidInput
+	}
+	return filter, nil
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"finance-management/internal/errors"
+	"finance-management/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TrashHandler handles trash endpoints
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+// NewTrashHandler creates a new trash handler
+func NewTrashHandler(trashService *services.TrashService) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+// ListTrash handles GET /api/trash
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	trash, err := h.trashService.ListTrash(userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, trash)
+}
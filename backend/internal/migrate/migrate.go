@@ -0,0 +1,406 @@
+// Package migrate applies and rolls back the SQL migrations in db/migrations.
+// Applied versions are recorded in app_schema_migrations together with the
+// checksum of their up script, and every command that changes the schema runs
+// under a Postgres advisory lock so concurrently starting instances cannot race.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// advisoryLockKey identifies the migration lock in pg_advisory_lock
+const advisoryLockKey int64 = 0x66696e6d6967 // "finmig"
+
+// Status states reported for each migration
+const (
+	StateApplied  = "applied"
+	StatePending  = "pending"
+	StateModified = "modified" // applied, but the up script changed since
+	StateMissing  = "missing"  // applied, but no longer present in the source
+)
+
+// MigrationStatus describes one migration in the status report
+type MigrationStatus struct {
+	Version   string
+	Name      string
+	State     string
+	AppliedAt *time.Time
+}
+
+// appliedMigration is a row of app_schema_migrations
+type appliedMigration struct {
+	Version   string
+	Name      string
+	Checksum  sql.NullString
+	AppliedAt time.Time
+}
+
+// Migrator runs migrations against a Postgres database
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New creates a migrator for the given migrations, which must be ordered by version (see Load)
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Up applies all pending migrations and returns how many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[string]appliedMigration) error {
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig, true); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down rolls back the n most recently applied migrations
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("down requires a positive number of migrations, got %d", n)
+	}
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[string]appliedMigration) error {
+		for i := len(m.migrations) - 1; i >= 0 && count < n; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig, false); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Redo rolls back the most recently applied migration and applies it again
+func (m *Migrator) Redo(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn, applied map[string]appliedMigration) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig, false); err != nil {
+				return err
+			}
+			return m.apply(ctx, conn, mig, true)
+		}
+		return fmt.Errorf("no applied migrations to redo")
+	})
+}
+
+// To migrates up or down until version is the latest applied migration.
+// Version "0" rolls back everything.
+func (m *Migrator) To(ctx context.Context, version string) error {
+	target := versionNumber(version)
+	if target < 0 {
+		return fmt.Errorf("invalid migration version %q", version)
+	}
+	if target > 0 && m.find(version) == nil {
+		return fmt.Errorf("unknown migration version %q", version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn, applied map[string]appliedMigration) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; ok && versionNumber(mig.Version) > target {
+				if err := m.apply(ctx, conn, mig, false); err != nil {
+					return err
+				}
+			}
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; !ok && versionNumber(mig.Version) <= target {
+				if err := m.apply(ctx, conn, mig, true); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Status reports every known and applied migration. It only reads: it takes no
+// lock and does not create or upgrade the bookkeeping table, so it works for a role
+// without DDL rights and does not wait behind a migration in progress. Without the
+// table every migration is pending.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := readApplied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	return buildStatus(m.migrations, applied), nil
+}
+
+// buildStatus merges source migrations with applied rows, ordered by version
+func buildStatus(migrations []Migration, applied map[string]appliedMigration) []MigrationStatus {
+	result := make([]MigrationStatus, 0, len(migrations))
+	known := map[string]bool{}
+	for _, mig := range migrations {
+		known[mig.Version] = true
+		status := MigrationStatus{Version: mig.Version, Name: mig.Name, State: StatePending}
+		if row, ok := applied[mig.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.State = StateApplied
+			if row.Checksum.Valid && row.Checksum.String != mig.Checksum {
+				status.State = StateModified
+			}
+		}
+		result = append(result, status)
+	}
+	for version, row := range applied {
+		if known[version] {
+			continue
+		}
+		appliedAt := row.AppliedAt
+		result = append(result, MigrationStatus{Version: version, Name: row.Name, State: StateMissing, AppliedAt: &appliedAt})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return versionNumber(result[i].Version) < versionNumber(result[j].Version)
+	})
+	return result
+}
+
+// withConn runs fn on a dedicated connection holding the advisory lock, after
+// making sure the bookkeeping table exists. Only commands that change the schema use it.
+func (m *Migrator) withConn(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire database connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockKey); err != nil {
+			log.Printf("⚠️  Failed to release migration lock: %v", err)
+		}
+	}()
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// withLock is withConn for commands that change the schema: it loads the applied
+// migrations and refuses to continue when the history does not match the source
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn, applied map[string]appliedMigration) error) error {
+	return m.withConn(ctx, func(conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(ctx, conn, applied); err != nil {
+			return err
+		}
+		return fn(conn, applied)
+	})
+}
+
+// verify checks applied checksums against the source. Rows recorded before
+// checksums existed adopt the current checksum.
+func (m *Migrator) verify(ctx context.Context, conn *sql.Conn, applied map[string]appliedMigration) error {
+	var problems []string
+	for version, row := range applied {
+		mig := m.find(version)
+		if mig == nil {
+			problems = append(problems, fmt.Sprintf("%s_%s is applied but has no migration file", version, row.Name))
+			continue
+		}
+		if !row.Checksum.Valid {
+			if _, err := conn.ExecContext(ctx, "UPDATE app_schema_migrations SET checksum = $1, name = $2 WHERE version = $3", mig.Checksum, mig.Name, version); err != nil {
+				return fmt.Errorf("failed to record checksum for %s: %w", version, err)
+			}
+			log.Printf("🔏 Recorded checksum for previously applied migration %s_%s", version, mig.Name)
+			continue
+		}
+		if row.Checksum.String != mig.Checksum {
+			problems = append(problems, fmt.Sprintf("%s_%s was modified after it was applied", version, mig.Name))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("migration history does not match migration files:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// apply runs one migration in the given direction and updates the bookkeeping table
+// in the same transaction, unless the migration opts out of transactions
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration, up bool) error {
+	script, action := mig.Down, "Rolling back"
+	record := "DELETE FROM app_schema_migrations WHERE version = $1"
+	args := []interface{}{mig.Version}
+	if up {
+		script, action = mig.Up, "Applying"
+		record = "INSERT INTO app_schema_migrations (version, name, checksum) VALUES ($1, $2, $3)"
+		args = []interface{}{mig.Version, mig.Name, mig.Checksum}
+	}
+	log.Printf("🔄 %s migration: %s_%s", action, mig.Version, mig.Name)
+
+	if mig.NoTransaction {
+		if _, err := conn.ExecContext(ctx, script); err != nil {
+			return fmt.Errorf("migration %s_%s failed: %w", mig.Version, mig.Name, err)
+		}
+		if _, err := conn.ExecContext(ctx, record, args...); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", mig.Version, err)
+		}
+		log.Printf("✅ %s_%s done", mig.Version, mig.Name)
+		return nil
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for %s: %w", mig.Version, err)
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration %s_%s failed: %w", mig.Version, mig.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record migration %s: %w", mig.Version, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", mig.Version, err)
+	}
+	log.Printf("✅ %s_%s done", mig.Version, mig.Name)
+	return nil
+}
+
+func (m *Migrator) find(version string) *Migration {
+	for i := range m.migrations {
+		if versionNumber(m.migrations[i].Version) == versionNumber(version) {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// ensureTable creates app_schema_migrations and upgrades rows written by the
+// original tool, which stored the whole file name as the version
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS app_schema_migrations (
+            version VARCHAR(255) PRIMARY KEY,
+            applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+        )`,
+		`ALTER TABLE app_schema_migrations ADD COLUMN IF NOT EXISTS name VARCHAR(255)`,
+		`ALTER TABLE app_schema_migrations ADD COLUMN IF NOT EXISTS checksum VARCHAR(64)`,
+		`UPDATE app_schema_migrations
+            SET name = substring(version from '^[0-9]+_(.*)\.sql$'),
+                version = substring(version from '^([0-9]+)_')
+          WHERE version ~ '^[0-9]+_.*\.sql$'`,
+	}
+	for _, stmt := range statements {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to prepare migrations table: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadApplied returns the applied migrations keyed by version
+func loadApplied(ctx context.Context, conn *sql.Conn) (map[string]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, COALESCE(name, ''), checksum, applied_at FROM app_schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check migration status: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]appliedMigration{}
+	for rows.Next() {
+		var row appliedMigration
+		if err := rows.Scan(&row.Version, &row.Name, &row.Checksum, &row.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read migration status: %w", err)
+		}
+		applied[row.Version] = row
+	}
+	return applied, rows.Err()
+}
+
+// legacyVersion matches the versions the original tool recorded: whole file names
+var legacyVersion = regexp.MustCompile(`^([0-9]+)_(.*)\.sql$`)
+
+// readApplied returns the applied migrations keyed by version without changing
+// anything, unlike ensureTable followed by loadApplied. A missing table means nothing
+// is applied; rows and columns left by the original tool are read as ensureTable
+// would upgrade them.
+func readApplied(ctx context.Context, db *sql.DB) (map[string]appliedMigration, error) {
+	var table sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass('app_schema_migrations')::text").Scan(&table); err != nil {
+		return nil, fmt.Errorf("failed to check migrations table: %w", err)
+	}
+	if !table.Valid {
+		return map[string]appliedMigration{}, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `
+        SELECT attname FROM pg_attribute
+         WHERE attrelid = 'app_schema_migrations'::regclass AND attname IN ('name', 'checksum') AND NOT attisdropped`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check migrations table: %w", err)
+	}
+	name, checksum := "''", "NULL::varchar"
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to check migrations table: %w", err)
+		}
+		switch column {
+		case "name":
+			name = "COALESCE(name, '')"
+		case "checksum":
+			checksum = "checksum"
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check migrations table: %w", err)
+	}
+
+	rows, err = db.QueryContext(ctx, fmt.Sprintf("SELECT version, %s, %s, applied_at FROM app_schema_migrations", name, checksum))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check migration status: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]appliedMigration{}
+	for rows.Next() {
+		var row appliedMigration
+		if err := rows.Scan(&row.Version, &row.Name, &row.Checksum, &row.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read migration status: %w", err)
+		}
+		if parts := legacyVersion.FindStringSubmatch(row.Version); parts != nil {
+			row.Version, row.Name = parts[1], parts[2]
+		}
+		applied[row.Version] = row
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,90 @@
+package migrate
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLoadPairsAndOrdersMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_later.up.sql":        {Data: []byte("CREATE TABLE later ();")},
+		"010_later.down.sql":      {Data: []byte("DROP TABLE later;")},
+		"002_first.up.sql":        {Data: []byte("CREATE TABLE first ();")},
+		"002_first.down.sql":      {Data: []byte("DROP TABLE first;")},
+		"003_concurrent.up.sql":   {Data: []byte("-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY idx ON first(id);")},
+		"003_concurrent.down.sql": {Data: []byte("")},
+		"README.md":               {Data: []byte("ignored")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(migrations) != 3 {
+		t.Fatalf("expected 3 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != "002" || migrations[1].Version != "003" || migrations[2].Version != "010" {
+		t.Fatalf("expected numeric version order, got %s, %s, %s", migrations[0].Version, migrations[1].Version, migrations[2].Version)
+	}
+	if migrations[0].Checksum != Checksum("CREATE TABLE first ();") {
+		t.Fatalf("checksum should be computed from the up script")
+	}
+	if migrations[0].NoTransaction || !migrations[1].NoTransaction {
+		t.Fatalf("only migrations with the marker should skip the transaction")
+	}
+}
+
+func TestLoadRejectsInvalidSources(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing down": {
+			"001_a.up.sql": {Data: []byte("SELECT 1;")},
+		},
+		"missing up": {
+			"001_a.down.sql": {Data: []byte("SELECT 1;")},
+		},
+		"legacy name": {
+			"001_a.sql": {Data: []byte("SELECT 1;")},
+		},
+		"duplicate version": {
+			"1_a.up.sql":    {Data: []byte("SELECT 1;")},
+			"1_a.down.sql":  {Data: []byte("SELECT 1;")},
+			"01_b.up.sql":   {Data: []byte("SELECT 1;")},
+			"01_b.down.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(fsys); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestBuildStatus(t *testing.T) {
+	migrations := []Migration{
+		{Version: "001", Name: "one", Checksum: Checksum("one")},
+		{Version: "002", Name: "two", Checksum: Checksum("two")},
+		{Version: "003", Name: "three", Checksum: Checksum("three")},
+	}
+	appliedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	applied := map[string]appliedMigration{
+		"001": {Version: "001", Name: "one", Checksum: sql.NullString{String: Checksum("one"), Valid: true}, AppliedAt: appliedAt},
+		"002": {Version: "002", Name: "two", Checksum: sql.NullString{String: Checksum("edited"), Valid: true}, AppliedAt: appliedAt},
+		"004": {Version: "004", Name: "gone", AppliedAt: appliedAt},
+	}
+
+	statuses := buildStatus(migrations, applied)
+	var states []string
+	for _, s := range statuses {
+		states = append(states, s.Version+":"+s.State)
+	}
+	got := strings.Join(states, ",")
+	want := "001:applied,002:modified,003:pending,004:missing"
+	if got != want {
+		t.Fatalf("status: got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// noTransactionMarker opts a migration out of the per-migration transaction,
+// e.g. for CREATE INDEX CONCURRENTLY
+const noTransactionMarker = "-- migrate:no-transaction"
+
+// fileNamePattern matches {version}_{name}.{up|down}.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is a paired up/down SQL migration
+type Migration struct {
+	Version       string
+	Name          string
+	Up            string
+	Down          string
+	Checksum      string // SHA-256 of the up script
+	NoTransaction bool
+}
+
+// Load reads paired migrations from fsys, ordered by version. Every version
+// must have exactly one up and one down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration files: %w", err)
+	}
+
+	type pair struct {
+		Migration
+		hasUp, hasDown bool
+	}
+	byVersion := map[string]*pair{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q: expected {version}_{name}.up.sql or .down.sql", entry.Name())
+		}
+		version, name, direction := match[1], match[2], match[3]
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &pair{Migration: Migration{Version: version, Name: name}}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %s has mismatched names %q and %q", version, m.Name, name)
+		}
+
+		switch direction {
+		case "up":
+			m.hasUp = true
+			m.Up = string(content)
+			m.Checksum = Checksum(m.Up)
+			m.NoTransaction = strings.Contains(m.Up, noTransactionMarker)
+		case "down":
+			m.hasDown = true
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if !m.hasUp {
+			return nil, fmt.Errorf("migration %s_%s is missing its .up.sql file", m.Version, m.Name)
+		}
+		if !m.hasDown {
+			return nil, fmt.Errorf("migration %s_%s is missing its .down.sql file", m.Version, m.Name)
+		}
+		migrations = append(migrations, m.Migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return versionNumber(migrations[i].Version) < versionNumber(migrations[j].Version)
+	})
+	for i := 1; i < len(migrations); i++ {
+		if versionNumber(migrations[i].Version) == versionNumber(migrations[i-1].Version) {
+			return nil, fmt.Errorf("duplicate migration version %s and %s", migrations[i-1].Version, migrations[i].Version)
+		}
+	}
+	return migrations, nil
+}
+
+// Checksum returns the hex SHA-256 of a migration script
+func Checksum(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
+
+// versionNumber compares versions numerically so "10" sorts after "009"
+func versionNumber(version string) int {
+	n, err := strconv.Atoi(version)
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"finance-management/db/migrations"
+)
+
+var (
+	// ErrSchemaBehind means migrations compiled into the binary have not been applied
+	ErrSchemaBehind = errors.New("database schema is behind the application")
+	// ErrSchemaAhead means the database has migrations this binary does not know about
+	ErrSchemaAhead = errors.New("database schema is ahead of the application")
+	// ErrSchemaModified means an applied migration no longer matches its file
+	ErrSchemaModified = errors.New("applied migrations were modified")
+)
+
+// Embedded returns the migrations compiled into the binary
+func Embedded() ([]Migration, error) {
+	return Load(migrations.FS)
+}
+
+// LatestVersion returns the version of the newest migration, or "0" if there are none
+func LatestVersion(migrations []Migration) string {
+	if len(migrations) == 0 {
+		return "0"
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// CurrentVersion returns the newest applied migration version, or "0" for an
+// empty database. It does not take the migration lock.
+func CurrentVersion(ctx context.Context, db *sql.DB) (string, error) {
+	var table sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass('app_schema_migrations')::text").Scan(&table); err != nil {
+		return "", fmt.Errorf("failed to check migrations table: %w", err)
+	}
+	if !table.Valid {
+		return "0", nil
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version FROM app_schema_migrations")
+	if err != nil {
+		return "", fmt.Errorf("failed to read schema version: %w", err)
+	}
+	defer rows.Close()
+
+	current := "0"
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return "", fmt.Errorf("failed to read schema version: %w", err)
+		}
+		if versionNumber(version) > versionNumber(current) {
+			current = version
+		}
+	}
+	return current, rows.Err()
+}
+
+// Check verifies that exactly the known migrations are applied, unmodified.
+// Like Status it only reads, so it can run at startup with AUTO_MIGRATE off. The
+// returned error wraps ErrSchemaBehind, ErrSchemaAhead or ErrSchemaModified.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pending, missing, modified []string
+	for _, s := range statuses {
+		switch s.State {
+		case StatePending:
+			pending = append(pending, s.Version)
+		case StateMissing:
+			missing = append(missing, s.Version)
+		case StateModified:
+			modified = append(modified, s.Version)
+		}
+	}
+
+	switch {
+	case len(missing) > 0:
+		return fmt.Errorf("%w: unknown applied versions %s", ErrSchemaAhead, strings.Join(missing, ", "))
+	case len(modified) > 0:
+		return fmt.Errorf("%w: %s", ErrSchemaModified, strings.Join(modified, ", "))
+	case len(pending) > 0:
+		return fmt.Errorf("%w: pending versions %s", ErrSchemaBehind, strings.Join(pending, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Record types an attachment can belong to
+const (
+	AttachmentOwnerExpense = "expense"
+	AttachmentOwnerIncome  = "income"
+	AttachmentOwnerNote    = "note"
+)
+
+// Attachment is a file, such as a receipt photo or a PDF, attached to an expense,
+// income or note. The file is kept in storage under StorageKey.
+type Attachment struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	OwnerType   string    `json:"owner_type" gorm:"column:owner_type"`
+	OwnerID     uuid.UUID `json:"owner_id" gorm:"type:uuid;column:owner_id"`
+	FileName    string    `json:"file_name" gorm:"column:file_name"`
+	ContentType string    `json:"content_type" gorm:"column:content_type"`
+	Size        int64     `json:"size" gorm:"column:size"`
+	StorageKey  string    `json:"-" gorm:"column:storage_key"`
+	// ThumbnailKey is set for images a thumbnail could be made of
+	ThumbnailKey *string   `json:"-" gorm:"column:thumbnail_key"`
+	CreatedAt    time.Time `json:"created_at" gorm:"column:created_at"`
+}
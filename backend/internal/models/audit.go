@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntry is an append-only record of one create, update, delete or restore
+type AuditEntry struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	Actor      string    `json:"actor" gorm:"column:actor"`
+	EntityType string    `json:"entity_type" gorm:"column:entity_type"`
+	EntityID   uuid.UUID `json:"entity_id" gorm:"type:uuid;column:entity_id"`
+	Action     string    `json:"action" gorm:"column:action"`
+	// Changes is a JSON object of field name to {"before": ..., "after": ...}
+	Changes   string    `json:"changes" gorm:"type:jsonb;column:changes"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of debt
+const (
+	DebtLoan       = "loan"
+	DebtCreditCard = "credit_card"
+)
+
+// Debt is a loan or credit card. Payments are expenses linked to it, and its
+// outstanding balance is worked out from the principal, the APR and those payments.
+type Debt struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	Name   string    `json:"name" gorm:"column:name"`
+	Kind   string    `json:"kind" gorm:"column:kind"`
+	// Principal is what was owed on StartedOn
+	Principal float64 `json:"principal" gorm:"column:principal"`
+	// APR is the nominal annual interest rate in percent, charged monthly on the due day
+	APR            float64   `json:"apr" gorm:"column:apr"`
+	MinimumPayment float64   `json:"minimum_payment" gorm:"column:minimum_payment"`
+	DueDay         int       `json:"due_day" gorm:"column:due_day"`
+	StartedOn      time.Time `json:"started_on" gorm:"type:date;column:started_on"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
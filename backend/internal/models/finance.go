@@ -4,43 +4,68 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
 )
 
 // Income represents an income entry (e.g., monthly salary)
 type Income struct {
-	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
-	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
-	Source     string    `json:"source" gorm:"column:source"`
-	Amount     float64   `json:"amount" gorm:"column:amount"`
-	ReceivedAt time.Time `json:"received_at" gorm:"type:date;column:received_at"`
-	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID     uuid.UUID      `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	Source     string         `json:"source" gorm:"column:source"`
+	Amount     float64        `json:"amount" gorm:"column:amount"`
+	ReceivedAt time.Time      `json:"received_at" gorm:"type:date;column:received_at"`
+	SecurityID *uuid.UUID     `json:"security_id" gorm:"type:uuid;column:security_id"`
+	Tags       pq.StringArray `json:"tags" gorm:"type:text[];column:tags;default:'{}'"`
+	Version    int            `json:"version" gorm:"column:version;default:1"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at" gorm:"index;column:deleted_at"`
 }
 
 // Expense represents a spending entry
 type Expense struct {
-	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;column:id"`
-	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;index;column:user_id"`
-	Category    string     `json:"category" gorm:"column:category"`
-	Description string     `json:"description" gorm:"column:description"`
-	Amount      float64    `json:"amount" gorm:"column:amount"`
-	SpentAt     time.Time  `json:"spent_at" gorm:"type:date;column:spent_at"`
-	GoalID      *uuid.UUID `json:"goal_id" gorm:"type:uuid;column:goal_id"`
-	CreatedAt   time.Time  `json:"created_at" gorm:"column:created_at"`
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID      uuid.UUID      `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	Category    string         `json:"category" gorm:"column:category"`
+	CategoryID  *uuid.UUID     `json:"category_id" gorm:"type:uuid;column:category_id"`
+	Description string         `json:"description" gorm:"column:description"`
+	Amount      float64        `json:"amount" gorm:"column:amount"`
+	SpentAt     time.Time      `json:"spent_at" gorm:"type:date;column:spent_at"`
+	GoalID      *uuid.UUID     `json:"goal_id" gorm:"type:uuid;column:goal_id"`
+	MerchantID  *uuid.UUID     `json:"merchant_id" gorm:"type:uuid;column:merchant_id"`
+	DebtID      *uuid.UUID     `json:"debt_id" gorm:"type:uuid;column:debt_id"`
+	Tags        pq.StringArray `json:"tags" gorm:"type:text[];column:tags;default:'{}'"`
+	Version     int            `json:"version" gorm:"column:version;default:1"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index;column:deleted_at"`
 }
 
 // Goal represents a savings goal
 type Goal struct {
-	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;column:id"`
-	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;index;column:user_id"`
-	Name         string     `json:"name" gorm:"column:name"`
-	Description  string     `json:"description" gorm:"column:description"`
-	Category     string     `json:"category" gorm:"column:category"`
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	Name        string    `json:"name" gorm:"column:name"`
+	Description string    `json:"description" gorm:"column:description"`
+	Category    string    `json:"category" gorm:"column:category"`
+	// CategoryID references the goal category; Category keeps a copy of its name
+	CategoryID   *uuid.UUID `json:"category_id" gorm:"type:uuid;column:category_id"`
 	TargetAmount float64    `json:"target_amount" gorm:"column:target_amount"`
 	TargetDate   *time.Time `json:"target_date" gorm:"type:date;column:target_date"`
 	ParentGoalID *uuid.UUID `json:"parent_goal_id" gorm:"type:uuid;column:parent_goal_id"`
 	IsMainGoal   bool       `json:"is_main_goal" gorm:"column:is_main_goal"`
-	CreatedAt    time.Time  `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt    time.Time  `json:"updated_at" gorm:"column:updated_at"`
+	// AllocationPriority orders goals when month-end surplus is allocated, 1 first, and
+	// AllocationWeight splits the surplus between goals of the same priority
+	AllocationPriority int     `json:"allocation_priority" gorm:"column:allocation_priority;default:1"`
+	AllocationWeight   float64 `json:"allocation_weight" gorm:"column:allocation_weight;default:1"`
+	// Status is one of the goal lifecycle statuses; StatusChangedAt is unset until it first changes
+	Status          string         `json:"status" gorm:"column:status;default:active"`
+	StatusChangedAt *time.Time     `json:"status_changed_at" gorm:"column:status_changed_at"`
+	Version         int            `json:"version" gorm:"column:version;default:1"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at" gorm:"index;column:deleted_at"`
 }
 
 // GoalContribution represents money allocated to a goal
@@ -50,17 +75,28 @@ type GoalContribution struct {
 	GoalID        uuid.UUID `json:"goal_id" gorm:"type:uuid;index;column:goal_id"`
 	Amount        float64   `json:"amount" gorm:"column:amount"`
 	ContributedAt time.Time `json:"contributed_at" gorm:"type:date;column:contributed_at"`
-	CreatedAt     time.Time `json:"created_at" gorm:"column:created_at"`
+	// FundingRuleID is set on contributions generated by a funding rule, and SourceID
+	// on those triggered by an income or expense
+	FundingRuleID *uuid.UUID `json:"funding_rule_id" gorm:"type:uuid;column:funding_rule_id"`
+	SourceID      *uuid.UUID `json:"source_id" gorm:"type:uuid;column:source_id"`
+	// Reason is set on withdrawals, which are stored with a negative amount
+	Reason    *string        `json:"reason" gorm:"column:reason"`
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index;column:deleted_at"`
 }
 
-// GoalCategory represents predefined goal categories
+// GoalCategory represents goal categories. Built-in categories have no user;
+// users add their own and hide them rather than delete them.
 type GoalCategory struct {
-	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
-	Name        string    `json:"name" gorm:"column:name"`
-	Description string    `json:"description" gorm:"column:description"`
-	Icon        string    `json:"icon" gorm:"column:icon"`
-	Color       string    `json:"color" gorm:"column:color"`
-	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID      *uuid.UUID `json:"user_id" gorm:"type:uuid;column:user_id"`
+	Name        string     `json:"name" gorm:"column:name"`
+	Description string     `json:"description" gorm:"column:description"`
+	Icon        string     `json:"icon" gorm:"column:icon"`
+	Color       string     `json:"color" gorm:"column:color"`
+	Hidden      bool       `json:"hidden" gorm:"column:hidden"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"column:updated_at"`
 }
 
 // GoalExpense represents expenses associated with goals
@@ -88,14 +124,20 @@ type MonthlySummary struct {
 	TotalExpenses     float64               `json:"total_expenses"`
 	TotalSavings      float64               `json:"total_savings"`
 	CategoryBreakdown map[string]float64    `json:"category_breakdown"`
+	CategorySpending  map[uuid.UUID]float64 `json:"category_spending"`
 	GoalSpending      map[uuid.UUID]float64 `json:"goal_spending"`
 	GoalContributions map[uuid.UUID]float64 `json:"goal_contributions"`
+	MerchantSpending  map[uuid.UUID]float64 `json:"merchant_spending"`
+	TagIncome         map[string]float64    `json:"tag_income"`
+	TagSpending       map[string]float64    `json:"tag_spending"`
 }
 
-// Category for expenses
+// Category for expenses. Names are unique among siblings, ignoring case.
 type Category struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
-	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
-	Name      string    `json:"name" gorm:"column:name"`
-	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	ParentID  *uuid.UUID `json:"parent_id" gorm:"type:uuid;column:parent_id"`
+	Name      string     `json:"name" gorm:"column:name"`
+	CreatedAt time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"column:updated_at"`
 }
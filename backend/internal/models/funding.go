@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of goal funding rule
+const (
+	// FundingPercentOfIncome contributes a percentage of each matching income
+	FundingPercentOfIncome = "percent_of_income"
+	// FundingRoundUp contributes the difference between each expense and the next multiple of RoundTo
+	FundingRoundUp = "round_up"
+	// FundingFixedMonthly contributes a fixed amount on a day of every month
+	FundingFixedMonthly = "fixed_monthly"
+)
+
+// GoalFundingRule generates contributions to a goal automatically until the goal reaches its target
+type GoalFundingRule struct {
+	ID      uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID  uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	GoalID  uuid.UUID `json:"goal_id" gorm:"type:uuid;column:goal_id"`
+	Name    string    `json:"name" gorm:"column:name"`
+	Type    string    `json:"type" gorm:"column:type"`
+	Enabled bool      `json:"enabled" gorm:"column:enabled"`
+	// Percent and IncomeSource configure percent_of_income rules; a nil source matches every income
+	Percent      *float64 `json:"percent" gorm:"column:percent"`
+	IncomeSource *string  `json:"income_source" gorm:"column:income_source"`
+	// RoundTo configures round_up rules
+	RoundTo *float64 `json:"round_to" gorm:"column:round_to"`
+	// Amount and DayOfMonth configure fixed_monthly rules
+	Amount     *float64 `json:"amount" gorm:"column:amount"`
+	DayOfMonth *int     `json:"day_of_month" gorm:"column:day_of_month"`
+	// LastFundedOn is the last scheduled date a fixed_monthly rule contributed for
+	LastFundedOn *time.Time `json:"last_funded_on" gorm:"type:date;column:last_funded_on"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey records a POST request made with an Idempotency-Key header
+// and, once handled, the response to replay for retries of the same request
+type IdempotencyKey struct {
+	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;primaryKey;column:user_id"`
+	Key          string     `json:"key" gorm:"primaryKey;column:key"`
+	Method       string     `json:"method" gorm:"column:method"`
+	Path         string     `json:"path" gorm:"column:path"`
+	RequestHash  string     `json:"request_hash" gorm:"column:request_hash"`
+	StatusCode   int        `json:"status_code" gorm:"column:status_code"`
+	ContentType  string     `json:"content_type" gorm:"column:content_type"`
+	ResponseBody []byte     `json:"response_body" gorm:"column:response_body"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"column:created_at"`
+	CompletedAt  *time.Time `json:"completed_at" gorm:"column:completed_at"`
+}
+
+// Completed reports whether the original request has finished and can be replayed
+func (k *IdempotencyKey) Completed() bool {
+	return k.CompletedAt != nil
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Asset classes a security can belong to
+const (
+	AssetClassStock     = "stock"
+	AssetClassBond      = "bond"
+	AssetClassFund      = "fund"
+	AssetClassProperty  = "property"
+	AssetClassCommodity = "commodity"
+	AssetClassCrypto    = "crypto"
+	AssetClassCash      = "cash"
+	AssetClassOther     = "other"
+)
+
+// Security is a stock, bond, fund or other instrument the user holds in a
+// brokerage account. What is held is worked out from its lots and sales, and
+// dividends are incomes linked to it.
+type Security struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	Symbol     string    `json:"symbol" gorm:"column:symbol"`
+	Name       string    `json:"name" gorm:"column:name"`
+	AssetClass string    `json:"asset_class" gorm:"column:asset_class"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// InvestmentLot is a purchase of a security. Cost is the total paid, fees included.
+type InvestmentLot struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	SecurityID uuid.UUID `json:"security_id" gorm:"type:uuid;column:security_id"`
+	BoughtOn   time.Time `json:"bought_on" gorm:"type:date;column:bought_on"`
+	Quantity   float64   `json:"quantity" gorm:"column:quantity"`
+	Cost       float64   `json:"cost" gorm:"column:cost"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// InvestmentSale is a sale of part of a holding. Proceeds is the total received, net of fees.
+type InvestmentSale struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	SecurityID uuid.UUID `json:"security_id" gorm:"type:uuid;column:security_id"`
+	SoldOn     time.Time `json:"sold_on" gorm:"type:date;column:sold_on"`
+	Quantity   float64   `json:"quantity" gorm:"column:quantity"`
+	Proceeds   float64   `json:"proceeds" gorm:"column:proceeds"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// SecurityPrice is a security's closing price on a day
+type SecurityPrice struct {
+	SecurityID uuid.UUID `json:"security_id" gorm:"type:uuid;primaryKey;column:security_id"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;column:user_id"`
+	PricedOn   time.Time `json:"priced_on" gorm:"type:date;primaryKey;column:priced_on"`
+	Close      float64   `json:"close" gorm:"column:close"`
+}
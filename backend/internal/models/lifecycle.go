@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Goal lifecycle statuses
+const (
+	// GoalActive goals receive contributions, funding and surplus allocations
+	GoalActive = "active"
+	// GoalPaused goals are on hold: rules and allocations skip them and dashboards hide them
+	GoalPaused = "paused"
+	// GoalCompleted goals were reached
+	GoalCompleted = "completed"
+	// GoalArchived goals are kept for the record only and hidden from dashboards
+	GoalArchived = "archived"
+	// GoalAbandoned goals were given up; their balance can still be withdrawn
+	GoalAbandoned = "abandoned"
+)
+
+// GoalStatusChange records one move of a goal from a status to another
+type GoalStatusChange struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	GoalID     uuid.UUID `json:"goal_id" gorm:"type:uuid;column:goal_id"`
+	FromStatus string    `json:"from_status" gorm:"column:from_status"`
+	ToStatus   string    `json:"to_status" gorm:"column:to_status"`
+	Note       string    `json:"note" gorm:"column:note"`
+	ChangedAt  time.Time `json:"changed_at" gorm:"column:changed_at"`
+}
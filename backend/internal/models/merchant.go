@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Merchant pattern match types
+const (
+	MatchExact    = "exact"
+	MatchPrefix   = "prefix"
+	MatchContains = "contains"
+)
+
+// Merchant is a normalised payee that expenses with differing descriptions are linked to
+type Merchant struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	Name      string    `json:"name" gorm:"column:name"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// MerchantPattern maps normalised expense descriptions to a merchant
+type MerchantPattern struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	MerchantID uuid.UUID `json:"merchant_id" gorm:"type:uuid;index;column:merchant_id"`
+	Pattern    string    `json:"pattern" gorm:"column:pattern"`
+	MatchType  string    `json:"match_type" gorm:"column:match_type"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Classes of net worth item
+const (
+	NetWorthAsset     = "asset"
+	NetWorthLiability = "liability"
+)
+
+// NetWorthItem is an asset or liability the user values by hand, such as a house,
+// a car or a mortgage. Its worth on a day is its latest valuation up to that day.
+type NetWorthItem struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	Name        string    `json:"name" gorm:"column:name"`
+	Class       string    `json:"class" gorm:"column:class"`
+	Type        string    `json:"type" gorm:"column:type"`
+	Description string    `json:"description" gorm:"column:description"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// NetWorthValuation is what an asset was worth, or a liability owed, on a day
+type NetWorthValuation struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	ItemID    uuid.UUID `json:"item_id" gorm:"type:uuid;column:item_id"`
+	Value     float64   `json:"value" gorm:"column:value"`
+	ValuedOn  time.Time `json:"valued_on" gorm:"type:date;column:valued_on"`
+	Note      string    `json:"note" gorm:"column:note"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// NetWorthSnapshot keeps a user's net worth as it stood at the end of a month
+type NetWorthSnapshot struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	TakenOn     time.Time `json:"taken_on" gorm:"type:date;column:taken_on"`
+	Assets      float64   `json:"assets" gorm:"column:assets"`
+	Liabilities float64   `json:"liabilities" gorm:"column:liabilities"`
+	NetWorth    float64   `json:"net_worth" gorm:"column:net_worth"`
+	// Breakdown is a JSON array of NetWorthComponent
+	Breakdown string    `json:"breakdown" gorm:"type:jsonb;column:breakdown"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// NetWorthComponent is the total of one type of asset or liability
+type NetWorthComponent struct {
+	Class  string  `json:"class"`
+	Type   string  `json:"type"`
+	Amount float64 `json:"amount"`
+}
@@ -4,18 +4,21 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Note represents a note/document in the system
 type Note struct {
-	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
-	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
-	Title      string    `json:"title" gorm:"column:title"`
-	Content    string    `json:"content" gorm:"column:content"`
-	Category   string    `json:"category" gorm:"column:category"`
-	Tags       []string  `json:"tags" gorm:"type:text[];column:tags"`
-	IsFavorite bool      `json:"is_favorite" gorm:"column:is_favorite"`
-	IsArchived bool      `json:"is_archived" gorm:"column:is_archived"`
-	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt  time.Time `json:"updated_at" gorm:"column:updated_at"`
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID     uuid.UUID      `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	Title      string         `json:"title" gorm:"column:title"`
+	Content    string         `json:"content" gorm:"column:content"`
+	Category   string         `json:"category" gorm:"column:category"`
+	Tags       []string       `json:"tags" gorm:"type:text[];column:tags"`
+	IsFavorite bool           `json:"is_favorite" gorm:"column:is_favorite"`
+	IsArchived bool           `json:"is_archived" gorm:"column:is_archived"`
+	Version    int            `json:"version" gorm:"column:version;default:1"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at" gorm:"index;column:deleted_at"`
 }
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fields an expense rule condition can test
+const (
+	RuleFieldDescription = "description"
+	RuleFieldCategory    = "category"
+	RuleFieldAmount      = "amount"
+)
+
+// Operators an expense rule condition can use. Text operators ignore case;
+// amount operators compare numerically.
+const (
+	RuleOpContains   = "contains"
+	RuleOpEquals     = "equals"
+	RuleOpStartsWith = "starts_with"
+	RuleOpEndsWith   = "ends_with"
+	RuleOpGreater    = "gt"
+	RuleOpGreaterEq  = "gte"
+	RuleOpLess       = "lt"
+	RuleOpLessEq     = "lte"
+)
+
+// RuleCondition is one test an expense must pass for a rule to match
+type RuleCondition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// ExpenseRule sets the category and/or goal of expenses matching all of its conditions
+type ExpenseRule struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID   uuid.UUID `json:"user_id" gorm:"type:uuid;index;column:user_id"`
+	Name     string    `json:"name" gorm:"column:name"`
+	Priority int       `json:"priority" gorm:"column:priority"`
+	Enabled  bool      `json:"enabled" gorm:"column:enabled"`
+	// Conditions is a JSON array of RuleCondition objects
+	Conditions    string     `json:"conditions" gorm:"type:jsonb;column:conditions"`
+	SetCategory   *string    `json:"set_category" gorm:"column:set_category"`
+	SetGoalID     *uuid.UUID `json:"set_goal_id" gorm:"type:uuid;column:set_goal_id"`
+	ClearGoal     bool       `json:"clear_goal" gorm:"column:clear_goal"`
+	MatchCount    int        `json:"match_count" gorm:"column:match_count"`
+	LastMatchedAt *time.Time `json:"last_matched_at" gorm:"column:last_matched_at"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
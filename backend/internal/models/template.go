@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Variables a goal template's target formulas can use
+const (
+	// TemplateAvgExpenses is the user's average monthly expenses
+	TemplateAvgExpenses = "avg_monthly_expenses"
+	// TemplateAvgIncome is the user's average monthly income
+	TemplateAvgIncome = "avg_monthly_income"
+	// TemplateParent is the target of the goal's parent, for sub-goals only
+	TemplateParent = "parent"
+)
+
+// TemplateGoal is a goal a template creates, with its sub-goals
+type TemplateGoal struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category,omitempty"`
+	// Target is a formula for the target amount, such as "6 * avg_monthly_expenses"
+	Target string `json:"target"`
+	// Months sets the target date that many months after the goal is created; 0 leaves it open
+	Months   int            `json:"months,omitempty"`
+	Subgoals []TemplateGoal `json:"subgoals,omitempty"`
+}
+
+// GoalTemplate describes a goal hierarchy to create in one go. Built-in templates have no user.
+type GoalTemplate struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;column:id"`
+	UserID      *uuid.UUID `json:"user_id" gorm:"type:uuid;column:user_id"`
+	Name        string     `json:"name" gorm:"column:name"`
+	Description string     `json:"description" gorm:"column:description"`
+	// Definition is the main TemplateGoal as a JSON object
+	Definition string    `json:"definition" gorm:"type:jsonb;column:definition"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
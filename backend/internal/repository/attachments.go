@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttachmentRepositoryInterface stores the metadata of files attached to
+// expenses, incomes and notes; the files themselves are kept in storage
+type AttachmentRepositoryInterface interface {
+	CreateAttachment(attachment *models.Attachment) error
+	GetAttachment(id, userID uuid.UUID) (*models.Attachment, error)
+	// ListAttachments returns the attachments of one record, oldest first
+	ListAttachments(userID uuid.UUID, ownerType string, ownerID uuid.UUID) ([]models.Attachment, error)
+	DeleteAttachment(id, userID uuid.UUID) error
+	// ListOrphanedAttachments returns up to limit attachments, of every user, whose
+	// record no longer exists. Records in the trash still own their attachments.
+	ListOrphanedAttachments(limit int) ([]models.Attachment, error)
+	// Transaction runs fn against a repository whose writes are committed together,
+	// or not at all when fn returns an error
+	Transaction(fn func(repo AttachmentRepositoryInterface) error) error
+}
+
+// AttachmentRepository handles database operations for attachments
+type AttachmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// Transaction runs fn in a database transaction; GORM turns nested calls into savepoints
+func (r *AttachmentRepository) Transaction(fn func(repo AttachmentRepositoryInterface) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&AttachmentRepository{db: tx})
+	})
+}
+
+// conn returns the connection, an open transaction inside Transaction
+func (r *AttachmentRepository) conn() *gorm.DB {
+	return r.db
+}
+
+func (r *AttachmentRepository) CreateAttachment(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *AttachmentRepository) GetAttachment(id, userID uuid.UUID) (*models.Attachment, error) {
+	var attachment models.Attachment
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&attachment).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *AttachmentRepository) ListAttachments(userID uuid.UUID, ownerType string, ownerID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Where("user_id = ? AND owner_type = ? AND owner_id = ?", userID, ownerType, ownerID).
+		Order("created_at ASC").
+		Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) DeleteAttachment(id, userID uuid.UUID) error {
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Attachment{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *AttachmentRepository) ListOrphanedAttachments(limit int) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	// Soft-deleted rows are still present, so only purged owners leave attachments orphaned
+	err := r.db.Where(`(owner_type = ? AND NOT EXISTS (SELECT 1 FROM expenses WHERE expenses.id = attachments.owner_id))
+		OR (owner_type = ? AND NOT EXISTS (SELECT 1 FROM incomes WHERE incomes.id = attachments.owner_id))
+		OR (owner_type = ? AND NOT EXISTS (SELECT 1 FROM notes WHERE notes.id = attachments.owner_id))`,
+		models.AttachmentOwnerExpense, models.AttachmentOwnerIncome, models.AttachmentOwnerNote).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditFilter narrows an audit log query; zero values match everything
+type AuditFilter struct {
+	EntityType string
+	EntityID   *uuid.UUID
+	Actor      string
+	From       *time.Time // inclusive
+	To         *time.Time // exclusive
+	Limit      int
+}
+
+// AuditRepositoryInterface stores the append-only audit log
+type AuditRepositoryInterface interface {
+	CreateAuditEntry(entry *models.AuditEntry) error
+	ListAuditEntries(userID uuid.UUID, filter AuditFilter) ([]models.AuditEntry, error)
+	// InTransaction returns a repository that writes through tx, a repository handed
+	// to a Transaction callback, so entries commit or roll back with the changes they
+	// record. Repositories that cannot share tx's transaction return themselves.
+	InTransaction(tx interface{}) AuditRepositoryInterface
+}
+
+// connectionSource is implemented by the Postgres repositories, which lend their
+// connection, an open transaction inside Transaction, to the audit repository
+type connectionSource interface {
+	conn() *gorm.DB
+}
+
+// AuditRepository handles database operations for the audit log
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// CreateAuditEntry appends an entry to the audit log
+func (r *AuditRepository) CreateAuditEntry(entry *models.AuditEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// InTransaction returns a repository writing through the transaction tx runs in
+func (r *AuditRepository) InTransaction(tx interface{}) AuditRepositoryInterface {
+	if source, ok := tx.(connectionSource); ok {
+		return &AuditRepository{db: source.conn()}
+	}
+	return r
+}
+
+// ListAuditEntries returns the user's audit entries matching filter, newest first
+func (r *AuditRepository) ListAuditEntries(userID uuid.UUID, filter AuditFilter) ([]models.AuditEntry, error) {
+	q := r.db.Where("user_id = ?", userID)
+	if filter.EntityType != "" {
+		q = q.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		q = q.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.Actor != "" {
+		q = q.Where("actor = ?", filter.Actor)
+	}
+	if filter.From != nil {
+		q = q.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("created_at < ?", *filter.To)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	var entries []models.AuditEntry
+	if err := q.Order("created_at DESC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// CashFlowRepositoryInterface reads the transaction history that cash-flow forecasts are built from
+type CashFlowRepositoryInterface interface {
+	// ListIncomesBetween returns the incomes received in [from, to), oldest first
+	ListIncomesBetween(userID uuid.UUID, from, to time.Time) ([]models.Income, error)
+	// ListExpensesBetween returns the expenses spent in [from, to), oldest first
+	ListExpensesBetween(userID uuid.UUID, from, to time.Time) ([]models.Expense, error)
+	// GetBalance returns income minus expenses and goal contributions dated before the cutoff
+	GetBalance(userID uuid.UUID, before time.Time) (float64, error)
+}
+
+func (r *FinanceRepository) ListIncomesBetween(userID uuid.UUID, from, to time.Time) ([]models.Income, error) {
+	var items []models.Income
+	err := r.db.Where("user_id = ? AND received_at >= ? AND received_at < ?", userID, from, to).
+		Order("received_at ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *FinanceRepository) ListExpensesBetween(userID uuid.UUID, from, to time.Time) ([]models.Expense, error) {
+	var items []models.Expense
+	err := r.db.Where("user_id = ? AND spent_at >= ? AND spent_at < ?", userID, from, to).
+		Order("spent_at ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *FinanceRepository) GetBalance(userID uuid.UUID, before time.Time) (float64, error) {
+	var income, expenses, contributions float64
+	if err := r.db.Model(&models.Income{}).
+		Where("user_id = ? AND received_at < ?", userID, before).
+		Select("COALESCE(SUM(amount), 0)").Scan(&income).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.Model(&models.Expense{}).
+		Where("user_id = ? AND spent_at < ?", userID, before).
+		Select("COALESCE(SUM(amount), 0)").Scan(&expenses).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.Model(&models.GoalContribution{}).
+		Where("user_id = ? AND contributed_at < ?", userID, before).
+		Select("COALESCE(SUM(amount), 0)").Scan(&contributions).Error; err != nil {
+		return 0, err
+	}
+	return income - expenses - contributions, nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"errors"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCategoryExists is returned when a sibling category already has the name, ignoring case
+var ErrCategoryExists = errors.New("category already exists")
+
+// ErrCategoryInUse is returned when deleting a category that expenses or sub-categories still reference
+var ErrCategoryInUse = errors.New("category is in use")
+
+// CategoryRepositoryInterface stores expense categories. Expenses keep a copy of
+// their category's name, which renames and merges keep in sync.
+type CategoryRepositoryInterface interface {
+	// CreateCategory returns ErrCategoryExists when a sibling has the same name
+	CreateCategory(cat *models.Category) error
+	GetCategory(id, userID uuid.UUID) (*models.Category, error)
+	// ListCategories returns the user's categories ordered by name
+	ListCategories(userID uuid.UUID) ([]models.Category, error)
+	// UpdateCategory renames or moves a category; a new name is copied to its expenses
+	UpdateCategory(id, userID uuid.UUID, updates map[string]interface{}) error
+	// DeleteCategory returns ErrCategoryInUse while expenses, including deleted
+	// ones, or sub-categories reference the category
+	DeleteCategory(id, userID uuid.UUID) error
+	// MergeCategories moves the expenses and sub-categories of the source categories
+	// to the target and deletes the sources, returning the number of expenses moved
+	MergeCategories(userID, targetID uuid.UUID, sourceIDs []uuid.UUID) (int64, error)
+}
+
+func (r *FinanceRepository) CreateCategory(cat *models.Category) error {
+	tx := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(cat)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return ErrCategoryExists
+	}
+	return nil
+}
+
+func (r *FinanceRepository) GetCategory(id, userID uuid.UUID) (*models.Category, error) {
+	var cat models.Category
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&cat).Error; err != nil {
+		return nil, err
+	}
+	return &cat, nil
+}
+
+func (r *FinanceRepository) ListCategories(userID uuid.UUID) ([]models.Category, error) {
+	var cats []models.Category
+	if err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&cats).Error; err != nil {
+		return nil, err
+	}
+	return cats, nil
+}
+
+func (r *FinanceRepository) UpdateCategory(id, userID uuid.UUID, updates map[string]interface{}) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Category{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		name, ok := updates["name"]
+		if !ok {
+			return nil
+		}
+		// Deleted expenses are renamed too so a restore shows the current name
+		return tx.Unscoped().Model(&models.Expense{}).
+			Where("user_id = ? AND category_id = ?", userID, id).
+			Updates(map[string]interface{}{"category": name, "version": gorm.Expr("version + 1")}).Error
+	})
+}
+
+func (r *FinanceRepository) DeleteCategory(id, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var expenses, children int64
+		if err := tx.Unscoped().Model(&models.Expense{}).Where("user_id = ? AND category_id = ?", userID, id).Count(&expenses).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).Where("user_id = ? AND parent_id = ?", userID, id).Count(&children).Error; err != nil {
+			return err
+		}
+		if expenses > 0 || children > 0 {
+			return ErrCategoryInUse
+		}
+		res := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Category{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *FinanceRepository) MergeCategories(userID, targetID uuid.UUID, sourceIDs []uuid.UUID) (int64, error) {
+	var moved int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var target models.Category
+		if err := tx.Where("id = ? AND user_id = ?", targetID, userID).First(&target).Error; err != nil {
+			return err
+		}
+		var found int64
+		if err := tx.Model(&models.Category{}).Where("user_id = ? AND id IN ?", userID, sourceIDs).Count(&found).Error; err != nil {
+			return err
+		}
+		if found != int64(len(sourceIDs)) {
+			return gorm.ErrRecordNotFound
+		}
+
+		res := tx.Unscoped().Model(&models.Expense{}).
+			Where("user_id = ? AND category_id IN ?", userID, sourceIDs).
+			Updates(map[string]interface{}{"category_id": targetID, "category": target.Name, "version": gorm.Expr("version + 1")})
+		if res.Error != nil {
+			return res.Error
+		}
+		moved = res.RowsAffected
+
+		if err := tx.Model(&models.Category{}).
+			Where("user_id = ? AND parent_id IN ?", userID, sourceIDs).
+			Update("parent_id", targetID).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND id IN ?", userID, sourceIDs).Delete(&models.Category{}).Error
+	})
+	return moved, err
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DebtRepositoryInterface stores loans and credit cards
+type DebtRepositoryInterface interface {
+	CreateDebt(debt *models.Debt) error
+	GetDebt(id, userID uuid.UUID) (*models.Debt, error)
+	// ListDebts returns the user's debts oldest first
+	ListDebts(userID uuid.UUID) ([]models.Debt, error)
+	UpdateDebt(id, userID uuid.UUID, updates map[string]interface{}) error
+	// DeleteDebt removes a debt and unlinks its payments, which are kept as expenses
+	DeleteDebt(id, userID uuid.UUID) error
+	// ListDebtPayments returns the expenses linked to any of the user's debts, oldest first
+	ListDebtPayments(userID uuid.UUID) ([]models.Expense, error)
+}
+
+func (r *FinanceRepository) CreateDebt(debt *models.Debt) error {
+	return r.db.Create(debt).Error
+}
+
+func (r *FinanceRepository) GetDebt(id, userID uuid.UUID) (*models.Debt, error) {
+	var debt models.Debt
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&debt).Error; err != nil {
+		return nil, err
+	}
+	return &debt, nil
+}
+
+func (r *FinanceRepository) ListDebts(userID uuid.UUID) ([]models.Debt, error) {
+	var debts []models.Debt
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&debts).Error; err != nil {
+		return nil, err
+	}
+	return debts, nil
+}
+
+func (r *FinanceRepository) UpdateDebt(id, userID uuid.UUID, updates map[string]interface{}) error {
+	tx := r.db.Model(&models.Debt{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) DeleteDebt(id, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Soft-deleted expenses are unlinked too so a restore does not point at a missing debt
+		if err := tx.Unscoped().Model(&models.Expense{}).
+			Where("debt_id = ? AND user_id = ?", id, userID).
+			Updates(map[string]interface{}{"debt_id": nil, "version": gorm.Expr("version + 1")}).Error; err != nil {
+			return err
+		}
+		res := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Debt{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *FinanceRepository) ListDebtPayments(userID uuid.UUID) ([]models.Expense, error) {
+	var expenses []models.Expense
+	err := r.db.Where("user_id = ? AND debt_id IS NOT NULL", userID).
+		Order("spent_at ASC, created_at ASC").
+		Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
@@ -1,12 +1,14 @@
 package repository
 
 import (
+	"errors"
 	"time"
 
 	"finance-management/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // FinanceRepositoryInterface defines CRUD and aggregation operations for finance data
@@ -15,23 +17,79 @@ type FinanceRepositoryInterface interface {
 	CreateExpense(expense *models.Expense) error
 	CreateGoal(goal *models.Goal) error
 	CreateGoalContribution(contrib *models.GoalContribution) error
+	GetGoalContribution(id, userID uuid.UUID) (*models.GoalContribution, error)
+	UpdateGoalContribution(id, userID uuid.UUID, updates map[string]interface{}) error
+	DeleteGoalContribution(id, userID uuid.UUID) error
+	// SumGoalContributions totals each goal's contributions dated in [from, to)
+	SumGoalContributions(userID uuid.UUID, from, to time.Time) (map[uuid.UUID]float64, error)
 	GetMonthlySummary(userID uuid.UUID, year int, month int) (*models.MonthlySummary, error)
-	CreateCategory(cat *models.Category) error
-	ListCategories(userID uuid.UUID) ([]models.Category, error)
 	ListGoalsWithProgress(userID uuid.UUID) ([]GoalWithProgress, error)
 	ListIncomes(userID uuid.UUID, limit int) ([]models.Income, error)
 	ListExpenses(userID uuid.UUID, limit int) ([]models.Expense, error)
-	UpdateIncome(id, userID uuid.UUID, updates map[string]interface{}) error
-	UpdateExpense(id, userID uuid.UUID, updates map[string]interface{}) error
+	GetIncome(id, userID uuid.UUID) (*models.Income, error)
+	GetExpense(id, userID uuid.UUID) (*models.Expense, error)
+	GetGoal(id, userID uuid.UUID) (*models.Goal, error)
+	// LockGoal gets a goal and, inside a transaction, locks its row until the transaction
+	// ends, so writes that depend on what the goal holds run one at a time
+	LockGoal(id, userID uuid.UUID) (*models.Goal, error)
+	// LockAllocations takes, inside a transaction, a lock on the user's allocations that
+	// is held until the transaction ends, so writes planned across all of the user's
+	// goals run one at a time
+	LockAllocations(userID uuid.UUID) error
+	UpdateIncome(id, userID uuid.UUID, version int, updates map[string]interface{}) error
+	UpdateExpense(id, userID uuid.UUID, version int, updates map[string]interface{}) error
 	DeleteIncome(id, userID uuid.UUID) error
 	DeleteExpense(id, userID uuid.UUID) error
-	UpdateGoal(id, userID uuid.UUID, updates map[string]interface{}) error
+	UpdateGoal(id, userID uuid.UUID, version int, updates map[string]interface{}) error
 	DeleteGoal(id, userID uuid.UUID) error
-	// Goal categories and hierarchical goals
-	ListGoalCategories() ([]models.GoalCategory, error)
+	// Hierarchical goals
 	ListMainGoalsWithSubgoals(userID uuid.UUID) ([]models.GoalWithSubgoals, error)
 	CreateGoalExpense(goalExpense *models.GoalExpense) error
 	ListGoalExpenses(userID uuid.UUID, goalID uuid.UUID) ([]models.GoalExpense, error)
+	// Trash: deletes are soft until the row is purged
+	ListTrash(userID uuid.UUID) (*FinanceTrash, error)
+	RestoreIncome(id, userID uuid.UUID) error
+	RestoreExpense(id, userID uuid.UUID) error
+	RestoreGoal(id, userID uuid.UUID) error
+	PurgeDeleted(before time.Time) (int64, error)
+	// Expense categories, which may be nested
+	CategoryRepositoryInterface
+	// Built-in and user-defined goal categories
+	GoalCategoryRepositoryInterface
+	// Built-in and user-defined goal templates
+	GoalTemplateRepositoryInterface
+	// Merchants and the patterns that link expense descriptions to them
+	MerchantRepositoryInterface
+	// Incomes and expenses filtered by their tags
+	TagRepositoryInterface
+	// Transaction history and balances for cash-flow forecasts
+	CashFlowRepositoryInterface
+	// Rules that generate goal contributions automatically
+	FundingRuleRepositoryInterface
+	// History of goal lifecycle status changes
+	GoalStatusRepositoryInterface
+	// Loans and credit cards and the expenses that pay them
+	DebtRepositoryInterface
+	// Manually valued assets and liabilities and monthly net worth snapshots
+	NetWorthRepositoryInterface
+	// Securities held with their lots, sales, prices and dividends
+	InvestmentRepositoryInterface
+	// Transaction runs fn against a repository whose writes are committed together,
+	// or rolled back when fn returns an error. Nested calls use savepoints.
+	Transaction(fn func(repo FinanceRepositoryInterface) error) error
+}
+
+// ErrParentGoalDeleted is returned when restoring a sub-goal whose parent is still in the trash
+var ErrParentGoalDeleted = errors.New("parent goal is deleted")
+
+// ErrVersionConflict is returned when an update names a version the row no longer has
+var ErrVersionConflict = errors.New("version conflict")
+
+// FinanceTrash holds a user's soft-deleted records, most recently deleted first
+type FinanceTrash struct {
+	Incomes  []models.Income
+	Expenses []models.Expense
+	Goals    []models.Goal
 }
 
 type FinanceRepository struct {
@@ -56,16 +114,49 @@ func (r *FinanceRepository) CreateGoalContribution(contrib *models.GoalContribut
 	return r.db.Create(contrib).Error
 }
 
-func (r *FinanceRepository) CreateCategory(cat *models.Category) error {
-	return r.db.Create(cat).Error
+func (r *FinanceRepository) GetGoalContribution(id, userID uuid.UUID) (*models.GoalContribution, error) {
+	var contrib models.GoalContribution
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&contrib).Error; err != nil {
+		return nil, err
+	}
+	return &contrib, nil
 }
 
-func (r *FinanceRepository) ListCategories(userID uuid.UUID) ([]models.Category, error) {
-	var cats []models.Category
-	if err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&cats).Error; err != nil {
-		return nil, err
+func (r *FinanceRepository) UpdateGoalContribution(id, userID uuid.UUID, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	tx := r.db.Model(&models.GoalContribution{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) DeleteGoalContribution(id, userID uuid.UUID) error {
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.GoalContribution{})
+	if tx.Error != nil {
+		return tx.Error
 	}
-	return cats, nil
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Transaction runs fn in a database transaction; GORM turns nested calls into savepoints
+func (r *FinanceRepository) Transaction(fn func(repo FinanceRepositoryInterface) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&FinanceRepository{db: tx})
+	})
+}
+
+// conn returns the connection, an open transaction inside Transaction
+func (r *FinanceRepository) conn() *gorm.DB {
+	return r.db
 }
 
 type GoalWithProgress struct {
@@ -103,6 +194,26 @@ func (r *FinanceRepository) ListGoalsWithProgress(userID uuid.UUID) ([]GoalWithP
 	return result, nil
 }
 
+func (r *FinanceRepository) SumGoalContributions(userID uuid.UUID, from, to time.Time) (map[uuid.UUID]float64, error) {
+	var rows []struct {
+		GoalID uuid.UUID
+		Total  float64
+	}
+	err := r.db.Model(&models.GoalContribution{}).
+		Where("user_id = ? AND contributed_at >= ? AND contributed_at < ?", userID, from, to).
+		Select("goal_id, COALESCE(SUM(amount),0) AS total").
+		Group("goal_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	sums := make(map[uuid.UUID]float64, len(rows))
+	for _, row := range rows {
+		sums[row.GoalID] = row.Total
+	}
+	return sums, nil
+}
+
 func (r *FinanceRepository) ListIncomes(userID uuid.UUID, limit int) ([]models.Income, error) {
 	var items []models.Income
 	q := r.db.Where("user_id = ?", userID).Order("received_at DESC")
@@ -127,32 +238,89 @@ func (r *FinanceRepository) ListExpenses(userID uuid.UUID, limit int) ([]models.
 	return items, nil
 }
 
-func (r *FinanceRepository) UpdateIncome(id, userID uuid.UUID, updates map[string]interface{}) error {
-	if len(updates) == 0 {
-		return nil
+func (r *FinanceRepository) GetIncome(id, userID uuid.UUID) (*models.Income, error) {
+	var income models.Income
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&income).Error; err != nil {
+		return nil, err
 	}
-	tx := r.db.Model(&models.Income{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
-	if tx.Error != nil {
-		return tx.Error
+	return &income, nil
+}
+
+func (r *FinanceRepository) GetExpense(id, userID uuid.UUID) (*models.Expense, error) {
+	var expense models.Expense
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&expense).Error; err != nil {
+		return nil, err
 	}
-	if tx.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	return &expense, nil
+}
+
+func (r *FinanceRepository) GetGoal(id, userID uuid.UUID) (*models.Goal, error) {
+	var goal models.Goal
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&goal).Error; err != nil {
+		return nil, err
 	}
-	return nil
+	return &goal, nil
+}
+
+func (r *FinanceRepository) LockGoal(id, userID uuid.UUID) (*models.Goal, error) {
+	var goal models.Goal
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&goal).Error
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
 }
 
-func (r *FinanceRepository) UpdateExpense(id, userID uuid.UUID, updates map[string]interface{}) error {
+func (r *FinanceRepository) LockAllocations(userID uuid.UUID) error {
+	return r.db.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "allocations:"+userID.String()).Error
+}
+
+func (r *FinanceRepository) UpdateIncome(id, userID uuid.UUID, version int, updates map[string]interface{}) error {
 	if len(updates) == 0 {
 		return nil
 	}
-	tx := r.db.Model(&models.Expense{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+	return updateVersioned(r.db, &models.Income{}, id, userID, version, updates)
+}
+
+func (r *FinanceRepository) UpdateExpense(id, userID uuid.UUID, version int, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	return updateVersioned(r.db, &models.Expense{}, id, userID, version, updates)
+}
+
+// updateVersioned applies updates to the user's row and bumps its version. A
+// non-zero version must match the stored one or ErrVersionConflict is returned.
+func updateVersioned(db *gorm.DB, model interface{}, id, userID uuid.UUID, version int, updates map[string]interface{}) error {
+	values := make(map[string]interface{}, len(updates)+1)
+	for column, value := range updates {
+		values[column] = value
+	}
+	values["version"] = gorm.Expr("version + 1")
+
+	query := db.Model(model).Where("id = ? AND user_id = ?", id, userID)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	}
+	tx := query.Updates(values)
 	if tx.Error != nil {
 		return tx.Error
 	}
-	if tx.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	if tx.RowsAffected > 0 {
+		return nil
 	}
-	return nil
+
+	// Nothing matched: tell a stale version apart from a missing row
+	var count int64
+	if err := db.Model(model).Where("id = ? AND user_id = ?", id, userID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrVersionConflict
+	}
+	return gorm.ErrRecordNotFound
 }
 
 func (r *FinanceRepository) DeleteIncome(id, userID uuid.UUID) error {
@@ -177,22 +345,74 @@ func (r *FinanceRepository) DeleteExpense(id, userID uuid.UUID) error {
 	return nil
 }
 
-func (r *FinanceRepository) UpdateGoal(id, userID uuid.UUID, updates map[string]interface{}) error {
+func (r *FinanceRepository) UpdateGoal(id, userID uuid.UUID, version int, updates map[string]interface{}) error {
 	if len(updates) == 0 {
 		return nil
 	}
-	tx := r.db.Model(&models.Goal{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
-	if tx.Error != nil {
-		return tx.Error
+	return updateVersioned(r.db, &models.Goal{}, id, userID, version, updates)
+}
+
+// DeleteGoal moves the goal, its sub-goals and their contributions to the trash.
+// They share one deleted_at so RestoreGoal can bring back exactly this cascade.
+func (r *FinanceRepository) DeleteGoal(id, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var goal models.Goal
+		if err := tx.Where("id = ? AND user_id = ?", id, userID).First(&goal).Error; err != nil {
+			return err
+		}
+		ids, err := goalTree(tx, id, "deleted_at IS NULL")
+		if err != nil {
+			return err
+		}
+		// Postgres keeps microseconds, so truncate to compare equal on restore
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		if err := tx.Model(&models.Goal{}).Where("id IN ?", ids).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.GoalContribution{}).Where("goal_id IN ?", ids).Update("deleted_at", now).Error
+	})
+}
+
+// goalTree returns id and the IDs of all sub-goals below it whose rows match cond
+func goalTree(tx *gorm.DB, id uuid.UUID, cond string, args ...interface{}) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	query := `WITH RECURSIVE tree AS (
+		SELECT id FROM goals WHERE id = ?
+		UNION ALL
+		SELECT g.id FROM goals g JOIN tree t ON g.parent_goal_id = t.id WHERE g.` + cond + `
+	) SELECT id FROM tree`
+	if err := tx.Raw(query, append([]interface{}{id}, args...)...).Scan(&ids).Error; err != nil {
+		return nil, err
 	}
-	if tx.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	return ids, nil
+}
+
+// ListTrash returns the user's soft-deleted incomes, expenses and goals
+func (r *FinanceRepository) ListTrash(userID uuid.UUID) (*FinanceTrash, error) {
+	trash := &FinanceTrash{}
+	deleted := r.db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID).Order("deleted_at DESC").Session(&gorm.Session{})
+	if err := deleted.Find(&trash.Incomes).Error; err != nil {
+		return nil, err
 	}
-	return nil
+	if err := deleted.Find(&trash.Expenses).Error; err != nil {
+		return nil, err
+	}
+	if err := deleted.Find(&trash.Goals).Error; err != nil {
+		return nil, err
+	}
+	return trash, nil
 }
 
-func (r *FinanceRepository) DeleteGoal(id, userID uuid.UUID) error {
-	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Goal{})
+func (r *FinanceRepository) RestoreIncome(id, userID uuid.UUID) error {
+	return restoreRow(r.db, &models.Income{}, id, userID)
+}
+
+func (r *FinanceRepository) RestoreExpense(id, userID uuid.UUID) error {
+	return restoreRow(r.db, &models.Expense{}, id, userID)
+}
+
+func restoreRow(db *gorm.DB, model interface{}, id, userID uuid.UUID) error {
+	tx := db.Unscoped().Model(model).Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).Update("deleted_at", nil)
 	if tx.Error != nil {
 		return tx.Error
 	}
@@ -202,6 +422,52 @@ func (r *FinanceRepository) DeleteGoal(id, userID uuid.UUID) error {
 	return nil
 }
 
+// RestoreGoal restores a trashed goal together with the sub-goals and contributions
+// that were deleted with it. Children trashed separately stay in the trash.
+func (r *FinanceRepository) RestoreGoal(id, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var goal models.Goal
+		if err := tx.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).First(&goal).Error; err != nil {
+			return err
+		}
+		if goal.ParentGoalID != nil {
+			if err := tx.Where("id = ?", *goal.ParentGoalID).First(&models.Goal{}).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrParentGoalDeleted
+				}
+				return err
+			}
+		}
+		deletedAt := goal.DeletedAt.Time
+		ids, err := goalTree(tx, id, "deleted_at = ?", deletedAt)
+		if err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Goal{}).Where("id IN ? AND deleted_at = ?", ids, deletedAt).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.GoalContribution{}).Where("goal_id IN ? AND deleted_at = ?", ids, deletedAt).Update("deleted_at", nil).Error
+	})
+}
+
+// PurgeDeleted permanently removes rows of every user that were trashed before the cutoff
+// and returns how many rows were removed
+func (r *FinanceRepository) PurgeDeleted(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Goals last: their foreign keys cascade to anything still referencing them
+		for _, model := range []interface{}{&models.GoalContribution{}, &models.Expense{}, &models.Income{}, &models.Goal{}} {
+			res := tx.Unscoped().Where("deleted_at < ?", before).Delete(model)
+			if res.Error != nil {
+				return res.Error
+			}
+			purged += res.RowsAffected
+		}
+		return nil
+	})
+	return purged, err
+}
+
 // GetMonthlySummary aggregates income, expenses, savings and breakdowns for a given month
 func (r *FinanceRepository) GetMonthlySummary(userID uuid.UUID, year int, month int) (*models.MonthlySummary, error) {
 	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
@@ -211,8 +477,12 @@ func (r *FinanceRepository) GetMonthlySummary(userID uuid.UUID, year int, month
 		Year:              year,
 		Month:             month,
 		CategoryBreakdown: map[string]float64{},
+		CategorySpending:  map[uuid.UUID]float64{},
 		GoalSpending:      map[uuid.UUID]float64{},
 		GoalContributions: map[uuid.UUID]float64{},
+		MerchantSpending:  map[uuid.UUID]float64{},
+		TagIncome:         map[string]float64{},
+		TagSpending:       map[string]float64{},
 	}
 
 	// Total income
@@ -252,6 +522,23 @@ func (r *FinanceRepository) GetMonthlySummary(userID uuid.UUID, year int, month
 		summary.CategoryBreakdown[row.Category] = row.Total
 	}
 
+	// Spending per category ID, which the service rolls up into parent categories
+	type catIDRow struct {
+		CategoryID uuid.UUID
+		Total      float64
+	}
+	var catIDRows []catIDRow
+	if err := r.db.Model(&models.Expense{}).
+		Where("user_id = ? AND spent_at >= ? AND spent_at < ? AND category_id IS NOT NULL", userID, start, end).
+		Select("category_id, COALESCE(SUM(amount), 0) as total").
+		Group("category_id").
+		Scan(&catIDRows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range catIDRows {
+		summary.CategorySpending[row.CategoryID] = row.Total
+	}
+
 	// Goal-linked spending (expenses with goal_id)
 	type goalRow struct {
 		GoalID uuid.UUID
@@ -287,16 +574,54 @@ func (r *FinanceRepository) GetMonthlySummary(userID uuid.UUID, year int, month
 	// Define savings as total contributions for now (can evolve later)
 	summary.TotalSavings = totalContrib
 
-	return summary, nil
-}
+	// Merchant spending (expenses linked to a merchant)
+	type merchantRow struct {
+		MerchantID uuid.UUID
+		Total      float64
+	}
+	var merchantRows []merchantRow
+	if err := r.db.Model(&models.Expense{}).
+		Where("user_id = ? AND spent_at >= ? AND spent_at < ? AND merchant_id IS NOT NULL", userID, start, end).
+		Select("merchant_id, COALESCE(SUM(amount), 0) as total").
+		Group("merchant_id").
+		Scan(&merchantRows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range merchantRows {
+		summary.MerchantSpending[row.MerchantID] = row.Total
+	}
 
-// ListGoalCategories returns all predefined goal categories
-func (r *FinanceRepository) ListGoalCategories() ([]models.GoalCategory, error) {
-	var categories []models.GoalCategory
-	if err := r.db.Order("name ASC").Find(&categories).Error; err != nil {
+	// Per-tag totals; a row with several tags counts towards each of them
+	type tagRow struct {
+		Tag   string
+		Total float64
+	}
+	var tagRows []tagRow
+	if err := r.db.Model(&models.Income{}).
+		Joins("CROSS JOIN UNNEST(incomes.tags) AS tag").
+		Where("user_id = ? AND received_at >= ? AND received_at < ?", userID, start, end).
+		Select("tag, COALESCE(SUM(amount), 0) as total").
+		Group("tag").
+		Scan(&tagRows).Error; err != nil {
 		return nil, err
 	}
-	return categories, nil
+	for _, row := range tagRows {
+		summary.TagIncome[row.Tag] = row.Total
+	}
+	tagRows = nil
+	if err := r.db.Model(&models.Expense{}).
+		Joins("CROSS JOIN UNNEST(expenses.tags) AS tag").
+		Where("user_id = ? AND spent_at >= ? AND spent_at < ?", userID, start, end).
+		Select("tag, COALESCE(SUM(amount), 0) as total").
+		Group("tag").
+		Scan(&tagRows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range tagRows {
+		summary.TagSpending[row.Tag] = row.Total
+	}
+
+	return summary, nil
 }
 
 // ListMainGoalsWithSubgoals returns main goals with their sub-goals
@@ -332,7 +657,10 @@ func (r *FinanceRepository) CreateGoalExpense(goalExpense *models.GoalExpense) e
 // ListGoalExpenses returns expenses associated with a specific goal
 func (r *FinanceRepository) ListGoalExpenses(userID uuid.UUID, goalID uuid.UUID) ([]models.GoalExpense, error) {
 	var goalExpenses []models.GoalExpense
-	if err := r.db.Where("user_id = ? AND goal_id = ?", userID, goalID).Order("created_at DESC").Find(&goalExpenses).Error; err != nil {
+	// Links to trashed expenses are hidden until the expense is restored
+	if err := r.db.Where("user_id = ? AND goal_id = ?", userID, goalID).
+		Where("expense_id IN (SELECT id FROM expenses WHERE deleted_at IS NULL)").
+		Order("created_at DESC").Find(&goalExpenses).Error; err != nil {
 		return nil, err
 	}
 	return goalExpenses, nil
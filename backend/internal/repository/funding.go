@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FundingRuleRepositoryInterface stores the rules that fund goals automatically
+type FundingRuleRepositoryInterface interface {
+	CreateFundingRule(rule *models.GoalFundingRule) error
+	GetFundingRule(id, userID uuid.UUID) (*models.GoalFundingRule, error)
+	// ListFundingRules returns the user's rules oldest first
+	ListFundingRules(userID uuid.UUID) ([]models.GoalFundingRule, error)
+	UpdateFundingRule(id, userID uuid.UUID, updates map[string]interface{}) error
+	// DeleteFundingRule removes a rule; the contributions it generated are kept
+	DeleteFundingRule(id, userID uuid.UUID) error
+	// ListScheduledFundingRules returns every user's enabled fixed_monthly rules
+	ListScheduledFundingRules() ([]models.GoalFundingRule, error)
+	// ClaimScheduledFunding moves a rule's last funded date from from to to and reports
+	// whether it did. It does nothing when the date is no longer from because another
+	// run funded the rule first.
+	ClaimScheduledFunding(id, userID uuid.UUID, from *time.Time, to time.Time) (bool, error)
+}
+
+func (r *FinanceRepository) CreateFundingRule(rule *models.GoalFundingRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *FinanceRepository) GetFundingRule(id, userID uuid.UUID) (*models.GoalFundingRule, error) {
+	var rule models.GoalFundingRule
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *FinanceRepository) ListFundingRules(userID uuid.UUID) ([]models.GoalFundingRule, error) {
+	var rules []models.GoalFundingRule
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *FinanceRepository) UpdateFundingRule(id, userID uuid.UUID, updates map[string]interface{}) error {
+	tx := r.db.Model(&models.GoalFundingRule{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) DeleteFundingRule(id, userID uuid.UUID) error {
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.GoalFundingRule{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) ListScheduledFundingRules() ([]models.GoalFundingRule, error) {
+	var rules []models.GoalFundingRule
+	err := r.db.Where("enabled AND type = ?", models.FundingFixedMonthly).
+		Order("created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *FinanceRepository) ClaimScheduledFunding(id, userID uuid.UUID, from *time.Time, to time.Time) (bool, error) {
+	tx := r.db.Model(&models.GoalFundingRule{}).
+		Where("id = ? AND user_id = ? AND last_funded_on IS NOT DISTINCT FROM ?", id, userID, from).
+		Update("last_funded_on", to)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected > 0, nil
+}
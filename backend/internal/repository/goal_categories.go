@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrGoalCategoryExists is returned when a built-in goal category or another of the
+// user's own already has the name, ignoring case
+var ErrGoalCategoryExists = errors.New("goal category already exists")
+
+// GoalCategoryRepositoryInterface stores goal categories: the built-ins, which have
+// no user, and each user's own. Goals keep a copy of their category's name, which
+// renames keep in sync.
+type GoalCategoryRepositoryInterface interface {
+	// CreateGoalCategory returns ErrGoalCategoryExists when the name is taken
+	CreateGoalCategory(cat *models.GoalCategory) error
+	// GetGoalCategory returns a built-in category or one of the user's own
+	GetGoalCategory(id, userID uuid.UUID) (*models.GoalCategory, error)
+	// ListGoalCategories returns the built-in categories and the user's own,
+	// hidden ones included, ordered by name
+	ListGoalCategories(userID uuid.UUID) ([]models.GoalCategory, error)
+	// UpdateGoalCategory edits one of the user's own categories; built-ins are not
+	// found. A new name is copied to the goals in the category.
+	UpdateGoalCategory(id, userID uuid.UUID, updates map[string]interface{}) error
+}
+
+func (r *FinanceRepository) CreateGoalCategory(cat *models.GoalCategory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := goalCategoryNameFree(tx, *cat.UserID, cat.ID, cat.Name); err != nil {
+			return err
+		}
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(cat)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrGoalCategoryExists
+		}
+		return nil
+	})
+}
+
+func (r *FinanceRepository) GetGoalCategory(id, userID uuid.UUID) (*models.GoalCategory, error) {
+	var cat models.GoalCategory
+	if err := r.db.Where("id = ? AND (user_id IS NULL OR user_id = ?)", id, userID).First(&cat).Error; err != nil {
+		return nil, err
+	}
+	return &cat, nil
+}
+
+func (r *FinanceRepository) ListGoalCategories(userID uuid.UUID) ([]models.GoalCategory, error) {
+	var categories []models.GoalCategory
+	if err := r.db.Where("user_id IS NULL OR user_id = ?", userID).Order("name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *FinanceRepository) UpdateGoalCategory(id, userID uuid.UUID, updates map[string]interface{}) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		name, renamed := updates["name"].(string)
+		if renamed {
+			if err := goalCategoryNameFree(tx, userID, id, name); err != nil {
+				return err
+			}
+		}
+		res := tx.Model(&models.GoalCategory{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if !renamed {
+			return nil
+		}
+		// Deleted goals are renamed too so a restore shows the current name
+		return tx.Unscoped().Model(&models.Goal{}).
+			Where("user_id = ? AND category_id = ?", userID, id).
+			Updates(map[string]interface{}{"category": name, "version": gorm.Expr("version + 1")}).Error
+	})
+}
+
+// goalCategoryNameFree returns ErrGoalCategoryExists when a built-in category or
+// another of the user's own is named name
+func goalCategoryNameFree(tx *gorm.DB, userID, id uuid.UUID, name string) error {
+	var taken int64
+	err := tx.Model(&models.GoalCategory{}).
+		Where("(user_id IS NULL OR user_id = ?) AND id <> ? AND LOWER(name) = LOWER(?)", userID, id, name).
+		Count(&taken).Error
+	if err != nil {
+		return err
+	}
+	if taken > 0 {
+		return ErrGoalCategoryExists
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GoalTemplateRepositoryInterface stores goal templates: the built-ins, which have
+// no user, and each user's own
+type GoalTemplateRepositoryInterface interface {
+	CreateGoalTemplate(template *models.GoalTemplate) error
+	// GetGoalTemplate returns a built-in template or one of the user's own
+	GetGoalTemplate(id, userID uuid.UUID) (*models.GoalTemplate, error)
+	// ListGoalTemplates returns the built-in templates and the user's own ordered by name
+	ListGoalTemplates(userID uuid.UUID) ([]models.GoalTemplate, error)
+	// UpdateGoalTemplate and DeleteGoalTemplate only find the user's own templates
+	UpdateGoalTemplate(id, userID uuid.UUID, updates map[string]interface{}) error
+	DeleteGoalTemplate(id, userID uuid.UUID) error
+}
+
+func (r *FinanceRepository) CreateGoalTemplate(template *models.GoalTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *FinanceRepository) GetGoalTemplate(id, userID uuid.UUID) (*models.GoalTemplate, error) {
+	var template models.GoalTemplate
+	if err := r.db.Where("id = ? AND (user_id IS NULL OR user_id = ?)", id, userID).First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *FinanceRepository) ListGoalTemplates(userID uuid.UUID) ([]models.GoalTemplate, error) {
+	var templates []models.GoalTemplate
+	if err := r.db.Where("user_id IS NULL OR user_id = ?", userID).Order("name ASC, created_at ASC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *FinanceRepository) UpdateGoalTemplate(id, userID uuid.UUID, updates map[string]interface{}) error {
+	tx := r.db.Model(&models.GoalTemplate{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) DeleteGoalTemplate(id, userID uuid.UUID) error {
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.GoalTemplate{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
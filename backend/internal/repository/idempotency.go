@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrIdempotencyKeyExists is returned when reserving a key the user has already used
+var ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+
+// IdempotencyRepositoryInterface stores Idempotency-Key reservations and the responses to replay
+type IdempotencyRepositoryInterface interface {
+	CreateIdempotencyKey(record *models.IdempotencyKey) error
+	GetIdempotencyKey(userID uuid.UUID, key string) (*models.IdempotencyKey, error)
+	CompleteIdempotencyKey(userID uuid.UUID, key string, statusCode int, contentType string, body []byte) error
+	DeleteIdempotencyKey(userID uuid.UUID, key string) error
+	PurgeIdempotencyKeys(before time.Time) (int64, error)
+}
+
+// IdempotencyRepository handles database operations for idempotency keys
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository creates a new idempotency key repository
+func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// CreateIdempotencyKey reserves a key, returning ErrIdempotencyKeyExists if it is taken
+func (r *IdempotencyRepository) CreateIdempotencyKey(record *models.IdempotencyKey) error {
+	tx := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return ErrIdempotencyKeyExists
+	}
+	return nil
+}
+
+// GetIdempotencyKey retrieves a reserved key
+func (r *IdempotencyRepository) GetIdempotencyKey(userID uuid.UUID, key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	if err := r.db.Where("user_id = ? AND key = ?", userID, key).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// CompleteIdempotencyKey stores the response of the request that reserved the key
+func (r *IdempotencyRepository) CompleteIdempotencyKey(userID uuid.UUID, key string, statusCode int, contentType string, body []byte) error {
+	tx := r.db.Model(&models.IdempotencyKey{}).Where("user_id = ? AND key = ?", userID, key).Updates(map[string]interface{}{
+		"status_code":   statusCode,
+		"content_type":  contentType,
+		"response_body": body,
+		"completed_at":  time.Now().UTC(),
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteIdempotencyKey releases a key so the request can be retried
+func (r *IdempotencyRepository) DeleteIdempotencyKey(userID uuid.UUID, key string) error {
+	return r.db.Where("user_id = ? AND key = ?", userID, key).Delete(&models.IdempotencyKey{}).Error
+}
+
+// PurgeIdempotencyKeys removes keys created before the cutoff
+func (r *IdempotencyRepository) PurgeIdempotencyKeys(before time.Time) (int64, error) {
+	tx := r.db.Where("created_at < ?", before).Delete(&models.IdempotencyKey{})
+	return tx.RowsAffected, tx.Error
+}
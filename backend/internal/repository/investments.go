@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSecurityExists is returned when the user already has a security with the symbol
+var ErrSecurityExists = errors.New("security already exists")
+
+// InvestmentRepositoryInterface stores securities, the lots bought and sales made of
+// them, and their closing prices
+type InvestmentRepositoryInterface interface {
+	// CreateSecurity returns ErrSecurityExists when the symbol is taken
+	CreateSecurity(security *models.Security) error
+	GetSecurity(id, userID uuid.UUID) (*models.Security, error)
+	// ListSecurities returns the user's securities ordered by symbol
+	ListSecurities(userID uuid.UUID) ([]models.Security, error)
+	// UpdateSecurity returns ErrSecurityExists when a new symbol is taken
+	UpdateSecurity(id, userID uuid.UUID, updates map[string]interface{}) error
+	// DeleteSecurity removes a security with its lots, sales and prices, and unlinks
+	// its dividends, which are kept as incomes
+	DeleteSecurity(id, userID uuid.UUID) error
+	CreateInvestmentLot(lot *models.InvestmentLot) error
+	// ListInvestmentLots returns the lots of all the user's securities, oldest first
+	ListInvestmentLots(userID uuid.UUID) ([]models.InvestmentLot, error)
+	DeleteInvestmentLot(id, userID uuid.UUID) error
+	CreateInvestmentSale(sale *models.InvestmentSale) error
+	// ListInvestmentSales returns the sales of all the user's securities, oldest first
+	ListInvestmentSales(userID uuid.UUID) ([]models.InvestmentSale, error)
+	DeleteInvestmentSale(id, userID uuid.UUID) error
+	// SaveSecurityPrices stores closing prices, replacing any a security already has on the same day
+	SaveSecurityPrices(prices []models.SecurityPrice) error
+	// ListSecurityPrices returns a security's prices dated in [from, to), oldest first
+	ListSecurityPrices(securityID, userID uuid.UUID, from, to time.Time) ([]models.SecurityPrice, error)
+	// LatestSecurityPrices returns each of the user's securities' last price on or before day
+	LatestSecurityPrices(userID uuid.UUID, day time.Time) (map[uuid.UUID]models.SecurityPrice, error)
+	// ListDividends returns the incomes linked to any of the user's securities, oldest first
+	ListDividends(userID uuid.UUID) ([]models.Income, error)
+}
+
+func (r *FinanceRepository) CreateSecurity(security *models.Security) error {
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(security)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrSecurityExists
+	}
+	return nil
+}
+
+func (r *FinanceRepository) GetSecurity(id, userID uuid.UUID) (*models.Security, error) {
+	var security models.Security
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&security).Error; err != nil {
+		return nil, err
+	}
+	return &security, nil
+}
+
+func (r *FinanceRepository) ListSecurities(userID uuid.UUID) ([]models.Security, error) {
+	var securities []models.Security
+	if err := r.db.Where("user_id = ?", userID).Order("symbol ASC").Find(&securities).Error; err != nil {
+		return nil, err
+	}
+	return securities, nil
+}
+
+func (r *FinanceRepository) UpdateSecurity(id, userID uuid.UUID, updates map[string]interface{}) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if symbol, ok := updates["symbol"].(string); ok {
+			var taken int64
+			err := tx.Model(&models.Security{}).
+				Where("user_id = ? AND id <> ? AND symbol = ?", userID, id, symbol).
+				Count(&taken).Error
+			if err != nil {
+				return err
+			}
+			if taken > 0 {
+				return ErrSecurityExists
+			}
+		}
+		res := tx.Model(&models.Security{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *FinanceRepository) DeleteSecurity(id, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Soft-deleted incomes are unlinked too so a restore does not point at a missing security
+		if err := tx.Unscoped().Model(&models.Income{}).
+			Where("security_id = ? AND user_id = ?", id, userID).
+			Updates(map[string]interface{}{"security_id": nil, "version": gorm.Expr("version + 1")}).Error; err != nil {
+			return err
+		}
+		// Lots, sales and prices go with the security through ON DELETE CASCADE
+		res := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Security{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *FinanceRepository) CreateInvestmentLot(lot *models.InvestmentLot) error {
+	return r.db.Create(lot).Error
+}
+
+func (r *FinanceRepository) ListInvestmentLots(userID uuid.UUID) ([]models.InvestmentLot, error) {
+	var lots []models.InvestmentLot
+	if err := r.db.Where("user_id = ?", userID).Order("bought_on ASC, created_at ASC").Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+func (r *FinanceRepository) DeleteInvestmentLot(id, userID uuid.UUID) error {
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.InvestmentLot{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) CreateInvestmentSale(sale *models.InvestmentSale) error {
+	return r.db.Create(sale).Error
+}
+
+func (r *FinanceRepository) ListInvestmentSales(userID uuid.UUID) ([]models.InvestmentSale, error) {
+	var sales []models.InvestmentSale
+	if err := r.db.Where("user_id = ?", userID).Order("sold_on ASC, created_at ASC").Find(&sales).Error; err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+func (r *FinanceRepository) DeleteInvestmentSale(id, userID uuid.UUID) error {
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.InvestmentSale{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) SaveSecurityPrices(prices []models.SecurityPrice) error {
+	if len(prices) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "security_id"}, {Name: "priced_on"}},
+		DoUpdates: clause.AssignmentColumns([]string{"close"}),
+	}).CreateInBatches(prices, 500).Error
+}
+
+func (r *FinanceRepository) ListSecurityPrices(securityID, userID uuid.UUID, from, to time.Time) ([]models.SecurityPrice, error) {
+	var prices []models.SecurityPrice
+	err := r.db.Where("security_id = ? AND user_id = ? AND priced_on >= ? AND priced_on < ?", securityID, userID, from, to).
+		Order("priced_on ASC").
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (r *FinanceRepository) LatestSecurityPrices(userID uuid.UUID, day time.Time) (map[uuid.UUID]models.SecurityPrice, error) {
+	var prices []models.SecurityPrice
+	err := r.db.Raw(`
+		SELECT DISTINCT ON (security_id) security_id, user_id, priced_on, close
+		FROM security_prices
+		WHERE user_id = ? AND priced_on <= ?
+		ORDER BY security_id, priced_on DESC`, userID, day).Scan(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[uuid.UUID]models.SecurityPrice, len(prices))
+	for _, price := range prices {
+		latest[price.SecurityID] = price
+	}
+	return latest, nil
+}
+
+func (r *FinanceRepository) ListDividends(userID uuid.UUID) ([]models.Income, error) {
+	var incomes []models.Income
+	err := r.db.Where("user_id = ? AND security_id IS NOT NULL", userID).
+		Order("received_at ASC, created_at ASC").
+		Find(&incomes).Error
+	if err != nil {
+		return nil, err
+	}
+	return incomes, nil
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// GoalStatusRepositoryInterface stores the history of goal status changes
+type GoalStatusRepositoryInterface interface {
+	CreateGoalStatusChange(change *models.GoalStatusChange) error
+	// ListGoalStatusChanges returns a goal's status changes oldest first
+	ListGoalStatusChanges(goalID, userID uuid.UUID) ([]models.GoalStatusChange, error)
+}
+
+func (r *FinanceRepository) CreateGoalStatusChange(change *models.GoalStatusChange) error {
+	return r.db.Create(change).Error
+}
+
+func (r *FinanceRepository) ListGoalStatusChanges(goalID, userID uuid.UUID) ([]models.GoalStatusChange, error) {
+	var changes []models.GoalStatusChange
+	err := r.db.Where("goal_id = ? AND user_id = ?", goalID, userID).
+		Order("changed_at ASC").
+		Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// applyUpdates mirrors GORM's Updates(map) for in-memory models: keys are
+// column names taken from the `gorm:"column:..."` tags and values are
+// dereferenced and converted to the field type. Returns an error for unknown columns.
+func applyUpdates(dst interface{}, updates map[string]interface{}) error {
+	v := reflect.ValueOf(dst).Elem()
+	t := v.Type()
+
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if column := columnName(t.Field(i)); column != "" {
+			fields[column] = i
+		}
+	}
+
+	for column, value := range updates {
+		idx, ok := fields[column]
+		if !ok {
+			return fmt.Errorf("unknown column %q for %s", column, t.Name())
+		}
+		if err := assignValue(v.Field(idx), value); err != nil {
+			return fmt.Errorf("column %q: %w", column, err)
+		}
+	}
+
+	// GORM refreshes updated_at on map updates unless it is set explicitly
+	if idx, ok := fields["updated_at"]; ok {
+		if _, set := updates["updated_at"]; !set {
+			v.Field(idx).Set(reflect.ValueOf(time.Now().UTC()))
+		}
+	}
+	return nil
+}
+
+// columnName extracts the column name from a struct field's gorm tag
+func columnName(f reflect.StructField) string {
+	for _, part := range strings.Split(f.Tag.Get("gorm"), ";") {
+		if strings.HasPrefix(part, "column:") {
+			return strings.TrimPrefix(part, "column:")
+		}
+	}
+	return ""
+}
+
+// assignValue sets field to value, following pointers the way database/sql
+// does when converting driver arguments
+func assignValue(field reflect.Value, value interface{}) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	for {
+		if rv.Type().AssignableTo(field.Type()) {
+			field.Set(rv)
+			return nil
+		}
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				field.Set(reflect.Zero(field.Type()))
+				return nil
+			}
+			rv = rv.Elem()
+			continue
+		}
+		break
+	}
+
+	// Column is nullable (pointer field) and value is the element type
+	if field.Kind() == reflect.Ptr && rv.Type().AssignableTo(field.Type().Elem()) {
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(rv)
+		field.Set(ptr)
+		return nil
+	}
+	if rv.Type().ConvertibleTo(field.Type()) {
+		field.Set(rv.Convert(field.Type()))
+		return nil
+	}
+	return fmt.Errorf("cannot assign %s to %s", rv.Type(), field.Type())
+}
+
+// cloneStrings copies a string slice so stored models do not share backing arrays with callers
+func cloneStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	copy(out, in)
+	return out
+}
+
+// inRange reports whether t falls in the half-open interval [start, end)
+func inRange(t, start, end time.Time) bool {
+	return !t.Before(start) && t.Before(end)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InMemoryAttachmentRepository is a thread-safe AttachmentRepositoryInterface backed
+// by a map. finance and notes stand in for the tables attachment owners live in.
+type InMemoryAttachmentRepository struct {
+	mu          sync.Mutex
+	attachments map[uuid.UUID]models.Attachment
+	finance     *InMemoryFinanceRepository
+	notes       *InMemoryNotesRepository
+}
+
+// NewInMemoryAttachmentRepository creates an empty in-memory attachment store whose
+// owners are looked up in finance and notes
+func NewInMemoryAttachmentRepository(finance *InMemoryFinanceRepository, notes *InMemoryNotesRepository) *InMemoryAttachmentRepository {
+	return &InMemoryAttachmentRepository{
+		attachments: make(map[uuid.UUID]models.Attachment),
+		finance:     finance,
+		notes:       notes,
+	}
+}
+
+// Transaction restores the attachments when fn fails. Like InMemoryFinanceRepository
+// it does not isolate fn from concurrent writers.
+func (r *InMemoryAttachmentRepository) Transaction(fn func(repo AttachmentRepositoryInterface) error) error {
+	r.mu.Lock()
+	saved := maps.Clone(r.attachments)
+	r.mu.Unlock()
+	if err := fn(r); err != nil {
+		r.mu.Lock()
+		r.attachments = saved
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (r *InMemoryAttachmentRepository) CreateAttachment(attachment *models.Attachment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if attachment.ID == uuid.Nil {
+		attachment.ID = uuid.New()
+	}
+	if attachment.CreatedAt.IsZero() {
+		attachment.CreatedAt = time.Now().UTC()
+	}
+	r.attachments[attachment.ID] = *attachment
+	return nil
+}
+
+func (r *InMemoryAttachmentRepository) GetAttachment(id, userID uuid.UUID) (*models.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attachment, ok := r.attachments[id]
+	if !ok || attachment.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &attachment, nil
+}
+
+func (r *InMemoryAttachmentRepository) ListAttachments(userID uuid.UUID, ownerType string, ownerID uuid.UUID) ([]models.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attachments := []models.Attachment{}
+	for _, a := range r.attachments {
+		if a.UserID == userID && a.OwnerType == ownerType && a.OwnerID == ownerID {
+			attachments = append(attachments, a)
+		}
+	}
+	sortAttachments(attachments)
+	return attachments, nil
+}
+
+func (r *InMemoryAttachmentRepository) DeleteAttachment(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attachment, ok := r.attachments[id]
+	if !ok || attachment.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.attachments, id)
+	return nil
+}
+
+func (r *InMemoryAttachmentRepository) ListOrphanedAttachments(limit int) ([]models.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	orphaned := []models.Attachment{}
+	for _, a := range r.attachments {
+		if !r.ownerExists(a.OwnerType, a.OwnerID) {
+			orphaned = append(orphaned, a)
+		}
+	}
+	sortAttachments(orphaned)
+	if limit > 0 && len(orphaned) > limit {
+		orphaned = orphaned[:limit]
+	}
+	return orphaned, nil
+}
+
+// ownerExists reports whether the record an attachment belongs to is stored,
+// in the trash or not
+func (r *InMemoryAttachmentRepository) ownerExists(ownerType string, ownerID uuid.UUID) bool {
+	switch ownerType {
+	case models.AttachmentOwnerExpense, models.AttachmentOwnerIncome:
+		if r.finance == nil {
+			return false
+		}
+		r.finance.mu.RLock()
+		defer r.finance.mu.RUnlock()
+		if ownerType == models.AttachmentOwnerExpense {
+			_, ok := r.finance.expenses[ownerID]
+			return ok
+		}
+		_, ok := r.finance.incomes[ownerID]
+		return ok
+	case models.AttachmentOwnerNote:
+		if r.notes == nil {
+			return false
+		}
+		r.notes.mu.RLock()
+		defer r.notes.mu.RUnlock()
+		_, ok := r.notes.notes[ownerID]
+		return ok
+	}
+	return false
+}
+
+func sortAttachments(attachments []models.Attachment) {
+	sort.SliceStable(attachments, func(i, j int) bool { return attachments[i].CreatedAt.Before(attachments[j].CreatedAt) })
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// InMemoryAuditRepository is a thread-safe AuditRepositoryInterface backed by a slice
+type InMemoryAuditRepository struct {
+	mu      sync.RWMutex
+	entries []models.AuditEntry
+}
+
+// NewInMemoryAuditRepository creates an empty in-memory audit log
+func NewInMemoryAuditRepository() *InMemoryAuditRepository {
+	return &InMemoryAuditRepository{}
+}
+
+// CreateAuditEntry appends an entry to the audit log
+func (r *InMemoryAuditRepository) CreateAuditEntry(entry *models.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+// InTransaction returns r: entries are appended as soon as they are created, so
+// callers write them once the rest of the transaction has succeeded
+func (r *InMemoryAuditRepository) InTransaction(interface{}) AuditRepositoryInterface {
+	return r
+}
+
+// ListAuditEntries returns the user's audit entries matching filter, newest first
+func (r *InMemoryAuditRepository) ListAuditEntries(userID uuid.UUID, filter AuditFilter) ([]models.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entries := []models.AuditEntry{}
+	// Walk backwards so entries written in the same instant stay newest first
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		e := r.entries[i]
+		switch {
+		case e.UserID != userID,
+			filter.EntityType != "" && e.EntityType != filter.EntityType,
+			filter.EntityID != nil && e.EntityID != *filter.EntityID,
+			filter.Actor != "" && e.Actor != filter.Actor,
+			filter.From != nil && e.CreatedAt.Before(*filter.From),
+			filter.To != nil && !e.CreatedAt.Before(*filter.To):
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].CreatedAt.After(entries[j].CreatedAt) })
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
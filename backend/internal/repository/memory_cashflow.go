@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func (r *InMemoryFinanceRepository) ListIncomesBetween(userID uuid.UUID, from, to time.Time) ([]models.Income, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	items := []models.Income{}
+	for _, i := range r.incomes {
+		if i.UserID == userID && !i.DeletedAt.Valid && !i.ReceivedAt.Before(from) && i.ReceivedAt.Before(to) {
+			items = append(items, cloneIncome(i))
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].ReceivedAt.Before(items[b].ReceivedAt) })
+	return items, nil
+}
+
+func (r *InMemoryFinanceRepository) ListExpensesBetween(userID uuid.UUID, from, to time.Time) ([]models.Expense, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	items := []models.Expense{}
+	for _, e := range r.expenses {
+		if e.UserID == userID && !e.DeletedAt.Valid && !e.SpentAt.Before(from) && e.SpentAt.Before(to) {
+			items = append(items, cloneExpense(e))
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].SpentAt.Before(items[b].SpentAt) })
+	return items, nil
+}
+
+func (r *InMemoryFinanceRepository) GetBalance(userID uuid.UUID, before time.Time) (float64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var balance float64
+	for _, i := range r.incomes {
+		if i.UserID == userID && !i.DeletedAt.Valid && i.ReceivedAt.Before(before) {
+			balance += i.Amount
+		}
+	}
+	for _, e := range r.expenses {
+		if e.UserID == userID && !e.DeletedAt.Valid && e.SpentAt.Before(before) {
+			balance -= e.Amount
+		}
+	}
+	for _, c := range r.contributions {
+		if c.UserID == userID && !c.DeletedAt.Valid && c.ContributedAt.Before(before) {
+			balance -= c.Amount
+		}
+	}
+	return balance, nil
+}
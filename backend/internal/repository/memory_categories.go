@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r *InMemoryFinanceRepository) CreateCategory(cat *models.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hasSiblingNamed(*cat) {
+		return ErrCategoryExists
+	}
+	if cat.ID == uuid.Nil {
+		cat.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if cat.CreatedAt.IsZero() {
+		cat.CreatedAt = now
+	}
+	if cat.UpdatedAt.IsZero() {
+		cat.UpdatedAt = now
+	}
+	r.categories[cat.ID] = *cat
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) GetCategory(id, userID uuid.UUID) (*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cat, ok := r.categories[id]
+	if !ok || cat.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &cat, nil
+}
+
+func (r *InMemoryFinanceRepository) ListCategories(userID uuid.UUID) ([]models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cats := []models.Category{}
+	for _, c := range r.categories {
+		if c.UserID == userID {
+			cats = append(cats, c)
+		}
+	}
+	sort.SliceStable(cats, func(i, j int) bool { return cats[i].Name < cats[j].Name })
+	return cats, nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateCategory(id, userID uuid.UUID, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cat, ok := r.categories[id]
+	if !ok || cat.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	if err := applyUpdates(&cat, updates); err != nil {
+		return err
+	}
+	if r.hasSiblingNamed(cat) {
+		return ErrCategoryExists
+	}
+	r.categories[id] = cat
+	if _, renamed := updates["name"]; renamed {
+		r.relinkCategoryExpenses(userID, id, cat)
+	}
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteCategory(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cat, ok := r.categories[id]
+	if !ok || cat.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	for _, e := range r.expenses {
+		if e.UserID == userID && e.CategoryID != nil && *e.CategoryID == id {
+			return ErrCategoryInUse
+		}
+	}
+	for _, c := range r.categories {
+		if c.ParentID != nil && *c.ParentID == id {
+			return ErrCategoryInUse
+		}
+	}
+	delete(r.categories, id)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) MergeCategories(userID, targetID uuid.UUID, sourceIDs []uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	target, ok := r.categories[targetID]
+	if !ok || target.UserID != userID {
+		return 0, gorm.ErrRecordNotFound
+	}
+	for _, id := range sourceIDs {
+		if c, ok := r.categories[id]; !ok || c.UserID != userID {
+			return 0, gorm.ErrRecordNotFound
+		}
+	}
+
+	var moved int64
+	for _, id := range sourceIDs {
+		moved += r.relinkCategoryExpenses(userID, id, target)
+		for cID, c := range r.categories {
+			if c.ParentID != nil && *c.ParentID == id {
+				parentID := targetID
+				c.ParentID = &parentID
+				r.categories[cID] = c
+			}
+		}
+		delete(r.categories, id)
+	}
+	return moved, nil
+}
+
+// hasSiblingNamed reports whether another category under the same parent has
+// cat's name, ignoring case. Callers must hold the lock.
+func (r *InMemoryFinanceRepository) hasSiblingNamed(cat models.Category) bool {
+	for _, c := range r.categories {
+		if c.ID != cat.ID && c.UserID == cat.UserID && sameParent(c.ParentID, cat.ParentID) && strings.EqualFold(c.Name, cat.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameParent reports whether two optional parent IDs are equal
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// relinkCategoryExpenses points every expense of category id, including deleted
+// ones, at target and returns how many changed. Callers must hold the write lock.
+func (r *InMemoryFinanceRepository) relinkCategoryExpenses(userID, id uuid.UUID, target models.Category) int64 {
+	var changed int64
+	for eID, e := range r.expenses {
+		if e.UserID != userID || e.CategoryID == nil || *e.CategoryID != id {
+			continue
+		}
+		categoryID := target.ID
+		e.CategoryID = &categoryID
+		e.Category = target.Name
+		e.Version++
+		r.expenses[eID] = e
+		changed++
+	}
+	return changed
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r *InMemoryFinanceRepository) CreateDebt(debt *models.Debt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if debt.ID == uuid.Nil {
+		debt.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if debt.CreatedAt.IsZero() {
+		debt.CreatedAt = now
+	}
+	if debt.UpdatedAt.IsZero() {
+		debt.UpdatedAt = now
+	}
+	r.debts[debt.ID] = *debt
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) GetDebt(id, userID uuid.UUID) (*models.Debt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	debt, ok := r.debts[id]
+	if !ok || debt.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &debt, nil
+}
+
+func (r *InMemoryFinanceRepository) ListDebts(userID uuid.UUID) ([]models.Debt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	debts := []models.Debt{}
+	for _, debt := range r.debts {
+		if debt.UserID == userID {
+			debts = append(debts, debt)
+		}
+	}
+	sort.Slice(debts, func(i, j int) bool { return debts[i].CreatedAt.Before(debts[j].CreatedAt) })
+	return debts, nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateDebt(id, userID uuid.UUID, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	debt, ok := r.debts[id]
+	if !ok || debt.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	if err := applyUpdates(&debt, updates); err != nil {
+		return err
+	}
+	debt.UpdatedAt = time.Now().UTC()
+	r.debts[id] = debt
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteDebt(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	debt, ok := r.debts[id]
+	if !ok || debt.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	for eID, e := range r.expenses {
+		if e.UserID == userID && e.DebtID != nil && *e.DebtID == id {
+			e.DebtID = nil
+			e.Version++
+			r.expenses[eID] = e
+		}
+	}
+	delete(r.debts, id)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) ListDebtPayments(userID uuid.UUID) ([]models.Expense, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	payments := []models.Expense{}
+	for _, e := range r.expenses {
+		if e.UserID == userID && !e.DeletedAt.Valid && e.DebtID != nil {
+			payments = append(payments, cloneExpense(e))
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool {
+		if !payments[i].SpentAt.Equal(payments[j].SpentAt) {
+			return payments[i].SpentAt.Before(payments[j].SpentAt)
+		}
+		return payments[i].CreatedAt.Before(payments[j].CreatedAt)
+	})
+	return payments, nil
+}
@@ -0,0 +1,865 @@
+package repository
+
+import (
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InMemoryFinanceRepository is a thread-safe FinanceRepositoryInterface backed by maps.
+// It is intended for tests and local development without Postgres.
+type InMemoryFinanceRepository struct {
+	mu             sync.RWMutex
+	incomes        map[uuid.UUID]models.Income
+	expenses       map[uuid.UUID]models.Expense
+	goals          map[uuid.UUID]models.Goal
+	contributions  map[uuid.UUID]models.GoalContribution
+	categories     map[uuid.UUID]models.Category
+	goalCategories map[uuid.UUID]models.GoalCategory
+	goalExpenses   map[uuid.UUID]models.GoalExpense
+	merchants      map[uuid.UUID]models.Merchant
+	patterns       map[uuid.UUID]models.MerchantPattern
+	fundingRules   map[uuid.UUID]models.GoalFundingRule
+	statusChanges  map[uuid.UUID]models.GoalStatusChange
+	goalTemplates  map[uuid.UUID]models.GoalTemplate
+	debts          map[uuid.UUID]models.Debt
+	netWorthItems  map[uuid.UUID]models.NetWorthItem
+	valuations     map[uuid.UUID]models.NetWorthValuation
+	snapshots      map[uuid.UUID]models.NetWorthSnapshot
+	securities     map[uuid.UUID]models.Security
+	lots           map[uuid.UUID]models.InvestmentLot
+	sales          map[uuid.UUID]models.InvestmentSale
+	prices         map[priceKey]models.SecurityPrice
+}
+
+// NewInMemoryFinanceRepository creates an empty in-memory finance repository
+// seeded with the predefined goal categories from migration 006 and the
+// built-in goal templates from migration 020
+func NewInMemoryFinanceRepository() *InMemoryFinanceRepository {
+	r := &InMemoryFinanceRepository{
+		incomes:        map[uuid.UUID]models.Income{},
+		expenses:       map[uuid.UUID]models.Expense{},
+		goals:          map[uuid.UUID]models.Goal{},
+		contributions:  map[uuid.UUID]models.GoalContribution{},
+		categories:     map[uuid.UUID]models.Category{},
+		goalCategories: map[uuid.UUID]models.GoalCategory{},
+		goalExpenses:   map[uuid.UUID]models.GoalExpense{},
+		merchants:      map[uuid.UUID]models.Merchant{},
+		patterns:       map[uuid.UUID]models.MerchantPattern{},
+		fundingRules:   map[uuid.UUID]models.GoalFundingRule{},
+		statusChanges:  map[uuid.UUID]models.GoalStatusChange{},
+		goalTemplates:  map[uuid.UUID]models.GoalTemplate{},
+		debts:          map[uuid.UUID]models.Debt{},
+		netWorthItems:  map[uuid.UUID]models.NetWorthItem{},
+		valuations:     map[uuid.UUID]models.NetWorthValuation{},
+		snapshots:      map[uuid.UUID]models.NetWorthSnapshot{},
+		securities:     map[uuid.UUID]models.Security{},
+		lots:           map[uuid.UUID]models.InvestmentLot{},
+		sales:          map[uuid.UUID]models.InvestmentSale{},
+		prices:         map[priceKey]models.SecurityPrice{},
+	}
+	now := time.Now().UTC()
+	for _, c := range defaultGoalCategories {
+		c.ID = uuid.New()
+		c.CreatedAt = now
+		c.UpdatedAt = now
+		r.goalCategories[c.ID] = c
+	}
+	r.seedGoalTemplates(now)
+	return r
+}
+
+// defaultGoalCategories matches the rows seeded by migration 006
+var defaultGoalCategories = []models.GoalCategory{
+	{Name: "Travel", Description: "Travel and vacation goals", Icon: "plane", Color: "#3b82f6"},
+	{Name: "Education", Description: "Educational goals and courses", Icon: "graduation-cap", Color: "#10b981"},
+	{Name: "Lifestyle", Description: "Lifestyle upgrades and improvements", Icon: "home", Color: "#f59e0b"},
+	{Name: "Health", Description: "Health and fitness goals", Icon: "heart", Color: "#ef4444"},
+	{Name: "Technology", Description: "Technology and gadgets", Icon: "smartphone", Color: "#8b5cf6"},
+	{Name: "Emergency", Description: "Emergency fund and safety nets", Icon: "shield", Color: "#06b6d4"},
+	{Name: "Investment", Description: "Investment and wealth building", Icon: "trending-up", Color: "#84cc16"},
+	{Name: "General", Description: "General savings goals", Icon: "target", Color: "#6b7280"},
+}
+
+func (r *InMemoryFinanceRepository) CreateIncome(income *models.Income) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if income.ID == uuid.Nil {
+		income.ID = uuid.New()
+	}
+	if income.Version == 0 {
+		income.Version = 1
+	}
+	now := time.Now().UTC()
+	if income.CreatedAt.IsZero() {
+		income.CreatedAt = now
+	}
+	if income.UpdatedAt.IsZero() {
+		income.UpdatedAt = now
+	}
+	r.incomes[income.ID] = cloneIncome(*income)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) CreateExpense(expense *models.Expense) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if expense.ID == uuid.Nil {
+		expense.ID = uuid.New()
+	}
+	if expense.Version == 0 {
+		expense.Version = 1
+	}
+	now := time.Now().UTC()
+	if expense.CreatedAt.IsZero() {
+		expense.CreatedAt = now
+	}
+	if expense.UpdatedAt.IsZero() {
+		expense.UpdatedAt = now
+	}
+	r.expenses[expense.ID] = cloneExpense(*expense)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) CreateGoal(goal *models.Goal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if goal.ID == uuid.Nil {
+		goal.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if goal.Version == 0 {
+		goal.Version = 1
+	}
+	if goal.AllocationPriority == 0 {
+		goal.AllocationPriority = 1
+	}
+	if goal.AllocationWeight == 0 {
+		goal.AllocationWeight = 1
+	}
+	if goal.Status == "" {
+		goal.Status = models.GoalActive
+	}
+	if goal.CreatedAt.IsZero() {
+		goal.CreatedAt = now
+	}
+	if goal.UpdatedAt.IsZero() {
+		goal.UpdatedAt = now
+	}
+	r.goals[goal.ID] = cloneGoal(*goal)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) CreateGoalContribution(contrib *models.GoalContribution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if contrib.ID == uuid.Nil {
+		contrib.ID = uuid.New()
+	}
+	if contrib.CreatedAt.IsZero() {
+		contrib.CreatedAt = time.Now().UTC()
+	}
+	r.contributions[contrib.ID] = *contrib
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) GetGoalContribution(id, userID uuid.UUID) (*models.GoalContribution, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	contrib, ok := r.contributions[id]
+	if !ok || contrib.UserID != userID || contrib.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &contrib, nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateGoalContribution(id, userID uuid.UUID, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	contrib, ok := r.contributions[id]
+	if !ok || contrib.UserID != userID || contrib.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	if err := applyUpdates(&contrib, updates); err != nil {
+		return err
+	}
+	r.contributions[id] = contrib
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteGoalContribution(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	contrib, ok := r.contributions[id]
+	if !ok || contrib.UserID != userID || contrib.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	contrib.DeletedAt = deletedNow()
+	r.contributions[id] = contrib
+	return nil
+}
+
+// Transaction snapshots the repository and restores it when fn fails. Unlike
+// Postgres it does not isolate fn from concurrent writers.
+func (r *InMemoryFinanceRepository) Transaction(fn func(repo FinanceRepositoryInterface) error) error {
+	saved := r.snapshot()
+	if err := fn(r); err != nil {
+		r.restore(saved)
+		return err
+	}
+	return nil
+}
+
+// financeSnapshot is a copy of every table held by InMemoryFinanceRepository
+type financeSnapshot struct {
+	incomes        map[uuid.UUID]models.Income
+	expenses       map[uuid.UUID]models.Expense
+	goals          map[uuid.UUID]models.Goal
+	contributions  map[uuid.UUID]models.GoalContribution
+	categories     map[uuid.UUID]models.Category
+	goalCategories map[uuid.UUID]models.GoalCategory
+	goalExpenses   map[uuid.UUID]models.GoalExpense
+	merchants      map[uuid.UUID]models.Merchant
+	patterns       map[uuid.UUID]models.MerchantPattern
+	fundingRules   map[uuid.UUID]models.GoalFundingRule
+	statusChanges  map[uuid.UUID]models.GoalStatusChange
+	goalTemplates  map[uuid.UUID]models.GoalTemplate
+	debts          map[uuid.UUID]models.Debt
+	netWorthItems  map[uuid.UUID]models.NetWorthItem
+	valuations     map[uuid.UUID]models.NetWorthValuation
+	snapshots      map[uuid.UUID]models.NetWorthSnapshot
+	securities     map[uuid.UUID]models.Security
+	lots           map[uuid.UUID]models.InvestmentLot
+	sales          map[uuid.UUID]models.InvestmentSale
+	prices         map[priceKey]models.SecurityPrice
+}
+
+// snapshot copies the maps; stored models are values whose pointer fields are
+// never mutated in place, so the copies can share them
+func (r *InMemoryFinanceRepository) snapshot() financeSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return financeSnapshot{
+		incomes:        maps.Clone(r.incomes),
+		expenses:       maps.Clone(r.expenses),
+		goals:          maps.Clone(r.goals),
+		contributions:  maps.Clone(r.contributions),
+		categories:     maps.Clone(r.categories),
+		goalCategories: maps.Clone(r.goalCategories),
+		goalExpenses:   maps.Clone(r.goalExpenses),
+		merchants:      maps.Clone(r.merchants),
+		patterns:       maps.Clone(r.patterns),
+		fundingRules:   maps.Clone(r.fundingRules),
+		statusChanges:  maps.Clone(r.statusChanges),
+		goalTemplates:  maps.Clone(r.goalTemplates),
+		debts:          maps.Clone(r.debts),
+		netWorthItems:  maps.Clone(r.netWorthItems),
+		valuations:     maps.Clone(r.valuations),
+		snapshots:      maps.Clone(r.snapshots),
+		securities:     maps.Clone(r.securities),
+		lots:           maps.Clone(r.lots),
+		sales:          maps.Clone(r.sales),
+		prices:         maps.Clone(r.prices),
+	}
+}
+
+func (r *InMemoryFinanceRepository) restore(s financeSnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.incomes = s.incomes
+	r.expenses = s.expenses
+	r.goals = s.goals
+	r.contributions = s.contributions
+	r.categories = s.categories
+	r.goalCategories = s.goalCategories
+	r.goalExpenses = s.goalExpenses
+	r.merchants = s.merchants
+	r.patterns = s.patterns
+	r.fundingRules = s.fundingRules
+	r.statusChanges = s.statusChanges
+	r.goalTemplates = s.goalTemplates
+	r.debts = s.debts
+	r.netWorthItems = s.netWorthItems
+	r.valuations = s.valuations
+	r.snapshots = s.snapshots
+	r.securities = s.securities
+	r.lots = s.lots
+	r.sales = s.sales
+	r.prices = s.prices
+}
+
+func (r *InMemoryFinanceRepository) ListGoalsWithProgress(userID uuid.UUID) ([]GoalWithProgress, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contribMap := map[uuid.UUID]float64{}
+	for _, c := range r.contributions {
+		if c.UserID == userID && !c.DeletedAt.Valid {
+			contribMap[c.GoalID] += c.Amount
+		}
+	}
+	expenseMap := map[uuid.UUID]float64{}
+	for _, e := range r.expenses {
+		if e.UserID == userID && !e.DeletedAt.Valid && e.GoalID != nil {
+			expenseMap[*e.GoalID] += e.Amount
+		}
+	}
+
+	goals := r.userGoals(userID, func(models.Goal) bool { return true })
+	sortGoalsByCreated(goals, true)
+
+	result := make([]GoalWithProgress, 0, len(goals))
+	for _, g := range goals {
+		result = append(result, GoalWithProgress{Goal: g, ContributedSum: contribMap[g.ID], ExpenseSum: expenseMap[g.ID]})
+	}
+	return result, nil
+}
+
+func (r *InMemoryFinanceRepository) SumGoalContributions(userID uuid.UUID, from, to time.Time) (map[uuid.UUID]float64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sums := map[uuid.UUID]float64{}
+	for _, c := range r.contributions {
+		if c.UserID == userID && !c.DeletedAt.Valid && !c.ContributedAt.Before(from) && c.ContributedAt.Before(to) {
+			sums[c.GoalID] += c.Amount
+		}
+	}
+	return sums, nil
+}
+
+func (r *InMemoryFinanceRepository) ListIncomes(userID uuid.UUID, limit int) ([]models.Income, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	items := []models.Income{}
+	for _, i := range r.incomes {
+		if i.UserID == userID && !i.DeletedAt.Valid {
+			items = append(items, cloneIncome(i))
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].ReceivedAt.After(items[b].ReceivedAt) })
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (r *InMemoryFinanceRepository) ListExpenses(userID uuid.UUID, limit int) ([]models.Expense, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	items := []models.Expense{}
+	for _, e := range r.expenses {
+		if e.UserID == userID && !e.DeletedAt.Valid {
+			items = append(items, cloneExpense(e))
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].SpentAt.After(items[b].SpentAt) })
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (r *InMemoryFinanceRepository) GetIncome(id, userID uuid.UUID) (*models.Income, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	income, ok := r.incomes[id]
+	if !ok || income.UserID != userID || income.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	income = cloneIncome(income)
+	return &income, nil
+}
+
+func (r *InMemoryFinanceRepository) GetExpense(id, userID uuid.UUID) (*models.Expense, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	expense, ok := r.expenses[id]
+	if !ok || expense.UserID != userID || expense.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	expense = cloneExpense(expense)
+	return &expense, nil
+}
+
+func (r *InMemoryFinanceRepository) GetGoal(id, userID uuid.UUID) (*models.Goal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	goal, ok := r.goals[id]
+	if !ok || goal.UserID != userID || goal.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	goal = cloneGoal(goal)
+	return &goal, nil
+}
+
+// LockGoal gets a goal. Transactions here are not isolated, so there is no row to lock.
+func (r *InMemoryFinanceRepository) LockGoal(id, userID uuid.UUID) (*models.Goal, error) {
+	return r.GetGoal(id, userID)
+}
+
+// LockAllocations does nothing, as transactions here are not isolated
+func (r *InMemoryFinanceRepository) LockAllocations(userID uuid.UUID) error {
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateIncome(id, userID uuid.UUID, version int, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	income, ok := r.incomes[id]
+	if !ok || income.UserID != userID || income.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	if version > 0 && income.Version != version {
+		return ErrVersionConflict
+	}
+	if err := applyUpdates(&income, updates); err != nil {
+		return err
+	}
+	income.Version++
+	r.incomes[id] = cloneIncome(income)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateExpense(id, userID uuid.UUID, version int, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	expense, ok := r.expenses[id]
+	if !ok || expense.UserID != userID || expense.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	if version > 0 && expense.Version != version {
+		return ErrVersionConflict
+	}
+	if err := applyUpdates(&expense, updates); err != nil {
+		return err
+	}
+	expense.Version++
+	r.expenses[id] = cloneExpense(expense)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteIncome(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	income, ok := r.incomes[id]
+	if !ok || income.UserID != userID || income.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	income.DeletedAt = deletedNow()
+	r.incomes[id] = income
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteExpense(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	expense, ok := r.expenses[id]
+	if !ok || expense.UserID != userID || expense.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	expense.DeletedAt = deletedNow()
+	r.expenses[id] = expense
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateGoal(id, userID uuid.UUID, version int, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	goal, ok := r.goals[id]
+	if !ok || goal.UserID != userID || goal.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	if version > 0 && goal.Version != version {
+		return ErrVersionConflict
+	}
+	if err := applyUpdates(&goal, updates); err != nil {
+		return err
+	}
+	goal.Version++
+	r.goals[id] = cloneGoal(goal)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteGoal(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	goal, ok := r.goals[id]
+	if !ok || goal.UserID != userID || goal.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	deletedAt := deletedNow()
+	for _, goalID := range r.goalTree(id, gorm.DeletedAt{}) {
+		g := r.goals[goalID]
+		g.DeletedAt = deletedAt
+		r.goals[goalID] = g
+		for cID, c := range r.contributions {
+			if c.GoalID == goalID && !c.DeletedAt.Valid {
+				c.DeletedAt = deletedAt
+				r.contributions[cID] = c
+			}
+		}
+	}
+	return nil
+}
+
+// goalTree returns id and the IDs of all sub-goals below it with the given
+// deleted_at, mirroring the recursive query in FinanceRepository. Caller holds the lock.
+func (r *InMemoryFinanceRepository) goalTree(id uuid.UUID, deletedAt gorm.DeletedAt) []uuid.UUID {
+	ids := []uuid.UUID{id}
+	for subID, g := range r.goals {
+		if g.ParentGoalID != nil && *g.ParentGoalID == id && g.DeletedAt == deletedAt {
+			ids = append(ids, r.goalTree(subID, deletedAt)...)
+		}
+	}
+	return ids
+}
+
+// ListTrash returns the user's soft-deleted incomes, expenses and goals
+func (r *InMemoryFinanceRepository) ListTrash(userID uuid.UUID) (*FinanceTrash, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	trash := &FinanceTrash{Incomes: []models.Income{}, Expenses: []models.Expense{}, Goals: []models.Goal{}}
+	for _, i := range r.incomes {
+		if i.UserID == userID && i.DeletedAt.Valid {
+			trash.Incomes = append(trash.Incomes, cloneIncome(i))
+		}
+	}
+	for _, e := range r.expenses {
+		if e.UserID == userID && e.DeletedAt.Valid {
+			trash.Expenses = append(trash.Expenses, cloneExpense(e))
+		}
+	}
+	for _, g := range r.goals {
+		if g.UserID == userID && g.DeletedAt.Valid {
+			trash.Goals = append(trash.Goals, cloneGoal(g))
+		}
+	}
+	sort.SliceStable(trash.Incomes, func(i, j int) bool { return trash.Incomes[i].DeletedAt.Time.After(trash.Incomes[j].DeletedAt.Time) })
+	sort.SliceStable(trash.Expenses, func(i, j int) bool { return trash.Expenses[i].DeletedAt.Time.After(trash.Expenses[j].DeletedAt.Time) })
+	sort.SliceStable(trash.Goals, func(i, j int) bool { return trash.Goals[i].DeletedAt.Time.After(trash.Goals[j].DeletedAt.Time) })
+	return trash, nil
+}
+
+func (r *InMemoryFinanceRepository) RestoreIncome(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	income, ok := r.incomes[id]
+	if !ok || income.UserID != userID || !income.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	income.DeletedAt = gorm.DeletedAt{}
+	r.incomes[id] = income
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) RestoreExpense(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	expense, ok := r.expenses[id]
+	if !ok || expense.UserID != userID || !expense.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	expense.DeletedAt = gorm.DeletedAt{}
+	r.expenses[id] = expense
+	return nil
+}
+
+// RestoreGoal restores a trashed goal together with the sub-goals and contributions
+// that were deleted with it
+func (r *InMemoryFinanceRepository) RestoreGoal(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	goal, ok := r.goals[id]
+	if !ok || goal.UserID != userID || !goal.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	if goal.ParentGoalID != nil {
+		if parent, ok := r.goals[*goal.ParentGoalID]; ok && parent.DeletedAt.Valid {
+			return ErrParentGoalDeleted
+		}
+	}
+	deletedAt := goal.DeletedAt
+	for _, goalID := range r.goalTree(id, deletedAt) {
+		g := r.goals[goalID]
+		g.DeletedAt = gorm.DeletedAt{}
+		r.goals[goalID] = g
+		for cID, c := range r.contributions {
+			if c.GoalID == goalID && c.DeletedAt == deletedAt {
+				c.DeletedAt = gorm.DeletedAt{}
+				r.contributions[cID] = c
+			}
+		}
+	}
+	return nil
+}
+
+// PurgeDeleted permanently removes rows of every user that were trashed before the cutoff
+func (r *InMemoryFinanceRepository) PurgeDeleted(before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var purged int64
+	expired := func(d gorm.DeletedAt) bool { return d.Valid && d.Time.Before(before) }
+	for id, c := range r.contributions {
+		if expired(c.DeletedAt) {
+			delete(r.contributions, id)
+			purged++
+		}
+	}
+	for id, e := range r.expenses {
+		if expired(e.DeletedAt) {
+			delete(r.expenses, id)
+			// fk_goal_expenses_expense ON DELETE CASCADE
+			for geID, ge := range r.goalExpenses {
+				if ge.ExpenseID == id {
+					delete(r.goalExpenses, geID)
+				}
+			}
+			purged++
+		}
+	}
+	for id, i := range r.incomes {
+		if expired(i.DeletedAt) {
+			delete(r.incomes, id)
+			purged++
+		}
+	}
+	for id, g := range r.goals {
+		if _, ok := r.goals[id]; ok && expired(g.DeletedAt) {
+			purged += r.deleteGoalCascade(id)
+		}
+	}
+	return purged, nil
+}
+
+func deletedNow() gorm.DeletedAt {
+	return gorm.DeletedAt{Time: time.Now().UTC().Truncate(time.Microsecond), Valid: true}
+}
+
+// deleteGoalCascade emulates the foreign keys on goals: sub-goals, contributions,
+// goal expenses and status changes cascade, while expenses.goal_id is set to NULL. It returns the
+// number of goals and contributions removed. Caller holds the lock.
+func (r *InMemoryFinanceRepository) deleteGoalCascade(id uuid.UUID) int64 {
+	delete(r.goals, id)
+	removed := int64(1)
+	for subID, g := range r.goals {
+		if g.ParentGoalID != nil && *g.ParentGoalID == id {
+			removed += r.deleteGoalCascade(subID)
+		}
+	}
+	for cID, c := range r.contributions {
+		if c.GoalID == id {
+			delete(r.contributions, cID)
+			removed++
+		}
+	}
+	for geID, ge := range r.goalExpenses {
+		if ge.GoalID == id {
+			delete(r.goalExpenses, geID)
+		}
+	}
+	for scID, sc := range r.statusChanges {
+		if sc.GoalID == id {
+			delete(r.statusChanges, scID)
+		}
+	}
+	for eID, e := range r.expenses {
+		if e.GoalID != nil && *e.GoalID == id {
+			e.GoalID = nil
+			r.expenses[eID] = e
+		}
+	}
+	return removed
+}
+
+// GetMonthlySummary aggregates income, expenses, savings and breakdowns for a given month
+func (r *InMemoryFinanceRepository) GetMonthlySummary(userID uuid.UUID, year int, month int) (*models.MonthlySummary, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	summary := &models.MonthlySummary{
+		Year:              year,
+		Month:             month,
+		CategoryBreakdown: map[string]float64{},
+		CategorySpending:  map[uuid.UUID]float64{},
+		GoalSpending:      map[uuid.UUID]float64{},
+		GoalContributions: map[uuid.UUID]float64{},
+		MerchantSpending:  map[uuid.UUID]float64{},
+		TagIncome:         map[string]float64{},
+		TagSpending:       map[string]float64{},
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, i := range r.incomes {
+		if i.UserID == userID && !i.DeletedAt.Valid && inRange(i.ReceivedAt, start, end) {
+			summary.TotalIncome += i.Amount
+			for _, tag := range i.Tags {
+				summary.TagIncome[tag] += i.Amount
+			}
+		}
+	}
+	for _, e := range r.expenses {
+		if e.UserID != userID || e.DeletedAt.Valid || !inRange(e.SpentAt, start, end) {
+			continue
+		}
+		summary.TotalExpenses += e.Amount
+		summary.CategoryBreakdown[e.Category] += e.Amount
+		if e.CategoryID != nil {
+			summary.CategorySpending[*e.CategoryID] += e.Amount
+		}
+		if e.GoalID != nil {
+			summary.GoalSpending[*e.GoalID] += e.Amount
+		}
+		if e.MerchantID != nil {
+			summary.MerchantSpending[*e.MerchantID] += e.Amount
+		}
+		for _, tag := range e.Tags {
+			summary.TagSpending[tag] += e.Amount
+		}
+	}
+	for _, c := range r.contributions {
+		if c.UserID == userID && !c.DeletedAt.Valid && inRange(c.ContributedAt, start, end) {
+			summary.GoalContributions[c.GoalID] += c.Amount
+			summary.TotalSavings += c.Amount
+		}
+	}
+
+	return summary, nil
+}
+
+// ListMainGoalsWithSubgoals returns main goals with their sub-goals
+func (r *InMemoryFinanceRepository) ListMainGoalsWithSubgoals(userID uuid.UUID) ([]models.GoalWithSubgoals, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mainGoals := r.userGoals(userID, func(g models.Goal) bool { return g.IsMainGoal })
+	sortGoalsByCreated(mainGoals, true)
+
+	var result []models.GoalWithSubgoals
+	for _, mainGoal := range mainGoals {
+		subgoals := r.userGoals(userID, func(g models.Goal) bool {
+			return g.ParentGoalID != nil && *g.ParentGoalID == mainGoal.ID
+		})
+		sortGoalsByCreated(subgoals, false)
+		result = append(result, models.GoalWithSubgoals{Goal: mainGoal, Subgoals: subgoals})
+	}
+	return result, nil
+}
+
+// CreateGoalExpense associates an expense with a goal
+func (r *InMemoryFinanceRepository) CreateGoalExpense(goalExpense *models.GoalExpense) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if goalExpense.ID == uuid.Nil {
+		goalExpense.ID = uuid.New()
+	}
+	if goalExpense.CreatedAt.IsZero() {
+		goalExpense.CreatedAt = time.Now().UTC()
+	}
+	r.goalExpenses[goalExpense.ID] = *goalExpense
+	return nil
+}
+
+// ListGoalExpenses returns expenses associated with a specific goal
+func (r *InMemoryFinanceRepository) ListGoalExpenses(userID uuid.UUID, goalID uuid.UUID) ([]models.GoalExpense, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	goalExpenses := []models.GoalExpense{}
+	for _, ge := range r.goalExpenses {
+		// Links to trashed expenses are hidden until the expense is restored
+		if ge.UserID == userID && ge.GoalID == goalID && !r.expenses[ge.ExpenseID].DeletedAt.Valid {
+			goalExpenses = append(goalExpenses, ge)
+		}
+	}
+	sort.SliceStable(goalExpenses, func(i, j int) bool { return goalExpenses[i].CreatedAt.After(goalExpenses[j].CreatedAt) })
+	return goalExpenses, nil
+}
+
+// userGoals returns copies of the user's live goals accepted by keep. Caller holds the lock.
+func (r *InMemoryFinanceRepository) userGoals(userID uuid.UUID, keep func(models.Goal) bool) []models.Goal {
+	goals := []models.Goal{}
+	for _, g := range r.goals {
+		if g.UserID == userID && !g.DeletedAt.Valid && keep(g) {
+			goals = append(goals, cloneGoal(g))
+		}
+	}
+	return goals
+}
+
+func sortGoalsByCreated(goals []models.Goal, desc bool) {
+	sort.SliceStable(goals, func(i, j int) bool {
+		if desc {
+			return goals[i].CreatedAt.After(goals[j].CreatedAt)
+		}
+		return goals[i].CreatedAt.Before(goals[j].CreatedAt)
+	})
+}
+
+func cloneIncome(i models.Income) models.Income {
+	i.Tags = cloneStrings(i.Tags)
+	if i.SecurityID != nil {
+		id := *i.SecurityID
+		i.SecurityID = &id
+	}
+	return i
+}
+
+func cloneExpense(e models.Expense) models.Expense {
+	e.Tags = cloneStrings(e.Tags)
+	if e.CategoryID != nil {
+		id := *e.CategoryID
+		e.CategoryID = &id
+	}
+	if e.GoalID != nil {
+		id := *e.GoalID
+		e.GoalID = &id
+	}
+	if e.MerchantID != nil {
+		id := *e.MerchantID
+		e.MerchantID = &id
+	}
+	if e.DebtID != nil {
+		id := *e.DebtID
+		e.DebtID = &id
+	}
+	return e
+}
+
+func cloneGoal(g models.Goal) models.Goal {
+	if g.TargetDate != nil {
+		d := *g.TargetDate
+		g.TargetDate = &d
+	}
+	if g.ParentGoalID != nil {
+		id := *g.ParentGoalID
+		g.ParentGoalID = &id
+	}
+	if g.CategoryID != nil {
+		id := *g.CategoryID
+		g.CategoryID = &id
+	}
+	if g.StatusChangedAt != nil {
+		t := *g.StatusChangedAt
+		g.StatusChangedAt = &t
+	}
+	return g
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r *InMemoryFinanceRepository) CreateFundingRule(rule *models.GoalFundingRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = now
+	}
+	if rule.UpdatedAt.IsZero() {
+		rule.UpdatedAt = now
+	}
+	r.fundingRules[rule.ID] = *rule
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) GetFundingRule(id, userID uuid.UUID) (*models.GoalFundingRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rule, ok := r.fundingRules[id]
+	if !ok || rule.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &rule, nil
+}
+
+func (r *InMemoryFinanceRepository) ListFundingRules(userID uuid.UUID) ([]models.GoalFundingRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.fundingRulesWhere(func(rule models.GoalFundingRule) bool { return rule.UserID == userID }), nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateFundingRule(id, userID uuid.UUID, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.fundingRules[id]
+	if !ok || rule.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	if err := applyUpdates(&rule, updates); err != nil {
+		return err
+	}
+	rule.UpdatedAt = time.Now().UTC()
+	r.fundingRules[id] = rule
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteFundingRule(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.fundingRules[id]
+	if !ok || rule.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.fundingRules, id)
+	for cid, c := range r.contributions {
+		if c.FundingRuleID != nil && *c.FundingRuleID == id {
+			c.FundingRuleID = nil
+			r.contributions[cid] = c
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) ListScheduledFundingRules() ([]models.GoalFundingRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.fundingRulesWhere(func(rule models.GoalFundingRule) bool {
+		return rule.Enabled && rule.Type == models.FundingFixedMonthly
+	}), nil
+}
+
+func (r *InMemoryFinanceRepository) ClaimScheduledFunding(id, userID uuid.UUID, from *time.Time, to time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.fundingRules[id]
+	if !ok || rule.UserID != userID {
+		return false, nil
+	}
+	if (rule.LastFundedOn == nil) != (from == nil) || (from != nil && !rule.LastFundedOn.Equal(*from)) {
+		return false, nil
+	}
+	rule.LastFundedOn = &to
+	rule.UpdatedAt = time.Now().UTC()
+	r.fundingRules[id] = rule
+	return true, nil
+}
+
+// fundingRulesWhere returns the rules keep accepts, oldest first. Callers hold the lock.
+func (r *InMemoryFinanceRepository) fundingRulesWhere(keep func(models.GoalFundingRule) bool) []models.GoalFundingRule {
+	rules := []models.GoalFundingRule{}
+	for _, rule := range r.fundingRules {
+		if keep(rule) {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].CreatedAt.Before(rules[j].CreatedAt) })
+	return rules
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r *InMemoryFinanceRepository) CreateGoalCategory(cat *models.GoalCategory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.goalCategoryNameTaken(*cat.UserID, cat.ID, cat.Name) {
+		return ErrGoalCategoryExists
+	}
+	if cat.ID == uuid.Nil {
+		cat.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if cat.CreatedAt.IsZero() {
+		cat.CreatedAt = now
+	}
+	if cat.UpdatedAt.IsZero() {
+		cat.UpdatedAt = now
+	}
+	r.goalCategories[cat.ID] = cloneGoalCategory(*cat)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) GetGoalCategory(id, userID uuid.UUID) (*models.GoalCategory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cat, ok := r.goalCategories[id]
+	if !ok || (cat.UserID != nil && *cat.UserID != userID) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cat = cloneGoalCategory(cat)
+	return &cat, nil
+}
+
+func (r *InMemoryFinanceRepository) ListGoalCategories(userID uuid.UUID) ([]models.GoalCategory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	categories := make([]models.GoalCategory, 0, len(r.goalCategories))
+	for _, c := range r.goalCategories {
+		if c.UserID == nil || *c.UserID == userID {
+			categories = append(categories, cloneGoalCategory(c))
+		}
+	}
+	sort.SliceStable(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateGoalCategory(id, userID uuid.UUID, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cat, ok := r.goalCategories[id]
+	if !ok || cat.UserID == nil || *cat.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	if err := applyUpdates(&cat, updates); err != nil {
+		return err
+	}
+	_, renamed := updates["name"]
+	if renamed && r.goalCategoryNameTaken(userID, id, cat.Name) {
+		return ErrGoalCategoryExists
+	}
+	r.goalCategories[id] = cloneGoalCategory(cat)
+	if !renamed {
+		return nil
+	}
+	for gID, g := range r.goals {
+		if g.UserID != userID || g.CategoryID == nil || *g.CategoryID != id {
+			continue
+		}
+		g.Category = cat.Name
+		g.Version++
+		r.goals[gID] = cloneGoal(g)
+	}
+	return nil
+}
+
+// goalCategoryNameTaken reports whether a built-in category or another of the
+// user's own is named name, ignoring case
+func (r *InMemoryFinanceRepository) goalCategoryNameTaken(userID, id uuid.UUID, name string) bool {
+	for _, c := range r.goalCategories {
+		if c.ID != id && (c.UserID == nil || *c.UserID == userID) && strings.EqualFold(c.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneGoalCategory(c models.GoalCategory) models.GoalCategory {
+	if c.UserID != nil {
+		id := *c.UserID
+		c.UserID = &id
+	}
+	return c
+}
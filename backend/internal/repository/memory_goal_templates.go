@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// defaultGoalTemplates matches the rows seeded by migration 020
+var defaultGoalTemplates = []struct {
+	description string
+	goal        models.TemplateGoal
+}{
+	{"Six months of average expenses set aside within a year", models.TemplateGoal{
+		Name: "Emergency fund", Description: "Six months of essential expenses", Category: "Emergency",
+		Target: "6 * avg_monthly_expenses", Months: 12,
+	}},
+	{"A wedding budget split into the usual costs", models.TemplateGoal{
+		Name: "Wedding", Description: "Ceremony and reception", Category: "Lifestyle",
+		Target: "4 * avg_monthly_income", Months: 18,
+		Subgoals: []models.TemplateGoal{
+			{Name: "Venue", Target: "parent * 0.4", Months: 12},
+			{Name: "Catering", Target: "parent * 0.3", Months: 18},
+			{Name: "Attire", Target: "parent * 0.1", Months: 15},
+			{Name: "Photography", Target: "parent * 0.1", Months: 15},
+			{Name: "Rings", Target: "parent * 0.1", Months: 9},
+		},
+	}},
+	{"A deposit and closing costs saved over five years", models.TemplateGoal{
+		Name: "Home down payment", Description: "Deposit and closing costs", Category: "Investment",
+		Target: "20 * avg_monthly_income", Months: 60,
+		Subgoals: []models.TemplateGoal{
+			{Name: "Deposit", Target: "parent * 0.85", Months: 60},
+			{Name: "Closing costs", Target: "parent * 0.15", Months: 60},
+		},
+	}},
+	{"A month of income put towards next year's trip", models.TemplateGoal{
+		Name: "Holiday", Category: "Travel", Target: "avg_monthly_income", Months: 12,
+	}},
+}
+
+func (r *InMemoryFinanceRepository) seedGoalTemplates(now time.Time) {
+	for _, t := range defaultGoalTemplates {
+		definition, _ := json.Marshal(t.goal)
+		template := models.GoalTemplate{
+			ID:          uuid.New(),
+			Name:        t.goal.Name,
+			Description: t.description,
+			Definition:  string(definition),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		r.goalTemplates[template.ID] = template
+	}
+}
+
+func (r *InMemoryFinanceRepository) CreateGoalTemplate(template *models.GoalTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if template.ID == uuid.Nil {
+		template.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = now
+	}
+	if template.UpdatedAt.IsZero() {
+		template.UpdatedAt = now
+	}
+	r.goalTemplates[template.ID] = cloneGoalTemplate(*template)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) GetGoalTemplate(id, userID uuid.UUID) (*models.GoalTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	template, ok := r.goalTemplates[id]
+	if !ok || (template.UserID != nil && *template.UserID != userID) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	template = cloneGoalTemplate(template)
+	return &template, nil
+}
+
+func (r *InMemoryFinanceRepository) ListGoalTemplates(userID uuid.UUID) ([]models.GoalTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	templates := []models.GoalTemplate{}
+	for _, t := range r.goalTemplates {
+		if t.UserID == nil || *t.UserID == userID {
+			templates = append(templates, cloneGoalTemplate(t))
+		}
+	}
+	sort.SliceStable(templates, func(i, j int) bool {
+		if templates[i].Name != templates[j].Name {
+			return templates[i].Name < templates[j].Name
+		}
+		return templates[i].CreatedAt.Before(templates[j].CreatedAt)
+	})
+	return templates, nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateGoalTemplate(id, userID uuid.UUID, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	template, ok := r.goalTemplates[id]
+	if !ok || template.UserID == nil || *template.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	if err := applyUpdates(&template, updates); err != nil {
+		return err
+	}
+	r.goalTemplates[id] = cloneGoalTemplate(template)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteGoalTemplate(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	template, ok := r.goalTemplates[id]
+	if !ok || template.UserID == nil || *template.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.goalTemplates, id)
+	return nil
+}
+
+func cloneGoalTemplate(t models.GoalTemplate) models.GoalTemplate {
+	if t.UserID != nil {
+		id := *t.UserID
+		t.UserID = &id
+	}
+	return t
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type idempotencyKeyID struct {
+	userID uuid.UUID
+	key    string
+}
+
+// InMemoryIdempotencyRepository is a thread-safe IdempotencyRepositoryInterface backed by a map
+type InMemoryIdempotencyRepository struct {
+	mu   sync.Mutex
+	keys map[idempotencyKeyID]models.IdempotencyKey
+}
+
+// NewInMemoryIdempotencyRepository creates an empty in-memory idempotency key store
+func NewInMemoryIdempotencyRepository() *InMemoryIdempotencyRepository {
+	return &InMemoryIdempotencyRepository{keys: make(map[idempotencyKeyID]models.IdempotencyKey)}
+}
+
+// CreateIdempotencyKey reserves a key, returning ErrIdempotencyKeyExists if it is taken
+func (r *InMemoryIdempotencyRepository) CreateIdempotencyKey(record *models.IdempotencyKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := idempotencyKeyID{record.UserID, record.Key}
+	if _, ok := r.keys[id]; ok {
+		return ErrIdempotencyKeyExists
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now().UTC()
+	}
+	r.keys[id] = cloneIdempotencyKey(*record)
+	return nil
+}
+
+// GetIdempotencyKey retrieves a reserved key
+func (r *InMemoryIdempotencyRepository) GetIdempotencyKey(userID uuid.UUID, key string) (*models.IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.keys[idempotencyKeyID{userID, key}]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	record = cloneIdempotencyKey(record)
+	return &record, nil
+}
+
+// CompleteIdempotencyKey stores the response of the request that reserved the key
+func (r *InMemoryIdempotencyRepository) CompleteIdempotencyKey(userID uuid.UUID, key string, statusCode int, contentType string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := idempotencyKeyID{userID, key}
+	record, ok := r.keys[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	now := time.Now().UTC()
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.ResponseBody = body
+	record.CompletedAt = &now
+	r.keys[id] = cloneIdempotencyKey(record)
+	return nil
+}
+
+// DeleteIdempotencyKey releases a key so the request can be retried
+func (r *InMemoryIdempotencyRepository) DeleteIdempotencyKey(userID uuid.UUID, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.keys, idempotencyKeyID{userID, key})
+	return nil
+}
+
+// PurgeIdempotencyKeys removes keys created before the cutoff
+func (r *InMemoryIdempotencyRepository) PurgeIdempotencyKeys(before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var purged int64
+	for id, record := range r.keys {
+		if record.CreatedAt.Before(before) {
+			delete(r.keys, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func cloneIdempotencyKey(k models.IdempotencyKey) models.IdempotencyKey {
+	if k.ResponseBody != nil {
+		k.ResponseBody = append([]byte(nil), k.ResponseBody...)
+	}
+	if k.CompletedAt != nil {
+		completedAt := *k.CompletedAt
+		k.CompletedAt = &completedAt
+	}
+	return k
+}
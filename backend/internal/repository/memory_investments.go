@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// priceKey identifies a stored closing price: one per security and day
+type priceKey struct {
+	securityID uuid.UUID
+	day        string
+}
+
+func newPriceKey(price models.SecurityPrice) priceKey {
+	return priceKey{securityID: price.SecurityID, day: price.PricedOn.Format("2006-01-02")}
+}
+
+func (r *InMemoryFinanceRepository) CreateSecurity(security *models.Security) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.securitySymbolTaken(security.UserID, security.ID, security.Symbol) {
+		return ErrSecurityExists
+	}
+	if security.ID == uuid.Nil {
+		security.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if security.CreatedAt.IsZero() {
+		security.CreatedAt = now
+	}
+	if security.UpdatedAt.IsZero() {
+		security.UpdatedAt = now
+	}
+	r.securities[security.ID] = *security
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) GetSecurity(id, userID uuid.UUID) (*models.Security, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	security, ok := r.securities[id]
+	if !ok || security.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &security, nil
+}
+
+func (r *InMemoryFinanceRepository) ListSecurities(userID uuid.UUID) ([]models.Security, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	securities := []models.Security{}
+	for _, security := range r.securities {
+		if security.UserID == userID {
+			securities = append(securities, security)
+		}
+	}
+	sort.Slice(securities, func(i, j int) bool { return securities[i].Symbol < securities[j].Symbol })
+	return securities, nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateSecurity(id, userID uuid.UUID, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	security, ok := r.securities[id]
+	if !ok || security.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	if symbol, ok := updates["symbol"].(string); ok && r.securitySymbolTaken(userID, id, symbol) {
+		return ErrSecurityExists
+	}
+	if err := applyUpdates(&security, updates); err != nil {
+		return err
+	}
+	security.UpdatedAt = time.Now().UTC()
+	r.securities[id] = security
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteSecurity(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	security, ok := r.securities[id]
+	if !ok || security.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	for iID, i := range r.incomes {
+		if i.UserID == userID && i.SecurityID != nil && *i.SecurityID == id {
+			i.SecurityID = nil
+			i.Version++
+			r.incomes[iID] = i
+		}
+	}
+	for lotID, lot := range r.lots {
+		if lot.SecurityID == id {
+			delete(r.lots, lotID)
+		}
+	}
+	for saleID, sale := range r.sales {
+		if sale.SecurityID == id {
+			delete(r.sales, saleID)
+		}
+	}
+	for key := range r.prices {
+		if key.securityID == id {
+			delete(r.prices, key)
+		}
+	}
+	delete(r.securities, id)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) CreateInvestmentLot(lot *models.InvestmentLot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if lot.ID == uuid.Nil {
+		lot.ID = uuid.New()
+	}
+	if lot.CreatedAt.IsZero() {
+		lot.CreatedAt = time.Now().UTC()
+	}
+	r.lots[lot.ID] = *lot
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) ListInvestmentLots(userID uuid.UUID) ([]models.InvestmentLot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	lots := []models.InvestmentLot{}
+	for _, lot := range r.lots {
+		if lot.UserID == userID {
+			lots = append(lots, lot)
+		}
+	}
+	sort.Slice(lots, func(i, j int) bool {
+		if !lots[i].BoughtOn.Equal(lots[j].BoughtOn) {
+			return lots[i].BoughtOn.Before(lots[j].BoughtOn)
+		}
+		return lots[i].CreatedAt.Before(lots[j].CreatedAt)
+	})
+	return lots, nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteInvestmentLot(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lot, ok := r.lots[id]
+	if !ok || lot.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.lots, id)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) CreateInvestmentSale(sale *models.InvestmentSale) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if sale.ID == uuid.Nil {
+		sale.ID = uuid.New()
+	}
+	if sale.CreatedAt.IsZero() {
+		sale.CreatedAt = time.Now().UTC()
+	}
+	r.sales[sale.ID] = *sale
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) ListInvestmentSales(userID uuid.UUID) ([]models.InvestmentSale, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sales := []models.InvestmentSale{}
+	for _, sale := range r.sales {
+		if sale.UserID == userID {
+			sales = append(sales, sale)
+		}
+	}
+	sort.Slice(sales, func(i, j int) bool {
+		if !sales[i].SoldOn.Equal(sales[j].SoldOn) {
+			return sales[i].SoldOn.Before(sales[j].SoldOn)
+		}
+		return sales[i].CreatedAt.Before(sales[j].CreatedAt)
+	})
+	return sales, nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteInvestmentSale(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sale, ok := r.sales[id]
+	if !ok || sale.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.sales, id)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) SaveSecurityPrices(prices []models.SecurityPrice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, price := range prices {
+		r.prices[newPriceKey(price)] = price
+	}
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) ListSecurityPrices(securityID, userID uuid.UUID, from, to time.Time) ([]models.SecurityPrice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prices := []models.SecurityPrice{}
+	for key, price := range r.prices {
+		if key.securityID == securityID && price.UserID == userID && !price.PricedOn.Before(from) && price.PricedOn.Before(to) {
+			prices = append(prices, price)
+		}
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].PricedOn.Before(prices[j].PricedOn) })
+	return prices, nil
+}
+
+func (r *InMemoryFinanceRepository) LatestSecurityPrices(userID uuid.UUID, day time.Time) (map[uuid.UUID]models.SecurityPrice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	latest := map[uuid.UUID]models.SecurityPrice{}
+	for _, price := range r.prices {
+		if price.UserID != userID || price.PricedOn.After(day) {
+			continue
+		}
+		if last, ok := latest[price.SecurityID]; !ok || price.PricedOn.After(last.PricedOn) {
+			latest[price.SecurityID] = price
+		}
+	}
+	return latest, nil
+}
+
+func (r *InMemoryFinanceRepository) ListDividends(userID uuid.UUID) ([]models.Income, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	dividends := []models.Income{}
+	for _, i := range r.incomes {
+		if i.UserID == userID && !i.DeletedAt.Valid && i.SecurityID != nil {
+			dividends = append(dividends, cloneIncome(i))
+		}
+	}
+	sort.Slice(dividends, func(i, j int) bool {
+		if !dividends[i].ReceivedAt.Equal(dividends[j].ReceivedAt) {
+			return dividends[i].ReceivedAt.Before(dividends[j].ReceivedAt)
+		}
+		return dividends[i].CreatedAt.Before(dividends[j].CreatedAt)
+	})
+	return dividends, nil
+}
+
+// securitySymbolTaken reports whether another of the user's securities has the symbol; callers hold mu
+func (r *InMemoryFinanceRepository) securitySymbolTaken(userID, id uuid.UUID, symbol string) bool {
+	for _, security := range r.securities {
+		if security.UserID == userID && security.ID != id && security.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func (r *InMemoryFinanceRepository) CreateGoalStatusChange(change *models.GoalStatusChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if change.ID == uuid.Nil {
+		change.ID = uuid.New()
+	}
+	if change.ChangedAt.IsZero() {
+		change.ChangedAt = time.Now().UTC()
+	}
+	r.statusChanges[change.ID] = *change
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) ListGoalStatusChanges(goalID, userID uuid.UUID) ([]models.GoalStatusChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	changes := []models.GoalStatusChange{}
+	for _, change := range r.statusChanges {
+		if change.GoalID == goalID && change.UserID == userID {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ChangedAt.Before(changes[j].ChangedAt) })
+	return changes, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r *InMemoryFinanceRepository) CreateMerchant(merchant *models.Merchant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.merchants {
+		if m.UserID == merchant.UserID && strings.EqualFold(m.Name, merchant.Name) {
+			return ErrMerchantExists
+		}
+	}
+	if merchant.ID == uuid.Nil {
+		merchant.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if merchant.CreatedAt.IsZero() {
+		merchant.CreatedAt = now
+	}
+	if merchant.UpdatedAt.IsZero() {
+		merchant.UpdatedAt = now
+	}
+	r.merchants[merchant.ID] = *merchant
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) GetMerchant(id, userID uuid.UUID) (*models.Merchant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	merchant, ok := r.merchants[id]
+	if !ok || merchant.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &merchant, nil
+}
+
+func (r *InMemoryFinanceRepository) ListMerchants(userID uuid.UUID) ([]models.Merchant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	merchants := []models.Merchant{}
+	for _, m := range r.merchants {
+		if m.UserID == userID {
+			merchants = append(merchants, m)
+		}
+	}
+	sort.Slice(merchants, func(i, j int) bool {
+		return strings.ToLower(merchants[i].Name) < strings.ToLower(merchants[j].Name)
+	})
+	return merchants, nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateMerchant(id, userID uuid.UUID, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	merchant, ok := r.merchants[id]
+	if !ok || merchant.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	if err := applyUpdates(&merchant, updates); err != nil {
+		return err
+	}
+	r.merchants[id] = merchant
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteMerchant(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	merchant, ok := r.merchants[id]
+	if !ok || merchant.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	r.relinkMerchantExpenses(userID, id, nil)
+	for pID, p := range r.patterns {
+		if p.MerchantID == id {
+			delete(r.patterns, pID)
+		}
+	}
+	delete(r.merchants, id)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) CreateMerchantPattern(pattern *models.MerchantPattern) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.patterns {
+		if p.UserID == pattern.UserID && p.Pattern == pattern.Pattern && p.MatchType == pattern.MatchType {
+			return ErrMerchantPatternExists
+		}
+	}
+	if pattern.ID == uuid.Nil {
+		pattern.ID = uuid.New()
+	}
+	if pattern.CreatedAt.IsZero() {
+		pattern.CreatedAt = time.Now().UTC()
+	}
+	r.patterns[pattern.ID] = *pattern
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) ListMerchantPatterns(userID uuid.UUID) ([]models.MerchantPattern, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	patterns := []models.MerchantPattern{}
+	for _, p := range r.patterns {
+		if p.UserID == userID {
+			patterns = append(patterns, p)
+		}
+	}
+	sort.Slice(patterns, func(i, j int) bool { return patterns[i].CreatedAt.Before(patterns[j].CreatedAt) })
+	return patterns, nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteMerchantPattern(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pattern, ok := r.patterns[id]
+	if !ok || pattern.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.patterns, id)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) MergeMerchants(userID, targetID uuid.UUID, sourceIDs []uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range append([]uuid.UUID{targetID}, sourceIDs...) {
+		if m, ok := r.merchants[id]; !ok || m.UserID != userID {
+			return 0, gorm.ErrRecordNotFound
+		}
+	}
+
+	var moved int64
+	for _, id := range sourceIDs {
+		moved += r.relinkMerchantExpenses(userID, id, &targetID)
+		for pID, p := range r.patterns {
+			if p.MerchantID == id {
+				p.MerchantID = targetID
+				r.patterns[pID] = p
+			}
+		}
+		delete(r.merchants, id)
+	}
+	return moved, nil
+}
+
+// relinkMerchantExpenses points every expense of merchant id, including deleted
+// ones, at target and returns how many changed. Callers must hold the write lock.
+func (r *InMemoryFinanceRepository) relinkMerchantExpenses(userID, id uuid.UUID, target *uuid.UUID) int64 {
+	var changed int64
+	for eID, e := range r.expenses {
+		if e.UserID != userID || e.MerchantID == nil || *e.MerchantID != id {
+			continue
+		}
+		if target != nil {
+			merchantID := *target
+			e.MerchantID = &merchantID
+		} else {
+			e.MerchantID = nil
+		}
+		e.Version++
+		r.expenses[eID] = e
+		changed++
+	}
+	return changed
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r *InMemoryFinanceRepository) CreateNetWorthItem(item *models.NetWorthItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if item.ID == uuid.Nil {
+		item.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = now
+	}
+	if item.UpdatedAt.IsZero() {
+		item.UpdatedAt = now
+	}
+	r.netWorthItems[item.ID] = *item
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) GetNetWorthItem(id, userID uuid.UUID) (*models.NetWorthItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	item, ok := r.netWorthItems[id]
+	if !ok || item.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &item, nil
+}
+
+func (r *InMemoryFinanceRepository) ListNetWorthItems(userID uuid.UUID) ([]models.NetWorthItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	items := []models.NetWorthItem{}
+	for _, item := range r.netWorthItems {
+		if item.UserID == userID {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.Before(items[j].CreatedAt) })
+	return items, nil
+}
+
+func (r *InMemoryFinanceRepository) UpdateNetWorthItem(id, userID uuid.UUID, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.netWorthItems[id]
+	if !ok || item.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	if err := applyUpdates(&item, updates); err != nil {
+		return err
+	}
+	item.UpdatedAt = time.Now().UTC()
+	r.netWorthItems[id] = item
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteNetWorthItem(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.netWorthItems[id]
+	if !ok || item.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	for vID, v := range r.valuations {
+		if v.ItemID == id {
+			delete(r.valuations, vID)
+		}
+	}
+	delete(r.netWorthItems, id)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) SaveNetWorthValuation(valuation *models.NetWorthValuation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range r.valuations {
+		if v.ItemID == valuation.ItemID && v.ValuedOn.Equal(valuation.ValuedOn) {
+			valuation.ID = v.ID
+			valuation.CreatedAt = v.CreatedAt
+			v.Value = valuation.Value
+			v.Note = valuation.Note
+			r.valuations[v.ID] = v
+			return nil
+		}
+	}
+	if valuation.ID == uuid.Nil {
+		valuation.ID = uuid.New()
+	}
+	if valuation.CreatedAt.IsZero() {
+		valuation.CreatedAt = time.Now().UTC()
+	}
+	r.valuations[valuation.ID] = *valuation
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) ListNetWorthValuations(userID uuid.UUID) ([]models.NetWorthValuation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	valuations := []models.NetWorthValuation{}
+	for _, v := range r.valuations {
+		if v.UserID == userID {
+			valuations = append(valuations, v)
+		}
+	}
+	sort.Slice(valuations, func(i, j int) bool {
+		if !valuations[i].ValuedOn.Equal(valuations[j].ValuedOn) {
+			return valuations[i].ValuedOn.Before(valuations[j].ValuedOn)
+		}
+		return valuations[i].CreatedAt.Before(valuations[j].CreatedAt)
+	})
+	return valuations, nil
+}
+
+func (r *InMemoryFinanceRepository) DeleteNetWorthValuation(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.valuations[id]
+	if !ok || v.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.valuations, id)
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) CreateNetWorthSnapshot(snapshot *models.NetWorthSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.snapshots {
+		if s.UserID == snapshot.UserID && s.TakenOn.Equal(snapshot.TakenOn) {
+			return ErrNetWorthSnapshotExists
+		}
+	}
+	if snapshot.ID == uuid.Nil {
+		snapshot.ID = uuid.New()
+	}
+	if snapshot.CreatedAt.IsZero() {
+		snapshot.CreatedAt = time.Now().UTC()
+	}
+	r.snapshots[snapshot.ID] = *snapshot
+	return nil
+}
+
+func (r *InMemoryFinanceRepository) ListNetWorthSnapshots(userID uuid.UUID, from, to time.Time) ([]models.NetWorthSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snapshots := []models.NetWorthSnapshot{}
+	for _, s := range r.snapshots {
+		if s.UserID == userID && !s.TakenOn.Before(from) && s.TakenOn.Before(to) {
+			snapshots = append(snapshots, s)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].TakenOn.Before(snapshots[j].TakenOn) })
+	return snapshots, nil
+}
+
+func (r *InMemoryFinanceRepository) ListNetWorthUsers() ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	seen := map[uuid.UUID]bool{}
+	for _, i := range r.incomes {
+		if !i.DeletedAt.Valid {
+			seen[i.UserID] = true
+		}
+	}
+	for _, e := range r.expenses {
+		if !e.DeletedAt.Valid {
+			seen[e.UserID] = true
+		}
+	}
+	for _, d := range r.debts {
+		seen[d.UserID] = true
+	}
+	for _, item := range r.netWorthItems {
+		seen[item.UserID] = true
+	}
+	for _, security := range r.securities {
+		seen[security.UserID] = true
+	}
+	users := make([]uuid.UUID, 0, len(seen))
+	for id := range seen {
+		users = append(users, id)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].String() < users[j].String() })
+	return users, nil
+}
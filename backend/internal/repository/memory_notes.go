@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InMemoryNotesRepository is a thread-safe NotesRepositoryInterface backed by a map.
+// Errors use the same messages as NotesRepository so services behave identically.
+type InMemoryNotesRepository struct {
+	mu    sync.RWMutex
+	notes map[uuid.UUID]models.Note
+}
+
+// NewInMemoryNotesRepository creates an empty in-memory notes repository
+func NewInMemoryNotesRepository() *InMemoryNotesRepository {
+	return &InMemoryNotesRepository{notes: map[uuid.UUID]models.Note{}}
+}
+
+// Transaction restores the notes when fn fails. Like InMemoryFinanceRepository it
+// does not isolate fn from concurrent writers.
+func (r *InMemoryNotesRepository) Transaction(fn func(repo NotesRepositoryInterface) error) error {
+	r.mu.RLock()
+	saved := make(map[uuid.UUID]models.Note, len(r.notes))
+	for id, note := range r.notes {
+		saved[id] = cloneNote(note)
+	}
+	r.mu.RUnlock()
+	if err := fn(r); err != nil {
+		r.mu.Lock()
+		r.notes = saved
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// CreateNote stores a new note
+func (r *InMemoryNotesRepository) CreateNote(note *models.Note) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if note.ID == uuid.Nil {
+		note.ID = uuid.New()
+	}
+	if note.Version == 0 {
+		note.Version = 1
+	}
+	now := time.Now().UTC()
+	if note.CreatedAt.IsZero() {
+		note.CreatedAt = now
+	}
+	if note.UpdatedAt.IsZero() {
+		note.UpdatedAt = now
+	}
+	r.notes[note.ID] = cloneNote(*note)
+	return nil
+}
+
+// GetNoteByID retrieves a note by its ID and user ID
+func (r *InMemoryNotesRepository) GetNoteByID(id, userID uuid.UUID) (*models.Note, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	note, ok := r.notes[id]
+	if !ok || note.UserID != userID || note.DeletedAt.Valid {
+		return nil, fmt.Errorf("note not found")
+	}
+	note = cloneNote(note)
+	return &note, nil
+}
+
+// GetNotesByUserID retrieves all notes for a user, newest first
+func (r *InMemoryNotesRepository) GetNotesByUserID(userID uuid.UUID) ([]models.Note, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	notes := []models.Note{}
+	for _, n := range r.notes {
+		if n.UserID == userID && !n.DeletedAt.Valid {
+			notes = append(notes, cloneNote(n))
+		}
+	}
+	sort.SliceStable(notes, func(i, j int) bool { return notes[i].CreatedAt.After(notes[j].CreatedAt) })
+	return notes, nil
+}
+
+// UpdateNote updates an existing note
+func (r *InMemoryNotesRepository) UpdateNote(id, userID uuid.UUID, version int, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return fmt.Errorf("no updates provided")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	note, ok := r.notes[id]
+	if !ok || note.UserID != userID || note.DeletedAt.Valid {
+		return fmt.Errorf("note not found or no changes made")
+	}
+	if version > 0 && note.Version != version {
+		return ErrVersionConflict
+	}
+	if err := applyUpdates(&note, updates); err != nil {
+		return err
+	}
+	note.Version++
+	r.notes[id] = cloneNote(note)
+	return nil
+}
+
+// DeleteNote moves a note to the trash
+func (r *InMemoryNotesRepository) DeleteNote(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	note, ok := r.notes[id]
+	if !ok || note.UserID != userID || note.DeletedAt.Valid {
+		return fmt.Errorf("note not found")
+	}
+	note.DeletedAt = deletedNow()
+	r.notes[id] = note
+	return nil
+}
+
+// ListDeletedNotes retrieves a user's trashed notes, most recently deleted first
+func (r *InMemoryNotesRepository) ListDeletedNotes(userID uuid.UUID) ([]models.Note, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	notes := []models.Note{}
+	for _, n := range r.notes {
+		if n.UserID == userID && n.DeletedAt.Valid {
+			notes = append(notes, cloneNote(n))
+		}
+	}
+	sort.SliceStable(notes, func(i, j int) bool { return notes[i].DeletedAt.Time.After(notes[j].DeletedAt.Time) })
+	return notes, nil
+}
+
+// RestoreNote takes a note out of the trash
+func (r *InMemoryNotesRepository) RestoreNote(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	note, ok := r.notes[id]
+	if !ok || note.UserID != userID || !note.DeletedAt.Valid {
+		return fmt.Errorf("note not found")
+	}
+	note.DeletedAt = gorm.DeletedAt{}
+	r.notes[id] = note
+	return nil
+}
+
+// PurgeDeletedNotes permanently removes notes of every user trashed before the cutoff
+func (r *InMemoryNotesRepository) PurgeDeletedNotes(before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var purged int64
+	for id, n := range r.notes {
+		if n.DeletedAt.Valid && n.DeletedAt.Time.Before(before) {
+			delete(r.notes, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func cloneNote(n models.Note) models.Note {
+	n.Tags = cloneStrings(n.Tags)
+	return n
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InMemoryRuleRepository is a thread-safe RuleRepositoryInterface backed by a map
+type InMemoryRuleRepository struct {
+	mu    sync.Mutex
+	rules map[uuid.UUID]models.ExpenseRule
+}
+
+// NewInMemoryRuleRepository creates an empty in-memory rule store
+func NewInMemoryRuleRepository() *InMemoryRuleRepository {
+	return &InMemoryRuleRepository{rules: make(map[uuid.UUID]models.ExpenseRule)}
+}
+
+// Transaction restores the rules when fn fails. Like InMemoryFinanceRepository it
+// does not isolate fn from concurrent writers.
+func (r *InMemoryRuleRepository) Transaction(fn func(repo RuleRepositoryInterface) error) error {
+	r.mu.Lock()
+	saved := maps.Clone(r.rules)
+	r.mu.Unlock()
+	if err := fn(r); err != nil {
+		r.mu.Lock()
+		r.rules = saved
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (r *InMemoryRuleRepository) CreateRule(rule *models.ExpenseRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.New()
+	}
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = now
+	r.rules[rule.ID] = *rule
+	return nil
+}
+
+func (r *InMemoryRuleRepository) GetRule(id, userID uuid.UUID) (*models.ExpenseRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.rules[id]
+	if !ok || rule.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &rule, nil
+}
+
+func (r *InMemoryRuleRepository) ListRules(userID uuid.UUID) ([]models.ExpenseRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rules []models.ExpenseRule
+	for _, rule := range r.rules {
+		if rule.UserID == userID {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+	return rules, nil
+}
+
+func (r *InMemoryRuleRepository) UpdateRule(id, userID uuid.UUID, updates map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.rules[id]
+	if !ok || rule.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	if err := applyUpdates(&rule, updates); err != nil {
+		return err
+	}
+	r.rules[id] = rule
+	return nil
+}
+
+func (r *InMemoryRuleRepository) DeleteRule(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.rules[id]
+	if !ok || rule.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.rules, id)
+	return nil
+}
+
+func (r *InMemoryRuleRepository) RecordRuleMatches(userID uuid.UUID, matches map[uuid.UUID]int, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, count := range matches {
+		rule, ok := r.rules[id]
+		if !ok || rule.UserID != userID {
+			continue
+		}
+		rule.MatchCount += count
+		matchedAt := at
+		rule.LastMatchedAt = &matchedAt
+		r.rules[id] = rule
+	}
+	return nil
+}
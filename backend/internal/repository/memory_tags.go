@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"slices"
+	"sort"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func (r *InMemoryFinanceRepository) ListTaggedIncomes(userID uuid.UUID, filter TagFilter, limit int) ([]models.Income, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	items := []models.Income{}
+	for _, i := range r.incomes {
+		if i.UserID == userID && !i.DeletedAt.Valid && filter.matches(i.Tags) {
+			items = append(items, cloneIncome(i))
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].ReceivedAt.After(items[b].ReceivedAt) })
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (r *InMemoryFinanceRepository) ListTaggedExpenses(userID uuid.UUID, filter TagFilter, limit int) ([]models.Expense, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	items := []models.Expense{}
+	for _, e := range r.expenses {
+		if e.UserID == userID && !e.DeletedAt.Valid && filter.matches(e.Tags) {
+			items = append(items, cloneExpense(e))
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].SpentAt.After(items[b].SpentAt) })
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// matches reports whether tags satisfy the filter
+func (f TagFilter) matches(tags []string) bool {
+	if len(f.Tags) == 0 {
+		return true
+	}
+	for _, tag := range f.Tags {
+		has := slices.Contains(tags, tag)
+		if f.Any && has {
+			return true
+		}
+		if !f.Any && !has {
+			return false
+		}
+	}
+	return !f.Any
+}
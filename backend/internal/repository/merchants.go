@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"errors"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrMerchantExists is returned when a merchant name is already used by the user
+var ErrMerchantExists = errors.New("merchant already exists")
+
+// ErrMerchantPatternExists is returned when the user already has the same pattern
+var ErrMerchantPatternExists = errors.New("merchant pattern already exists")
+
+// MerchantRepositoryInterface stores merchants and their description patterns
+type MerchantRepositoryInterface interface {
+	// CreateMerchant returns ErrMerchantExists when the name is taken, ignoring case
+	CreateMerchant(merchant *models.Merchant) error
+	GetMerchant(id, userID uuid.UUID) (*models.Merchant, error)
+	// ListMerchants returns the user's merchants ordered by name
+	ListMerchants(userID uuid.UUID) ([]models.Merchant, error)
+	UpdateMerchant(id, userID uuid.UUID, updates map[string]interface{}) error
+	// DeleteMerchant removes a merchant and its patterns and unlinks its expenses
+	DeleteMerchant(id, userID uuid.UUID) error
+	// CreateMerchantPattern returns ErrMerchantPatternExists for a duplicate pattern
+	CreateMerchantPattern(pattern *models.MerchantPattern) error
+	ListMerchantPatterns(userID uuid.UUID) ([]models.MerchantPattern, error)
+	DeleteMerchantPattern(id, userID uuid.UUID) error
+	// MergeMerchants moves the expenses and patterns of the source merchants to the
+	// target and deletes the sources, returning the number of expenses moved
+	MergeMerchants(userID, targetID uuid.UUID, sourceIDs []uuid.UUID) (int64, error)
+}
+
+func (r *FinanceRepository) CreateMerchant(merchant *models.Merchant) error {
+	tx := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(merchant)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return ErrMerchantExists
+	}
+	return nil
+}
+
+func (r *FinanceRepository) GetMerchant(id, userID uuid.UUID) (*models.Merchant, error) {
+	var merchant models.Merchant
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&merchant).Error; err != nil {
+		return nil, err
+	}
+	return &merchant, nil
+}
+
+func (r *FinanceRepository) ListMerchants(userID uuid.UUID) ([]models.Merchant, error) {
+	var merchants []models.Merchant
+	if err := r.db.Where("user_id = ?", userID).Order("LOWER(name) ASC").Find(&merchants).Error; err != nil {
+		return nil, err
+	}
+	return merchants, nil
+}
+
+func (r *FinanceRepository) UpdateMerchant(id, userID uuid.UUID, updates map[string]interface{}) error {
+	tx := r.db.Model(&models.Merchant{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) DeleteMerchant(id, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Soft-deleted expenses are unlinked too so a restore does not point at a missing merchant
+		if err := tx.Unscoped().Model(&models.Expense{}).
+			Where("merchant_id = ? AND user_id = ?", id, userID).
+			Updates(map[string]interface{}{"merchant_id": nil, "version": gorm.Expr("version + 1")}).Error; err != nil {
+			return err
+		}
+		res := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Merchant{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *FinanceRepository) CreateMerchantPattern(pattern *models.MerchantPattern) error {
+	tx := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(pattern)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return ErrMerchantPatternExists
+	}
+	return nil
+}
+
+func (r *FinanceRepository) ListMerchantPatterns(userID uuid.UUID) ([]models.MerchantPattern, error) {
+	var patterns []models.MerchantPattern
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&patterns).Error; err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+func (r *FinanceRepository) DeleteMerchantPattern(id, userID uuid.UUID) error {
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.MerchantPattern{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) MergeMerchants(userID, targetID uuid.UUID, sourceIDs []uuid.UUID) (int64, error) {
+	var moved int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		ids := append([]uuid.UUID{targetID}, sourceIDs...)
+		var found int64
+		if err := tx.Model(&models.Merchant{}).Where("user_id = ? AND id IN ?", userID, ids).Count(&found).Error; err != nil {
+			return err
+		}
+		if found != int64(len(ids)) {
+			return gorm.ErrRecordNotFound
+		}
+
+		res := tx.Unscoped().Model(&models.Expense{}).
+			Where("user_id = ? AND merchant_id IN ?", userID, sourceIDs).
+			Updates(map[string]interface{}{"merchant_id": targetID, "version": gorm.Expr("version + 1")})
+		if res.Error != nil {
+			return res.Error
+		}
+		moved = res.RowsAffected
+
+		// Patterns are unique per user, so they can move without clashing
+		if err := tx.Model(&models.MerchantPattern{}).
+			Where("user_id = ? AND merchant_id IN ?", userID, sourceIDs).
+			Update("merchant_id", targetID).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND id IN ?", userID, sourceIDs).Delete(&models.Merchant{}).Error
+	})
+	return moved, err
+}
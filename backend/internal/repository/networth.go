@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNetWorthSnapshotExists is returned when the user already has a snapshot for the day
+var ErrNetWorthSnapshotExists = errors.New("net worth snapshot already exists")
+
+// NetWorthRepositoryInterface stores manually valued assets and liabilities and net worth snapshots
+type NetWorthRepositoryInterface interface {
+	CreateNetWorthItem(item *models.NetWorthItem) error
+	GetNetWorthItem(id, userID uuid.UUID) (*models.NetWorthItem, error)
+	// ListNetWorthItems returns the user's items oldest first
+	ListNetWorthItems(userID uuid.UUID) ([]models.NetWorthItem, error)
+	UpdateNetWorthItem(id, userID uuid.UUID, updates map[string]interface{}) error
+	// DeleteNetWorthItem removes an item together with its valuations
+	DeleteNetWorthItem(id, userID uuid.UUID) error
+	// SaveNetWorthValuation stores a valuation, replacing the value and note of one
+	// the item already has on the same day; valuation then carries the stored ID
+	SaveNetWorthValuation(valuation *models.NetWorthValuation) error
+	// ListNetWorthValuations returns the valuations of all the user's items, oldest first
+	ListNetWorthValuations(userID uuid.UUID) ([]models.NetWorthValuation, error)
+	DeleteNetWorthValuation(id, userID uuid.UUID) error
+	// CreateNetWorthSnapshot returns ErrNetWorthSnapshotExists when the day already has one
+	CreateNetWorthSnapshot(snapshot *models.NetWorthSnapshot) error
+	// ListNetWorthSnapshots returns the user's snapshots taken in [from, to), oldest first
+	ListNetWorthSnapshots(userID uuid.UUID, from, to time.Time) ([]models.NetWorthSnapshot, error)
+	// ListNetWorthUsers returns every user with transactions, debts, net worth items or securities
+	ListNetWorthUsers() ([]uuid.UUID, error)
+}
+
+func (r *FinanceRepository) CreateNetWorthItem(item *models.NetWorthItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *FinanceRepository) GetNetWorthItem(id, userID uuid.UUID) (*models.NetWorthItem, error) {
+	var item models.NetWorthItem
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *FinanceRepository) ListNetWorthItems(userID uuid.UUID) ([]models.NetWorthItem, error) {
+	var items []models.NetWorthItem
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *FinanceRepository) UpdateNetWorthItem(id, userID uuid.UUID, updates map[string]interface{}) error {
+	tx := r.db.Model(&models.NetWorthItem{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) DeleteNetWorthItem(id, userID uuid.UUID) error {
+	// Valuations go with the item through ON DELETE CASCADE
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.NetWorthItem{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) SaveNetWorthValuation(valuation *models.NetWorthValuation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.NetWorthValuation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("item_id = ? AND valued_on = ?", valuation.ItemID, valuation.ValuedOn).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(valuation).Error
+		}
+		if err != nil {
+			return err
+		}
+		valuation.ID = existing.ID
+		valuation.CreatedAt = existing.CreatedAt
+		return tx.Model(&existing).Updates(map[string]interface{}{"value": valuation.Value, "note": valuation.Note}).Error
+	})
+}
+
+func (r *FinanceRepository) ListNetWorthValuations(userID uuid.UUID) ([]models.NetWorthValuation, error) {
+	var valuations []models.NetWorthValuation
+	err := r.db.Where("user_id = ?", userID).Order("valued_on ASC, created_at ASC").Find(&valuations).Error
+	if err != nil {
+		return nil, err
+	}
+	return valuations, nil
+}
+
+func (r *FinanceRepository) DeleteNetWorthValuation(id, userID uuid.UUID) error {
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.NetWorthValuation{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FinanceRepository) CreateNetWorthSnapshot(snapshot *models.NetWorthSnapshot) error {
+	tx := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(snapshot)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return ErrNetWorthSnapshotExists
+	}
+	return nil
+}
+
+func (r *FinanceRepository) ListNetWorthSnapshots(userID uuid.UUID, from, to time.Time) ([]models.NetWorthSnapshot, error) {
+	var snapshots []models.NetWorthSnapshot
+	err := r.db.Where("user_id = ? AND taken_on >= ? AND taken_on < ?", userID, from, to).
+		Order("taken_on ASC").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (r *FinanceRepository) ListNetWorthUsers() ([]uuid.UUID, error) {
+	var users []uuid.UUID
+	err := r.db.Raw(`
+		SELECT user_id FROM incomes WHERE deleted_at IS NULL
+		UNION SELECT user_id FROM expenses WHERE deleted_at IS NULL
+		UNION SELECT user_id FROM debts
+		UNION SELECT user_id FROM net_worth_items
+		UNION SELECT user_id FROM securities
+		ORDER BY user_id`).Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
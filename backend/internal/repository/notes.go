@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"finance-management/internal/models"
 
@@ -15,8 +16,14 @@ type NotesRepositoryInterface interface {
 	CreateNote(note *models.Note) error
 	GetNoteByID(id, userID uuid.UUID) (*models.Note, error)
 	GetNotesByUserID(userID uuid.UUID) ([]models.Note, error)
-	UpdateNote(id, userID uuid.UUID, updates map[string]interface{}) error
+	UpdateNote(id, userID uuid.UUID, version int, updates map[string]interface{}) error
 	DeleteNote(id, userID uuid.UUID) error
+	ListDeletedNotes(userID uuid.UUID) ([]models.Note, error)
+	RestoreNote(id, userID uuid.UUID) error
+	PurgeDeletedNotes(before time.Time) (int64, error)
+	// Transaction runs fn against a repository whose writes are committed together,
+	// or not at all when fn returns an error
+	Transaction(fn func(repo NotesRepositoryInterface) error) error
 }
 
 // NotesRepository handles database operations for notes
@@ -29,6 +36,18 @@ func NewNotesRepository(db *gorm.DB) *NotesRepository {
 	return &NotesRepository{db: db}
 }
 
+// Transaction runs fn in a database transaction; GORM turns nested calls into savepoints
+func (r *NotesRepository) Transaction(fn func(repo NotesRepositoryInterface) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&NotesRepository{db: tx})
+	})
+}
+
+// conn returns the connection, an open transaction inside Transaction
+func (r *NotesRepository) conn() *gorm.DB {
+	return r.db
+}
+
 // CreateNote creates a new note in the database
 func (r *NotesRepository) CreateNote(note *models.Note) error {
 	return r.db.Create(note).Error
@@ -59,23 +78,42 @@ func (r *NotesRepository) GetNotesByUserID(userID uuid.UUID) ([]models.Note, err
 }
 
 // UpdateNote updates an existing note
-func (r *NotesRepository) UpdateNote(id, userID uuid.UUID, updates map[string]interface{}) error {
+func (r *NotesRepository) UpdateNote(id, userID uuid.UUID, version int, updates map[string]interface{}) error {
 	if len(updates) == 0 {
 		return fmt.Errorf("no updates provided")
 	}
-	tx := r.db.Model(&models.Note{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+	err := updateVersioned(r.db, &models.Note{}, id, userID, version, updates)
+	if err == gorm.ErrRecordNotFound {
+		return fmt.Errorf("note not found or no changes made")
+	}
+	return err
+}
+
+// DeleteNote moves a note to the trash
+func (r *NotesRepository) DeleteNote(id, userID uuid.UUID) error {
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Note{})
 	if tx.Error != nil {
 		return tx.Error
 	}
 	if tx.RowsAffected == 0 {
-		return fmt.Errorf("note not found or no changes made")
+		return fmt.Errorf("note not found")
 	}
 	return nil
 }
 
-// DeleteNote deletes a note by ID and user ID
-func (r *NotesRepository) DeleteNote(id, userID uuid.UUID) error {
-	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Note{})
+// ListDeletedNotes retrieves a user's trashed notes, most recently deleted first
+func (r *NotesRepository) ListDeletedNotes(userID uuid.UUID) ([]models.Note, error) {
+	var notes []models.Note
+	err := r.db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID).Order("deleted_at DESC").Find(&notes).Error
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// RestoreNote takes a note out of the trash
+func (r *NotesRepository) RestoreNote(id, userID uuid.UUID) error {
+	tx := r.db.Unscoped().Model(&models.Note{}).Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).Update("deleted_at", nil)
 	if tx.Error != nil {
 		return tx.Error
 	}
@@ -84,3 +122,9 @@ func (r *NotesRepository) DeleteNote(id, userID uuid.UUID) error {
 	}
 	return nil
 }
+
+// PurgeDeletedNotes permanently removes notes of every user trashed before the cutoff
+func (r *NotesRepository) PurgeDeletedNotes(before time.Time) (int64, error) {
+	tx := r.db.Unscoped().Where("deleted_at < ?", before).Delete(&models.Note{})
+	return tx.RowsAffected, tx.Error
+}
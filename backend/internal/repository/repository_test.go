@@ -0,0 +1,116 @@
+package repository_test
+
+import (
+	"os"
+	"testing"
+
+	"finance-management/internal/repository"
+	"finance-management/internal/repository/repositorytest"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestInMemoryFinanceRepository(t *testing.T) {
+	repositorytest.TestFinanceRepository(t, func(t *testing.T) repository.FinanceRepositoryInterface {
+		return repository.NewInMemoryFinanceRepository()
+	})
+}
+
+func TestInMemoryNotesRepository(t *testing.T) {
+	repositorytest.TestNotesRepository(t, func(t *testing.T) repository.NotesRepositoryInterface {
+		return repository.NewInMemoryNotesRepository()
+	})
+}
+
+func TestInMemoryAuditRepository(t *testing.T) {
+	repositorytest.TestAuditRepository(t, func(t *testing.T) repository.AuditRepositoryInterface {
+		return repository.NewInMemoryAuditRepository()
+	})
+}
+
+func TestInMemoryIdempotencyRepository(t *testing.T) {
+	repositorytest.TestIdempotencyRepository(t, func(t *testing.T) repository.IdempotencyRepositoryInterface {
+		return repository.NewInMemoryIdempotencyRepository()
+	})
+}
+
+func TestInMemoryRuleRepository(t *testing.T) {
+	repositorytest.TestRuleRepository(t, func(t *testing.T) repository.RuleRepositoryInterface {
+		return repository.NewInMemoryRuleRepository()
+	})
+}
+
+func TestInMemoryAttachmentRepository(t *testing.T) {
+	repositorytest.TestAttachmentRepository(t, func(t *testing.T) repositorytest.AttachmentRepositories {
+		finance := repository.NewInMemoryFinanceRepository()
+		notes := repository.NewInMemoryNotesRepository()
+		return repositorytest.AttachmentRepositories{
+			Attachments: repository.NewInMemoryAttachmentRepository(finance, notes),
+			Finance:     finance,
+			Notes:       notes,
+		}
+	})
+}
+
+// openTestDB connects to the migrated database named by TEST_DATABASE_DSN,
+// skipping the test when it is not set
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set; skipping Postgres contract tests")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	return db
+}
+
+func TestFinanceRepository(t *testing.T) {
+	db := openTestDB(t)
+	repositorytest.TestFinanceRepository(t, func(t *testing.T) repository.FinanceRepositoryInterface {
+		return repository.NewFinanceRepository(db)
+	})
+}
+
+func TestNotesRepository(t *testing.T) {
+	db := openTestDB(t)
+	repositorytest.TestNotesRepository(t, func(t *testing.T) repository.NotesRepositoryInterface {
+		return repository.NewNotesRepository(db)
+	})
+}
+
+func TestAuditRepository(t *testing.T) {
+	db := openTestDB(t)
+	repositorytest.TestAuditRepository(t, func(t *testing.T) repository.AuditRepositoryInterface {
+		return repository.NewAuditRepository(db)
+	})
+}
+
+func TestIdempotencyRepository(t *testing.T) {
+	db := openTestDB(t)
+	repositorytest.TestIdempotencyRepository(t, func(t *testing.T) repository.IdempotencyRepositoryInterface {
+		return repository.NewIdempotencyRepository(db)
+	})
+}
+
+func TestRuleRepository(t *testing.T) {
+	db := openTestDB(t)
+	repositorytest.TestRuleRepository(t, func(t *testing.T) repository.RuleRepositoryInterface {
+		return repository.NewRuleRepository(db)
+	})
+}
+
+func TestAttachmentRepository(t *testing.T) {
+	db := openTestDB(t)
+	repositorytest.TestAttachmentRepository(t, func(t *testing.T) repositorytest.AttachmentRepositories {
+		return repositorytest.AttachmentRepositories{
+			Attachments: repository.NewAttachmentRepository(db),
+			Finance:     repository.NewFinanceRepository(db),
+			Notes:       repository.NewNotesRepository(db),
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package repositorytest
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// AttachmentRepositories are the repositories the attachment contract needs: the
+// attachment store and the stores its owners live in
+type AttachmentRepositories struct {
+	Attachments repository.AttachmentRepositoryInterface
+	Finance     repository.FinanceRepositoryInterface
+	Notes       repository.NotesRepositoryInterface
+}
+
+// TestAttachmentRepository runs the attachment repository contract against implementations created by newRepos
+func TestAttachmentRepository(t *testing.T, newRepos func(t *testing.T) AttachmentRepositories) {
+	repos := newRepos(t)
+	repo := repos.Attachments
+	userID := uuid.New()
+
+	expense := newExpense(userID, "travel", 120, date(2024, 4, 2), nil)
+	income := newIncome(userID, "salary", 3000, date(2024, 4, 1))
+	note := newNote(userID, "receipts", date(2024, 4, 3))
+	mustNoErr(t, repos.Finance.CreateExpense(expense), "CreateExpense")
+	mustNoErr(t, repos.Finance.CreateIncome(income), "CreateIncome")
+	mustNoErr(t, repos.Notes.CreateNote(note), "CreateNote")
+
+	newAttachment := func(ownerType string, ownerID uuid.UUID, name string, createdAt time.Time) *models.Attachment {
+		id := uuid.New()
+		return &models.Attachment{
+			ID:          id,
+			UserID:      userID,
+			OwnerType:   ownerType,
+			OwnerID:     ownerID,
+			FileName:    name,
+			ContentType: "image/png",
+			Size:        42,
+			StorageKey:  userID.String() + "/" + id.String(),
+			CreatedAt:   createdAt,
+		}
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	receipt := newAttachment(models.AttachmentOwnerExpense, expense.ID, "receipt.png", now.Add(-time.Minute))
+	invoice := newAttachment(models.AttachmentOwnerExpense, expense.ID, "invoice.pdf", now)
+	payslip := newAttachment(models.AttachmentOwnerIncome, income.ID, "payslip.pdf", now)
+	scan := newAttachment(models.AttachmentOwnerNote, note.ID, "scan.png", now)
+	thumbnail := receipt.StorageKey + ".thumb.jpg"
+	receipt.ThumbnailKey = &thumbnail
+	for _, a := range []*models.Attachment{invoice, receipt, payslip, scan} {
+		mustNoErr(t, repo.CreateAttachment(a), "CreateAttachment "+a.FileName)
+	}
+
+	listed, err := repo.ListAttachments(userID, models.AttachmentOwnerExpense, expense.ID)
+	mustNoErr(t, err, "ListAttachments")
+	if len(listed) != 2 || listed[0].ID != receipt.ID || listed[1].ID != invoice.ID {
+		t.Fatalf("ListAttachments: expected the expense's attachments oldest first, got %d", len(listed))
+	}
+	if other, _ := repo.ListAttachments(uuid.New(), models.AttachmentOwnerExpense, expense.ID); len(other) != 0 {
+		t.Fatalf("ListAttachments: expected nothing for another user, got %d", len(other))
+	}
+
+	got, err := repo.GetAttachment(receipt.ID, userID)
+	mustNoErr(t, err, "GetAttachment")
+	if got.FileName != "receipt.png" || got.Size != 42 || got.ThumbnailKey == nil || *got.ThumbnailKey != thumbnail {
+		t.Fatalf("GetAttachment: stored attachment does not match")
+	}
+	_, err = repo.GetAttachment(receipt.ID, uuid.New())
+	expectNotFound(t, err, "GetAttachment other user")
+
+	mustNoErr(t, repo.DeleteAttachment(invoice.ID, userID), "DeleteAttachment")
+	expectNotFound(t, repo.DeleteAttachment(invoice.ID, userID), "DeleteAttachment twice")
+
+	orphaned := func() map[uuid.UUID]bool {
+		t.Helper()
+		attachments, err := repo.ListOrphanedAttachments(1000)
+		mustNoErr(t, err, "ListOrphanedAttachments")
+		ids := make(map[uuid.UUID]bool, len(attachments))
+		for _, a := range attachments {
+			ids[a.ID] = true
+		}
+		return ids
+	}
+
+	// Records in the trash keep their attachments until they are purged
+	mustNoErr(t, repos.Finance.DeleteExpense(expense.ID, userID), "DeleteExpense")
+	mustNoErr(t, repos.Notes.DeleteNote(note.ID, userID), "DeleteNote")
+	if ids := orphaned(); ids[receipt.ID] || ids[scan.ID] || ids[payslip.ID] {
+		t.Fatalf("ListOrphanedAttachments: trashed records must still own their attachments")
+	}
+
+	future := time.Now().UTC().Add(time.Hour)
+	_, err = repos.Finance.PurgeDeleted(future)
+	mustNoErr(t, err, "PurgeDeleted")
+	_, err = repos.Notes.PurgeDeletedNotes(future)
+	mustNoErr(t, err, "PurgeDeletedNotes")
+	if ids := orphaned(); !ids[receipt.ID] || !ids[scan.ID] || ids[payslip.ID] {
+		t.Fatalf("ListOrphanedAttachments: expected the purged records' attachments only")
+	}
+}
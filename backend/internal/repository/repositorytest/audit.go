@@ -0,0 +1,57 @@
+package repositorytest
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TestAuditRepository runs the audit repository contract against implementations created by newRepo
+func TestAuditRepository(t *testing.T, newRepo func(t *testing.T) repository.AuditRepositoryInterface) {
+	repo := newRepo(t)
+	userID := uuid.New()
+	expenseID := uuid.New()
+	base := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+
+	entries := []*models.AuditEntry{
+		{UserID: userID, Actor: "alex", EntityType: "expense", EntityID: expenseID, Action: "create", Changes: `{"amount": {"after": 10}}`, CreatedAt: base},
+		{UserID: userID, Actor: "sam", EntityType: "expense", EntityID: expenseID, Action: "update", Changes: `{"amount": {"before": 10, "after": 12}}`, CreatedAt: base.Add(time.Minute)},
+		{UserID: userID, Actor: "alex", EntityType: "goal", EntityID: uuid.New(), Action: "delete", Changes: `{}`, CreatedAt: base.Add(2 * time.Minute)},
+		{UserID: uuid.New(), Actor: "alex", EntityType: "expense", EntityID: expenseID, Action: "create", Changes: `{}`, CreatedAt: base},
+	}
+	for _, e := range entries {
+		e.ID = uuid.New()
+		mustNoErr(t, repo.CreateAuditEntry(e), "CreateAuditEntry")
+	}
+
+	all, err := repo.ListAuditEntries(userID, repository.AuditFilter{})
+	mustNoErr(t, err, "ListAuditEntries")
+	if len(all) != 3 || all[0].Action != "delete" || all[2].Action != "create" {
+		t.Fatalf("ListAuditEntries: expected the user's 3 entries newest first, got %d", len(all))
+	}
+	if all[1].Actor != "sam" || all[1].EntityID != expenseID {
+		t.Fatalf("ListAuditEntries: stored entry does not match")
+	}
+
+	byEntity, _ := repo.ListAuditEntries(userID, repository.AuditFilter{EntityType: "expense", EntityID: &expenseID})
+	if len(byEntity) != 2 {
+		t.Fatalf("filter by entity: got %d entries, want 2", len(byEntity))
+	}
+	byActor, _ := repo.ListAuditEntries(userID, repository.AuditFilter{Actor: "alex"})
+	if len(byActor) != 2 {
+		t.Fatalf("filter by actor: got %d entries, want 2", len(byActor))
+	}
+	from, to := base.Add(time.Minute), base.Add(2*time.Minute)
+	byTime, _ := repo.ListAuditEntries(userID, repository.AuditFilter{From: &from, To: &to})
+	if len(byTime) != 1 || byTime[0].Action != "update" {
+		t.Fatalf("filter by time: expected only the update, got %d entries", len(byTime))
+	}
+	limited, _ := repo.ListAuditEntries(userID, repository.AuditFilter{Limit: 1})
+	if len(limited) != 1 || limited[0].Action != "delete" {
+		t.Fatalf("limit: expected the newest entry only")
+	}
+}
@@ -0,0 +1,46 @@
+package repositorytest
+
+import (
+	"testing"
+
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func testCashFlow(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	goal := newGoal(userID, "Holiday", 1000, date(2024, 1, 1), nil)
+	mustNoErr(t, repo.CreateGoal(goal), "CreateGoal")
+
+	mustNoErr(t, repo.CreateIncome(newIncome(userID, "Salary", 3000, date(2024, 1, 25))), "CreateIncome january")
+	february := newIncome(userID, "Salary", 3000, date(2024, 2, 26))
+	mustNoErr(t, repo.CreateIncome(february), "CreateIncome february")
+	mustNoErr(t, repo.CreateIncome(newIncome(userID, "Salary", 3000, date(2024, 3, 25))), "CreateIncome march")
+	rent := newExpense(userID, "housing", 1200, date(2024, 2, 1), nil)
+	groceries := newExpense(userID, "food", 150, date(2024, 2, 14), nil)
+	mustNoErr(t, repo.CreateExpense(groceries), "CreateExpense groceries")
+	mustNoErr(t, repo.CreateExpense(rent), "CreateExpense rent")
+	mustNoErr(t, repo.CreateExpense(newExpense(userID, "housing", 1200, date(2024, 3, 1), nil)), "CreateExpense march rent")
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, goal.ID, 400, date(2024, 2, 28))), "CreateGoalContribution")
+	mustNoErr(t, repo.CreateIncome(newIncome(uuid.New(), "Salary", 9999, date(2024, 2, 10))), "CreateIncome other user")
+
+	incomes, err := repo.ListIncomesBetween(userID, date(2024, 2, 1), date(2024, 3, 1))
+	mustNoErr(t, err, "ListIncomesBetween")
+	if len(incomes) != 1 || incomes[0].ID != february.ID {
+		t.Fatalf("ListIncomesBetween: expected only the february salary, got %d", len(incomes))
+	}
+	expenses, err := repo.ListExpensesBetween(userID, date(2024, 2, 1), date(2024, 3, 1))
+	mustNoErr(t, err, "ListExpensesBetween")
+	if len(expenses) != 2 || expenses[0].ID != rent.ID || expenses[1].ID != groceries.ID {
+		t.Fatalf("ListExpensesBetween: expected rent then groceries, got %d", len(expenses))
+	}
+
+	balance, err := repo.GetBalance(userID, date(2024, 3, 1))
+	mustNoErr(t, err, "GetBalance")
+	expectAmount(t, balance, 6000-1350-400, "balance before march")
+
+	mustNoErr(t, repo.DeleteExpense(groceries.ID, userID), "DeleteExpense")
+	balance, err = repo.GetBalance(userID, date(2024, 3, 1))
+	mustNoErr(t, err, "GetBalance after delete")
+	expectAmount(t, balance, 6000-1200-400, "balance ignoring trashed expenses")
+}
@@ -0,0 +1,85 @@
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func newCategory(userID uuid.UUID, name string, parentID *uuid.UUID) *models.Category {
+	return &models.Category{ID: uuid.New(), UserID: userID, ParentID: parentID, Name: name, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
+}
+
+func testCategoryHierarchy(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	food := newCategory(userID, "Food", nil)
+	mustNoErr(t, repo.CreateCategory(food), "CreateCategory food")
+	if err := repo.CreateCategory(newCategory(userID, "FOOD", nil)); !errors.Is(err, repository.ErrCategoryExists) {
+		t.Fatalf("CreateCategory duplicate: expected ErrCategoryExists, got %v", err)
+	}
+	groceries := newCategory(userID, "Groceries", &food.ID)
+	mustNoErr(t, repo.CreateCategory(groceries), "CreateCategory groceries")
+	// The same name is allowed under a different parent
+	mustNoErr(t, repo.CreateCategory(newCategory(userID, "Groceries", nil)), "CreateCategory top-level groceries")
+	dining := newCategory(userID, "Dining", nil)
+	mustNoErr(t, repo.CreateCategory(dining), "CreateCategory dining")
+	takeaway := newCategory(userID, "Takeaway", &dining.ID)
+	mustNoErr(t, repo.CreateCategory(takeaway), "CreateCategory takeaway")
+
+	got, err := repo.GetCategory(groceries.ID, userID)
+	mustNoErr(t, err, "GetCategory")
+	if got.ParentID == nil || *got.ParentID != food.ID {
+		t.Fatalf("GetCategory: expected parent %s, got %v", food.ID, got.ParentID)
+	}
+	_, err = repo.GetCategory(groceries.ID, uuid.New())
+	expectNotFound(t, err, "GetCategory other user")
+
+	e1 := newExpense(userID, "Groceries", 40, date(2024, 10, 2), nil)
+	e1.CategoryID = &groceries.ID
+	e2 := newExpense(userID, "Dining", 25, date(2024, 10, 5), nil)
+	e2.CategoryID = &dining.ID
+	mustNoErr(t, repo.CreateExpense(e1), "CreateExpense e1")
+	mustNoErr(t, repo.CreateExpense(e2), "CreateExpense e2")
+
+	summary, err := repo.GetMonthlySummary(userID, 2024, 10)
+	mustNoErr(t, err, "GetMonthlySummary")
+	expectAmount(t, summary.CategorySpending[groceries.ID], 40, "groceries spending")
+
+	// Renaming copies the new name to linked expenses and bumps their version
+	mustNoErr(t, repo.UpdateCategory(groceries.ID, userID, map[string]interface{}{"name": "Supermarket"}), "UpdateCategory rename")
+	renamed, err := repo.GetExpense(e1.ID, userID)
+	mustNoErr(t, err, "GetExpense renamed")
+	if renamed.Category != "Supermarket" || renamed.Version != e1.Version+1 {
+		t.Fatalf("UpdateCategory: expected the expense to be renamed, got %q v%d", renamed.Category, renamed.Version)
+	}
+
+	if err := repo.DeleteCategory(dining.ID, userID); !errors.Is(err, repository.ErrCategoryInUse) {
+		t.Fatalf("DeleteCategory in use: expected ErrCategoryInUse, got %v", err)
+	}
+
+	moved, err := repo.MergeCategories(userID, food.ID, []uuid.UUID{dining.ID})
+	mustNoErr(t, err, "MergeCategories")
+	if moved != 1 {
+		t.Fatalf("MergeCategories: expected 1 expense moved, got %d", moved)
+	}
+	merged, err := repo.GetExpense(e2.ID, userID)
+	mustNoErr(t, err, "GetExpense merged")
+	if merged.CategoryID == nil || *merged.CategoryID != food.ID || merged.Category != "Food" {
+		t.Fatalf("MergeCategories: expected the expense to move to food, got %+v", merged)
+	}
+	child, err := repo.GetCategory(takeaway.ID, userID)
+	mustNoErr(t, err, "GetCategory takeaway")
+	if child.ParentID == nil || *child.ParentID != food.ID {
+		t.Fatalf("MergeCategories: expected sub-categories to move to the target, got %v", child.ParentID)
+	}
+	_, err = repo.GetCategory(dining.ID, userID)
+	expectNotFound(t, err, "GetCategory merged source")
+
+	mustNoErr(t, repo.UpdateCategory(takeaway.ID, userID, map[string]interface{}{"parent_id": nil}), "UpdateCategory move to top level")
+	mustNoErr(t, repo.DeleteCategory(takeaway.ID, userID), "DeleteCategory unused")
+	expectNotFound(t, repo.DeleteCategory(takeaway.ID, userID), "DeleteCategory twice")
+}
@@ -0,0 +1,78 @@
+package repositorytest
+
+import (
+	"testing"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func newDebt(userID uuid.UUID, name string, principal, apr float64) *models.Debt {
+	return &models.Debt{ID: uuid.New(), UserID: userID, Name: name, Kind: models.DebtLoan, Principal: principal, APR: apr, MinimumPayment: 50, DueDay: 15, StartedOn: date(2024, 1, 1)}
+}
+
+func testDebts(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	car := newDebt(userID, "Car loan", 8000, 6.9)
+	card := newDebt(userID, "Credit card", 1200, 22.9)
+	card.Kind = models.DebtCreditCard
+	card.CreatedAt = date(2024, 1, 2)
+	car.CreatedAt = date(2024, 1, 1)
+	mustNoErr(t, repo.CreateDebt(car), "CreateDebt car")
+	mustNoErr(t, repo.CreateDebt(card), "CreateDebt card")
+	mustNoErr(t, repo.CreateDebt(newDebt(uuid.New(), "Mortgage", 200000, 4)), "CreateDebt other user")
+
+	debts, err := repo.ListDebts(userID)
+	mustNoErr(t, err, "ListDebts")
+	if len(debts) != 2 || debts[0].ID != car.ID {
+		t.Fatalf("ListDebts: expected the user's 2 debts oldest first, got %d", len(debts))
+	}
+	_, err = repo.GetDebt(car.ID, uuid.New())
+	expectNotFound(t, err, "GetDebt other user")
+
+	mustNoErr(t, repo.UpdateDebt(card.ID, userID, map[string]interface{}{"apr": 19.9, "minimum_payment": 35.0}), "UpdateDebt")
+	got, err := repo.GetDebt(card.ID, userID)
+	mustNoErr(t, err, "GetDebt")
+	if got.APR != 19.9 || got.MinimumPayment != 35 || got.Kind != models.DebtCreditCard || !got.StartedOn.Equal(date(2024, 1, 1)) {
+		t.Fatalf("GetDebt: unexpected debt after update %+v", got)
+	}
+	expectNotFound(t, repo.UpdateDebt(card.ID, uuid.New(), map[string]interface{}{"apr": 1.0}), "UpdateDebt other user")
+
+	first := newExpense(userID, "Debt repayment", 200, date(2024, 2, 15), nil)
+	first.DebtID = &car.ID
+	second := newExpense(userID, "Debt repayment", 50, date(2024, 2, 10), nil)
+	second.DebtID = &card.ID
+	deleted := newExpense(userID, "Debt repayment", 75, date(2024, 3, 10), nil)
+	deleted.DebtID = &card.ID
+	mustNoErr(t, repo.CreateExpense(first), "CreateExpense car payment")
+	mustNoErr(t, repo.CreateExpense(second), "CreateExpense card payment")
+	mustNoErr(t, repo.CreateExpense(deleted), "CreateExpense deleted payment")
+	mustNoErr(t, repo.CreateExpense(newExpense(userID, "Groceries", 40, date(2024, 2, 1), nil)), "CreateExpense groceries")
+	mustNoErr(t, repo.DeleteExpense(deleted.ID, userID), "DeleteExpense")
+
+	payments, err := repo.ListDebtPayments(userID)
+	mustNoErr(t, err, "ListDebtPayments")
+	if len(payments) != 2 || payments[0].ID != second.ID || payments[1].ID != first.ID {
+		t.Fatalf("ListDebtPayments: expected the 2 live payments by date, got %+v", payments)
+	}
+
+	// Deleting a debt keeps its payments as plain expenses, including those in the trash
+	mustNoErr(t, repo.DeleteDebt(card.ID, userID), "DeleteDebt")
+	_, err = repo.GetDebt(card.ID, userID)
+	expectNotFound(t, err, "GetDebt deleted")
+	expectNotFound(t, repo.DeleteDebt(card.ID, userID), "DeleteDebt twice")
+	expense, err := repo.GetExpense(second.ID, userID)
+	mustNoErr(t, err, "GetExpense unlinked payment")
+	if expense.DebtID != nil || expense.Version != second.Version+1 {
+		t.Fatalf("DeleteDebt: expected the payment to be unlinked with a new version, got %+v", expense)
+	}
+	mustNoErr(t, repo.RestoreExpense(deleted.ID, userID), "RestoreExpense")
+	if restored, _ := repo.GetExpense(deleted.ID, userID); restored == nil || restored.DebtID != nil {
+		t.Fatalf("DeleteDebt: expected the deleted payment to be unlinked too, got %+v", restored)
+	}
+	payments, _ = repo.ListDebtPayments(userID)
+	if len(payments) != 1 || payments[0].ID != first.ID {
+		t.Fatalf("ListDebtPayments after delete: expected only the car payment, got %d", len(payments))
+	}
+}
@@ -0,0 +1,675 @@
+// Package repositorytest provides contract test suites that every repository
+// implementation (GORM/Postgres, in-memory, ...) must pass.
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TestFinanceRepository runs the finance repository contract against implementations
+// created by newRepo. Each subtest uses a fresh repository and a random user ID,
+// so implementations backed by a shared database do not need to be emptied.
+func TestFinanceRepository(t *testing.T, newRepo func(t *testing.T) repository.FinanceRepositoryInterface) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID)
+	}{
+		{"Incomes", testIncomes},
+		{"Expenses", testExpenses},
+		{"Categories", testCategories},
+		{"CategoryHierarchy", testCategoryHierarchy},
+		{"MonthlySummary", testMonthlySummary},
+		{"GoalsWithProgress", testGoalsWithProgress},
+		{"Versions", testVersions},
+		{"DeleteGoalCascades", testDeleteGoalCascades},
+		{"TrashAndRestore", testTrashAndRestore},
+		{"RestoreGoal", testRestoreGoal},
+		{"MainGoalsWithSubgoals", testMainGoalsWithSubgoals},
+		{"GoalExpenses", testGoalExpenses},
+		{"GoalCategories", testGoalCategories},
+		{"GoalTemplates", testGoalTemplates},
+		{"GoalContributions", testGoalContributions},
+		{"Merchants", testMerchants},
+		{"Tags", testTags},
+		{"CashFlow", testCashFlow},
+		{"FundingRules", testFundingRules},
+		{"GoalLifecycle", testGoalLifecycle},
+		{"Debts", testDebts},
+		{"NetWorth", testNetWorth},
+		{"Investments", testInvestments},
+		{"Transaction", testTransaction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepo(t), uuid.New())
+		})
+	}
+}
+
+// date returns midnight UTC, matching how DATE columns are read back
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func mustNoErr(t *testing.T, err error, what string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", what, err)
+	}
+}
+
+func expectConflict(t *testing.T, err error, what string) {
+	t.Helper()
+	if !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("%s: expected repository.ErrVersionConflict, got %v", what, err)
+	}
+}
+
+func expectNotFound(t *testing.T, err error, what string) {
+	t.Helper()
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("%s: expected gorm.ErrRecordNotFound, got %v", what, err)
+	}
+}
+
+func expectAmount(t *testing.T, got, want float64, what string) {
+	t.Helper()
+	if diff := got - want; diff > 0.001 || diff < -0.001 {
+		t.Fatalf("%s: got %.2f, want %.2f", what, got, want)
+	}
+}
+
+func newIncome(userID uuid.UUID, source string, amount float64, receivedAt time.Time) *models.Income {
+	return &models.Income{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Source:     source,
+		Amount:     amount,
+		ReceivedAt: receivedAt,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+func newExpense(userID uuid.UUID, category string, amount float64, spentAt time.Time, goalID *uuid.UUID) *models.Expense {
+	return &models.Expense{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Category:    category,
+		Description: category + " expense",
+		Amount:      amount,
+		SpentAt:     spentAt,
+		GoalID:      goalID,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+func newGoal(userID uuid.UUID, name string, target float64, createdAt time.Time, parent *uuid.UUID) *models.Goal {
+	return &models.Goal{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Name:         name,
+		Category:     "General",
+		TargetAmount: target,
+		ParentGoalID: parent,
+		IsMainGoal:   parent == nil,
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	}
+}
+
+func newContribution(userID, goalID uuid.UUID, amount float64, at time.Time) *models.GoalContribution {
+	return &models.GoalContribution{
+		ID:            uuid.New(),
+		UserID:        userID,
+		GoalID:        goalID,
+		Amount:        amount,
+		ContributedAt: at,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+func testIncomes(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	older := newIncome(userID, "salary", 1000, date(2024, 1, 5))
+	newer := newIncome(userID, "bonus", 250.50, date(2024, 2, 5))
+	other := newIncome(uuid.New(), "salary", 999, date(2024, 3, 1))
+	for _, i := range []*models.Income{older, newer, other} {
+		mustNoErr(t, repo.CreateIncome(i), "CreateIncome")
+	}
+
+	items, err := repo.ListIncomes(userID, 0)
+	mustNoErr(t, err, "ListIncomes")
+	if len(items) != 2 {
+		t.Fatalf("ListIncomes: got %d items, want 2 (other users must be excluded)", len(items))
+	}
+	if items[0].ID != newer.ID || items[1].ID != older.ID {
+		t.Fatalf("ListIncomes: expected newest received_at first")
+	}
+
+	limited, err := repo.ListIncomes(userID, 1)
+	mustNoErr(t, err, "ListIncomes with limit")
+	if len(limited) != 1 || limited[0].ID != newer.ID {
+		t.Fatalf("ListIncomes with limit 1: got %d items", len(limited))
+	}
+
+	mustNoErr(t, repo.UpdateIncome(older.ID, userID, 0, map[string]interface{}{}), "UpdateIncome with no updates")
+	mustNoErr(t, repo.UpdateIncome(older.ID, userID, 0, map[string]interface{}{"amount": 1200.0, "source": "raise"}), "UpdateIncome")
+	items, _ = repo.ListIncomes(userID, 0)
+	updated := items[1]
+	expectAmount(t, updated.Amount, 1200, "updated income amount")
+	if updated.Source != "raise" {
+		t.Fatalf("updated income source: got %q", updated.Source)
+	}
+
+	expectNotFound(t, repo.UpdateIncome(uuid.New(), userID, 0, map[string]interface{}{"amount": 1.0}), "UpdateIncome unknown id")
+	expectNotFound(t, repo.UpdateIncome(other.ID, userID, 0, map[string]interface{}{"amount": 1.0}), "UpdateIncome other user")
+
+	got, err := repo.GetIncome(older.ID, userID)
+	mustNoErr(t, err, "GetIncome")
+	if got.Source != "raise" {
+		t.Fatalf("GetIncome: got source %q", got.Source)
+	}
+	_, err = repo.GetIncome(other.ID, userID)
+	expectNotFound(t, err, "GetIncome other user")
+
+	mustNoErr(t, repo.DeleteIncome(older.ID, userID), "DeleteIncome")
+	_, err = repo.GetIncome(older.ID, userID)
+	expectNotFound(t, err, "GetIncome after delete")
+	expectNotFound(t, repo.DeleteIncome(older.ID, userID), "DeleteIncome twice")
+	expectNotFound(t, repo.DeleteIncome(other.ID, userID), "DeleteIncome other user")
+	items, _ = repo.ListIncomes(userID, 0)
+	if len(items) != 1 || items[0].ID != newer.ID {
+		t.Fatalf("ListIncomes after delete: got %d items", len(items))
+	}
+}
+
+func testExpenses(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	goal := newGoal(userID, "Holiday", 500, time.Now().UTC(), nil)
+	mustNoErr(t, repo.CreateGoal(goal), "CreateGoal")
+
+	first := newExpense(userID, "food", 12.25, date(2024, 1, 10), nil)
+	second := newExpense(userID, "travel", 80, date(2024, 1, 20), &goal.ID)
+	for _, e := range []*models.Expense{first, second} {
+		mustNoErr(t, repo.CreateExpense(e), "CreateExpense")
+	}
+
+	items, err := repo.ListExpenses(userID, 0)
+	mustNoErr(t, err, "ListExpenses")
+	if len(items) != 2 || items[0].ID != second.ID {
+		t.Fatalf("ListExpenses: expected 2 items with newest spent_at first")
+	}
+	if items[0].GoalID == nil || *items[0].GoalID != goal.ID {
+		t.Fatalf("ListExpenses: goal_id not persisted")
+	}
+
+	// The service clears goal_id with a nil *uuid.UUID
+	var cleared *uuid.UUID
+	mustNoErr(t, repo.UpdateExpense(second.ID, userID, 0, map[string]interface{}{"goal_id": cleared, "category": "flights"}), "UpdateExpense")
+	items, _ = repo.ListExpenses(userID, 0)
+	if items[0].GoalID != nil {
+		t.Fatalf("UpdateExpense: goal_id should be cleared")
+	}
+	if items[0].Category != "flights" {
+		t.Fatalf("UpdateExpense: category got %q", items[0].Category)
+	}
+
+	got, err := repo.GetExpense(second.ID, userID)
+	mustNoErr(t, err, "GetExpense")
+	if got.Category != "flights" || got.GoalID != nil {
+		t.Fatalf("GetExpense: stored expense does not match")
+	}
+	_, err = repo.GetExpense(uuid.New(), userID)
+	expectNotFound(t, err, "GetExpense unknown id")
+
+	expectNotFound(t, repo.UpdateExpense(uuid.New(), userID, 0, map[string]interface{}{"amount": 1.0}), "UpdateExpense unknown id")
+	mustNoErr(t, repo.DeleteExpense(first.ID, userID), "DeleteExpense")
+	expectNotFound(t, repo.DeleteExpense(first.ID, userID), "DeleteExpense twice")
+}
+
+func testCategories(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	for _, name := range []string{"Utilities", "Food", "Rent"} {
+		mustNoErr(t, repo.CreateCategory(&models.Category{ID: uuid.New(), UserID: userID, Name: name, CreatedAt: time.Now().UTC()}), "CreateCategory")
+	}
+	mustNoErr(t, repo.CreateCategory(&models.Category{ID: uuid.New(), UserID: uuid.New(), Name: "Other", CreatedAt: time.Now().UTC()}), "CreateCategory other user")
+
+	cats, err := repo.ListCategories(userID)
+	mustNoErr(t, err, "ListCategories")
+	if len(cats) != 3 {
+		t.Fatalf("ListCategories: got %d, want 3", len(cats))
+	}
+	if cats[0].Name != "Food" || cats[1].Name != "Rent" || cats[2].Name != "Utilities" {
+		t.Fatalf("ListCategories: expected name order, got %s, %s, %s", cats[0].Name, cats[1].Name, cats[2].Name)
+	}
+}
+
+func testMonthlySummary(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	goal := newGoal(userID, "Car", 10000, time.Now().UTC(), nil)
+	mustNoErr(t, repo.CreateGoal(goal), "CreateGoal")
+
+	// Month boundaries are [first day, first day of next month)
+	mustNoErr(t, repo.CreateIncome(newIncome(userID, "salary", 3000, date(2024, 3, 1))), "CreateIncome")
+	mustNoErr(t, repo.CreateIncome(newIncome(userID, "side", 200, date(2024, 3, 31))), "CreateIncome")
+	mustNoErr(t, repo.CreateIncome(newIncome(userID, "salary", 3000, date(2024, 4, 1))), "CreateIncome next month")
+	mustNoErr(t, repo.CreateIncome(newIncome(uuid.New(), "salary", 5000, date(2024, 3, 15))), "CreateIncome other user")
+
+	mustNoErr(t, repo.CreateExpense(newExpense(userID, "food", 100, date(2024, 3, 2), nil)), "CreateExpense")
+	mustNoErr(t, repo.CreateExpense(newExpense(userID, "food", 50.50, date(2024, 3, 20), nil)), "CreateExpense")
+	mustNoErr(t, repo.CreateExpense(newExpense(userID, "car", 400, date(2024, 3, 25), &goal.ID)), "CreateExpense")
+	mustNoErr(t, repo.CreateExpense(newExpense(userID, "food", 999, date(2024, 2, 29), nil)), "CreateExpense previous month")
+
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, goal.ID, 300, date(2024, 3, 5))), "CreateGoalContribution")
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, goal.ID, 200, date(2024, 3, 28))), "CreateGoalContribution")
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, goal.ID, 700, date(2024, 4, 2))), "CreateGoalContribution next month")
+
+	summary, err := repo.GetMonthlySummary(userID, 2024, 3)
+	mustNoErr(t, err, "GetMonthlySummary")
+	if summary.Year != 2024 || summary.Month != 3 {
+		t.Fatalf("GetMonthlySummary: period got %d-%d", summary.Year, summary.Month)
+	}
+	expectAmount(t, summary.TotalIncome, 3200, "total income")
+	expectAmount(t, summary.TotalExpenses, 550.50, "total expenses")
+	expectAmount(t, summary.TotalSavings, 500, "total savings (sum of contributions)")
+	if len(summary.CategoryBreakdown) != 2 {
+		t.Fatalf("category breakdown: got %d categories, want 2", len(summary.CategoryBreakdown))
+	}
+	expectAmount(t, summary.CategoryBreakdown["food"], 150.50, "food category")
+	expectAmount(t, summary.CategoryBreakdown["car"], 400, "car category")
+	if len(summary.GoalSpending) != 1 {
+		t.Fatalf("goal spending: got %d goals, want 1", len(summary.GoalSpending))
+	}
+	expectAmount(t, summary.GoalSpending[goal.ID], 400, "goal spending")
+	expectAmount(t, summary.GoalContributions[goal.ID], 500, "goal contributions")
+
+	empty, err := repo.GetMonthlySummary(userID, 2023, 1)
+	mustNoErr(t, err, "GetMonthlySummary empty month")
+	if empty.TotalIncome != 0 || empty.TotalExpenses != 0 || empty.TotalSavings != 0 {
+		t.Fatalf("empty month should have zero totals")
+	}
+	if empty.CategoryBreakdown == nil || empty.GoalSpending == nil || empty.GoalContributions == nil {
+		t.Fatalf("empty month should return initialised maps")
+	}
+}
+
+func testVersions(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	income := newIncome(userID, "salary", 1000, date(2024, 3, 1))
+	mustNoErr(t, repo.CreateIncome(income), "CreateIncome")
+	got, err := repo.GetIncome(income.ID, userID)
+	mustNoErr(t, err, "GetIncome")
+	if got.Version != 1 {
+		t.Fatalf("new income: got version %d, want 1", got.Version)
+	}
+
+	mustNoErr(t, repo.UpdateIncome(income.ID, userID, 1, map[string]interface{}{"amount": 1100.0}), "UpdateIncome at current version")
+	expectConflict(t, repo.UpdateIncome(income.ID, userID, 1, map[string]interface{}{"amount": 1200.0}), "UpdateIncome at stale version")
+	got, _ = repo.GetIncome(income.ID, userID)
+	if got.Version != 2 {
+		t.Fatalf("updated income: got version %d, want 2", got.Version)
+	}
+	expectAmount(t, got.Amount, 1100, "income after rejected update")
+	if got.UpdatedAt.Before(got.CreatedAt) {
+		t.Fatalf("updated income: updated_at must not precede created_at")
+	}
+	expectNotFound(t, repo.UpdateIncome(uuid.New(), userID, 1, map[string]interface{}{"amount": 1.0}), "UpdateIncome unknown id with version")
+
+	expense := newExpense(userID, "food", 20, date(2024, 3, 2), nil)
+	mustNoErr(t, repo.CreateExpense(expense), "CreateExpense")
+	mustNoErr(t, repo.UpdateExpense(expense.ID, userID, 1, map[string]interface{}{"category": "groceries"}), "UpdateExpense at current version")
+	expectConflict(t, repo.UpdateExpense(expense.ID, userID, 1, map[string]interface{}{"category": "dining"}), "UpdateExpense at stale version")
+	// Version 0 skips the check but still bumps the version
+	mustNoErr(t, repo.UpdateExpense(expense.ID, userID, 0, map[string]interface{}{"category": "dining"}), "UpdateExpense unchecked")
+	gotExpense, err := repo.GetExpense(expense.ID, userID)
+	mustNoErr(t, err, "GetExpense")
+	if gotExpense.Version != 3 || gotExpense.Category != "dining" {
+		t.Fatalf("expense after updates: got version %d category %q", gotExpense.Version, gotExpense.Category)
+	}
+
+	goal := newGoal(userID, "Car", 5000, time.Now().UTC(), nil)
+	mustNoErr(t, repo.CreateGoal(goal), "CreateGoal")
+	expectConflict(t, repo.UpdateGoal(goal.ID, userID, 7, map[string]interface{}{"name": "Van"}), "UpdateGoal at wrong version")
+	mustNoErr(t, repo.UpdateGoal(goal.ID, userID, 1, map[string]interface{}{"name": "Van"}), "UpdateGoal at current version")
+	gotGoal, err := repo.GetGoal(goal.ID, userID)
+	mustNoErr(t, err, "GetGoal")
+	if gotGoal.Version != 2 || gotGoal.Name != "Van" {
+		t.Fatalf("goal after update: got version %d name %q", gotGoal.Version, gotGoal.Name)
+	}
+}
+
+func testGoalsWithProgress(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	base := time.Now().UTC().Add(-time.Hour)
+	older := newGoal(userID, "Laptop", 2000, base, nil)
+	newer := newGoal(userID, "Bike", 800, base.Add(time.Minute), nil)
+	idle := newGoal(userID, "Idle", 100, base.Add(-time.Minute), nil)
+	for _, g := range []*models.Goal{older, newer, idle} {
+		mustNoErr(t, repo.CreateGoal(g), "CreateGoal")
+	}
+	mustNoErr(t, repo.CreateGoal(newGoal(uuid.New(), "Not mine", 1, base, nil)), "CreateGoal other user")
+
+	// Contributions across months all count towards progress
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, older.ID, 500, date(2023, 12, 1))), "CreateGoalContribution")
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, older.ID, 250, date(2024, 1, 1))), "CreateGoalContribution")
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, newer.ID, 100, date(2024, 1, 1))), "CreateGoalContribution")
+	mustNoErr(t, repo.CreateExpense(newExpense(userID, "tech", 60, date(2024, 1, 3), &older.ID)), "CreateExpense")
+	mustNoErr(t, repo.CreateExpense(newExpense(userID, "tech", 40, date(2024, 2, 3), &older.ID)), "CreateExpense")
+	mustNoErr(t, repo.CreateExpense(newExpense(userID, "misc", 75, date(2024, 2, 3), nil)), "CreateExpense without goal")
+
+	goals, err := repo.ListGoalsWithProgress(userID)
+	mustNoErr(t, err, "ListGoalsWithProgress")
+	if len(goals) != 3 {
+		t.Fatalf("ListGoalsWithProgress: got %d goals, want 3", len(goals))
+	}
+	if goals[0].Goal.ID != newer.ID || goals[1].Goal.ID != older.ID || goals[2].Goal.ID != idle.ID {
+		t.Fatalf("ListGoalsWithProgress: expected newest created_at first")
+	}
+	expectAmount(t, goals[0].ContributedSum, 100, "newer contributed")
+	expectAmount(t, goals[0].ExpenseSum, 0, "newer expenses")
+	expectAmount(t, goals[1].ContributedSum, 750, "older contributed")
+	expectAmount(t, goals[1].ExpenseSum, 100, "older expenses")
+	expectAmount(t, goals[2].ContributedSum, 0, "idle contributed")
+	expectAmount(t, goals[2].ExpenseSum, 0, "idle expenses")
+	if goals[0].Goal.AllocationPriority != 1 || goals[0].Goal.AllocationWeight != 1 {
+		t.Fatalf("ListGoalsWithProgress: expected allocation priority and weight to default to 1, got %d and %v",
+			goals[0].Goal.AllocationPriority, goals[0].Goal.AllocationWeight)
+	}
+}
+
+func testDeleteGoalCascades(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	now := time.Now().UTC()
+	parent := newGoal(userID, "Wedding", 20000, now, nil)
+	mustNoErr(t, repo.CreateGoal(parent), "CreateGoal parent")
+	child := newGoal(userID, "Venue", 8000, now.Add(time.Second), &parent.ID)
+	mustNoErr(t, repo.CreateGoal(child), "CreateGoal child")
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, child.ID, 100, date(2024, 5, 1))), "CreateGoalContribution")
+	linked := newExpense(userID, "deposit", 500, date(2024, 5, 2), &parent.ID)
+	mustNoErr(t, repo.CreateExpense(linked), "CreateExpense")
+
+	expectNotFound(t, repo.DeleteGoal(parent.ID, uuid.New()), "DeleteGoal other user")
+	mustNoErr(t, repo.UpdateGoal(parent.ID, userID, 0, map[string]interface{}{"name": "Big day"}), "UpdateGoal")
+	got, err := repo.GetGoal(parent.ID, userID)
+	mustNoErr(t, err, "GetGoal")
+	if got.Name != "Big day" {
+		t.Fatalf("GetGoal: got name %q", got.Name)
+	}
+	mustNoErr(t, repo.DeleteGoal(parent.ID, userID), "DeleteGoal")
+	expectNotFound(t, repo.DeleteGoal(parent.ID, userID), "DeleteGoal twice")
+	expectNotFound(t, repo.UpdateGoal(child.ID, userID, 0, map[string]interface{}{"name": "x"}), "UpdateGoal on cascaded sub-goal")
+	_, err = repo.GetGoal(child.ID, userID)
+	expectNotFound(t, err, "GetGoal on cascaded sub-goal")
+
+	goals, err := repo.ListGoalsWithProgress(userID)
+	mustNoErr(t, err, "ListGoalsWithProgress")
+	if len(goals) != 0 {
+		t.Fatalf("deleting a goal should cascade to its sub-goals, %d goals left", len(goals))
+	}
+	summary, err := repo.GetMonthlySummary(userID, 2024, 5)
+	mustNoErr(t, err, "GetMonthlySummary")
+	if len(summary.GoalContributions) != 0 {
+		t.Fatalf("deleting a goal should cascade to its contributions")
+	}
+	trash, err := repo.ListTrash(userID)
+	mustNoErr(t, err, "ListTrash")
+	if len(trash.Goals) != 2 {
+		t.Fatalf("ListTrash: got %d goals, want the goal and its sub-goal", len(trash.Goals))
+	}
+	expenses, err := repo.ListExpenses(userID, 0)
+	mustNoErr(t, err, "ListExpenses")
+	if len(expenses) != 1 || expenses[0].GoalID == nil {
+		t.Fatalf("trashing a goal should keep linked expenses and their goal_id")
+	}
+
+	// Purging enforces the foreign keys: sub-goals and contributions go, goal_id is cleared
+	_, err = repo.PurgeDeleted(time.Now().Add(time.Minute))
+	mustNoErr(t, err, "PurgeDeleted")
+	trash, _ = repo.ListTrash(userID)
+	if len(trash.Goals) != 0 {
+		t.Fatalf("PurgeDeleted: %d goals left in the trash", len(trash.Goals))
+	}
+	expectNotFound(t, repo.RestoreGoal(parent.ID, userID), "RestoreGoal after purge")
+	expenses, _ = repo.ListExpenses(userID, 0)
+	if len(expenses) != 1 || expenses[0].GoalID != nil {
+		t.Fatalf("purging a goal should keep linked expenses and clear goal_id")
+	}
+}
+
+func testTrashAndRestore(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	income := newIncome(userID, "salary", 3000, date(2024, 7, 1))
+	expense := newExpense(userID, "food", 45, date(2024, 7, 2), nil)
+	mustNoErr(t, repo.CreateIncome(income), "CreateIncome")
+	mustNoErr(t, repo.CreateExpense(expense), "CreateExpense")
+
+	expectNotFound(t, repo.RestoreIncome(income.ID, userID), "RestoreIncome not in trash")
+	mustNoErr(t, repo.DeleteIncome(income.ID, userID), "DeleteIncome")
+	mustNoErr(t, repo.DeleteExpense(expense.ID, userID), "DeleteExpense")
+
+	// Trashed rows are excluded from lists and aggregates
+	incomes, _ := repo.ListIncomes(userID, 0)
+	expenses, _ := repo.ListExpenses(userID, 0)
+	if len(incomes) != 0 || len(expenses) != 0 {
+		t.Fatalf("trashed rows must not be listed")
+	}
+	summary, err := repo.GetMonthlySummary(userID, 2024, 7)
+	mustNoErr(t, err, "GetMonthlySummary")
+	if summary.TotalIncome != 0 || summary.TotalExpenses != 0 || len(summary.CategoryBreakdown) != 0 {
+		t.Fatalf("trashed rows must not be aggregated, got %+v", summary)
+	}
+	expectNotFound(t, repo.UpdateIncome(income.ID, userID, 0, map[string]interface{}{"amount": 1.0}), "UpdateIncome in trash")
+
+	trash, err := repo.ListTrash(userID)
+	mustNoErr(t, err, "ListTrash")
+	if len(trash.Incomes) != 1 || len(trash.Expenses) != 1 || len(trash.Goals) != 0 {
+		t.Fatalf("ListTrash: got %d incomes, %d expenses, %d goals", len(trash.Incomes), len(trash.Expenses), len(trash.Goals))
+	}
+	if !trash.Incomes[0].DeletedAt.Valid {
+		t.Fatalf("ListTrash: deleted_at not set")
+	}
+	other, err := repo.ListTrash(uuid.New())
+	mustNoErr(t, err, "ListTrash other user")
+	if len(other.Incomes)+len(other.Expenses) != 0 {
+		t.Fatalf("ListTrash must be scoped to the user")
+	}
+
+	expectNotFound(t, repo.RestoreIncome(income.ID, uuid.New()), "RestoreIncome other user")
+	mustNoErr(t, repo.RestoreIncome(income.ID, userID), "RestoreIncome")
+	mustNoErr(t, repo.RestoreExpense(expense.ID, userID), "RestoreExpense")
+	summary, _ = repo.GetMonthlySummary(userID, 2024, 7)
+	expectAmount(t, summary.TotalIncome, 3000, "restored income")
+	expectAmount(t, summary.TotalExpenses, 45, "restored expense")
+
+	// Only rows trashed before the cutoff are purged
+	mustNoErr(t, repo.DeleteExpense(expense.ID, userID), "DeleteExpense again")
+	_, err = repo.PurgeDeleted(time.Now().Add(-time.Hour))
+	mustNoErr(t, err, "PurgeDeleted before cutoff")
+	mustNoErr(t, repo.RestoreExpense(expense.ID, userID), "RestoreExpense within retention")
+	mustNoErr(t, repo.DeleteExpense(expense.ID, userID), "DeleteExpense to purge")
+	purged, err := repo.PurgeDeleted(time.Now().Add(time.Minute))
+	mustNoErr(t, err, "PurgeDeleted")
+	if purged < 1 {
+		t.Fatalf("PurgeDeleted: expected at least one row removed")
+	}
+	expectNotFound(t, repo.RestoreExpense(expense.ID, userID), "RestoreExpense after purge")
+}
+
+func testRestoreGoal(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	now := time.Now().UTC()
+	parent := newGoal(userID, "Trip", 3000, now, nil)
+	mustNoErr(t, repo.CreateGoal(parent), "CreateGoal parent")
+	flights := newGoal(userID, "Flights", 1000, now.Add(time.Second), &parent.ID)
+	hotel := newGoal(userID, "Hotel", 1500, now.Add(2*time.Second), &parent.ID)
+	for _, g := range []*models.Goal{flights, hotel} {
+		mustNoErr(t, repo.CreateGoal(g), "CreateGoal child")
+	}
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, flights.ID, 200, date(2024, 8, 1))), "CreateGoalContribution")
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, hotel.ID, 300, date(2024, 8, 1))), "CreateGoalContribution")
+
+	// The hotel was trashed on its own before the parent, so restoring the parent leaves it in the trash
+	mustNoErr(t, repo.DeleteGoal(hotel.ID, userID), "DeleteGoal child")
+	time.Sleep(2 * time.Millisecond)
+	mustNoErr(t, repo.DeleteGoal(parent.ID, userID), "DeleteGoal parent")
+
+	if err := repo.RestoreGoal(flights.ID, userID); !errors.Is(err, repository.ErrParentGoalDeleted) {
+		t.Fatalf("RestoreGoal child of trashed parent: expected ErrParentGoalDeleted, got %v", err)
+	}
+	expectNotFound(t, repo.RestoreGoal(parent.ID, uuid.New()), "RestoreGoal other user")
+	mustNoErr(t, repo.RestoreGoal(parent.ID, userID), "RestoreGoal")
+	expectNotFound(t, repo.RestoreGoal(parent.ID, userID), "RestoreGoal twice")
+
+	goals, err := repo.ListGoalsWithProgress(userID)
+	mustNoErr(t, err, "ListGoalsWithProgress")
+	if len(goals) != 2 {
+		t.Fatalf("RestoreGoal: got %d goals, want the parent and the sub-goal deleted with it", len(goals))
+	}
+	summary, _ := repo.GetMonthlySummary(userID, 2024, 8)
+	expectAmount(t, summary.GoalContributions[flights.ID], 200, "restored contribution")
+	expectAmount(t, summary.GoalContributions[hotel.ID], 0, "separately trashed contribution")
+
+	mustNoErr(t, repo.RestoreGoal(hotel.ID, userID), "RestoreGoal separately trashed child")
+	summary, _ = repo.GetMonthlySummary(userID, 2024, 8)
+	expectAmount(t, summary.TotalSavings, 500, "all contributions restored")
+}
+
+func testMainGoalsWithSubgoals(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	base := time.Now().UTC().Add(-time.Hour)
+	main := newGoal(userID, "House", 50000, base, nil)
+	mustNoErr(t, repo.CreateGoal(main), "CreateGoal")
+	late := newGoal(userID, "Furniture", 5000, base.Add(2*time.Minute), &main.ID)
+	early := newGoal(userID, "Deposit", 40000, base.Add(time.Minute), &main.ID)
+	for _, g := range []*models.Goal{late, early} {
+		mustNoErr(t, repo.CreateGoal(g), "CreateGoal sub-goal")
+	}
+
+	result, err := repo.ListMainGoalsWithSubgoals(userID)
+	mustNoErr(t, err, "ListMainGoalsWithSubgoals")
+	if len(result) != 1 || result[0].Goal.ID != main.ID {
+		t.Fatalf("ListMainGoalsWithSubgoals: expected only the main goal at the top level")
+	}
+	subs := result[0].Subgoals
+	if len(subs) != 2 || subs[0].ID != early.ID || subs[1].ID != late.ID {
+		t.Fatalf("ListMainGoalsWithSubgoals: expected sub-goals oldest first")
+	}
+}
+
+func testGoalExpenses(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	goal := newGoal(userID, "Garden", 1000, time.Now().UTC(), nil)
+	mustNoErr(t, repo.CreateGoal(goal), "CreateGoal")
+	expense := newExpense(userID, "garden", 120, date(2024, 6, 1), nil)
+	mustNoErr(t, repo.CreateExpense(expense), "CreateExpense")
+
+	ge := &models.GoalExpense{
+		ID:          uuid.New(),
+		UserID:      userID,
+		GoalID:      goal.ID,
+		ExpenseID:   expense.ID,
+		Amount:      120,
+		Description: "plants",
+		CreatedAt:   time.Now().UTC(),
+	}
+	mustNoErr(t, repo.CreateGoalExpense(ge), "CreateGoalExpense")
+
+	items, err := repo.ListGoalExpenses(userID, goal.ID)
+	mustNoErr(t, err, "ListGoalExpenses")
+	if len(items) != 1 || items[0].ExpenseID != expense.ID {
+		t.Fatalf("ListGoalExpenses: got %d items", len(items))
+	}
+	others, err := repo.ListGoalExpenses(uuid.New(), goal.ID)
+	mustNoErr(t, err, "ListGoalExpenses other user")
+	if len(others) != 0 {
+		t.Fatalf("ListGoalExpenses must be scoped to the user")
+	}
+
+	mustNoErr(t, repo.DeleteExpense(expense.ID, userID), "DeleteExpense")
+	items, _ = repo.ListGoalExpenses(userID, goal.ID)
+	if len(items) != 0 {
+		t.Fatalf("trashing an expense should hide its goal expenses")
+	}
+	mustNoErr(t, repo.RestoreExpense(expense.ID, userID), "RestoreExpense")
+	items, _ = repo.ListGoalExpenses(userID, goal.ID)
+	if len(items) != 1 {
+		t.Fatalf("restoring an expense should bring back its goal expenses")
+	}
+}
+
+func testGoalContributions(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	goal := newGoal(userID, "Bike", 800, time.Now().UTC(), nil)
+	mustNoErr(t, repo.CreateGoal(goal), "CreateGoal")
+	contrib := newContribution(userID, goal.ID, 50, date(2024, 6, 1))
+	mustNoErr(t, repo.CreateGoalContribution(contrib), "CreateGoalContribution")
+
+	got, err := repo.GetGoalContribution(contrib.ID, userID)
+	mustNoErr(t, err, "GetGoalContribution")
+	expectAmount(t, got.Amount, 50, "stored contribution amount")
+	_, err = repo.GetGoalContribution(contrib.ID, uuid.New())
+	expectNotFound(t, err, "GetGoalContribution other user")
+
+	mustNoErr(t, repo.UpdateGoalContribution(contrib.ID, userID, map[string]interface{}{"amount": 75.0}), "UpdateGoalContribution")
+	got, _ = repo.GetGoalContribution(contrib.ID, userID)
+	expectAmount(t, got.Amount, 75, "updated contribution amount")
+	expectNotFound(t, repo.UpdateGoalContribution(uuid.New(), userID, map[string]interface{}{"amount": 1.0}), "UpdateGoalContribution unknown id")
+
+	mustNoErr(t, repo.DeleteGoalContribution(contrib.ID, userID), "DeleteGoalContribution")
+	expectNotFound(t, repo.DeleteGoalContribution(contrib.ID, userID), "DeleteGoalContribution twice")
+	_, err = repo.GetGoalContribution(contrib.ID, userID)
+	expectNotFound(t, err, "GetGoalContribution after delete")
+	goals, err := repo.ListGoalsWithProgress(userID)
+	mustNoErr(t, err, "ListGoalsWithProgress")
+	if len(goals) != 1 || goals[0].ContributedSum != 0 {
+		t.Fatalf("deleted contributions must not count towards progress")
+	}
+
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, goal.ID, 30, date(2024, 6, 15))), "CreateGoalContribution june")
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, goal.ID, 20, date(2024, 7, 1))), "CreateGoalContribution july")
+	sums, err := repo.SumGoalContributions(userID, date(2024, 6, 1), date(2024, 7, 1))
+	mustNoErr(t, err, "SumGoalContributions")
+	expectAmount(t, sums[goal.ID], 30, "contributions in june")
+	sums, _ = repo.SumGoalContributions(uuid.New(), date(2024, 1, 1), date(2025, 1, 1))
+	if len(sums) != 0 {
+		t.Fatalf("SumGoalContributions: expected nothing for another user, got %v", sums)
+	}
+}
+
+func testTransaction(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	committed := newIncome(userID, "committed", 10, date(2024, 7, 1))
+	mustNoErr(t, repo.Transaction(func(tx repository.FinanceRepositoryInterface) error {
+		return tx.CreateIncome(committed)
+	}), "Transaction commit")
+	_, err := repo.GetIncome(committed.ID, userID)
+	mustNoErr(t, err, "GetIncome after commit")
+
+	rolledBack := newIncome(userID, "rolled back", 20, date(2024, 7, 2))
+	failure := errors.New("abort")
+	err = repo.Transaction(func(tx repository.FinanceRepositoryInterface) error {
+		mustNoErr(t, tx.CreateIncome(rolledBack), "CreateIncome in transaction")
+		mustNoErr(t, tx.UpdateIncome(committed.ID, userID, 0, map[string]interface{}{"amount": 99.0}), "UpdateIncome in transaction")
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Transaction: expected fn's error, got %v", err)
+	}
+	_, err = repo.GetIncome(rolledBack.ID, userID)
+	expectNotFound(t, err, "GetIncome after rollback")
+	got, _ := repo.GetIncome(committed.ID, userID)
+	expectAmount(t, got.Amount, 10, "income after rolled back update")
+
+	// A failed nested transaction only undoes its own writes
+	outer := newIncome(userID, "outer", 30, date(2024, 7, 3))
+	inner := newIncome(userID, "inner", 40, date(2024, 7, 4))
+	mustNoErr(t, repo.Transaction(func(tx repository.FinanceRepositoryInterface) error {
+		if err := tx.CreateIncome(outer); err != nil {
+			return err
+		}
+		_ = tx.Transaction(func(nested repository.FinanceRepositoryInterface) error {
+			mustNoErr(t, nested.CreateIncome(inner), "CreateIncome in nested transaction")
+			return failure
+		})
+		return nil
+	}), "Transaction with failed nested transaction")
+	_, err = repo.GetIncome(outer.ID, userID)
+	mustNoErr(t, err, "GetIncome written by the outer transaction")
+	_, err = repo.GetIncome(inner.ID, userID)
+	expectNotFound(t, err, "GetIncome written by the rolled back nested transaction")
+}
@@ -0,0 +1,85 @@
+package repositorytest
+
+import (
+	"testing"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func testFundingRules(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	goal := newGoal(userID, "Emergency fund", 5000, date(2024, 1, 1), nil)
+	mustNoErr(t, repo.CreateGoal(goal), "CreateGoal")
+
+	percent, source := 10.0, "Salary"
+	salary := &models.GoalFundingRule{ID: uuid.New(), UserID: userID, GoalID: goal.ID, Name: "Tithe", Type: models.FundingPercentOfIncome, Enabled: true, Percent: &percent, IncomeSource: &source, CreatedAt: date(2024, 1, 1), UpdatedAt: date(2024, 1, 1)}
+	amount, day := 200.0, 1
+	monthly := &models.GoalFundingRule{ID: uuid.New(), UserID: userID, GoalID: goal.ID, Name: "Standing order", Type: models.FundingFixedMonthly, Enabled: true, Amount: &amount, DayOfMonth: &day, CreatedAt: date(2024, 1, 2), UpdatedAt: date(2024, 1, 2)}
+	mustNoErr(t, repo.CreateFundingRule(salary), "CreateFundingRule percent")
+	mustNoErr(t, repo.CreateFundingRule(monthly), "CreateFundingRule monthly")
+
+	got, err := repo.GetFundingRule(salary.ID, userID)
+	mustNoErr(t, err, "GetFundingRule")
+	if got.Percent == nil || *got.Percent != 10 || got.IncomeSource == nil || *got.IncomeSource != "Salary" {
+		t.Fatalf("GetFundingRule: unexpected rule %+v", got)
+	}
+	_, err = repo.GetFundingRule(salary.ID, uuid.New())
+	expectNotFound(t, err, "GetFundingRule other user")
+
+	rules, err := repo.ListFundingRules(userID)
+	mustNoErr(t, err, "ListFundingRules")
+	if len(rules) != 2 || rules[0].ID != salary.ID {
+		t.Fatalf("ListFundingRules: expected both rules oldest first, got %d", len(rules))
+	}
+
+	mustNoErr(t, repo.UpdateFundingRule(monthly.ID, userID, map[string]interface{}{"last_funded_on": date(2024, 3, 1)}), "UpdateFundingRule")
+	scheduled, err := repo.ListScheduledFundingRules()
+	mustNoErr(t, err, "ListScheduledFundingRules")
+	var found bool
+	for _, rule := range scheduled {
+		if rule.ID == salary.ID {
+			t.Fatal("ListScheduledFundingRules: percent rules are not scheduled")
+		}
+		if rule.ID == monthly.ID {
+			found = true
+			if rule.LastFundedOn == nil || !rule.LastFundedOn.Equal(date(2024, 3, 1)) {
+				t.Fatalf("ListScheduledFundingRules: expected the updated last funded date, got %v", rule.LastFundedOn)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("ListScheduledFundingRules: expected the monthly rule")
+	}
+	// Only the run that saw the current last funded date moves it on
+	claimed, err := repo.ClaimScheduledFunding(monthly.ID, userID, nil, date(2024, 4, 1))
+	mustNoErr(t, err, "ClaimScheduledFunding stale")
+	if claimed {
+		t.Fatal("ClaimScheduledFunding: expected a stale last funded date not to claim the rule")
+	}
+	march := date(2024, 3, 1)
+	claimed, err = repo.ClaimScheduledFunding(monthly.ID, userID, &march, date(2024, 4, 1))
+	mustNoErr(t, err, "ClaimScheduledFunding")
+	if got, _ := repo.GetFundingRule(monthly.ID, userID); !claimed || got.LastFundedOn == nil || !got.LastFundedOn.Equal(date(2024, 4, 1)) {
+		t.Fatalf("ClaimScheduledFunding: expected the rule moved on, got %v", got)
+	}
+	mustNoErr(t, repo.UpdateFundingRule(monthly.ID, userID, map[string]interface{}{"enabled": false}), "UpdateFundingRule disable")
+	scheduled, _ = repo.ListScheduledFundingRules()
+	for _, rule := range scheduled {
+		if rule.ID == monthly.ID {
+			t.Fatal("ListScheduledFundingRules: disabled rules must not be scheduled")
+		}
+	}
+
+	contrib := newContribution(userID, goal.ID, 300, date(2024, 2, 25))
+	contrib.FundingRuleID = &salary.ID
+	mustNoErr(t, repo.CreateGoalContribution(contrib), "CreateGoalContribution generated")
+	mustNoErr(t, repo.DeleteFundingRule(salary.ID, userID), "DeleteFundingRule")
+	expectNotFound(t, repo.DeleteFundingRule(salary.ID, userID), "DeleteFundingRule twice")
+	kept, err := repo.GetGoalContribution(contrib.ID, userID)
+	mustNoErr(t, err, "GetGoalContribution after rule delete")
+	if kept.FundingRuleID != nil {
+		t.Fatalf("expected the contribution to be unlinked from the deleted rule, got %v", kept.FundingRuleID)
+	}
+}
@@ -0,0 +1,78 @@
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func testGoalCategories(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	categories, err := repo.ListGoalCategories(userID)
+	mustNoErr(t, err, "ListGoalCategories")
+	var general *models.GoalCategory
+	for i, c := range categories {
+		if i > 0 && categories[i-1].Name > c.Name {
+			t.Fatalf("ListGoalCategories: expected name order")
+		}
+		if c.Name == "General" {
+			general = &categories[i]
+		}
+	}
+	if general == nil || general.UserID != nil || general.Icon == "" || general.Color == "" {
+		t.Fatalf("ListGoalCategories: expected the seeded General category with its icon and colour, got %+v", general)
+	}
+
+	owner := userID
+	now := time.Now().UTC()
+	garden := &models.GoalCategory{ID: uuid.New(), UserID: &owner, Name: "Garden", Icon: "flower", Color: "#22c55e", CreatedAt: now, UpdatedAt: now}
+	mustNoErr(t, repo.CreateGoalCategory(garden), "CreateGoalCategory")
+	if err := repo.CreateGoalCategory(&models.GoalCategory{ID: uuid.New(), UserID: &owner, Name: "GARDEN"}); !errors.Is(err, repository.ErrGoalCategoryExists) {
+		t.Fatalf("CreateGoalCategory duplicate: expected ErrGoalCategoryExists, got %v", err)
+	}
+	if err := repo.CreateGoalCategory(&models.GoalCategory{ID: uuid.New(), UserID: &owner, Name: "travel"}); !errors.Is(err, repository.ErrGoalCategoryExists) {
+		t.Fatalf("CreateGoalCategory built-in name: expected ErrGoalCategoryExists, got %v", err)
+	}
+	// Other users may use the same name
+	other := uuid.New()
+	mustNoErr(t, repo.CreateGoalCategory(&models.GoalCategory{ID: uuid.New(), UserID: &other, Name: "Garden"}), "CreateGoalCategory other user")
+
+	got, err := repo.GetGoalCategory(garden.ID, userID)
+	mustNoErr(t, err, "GetGoalCategory")
+	if got.UserID == nil || *got.UserID != userID || got.Icon != "flower" || got.Color != "#22c55e" {
+		t.Fatalf("GetGoalCategory: unexpected %+v", got)
+	}
+	_, err = repo.GetGoalCategory(garden.ID, other)
+	expectNotFound(t, err, "GetGoalCategory other user")
+	if _, err := repo.GetGoalCategory(general.ID, userID); err != nil {
+		t.Fatalf("GetGoalCategory built-in: %v", err)
+	}
+	categories, _ = repo.ListGoalCategories(userID)
+	if len(categories) != 9 {
+		t.Fatalf("ListGoalCategories: expected the built-ins and the user's own, got %d", len(categories))
+	}
+
+	// Renaming copies the new name to linked goals and bumps their version
+	goal := newGoal(userID, "Greenhouse", 800, now, nil)
+	goal.Category, goal.CategoryID = garden.Name, &garden.ID
+	mustNoErr(t, repo.CreateGoal(goal), "CreateGoal")
+	mustNoErr(t, repo.UpdateGoalCategory(garden.ID, userID, map[string]interface{}{"name": "Outdoors", "hidden": true}), "UpdateGoalCategory")
+	renamed, err := repo.GetGoal(goal.ID, userID)
+	mustNoErr(t, err, "GetGoal renamed")
+	if renamed.Category != "Outdoors" || renamed.CategoryID == nil || *renamed.CategoryID != garden.ID || renamed.Version != goal.Version+1 {
+		t.Fatalf("UpdateGoalCategory: expected the goal to be renamed, got %q v%d", renamed.Category, renamed.Version)
+	}
+	got, _ = repo.GetGoalCategory(garden.ID, userID)
+	if got.Name != "Outdoors" || !got.Hidden {
+		t.Fatalf("UpdateGoalCategory: expected a hidden Outdoors category, got %+v", got)
+	}
+	if err := repo.UpdateGoalCategory(garden.ID, userID, map[string]interface{}{"name": "Health"}); !errors.Is(err, repository.ErrGoalCategoryExists) {
+		t.Fatalf("UpdateGoalCategory built-in name: expected ErrGoalCategoryExists, got %v", err)
+	}
+	expectNotFound(t, repo.UpdateGoalCategory(general.ID, userID, map[string]interface{}{"icon": "star"}), "UpdateGoalCategory built-in")
+	expectNotFound(t, repo.UpdateGoalCategory(garden.ID, other, map[string]interface{}{"icon": "star"}), "UpdateGoalCategory other user")
+}
@@ -0,0 +1,71 @@
+package repositorytest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func testGoalTemplates(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	templates, err := repo.ListGoalTemplates(userID)
+	mustNoErr(t, err, "ListGoalTemplates")
+	var emergency *models.GoalTemplate
+	for i, tmpl := range templates {
+		if i > 0 && templates[i-1].Name > tmpl.Name {
+			t.Fatalf("ListGoalTemplates: expected name order")
+		}
+		if tmpl.Name == "Emergency fund" {
+			emergency = &templates[i]
+		}
+	}
+	if emergency == nil || emergency.UserID != nil {
+		t.Fatalf("ListGoalTemplates: expected the built-in Emergency fund template")
+	}
+	var goal models.TemplateGoal
+	if err := json.Unmarshal([]byte(emergency.Definition), &goal); err != nil || goal.Target != "6 * avg_monthly_expenses" {
+		t.Fatalf("ListGoalTemplates: unexpected Emergency fund definition %s", emergency.Definition)
+	}
+	builtIns := len(templates)
+
+	owner := userID
+	now := time.Now().UTC()
+	template := &models.GoalTemplate{
+		ID: uuid.New(), UserID: &owner, Name: "Bike", Definition: `{"name": "Bike", "target": "1500"}`,
+		CreatedAt: now, UpdatedAt: now,
+	}
+	mustNoErr(t, repo.CreateGoalTemplate(template), "CreateGoalTemplate")
+	other := uuid.New()
+	mustNoErr(t, repo.CreateGoalTemplate(&models.GoalTemplate{ID: uuid.New(), UserID: &other, Name: "Car", Definition: `{"name": "Car", "target": "9000"}`}), "CreateGoalTemplate other user")
+
+	templates, _ = repo.ListGoalTemplates(userID)
+	if len(templates) != builtIns+1 {
+		t.Fatalf("ListGoalTemplates: expected the built-ins and the user's own, got %d", len(templates))
+	}
+	got, err := repo.GetGoalTemplate(template.ID, userID)
+	mustNoErr(t, err, "GetGoalTemplate")
+	if got.UserID == nil || *got.UserID != userID || got.Name != "Bike" {
+		t.Fatalf("GetGoalTemplate: unexpected %+v", got)
+	}
+	_, err = repo.GetGoalTemplate(template.ID, other)
+	expectNotFound(t, err, "GetGoalTemplate other user")
+	if _, err := repo.GetGoalTemplate(emergency.ID, userID); err != nil {
+		t.Fatalf("GetGoalTemplate built-in: %v", err)
+	}
+
+	mustNoErr(t, repo.UpdateGoalTemplate(template.ID, userID, map[string]interface{}{"name": "E-bike", "definition": `{"name": "E-bike", "target": "2500"}`}), "UpdateGoalTemplate")
+	got, _ = repo.GetGoalTemplate(template.ID, userID)
+	if got.Name != "E-bike" {
+		t.Fatalf("UpdateGoalTemplate: expected the new name, got %q", got.Name)
+	}
+	expectNotFound(t, repo.UpdateGoalTemplate(emergency.ID, userID, map[string]interface{}{"name": "Mine"}), "UpdateGoalTemplate built-in")
+	expectNotFound(t, repo.DeleteGoalTemplate(emergency.ID, userID), "DeleteGoalTemplate built-in")
+	expectNotFound(t, repo.DeleteGoalTemplate(template.ID, other), "DeleteGoalTemplate other user")
+	mustNoErr(t, repo.DeleteGoalTemplate(template.ID, userID), "DeleteGoalTemplate")
+	_, err = repo.GetGoalTemplate(template.ID, userID)
+	expectNotFound(t, err, "GetGoalTemplate deleted")
+}
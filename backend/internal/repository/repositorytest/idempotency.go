@@ -0,0 +1,63 @@
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TestIdempotencyRepository runs the idempotency key repository contract against implementations created by newRepo
+func TestIdempotencyRepository(t *testing.T, newRepo func(t *testing.T) repository.IdempotencyRepositoryInterface) {
+	repo := newRepo(t)
+	userID := uuid.New()
+	key := "retry-" + uuid.NewString()
+
+	record := &models.IdempotencyKey{UserID: userID, Key: key, Method: "POST", Path: "/api/finance/expenses", RequestHash: "abc", CreatedAt: time.Now().UTC()}
+	mustNoErr(t, repo.CreateIdempotencyKey(record), "CreateIdempotencyKey")
+	again := *record
+	if err := repo.CreateIdempotencyKey(&again); !errors.Is(err, repository.ErrIdempotencyKeyExists) {
+		t.Fatalf("CreateIdempotencyKey twice: expected ErrIdempotencyKeyExists, got %v", err)
+	}
+	// Keys are scoped to a user
+	mustNoErr(t, repo.CreateIdempotencyKey(&models.IdempotencyKey{UserID: uuid.New(), Key: key, Method: "POST", Path: "/", RequestHash: "abc", CreatedAt: time.Now().UTC()}), "CreateIdempotencyKey other user")
+
+	got, err := repo.GetIdempotencyKey(userID, key)
+	mustNoErr(t, err, "GetIdempotencyKey")
+	if got.Completed() || got.RequestHash != "abc" || got.Path != "/api/finance/expenses" {
+		t.Fatalf("GetIdempotencyKey: reserved key does not match")
+	}
+	_, err = repo.GetIdempotencyKey(userID, "unknown")
+	expectNotFound(t, err, "GetIdempotencyKey unknown key")
+
+	body := []byte(`{"id":"1"}`)
+	mustNoErr(t, repo.CompleteIdempotencyKey(userID, key, 201, "application/json", body), "CompleteIdempotencyKey")
+	got, err = repo.GetIdempotencyKey(userID, key)
+	mustNoErr(t, err, "GetIdempotencyKey after complete")
+	if !got.Completed() || got.StatusCode != 201 || got.ContentType != "application/json" || string(got.ResponseBody) != string(body) {
+		t.Fatalf("GetIdempotencyKey: stored response does not match, got %d %q", got.StatusCode, got.ResponseBody)
+	}
+	expectNotFound(t, repo.CompleteIdempotencyKey(userID, "unknown", 200, "", nil), "CompleteIdempotencyKey unknown key")
+
+	mustNoErr(t, repo.DeleteIdempotencyKey(userID, key), "DeleteIdempotencyKey")
+	_, err = repo.GetIdempotencyKey(userID, key)
+	expectNotFound(t, err, "GetIdempotencyKey after delete")
+
+	old := &models.IdempotencyKey{UserID: userID, Key: "old-" + uuid.NewString(), Method: "POST", Path: "/", RequestHash: "abc", CreatedAt: time.Now().UTC().Add(-48 * time.Hour)}
+	fresh := &models.IdempotencyKey{UserID: userID, Key: "fresh-" + uuid.NewString(), Method: "POST", Path: "/", RequestHash: "abc", CreatedAt: time.Now().UTC()}
+	mustNoErr(t, repo.CreateIdempotencyKey(old), "CreateIdempotencyKey old")
+	mustNoErr(t, repo.CreateIdempotencyKey(fresh), "CreateIdempotencyKey fresh")
+	purged, err := repo.PurgeIdempotencyKeys(time.Now().UTC().Add(-24 * time.Hour))
+	mustNoErr(t, err, "PurgeIdempotencyKeys")
+	if purged < 1 {
+		t.Fatalf("PurgeIdempotencyKeys: expected the expired key to be purged")
+	}
+	_, err = repo.GetIdempotencyKey(userID, old.Key)
+	expectNotFound(t, err, "GetIdempotencyKey after purge")
+	_, err = repo.GetIdempotencyKey(userID, fresh.Key)
+	mustNoErr(t, err, "GetIdempotencyKey inside the retention window")
+}
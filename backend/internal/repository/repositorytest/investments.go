@@ -0,0 +1,115 @@
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func newSecurity(userID uuid.UUID, symbol, assetClass string) *models.Security {
+	return &models.Security{ID: uuid.New(), UserID: userID, Symbol: symbol, Name: symbol, AssetClass: assetClass}
+}
+
+func testInvestments(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	fund := newSecurity(userID, "VWRL", models.AssetClassFund)
+	bond := newSecurity(userID, "AGGG", models.AssetClassBond)
+	mustNoErr(t, repo.CreateSecurity(fund), "CreateSecurity fund")
+	mustNoErr(t, repo.CreateSecurity(bond), "CreateSecurity bond")
+	mustNoErr(t, repo.CreateSecurity(newSecurity(uuid.New(), "VWRL", models.AssetClassFund)), "CreateSecurity other user")
+	if err := repo.CreateSecurity(newSecurity(userID, "VWRL", models.AssetClassStock)); !errors.Is(err, repository.ErrSecurityExists) {
+		t.Fatalf("CreateSecurity duplicate: expected ErrSecurityExists, got %v", err)
+	}
+
+	securities, err := repo.ListSecurities(userID)
+	mustNoErr(t, err, "ListSecurities")
+	if len(securities) != 2 || securities[0].ID != bond.ID {
+		t.Fatalf("ListSecurities: expected the user's 2 securities by symbol, got %+v", securities)
+	}
+	if err := repo.UpdateSecurity(bond.ID, userID, map[string]interface{}{"symbol": "VWRL"}); !errors.Is(err, repository.ErrSecurityExists) {
+		t.Fatalf("UpdateSecurity duplicate: expected ErrSecurityExists, got %v", err)
+	}
+	mustNoErr(t, repo.UpdateSecurity(bond.ID, userID, map[string]interface{}{"name": "Global bonds"}), "UpdateSecurity")
+	got, err := repo.GetSecurity(bond.ID, userID)
+	mustNoErr(t, err, "GetSecurity")
+	if got.Name != "Global bonds" || got.Symbol != "AGGG" {
+		t.Fatalf("GetSecurity: unexpected security after update %+v", got)
+	}
+	_, err = repo.GetSecurity(bond.ID, uuid.New())
+	expectNotFound(t, err, "GetSecurity other user")
+
+	later := &models.InvestmentLot{ID: uuid.New(), UserID: userID, SecurityID: fund.ID, BoughtOn: date(2024, 3, 1), Quantity: 5, Cost: 550}
+	earlier := &models.InvestmentLot{ID: uuid.New(), UserID: userID, SecurityID: bond.ID, BoughtOn: date(2024, 1, 15), Quantity: 2.5, Cost: 250}
+	mustNoErr(t, repo.CreateInvestmentLot(later), "CreateInvestmentLot later")
+	mustNoErr(t, repo.CreateInvestmentLot(earlier), "CreateInvestmentLot earlier")
+	lots, err := repo.ListInvestmentLots(userID)
+	mustNoErr(t, err, "ListInvestmentLots")
+	if len(lots) != 2 || lots[0].ID != earlier.ID || lots[0].Quantity != 2.5 || !lots[0].BoughtOn.Equal(date(2024, 1, 15)) {
+		t.Fatalf("ListInvestmentLots: expected the lots by purchase date, got %+v", lots)
+	}
+	sale := &models.InvestmentSale{ID: uuid.New(), UserID: userID, SecurityID: fund.ID, SoldOn: date(2024, 4, 1), Quantity: 2, Proceeds: 240}
+	mustNoErr(t, repo.CreateInvestmentSale(sale), "CreateInvestmentSale")
+	sales, err := repo.ListInvestmentSales(userID)
+	mustNoErr(t, err, "ListInvestmentSales")
+	if len(sales) != 1 || sales[0].Proceeds != 240 {
+		t.Fatalf("ListInvestmentSales: expected the sale, got %+v", sales)
+	}
+	expectNotFound(t, repo.DeleteInvestmentSale(sale.ID, uuid.New()), "DeleteInvestmentSale other user")
+	mustNoErr(t, repo.DeleteInvestmentSale(sale.ID, userID), "DeleteInvestmentSale")
+	expectNotFound(t, repo.DeleteInvestmentLot(earlier.ID, uuid.New()), "DeleteInvestmentLot other user")
+
+	// A second import of a day replaces its price
+	mustNoErr(t, repo.SaveSecurityPrices([]models.SecurityPrice{
+		{SecurityID: fund.ID, UserID: userID, PricedOn: date(2024, 3, 1), Close: 110},
+		{SecurityID: fund.ID, UserID: userID, PricedOn: date(2024, 3, 28), Close: 115},
+		{SecurityID: bond.ID, UserID: userID, PricedOn: date(2024, 3, 15), Close: 98.5},
+	}), "SaveSecurityPrices")
+	mustNoErr(t, repo.SaveSecurityPrices([]models.SecurityPrice{{SecurityID: fund.ID, UserID: userID, PricedOn: date(2024, 3, 28), Close: 116.25}}), "SaveSecurityPrices again")
+	prices, err := repo.ListSecurityPrices(fund.ID, userID, date(2024, 3, 1), date(2024, 4, 1))
+	mustNoErr(t, err, "ListSecurityPrices")
+	if len(prices) != 2 || prices[1].Close != 116.25 {
+		t.Fatalf("ListSecurityPrices: expected the replaced price, got %+v", prices)
+	}
+	latest, err := repo.LatestSecurityPrices(userID, date(2024, 3, 20))
+	mustNoErr(t, err, "LatestSecurityPrices")
+	if len(latest) != 2 || latest[fund.ID].Close != 110 || !latest[bond.ID].PricedOn.Equal(date(2024, 3, 15)) {
+		t.Fatalf("LatestSecurityPrices: expected each security's last price up to the day, got %+v", latest)
+	}
+
+	dividend := newIncome(userID, "VWRL dividend", 12.5, date(2024, 3, 20))
+	dividend.SecurityID = &fund.ID
+	deleted := newIncome(userID, "VWRL dividend", 10, date(2024, 2, 20))
+	deleted.SecurityID = &fund.ID
+	mustNoErr(t, repo.CreateIncome(dividend), "CreateIncome dividend")
+	mustNoErr(t, repo.CreateIncome(deleted), "CreateIncome deleted dividend")
+	mustNoErr(t, repo.CreateIncome(newIncome(userID, "Salary", 3000, date(2024, 3, 1))), "CreateIncome salary")
+	mustNoErr(t, repo.DeleteIncome(deleted.ID, userID), "DeleteIncome")
+	dividends, err := repo.ListDividends(userID)
+	mustNoErr(t, err, "ListDividends")
+	if len(dividends) != 1 || dividends[0].ID != dividend.ID || dividends[0].SecurityID == nil || *dividends[0].SecurityID != fund.ID {
+		t.Fatalf("ListDividends: expected the live dividend, got %+v", dividends)
+	}
+
+	// Deleting a security removes its lots and prices and keeps its dividends as plain incomes
+	mustNoErr(t, repo.DeleteSecurity(fund.ID, userID), "DeleteSecurity")
+	expectNotFound(t, repo.DeleteSecurity(fund.ID, userID), "DeleteSecurity twice")
+	lots, _ = repo.ListInvestmentLots(userID)
+	if len(lots) != 1 || lots[0].ID != earlier.ID {
+		t.Fatalf("DeleteSecurity: expected its lots to go, got %+v", lots)
+	}
+	if prices, _ := repo.ListSecurityPrices(fund.ID, userID, date(2024, 1, 1), date(2025, 1, 1)); len(prices) != 0 {
+		t.Fatalf("DeleteSecurity: expected its prices to go, got %+v", prices)
+	}
+	income, err := repo.GetIncome(dividend.ID, userID)
+	mustNoErr(t, err, "GetIncome unlinked dividend")
+	if income.SecurityID != nil || income.Version != dividend.Version+1 {
+		t.Fatalf("DeleteSecurity: expected the dividend to be unlinked with a new version, got %+v", income)
+	}
+	mustNoErr(t, repo.RestoreIncome(deleted.ID, userID), "RestoreIncome")
+	if restored, _ := repo.GetIncome(deleted.ID, userID); restored == nil || restored.SecurityID != nil {
+		t.Fatalf("DeleteSecurity: expected the deleted dividend to be unlinked too, got %+v", restored)
+	}
+}
@@ -0,0 +1,73 @@
+package repositorytest
+
+import (
+	"testing"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func testGoalLifecycle(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	goal := newGoal(userID, "Emergency fund", 5000, date(2024, 1, 1), nil)
+	mustNoErr(t, repo.CreateGoal(goal), "CreateGoal")
+	got, err := repo.GetGoal(goal.ID, userID)
+	mustNoErr(t, err, "GetGoal")
+	if got.Status != models.GoalActive || got.StatusChangedAt != nil {
+		t.Fatalf("GetGoal: expected new goals to be active, got %q changed at %v", got.Status, got.StatusChangedAt)
+	}
+
+	changedAt := date(2024, 3, 1)
+	mustNoErr(t, repo.UpdateGoal(goal.ID, userID, 0, map[string]interface{}{"status": models.GoalPaused, "status_changed_at": changedAt}), "UpdateGoal status")
+	later := &models.GoalStatusChange{ID: uuid.New(), UserID: userID, GoalID: goal.ID, FromStatus: models.GoalPaused, ToStatus: models.GoalActive, ChangedAt: date(2024, 4, 1)}
+	earlier := &models.GoalStatusChange{ID: uuid.New(), UserID: userID, GoalID: goal.ID, FromStatus: models.GoalActive, ToStatus: models.GoalPaused, Note: "Saving for a car first", ChangedAt: changedAt}
+	mustNoErr(t, repo.CreateGoalStatusChange(later), "CreateGoalStatusChange")
+	mustNoErr(t, repo.CreateGoalStatusChange(earlier), "CreateGoalStatusChange")
+
+	got, err = repo.GetGoal(goal.ID, userID)
+	mustNoErr(t, err, "GetGoal")
+	if got.Status != models.GoalPaused || got.StatusChangedAt == nil || !got.StatusChangedAt.Equal(changedAt) {
+		t.Fatalf("GetGoal: expected the goal to be paused at %v, got %q at %v", changedAt, got.Status, got.StatusChangedAt)
+	}
+	mustNoErr(t, repo.Transaction(func(tx repository.FinanceRepositoryInterface) error {
+		locked, err := tx.LockGoal(goal.ID, userID)
+		if err == nil && locked.Status != models.GoalPaused {
+			t.Fatalf("LockGoal: expected the paused goal, got %q", locked.Status)
+		}
+		return err
+	}), "LockGoal")
+	mustNoErr(t, repo.Transaction(func(tx repository.FinanceRepositoryInterface) error {
+		return tx.LockAllocations(userID)
+	}), "LockAllocations")
+	_, err = repo.LockGoal(goal.ID, uuid.New())
+	expectNotFound(t, err, "LockGoal other user")
+	history, err := repo.ListGoalStatusChanges(goal.ID, userID)
+	mustNoErr(t, err, "ListGoalStatusChanges")
+	if len(history) != 2 || history[0].ID != earlier.ID || history[0].Note != "Saving for a car first" {
+		t.Fatalf("ListGoalStatusChanges: expected both changes oldest first, got %+v", history)
+	}
+	other, err := repo.ListGoalStatusChanges(goal.ID, uuid.New())
+	mustNoErr(t, err, "ListGoalStatusChanges other user")
+	if len(other) != 0 {
+		t.Fatalf("ListGoalStatusChanges: expected no changes for another user, got %d", len(other))
+	}
+
+	// Withdrawals are negative contributions and reduce the goal's progress
+	reason := "Car repair"
+	withdrawal := newContribution(userID, goal.ID, -200, date(2024, 2, 10))
+	withdrawal.Reason = &reason
+	mustNoErr(t, repo.CreateGoalContribution(newContribution(userID, goal.ID, 500, date(2024, 2, 1))), "CreateGoalContribution")
+	mustNoErr(t, repo.CreateGoalContribution(withdrawal), "CreateGoalContribution withdrawal")
+	stored, err := repo.GetGoalContribution(withdrawal.ID, userID)
+	mustNoErr(t, err, "GetGoalContribution withdrawal")
+	if stored.Reason == nil || *stored.Reason != reason {
+		t.Fatalf("GetGoalContribution: expected the withdrawal reason, got %v", stored.Reason)
+	}
+	goals, err := repo.ListGoalsWithProgress(userID)
+	mustNoErr(t, err, "ListGoalsWithProgress")
+	expectAmount(t, goals[0].ContributedSum, 300, "contributed less withdrawn")
+	summary, err := repo.GetMonthlySummary(userID, 2024, 2)
+	mustNoErr(t, err, "GetMonthlySummary")
+	expectAmount(t, summary.TotalSavings, 300, "monthly savings less withdrawals")
+}
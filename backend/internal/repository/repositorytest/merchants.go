@@ -0,0 +1,96 @@
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func newMerchant(userID uuid.UUID, name string) *models.Merchant {
+	return &models.Merchant{ID: uuid.New(), UserID: userID, Name: name, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
+}
+
+func newMerchantPattern(userID, merchantID uuid.UUID, pattern string) *models.MerchantPattern {
+	return &models.MerchantPattern{ID: uuid.New(), UserID: userID, MerchantID: merchantID, Pattern: pattern, MatchType: models.MatchContains, CreatedAt: time.Now().UTC()}
+}
+
+func testMerchants(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	amazon := newMerchant(userID, "Amazon")
+	mustNoErr(t, repo.CreateMerchant(amazon), "CreateMerchant")
+	if err := repo.CreateMerchant(newMerchant(userID, "AMAZON")); !errors.Is(err, repository.ErrMerchantExists) {
+		t.Fatalf("CreateMerchant duplicate: expected ErrMerchantExists, got %v", err)
+	}
+	mustNoErr(t, repo.CreateMerchant(newMerchant(uuid.New(), "Amazon")), "CreateMerchant other user")
+	amzn := newMerchant(userID, "AMZN Mktp")
+	mustNoErr(t, repo.CreateMerchant(amzn), "CreateMerchant amzn")
+
+	merchants, err := repo.ListMerchants(userID)
+	mustNoErr(t, err, "ListMerchants")
+	if len(merchants) != 2 || merchants[0].ID != amazon.ID {
+		t.Fatalf("ListMerchants: expected the user's 2 merchants by name, got %d", len(merchants))
+	}
+	_, err = repo.GetMerchant(amazon.ID, uuid.New())
+	expectNotFound(t, err, "GetMerchant other user")
+
+	mustNoErr(t, repo.CreateMerchantPattern(newMerchantPattern(userID, amazon.ID, "amazon")), "CreateMerchantPattern")
+	mustNoErr(t, repo.CreateMerchantPattern(newMerchantPattern(userID, amzn.ID, "amzn mktp")), "CreateMerchantPattern amzn")
+	if err := repo.CreateMerchantPattern(newMerchantPattern(userID, amzn.ID, "amazon")); !errors.Is(err, repository.ErrMerchantPatternExists) {
+		t.Fatalf("CreateMerchantPattern duplicate: expected ErrMerchantPatternExists, got %v", err)
+	}
+
+	e1 := newExpense(userID, "shopping", 30, date(2024, 9, 3), nil)
+	e1.MerchantID = &amzn.ID
+	e2 := newExpense(userID, "shopping", 45, date(2024, 9, 9), nil)
+	e2.MerchantID = &amazon.ID
+	mustNoErr(t, repo.CreateExpense(e1), "CreateExpense e1")
+	mustNoErr(t, repo.CreateExpense(e2), "CreateExpense e2")
+
+	summary, err := repo.GetMonthlySummary(userID, 2024, 9)
+	mustNoErr(t, err, "GetMonthlySummary")
+	expectAmount(t, summary.MerchantSpending[amzn.ID], 30, "amzn spending before merge")
+
+	if _, err := repo.MergeMerchants(userID, amazon.ID, []uuid.UUID{uuid.New()}); err == nil {
+		t.Fatalf("MergeMerchants with unknown source: expected an error")
+	}
+	moved, err := repo.MergeMerchants(userID, amazon.ID, []uuid.UUID{amzn.ID})
+	mustNoErr(t, err, "MergeMerchants")
+	if moved != 1 {
+		t.Fatalf("MergeMerchants: expected 1 expense moved, got %d", moved)
+	}
+	_, err = repo.GetMerchant(amzn.ID, userID)
+	expectNotFound(t, err, "GetMerchant after merge")
+	got, err := repo.GetExpense(e1.ID, userID)
+	mustNoErr(t, err, "GetExpense after merge")
+	if got.MerchantID == nil || *got.MerchantID != amazon.ID || got.Version != 2 {
+		t.Fatalf("MergeMerchants: expected the expense to move to the target with a new version")
+	}
+	patterns, err := repo.ListMerchantPatterns(userID)
+	mustNoErr(t, err, "ListMerchantPatterns")
+	if len(patterns) != 2 || patterns[0].MerchantID != amazon.ID || patterns[1].MerchantID != amazon.ID {
+		t.Fatalf("MergeMerchants: expected both patterns to belong to the target, got %+v", patterns)
+	}
+	summary, err = repo.GetMonthlySummary(userID, 2024, 9)
+	mustNoErr(t, err, "GetMonthlySummary after merge")
+	expectAmount(t, summary.MerchantSpending[amazon.ID], 75, "merged spending")
+
+	mustNoErr(t, repo.UpdateMerchant(amazon.ID, userID, map[string]interface{}{"name": "Amazon.com"}), "UpdateMerchant")
+	mustNoErr(t, repo.DeleteMerchantPattern(patterns[0].ID, userID), "DeleteMerchantPattern")
+	expectNotFound(t, repo.DeleteMerchantPattern(patterns[0].ID, userID), "DeleteMerchantPattern twice")
+
+	mustNoErr(t, repo.DeleteMerchant(amazon.ID, userID), "DeleteMerchant")
+	got, err = repo.GetExpense(e2.ID, userID)
+	mustNoErr(t, err, "GetExpense after merchant delete")
+	if got.MerchantID != nil {
+		t.Fatalf("DeleteMerchant: expected expenses to be unlinked")
+	}
+	patterns, _ = repo.ListMerchantPatterns(userID)
+	if len(patterns) != 0 {
+		t.Fatalf("DeleteMerchant: expected patterns to be removed, got %d", len(patterns))
+	}
+	expectNotFound(t, repo.DeleteMerchant(amazon.ID, userID), "DeleteMerchant twice")
+}
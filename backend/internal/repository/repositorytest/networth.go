@@ -0,0 +1,84 @@
+package repositorytest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func newNetWorthItem(userID uuid.UUID, name, class, itemType string) *models.NetWorthItem {
+	return &models.NetWorthItem{ID: uuid.New(), UserID: userID, Name: name, Class: class, Type: itemType}
+}
+
+func testNetWorth(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	house := newNetWorthItem(userID, "House", models.NetWorthAsset, "property")
+	house.CreatedAt = date(2024, 1, 1)
+	mortgage := newNetWorthItem(userID, "Mortgage", models.NetWorthLiability, "mortgage")
+	mortgage.CreatedAt = date(2024, 1, 2)
+	mustNoErr(t, repo.CreateNetWorthItem(house), "CreateNetWorthItem house")
+	mustNoErr(t, repo.CreateNetWorthItem(mortgage), "CreateNetWorthItem mortgage")
+
+	items, err := repo.ListNetWorthItems(userID)
+	mustNoErr(t, err, "ListNetWorthItems")
+	if len(items) != 2 || items[0].ID != house.ID {
+		t.Fatalf("ListNetWorthItems: expected both items oldest first, got %d", len(items))
+	}
+	_, err = repo.GetNetWorthItem(house.ID, uuid.New())
+	expectNotFound(t, err, "GetNetWorthItem other user")
+	mustNoErr(t, repo.UpdateNetWorthItem(house.ID, userID, map[string]interface{}{"name": "Home"}), "UpdateNetWorthItem")
+	got, err := repo.GetNetWorthItem(house.ID, userID)
+	mustNoErr(t, err, "GetNetWorthItem")
+	if got.Name != "Home" || got.Class != models.NetWorthAsset {
+		t.Fatalf("GetNetWorthItem: unexpected item after update %+v", got)
+	}
+
+	valuation := &models.NetWorthValuation{ID: uuid.New(), UserID: userID, ItemID: house.ID, Value: 300000, ValuedOn: date(2024, 3, 1)}
+	mustNoErr(t, repo.SaveNetWorthValuation(valuation), "SaveNetWorthValuation")
+	mustNoErr(t, repo.SaveNetWorthValuation(&models.NetWorthValuation{ID: uuid.New(), UserID: userID, ItemID: house.ID, Value: 290000, ValuedOn: date(2024, 1, 1)}), "SaveNetWorthValuation earlier")
+	mustNoErr(t, repo.SaveNetWorthValuation(&models.NetWorthValuation{ID: uuid.New(), UserID: userID, ItemID: mortgage.ID, Value: 200000, ValuedOn: date(2024, 1, 1)}), "SaveNetWorthValuation mortgage")
+
+	// Valuing an item again on the same day replaces the value
+	again := &models.NetWorthValuation{ID: uuid.New(), UserID: userID, ItemID: house.ID, Value: 310000, ValuedOn: date(2024, 3, 1), Note: "Survey"}
+	mustNoErr(t, repo.SaveNetWorthValuation(again), "SaveNetWorthValuation same day")
+	if again.ID != valuation.ID {
+		t.Fatalf("SaveNetWorthValuation: expected the stored valuation's ID, got %s", again.ID)
+	}
+	valuations, err := repo.ListNetWorthValuations(userID)
+	mustNoErr(t, err, "ListNetWorthValuations")
+	if len(valuations) != 3 || !valuations[0].ValuedOn.Equal(date(2024, 1, 1)) || valuations[2].Value != 310000 || valuations[2].Note != "Survey" {
+		t.Fatalf("ListNetWorthValuations: expected 3 valuations by date with the replaced value, got %+v", valuations)
+	}
+	expectNotFound(t, repo.DeleteNetWorthValuation(valuation.ID, uuid.New()), "DeleteNetWorthValuation other user")
+	mustNoErr(t, repo.DeleteNetWorthValuation(valuation.ID, userID), "DeleteNetWorthValuation")
+
+	// Deleting an item deletes its valuations
+	mustNoErr(t, repo.DeleteNetWorthItem(mortgage.ID, userID), "DeleteNetWorthItem")
+	expectNotFound(t, repo.DeleteNetWorthItem(mortgage.ID, userID), "DeleteNetWorthItem twice")
+	valuations, _ = repo.ListNetWorthValuations(userID)
+	if len(valuations) != 1 || valuations[0].ItemID != house.ID {
+		t.Fatalf("DeleteNetWorthItem: expected only the house's earlier valuation, got %+v", valuations)
+	}
+
+	snapshot := &models.NetWorthSnapshot{ID: uuid.New(), UserID: userID, TakenOn: date(2024, 1, 31), Assets: 290000, Liabilities: 200000, NetWorth: 90000, Breakdown: "[]"}
+	mustNoErr(t, repo.CreateNetWorthSnapshot(snapshot), "CreateNetWorthSnapshot")
+	mustNoErr(t, repo.CreateNetWorthSnapshot(&models.NetWorthSnapshot{ID: uuid.New(), UserID: userID, TakenOn: date(2024, 2, 29), Breakdown: "[]"}), "CreateNetWorthSnapshot february")
+	if err := repo.CreateNetWorthSnapshot(&models.NetWorthSnapshot{ID: uuid.New(), UserID: userID, TakenOn: date(2024, 1, 31), Breakdown: "[]"}); !errors.Is(err, repository.ErrNetWorthSnapshotExists) {
+		t.Fatalf("CreateNetWorthSnapshot duplicate: expected ErrNetWorthSnapshotExists, got %v", err)
+	}
+	snapshots, err := repo.ListNetWorthSnapshots(userID, date(2024, 1, 1), date(2024, 2, 29))
+	mustNoErr(t, err, "ListNetWorthSnapshots")
+	if len(snapshots) != 1 || snapshots[0].NetWorth != 90000 {
+		t.Fatalf("ListNetWorthSnapshots: expected January's snapshot only, got %+v", snapshots)
+	}
+
+	users, err := repo.ListNetWorthUsers()
+	mustNoErr(t, err, "ListNetWorthUsers")
+	if !slices.Contains(users, userID) {
+		t.Fatal("ListNetWorthUsers: expected the user with net worth items")
+	}
+}
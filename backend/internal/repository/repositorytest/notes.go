@@ -0,0 +1,155 @@
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TestNotesRepository runs the notes repository contract against implementations created by newRepo
+func TestNotesRepository(t *testing.T, newRepo func(t *testing.T) repository.NotesRepositoryInterface) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo repository.NotesRepositoryInterface, userID uuid.UUID)
+	}{
+		{"CreateAndGet", testNoteCreateAndGet},
+		{"ListOrder", testNoteListOrder},
+		{"Update", testNoteUpdate},
+		{"Delete", testNoteDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepo(t), uuid.New())
+		})
+	}
+}
+
+func newNote(userID uuid.UUID, title string, createdAt time.Time) *models.Note {
+	return &models.Note{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Title:     title,
+		Content:   "content of " + title,
+		Category:  "general",
+		Tags:      []string{"a", "b"},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+// expectErrMessage checks error messages that NotesService matches on
+func expectErrMessage(t *testing.T, err error, want, what string) {
+	t.Helper()
+	if err == nil || err.Error() != want {
+		t.Fatalf("%s: expected error %q, got %v", what, want, err)
+	}
+}
+
+func testNoteCreateAndGet(t *testing.T, repo repository.NotesRepositoryInterface, userID uuid.UUID) {
+	note := newNote(userID, "Budget ideas", time.Now().UTC())
+	mustNoErr(t, repo.CreateNote(note), "CreateNote")
+
+	got, err := repo.GetNoteByID(note.ID, userID)
+	mustNoErr(t, err, "GetNoteByID")
+	if got.Title != note.Title || got.Content != note.Content || len(got.Tags) != 2 {
+		t.Fatalf("GetNoteByID: stored note does not match")
+	}
+
+	// Mutating the returned value must not change the stored note
+	got.Tags[0] = "changed"
+	again, _ := repo.GetNoteByID(note.ID, userID)
+	if again.Tags[0] != "a" {
+		t.Fatalf("GetNoteByID: returned note shares state with the repository")
+	}
+
+	_, err = repo.GetNoteByID(note.ID, uuid.New())
+	expectErrMessage(t, err, "note not found", "GetNoteByID other user")
+	_, err = repo.GetNoteByID(uuid.New(), userID)
+	expectErrMessage(t, err, "note not found", "GetNoteByID unknown id")
+}
+
+func testNoteListOrder(t *testing.T, repo repository.NotesRepositoryInterface, userID uuid.UUID) {
+	base := time.Now().UTC().Add(-time.Hour)
+	first := newNote(userID, "first", base)
+	second := newNote(userID, "second", base.Add(time.Minute))
+	for _, n := range []*models.Note{first, second} {
+		mustNoErr(t, repo.CreateNote(n), "CreateNote")
+	}
+	mustNoErr(t, repo.CreateNote(newNote(uuid.New(), "foreign", base)), "CreateNote other user")
+
+	notes, err := repo.GetNotesByUserID(userID)
+	mustNoErr(t, err, "GetNotesByUserID")
+	if len(notes) != 2 || notes[0].ID != second.ID || notes[1].ID != first.ID {
+		t.Fatalf("GetNotesByUserID: expected the user's notes newest first")
+	}
+}
+
+func testNoteUpdate(t *testing.T, repo repository.NotesRepositoryInterface, userID uuid.UUID) {
+	note := newNote(userID, "draft", time.Now().UTC())
+	mustNoErr(t, repo.CreateNote(note), "CreateNote")
+
+	mustNoErr(t, repo.UpdateNote(note.ID, userID, 0, map[string]interface{}{"title": "final", "is_favorite": true}), "UpdateNote")
+	got, err := repo.GetNoteByID(note.ID, userID)
+	mustNoErr(t, err, "GetNoteByID")
+	if got.Title != "final" || !got.IsFavorite {
+		t.Fatalf("UpdateNote: changes not applied")
+	}
+	if got.Version != 2 {
+		t.Fatalf("UpdateNote: got version %d, want 2", got.Version)
+	}
+
+	mustNoErr(t, repo.UpdateNote(note.ID, userID, 2, map[string]interface{}{"title": "published"}), "UpdateNote at current version")
+	if err := repo.UpdateNote(note.ID, userID, 2, map[string]interface{}{"title": "stale"}); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("UpdateNote at stale version: expected repository.ErrVersionConflict, got %v", err)
+	}
+	got, _ = repo.GetNoteByID(note.ID, userID)
+	if got.Title != "published" || got.Version != 3 {
+		t.Fatalf("UpdateNote: got title %q version %d after rejected update", got.Title, got.Version)
+	}
+
+	expectErrMessage(t, repo.UpdateNote(note.ID, userID, 0, map[string]interface{}{}), "no updates provided", "UpdateNote empty")
+	expectErrMessage(t, repo.UpdateNote(uuid.New(), userID, 0, map[string]interface{}{"title": "x"}), "note not found or no changes made", "UpdateNote unknown id")
+	expectErrMessage(t, repo.UpdateNote(note.ID, uuid.New(), 0, map[string]interface{}{"title": "x"}), "note not found or no changes made", "UpdateNote other user")
+}
+
+func testNoteDelete(t *testing.T, repo repository.NotesRepositoryInterface, userID uuid.UUID) {
+	note := newNote(userID, "temporary", time.Now().UTC())
+	mustNoErr(t, repo.CreateNote(note), "CreateNote")
+
+	expectErrMessage(t, repo.DeleteNote(note.ID, uuid.New()), "note not found", "DeleteNote other user")
+	mustNoErr(t, repo.DeleteNote(note.ID, userID), "DeleteNote")
+	expectErrMessage(t, repo.DeleteNote(note.ID, userID), "note not found", "DeleteNote twice")
+	_, err := repo.GetNoteByID(note.ID, userID)
+	expectErrMessage(t, err, "note not found", "GetNoteByID after delete")
+	notes, _ := repo.GetNotesByUserID(userID)
+	if len(notes) != 0 {
+		t.Fatalf("GetNotesByUserID: trashed notes must not be listed")
+	}
+
+	trashed, err := repo.ListDeletedNotes(userID)
+	mustNoErr(t, err, "ListDeletedNotes")
+	if len(trashed) != 1 || trashed[0].ID != note.ID || !trashed[0].DeletedAt.Valid {
+		t.Fatalf("ListDeletedNotes: expected the deleted note")
+	}
+	expectErrMessage(t, repo.RestoreNote(note.ID, uuid.New()), "note not found", "RestoreNote other user")
+	mustNoErr(t, repo.RestoreNote(note.ID, userID), "RestoreNote")
+	expectErrMessage(t, repo.RestoreNote(note.ID, userID), "note not found", "RestoreNote twice")
+	_, err = repo.GetNoteByID(note.ID, userID)
+	mustNoErr(t, err, "GetNoteByID after restore")
+
+	mustNoErr(t, repo.DeleteNote(note.ID, userID), "DeleteNote again")
+	_, err = repo.PurgeDeletedNotes(time.Now().Add(-time.Hour))
+	mustNoErr(t, err, "PurgeDeletedNotes before cutoff")
+	trashed, _ = repo.ListDeletedNotes(userID)
+	if len(trashed) != 1 {
+		t.Fatalf("PurgeDeletedNotes: notes inside the retention window must be kept")
+	}
+	_, err = repo.PurgeDeletedNotes(time.Now().Add(time.Minute))
+	mustNoErr(t, err, "PurgeDeletedNotes")
+	expectErrMessage(t, repo.RestoreNote(note.ID, userID), "note not found", "RestoreNote after purge")
+}
@@ -0,0 +1,72 @@
+package repositorytest
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TestRuleRepository runs the expense rule repository contract against implementations created by newRepo
+func TestRuleRepository(t *testing.T, newRepo func(t *testing.T) repository.RuleRepositoryInterface) {
+	repo := newRepo(t)
+	userID := uuid.New()
+	category := "Transport"
+
+	newRule := func(name string, priority int) *models.ExpenseRule {
+		return &models.ExpenseRule{
+			ID:          uuid.New(),
+			UserID:      userID,
+			Name:        name,
+			Priority:    priority,
+			Enabled:     true,
+			Conditions:  `[{"field":"description","operator":"contains","value":"uber"}]`,
+			SetCategory: &category,
+			CreatedAt:   time.Now().UTC(),
+		}
+	}
+	late := newRule("late", 10)
+	early := newRule("early", 1)
+	mustNoErr(t, repo.CreateRule(late), "CreateRule late")
+	mustNoErr(t, repo.CreateRule(early), "CreateRule early")
+	mustNoErr(t, repo.CreateRule(&models.ExpenseRule{ID: uuid.New(), UserID: uuid.New(), Name: "other user", Conditions: "[]", CreatedAt: time.Now().UTC()}), "CreateRule other user")
+
+	rules, err := repo.ListRules(userID)
+	mustNoErr(t, err, "ListRules")
+	if len(rules) != 2 || rules[0].ID != early.ID || rules[1].ID != late.ID {
+		t.Fatalf("ListRules: expected the user's rules in priority order, got %d rules", len(rules))
+	}
+
+	got, err := repo.GetRule(early.ID, userID)
+	mustNoErr(t, err, "GetRule")
+	if got.Name != "early" || got.SetCategory == nil || *got.SetCategory != category || !got.Enabled {
+		t.Fatalf("GetRule: stored rule does not match")
+	}
+	_, err = repo.GetRule(early.ID, uuid.New())
+	expectNotFound(t, err, "GetRule other user")
+
+	mustNoErr(t, repo.UpdateRule(early.ID, userID, map[string]interface{}{"enabled": false, "set_category": nil}), "UpdateRule")
+	got, err = repo.GetRule(early.ID, userID)
+	mustNoErr(t, err, "GetRule after update")
+	if got.Enabled || got.SetCategory != nil {
+		t.Fatalf("UpdateRule: expected the rule to be disabled with no category")
+	}
+	expectNotFound(t, repo.UpdateRule(uuid.New(), userID, map[string]interface{}{"enabled": true}), "UpdateRule unknown rule")
+
+	at := time.Now().UTC().Truncate(time.Second)
+	mustNoErr(t, repo.RecordRuleMatches(userID, map[uuid.UUID]int{late.ID: 3}, at), "RecordRuleMatches")
+	mustNoErr(t, repo.RecordRuleMatches(userID, map[uuid.UUID]int{late.ID: 2}, at), "RecordRuleMatches again")
+	got, err = repo.GetRule(late.ID, userID)
+	mustNoErr(t, err, "GetRule after matches")
+	if got.MatchCount != 5 || got.LastMatchedAt == nil || !got.LastMatchedAt.Equal(at) {
+		t.Fatalf("RecordRuleMatches: expected 5 matches at %s, got %d at %v", at, got.MatchCount, got.LastMatchedAt)
+	}
+
+	mustNoErr(t, repo.DeleteRule(late.ID, userID), "DeleteRule")
+	_, err = repo.GetRule(late.ID, userID)
+	expectNotFound(t, err, "GetRule after delete")
+	expectNotFound(t, repo.DeleteRule(late.ID, userID), "DeleteRule twice")
+}
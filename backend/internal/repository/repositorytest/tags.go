@@ -0,0 +1,65 @@
+package repositorytest
+
+import (
+	"testing"
+
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func testTags(t *testing.T, repo repository.FinanceRepositoryInterface, userID uuid.UUID) {
+	salary := newIncome(userID, "Salary", 4000, date(2024, 11, 25))
+	refund := newIncome(userID, "Refund", 120, date(2024, 11, 12))
+	refund.Tags = []string{"reimbursable"}
+	mustNoErr(t, repo.CreateIncome(salary), "CreateIncome salary")
+	mustNoErr(t, repo.CreateIncome(refund), "CreateIncome refund")
+
+	hotel := newExpense(userID, "travel", 300, date(2024, 11, 3), nil)
+	hotel.Tags = []string{"vacation-2026", "reimbursable"}
+	flight := newExpense(userID, "travel", 500, date(2024, 11, 8), nil)
+	flight.Tags = []string{"vacation-2026"}
+	lunch := newExpense(userID, "food", 20, date(2024, 11, 9), nil)
+	mustNoErr(t, repo.CreateExpense(hotel), "CreateExpense hotel")
+	mustNoErr(t, repo.CreateExpense(flight), "CreateExpense flight")
+	mustNoErr(t, repo.CreateExpense(lunch), "CreateExpense lunch")
+
+	// The stored tags must not share memory with the caller's slice
+	hotel.Tags[0] = "changed"
+	got, err := repo.GetExpense(hotel.ID, userID)
+	mustNoErr(t, err, "GetExpense")
+	if len(got.Tags) != 2 || got.Tags[0] != "vacation-2026" {
+		t.Fatalf("GetExpense: expected the stored tags, got %v", got.Tags)
+	}
+
+	all, err := repo.ListTaggedExpenses(userID, repository.TagFilter{Tags: []string{"vacation-2026", "reimbursable"}}, 0)
+	mustNoErr(t, err, "ListTaggedExpenses all")
+	if len(all) != 1 || all[0].ID != hotel.ID {
+		t.Fatalf("ListTaggedExpenses all: expected only the hotel, got %d", len(all))
+	}
+	anyTag, err := repo.ListTaggedExpenses(userID, repository.TagFilter{Tags: []string{"vacation-2026", "reimbursable"}, Any: true}, 0)
+	mustNoErr(t, err, "ListTaggedExpenses any")
+	if len(anyTag) != 2 || anyTag[0].ID != flight.ID {
+		t.Fatalf("ListTaggedExpenses any: expected flight then hotel, got %d", len(anyTag))
+	}
+	limited, _ := repo.ListTaggedExpenses(userID, repository.TagFilter{Tags: []string{"vacation-2026"}}, 1)
+	if len(limited) != 1 {
+		t.Fatalf("ListTaggedExpenses limit: got %d, want 1", len(limited))
+	}
+	incomes, err := repo.ListTaggedIncomes(userID, repository.TagFilter{Tags: []string{"reimbursable"}}, 0)
+	mustNoErr(t, err, "ListTaggedIncomes")
+	if len(incomes) != 1 || incomes[0].ID != refund.ID {
+		t.Fatalf("ListTaggedIncomes: expected only the refund, got %d", len(incomes))
+	}
+
+	mustNoErr(t, repo.UpdateExpense(lunch.ID, userID, 0, map[string]interface{}{"tags": []string{"reimbursable"}}), "UpdateExpense tags")
+
+	summary, err := repo.GetMonthlySummary(userID, 2024, 11)
+	mustNoErr(t, err, "GetMonthlySummary")
+	expectAmount(t, summary.TagSpending["vacation-2026"], 800, "vacation spending")
+	expectAmount(t, summary.TagSpending["reimbursable"], 320, "reimbursable spending")
+	expectAmount(t, summary.TagIncome["reimbursable"], 120, "reimbursable income")
+	if len(summary.TagIncome) != 1 {
+		t.Fatalf("GetMonthlySummary: expected untagged income to be left out, got %v", summary.TagIncome)
+	}
+}
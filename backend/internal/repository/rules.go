@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"time"
+
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RuleRepositoryInterface stores expense categorisation rules
+type RuleRepositoryInterface interface {
+	CreateRule(rule *models.ExpenseRule) error
+	GetRule(id, userID uuid.UUID) (*models.ExpenseRule, error)
+	// ListRules returns the user's rules in evaluation order: priority, then creation time
+	ListRules(userID uuid.UUID) ([]models.ExpenseRule, error)
+	UpdateRule(id, userID uuid.UUID, updates map[string]interface{}) error
+	DeleteRule(id, userID uuid.UUID) error
+	// RecordRuleMatches adds matches[ruleID] to each rule's match count and sets its last match time
+	RecordRuleMatches(userID uuid.UUID, matches map[uuid.UUID]int, at time.Time) error
+	// Transaction runs fn against a repository whose writes are committed together,
+	// or not at all when fn returns an error
+	Transaction(fn func(repo RuleRepositoryInterface) error) error
+}
+
+// RuleRepository handles database operations for expense rules
+type RuleRepository struct {
+	db *gorm.DB
+}
+
+// NewRuleRepository creates a new expense rule repository
+func NewRuleRepository(db *gorm.DB) *RuleRepository {
+	return &RuleRepository{db: db}
+}
+
+// Transaction runs fn in a database transaction; GORM turns nested calls into savepoints
+func (r *RuleRepository) Transaction(fn func(repo RuleRepositoryInterface) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&RuleRepository{db: tx})
+	})
+}
+
+// conn returns the connection, an open transaction inside Transaction
+func (r *RuleRepository) conn() *gorm.DB {
+	return r.db
+}
+
+func (r *RuleRepository) CreateRule(rule *models.ExpenseRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *RuleRepository) GetRule(id, userID uuid.UUID) (*models.ExpenseRule, error) {
+	var rule models.ExpenseRule
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *RuleRepository) ListRules(userID uuid.UUID) ([]models.ExpenseRule, error) {
+	var rules []models.ExpenseRule
+	if err := r.db.Where("user_id = ?", userID).Order("priority ASC, created_at ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *RuleRepository) UpdateRule(id, userID uuid.UUID, updates map[string]interface{}) error {
+	tx := r.db.Model(&models.ExpenseRule{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *RuleRepository) DeleteRule(id, userID uuid.UUID) error {
+	tx := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.ExpenseRule{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *RuleRepository) RecordRuleMatches(userID uuid.UUID, matches map[uuid.UUID]int, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for id, count := range matches {
+			err := tx.Model(&models.ExpenseRule{}).
+				Where("id = ? AND user_id = ?", id, userID).
+				UpdateColumns(map[string]interface{}{
+					"match_count":     gorm.Expr("match_count + ?", count),
+					"last_matched_at": at,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
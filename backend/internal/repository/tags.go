@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// TagFilter selects incomes or expenses by their tags
+type TagFilter struct {
+	Tags []string
+	// Any matches rows carrying at least one of Tags instead of all of them
+	Any bool
+}
+
+// TagRepositoryInterface lists incomes and expenses by tag
+type TagRepositoryInterface interface {
+	// ListTaggedIncomes returns matching incomes, newest first; a limit of 0 returns all
+	ListTaggedIncomes(userID uuid.UUID, filter TagFilter, limit int) ([]models.Income, error)
+	// ListTaggedExpenses returns matching expenses, newest first; a limit of 0 returns all
+	ListTaggedExpenses(userID uuid.UUID, filter TagFilter, limit int) ([]models.Expense, error)
+}
+
+func (r *FinanceRepository) ListTaggedIncomes(userID uuid.UUID, filter TagFilter, limit int) ([]models.Income, error) {
+	var items []models.Income
+	q := tagQuery(r.db.Where("user_id = ?", userID), filter).Order("received_at DESC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if err := q.Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *FinanceRepository) ListTaggedExpenses(userID uuid.UUID, filter TagFilter, limit int) ([]models.Expense, error) {
+	var items []models.Expense
+	q := tagQuery(r.db.Where("user_id = ?", userID), filter).Order("spent_at DESC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if err := q.Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// tagQuery restricts q to rows matching filter using the GIN-indexed array operators
+func tagQuery(q *gorm.DB, filter TagFilter) *gorm.DB {
+	if len(filter.Tags) == 0 {
+		return q
+	}
+	if filter.Any {
+		return q.Where("tags && ?::text[]", pq.StringArray(filter.Tags))
+	}
+	return q.Where("tags @> ?::text[]", pq.StringArray(filter.Tags))
+}
@@ -0,0 +1,148 @@
+package seed
+
+import "time"
+
+// expenseCategory describes how spending in one category is generated
+type expenseCategory struct {
+	Name         string
+	MonthlyBase  float64 // typical monthly spend before seasonality
+	Transactions [2]int  // min and max transactions per month
+	Merchants    []string
+	// Seasonality multiplies MonthlyBase per calendar month (index 0 = January)
+	Seasonality [12]float64
+}
+
+var flat = [12]float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+
+var expenseCategories = []expenseCategory{
+	{
+		Name: "Rent", MonthlyBase: 1450, Transactions: [2]int{1, 1},
+		Merchants:   []string{"Monthly rent"},
+		Seasonality: flat,
+	},
+	{
+		Name: "Groceries", MonthlyBase: 520, Transactions: [2]int{6, 12},
+		Merchants:   []string{"WHOLEFDS MKT #1023", "Trader Joe's", "ALDI 4471", "Safeway Store 0912", "Costco Whse #0441"},
+		Seasonality: [12]float64{0.95, 0.92, 0.97, 1, 1, 1.02, 1.03, 1.02, 0.98, 1.02, 1.12, 1.22},
+	},
+	{
+		Name: "Utilities", MonthlyBase: 210, Transactions: [2]int{2, 3},
+		Merchants:   []string{"City Power & Light", "Metro Water Dept", "Comcast Xfinity", "National Gas Co"},
+		Seasonality: [12]float64{1.45, 1.4, 1.2, 0.95, 0.85, 0.95, 1.15, 1.2, 0.95, 0.9, 1.1, 1.35},
+	},
+	{
+		Name: "Transport", MonthlyBase: 240, Transactions: [2]int{4, 10},
+		Merchants:   []string{"UBER *TRIP", "Lyft Ride", "Shell Oil 5744", "Chevron 0093", "Metro Transit Pass"},
+		Seasonality: [12]float64{0.9, 0.9, 1, 1, 1.05, 1.1, 1.15, 1.15, 1, 1, 0.95, 1},
+	},
+	{
+		Name: "Dining", MonthlyBase: 310, Transactions: [2]int{4, 14},
+		Merchants:   []string{"STARBUCKS STORE 2201", "Chipotle 1932", "DoorDash*Thai Palace", "Local Bistro", "SQ *Corner Cafe"},
+		Seasonality: [12]float64{0.85, 0.95, 0.95, 1, 1.05, 1.1, 1.15, 1.1, 1, 1, 1, 1.25},
+	},
+	{
+		Name: "Shopping", MonthlyBase: 260, Transactions: [2]int{1, 6},
+		Merchants:   []string{"AMZN Mktp US*2K3", "Amazon.com", "Target 00021", "IKEA US", "Best Buy 00412"},
+		Seasonality: [12]float64{0.7, 0.75, 0.9, 0.9, 0.95, 0.95, 1, 1.1, 1, 1, 1.45, 1.9},
+	},
+	{
+		Name: "Health", MonthlyBase: 120, Transactions: [2]int{0, 3},
+		Merchants:   []string{"CVS Pharmacy", "Walgreens #3345", "City Dental Care", "PureGym Membership"},
+		Seasonality: [12]float64{1.3, 1.1, 1, 1, 0.95, 0.9, 0.85, 0.9, 1, 1, 1, 1},
+	},
+	{
+		Name: "Entertainment", MonthlyBase: 95, Transactions: [2]int{2, 5},
+		Merchants:   []string{"NETFLIX.COM", "Spotify USA", "AMC Theatres", "Steam Purchase"},
+		Seasonality: [12]float64{0.9, 0.9, 0.95, 1, 1, 1.05, 1.15, 1.1, 0.95, 1, 1, 1.2},
+	},
+	{
+		Name: "Travel", MonthlyBase: 180, Transactions: [2]int{0, 3},
+		Merchants:   []string{"DELTA AIR 0062", "Airbnb * HMQ2", "Marriott Hotels", "Expedia Booking"},
+		Seasonality: [12]float64{0.3, 0.4, 0.8, 0.9, 1.1, 2.2, 2.6, 2.0, 0.8, 0.6, 0.7, 1.6},
+	},
+	{
+		Name: "Gifts", MonthlyBase: 60, Transactions: [2]int{0, 2},
+		Merchants:   []string{"Etsy Purchase", "Hallmark", "Local Florist"},
+		Seasonality: [12]float64{0.3, 1.4, 0.6, 0.6, 1.2, 0.7, 0.6, 0.6, 0.6, 0.8, 1.3, 4},
+	},
+}
+
+// goalPlan is a template for a main goal and its sub-goals
+type goalPlan struct {
+	Name        string
+	Category    string
+	Description string
+	Target      float64
+	Months      int // months from start until the target date
+	Subgoals    []subgoalPlan
+}
+
+type subgoalPlan struct {
+	Name   string
+	Share  float64 // fraction of the parent's target
+	Months int
+}
+
+var goalPlans = []goalPlan{
+	{
+		Name: "Emergency Fund", Category: "Emergency", Description: "Six months of essential expenses",
+		Target: 15000, Months: 24,
+	},
+	{
+		Name: "Japan Trip", Category: "Travel", Description: "Two weeks in Tokyo and Kyoto",
+		Target: 6500, Months: 14,
+		Subgoals: []subgoalPlan{
+			{Name: "Flights", Share: 0.35, Months: 8},
+			{Name: "Hotels", Share: 0.4, Months: 12},
+			{Name: "Rail pass and spending money", Share: 0.25, Months: 14},
+		},
+	},
+	{
+		Name: "New Laptop", Category: "Technology", Description: "Replace the old work laptop",
+		Target: 2200, Months: 8,
+	},
+	{
+		Name: "Home Down Payment", Category: "Investment", Description: "20% down payment",
+		Target: 60000, Months: 60,
+		Subgoals: []subgoalPlan{
+			{Name: "Deposit", Share: 0.8, Months: 48},
+			{Name: "Closing costs", Share: 0.12, Months: 54},
+			{Name: "Moving and furniture", Share: 0.08, Months: 60},
+		},
+	},
+	{
+		Name: "Wedding", Category: "Lifestyle", Description: "Ceremony and reception",
+		Target: 25000, Months: 30,
+		Subgoals: []subgoalPlan{
+			{Name: "Venue", Share: 0.45, Months: 18},
+			{Name: "Catering", Share: 0.3, Months: 26},
+			{Name: "Photography", Share: 0.1, Months: 26},
+			{Name: "Attire", Share: 0.15, Months: 28},
+		},
+	},
+	{
+		Name: "Language Course", Category: "Education", Description: "Evening classes for a year",
+		Target: 1800, Months: 10,
+	},
+}
+
+var noteTopics = []struct {
+	Title    string
+	Category string
+	Tags     []string
+	Body     string
+}{
+	{"Monthly budget review", "finance", []string{"budget", "review"}, "Groceries ran over again. Try meal planning on Sundays and one big shop instead of several small ones."},
+	{"Tax documents checklist", "finance", []string{"tax", "checklist"}, "W-2, 1099-INT from the savings account, charitable receipts, HSA statement."},
+	{"Insurance renewal", "personal", []string{"insurance"}, "Car insurance renews next month. Get at least two comparison quotes."},
+	{"Subscriptions audit", "finance", []string{"subscriptions", "savings"}, "Cancel the unused streaming service and the second cloud storage plan."},
+	{"Holiday gift ideas", "personal", []string{"gifts", "holidays"}, "Keep the total under the gifts budget. Handmade where possible."},
+	{"Investment plan", "finance", []string{"investing", "retirement"}, "Increase retirement contributions by 1% after the next raise."},
+	{"Trip packing list", "travel", []string{"travel", "checklist"}, "Passport, adapters, rail pass voucher, travel insurance documents."},
+	{"Debt payoff notes", "finance", []string{"debt"}, "Pay the highest interest card first and keep minimums on the rest."},
+}
+
+// monthStart returns the first day of t's month in UTC
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,276 @@
+package seed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Load scenarios, each one kind of request sent to the API
+const (
+	ScenarioListExpenses  = "list_expenses"
+	ScenarioCreateExpense = "create_expense"
+	ScenarioSummary       = "summary"
+	ScenarioGoals         = "goals"
+	ScenarioNotes         = "notes"
+)
+
+// DefaultMix weighs the scenarios like a user browsing the app: mostly reads, some writes
+var DefaultMix = map[string]int{
+	ScenarioListExpenses:  35,
+	ScenarioSummary:       20,
+	ScenarioGoals:         15,
+	ScenarioNotes:         10,
+	ScenarioCreateExpense: 20,
+}
+
+// LoadTag marks the expenses a load run creates so they can be told apart from demo data
+const LoadTag = "synthetic-load"
+
+// LoadConfig controls a synthetic load run against a running API
+type LoadConfig struct {
+	BaseURL  string         // API root, e.g. http://localhost:8080
+	Rate     float64        // requests started per second, whether or not earlier ones finished
+	Duration time.Duration  // how long to keep starting requests
+	Mix      map[string]int // relative weight per scenario; DefaultMix when empty
+	Seed     int64          // same seed picks the same sequence of requests
+	Client   *http.Client   // http.DefaultClient when nil
+}
+
+// LatencySummary describes the requests of one scenario, or of the whole run
+type LatencySummary struct {
+	Requests int
+	Errors   int // transport failures and responses with a status of 400 or more
+	Min      time.Duration
+	Mean     time.Duration
+	P50      time.Duration
+	P90      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+}
+
+// LoadReport is the outcome of a load run
+type LoadReport struct {
+	Elapsed   time.Duration
+	Total     LatencySummary
+	Scenarios map[string]LatencySummary
+}
+
+// ParseMix reads a mix written as name=weight pairs separated by commas,
+// e.g. "list_expenses=60,create_expense=40"
+func ParseMix(s string) (map[string]int, error) {
+	mix := map[string]int{}
+	for _, part := range strings.Split(s, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("mix entry %q is not name=weight", part)
+		}
+		w, err := strconv.Atoi(weight)
+		if err != nil {
+			return nil, fmt.Errorf("mix entry %q: invalid weight", part)
+		}
+		mix[strings.TrimSpace(name)] = w
+	}
+	return mix, nil
+}
+
+// loadRequest is one request picked for the run
+type loadRequest struct {
+	scenario string
+	method   string
+	path     string
+	body     []byte
+}
+
+// loadSample is the outcome of one request
+type loadSample struct {
+	scenario string
+	latency  time.Duration
+	failed   bool
+}
+
+// RunLoad sends requests at cfg.Rate for cfg.Duration, or until ctx is cancelled, and
+// summarises their latencies once the requests in flight have finished
+func RunLoad(ctx context.Context, cfg LoadConfig) (*LoadReport, error) {
+	if cfg.Rate <= 0 || cfg.Duration <= 0 {
+		return nil, fmt.Errorf("rate and duration must be positive")
+	}
+	mix := cfg.Mix
+	if len(mix) == 0 {
+		mix = DefaultMix
+	}
+	names, err := mixNames(mix)
+	if err != nil {
+		return nil, err
+	}
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	base := strings.TrimRight(cfg.BaseURL, "/")
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.Rate))
+	defer ticker.Stop()
+
+	var (
+		mu      sync.Mutex
+		samples []loadSample
+		wg      sync.WaitGroup
+	)
+	started := time.Now()
+	for {
+		// Requests are picked here, in one goroutine, so the sequence only depends on the seed
+		req := newLoadRequest(rng, pickScenario(rng, names, mix), time.Now().UTC())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sample := send(client, base, req)
+			mu.Lock()
+			samples = append(samples, sample)
+			mu.Unlock()
+		}()
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return summarise(samples, time.Since(started)), nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// mixNames validates mix and returns its scenario names in a stable order
+func mixNames(mix map[string]int) ([]string, error) {
+	names := make([]string, 0, len(mix))
+	total := 0
+	for name, weight := range mix {
+		if _, ok := DefaultMix[name]; !ok {
+			return nil, fmt.Errorf("unknown scenario %q", name)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("scenario %q has a negative weight", name)
+		}
+		total += weight
+		names = append(names, name)
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("the mix needs at least one scenario with a positive weight")
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func pickScenario(rng *rand.Rand, names []string, mix map[string]int) string {
+	total := 0
+	for _, name := range names {
+		total += mix[name]
+	}
+	n := rng.Intn(total)
+	for _, name := range names {
+		if n < mix[name] {
+			return name
+		}
+		n -= mix[name]
+	}
+	return names[len(names)-1]
+}
+
+func newLoadRequest(rng *rand.Rand, scenario string, now time.Time) loadRequest {
+	switch scenario {
+	case ScenarioCreateExpense:
+		category := expenseCategories[rng.Intn(len(expenseCategories))]
+		body, _ := json.Marshal(map[string]interface{}{
+			"category":    category.Name,
+			"description": category.Merchants[rng.Intn(len(category.Merchants))],
+			"amount":      cents(5 + rng.Float64()*145),
+			"spent_at":    now,
+			"tags":        []string{LoadTag},
+		})
+		return loadRequest{scenario: scenario, method: http.MethodPost, path: "/api/finance/expenses", body: body}
+	case ScenarioSummary:
+		path := fmt.Sprintf("/api/finance/summary?year=%d&month=%d", now.Year(), int(now.Month()))
+		return loadRequest{scenario: scenario, method: http.MethodGet, path: path}
+	case ScenarioGoals:
+		return loadRequest{scenario: scenario, method: http.MethodGet, path: "/api/finance/goals"}
+	case ScenarioNotes:
+		return loadRequest{scenario: scenario, method: http.MethodGet, path: "/api/notes"}
+	}
+	return loadRequest{scenario: ScenarioListExpenses, method: http.MethodGet, path: "/api/finance/expenses"}
+}
+
+func send(client *http.Client, base string, req loadRequest) loadSample {
+	sample := loadSample{scenario: req.scenario}
+	httpReq, err := http.NewRequest(req.method, base+req.path, bytes.NewReader(req.body))
+	if err != nil {
+		sample.failed = true
+		return sample
+	}
+	if req.body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	if err == nil {
+		// Reading the body keeps the connection reusable and counts it in the latency
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	sample.latency = time.Since(start)
+	sample.failed = err != nil || resp.StatusCode >= http.StatusBadRequest
+	return sample
+}
+
+func summarise(samples []loadSample, elapsed time.Duration) *LoadReport {
+	byScenario := map[string][]loadSample{}
+	for _, s := range samples {
+		byScenario[s.scenario] = append(byScenario[s.scenario], s)
+	}
+	report := &LoadReport{Elapsed: elapsed, Total: latencySummary(samples), Scenarios: map[string]LatencySummary{}}
+	for name, s := range byScenario {
+		report.Scenarios[name] = latencySummary(s)
+	}
+	return report
+}
+
+func latencySummary(samples []loadSample) LatencySummary {
+	summary := LatencySummary{Requests: len(samples)}
+	if len(samples) == 0 {
+		return summary
+	}
+	latencies := make([]time.Duration, len(samples))
+	var sum time.Duration
+	for i, s := range samples {
+		latencies[i] = s.latency
+		sum += s.latency
+		if s.failed {
+			summary.Errors++
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	// Nearest-rank percentiles
+	percentile := func(p float64) time.Duration {
+		rank := int(math.Ceil(p*float64(len(latencies)))) - 1
+		return latencies[max(rank, 0)]
+	}
+	summary.Min = latencies[0]
+	summary.Max = latencies[len(latencies)-1]
+	summary.Mean = sum / time.Duration(len(latencies))
+	summary.P50 = percentile(0.50)
+	summary.P90 = percentile(0.90)
+	summary.P99 = percentile(0.99)
+	return summary
+}
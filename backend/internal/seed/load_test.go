@@ -0,0 +1,67 @@
+package seed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunLoadFollowsTheMixAndSummarisesLatencies(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths = map[string]int{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.Method+" "+r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/api/notes" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	report, err := RunLoad(context.Background(), LoadConfig{
+		BaseURL:  server.URL,
+		Rate:     400,
+		Duration: 250 * time.Millisecond,
+		Mix:      map[string]int{ScenarioCreateExpense: 1, ScenarioNotes: 1, ScenarioGoals: 0},
+		Seed:     7,
+	})
+	if err != nil {
+		t.Fatalf("RunLoad: %v", err)
+	}
+
+	if report.Total.Requests < 10 || report.Total.Requests != paths["POST /api/finance/expenses"]+paths["GET /api/notes"] {
+		t.Fatalf("expected every request to reach the server, got %d for %v", report.Total.Requests, paths)
+	}
+	if paths["GET /api/finance/goals"] != 0 {
+		t.Fatal("scenarios weighted 0 must not be sent")
+	}
+	notes := report.Scenarios[ScenarioNotes]
+	if notes.Errors != notes.Requests || report.Scenarios[ScenarioCreateExpense].Errors != 0 || report.Total.Errors != notes.Requests {
+		t.Fatalf("expected only the failing scenario to count errors, got %+v", report.Scenarios)
+	}
+	total := report.Total
+	if !(total.Min <= total.P50 && total.P50 <= total.P90 && total.P90 <= total.P99 && total.P99 <= total.Max) {
+		t.Fatalf("percentiles out of order: %+v", total)
+	}
+}
+
+func TestRunLoadRejectsUnknownScenarios(t *testing.T) {
+	if _, err := RunLoad(context.Background(), LoadConfig{Rate: 1, Duration: time.Second, Mix: map[string]int{"bulk_delete": 1}}); err == nil {
+		t.Fatal("expected an unknown scenario to be rejected")
+	}
+	if _, err := ParseMix("list_expenses=3,summary"); err == nil {
+		t.Fatal("expected an entry without a weight to be rejected")
+	}
+	mix, err := ParseMix("list_expenses=3, summary=1")
+	if err != nil || mix[ScenarioListExpenses] != 3 || mix[ScenarioSummary] != 1 {
+		t.Fatalf("ParseMix: got %v, %v", mix, err)
+	}
+}
@@ -0,0 +1,398 @@
+// Package seed generates realistic demo data through the repository interfaces,
+// so the data always matches the real schema, and sends synthetic load to a
+// running API over HTTP.
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// DefaultUserID is the user the HTTP handlers currently act as
+var DefaultUserID = uuid.MustParse("00000000-0000-0000-0000-000000000000")
+
+// Config controls how much data is generated
+type Config struct {
+	Seed               int64     // same seed and End produce identical data
+	Users              int       // number of users to generate
+	Years              int       // years of history per user
+	End                time.Time // history covers the months before End's month
+	NotesPerUser       int       // notes to generate per user
+	IncludeDefaultUser bool      // make the first user DefaultUserID
+	Workers            int       // users seeded concurrently; output does not depend on it
+}
+
+// Stats counts the rows written
+type Stats struct {
+	Users         int
+	Incomes       int
+	Expenses      int
+	Categories    int
+	Goals         int
+	Contributions int
+	Notes         int
+}
+
+func (s *Stats) add(o Stats) {
+	s.Users += o.Users
+	s.Incomes += o.Incomes
+	s.Expenses += o.Expenses
+	s.Categories += o.Categories
+	s.Goals += o.Goals
+	s.Contributions += o.Contributions
+	s.Notes += o.Notes
+}
+
+// Generator writes generated data through the repositories
+type Generator struct {
+	finance repository.FinanceRepositoryInterface
+	notes   repository.NotesRepositoryInterface
+	cfg     Config
+	rng     *rand.Rand
+}
+
+// New creates a generator; all randomness, including IDs, comes from cfg.Seed
+func New(finance repository.FinanceRepositoryInterface, notes repository.NotesRepositoryInterface, cfg Config) *Generator {
+	return &Generator{finance: finance, notes: notes, cfg: cfg}
+}
+
+// Run generates data for every configured user. progress, if not nil, is called
+// after each user with the running totals.
+func (g *Generator) Run(progress func(stats Stats)) (*Stats, error) {
+	if g.cfg.Users < 1 || g.cfg.Years < 1 {
+		return nil, fmt.Errorf("users and years must be at least 1")
+	}
+	workers := g.cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	end := monthStart(g.cfg.End)
+	start := end.AddDate(-g.cfg.Years, 0, 0)
+
+	var (
+		mu       sync.Mutex
+		total    Stats
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	users := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range users {
+				// Each user has its own source so results do not depend on scheduling
+				ug := &Generator{finance: g.finance, notes: g.notes, cfg: g.cfg, rng: rand.New(rand.NewSource(g.cfg.Seed*1_000_003 + int64(i)))}
+				userID := ug.newID()
+				if i == 0 && g.cfg.IncludeDefaultUser {
+					userID = DefaultUserID
+				}
+				stats := Stats{Users: 1}
+				err := ug.seedUser(userID, start, end, &stats)
+
+				mu.Lock()
+				total.add(stats)
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("user %s: %w", userID, err)
+				}
+				if progress != nil {
+					progress(total)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < g.cfg.Users; i++ {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		users <- i
+	}
+	close(users)
+	wg.Wait()
+
+	return &total, firstErr
+}
+
+func (g *Generator) seedUser(userID uuid.UUID, start, end time.Time, stats *Stats) error {
+	// Per-user scale so households differ in income and spending
+	salary := g.between(3800, 7500)
+	spendScale := g.between(0.7, 1.3)
+
+	categoryIDs := make(map[string]uuid.UUID, len(expenseCategories))
+	for _, cat := range expenseCategories {
+		category := &models.Category{
+			ID:        g.newID(),
+			UserID:    userID,
+			Name:      cat.Name,
+			CreatedAt: start,
+			UpdatedAt: start,
+		}
+		if err := g.finance.CreateCategory(category); err != nil {
+			return err
+		}
+		categoryIDs[cat.Name] = category.ID
+		stats.Categories++
+	}
+
+	goals, err := g.seedGoals(userID, start, end, stats)
+	if err != nil {
+		return err
+	}
+
+	for month := start; month.Before(end); month = month.AddDate(0, 1, 0) {
+		if month.Month() == time.January && month.After(start) {
+			salary *= 1 + g.between(0.01, 0.05)
+		}
+		if err := g.seedIncomes(userID, month, salary, stats); err != nil {
+			return err
+		}
+		if err := g.seedExpenses(userID, month, spendScale, categoryIDs, goals, stats); err != nil {
+			return err
+		}
+	}
+
+	return g.seedNotes(userID, start, end, stats)
+}
+
+func (g *Generator) seedIncomes(userID uuid.UUID, month time.Time, salary float64, stats *Stats) error {
+	incomes := []*models.Income{{Source: "Salary", Amount: salary, ReceivedAt: month.AddDate(0, 0, 24)}}
+	if month.Month() == time.December && g.rng.Float64() < 0.6 {
+		incomes = append(incomes, &models.Income{Source: "Annual bonus", Amount: salary * g.between(0.5, 1.5), ReceivedAt: month.AddDate(0, 0, 14)})
+	}
+	for n := g.rng.Intn(3); n > 0; n-- {
+		incomes = append(incomes, &models.Income{Source: "Freelance", Amount: g.between(150, 900), ReceivedAt: g.dayIn(month)})
+	}
+
+	for _, income := range incomes {
+		income.ID = g.newID()
+		income.UserID = userID
+		income.Amount = cents(income.Amount)
+		income.CreatedAt = income.ReceivedAt
+		if err := g.finance.CreateIncome(income); err != nil {
+			return err
+		}
+		stats.Incomes++
+	}
+	return nil
+}
+
+func (g *Generator) seedExpenses(userID uuid.UUID, month time.Time, scale float64, categoryIDs map[string]uuid.UUID, goals map[string]*models.Goal, stats *Stats) error {
+	for _, cat := range expenseCategories {
+		count := cat.Transactions[0] + g.rng.Intn(cat.Transactions[1]-cat.Transactions[0]+1)
+		if count == 0 {
+			continue
+		}
+		total := cat.MonthlyBase * cat.Seasonality[month.Month()-1] * scale * g.between(0.85, 1.15)
+
+		categoryID := categoryIDs[cat.Name]
+
+		// Split the monthly total into uneven transactions
+		weights := make([]float64, count)
+		var sum float64
+		for i := range weights {
+			weights[i] = g.between(0.3, 1.7)
+			sum += weights[i]
+		}
+
+		for i := 0; i < count; i++ {
+			spentAt := g.dayIn(month)
+			if cat.Name == "Rent" {
+				spentAt = month
+			}
+			expense := &models.Expense{
+				ID:          g.newID(),
+				UserID:      userID,
+				Category:    cat.Name,
+				CategoryID:  &categoryID,
+				Description: cat.Merchants[g.rng.Intn(len(cat.Merchants))],
+				Amount:      cents(total * weights[i] / sum),
+				SpentAt:     spentAt,
+				CreatedAt:   spentAt,
+			}
+			// Some travel spending is paid directly from the trip goal
+			if trip, ok := goals["Japan Trip"]; ok && cat.Name == "Travel" && !spentAt.Before(trip.CreatedAt) && g.rng.Float64() < 0.3 {
+				goalID := trip.ID
+				expense.GoalID = &goalID
+			}
+			if err := g.finance.CreateExpense(expense); err != nil {
+				return err
+			}
+			stats.Expenses++
+		}
+	}
+	return nil
+}
+
+// seedGoals creates a random selection of goal hierarchies with monthly contributions
+// and returns the main goals by name
+func (g *Generator) seedGoals(userID uuid.UUID, start, end time.Time, stats *Stats) (map[string]*models.Goal, error) {
+	months := monthsBetween(start, end)
+	mainGoals := map[string]*models.Goal{}
+	categories, err := g.finance.ListGoalCategories(userID)
+	if err != nil {
+		return nil, err
+	}
+	categoryIDs := make(map[string]uuid.UUID, len(categories))
+	for _, c := range categories {
+		categoryIDs[c.Name] = c.ID
+	}
+
+	for _, idx := range g.rng.Perm(len(goalPlans))[:3+g.rng.Intn(len(goalPlans)-2)] {
+		plan := goalPlans[idx]
+		created := start.AddDate(0, g.rng.Intn(months/2+1), g.rng.Intn(28))
+		main := g.newGoal(userID, plan.Name, plan.Description, plan.Category, plan.Target, created, plan.Months, nil)
+		main.CategoryID = linkedCategory(categoryIDs, plan.Category)
+		if err := g.finance.CreateGoal(main); err != nil {
+			return nil, err
+		}
+		stats.Goals++
+		mainGoals[plan.Name] = main
+
+		if len(plan.Subgoals) == 0 {
+			if err := g.seedContributions(userID, main, end, stats); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, sub := range plan.Subgoals {
+			parentID := main.ID
+			subgoal := g.newGoal(userID, sub.Name, "", plan.Category, plan.Target*sub.Share, created, sub.Months, &parentID)
+			subgoal.CategoryID = linkedCategory(categoryIDs, plan.Category)
+			if err := g.finance.CreateGoal(subgoal); err != nil {
+				return nil, err
+			}
+			stats.Goals++
+			if err := g.seedContributions(userID, subgoal, end, stats); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return mainGoals, nil
+}
+
+func (g *Generator) newGoal(userID uuid.UUID, name, description, category string, target float64, created time.Time, months int, parent *uuid.UUID) *models.Goal {
+	targetDate := monthStart(created).AddDate(0, months, 0)
+	return &models.Goal{
+		ID:           g.newID(),
+		UserID:       userID,
+		Name:         name,
+		Description:  description,
+		Category:     category,
+		TargetAmount: cents(target),
+		TargetDate:   &targetDate,
+		ParentGoalID: parent,
+		IsMainGoal:   parent == nil,
+		CreatedAt:    created,
+		UpdatedAt:    created,
+	}
+}
+
+// linkedCategory returns the ID of the built-in goal category named name, if there is one
+func linkedCategory(categoryIDs map[string]uuid.UUID, name string) *uuid.UUID {
+	id, ok := categoryIDs[name]
+	if !ok {
+		return nil
+	}
+	return &id
+}
+
+// seedContributions saves towards the goal monthly, sometimes skipping a month,
+// until the target is reached or the history ends
+func (g *Generator) seedContributions(userID uuid.UUID, goal *models.Goal, end time.Time, stats *Stats) error {
+	months := monthsBetween(goal.CreatedAt, *goal.TargetDate)
+	if months < 1 {
+		months = 1
+	}
+	monthly := goal.TargetAmount / float64(months)
+	saved := 0.0
+
+	for month := monthStart(goal.CreatedAt).AddDate(0, 1, 0); month.Before(end) && saved < goal.TargetAmount; month = month.AddDate(0, 1, 0) {
+		if g.rng.Float64() < 0.15 {
+			continue
+		}
+		amount := cents(math.Min(monthly*g.between(0.6, 1.4), goal.TargetAmount-saved))
+		if amount <= 0 {
+			break
+		}
+		at := g.dayIn(month)
+		if err := g.finance.CreateGoalContribution(&models.GoalContribution{
+			ID:            g.newID(),
+			UserID:        userID,
+			GoalID:        goal.ID,
+			Amount:        amount,
+			ContributedAt: at,
+			CreatedAt:     at,
+		}); err != nil {
+			return err
+		}
+		saved += amount
+		stats.Contributions++
+	}
+	return nil
+}
+
+func (g *Generator) seedNotes(userID uuid.UUID, start, end time.Time, stats *Stats) error {
+	span := end.Sub(start)
+	for i := 0; i < g.cfg.NotesPerUser; i++ {
+		topic := noteTopics[g.rng.Intn(len(noteTopics))]
+		created := start.Add(time.Duration(g.rng.Int63n(int64(span))))
+		note := &models.Note{
+			ID:         g.newID(),
+			UserID:     userID,
+			Title:      fmt.Sprintf("%s (%s)", topic.Title, created.Format("Jan 2006")),
+			Content:    topic.Body,
+			Category:   topic.Category,
+			Tags:       append([]string(nil), topic.Tags...),
+			IsFavorite: g.rng.Float64() < 0.2,
+			IsArchived: created.Before(end.AddDate(-1, 0, 0)) && g.rng.Float64() < 0.3,
+			CreatedAt:  created,
+			UpdatedAt:  created,
+		}
+		if err := g.notes.CreateNote(note); err != nil {
+			return err
+		}
+		stats.Notes++
+	}
+	return nil
+}
+
+// newID draws a UUID from the seeded source so IDs are reproducible
+func (g *Generator) newID() uuid.UUID {
+	id, err := uuid.NewRandomFromReader(g.rng)
+	if err != nil {
+		panic(err) // rand.Rand.Read never fails
+	}
+	return id
+}
+
+func (g *Generator) between(min, max float64) float64 {
+	return min + g.rng.Float64()*(max-min)
+}
+
+// dayIn returns a random day in month
+func (g *Generator) dayIn(month time.Time) time.Time {
+	days := month.AddDate(0, 1, -1).Day()
+	return month.AddDate(0, 0, g.rng.Intn(days))
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+func cents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,83 @@
+package seed
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+)
+
+func runSeed(t *testing.T, seedValue int64, workers int) (*repository.InMemoryFinanceRepository, *Stats) {
+	t.Helper()
+	finance := repository.NewInMemoryFinanceRepository()
+	stats, err := New(finance, repository.NewInMemoryNotesRepository(), Config{
+		Seed:               seedValue,
+		Users:              3,
+		Years:              1,
+		End:                time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotesPerUser:       2,
+		IncludeDefaultUser: true,
+		Workers:            workers,
+	}).Run(nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return finance, stats
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	first, firstStats := runSeed(t, 42, 1)
+	second, secondStats := runSeed(t, 42, 3)
+
+	if *firstStats != *secondStats {
+		t.Fatalf("stats differ between runs: %+v vs %+v", firstStats, secondStats)
+	}
+	if firstStats.Users != 3 || firstStats.Incomes == 0 || firstStats.Expenses == 0 || firstStats.Goals == 0 {
+		t.Fatalf("expected data for every entity, got %+v", firstStats)
+	}
+
+	a, _ := first.ListExpenses(DefaultUserID, 0)
+	b, _ := second.ListExpenses(DefaultUserID, 0)
+	if len(a) == 0 || !reflect.DeepEqual(expenseIDs(a), expenseIDs(b)) {
+		t.Fatalf("same seed should produce the same expenses for the default user")
+	}
+
+	_, otherStats := runSeed(t, 43, 1)
+	if *otherStats == *firstStats {
+		t.Fatalf("different seeds should produce different data")
+	}
+}
+
+func TestRunStaysWithinHistory(t *testing.T) {
+	finance, _ := runSeed(t, 7, 2)
+	end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(-1, 0, 0)
+
+	expenses, _ := finance.ListExpenses(DefaultUserID, 0)
+	for _, e := range expenses {
+		if e.SpentAt.Before(start) || !e.SpentAt.Before(end) {
+			t.Fatalf("expense dated %s outside [%s, %s)", e.SpentAt, start, end)
+		}
+	}
+
+	summary, err := finance.GetMonthlySummary(DefaultUserID, 2024, 12)
+	if err != nil {
+		t.Fatalf("GetMonthlySummary: %v", err)
+	}
+	if summary.TotalIncome <= 0 || summary.CategoryBreakdown["Rent"] <= 0 {
+		t.Fatalf("expected salary and rent in every month, got %+v", summary)
+	}
+}
+
+// expenseIDs returns sorted IDs, since expenses on the same day have no fixed order
+func expenseIDs(expenses []models.Expense) []string {
+	ids := make([]string, len(expenses))
+	for i, e := range expenses {
+		ids[i] = e.ID.String()
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// allocationNode is a goal in the surplus allocation waterfall. Amounts are in cents
+// so that proportional shares add up to exactly what was allocated.
+type allocationNode struct {
+	goal     models.Goal
+	need     int64
+	amount   int64
+	children []*allocationNode
+}
+
+// PreviewSurplusAllocation shows how a month's surplus would be allocated across goals without writing anything
+func (s *FinanceService) PreviewSurplusAllocation(userID uuid.UUID, req *request.AllocateSurplusRequest, now time.Time) (*response.SurplusAllocationResponse, error) {
+	year, month := allocationMonth(req, now)
+	resp, _, err := s.planSurplusAllocation(userID, year, month, req.Amount)
+	return resp, err
+}
+
+// CommitSurplusAllocation allocates a month's surplus and writes a contribution for every
+// funded goal in one transaction. The plan is worked out again inside the transaction,
+// after taking the user's allocation lock so concurrent commits run one at a time; as
+// the contributions reduce both the surplus and the goals' monthly needs, committing
+// the same month twice only allocates what was left over.
+func (s *FinanceService) CommitSurplusAllocation(userID uuid.UUID, req *request.AllocateSurplusRequest, now time.Time) (*response.SurplusAllocationResponse, error) {
+	year, month := allocationMonth(req, now)
+	// Contributions are dated at the month's end, or today while the month is running
+	at := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC)
+	if today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC); today.Before(at) {
+		at = today
+	}
+
+	var resp *response.SurplusAllocationResponse
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.LockAllocations(userID); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to allocate surplus")
+		}
+		plan, funded, err := tx.planSurplusAllocation(userID, year, month, req.Amount)
+		if err != nil {
+			return err
+		}
+		plan.Contributions = []response.GoalContributionResponse{}
+		for _, node := range funded {
+			contribution := &models.GoalContribution{
+				ID:            uuid.New(),
+				UserID:        userID,
+				GoalID:        node.goal.ID,
+				Amount:        float64(node.amount) / 100,
+				ContributedAt: at,
+				CreatedAt:     time.Now().UTC(),
+			}
+			if err := tx.financeRepo.CreateGoalContribution(contribution); err != nil {
+				return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to allocate surplus")
+			}
+			tx.audit.record(userID, EntityGoalContribution, contribution.ID, AuditCreate, createdChanges(contribution))
+			plan.Contributions = append(plan.Contributions, goalContributionResponse(*contribution))
+		}
+		plan.Committed = true
+		resp = plan
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// planSurplusAllocation runs the allocation waterfall for a month. It returns the plan
+// and the goals that receive money, in waterfall order. The surplus is the month's
+// income less its expenses and what was already contributed to goals; amount, when
+// set, allocates only part of it. Paused, archived and other inactive goals, and
+// their sub-goals, get nothing.
+func (s *FinanceService) planSurplusAllocation(userID uuid.UUID, year, month int, amount *float64) (*response.SurplusAllocationResponse, []*allocationNode, error) {
+	summary, err := s.financeRepo.GetMonthlySummary(userID, year, month)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to get monthly summary")
+	}
+	goals, err := s.financeRepo.ListGoalsWithProgress(userID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list goals")
+	}
+
+	resp := &response.SurplusAllocationResponse{
+		Year:        year,
+		Month:       month,
+		Income:      roundCents(summary.TotalIncome),
+		Expenses:    roundCents(summary.TotalExpenses),
+		Contributed: roundCents(summary.TotalSavings),
+		Surplus:     roundCents(summary.TotalIncome - summary.TotalExpenses - summary.TotalSavings),
+		Allocations: []response.GoalAllocationResponse{},
+	}
+	resp.Available = max(resp.Surplus, 0)
+	if amount != nil {
+		if roundCents(*amount) > resp.Available {
+			return nil, nil, errors.NewWithDetails(
+				errors.ErrInvalidInput.Code,
+				"Invalid allocation amount",
+				fmt.Sprintf("Only %.2f of the month's surplus is available to allocate", resp.Available),
+			)
+		}
+		resp.Available = roundCents(*amount)
+	}
+
+	roots := allocationTree(activeGoals(goals), summary.GoalContributions, year, month)
+	available := toCents(resp.Available)
+	left := allocateWaterfall(roots, available)
+	resp.Allocated = float64(available-left) / 100
+	resp.Unallocated = float64(left) / 100
+
+	var funded []*allocationNode
+	var walk func(nodes []*allocationNode)
+	walk = func(nodes []*allocationNode) {
+		for _, node := range nodes {
+			if node.need == 0 {
+				continue
+			}
+			resp.Allocations = append(resp.Allocations, response.GoalAllocationResponse{
+				GoalID:       node.goal.ID,
+				Name:         node.goal.Name,
+				ParentGoalID: node.goal.ParentGoalID,
+				Priority:     node.goal.AllocationPriority,
+				Weight:       node.goal.AllocationWeight,
+				MonthlyNeed:  float64(node.need) / 100,
+				Amount:       float64(node.amount) / 100,
+			})
+			if len(node.children) > 0 {
+				walk(node.children)
+			} else if node.amount > 0 {
+				funded = append(funded, node)
+			}
+		}
+	}
+	walk(roots)
+	return resp, funded, nil
+}
+
+// allocationTree arranges goals into their hierarchy, each level in waterfall order,
+// and works out how much every goal still needs this month. A parent goal needs what
+// its sub-goals need, as they carry the amounts, the way the forecast treats them.
+func allocationTree(goals []repository.GoalWithProgress, contributed map[uuid.UUID]float64, year, month int) []*allocationNode {
+	nodes := make(map[uuid.UUID]*allocationNode, len(goals))
+	for _, g := range goals {
+		nodes[g.Goal.ID] = &allocationNode{goal: g.Goal, need: toCents(goalMonthlyNeed(g, contributed[g.Goal.ID], year, month))}
+	}
+	var roots []*allocationNode
+	for _, g := range goals {
+		node := nodes[g.Goal.ID]
+		if g.Goal.ParentGoalID != nil {
+			if parent, ok := nodes[*g.Goal.ParentGoalID]; ok {
+				parent.children = append(parent.children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	var arrange func(nodes []*allocationNode)
+	arrange = func(nodes []*allocationNode) {
+		sort.SliceStable(nodes, func(i, j int) bool {
+			a, b := nodes[i].goal, nodes[j].goal
+			if a.AllocationPriority != b.AllocationPriority {
+				return a.AllocationPriority < b.AllocationPriority
+			}
+			return a.CreatedAt.Before(b.CreatedAt)
+		})
+		for _, node := range nodes {
+			if len(node.children) > 0 {
+				arrange(node.children)
+				node.need = 0
+				for _, child := range node.children {
+					node.need += child.need
+				}
+			}
+		}
+	}
+	arrange(roots)
+	return roots
+}
+
+// goalMonthlyNeed is what a goal still needs in a month: the month's share of what was
+// left at its start, spread evenly over the months up to the target date, less what
+// was already contributed. Goals without a target date, or past it, need everything.
+func goalMonthlyNeed(g repository.GoalWithProgress, contributed float64, year, month int) float64 {
+	remaining := g.Goal.TargetAmount - g.ContributedSum
+	if remaining <= 0 {
+		return 0
+	}
+	if g.Goal.TargetDate == nil {
+		return remaining
+	}
+	target := g.Goal.TargetDate.UTC()
+	left := (target.Year()-year)*12 + int(target.Month()) - month + 1
+	if left < 1 {
+		return remaining
+	}
+	required := (remaining + contributed) / float64(left)
+	return min(remaining, max(required-contributed, 0))
+}
+
+// allocateWaterfall fills nodes up to their need in priority order and returns the
+// cents left over. Each priority is filled before the next gets anything. What a
+// parent goal receives is allocated among its sub-goals the same way.
+func allocateWaterfall(nodes []*allocationNode, cents int64) int64 {
+	for i := 0; i < len(nodes) && cents > 0; {
+		j := i
+		for j < len(nodes) && nodes[j].goal.AllocationPriority == nodes[i].goal.AllocationPriority {
+			j++
+		}
+		cents = fillTier(nodes[i:j], cents)
+		i = j
+	}
+	for _, node := range nodes {
+		if len(node.children) > 0 && node.amount > 0 {
+			allocateWaterfall(node.children, node.amount)
+		}
+	}
+	return cents
+}
+
+// fillTier splits cents between goals of the same priority in proportion to their
+// weights. Goals whose share covers their need are filled and the rest is split again
+// between the others; cents lost to rounding go one each to the first goals in order.
+func fillTier(tier []*allocationNode, cents int64) int64 {
+	var active []*allocationNode
+	for _, node := range tier {
+		if node.need > node.amount {
+			active = append(active, node)
+		}
+	}
+	for cents > 0 && len(active) > 0 {
+		weight := 0.0
+		for _, node := range active {
+			weight += node.goal.AllocationWeight
+		}
+		pool := cents
+		var open []*allocationNode
+		for _, node := range active {
+			if room := node.need - node.amount; float64(pool)*node.goal.AllocationWeight/weight >= float64(room) {
+				node.amount += room
+				cents -= room
+			} else {
+				open = append(open, node)
+			}
+		}
+		if len(open) < len(active) {
+			active = open
+			continue
+		}
+
+		for _, node := range open {
+			share := int64(float64(pool) * node.goal.AllocationWeight / weight)
+			node.amount += share
+			cents -= share
+		}
+		for _, node := range open {
+			if cents == 0 {
+				break
+			}
+			if node.amount < node.need {
+				node.amount++
+				cents--
+			}
+		}
+		break
+	}
+	return cents
+}
+
+// allocationMonth returns the month a request allocates, the current one unless both year and month are set
+func allocationMonth(req *request.AllocateSurplusRequest, now time.Time) (int, int) {
+	if req.Year == 0 || req.Month == 0 {
+		return now.Year(), int(now.Month())
+	}
+	return req.Year, req.Month
+}
+
+// toCents converts an amount to whole cents
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
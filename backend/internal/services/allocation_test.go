@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestSurplusAllocationWaterfall(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	goal := func(name string, target float64, due *time.Time, priority int, weight float64, parent *uuid.UUID) uuid.UUID {
+		g := &models.Goal{
+			ID: uuid.New(), UserID: userID, Name: name, TargetAmount: target, TargetDate: due,
+			ParentGoalID: parent, AllocationPriority: priority, AllocationWeight: weight, CreatedAt: created,
+		}
+		if err := repo.CreateGoal(g); err != nil {
+			t.Fatalf("CreateGoal: %v", err)
+		}
+		return g.ID
+	}
+	holidayDue := time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC)
+	carDue := time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)
+	emergency := goal("Emergency fund", 500, nil, 1, 1, nil)
+	holiday := goal("Holiday", 1200, &holidayDue, 2, 1, nil)
+	car := goal("Car", 3000, &carDue, 2, 3, nil)
+	house := goal("House", 0, nil, 3, 1, nil)
+	deposit := goal("Deposit", 10000, nil, 1, 1, &house)
+	fees := goal("Fees", 1000, nil, 2, 1, &house)
+
+	// 3000 in, 1000 out and 200 already saved leave a surplus of 1800
+	if err := repo.CreateIncome(&models.Income{ID: uuid.New(), UserID: userID, Source: "Salary", Amount: 3000, ReceivedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+	if err := repo.CreateExpense(&models.Expense{ID: uuid.New(), UserID: userID, Category: "Rent", Amount: 1000, SpentAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	if err := repo.CreateGoalContribution(&models.GoalContribution{ID: uuid.New(), UserID: userID, GoalID: emergency, Amount: 200, ContributedAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatalf("CreateGoalContribution: %v", err)
+	}
+
+	// Part of the surplus fills the first priority and is split 1:3 within the second
+	partial := 700.0
+	plan, err := finance.PreviewSurplusAllocation(userID, &request.AllocateSurplusRequest{Amount: &partial}, now)
+	if err != nil {
+		t.Fatalf("PreviewSurplusAllocation: %v", err)
+	}
+	amounts := map[uuid.UUID]float64{}
+	for _, a := range plan.Allocations {
+		amounts[a.GoalID] = a.Amount
+	}
+	if plan.Surplus != 1800 || plan.Allocated != 700 || amounts[emergency] != 300 || amounts[holiday] != 100 || amounts[car] != 300 || amounts[house] != 0 {
+		t.Fatalf("expected 300 to the emergency fund and 100/300 split by weight, got %+v", plan)
+	}
+
+	// The whole surplus fills both priorities and the rest flows through the house to its first sub-goal
+	plan, err = finance.CommitSurplusAllocation(userID, &request.AllocateSurplusRequest{}, now)
+	if err != nil {
+		t.Fatalf("CommitSurplusAllocation: %v", err)
+	}
+	amounts = map[uuid.UUID]float64{}
+	for _, a := range plan.Allocations {
+		amounts[a.GoalID] = a.Amount
+	}
+	if !plan.Committed || plan.Allocated != 1800 || plan.Unallocated != 0 {
+		t.Fatalf("expected the whole surplus to be allocated, got %+v", plan)
+	}
+	if amounts[emergency] != 300 || amounts[holiday] != 200 || amounts[car] != 1000 || amounts[house] != 300 || amounts[deposit] != 300 || amounts[fees] != 0 {
+		t.Fatalf("unexpected waterfall %+v", plan.Allocations)
+	}
+	if len(plan.Contributions) != 4 {
+		t.Fatalf("expected a contribution for each funded goal but not the parent, got %+v", plan.Contributions)
+	}
+	for _, c := range plan.Contributions {
+		if c.GoalID == house || !c.ContributedAt.Equal(time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("unexpected contribution %+v", c)
+		}
+	}
+
+	// Committing again finds nothing left to allocate
+	plan, err = finance.CommitSurplusAllocation(userID, &request.AllocateSurplusRequest{Year: 2026, Month: 3}, now)
+	if err != nil {
+		t.Fatalf("CommitSurplusAllocation: %v", err)
+	}
+	if plan.Surplus != 0 || len(plan.Contributions) != 0 {
+		t.Fatalf("expected an allocated month to have no surplus left, got %+v", plan)
+	}
+	if _, err := finance.PreviewSurplusAllocation(userID, &request.AllocateSurplusRequest{Amount: &partial}, now); err == nil {
+		t.Fatalf("expected allocating more than the surplus to fail")
+	}
+}
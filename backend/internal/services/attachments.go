@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+	"finance-management/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// orphanBatchSize is how many orphaned attachments one purge pass removes at a time
+const orphanBatchSize = 100
+
+// receiptTypes are the file types, sniffed from their content, accepted as
+// attachments of expenses and incomes. Notes also accept plain text documents.
+var receiptTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// AttachmentService stores receipts and documents attached to expenses, incomes and notes
+type AttachmentService struct {
+	attachmentRepo repository.AttachmentRepositoryInterface
+	financeRepo    repository.FinanceRepositoryInterface
+	notesRepo      repository.NotesRepositoryInterface
+	store          storage.Storage
+	maxBytes       int64
+	audit          auditor
+}
+
+// NewAttachmentService creates a new attachment service that keeps files of up to
+// maxBytes in store. Mutations are recorded in auditRepo unless it is nil.
+func NewAttachmentService(attachmentRepo repository.AttachmentRepositoryInterface, financeRepo repository.FinanceRepositoryInterface, notesRepo repository.NotesRepositoryInterface, store storage.Storage, maxBytes int64, auditRepo repository.AuditRepositoryInterface) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		financeRepo:    financeRepo,
+		notesRepo:      notesRepo,
+		store:          store,
+		maxBytes:       maxBytes,
+		audit:          newAuditor(auditRepo),
+	}
+}
+
+// WithActor returns a copy of the service that attributes audit entries to actor
+func (s *AttachmentService) WithActor(actor string) *AttachmentService {
+	c := *s
+	c.audit.actor = actor
+	return &c
+}
+
+// transact runs fn in a transaction together with the audit entries it records through
+// audit, so a change is stored with its audit trail or not at all
+func (s *AttachmentService) transact(fn func(repo repository.AttachmentRepositoryInterface, audit auditor) error) error {
+	return s.attachmentRepo.Transaction(func(repo repository.AttachmentRepositoryInterface) error {
+		var pending pendingWork
+		audit := s.audit
+		audit.pending = &pending
+		if err := fn(repo, audit); err != nil {
+			return err
+		}
+		return s.audit.flush(repo, &pending)
+	})
+}
+
+// MaxBytes returns the largest file that may be attached
+func (s *AttachmentService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Upload attaches the file read from r to a record. The file type is sniffed
+// from the content; images also get a thumbnail.
+func (s *AttachmentService) Upload(ctx context.Context, userID uuid.UUID, ownerType string, ownerID uuid.UUID, fileName string, r io.Reader) (*response.AttachmentResponse, error) {
+	if err := s.checkOwner(userID, ownerType, ownerID); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrInvalidInput.Code, "Failed to read the uploaded file")
+	}
+	if int64(len(data)) > s.maxBytes {
+		return nil, errors.NewWithDetails(
+			errors.ErrRequestTooLarge.Code,
+			"File too large",
+			fmt.Sprintf("Attachments may be at most %d bytes", s.maxBytes),
+		)
+	}
+	if len(data) == 0 {
+		return nil, errors.NewWithDetails(errors.ErrInvalidInput.Code, "File is empty", "Upload a file with content")
+	}
+	contentType, err := sniffContentType(ownerType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.New()
+	attachment := &models.Attachment{
+		ID:          id,
+		UserID:      userID,
+		OwnerType:   ownerType,
+		OwnerID:     ownerID,
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  userID.String() + "/" + id.String(),
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.store.Put(ctx, attachment.StorageKey, data, contentType); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to store attachment")
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); thumbnailable[mediaType] {
+		s.storeThumbnail(ctx, attachment, data)
+	}
+
+	err = s.transact(func(repo repository.AttachmentRepositoryInterface, audit auditor) error {
+		if err := repo.CreateAttachment(attachment); err != nil {
+			return err
+		}
+		audit.record(userID, EntityAttachment, attachment.ID, AuditCreate, createdChanges(attachment))
+		return nil
+	})
+	if err != nil {
+		s.deleteFiles(ctx, *attachment)
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create attachment")
+	}
+
+	resp := attachmentResponse(*attachment)
+	return &resp, nil
+}
+
+// storeThumbnail saves a thumbnail of an image attachment. An image that cannot
+// be decoded is still attached, just without a thumbnail.
+func (s *AttachmentService) storeThumbnail(ctx context.Context, attachment *models.Attachment, data []byte) {
+	thumbnail, err := makeThumbnail(data)
+	if err != nil {
+		log.Printf("⚠️  No thumbnail for attachment %s: %v", attachment.ID, err)
+		return
+	}
+	key := attachment.StorageKey + ".thumb.jpg"
+	if err := s.store.Put(ctx, key, thumbnail, "image/jpeg"); err != nil {
+		log.Printf("⚠️  Failed to store thumbnail for attachment %s: %v", attachment.ID, err)
+		return
+	}
+	attachment.ThumbnailKey = &key
+}
+
+// ListAttachments retrieves the attachments of a record, oldest first
+func (s *AttachmentService) ListAttachments(userID uuid.UUID, ownerType string, ownerID uuid.UUID) ([]response.AttachmentResponse, error) {
+	if err := s.checkOwner(userID, ownerType, ownerID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.ListAttachments(userID, ownerType, ownerID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list attachments")
+	}
+	responses := make([]response.AttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		responses[i] = attachmentResponse(attachment)
+	}
+	return responses, nil
+}
+
+// OpenAttachment returns an attachment's metadata and its file, or its thumbnail
+// when thumbnail is set. The caller must close the file.
+func (s *AttachmentService) OpenAttachment(ctx context.Context, userID, id uuid.UUID, thumbnail bool) (*response.AttachmentResponse, io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.GetAttachment(id, userID)
+	if err != nil {
+		return nil, nil, lookupError(err, errors.ErrAttachmentNotFound, "Failed to get attachment")
+	}
+	key := attachment.StorageKey
+	if thumbnail {
+		if attachment.ThumbnailKey == nil {
+			return nil, nil, errors.ErrThumbnailNotFound
+		}
+		key = *attachment.ThumbnailKey
+	}
+	file, err := s.store.Get(ctx, key)
+	if err != nil {
+		if stderrors.Is(err, storage.ErrNotFound) {
+			return nil, nil, errors.ErrAttachmentNotFound
+		}
+		return nil, nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to read attachment")
+	}
+	resp := attachmentResponse(*attachment)
+	return &resp, file, nil
+}
+
+// DeleteAttachment removes an attachment and its files
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, userID, id uuid.UUID) error {
+	attachment, err := s.attachmentRepo.GetAttachment(id, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrAttachmentNotFound, "Failed to delete attachment")
+	}
+	err = s.transact(func(repo repository.AttachmentRepositoryInterface, audit auditor) error {
+		if err := repo.DeleteAttachment(id, userID); err != nil {
+			return err
+		}
+		audit.record(userID, EntityAttachment, id, AuditDelete, deletedChanges(attachment))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrAttachmentNotFound, "Failed to delete attachment")
+	}
+	s.deleteFiles(ctx, *attachment)
+	return nil
+}
+
+// PurgeOrphans removes the attachments, and their files, of records that have
+// been purged from the trash and returns how many were removed. An attachment
+// whose files cannot be deleted is kept so the next purge retries it.
+func (s *AttachmentService) PurgeOrphans(ctx context.Context) (int64, error) {
+	var purged int64
+	for {
+		orphans, err := s.attachmentRepo.ListOrphanedAttachments(orphanBatchSize)
+		if err != nil {
+			return purged, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list orphaned attachments")
+		}
+		removed := 0
+		for _, attachment := range orphans {
+			if !s.deleteFiles(ctx, attachment) {
+				continue
+			}
+			if err := s.attachmentRepo.DeleteAttachment(attachment.ID, attachment.UserID); err != nil {
+				return purged, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to delete orphaned attachment")
+			}
+			removed++
+		}
+		purged += int64(removed)
+		// Stop after a short batch, or when every file deletion failed so the same batch would come back
+		if len(orphans) < orphanBatchSize || removed == 0 {
+			return purged, nil
+		}
+	}
+}
+
+// deleteFiles removes an attachment's file and thumbnail from storage and reports
+// whether both are gone. Failures are logged since the record may already be deleted.
+func (s *AttachmentService) deleteFiles(ctx context.Context, attachment models.Attachment) bool {
+	keys := []string{attachment.StorageKey}
+	if attachment.ThumbnailKey != nil {
+		keys = append(keys, *attachment.ThumbnailKey)
+	}
+	ok := true
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("⚠️  Failed to delete attachment file %s: %v", key, err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// checkOwner verifies that the record an attachment belongs to exists and is not in the trash
+func (s *AttachmentService) checkOwner(userID uuid.UUID, ownerType string, ownerID uuid.UUID) error {
+	switch ownerType {
+	case models.AttachmentOwnerExpense:
+		if _, err := s.financeRepo.GetExpense(ownerID, userID); err != nil {
+			return lookupError(err, errors.ErrExpenseNotFound, "Failed to get expense")
+		}
+	case models.AttachmentOwnerIncome:
+		if _, err := s.financeRepo.GetIncome(ownerID, userID); err != nil {
+			return lookupError(err, errors.ErrIncomeNotFound, "Failed to get income")
+		}
+	case models.AttachmentOwnerNote:
+		if _, err := s.notesRepo.GetNoteByID(ownerID, userID); err != nil {
+			if err.Error() == "note not found" {
+				return errors.ErrNoteNotFound
+			}
+			return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to get note")
+		}
+	default:
+		return errors.ErrInvalidInput
+	}
+	return nil
+}
+
+// sniffContentType detects a file's type from its first bytes and checks that
+// records of ownerType accept it
+func sniffContentType(ownerType string, data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (receiptTypes[mediaType] || (ownerType == models.AttachmentOwnerNote && mediaType == "text/plain")) {
+		return contentType, nil
+	}
+	details := "Attach a JPEG, PNG, GIF or WebP image or a PDF"
+	if ownerType == models.AttachmentOwnerNote {
+		details = "Attach a JPEG, PNG, GIF or WebP image, a PDF or a plain text file"
+	}
+	return "", errors.NewWithDetails(errors.ErrUnsupportedMediaType.Code, errors.ErrUnsupportedMediaType.Message, details)
+}
+
+// cleanFileName keeps the base name of an uploaded file without control
+// characters or quotes, falling back to "attachment"
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	return name
+}
+
+// attachmentResponse converts an attachment model to its API representation
+func attachmentResponse(attachment models.Attachment) response.AttachmentResponse {
+	return response.AttachmentResponse{
+		ID:           attachment.ID,
+		OwnerType:    attachment.OwnerType,
+		OwnerID:      attachment.OwnerID,
+		FileName:     attachment.FileName,
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		HasThumbnail: attachment.ThumbnailKey != nil,
+		CreatedAt:    attachment.CreatedAt,
+	}
+}
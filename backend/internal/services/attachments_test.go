@@ -0,0 +1,175 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+	"finance-management/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 40, B: 40, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+type attachmentFixture struct {
+	attachments *AttachmentService
+	finance     *FinanceService
+	trash       *TrashService
+	store       storage.Storage
+}
+
+func newAttachmentFixture(t *testing.T, maxBytes int64) attachmentFixture {
+	t.Helper()
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	notesRepo := repository.NewInMemoryNotesRepository()
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	attachments := NewAttachmentService(repository.NewInMemoryAttachmentRepository(financeRepo, notesRepo), financeRepo, notesRepo, store, maxBytes, nil)
+	return attachmentFixture{
+		attachments: attachments,
+		finance:     NewFinanceService(financeRepo, nil, nil),
+		trash:       NewTrashService(financeRepo, notesRepo, 24*time.Hour).WithAttachments(attachments),
+		store:       store,
+	}
+}
+
+func TestUploadSniffsTypeAndMakesThumbnails(t *testing.T) {
+	f := newAttachmentFixture(t, 1<<20)
+	ctx := context.Background()
+	userID := uuid.New()
+	expense, _ := f.finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "food", Amount: 12, SpentAt: time.Now()})
+
+	photo, err := f.attachments.Upload(ctx, userID, models.AttachmentOwnerExpense, expense.ID, `C:\scans\receipt.pdf`, bytes.NewReader(pngImage(t, 600, 300)))
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if photo.ContentType != "image/png" || photo.FileName != "receipt.pdf" || !photo.HasThumbnail {
+		t.Fatalf("expected a sniffed PNG with a thumbnail and a base file name, got %+v", photo)
+	}
+
+	_, thumb, err := f.attachments.OpenAttachment(ctx, userID, photo.ID, true)
+	if err != nil {
+		t.Fatalf("OpenAttachment thumbnail: %v", err)
+	}
+	defer thumb.Close()
+	img, err := jpeg.Decode(thumb)
+	if err != nil {
+		t.Fatalf("decode thumbnail: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != thumbnailSize || b.Dy() != thumbnailSize/2 {
+		t.Fatalf("expected the thumbnail to keep the aspect ratio, got %v", b)
+	}
+
+	pdf, err := f.attachments.Upload(ctx, userID, models.AttachmentOwnerExpense, expense.ID, "invoice.pdf", strings.NewReader("%PDF-1.7\n..."))
+	if err != nil {
+		t.Fatalf("Upload pdf: %v", err)
+	}
+	if pdf.ContentType != "application/pdf" || pdf.HasThumbnail {
+		t.Fatalf("expected a PDF without a thumbnail, got %+v", pdf)
+	}
+	if _, _, err := f.attachments.OpenAttachment(ctx, userID, pdf.ID, true); err != errors.ErrThumbnailNotFound {
+		t.Fatalf("expected ErrThumbnailNotFound, got %v", err)
+	}
+
+	if _, err := f.attachments.Upload(ctx, userID, models.AttachmentOwnerExpense, expense.ID, "notes.txt", strings.NewReader("just some text")); err == nil {
+		t.Fatal("expected plain text to be rejected for expenses")
+	}
+	if _, err := f.attachments.Upload(ctx, userID, models.AttachmentOwnerExpense, uuid.New(), "receipt.pdf", strings.NewReader("%PDF-1.7")); err != errors.ErrExpenseNotFound {
+		t.Fatalf("expected ErrExpenseNotFound for an unknown expense, got %v", err)
+	}
+
+	listed, err := f.attachments.ListAttachments(userID, models.AttachmentOwnerExpense, expense.ID)
+	if err != nil || len(listed) != 2 || listed[0].ID != photo.ID {
+		t.Fatalf("expected both attachments oldest first, got %+v (%v)", listed, err)
+	}
+}
+
+func TestUploadRejectsFilesOverTheLimit(t *testing.T) {
+	f := newAttachmentFixture(t, 16)
+	userID := uuid.New()
+	expense, _ := f.finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "food", Amount: 12, SpentAt: time.Now()})
+
+	_, err := f.attachments.Upload(context.Background(), userID, models.AttachmentOwnerExpense, expense.ID, "big.pdf", strings.NewReader("%PDF-1.7 "+strings.Repeat("x", 16)))
+	appErr, ok := err.(*errors.AppError)
+	if !ok || appErr.Code != errors.ErrRequestTooLarge.Code {
+		t.Fatalf("expected a 413 error, got %v", err)
+	}
+}
+
+func TestPurgeRemovesAttachmentsOfPurgedRecords(t *testing.T) {
+	f := newAttachmentFixture(t, 1<<20)
+	ctx := context.Background()
+	userID := uuid.New()
+	kept, _ := f.finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "food", Amount: 12, SpentAt: time.Now()})
+	trashed, _ := f.finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "food", Amount: 30, SpentAt: time.Now()})
+
+	keptFile, _ := f.attachments.Upload(ctx, userID, models.AttachmentOwnerExpense, kept.ID, "a.png", bytes.NewReader(pngImage(t, 10, 10)))
+	trashedFile, _ := f.attachments.Upload(ctx, userID, models.AttachmentOwnerExpense, trashed.ID, "b.png", bytes.NewReader(pngImage(t, 10, 10)))
+	if err := f.finance.DeleteExpense(userID, trashed.ID); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+
+	// Records still in the trash keep their attachments
+	if _, err := f.trash.Purge(time.Now().UTC()); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if _, file, err := f.attachments.OpenAttachment(ctx, userID, trashedFile.ID, false); err != nil {
+		t.Fatalf("expected the trashed expense's attachment to survive, got %v", err)
+	} else {
+		file.Close()
+	}
+
+	purged, err := f.trash.Purge(time.Now().UTC().Add(48 * time.Hour))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if purged != 2 {
+		t.Fatalf("expected the expense and its attachment to be purged, got %d", purged)
+	}
+	if _, _, err := f.attachments.OpenAttachment(ctx, userID, trashedFile.ID, false); err != errors.ErrAttachmentNotFound {
+		t.Fatalf("expected the orphaned attachment to be removed, got %v", err)
+	}
+	key := userID.String() + "/" + trashedFile.ID.String()
+	for _, k := range []string{key, key + ".thumb.jpg"} {
+		if _, err := f.store.Get(ctx, k); err != storage.ErrNotFound {
+			t.Fatalf("expected %s to be deleted from storage, got %v", k, err)
+		}
+	}
+
+	_, file, err := f.attachments.OpenAttachment(ctx, userID, keptFile.ID, false)
+	if err != nil {
+		t.Fatalf("expected the other attachment to remain, got %v", err)
+	}
+	data, _ := io.ReadAll(file)
+	file.Close()
+	if int64(len(data)) != keptFile.Size {
+		t.Fatalf("expected %d bytes, got %d", keptFile.Size, len(data))
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+)
+
+// Audited entity types
+const (
+	EntityIncome           = "income"
+	EntityExpense          = "expense"
+	EntityGoal             = "goal"
+	EntityGoalContribution = "goal_contribution"
+	EntityGoalExpense      = "goal_expense"
+	EntityCategory         = "category"
+	EntityGoalCategory     = "goal_category"
+	EntityGoalTemplate     = "goal_template"
+	EntityNote             = "note"
+	EntityExpenseRule      = "expense_rule"
+	EntityMerchant         = "merchant"
+	EntityMerchantPattern  = "merchant_pattern"
+	EntityAttachment       = "attachment"
+	EntityFundingRule      = "funding_rule"
+	EntityDebt             = "debt"
+	EntityNetWorthItem     = "net_worth_item"
+	EntityValuation        = "net_worth_valuation"
+	EntitySecurity         = "security"
+	EntityInvestmentLot    = "investment_lot"
+	EntityInvestmentSale   = "investment_sale"
+)
+
+// DefaultActor is recorded when a request does not identify who made it
+const DefaultActor = "unknown"
+
+// Maximum number of entries returned by one audit query
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// auditor appends audit entries on behalf of one actor. A nil repository disables auditing.
+// When pending is set, entries are buffered there and written by the transaction that
+// set it, together with the changes they record.
+type auditor struct {
+	repo    repository.AuditRepositoryInterface
+	actor   string
+	pending *pendingWork
+}
+
+// pendingWork is what a transaction holds back until it commits: its audit entries, and
+// effects outside the transaction, such as rule statistics and the suggestion model,
+// that a rollback could not undo
+type pendingWork struct {
+	entries []models.AuditEntry
+	effects []func()
+}
+
+func newAuditor(repo repository.AuditRepositoryInterface) auditor {
+	return auditor{repo: repo, actor: DefaultActor}
+}
+
+// fieldChanges maps a column name to its "before" and/or "after" value
+type fieldChanges map[string]map[string]interface{}
+
+// record buffers an entry for the surrounding transaction to write. Outside a
+// transaction it is written straight away, after the mutation has succeeded; the
+// mutation is not rolled back then when the write fails, so failures are logged instead.
+func (a auditor) record(userID uuid.UUID, entityType string, entityID uuid.UUID, action string, changes fieldChanges) {
+	if a.repo == nil {
+		return
+	}
+	if changes == nil {
+		changes = fieldChanges{}
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		log.Printf("⚠️  Failed to encode audit changes for %s %s: %v", entityType, entityID, err)
+		return
+	}
+	entry := models.AuditEntry{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Actor:      a.actor,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Changes:    string(data),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if a.pending != nil {
+		a.pending.entries = append(a.pending.entries, entry)
+		return
+	}
+	a.write(entry)
+}
+
+// afterCommit runs effect once the surrounding transaction commits, or now outside one
+func (a auditor) afterCommit(effect func()) {
+	if a.pending != nil {
+		a.pending.effects = append(a.pending.effects, effect)
+		return
+	}
+	effect()
+}
+
+// flush writes the entries buffered in work through tx, the repository a Transaction
+// callback was handed, so they commit or roll back with the changes they record
+func (a auditor) flush(tx interface{}, work *pendingWork) error {
+	if a.repo == nil {
+		return nil
+	}
+	repo := a.repo.InTransaction(tx)
+	for i := range work.entries {
+		entry := &work.entries[i]
+		if err := repo.CreateAuditEntry(entry); err != nil {
+			return errors.Wrap(fmt.Errorf("%s %s %s: %w", entry.Action, entry.EntityType, entry.EntityID, err), errors.ErrDatabaseError.Code, "Failed to write audit entry")
+		}
+	}
+	work.entries = nil
+	return nil
+}
+
+// transact runs fn in a transaction on repo together with the audit entries fn records
+// through audit, so a change is stored with its audit trail or not at all. Effects fn
+// defers with audit.afterCommit run once the transaction commits. Inside another
+// transaction fn runs in a savepoint and hands its entries and effects to the
+// surrounding one.
+func (a auditor) transact(repo repository.FinanceRepositoryInterface, fn func(repo repository.FinanceRepositoryInterface, audit auditor) error) error {
+	var pending pendingWork
+	err := repo.Transaction(func(tx repository.FinanceRepositoryInterface) error {
+		audit := a
+		audit.pending = &pending
+		if err := fn(tx, audit); err != nil {
+			return err
+		}
+		if a.pending != nil {
+			return nil
+		}
+		return a.flush(tx, &pending)
+	})
+	if err != nil {
+		return err
+	}
+	a.commit(&pending)
+	return nil
+}
+
+// commit hands work buffered by a nested transaction to the surrounding one, or runs
+// its effects once its own transaction, which flushed its entries, has committed
+func (a auditor) commit(work *pendingWork) {
+	if a.pending != nil {
+		a.pending.entries = append(a.pending.entries, work.entries...)
+		a.pending.effects = append(a.pending.effects, work.effects...)
+		return
+	}
+	for _, effect := range work.effects {
+		effect()
+	}
+}
+
+// write stores entries straight away for services without a transaction to share
+func (a auditor) write(entries ...models.AuditEntry) {
+	if a.repo == nil {
+		return
+	}
+	for i := range entries {
+		entry := &entries[i]
+		if err := a.repo.CreateAuditEntry(entry); err != nil {
+			log.Printf("⚠️  Failed to write audit entry for %s %s %s: %v", entry.Action, entry.EntityType, entry.EntityID, err)
+		}
+	}
+}
+
+// snapshotFields are bookkeeping columns left out of audit diffs
+var snapshotFields = map[string]bool{"id": true, "user_id": true, "created_at": true, "updated_at": true, "deleted_at": true, "version": true, "match_count": true, "last_matched_at": true}
+
+// snapshot returns a model's fields keyed by their JSON (and column) names
+func snapshot(model interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	data, err := json.Marshal(model)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	for name := range snapshotFields {
+		delete(fields, name)
+	}
+	return fields
+}
+
+// jsonValue converts v to the value it would have after a JSON round trip,
+// so request values compare equal to the stored ones
+func jsonValue(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out interface{}
+	_ = json.Unmarshal(data, &out)
+	return out
+}
+
+// createdChanges records every field of a new entity as "after"
+func createdChanges(model interface{}) fieldChanges {
+	changes := fieldChanges{}
+	for name, value := range snapshot(model) {
+		changes[name] = map[string]interface{}{"after": value}
+	}
+	return changes
+}
+
+// deletedChanges records every field of a removed entity as "before"
+func deletedChanges(model interface{}) fieldChanges {
+	changes := fieldChanges{}
+	for name, value := range snapshot(model) {
+		changes[name] = map[string]interface{}{"before": value}
+	}
+	return changes
+}
+
+// updatedChanges diffs the stored entity against the updates map given to the
+// repository, keeping only the fields whose value actually changed
+func updatedChanges(before interface{}, updates map[string]interface{}) fieldChanges {
+	current := snapshot(before)
+	changes := fieldChanges{}
+	for name, value := range updates {
+		after := jsonValue(value)
+		if reflect.DeepEqual(current[name], after) {
+			continue
+		}
+		changes[name] = map[string]interface{}{"before": current[name], "after": after}
+	}
+	return changes
+}
+
+// AuditService queries the audit log
+type AuditService struct {
+	auditRepo repository.AuditRepositoryInterface
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditRepo repository.AuditRepositoryInterface) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// ListEntries retrieves the user's audit entries matching filter, newest first
+func (s *AuditService) ListEntries(userID uuid.UUID, filter repository.AuditFilter) ([]response.AuditEntryResponse, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+
+	entries, err := s.auditRepo.ListAuditEntries(userID, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list audit entries")
+	}
+
+	responses := make([]response.AuditEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = response.AuditEntryResponse{
+			ID:         entry.ID,
+			Actor:      entry.Actor,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			Action:     entry.Action,
+			Changes:    json.RawMessage(entry.Changes),
+			CreatedAt:  entry.CreatedAt,
+		}
+	}
+	return responses, nil
+}
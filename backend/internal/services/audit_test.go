@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+	"finance-management/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+func TestFinanceMutationsAreAudited(t *testing.T) {
+	auditRepo := repository.NewInMemoryAuditRepository()
+	svc := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, auditRepo).WithActor("sam")
+	userID := uuid.New()
+
+	expense, err := svc.CreateExpense(userID, &request.CreateExpenseRequest{Category: "food", Amount: 10, SpentAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	amount, category := 12.5, "food"
+	if _, err := svc.UpdateExpense(userID, expense.ID, expense.Version, &request.UpdateExpenseRequest{Amount: &amount, Category: &category}); err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	if err := svc.DeleteExpense(userID, expense.ID); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+
+	entries, _ := NewAuditService(auditRepo).ListEntries(userID, repository.AuditFilter{EntityID: &expense.ID})
+	if len(entries) != 3 {
+		t.Fatalf("expected create, update and delete entries, got %d", len(entries))
+	}
+	for i, action := range []string{AuditDelete, AuditUpdate, AuditCreate} {
+		if entries[i].Action != action || entries[i].Actor != "sam" || entries[i].EntityType != EntityExpense {
+			t.Fatalf("entry %d: unexpected %+v", i, entries[i])
+		}
+	}
+
+	var changes map[string]map[string]interface{}
+	if err := json.Unmarshal(entries[1].Changes, &changes); err != nil {
+		t.Fatalf("invalid changes: %v", err)
+	}
+	if len(changes) != 1 || changes["amount"]["before"] != 10.0 || changes["amount"]["after"] != 12.5 {
+		t.Fatalf("update diff should only contain the amount, got %s", entries[1].Changes)
+	}
+	if err := json.Unmarshal(entries[0].Changes, &changes); err != nil || changes["category"]["before"] != "food" {
+		t.Fatalf("delete should record the removed values, got %s", entries[0].Changes)
+	}
+}
+
+// failingAuditRepository refuses every audit entry
+type failingAuditRepository struct {
+	*repository.InMemoryAuditRepository
+}
+
+func (r failingAuditRepository) CreateAuditEntry(*models.AuditEntry) error {
+	return stderrors.New("audit log unavailable")
+}
+
+func (r failingAuditRepository) InTransaction(interface{}) repository.AuditRepositoryInterface {
+	return r
+}
+
+func TestMutationsRollBackWhenTheirAuditEntryFails(t *testing.T) {
+	auditRepo := failingAuditRepository{repository.NewInMemoryAuditRepository()}
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	notesRepo := repository.NewInMemoryNotesRepository()
+	userID := uuid.New()
+
+	svc := NewFinanceService(financeRepo, nil, auditRepo)
+	if _, err := svc.CreateIncome(userID, &request.CreateIncomeRequest{Source: "Salary", Amount: 100, ReceivedAt: time.Now()}); err == nil {
+		t.Fatal("CreateIncome: expected the audit failure to be returned")
+	}
+	if incomes, _ := financeRepo.ListIncomes(userID, 10); len(incomes) != 0 {
+		t.Fatalf("expected the income to be rolled back, got %d", len(incomes))
+	}
+
+	notes := NewNotesService(notesRepo, auditRepo)
+	if _, err := notes.CreateNote(userID, &request.CreateNoteRequest{Title: "Budget", Content: "Plan"}); err == nil {
+		t.Fatal("CreateNote: expected the audit failure to be returned")
+	}
+	if list, _ := notesRepo.GetNotesByUserID(userID); len(list) != 0 {
+		t.Fatalf("expected the note to be rolled back, got %d", len(list))
+	}
+
+	ruleRepo := repository.NewInMemoryRuleRepository()
+	rules := NewRulesService(ruleRepo, financeRepo, auditRepo)
+	_, err := rules.CreateRule(userID, &request.CreateRuleRequest{
+		Name:        "Coffee",
+		Conditions:  []request.RuleConditionRequest{{Field: "description", Operator: "contains", Value: "coffee"}},
+		SetCategory: strPtr("Eating out"),
+	})
+	if err == nil {
+		t.Fatal("CreateRule: expected the audit failure to be returned")
+	}
+	if list, _ := ruleRepo.ListRules(userID); len(list) != 0 {
+		t.Fatalf("expected the rule to be rolled back, got %d", len(list))
+	}
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	expense, err := NewFinanceService(financeRepo, nil, nil).CreateExpense(userID, &request.CreateExpenseRequest{Category: "Food", Amount: 5, SpentAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	attachmentRepo := repository.NewInMemoryAttachmentRepository(financeRepo, notesRepo)
+	attachments := NewAttachmentService(attachmentRepo, financeRepo, notesRepo, store, 1<<20, auditRepo)
+	if _, err := attachments.Upload(context.Background(), userID, models.AttachmentOwnerExpense, expense.ID, "receipt.pdf", strings.NewReader("%PDF-1.7")); err == nil {
+		t.Fatal("Upload: expected the audit failure to be returned")
+	}
+	if list, _ := attachmentRepo.ListAttachments(userID, models.AttachmentOwnerExpense, expense.ID); len(list) != 0 {
+		t.Fatalf("expected the attachment to be rolled back, got %d", len(list))
+	}
+}
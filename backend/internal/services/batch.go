@@ -0,0 +1,282 @@
+package services
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/validation"
+
+	"github.com/google/uuid"
+)
+
+// batchStep is one validated operation of a batch request
+type batchStep struct {
+	op string
+	id *uuid.UUID
+	// invalid is set when the operation failed validation and must not be applied
+	invalid error
+	// apply performs the operation and returns the affected row's id and representation
+	apply func(s *FinanceService) (uuid.UUID, interface{}, error)
+}
+
+// errBatchStepFailed aborts an all-or-nothing transaction after an operation failed
+var errBatchStepFailed = stderrors.New("batch operation failed")
+
+// BatchIncomes creates, updates and deletes incomes in one transaction
+func (s *FinanceService) BatchIncomes(userID uuid.UUID, req *request.IncomeBatchRequest) (*response.BatchResponse, error) {
+	if err := validation.ValidateBatchSize(len(req.Operations)); err != nil {
+		return nil, err
+	}
+
+	steps := make([]batchStep, len(req.Operations))
+	for i, op := range req.Operations {
+		step := batchStep{op: op.Op, id: op.ID, invalid: validation.ValidateBatchTarget(op.Op, op.ID)}
+		switch op.Op {
+		case request.BatchCreate:
+			if step.invalid == nil {
+				step.invalid = validation.ValidateAmount(op.Create.Amount)
+			}
+			step.apply = func(s *FinanceService) (uuid.UUID, interface{}, error) {
+				income, err := s.CreateIncome(userID, op.Create)
+				if err != nil {
+					return uuid.Nil, nil, err
+				}
+				return income.ID, income, nil
+			}
+		case request.BatchUpdate:
+			if step.invalid == nil {
+				step.invalid = validation.ValidateBatchVersion(op.Op, op.Version)
+			}
+			if step.invalid == nil {
+				_, step.invalid = incomeUpdates(op.Update)
+			}
+			step.apply = func(s *FinanceService) (uuid.UUID, interface{}, error) {
+				income, err := s.UpdateIncome(userID, *op.ID, op.Version, op.Update)
+				if err != nil {
+					return uuid.Nil, nil, err
+				}
+				return income.ID, income, nil
+			}
+		case request.BatchDelete:
+			step.apply = func(s *FinanceService) (uuid.UUID, interface{}, error) {
+				if _, err := s.GetIncome(userID, *op.ID); err != nil {
+					return uuid.Nil, nil, err
+				}
+				return *op.ID, nil, s.DeleteIncome(userID, *op.ID)
+			}
+		}
+		steps[i] = step
+	}
+
+	return s.runBatch(req.Mode, steps)
+}
+
+// BatchExpenses creates, updates and deletes expenses in one transaction
+func (s *FinanceService) BatchExpenses(userID uuid.UUID, req *request.ExpenseBatchRequest) (*response.BatchResponse, error) {
+	if err := validation.ValidateBatchSize(len(req.Operations)); err != nil {
+		return nil, err
+	}
+
+	steps := make([]batchStep, len(req.Operations))
+	for i, op := range req.Operations {
+		step := batchStep{op: op.Op, id: op.ID, invalid: validation.ValidateBatchTarget(op.Op, op.ID)}
+		switch op.Op {
+		case request.BatchCreate:
+			if step.invalid == nil {
+				step.invalid = validation.ValidateAmount(op.Create.Amount)
+			}
+			step.apply = func(s *FinanceService) (uuid.UUID, interface{}, error) {
+				expense, err := s.CreateExpense(userID, op.Create)
+				if err != nil {
+					return uuid.Nil, nil, err
+				}
+				return expense.ID, expense, nil
+			}
+		case request.BatchUpdate:
+			if step.invalid == nil {
+				step.invalid = validation.ValidateBatchVersion(op.Op, op.Version)
+			}
+			if step.invalid == nil {
+				_, step.invalid = expenseUpdates(op.Update)
+			}
+			step.apply = func(s *FinanceService) (uuid.UUID, interface{}, error) {
+				expense, err := s.UpdateExpense(userID, *op.ID, op.Version, op.Update)
+				if err != nil {
+					return uuid.Nil, nil, err
+				}
+				return expense.ID, expense, nil
+			}
+		case request.BatchDelete:
+			step.apply = func(s *FinanceService) (uuid.UUID, interface{}, error) {
+				if _, err := s.GetExpense(userID, *op.ID); err != nil {
+					return uuid.Nil, nil, err
+				}
+				return *op.ID, nil, s.DeleteExpense(userID, *op.ID)
+			}
+		}
+		steps[i] = step
+	}
+
+	return s.runBatch(req.Mode, steps)
+}
+
+// BatchGoalContributions creates, updates and deletes goal contributions in one transaction.
+// Contributions are not versioned, so operation versions are ignored.
+func (s *FinanceService) BatchGoalContributions(userID uuid.UUID, req *request.GoalContributionBatchRequest) (*response.BatchResponse, error) {
+	if err := validation.ValidateBatchSize(len(req.Operations)); err != nil {
+		return nil, err
+	}
+
+	steps := make([]batchStep, len(req.Operations))
+	for i, op := range req.Operations {
+		step := batchStep{op: op.Op, id: op.ID, invalid: validation.ValidateBatchTarget(op.Op, op.ID)}
+		switch op.Op {
+		case request.BatchCreate:
+			if step.invalid == nil {
+				step.invalid = validation.ValidateAmount(op.Create.Amount)
+			}
+			step.apply = func(s *FinanceService) (uuid.UUID, interface{}, error) {
+				contribution, err := s.CreateGoalContribution(userID, op.Create)
+				if err != nil {
+					return uuid.Nil, nil, err
+				}
+				return contribution.ID, contribution, nil
+			}
+		case request.BatchUpdate:
+			if step.invalid == nil {
+				_, step.invalid = goalContributionUpdates(op.Update)
+			}
+			step.apply = func(s *FinanceService) (uuid.UUID, interface{}, error) {
+				contribution, err := s.UpdateGoalContribution(userID, *op.ID, op.Update)
+				if err != nil {
+					return uuid.Nil, nil, err
+				}
+				return contribution.ID, contribution, nil
+			}
+		case request.BatchDelete:
+			step.apply = func(s *FinanceService) (uuid.UUID, interface{}, error) {
+				return *op.ID, nil, s.DeleteGoalContribution(userID, *op.ID)
+			}
+		}
+		steps[i] = step
+	}
+
+	return s.runBatch(req.Mode, steps)
+}
+
+// runBatch applies steps in a single transaction. In all-or-nothing mode the first
+// failure rolls everything back; in best-effort mode each step runs in its own
+// savepoint so failed steps are skipped. Audit entries are written after commit.
+func (s *FinanceService) runBatch(mode string, steps []batchStep) (*response.BatchResponse, error) {
+	if mode == "" {
+		mode = request.BatchAllOrNothing
+	}
+
+	results := make([]response.BatchItemResult, len(steps))
+	valid := true
+	for i, step := range steps {
+		results[i] = response.BatchItemResult{Index: i, Op: step.op, ID: step.id}
+		if step.invalid != nil {
+			failBatchItem(&results[i], step.invalid)
+			valid = false
+		}
+	}
+
+	var err error
+	switch {
+	case mode == request.BatchBestEffort:
+		err = s.transact(func(tx *FinanceService) error {
+			for i, step := range steps {
+				if step.invalid != nil {
+					continue
+				}
+				// Each operation runs in a savepoint whose audit entries go when it fails
+				err := tx.transact(func(tx *FinanceService) error {
+					return tx.applyBatchStep(&results[i], step)
+				})
+				if err != nil && results[i].Error == "" {
+					failBatchItem(&results[i], errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to apply batch operation"))
+				}
+			}
+			return nil
+		})
+
+	case !valid:
+		// Nothing is applied when any operation is invalid
+		abortBatchItems(results, -1)
+
+	default:
+		failed := -1
+		err = s.transact(func(tx *FinanceService) error {
+			for i, step := range steps {
+				if err := tx.applyBatchStep(&results[i], step); err != nil {
+					failed = i
+					return err
+				}
+			}
+			return nil
+		})
+		if failed >= 0 {
+			abortBatchItems(results, failed)
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to apply batch")
+	}
+
+	resp := &response.BatchResponse{Mode: mode, Results: results}
+	for _, result := range results {
+		if result.Status < http.StatusBadRequest {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	return resp, nil
+}
+
+// applyBatchStep runs one step and records its outcome in result
+func (s *FinanceService) applyBatchStep(result *response.BatchItemResult, step batchStep) error {
+	id, data, err := step.apply(s)
+	if err != nil {
+		failBatchItem(result, err)
+		return errBatchStepFailed
+	}
+	result.ID = &id
+	result.Data = data
+	result.Status = http.StatusOK
+	if step.op == request.BatchCreate {
+		result.Status = http.StatusCreated
+	}
+	return nil
+}
+
+// failBatchItem records err as the outcome of an operation
+func failBatchItem(result *response.BatchItemResult, err error) {
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) {
+		appErr = errors.Wrap(err, errors.ErrDatabaseError.Code, errors.ErrDatabaseError.Message)
+	}
+	result.Status = appErr.Code
+	result.Error = appErr.Message
+	result.Details = appErr.Details
+	result.Current = appErr.Current
+	result.Data = nil
+}
+
+// abortBatchItems marks every operation except failed and the invalid ones as not applied
+func abortBatchItems(results []response.BatchItemResult, failed int) {
+	for i := range results {
+		if i == failed || results[i].Error != "" {
+			continue
+		}
+		if results[i].Op == request.BatchCreate {
+			results[i].ID = nil
+		}
+		failBatchItem(&results[i], errors.ErrBatchAborted)
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func decodeIncomeBatch(t *testing.T, body string) *request.IncomeBatchRequest {
+	t.Helper()
+	var req request.IncomeBatchRequest
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("decode batch: %v", err)
+	}
+	return &req
+}
+
+func TestBatchIncomesAllOrNothingRollsBack(t *testing.T) {
+	auditRepo := repository.NewInMemoryAuditRepository()
+	svc := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, auditRepo)
+	userID := uuid.New()
+
+	existing, err := svc.CreateIncome(userID, &request.CreateIncomeRequest{Source: "salary", Amount: 1000, ReceivedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+
+	// The stale version makes the second operation fail after the first was applied
+	result, err := svc.BatchIncomes(userID, decodeIncomeBatch(t, `{"operations":[
+		{"op":"create","data":{"source":"bonus","amount":250,"received_at":"2026-01-15T00:00:00Z"}},
+		{"op":"update","id":"`+existing.ID.String()+`","version":7,"data":{"amount":1100}}
+	]}`))
+	if err != nil {
+		t.Fatalf("BatchIncomes: %v", err)
+	}
+	if result.Succeeded != 0 || result.Failed != 2 {
+		t.Fatalf("expected every operation to fail, got %+v", result)
+	}
+	if result.Results[0].Status != http.StatusFailedDependency || result.Results[0].ID != nil {
+		t.Fatalf("expected the create to be rolled back, got %+v", result.Results[0])
+	}
+	if result.Results[1].Status != http.StatusPreconditionFailed || result.Results[1].Current == nil {
+		t.Fatalf("expected a version conflict with the current income, got %+v", result.Results[1])
+	}
+
+	incomes, _ := svc.ListIncomes(userID, 0, repository.TagFilter{})
+	if len(incomes) != 1 || incomes[0].Amount != 1000 {
+		t.Fatalf("expected only the untouched income, got %+v", incomes)
+	}
+	entries, _ := auditRepo.ListAuditEntries(userID, repository.AuditFilter{})
+	if len(entries) != 1 {
+		t.Fatalf("expected no audit entries from the rolled back batch, got %d", len(entries)-1)
+	}
+}
+
+func TestBatchExpensesRecordRuleMatchesOnlyOnceCommitted(t *testing.T) {
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	ruleRepo := repository.NewInMemoryRuleRepository()
+	svc := NewFinanceService(financeRepo, ruleRepo, nil)
+	rules := NewRulesService(ruleRepo, financeRepo, nil)
+	userID := uuid.New()
+
+	rule, err := rules.CreateRule(userID, &request.CreateRuleRequest{
+		Name:        "Coffee",
+		Conditions:  []request.RuleConditionRequest{{Field: "description", Operator: "contains", Value: "coffee"}},
+		SetCategory: strPtr("Eating out"),
+	})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	batch := func(body string) {
+		var req request.ExpenseBatchRequest
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatalf("decode batch: %v", err)
+		}
+		if _, err := svc.BatchExpenses(userID, &req); err != nil {
+			t.Fatalf("BatchExpenses: %v", err)
+		}
+	}
+	matches := func() int {
+		got, err := rules.GetRule(userID, rule.ID)
+		if err != nil {
+			t.Fatalf("GetRule: %v", err)
+		}
+		return got.MatchCount
+	}
+
+	// The delete of an unknown expense rolls the matching create back
+	batch(`{"operations":[
+		{"op":"create","data":{"description":"Coffee","amount":3,"spent_at":"2026-01-15T00:00:00Z"}},
+		{"op":"delete","id":"` + uuid.New().String() + `"}
+	]}`)
+	if n := matches(); n != 0 {
+		t.Fatalf("expected a rolled back batch not to count rule matches, got %d", n)
+	}
+	batch(`{"operations":[{"op":"create","data":{"description":"Coffee","amount":3,"spent_at":"2026-01-15T00:00:00Z"}}]}`)
+	if n := matches(); n != 1 {
+		t.Fatalf("expected the committed expense to count, got %d", n)
+	}
+}
+
+func TestBatchIncomesAllOrNothingSkipsInvalidBatch(t *testing.T) {
+	svc := newTestFinanceService()
+	userID := uuid.New()
+
+	result, err := svc.BatchIncomes(userID, decodeIncomeBatch(t, `{"operations":[
+		{"op":"create","data":{"source":"bonus","amount":250,"received_at":"2026-01-15T00:00:00Z"}},
+		{"op":"delete"}
+	]}`))
+	if err != nil {
+		t.Fatalf("BatchIncomes: %v", err)
+	}
+	if result.Results[0].Status != http.StatusFailedDependency || result.Results[1].Status != http.StatusBadRequest {
+		t.Fatalf("expected 424 and 400, got %+v", result.Results)
+	}
+	if incomes, _ := svc.ListIncomes(userID, 0, repository.TagFilter{}); len(incomes) != 0 {
+		t.Fatalf("expected nothing to be applied, got %d incomes", len(incomes))
+	}
+}
+
+func TestBatchIncomesRequireVersionsOnUpdates(t *testing.T) {
+	svc := newTestFinanceService()
+	userID := uuid.New()
+
+	existing, err := svc.CreateIncome(userID, &request.CreateIncomeRequest{Source: "salary", Amount: 1000, ReceivedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+
+	result, err := svc.BatchIncomes(userID, decodeIncomeBatch(t, `{"mode":"best_effort","operations":[
+		{"op":"update","id":"`+existing.ID.String()+`","data":{"amount":1100}},
+		{"op":"update","id":"`+existing.ID.String()+`","version":1,"data":{"source":"payroll"}}
+	]}`))
+	if err != nil {
+		t.Fatalf("BatchIncomes: %v", err)
+	}
+	if result.Results[0].Status != http.StatusPreconditionRequired || result.Results[1].Status != http.StatusOK {
+		t.Fatalf("expected 428 for the update without a version and 200 for the other, got %+v", result.Results)
+	}
+	income, _ := svc.GetIncome(userID, existing.ID)
+	if income.Amount != 1000 || income.Source != "payroll" {
+		t.Fatalf("expected only the versioned update to be applied, got %+v", income)
+	}
+}
+
+func TestBatchIncomesBestEffortAppliesValidOperations(t *testing.T) {
+	auditRepo := repository.NewInMemoryAuditRepository()
+	svc := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, auditRepo)
+	userID := uuid.New()
+
+	existing, err := svc.CreateIncome(userID, &request.CreateIncomeRequest{Source: "salary", Amount: 1000, ReceivedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+
+	result, err := svc.BatchIncomes(userID, decodeIncomeBatch(t, `{"mode":"best_effort","operations":[
+		{"op":"update","id":"`+existing.ID.String()+`","version":1,"data":{"source":"payroll"}},
+		{"op":"create","data":{"source":"refund","amount":-5,"received_at":"2026-01-15T00:00:00Z"}},
+		{"op":"delete","id":"`+uuid.NewString()+`"},
+		{"op":"create","data":{"source":"bonus","amount":250,"received_at":"2026-01-15T00:00:00Z"}}
+	]}`))
+	if err != nil {
+		t.Fatalf("BatchIncomes: %v", err)
+	}
+	if result.Succeeded != 2 || result.Failed != 2 {
+		t.Fatalf("expected 2 successes and 2 failures, got %+v", result)
+	}
+	statuses := []int{http.StatusOK, http.StatusBadRequest, http.StatusNotFound, http.StatusCreated}
+	for i, status := range statuses {
+		if result.Results[i].Status != status {
+			t.Fatalf("operation %d: expected status %d, got %+v", i, status, result.Results[i])
+		}
+	}
+
+	incomes, _ := svc.ListIncomes(userID, 0, repository.TagFilter{})
+	if len(incomes) != 2 {
+		t.Fatalf("expected 2 incomes, got %d", len(incomes))
+	}
+	entries, _ := auditRepo.ListAuditEntries(userID, repository.AuditFilter{})
+	if len(entries) != 3 {
+		t.Fatalf("expected audit entries for the initial create and both batch successes, got %d", len(entries))
+	}
+}
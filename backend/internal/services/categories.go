@@ -0,0 +1,433 @@
+package services
+
+import (
+	stderrors "errors"
+	"sort"
+	"strings"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+	"finance-management/internal/validation"
+
+	"github.com/google/uuid"
+)
+
+// GetCategory retrieves a single expense category
+func (s *FinanceService) GetCategory(userID, categoryID uuid.UUID) (*response.CategoryResponse, error) {
+	category, err := s.financeRepo.GetCategory(categoryID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrCategoryNotFound, "Failed to get category")
+	}
+	resp := categoryResponse(*category)
+	return &resp, nil
+}
+
+// UpdateCategory renames a category or moves it under another parent. A new
+// name is copied to every expense filed under the category.
+func (s *FinanceService) UpdateCategory(userID, categoryID uuid.UUID, req *request.UpdateCategoryRequest) (*response.CategoryResponse, error) {
+	categories, err := s.financeRepo.ListCategories(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to update category")
+	}
+	tree := newCategoryTree(categories)
+	before, ok := tree.byID[categoryID]
+	if !ok {
+		return nil, errors.ErrCategoryNotFound
+	}
+
+	updates := make(map[string]interface{})
+	after := *before
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := validation.ValidateCategoryName(name); err != nil {
+			return nil, err
+		}
+		updates["name"] = name
+		after.Name = name
+	}
+	if req.ParentID.Set {
+		parentID := req.ParentID.ID
+		if parentID != nil {
+			if _, ok := tree.byID[*parentID]; !ok {
+				return nil, errors.ErrCategoryNotFound
+			}
+			if *parentID == categoryID || tree.isDescendant(*parentID, categoryID) {
+				return nil, errors.NewWithDetails(
+					errors.ErrInvalidInput.Code,
+					"Invalid parent category",
+					"A category cannot be moved under itself or one of its sub-categories",
+				)
+			}
+		}
+		updates["parent_id"] = parentID
+		after.ParentID = parentID
+	}
+	if len(updates) == 0 {
+		return nil, errors.ErrInvalidInput
+	}
+	if tree.siblingNamed(after.ParentID, after.Name, categoryID) {
+		return nil, errors.ErrCategoryExists
+	}
+
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateCategory(categoryID, userID, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityCategory, categoryID, AuditUpdate, updatedChanges(before, updates))
+		// A rename reaches every expense in the category, which suggestions learned by name
+		if _, renamed := updates["name"]; renamed {
+			tx.audit.afterCommit(func() { s.suggestions.Invalidate(userID) })
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, categoryError(err, "Failed to update category")
+	}
+
+	return s.GetCategory(userID, categoryID)
+}
+
+// DeleteCategory removes a category. A category that expenses or sub-categories
+// still use can only be deleted by reassigning them to another category.
+func (s *FinanceService) DeleteCategory(userID, categoryID uuid.UUID, reassignTo *uuid.UUID) error {
+	if reassignTo != nil {
+		_, err := s.MergeCategories(userID, *reassignTo, &request.MergeCategoriesRequest{SourceIDs: []uuid.UUID{categoryID}})
+		return err
+	}
+
+	before, err := s.financeRepo.GetCategory(categoryID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrCategoryNotFound, "Failed to delete category")
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteCategory(categoryID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityCategory, categoryID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return categoryError(err, "Failed to delete category")
+	}
+	return nil
+}
+
+// MergeCategories folds the source categories into the target: their expenses
+// and sub-categories move to the target and the sources are deleted
+func (s *FinanceService) MergeCategories(userID, targetID uuid.UUID, req *request.MergeCategoriesRequest) (*response.MergeCategoriesResponse, error) {
+	categories, err := s.financeRepo.ListCategories(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to merge categories")
+	}
+	tree := newCategoryTree(categories)
+	if _, ok := tree.byID[targetID]; !ok {
+		return nil, errors.ErrCategoryNotFound
+	}
+
+	sources := make(map[uuid.UUID]bool, len(req.SourceIDs))
+	for _, id := range req.SourceIDs {
+		if id == targetID || sources[id] {
+			return nil, errors.NewWithDetails(
+				errors.ErrInvalidInput.Code,
+				"Invalid merge",
+				"Source categories must be distinct and must not include the target",
+			)
+		}
+		if _, ok := tree.byID[id]; !ok {
+			return nil, errors.ErrCategoryNotFound
+		}
+		if tree.isDescendant(targetID, id) {
+			return nil, errors.NewWithDetails(
+				errors.ErrInvalidInput.Code,
+				"Invalid merge",
+				"A category cannot be merged into one of its own sub-categories",
+			)
+		}
+		sources[id] = true
+	}
+
+	// Sub-categories of the sources join the target's, so their names must not clash
+	names := make(map[string]bool)
+	for _, id := range append([]uuid.UUID{targetID}, req.SourceIDs...) {
+		for _, child := range tree.children[id] {
+			if sources[child.ID] {
+				continue
+			}
+			name := strings.ToLower(child.Name)
+			if names[name] {
+				return nil, errors.NewWithDetails(
+					errors.ErrCategoryExists.Code,
+					errors.ErrCategoryExists.Message,
+					"Sub-category \""+child.Name+"\" exists in more than one of the merged categories",
+				)
+			}
+			names[name] = true
+		}
+	}
+
+	var moved int64
+	err = s.transact(func(tx *FinanceService) error {
+		var err error
+		if moved, err = tx.financeRepo.MergeCategories(userID, targetID, req.SourceIDs); err != nil {
+			return err
+		}
+		for _, id := range req.SourceIDs {
+			tx.audit.record(userID, EntityCategory, id, AuditDelete, deletedChanges(tree.byID[id]))
+		}
+		tx.audit.afterCommit(func() { s.suggestions.Invalidate(userID) })
+		return nil
+	})
+	if err != nil {
+		return nil, categoryError(err, "Failed to merge categories")
+	}
+
+	category, err := s.GetCategory(userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &response.MergeCategoriesResponse{Category: *category, MovedExpenses: moved}, nil
+}
+
+// nameExpenseCategory gives a new expense the name of the category it names by ID,
+// so rules see it. A category name that does not match the ID is rejected.
+func (s *FinanceService) nameExpenseCategory(userID uuid.UUID, expense *models.Expense) error {
+	category, err := s.financeRepo.GetCategory(*expense.CategoryID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrCategoryNotFound, "Failed to get category")
+	}
+	if expense.Category != "" && !strings.EqualFold(expense.Category, category.Name) {
+		return errors.ErrCategoryMismatch
+	}
+	expense.Category = category.Name
+	return nil
+}
+
+// linkExpenseCategory points a new expense at its category once rules have run: the
+// one it names by ID, or otherwise the one matching its category name, which is
+// created when missing
+func (s *FinanceService) linkExpenseCategory(userID uuid.UUID, expense *models.Expense) error {
+	if expense.CategoryID != nil {
+		return nil
+	}
+	if err := validation.ValidateCategoryName(expense.Category); err != nil {
+		return err
+	}
+	category, err := categoryByName(s.financeRepo, s.audit, userID, expense.Category)
+	if err != nil {
+		return err
+	}
+	expense.CategoryID = &category.ID
+	expense.Category = category.Name
+	return nil
+}
+
+// linkCategoryUpdates keeps an expense update's category name and ID consistent
+func (s *FinanceService) linkCategoryUpdates(userID uuid.UUID, updates map[string]interface{}) error {
+	if id, ok := updates["category_id"].(uuid.UUID); ok {
+		category, err := s.financeRepo.GetCategory(id, userID)
+		if err != nil {
+			return lookupError(err, errors.ErrCategoryNotFound, "Failed to get category")
+		}
+		updates["category"] = category.Name
+		return nil
+	}
+	name, ok := updates["category"].(string)
+	if !ok {
+		return nil
+	}
+	if err := validation.ValidateCategoryName(name); err != nil {
+		return err
+	}
+	category, err := categoryByName(s.financeRepo, s.audit, userID, name)
+	if err != nil {
+		return err
+	}
+	updates["category"] = category.Name
+	updates["category_id"] = category.ID
+	return nil
+}
+
+// categoryByName finds the category with the given name, ignoring case and
+// preferring a top-level one, and creates a top-level category when none exists
+func categoryByName(repo repository.FinanceRepositoryInterface, audit auditor, userID uuid.UUID, name string) (*models.Category, error) {
+	name = strings.TrimSpace(name)
+	categories, err := repo.ListCategories(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list categories")
+	}
+	if category := findCategory(categories, name); category != nil {
+		return category, nil
+	}
+
+	category := &models.Category{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	err = audit.transact(repo, func(repo repository.FinanceRepositoryInterface, audit auditor) error {
+		if err := repo.CreateCategory(category); err != nil {
+			return err
+		}
+		audit.record(userID, EntityCategory, category.ID, AuditCreate, createdChanges(category))
+		return nil
+	})
+	if err != nil {
+		if !stderrors.Is(err, repository.ErrCategoryExists) {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create category")
+		}
+		// Another request created it first
+		if categories, err = repo.ListCategories(userID); err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list categories")
+		}
+		if existing := findCategory(categories, name); existing != nil {
+			return existing, nil
+		}
+		return nil, errors.ErrCategoryExists
+	}
+	return category, nil
+}
+
+// findCategory returns the category with the given name, preferring a top-level one
+func findCategory(categories []models.Category, name string) *models.Category {
+	var found *models.Category
+	for i := range categories {
+		if !strings.EqualFold(categories[i].Name, name) {
+			continue
+		}
+		if categories[i].ParentID == nil {
+			return &categories[i]
+		}
+		if found == nil {
+			found = &categories[i]
+		}
+	}
+	return found
+}
+
+// categoryTotals rolls a month's spending per category up the category tree
+func (s *FinanceService) categoryTotals(userID uuid.UUID, spending map[uuid.UUID]float64) ([]response.CategoryTotalResponse, error) {
+	categories, err := s.financeRepo.ListCategories(userID)
+	if err != nil {
+		return nil, err
+	}
+	tree := newCategoryTree(categories)
+
+	var build func(category *models.Category) *response.CategoryTotalResponse
+	build = func(category *models.Category) *response.CategoryTotalResponse {
+		total := &response.CategoryTotalResponse{
+			CategoryID: category.ID,
+			Name:       category.Name,
+			Total:      spending[category.ID],
+			Children:   []response.CategoryTotalResponse{},
+		}
+		total.Subtotal = total.Total
+		for _, child := range tree.children[category.ID] {
+			if node := build(child); node != nil {
+				total.Subtotal += node.Subtotal
+				total.Children = append(total.Children, *node)
+			}
+		}
+		if total.Subtotal == 0 {
+			return nil
+		}
+		sortCategoryTotals(total.Children)
+		return total
+	}
+
+	totals := []response.CategoryTotalResponse{}
+	for _, root := range tree.children[uuid.Nil] {
+		if node := build(root); node != nil {
+			totals = append(totals, *node)
+		}
+	}
+	sortCategoryTotals(totals)
+	return totals, nil
+}
+
+// sortCategoryTotals orders totals by subtotal, largest first, then by name
+func sortCategoryTotals(totals []response.CategoryTotalResponse) {
+	sort.SliceStable(totals, func(i, j int) bool {
+		if totals[i].Subtotal != totals[j].Subtotal {
+			return totals[i].Subtotal > totals[j].Subtotal
+		}
+		return totals[i].Name < totals[j].Name
+	})
+}
+
+// categoryTree indexes a user's categories by ID and by parent. Top-level
+// categories are listed under uuid.Nil.
+type categoryTree struct {
+	byID     map[uuid.UUID]*models.Category
+	children map[uuid.UUID][]*models.Category
+}
+
+func newCategoryTree(categories []models.Category) *categoryTree {
+	tree := &categoryTree{
+		byID:     make(map[uuid.UUID]*models.Category, len(categories)),
+		children: make(map[uuid.UUID][]*models.Category),
+	}
+	for i := range categories {
+		category := &categories[i]
+		tree.byID[category.ID] = category
+		parentID := uuid.Nil
+		if category.ParentID != nil {
+			parentID = *category.ParentID
+		}
+		tree.children[parentID] = append(tree.children[parentID], category)
+	}
+	return tree
+}
+
+// isDescendant reports whether id sits somewhere below ancestorID
+func (t *categoryTree) isDescendant(id, ancestorID uuid.UUID) bool {
+	for category, seen := t.byID[id], 0; category != nil && category.ParentID != nil && seen < len(t.byID); seen++ {
+		if *category.ParentID == ancestorID {
+			return true
+		}
+		category = t.byID[*category.ParentID]
+	}
+	return false
+}
+
+// siblingNamed reports whether a category other than exceptID under parentID has the name, ignoring case
+func (t *categoryTree) siblingNamed(parentID *uuid.UUID, name string, exceptID uuid.UUID) bool {
+	key := uuid.Nil
+	if parentID != nil {
+		key = *parentID
+	}
+	for _, sibling := range t.children[key] {
+		if sibling.ID != exceptID && strings.EqualFold(sibling.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryError maps repository category errors to API errors
+func categoryError(err error, message string) error {
+	switch {
+	case stderrors.Is(err, repository.ErrCategoryExists):
+		return errors.ErrCategoryExists
+	case stderrors.Is(err, repository.ErrCategoryInUse):
+		return errors.ErrCategoryInUse
+	}
+	return lookupError(err, errors.ErrCategoryNotFound, message)
+}
+
+// categoryResponse converts a category model to its API representation
+func categoryResponse(category models.Category) response.CategoryResponse {
+	return response.CategoryResponse{
+		ID:        category.ID,
+		UserID:    category.UserID,
+		ParentID:  category.ParentID,
+		Name:      category.Name,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestExpensesAreLinkedToCategories(t *testing.T) {
+	finance := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, nil)
+	userID := uuid.New()
+
+	food, err := finance.CreateCategory(userID, &request.CreateCategoryRequest{Name: "Food"})
+	if err != nil {
+		t.Fatalf("CreateCategory food: %v", err)
+	}
+	groceries, err := finance.CreateCategory(userID, &request.CreateCategoryRequest{Name: "Groceries", ParentID: &food.ID})
+	if err != nil {
+		t.Fatalf("CreateCategory groceries: %v", err)
+	}
+	if _, err := finance.CreateCategory(userID, &request.CreateCategoryRequest{Name: "groceries", ParentID: &food.ID}); err != errors.ErrCategoryExists {
+		t.Fatalf("expected ErrCategoryExists for a sibling differing only in case, got %v", err)
+	}
+
+	byID, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{CategoryID: &groceries.ID, Amount: 40, SpentAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateExpense by id: %v", err)
+	}
+	if byID.Category != "Groceries" || byID.CategoryID == nil || *byID.CategoryID != groceries.ID {
+		t.Fatalf("expected the expense to take the category's name, got %+v", byID)
+	}
+	if _, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Travel", CategoryID: &groceries.ID, Amount: 40, SpentAt: time.Now()}); err != errors.ErrCategoryMismatch {
+		t.Fatalf("expected a name naming another category than the id to be rejected, got %v", err)
+	}
+
+	byName, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "travel", Amount: 300, SpentAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateExpense by name: %v", err)
+	}
+	categories, _ := finance.ListCategories(userID)
+	if len(categories) != 3 || byName.CategoryID == nil {
+		t.Fatalf("expected an unknown name to create a top-level category, got %+v", categories)
+	}
+
+	moved, err := finance.UpdateExpense(userID, byName.ID, 0, &request.UpdateExpenseRequest{Category: strPtr("FOOD")})
+	if err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	if moved.Category != "Food" || *moved.CategoryID != food.ID {
+		t.Fatalf("expected an updated name to link the existing category, got %+v", moved)
+	}
+
+	if _, err := finance.UpdateCategory(userID, groceries.ID, &request.UpdateCategoryRequest{Name: strPtr("Supermarket")}); err != nil {
+		t.Fatalf("UpdateCategory rename: %v", err)
+	}
+	renamed, _ := finance.GetExpense(userID, byID.ID)
+	if renamed.Category != "Supermarket" {
+		t.Fatalf("expected the rename to reach linked expenses, got %q", renamed.Category)
+	}
+
+	if _, err := finance.UpdateCategory(userID, food.ID, &request.UpdateCategoryRequest{ParentID: request.SetID(&groceries.ID)}); err == nil {
+		t.Fatal("expected moving a category under its own child to fail")
+	}
+}
+
+func TestNullIDsInUpdateBodiesClearTheLink(t *testing.T) {
+	finance := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, nil)
+	userID := uuid.New()
+
+	food, _ := finance.CreateCategory(userID, &request.CreateCategoryRequest{Name: "Food"})
+	groceries, _ := finance.CreateCategory(userID, &request.CreateCategoryRequest{Name: "Groceries", ParentID: &food.ID})
+	house, _ := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "House", TargetAmount: 20000})
+	deposit, _ := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Deposit", TargetAmount: 8000, ParentGoalID: &house.ID})
+	expense, _ := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Food", Amount: 25, SpentAt: time.Now(), GoalID: &house.ID})
+
+	var rename request.UpdateCategoryRequest
+	if err := json.Unmarshal([]byte(`{"name": "Supermarket"}`), &rename); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	renamed, err := finance.UpdateCategory(userID, groceries.ID, &rename)
+	if err != nil {
+		t.Fatalf("UpdateCategory rename: %v", err)
+	}
+	if renamed.ParentID == nil || *renamed.ParentID != food.ID {
+		t.Fatalf("expected a body without parent_id to keep the parent, got %+v", renamed)
+	}
+
+	var move request.UpdateCategoryRequest
+	if err := json.Unmarshal([]byte(`{"parent_id": null}`), &move); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	moved, err := finance.UpdateCategory(userID, groceries.ID, &move)
+	if err != nil {
+		t.Fatalf("UpdateCategory move: %v", err)
+	}
+	if moved.ParentID != nil {
+		t.Fatalf("expected a null parent_id to move the category to the top level, got %+v", moved)
+	}
+
+	var unlink request.UpdateExpenseRequest
+	if err := json.Unmarshal([]byte(`{"goal_id": null}`), &unlink); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	updated, err := finance.UpdateExpense(userID, expense.ID, 0, &unlink)
+	if err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	if updated.GoalID != nil {
+		t.Fatalf("expected a null goal_id to unlink the goal, got %+v", updated)
+	}
+
+	var detach request.UpdateGoalRequest
+	if err := json.Unmarshal([]byte(`{"parent_goal_id": null}`), &detach); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	detached, err := finance.UpdateGoal(userID, deposit.ID, 0, &detach)
+	if err != nil {
+		t.Fatalf("UpdateGoal: %v", err)
+	}
+	if detached.ParentGoalID != nil {
+		t.Fatalf("expected a null parent_goal_id to detach the goal, got %+v", detached)
+	}
+}
+
+func TestDeleteAndMergeCategoriesRollUpSummary(t *testing.T) {
+	finance := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, nil)
+	userID := uuid.New()
+	day := time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	food, _ := finance.CreateCategory(userID, &request.CreateCategoryRequest{Name: "Food"})
+	groceries, _ := finance.CreateCategory(userID, &request.CreateCategoryRequest{Name: "Groceries", ParentID: &food.ID})
+	dining, _ := finance.CreateCategory(userID, &request.CreateCategoryRequest{Name: "Dining"})
+	takeaway, _ := finance.CreateCategory(userID, &request.CreateCategoryRequest{Name: "Takeaway", ParentID: &dining.ID})
+
+	for _, e := range []struct {
+		categoryID uuid.UUID
+		amount     float64
+	}{{food.ID, 10}, {groceries.ID, 60}, {dining.ID, 30}, {takeaway.ID, 20}} {
+		categoryID := e.categoryID
+		if _, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{CategoryID: &categoryID, Amount: e.amount, SpentAt: day}); err != nil {
+			t.Fatalf("CreateExpense: %v", err)
+		}
+	}
+
+	summary, err := finance.GetMonthlySummary(userID, 2024, 3)
+	if err != nil {
+		t.Fatalf("GetMonthlySummary: %v", err)
+	}
+	if len(summary.CategoryTotals) != 2 || summary.CategoryTotals[0].CategoryID != food.ID {
+		t.Fatalf("expected food then dining at the top level, got %+v", summary.CategoryTotals)
+	}
+	if top := summary.CategoryTotals[0]; top.Total != 10 || top.Subtotal != 70 || len(top.Children) != 1 || top.Children[0].Subtotal != 60 {
+		t.Fatalf("expected food to roll up its groceries, got %+v", top)
+	}
+
+	if err := finance.DeleteCategory(userID, dining.ID, nil); err != errors.ErrCategoryInUse {
+		t.Fatalf("expected ErrCategoryInUse for a category with expenses, got %v", err)
+	}
+	if err := finance.DeleteCategory(userID, dining.ID, &takeaway.ID); err == nil {
+		t.Fatal("expected reassigning to a sub-category of the deleted category to fail")
+	}
+	if err := finance.DeleteCategory(userID, dining.ID, &food.ID); err != nil {
+		t.Fatalf("DeleteCategory with reassign: %v", err)
+	}
+
+	summary, _ = finance.GetMonthlySummary(userID, 2024, 3)
+	if len(summary.CategoryTotals) != 1 {
+		t.Fatalf("expected only food at the top level, got %+v", summary.CategoryTotals)
+	}
+	if top := summary.CategoryTotals[0]; top.Total != 40 || top.Subtotal != 120 || len(top.Children) != 2 {
+		t.Fatalf("expected dining's expenses and sub-category to move to food, got %+v", top)
+	}
+
+	merged, err := finance.MergeCategories(userID, food.ID, &request.MergeCategoriesRequest{SourceIDs: []uuid.UUID{groceries.ID, takeaway.ID}})
+	if err != nil {
+		t.Fatalf("MergeCategories: %v", err)
+	}
+	if merged.MovedExpenses != 2 {
+		t.Fatalf("expected 2 expenses to move, got %d", merged.MovedExpenses)
+	}
+	if _, err := finance.GetCategory(userID, groceries.ID); err != errors.ErrCategoryNotFound {
+		t.Fatalf("expected merged categories to be deleted, got %v", err)
+	}
+}
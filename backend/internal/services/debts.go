@@ -0,0 +1,590 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// maxDebtMonths bounds amortisation schedules and payoff plans; payments that have
+// not cleared the debts in 50 years are treated as never repaying them
+const maxDebtMonths = 600
+
+// Debt payoff strategies
+const (
+	// PayoffSnowball sends extra payments to the smallest balance first
+	PayoffSnowball = "snowball"
+	// PayoffAvalanche sends extra payments to the highest APR first
+	PayoffAvalanche = "avalanche"
+)
+
+// defaultDebtPaymentCategory files debt payments recorded without a category
+const defaultDebtPaymentCategory = "Debt repayment"
+
+// CreateDebt adds a loan or credit card
+func (s *FinanceService) CreateDebt(userID uuid.UUID, req *request.CreateDebtRequest, now time.Time) (*response.DebtResponse, error) {
+	startedOn := startOfDay(now)
+	if req.StartedOn != nil {
+		startedOn = startOfDay(*req.StartedOn)
+	}
+	debt := &models.Debt{
+		ID:             uuid.New(),
+		UserID:         userID,
+		Name:           strings.TrimSpace(req.Name),
+		Kind:           req.Kind,
+		Principal:      roundCents(req.Principal),
+		APR:            req.APR,
+		MinimumPayment: roundCents(req.MinimumPayment),
+		DueDay:         req.DueDay,
+		StartedOn:      startedOn,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := checkDebtSettings(debt); err != nil {
+		return nil, err
+	}
+
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateDebt(debt); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityDebt, debt.ID, AuditCreate, createdChanges(debt))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create debt")
+	}
+
+	resp := debtResponse(*debt, nil, now)
+	return &resp, nil
+}
+
+// ListDebts returns the user's debts with their balances today, oldest first
+func (s *FinanceService) ListDebts(userID uuid.UUID, now time.Time) ([]response.DebtResponse, error) {
+	debts, payments, err := s.debtsWithPayments(userID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]response.DebtResponse, len(debts))
+	for i, debt := range debts {
+		responses[i] = debtResponse(debt, payments[debt.ID], now)
+	}
+	return responses, nil
+}
+
+// GetDebt returns one debt with its balance today
+func (s *FinanceService) GetDebt(userID, debtID uuid.UUID, now time.Time) (*response.DebtResponse, error) {
+	debt, payments, err := s.debtWithPayments(userID, debtID, "Failed to get debt")
+	if err != nil {
+		return nil, err
+	}
+	resp := debtResponse(*debt, payments, now)
+	return &resp, nil
+}
+
+// UpdateDebt edits a debt; changing the principal or start date changes the balance derived from them
+func (s *FinanceService) UpdateDebt(userID, debtID uuid.UUID, req *request.UpdateDebtRequest, now time.Time) (*response.DebtResponse, error) {
+	before, err := s.financeRepo.GetDebt(debtID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrDebtNotFound, "Failed to update debt")
+	}
+
+	updates := make(map[string]interface{})
+	after := *before
+	if req.Name != nil {
+		after.Name = strings.TrimSpace(*req.Name)
+		updates["name"] = after.Name
+	}
+	if req.Kind != nil {
+		after.Kind = *req.Kind
+		updates["kind"] = after.Kind
+	}
+	if req.Principal != nil {
+		after.Principal = roundCents(*req.Principal)
+		updates["principal"] = after.Principal
+	}
+	if req.APR != nil {
+		after.APR = *req.APR
+		updates["apr"] = after.APR
+	}
+	if req.MinimumPayment != nil {
+		after.MinimumPayment = roundCents(*req.MinimumPayment)
+		updates["minimum_payment"] = after.MinimumPayment
+	}
+	if req.DueDay != nil {
+		after.DueDay = *req.DueDay
+		updates["due_day"] = after.DueDay
+	}
+	if req.StartedOn != nil {
+		after.StartedOn = startOfDay(*req.StartedOn)
+		updates["started_on"] = after.StartedOn
+	}
+	if len(updates) == 0 {
+		return nil, errors.ErrInvalidInput
+	}
+	if err := checkDebtSettings(&after); err != nil {
+		return nil, err
+	}
+
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateDebt(debtID, userID, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityDebt, debtID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		return nil, lookupError(err, errors.ErrDebtNotFound, "Failed to update debt")
+	}
+
+	return s.GetDebt(userID, debtID, now)
+}
+
+// DeleteDebt removes a debt; its payments are kept as ordinary expenses
+func (s *FinanceService) DeleteDebt(userID, debtID uuid.UUID) error {
+	before, err := s.financeRepo.GetDebt(debtID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrDebtNotFound, "Failed to delete debt")
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteDebt(debtID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityDebt, debtID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrDebtNotFound, "Failed to delete debt")
+	}
+	return nil
+}
+
+// RecordDebtPayment records a payment towards a debt as an expense linked to it
+func (s *FinanceService) RecordDebtPayment(userID, debtID uuid.UUID, req *request.CreateDebtPaymentRequest) (*response.ExpenseResponse, error) {
+	debt, err := s.financeRepo.GetDebt(debtID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrDebtNotFound, "Failed to record debt payment")
+	}
+	category := strings.TrimSpace(req.Category)
+	if category == "" {
+		category = defaultDebtPaymentCategory
+	}
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		description = debt.Name
+	}
+	return s.CreateExpense(userID, &request.CreateExpenseRequest{
+		Category:    category,
+		Description: description,
+		Amount:      req.Amount,
+		SpentAt:     req.PaidAt,
+		DebtID:      &debtID,
+		// The category was chosen for the payment, so rules must not move it
+		SkipRules: true,
+	})
+}
+
+// ListDebtPayments returns the expenses paying a debt, oldest first
+func (s *FinanceService) ListDebtPayments(userID, debtID uuid.UUID) ([]response.ExpenseResponse, error) {
+	_, payments, err := s.debtWithPayments(userID, debtID, "Failed to list debt payments")
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]response.ExpenseResponse, len(payments))
+	for i, payment := range payments {
+		responses[i] = expenseResponse(payment)
+	}
+	return responses, nil
+}
+
+// GetDebtSchedule amortises a debt from today's balance, paying payment, or the
+// minimum payment when it is nil, on every due date until the debt is cleared
+func (s *FinanceService) GetDebtSchedule(userID, debtID uuid.UUID, payment *float64, now time.Time) (*response.DebtScheduleResponse, error) {
+	debt, payments, err := s.debtWithPayments(userID, debtID, "Failed to get debt schedule")
+	if err != nil {
+		return nil, err
+	}
+	amount := debt.MinimumPayment
+	if payment != nil {
+		if *payment <= 0 {
+			return nil, errors.NewWithDetails(errors.ErrInvalidAmount.Code, errors.ErrInvalidAmount.Message, "Payment must be greater than 0")
+		}
+		amount = roundCents(*payment)
+	}
+
+	today := startOfDay(now)
+	balance, _ := debtBalance(*debt, payments, today)
+	resp := &response.DebtScheduleResponse{
+		DebtID:   debt.ID,
+		Balance:  float64(balance) / 100,
+		Payment:  amount,
+		Schedule: []response.DebtScheduleEntryResponse{},
+	}
+	var interest, paid int64
+	due := nextDueDate(*debt, today)
+	for month := 1; balance > 0; month++ {
+		charge := monthlyInterest(balance, debt.APR)
+		pay := min(toCents(amount), balance+charge)
+		if month > maxDebtMonths || pay <= charge {
+			return nil, debtNotRepaid(fmt.Sprintf("A payment of %.2f does not clear %s within %d years", amount, debt.Name, maxDebtMonths/12))
+		}
+		balance += charge - pay
+		interest += charge
+		paid += pay
+		resp.Schedule = append(resp.Schedule, response.DebtScheduleEntryResponse{
+			Month:     month,
+			DueDate:   due,
+			Payment:   float64(pay) / 100,
+			Interest:  float64(charge) / 100,
+			Principal: float64(pay-charge) / 100,
+			Balance:   float64(balance) / 100,
+		})
+		if balance == 0 {
+			payoff := due
+			resp.PayoffDate = &payoff
+		}
+		due = nextDueDate(*debt, due)
+	}
+	resp.Months = len(resp.Schedule)
+	resp.TotalInterest = float64(interest) / 100
+	resp.TotalPaid = float64(paid) / 100
+	return resp, nil
+}
+
+// PlanDebtPayoff compares the snowball and avalanche strategies for clearing the
+// user's debts from today's balances. Each month every debt gets its minimum
+// payment; the extra amount, and the minimums of debts already cleared, go to the
+// debts in the strategy's order.
+func (s *FinanceService) PlanDebtPayoff(userID uuid.UUID, req *request.DebtPayoffRequest, now time.Time) (*response.DebtPayoffResponse, error) {
+	if req.ExtraMonthly < 0 || math.IsNaN(req.ExtraMonthly) || math.IsInf(req.ExtraMonthly, 0) {
+		return nil, errors.NewWithDetails(errors.ErrInvalidAmount.Code, errors.ErrInvalidAmount.Message, "Extra monthly amount cannot be negative")
+	}
+	debts, payments, err := s.debtsWithPayments(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := startOfDay(now)
+	var open []payoffDebt
+	var start time.Time
+	budget := toCents(req.ExtraMonthly)
+	for _, debt := range debts {
+		balance, _ := debtBalance(debt, payments[debt.ID], today)
+		if balance == 0 {
+			continue
+		}
+		open = append(open, payoffDebt{debt: debt, balance: balance})
+		budget += toCents(debt.MinimumPayment)
+		if due := nextDueDate(debt, today); start.IsZero() || due.Before(start) {
+			start = due
+		}
+	}
+	if start.IsZero() {
+		start = today
+	}
+
+	resp := &response.DebtPayoffResponse{
+		ExtraMonthly:  roundCents(req.ExtraMonthly),
+		MonthlyBudget: float64(budget) / 100,
+	}
+	for _, d := range open {
+		resp.Balance += float64(d.balance) / 100
+	}
+	resp.Balance = roundCents(resp.Balance)
+
+	month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if resp.Snowball, err = simulatePayoff(PayoffSnowball, open, budget, month); err != nil {
+		return nil, err
+	}
+	if resp.Avalanche, err = simulatePayoff(PayoffAvalanche, open, budget, month); err != nil {
+		return nil, err
+	}
+	resp.InterestSaved = roundCents(resp.Snowball.TotalInterest - resp.Avalanche.TotalInterest)
+	resp.Recommended = PayoffSnowball
+	if resp.InterestSaved > 0 {
+		resp.Recommended = PayoffAvalanche
+	}
+	return resp, nil
+}
+
+// payoffDebt is a debt being paid off in a plan. Amounts are in cents.
+type payoffDebt struct {
+	debt      models.Debt
+	balance   int64
+	interest  int64
+	paid      int64
+	clearedIn int
+}
+
+// simulatePayoff runs one strategy over copies of the debts, month by month from
+// the given month, until every debt is cleared
+func simulatePayoff(strategy string, debts []payoffDebt, budget int64, month time.Time) (response.DebtPayoffPlanResponse, error) {
+	plan := response.DebtPayoffPlanResponse{
+		Strategy: strategy,
+		Order:    []uuid.UUID{},
+		Debts:    []response.DebtPayoffResultResponse{},
+		Schedule: []response.DebtPayoffMonthResponse{},
+	}
+	order := make([]*payoffDebt, len(debts))
+	for i := range debts {
+		d := debts[i]
+		order[i] = &d
+	}
+	// The order is fixed from the starting balances; ties keep the oldest debt first
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if strategy == PayoffAvalanche && a.debt.APR != b.debt.APR {
+			return a.debt.APR > b.debt.APR
+		}
+		if a.balance != b.balance {
+			return a.balance < b.balance
+		}
+		return strategy == PayoffSnowball && a.debt.APR > b.debt.APR
+	})
+	for _, d := range order {
+		plan.Order = append(plan.Order, d.debt.ID)
+	}
+
+	var totalInterest, totalPaid int64
+	for index := 1; ; index++ {
+		var owed int64
+		for _, d := range order {
+			owed += d.balance
+		}
+		if owed == 0 {
+			break
+		}
+		if index > maxDebtMonths {
+			return plan, debtNotRepaid(fmt.Sprintf("The minimum payments and extra amount do not clear the debts within %d years", maxDebtMonths/12))
+		}
+
+		entry := response.DebtPayoffMonthResponse{Index: index, Year: month.Year(), Month: int(month.Month()), Debts: []response.DebtPayoffBalanceResponse{}}
+		payments := make([]int64, len(order))
+		charges := make([]int64, len(order))
+		left := budget
+		for i, d := range order {
+			if d.balance == 0 {
+				continue
+			}
+			charges[i] = monthlyInterest(d.balance, d.debt.APR)
+			d.balance += charges[i]
+			payments[i] = min(toCents(d.debt.MinimumPayment), d.balance)
+			d.balance -= payments[i]
+			left -= payments[i]
+		}
+		for i, d := range order {
+			if left == 0 {
+				break
+			}
+			extra := min(left, d.balance)
+			payments[i] += extra
+			d.balance -= extra
+			left -= extra
+		}
+
+		for i, d := range order {
+			if payments[i] == 0 && charges[i] == 0 {
+				continue
+			}
+			d.interest += charges[i]
+			d.paid += payments[i]
+			if d.balance == 0 {
+				d.clearedIn = index
+			}
+			entry.Payment += float64(payments[i]) / 100
+			entry.Interest += float64(charges[i]) / 100
+			entry.Balance += float64(d.balance) / 100
+			entry.Debts = append(entry.Debts, response.DebtPayoffBalanceResponse{
+				DebtID:   d.debt.ID,
+				Payment:  float64(payments[i]) / 100,
+				Interest: float64(charges[i]) / 100,
+				Balance:  float64(d.balance) / 100,
+			})
+			totalInterest += charges[i]
+			totalPaid += payments[i]
+		}
+		entry.Payment = roundCents(entry.Payment)
+		entry.Interest = roundCents(entry.Interest)
+		entry.Balance = roundCents(entry.Balance)
+		plan.Schedule = append(plan.Schedule, entry)
+		month = month.AddDate(0, 1, 0)
+	}
+
+	for _, d := range order {
+		plan.Debts = append(plan.Debts, response.DebtPayoffResultResponse{
+			DebtID:       d.debt.ID,
+			Name:         d.debt.Name,
+			PaidOffMonth: d.clearedIn,
+			Interest:     float64(d.interest) / 100,
+			Paid:         float64(d.paid) / 100,
+		})
+	}
+	plan.Months = len(plan.Schedule)
+	plan.TotalInterest = float64(totalInterest) / 100
+	plan.TotalPaid = float64(totalPaid) / 100
+	return plan, nil
+}
+
+// checkDebtSettings validates a debt before it is stored
+func checkDebtSettings(debt *models.Debt) error {
+	invalid := func(details string) error {
+		return errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid debt", details)
+	}
+	if debt.Name == "" {
+		return invalid("Name is required")
+	}
+	if debt.Principal <= 0 {
+		return invalid("Principal must be greater than 0")
+	}
+	if debt.APR < 0 || debt.APR > 100 {
+		return invalid("APR must be between 0 and 100")
+	}
+	if debt.MinimumPayment <= 0 {
+		return invalid("Minimum payment must be greater than 0")
+	}
+	if debt.DueDay < 1 || debt.DueDay > 31 {
+		return invalid("Due day must be between 1 and 31")
+	}
+	return nil
+}
+
+// checkDebt confirms a debt an expense is linked to belongs to the user
+func (s *FinanceService) checkDebt(userID uuid.UUID, debtID *uuid.UUID) error {
+	if debtID == nil {
+		return nil
+	}
+	if _, err := s.financeRepo.GetDebt(*debtID, userID); err != nil {
+		return lookupError(err, errors.ErrDebtNotFound, "Failed to get debt")
+	}
+	return nil
+}
+
+// debtWithPayments loads one debt and the expenses paying it
+func (s *FinanceService) debtWithPayments(userID, debtID uuid.UUID, message string) (*models.Debt, []models.Expense, error) {
+	debt, err := s.financeRepo.GetDebt(debtID, userID)
+	if err != nil {
+		return nil, nil, lookupError(err, errors.ErrDebtNotFound, message)
+	}
+	all, err := s.financeRepo.ListDebtPayments(userID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrDatabaseError.Code, message)
+	}
+	var payments []models.Expense
+	for _, payment := range all {
+		if *payment.DebtID == debtID {
+			payments = append(payments, payment)
+		}
+	}
+	return debt, payments, nil
+}
+
+// debtsWithPayments loads the user's debts and the expenses paying each of them
+func (s *FinanceService) debtsWithPayments(userID uuid.UUID) ([]models.Debt, map[uuid.UUID][]models.Expense, error) {
+	debts, err := s.financeRepo.ListDebts(userID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list debts")
+	}
+	all, err := s.financeRepo.ListDebtPayments(userID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list debt payments")
+	}
+	payments := make(map[uuid.UUID][]models.Expense)
+	for _, payment := range all {
+		payments[*payment.DebtID] = append(payments[*payment.DebtID], payment)
+	}
+	return debts, payments, nil
+}
+
+// debtBalance replays a debt from its start up to asOf and returns the balance and
+// the interest charged, in cents. Each due date charges a month's interest on what
+// was owed the day before, so payments made on a due date count towards the next
+// month. Payments dated before the start are already part of the principal.
+func debtBalance(debt models.Debt, payments []models.Expense, asOf time.Time) (int64, int64) {
+	start := startOfDay(debt.StartedOn)
+	balance := toCents(debt.Principal)
+	var interest int64
+	i := 0
+	for i < len(payments) && payments[i].SpentAt.Before(start) {
+		i++
+	}
+	for due := nextDueDate(debt, start); !due.After(asOf); due = nextDueDate(debt, due) {
+		for ; i < len(payments) && payments[i].SpentAt.Before(due); i++ {
+			balance -= toCents(payments[i].Amount)
+		}
+		if balance > 0 {
+			charge := monthlyInterest(balance, debt.APR)
+			balance += charge
+			interest += charge
+		}
+	}
+	for ; i < len(payments) && !payments[i].SpentAt.After(asOf); i++ {
+		balance -= toCents(payments[i].Amount)
+	}
+	return max(balance, 0), interest
+}
+
+// nextDueDate returns the first due date of a debt after a day. Due days past the
+// end of a short month fall on its last day.
+func nextDueDate(debt models.Debt, after time.Time) time.Time {
+	for month := time.Date(after.Year(), after.Month(), 1, 0, 0, 0, 0, time.UTC); ; month = month.AddDate(0, 1, 0) {
+		lastDay := month.AddDate(0, 1, -1).Day()
+		due := time.Date(month.Year(), month.Month(), min(debt.DueDay, lastDay), 0, 0, 0, 0, time.UTC)
+		if due.After(after) {
+			return due
+		}
+	}
+}
+
+// monthlyInterest is a month's interest in cents on a balance in cents at an APR in percent
+func monthlyInterest(balance int64, apr float64) int64 {
+	return int64(math.Round(float64(balance) * apr / 1200))
+}
+
+// debtNotRepaid builds the error for payments that never clear a debt
+func debtNotRepaid(details string) error {
+	return errors.NewWithDetails(errors.ErrDebtNotRepaid.Code, errors.ErrDebtNotRepaid.Message, details)
+}
+
+// startOfDay truncates a time to midnight UTC of its day
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// debtResponse converts a debt to its API representation with its balance on now
+func debtResponse(debt models.Debt, payments []models.Expense, now time.Time) response.DebtResponse {
+	today := startOfDay(now)
+	balance, interest := debtBalance(debt, payments, today)
+	var paid int64
+	for _, payment := range payments {
+		if !payment.SpentAt.Before(startOfDay(debt.StartedOn)) && !payment.SpentAt.After(today) {
+			paid += toCents(payment.Amount)
+		}
+	}
+	return response.DebtResponse{
+		ID:             debt.ID,
+		UserID:         debt.UserID,
+		Name:           debt.Name,
+		Kind:           debt.Kind,
+		Principal:      debt.Principal,
+		APR:            debt.APR,
+		MinimumPayment: debt.MinimumPayment,
+		DueDay:         debt.DueDay,
+		StartedOn:      debt.StartedOn,
+		Paid:           float64(paid) / 100,
+		Interest:       float64(interest) / 100,
+		Balance:        float64(balance) / 100,
+		NextDueDate:    nextDueDate(debt, today),
+		CreatedAt:      debt.CreatedAt,
+		UpdatedAt:      debt.UpdatedAt,
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestDebts(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+	now := time.Date(2024, 2, 20, 9, 0, 0, 0, time.UTC)
+
+	startedOn := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	loan, err := finance.CreateDebt(userID, &request.CreateDebtRequest{Name: "Car loan", Kind: models.DebtLoan, Principal: 1000, APR: 12, MinimumPayment: 110, DueDay: 15, StartedOn: &startedOn}, now)
+	if err != nil {
+		t.Fatalf("CreateDebt: %v", err)
+	}
+	if loan.Balance != 1010 || loan.Interest != 10 || !loan.NextDueDate.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected a month's interest to be charged on the 15th, got %+v", loan)
+	}
+
+	// Payments on a due date count after that day's interest
+	payment, err := finance.RecordDebtPayment(userID, loan.ID, &request.CreateDebtPaymentRequest{Amount: 110, PaidAt: time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("RecordDebtPayment: %v", err)
+	}
+	if payment.DebtID == nil || *payment.DebtID != loan.ID || payment.Category != defaultDebtPaymentCategory || payment.Description != "Car loan" {
+		t.Fatalf("expected a linked expense with the default category, got %+v", payment)
+	}
+	loan, err = finance.GetDebt(userID, loan.ID, now)
+	if err != nil {
+		t.Fatalf("GetDebt: %v", err)
+	}
+	if loan.Balance != 900 || loan.Paid != 110 {
+		t.Fatalf("expected the payment to reduce the balance to 900, got %+v", loan)
+	}
+	unknown := uuid.New()
+	if _, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Loans", Amount: 10, SpentAt: now, DebtID: &unknown}); err != errors.ErrDebtNotFound {
+		t.Fatalf("expected an unknown debt to be rejected, got %v", err)
+	}
+
+	schedule, err := finance.GetDebtSchedule(userID, loan.ID, nil, now)
+	if err != nil {
+		t.Fatalf("GetDebtSchedule: %v", err)
+	}
+	last := schedule.Schedule[len(schedule.Schedule)-1]
+	if schedule.Months != 9 || schedule.Schedule[0].Interest != 9 || schedule.Schedule[0].Principal != 101 || last.Balance != 0 || schedule.PayoffDate == nil || !schedule.PayoffDate.Equal(last.DueDate) {
+		t.Fatalf("unexpected amortisation schedule %+v", schedule)
+	}
+	if math.Abs(schedule.TotalPaid-schedule.Balance-schedule.TotalInterest) > 0.001 {
+		t.Fatalf("expected the payments to cover the balance and interest, got %+v", schedule)
+	}
+	low := 9.0
+	_, err = finance.GetDebtSchedule(userID, loan.ID, &low, now)
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected a payment that only covers the interest to be rejected, got %v", err)
+	}
+
+	// Deleting the debt keeps its payment as an ordinary expense
+	if err := finance.DeleteDebt(userID, loan.ID); err != nil {
+		t.Fatalf("DeleteDebt: %v", err)
+	}
+	expense, err := finance.GetExpense(userID, payment.ID)
+	if err != nil || expense.DebtID != nil {
+		t.Fatalf("expected the payment to be kept without its debt, got %+v, %v", expense, err)
+	}
+}
+
+func TestNullDebtIDUnlinksAPayment(t *testing.T) {
+	finance := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, nil)
+	userID := uuid.New()
+	now := time.Date(2024, 2, 20, 9, 0, 0, 0, time.UTC)
+
+	card, err := finance.CreateDebt(userID, &request.CreateDebtRequest{Name: "Card", Kind: models.DebtLoan, Principal: 500, MinimumPayment: 50, DueDay: 1}, now)
+	if err != nil {
+		t.Fatalf("CreateDebt: %v", err)
+	}
+	payment, err := finance.RecordDebtPayment(userID, card.ID, &request.CreateDebtPaymentRequest{Amount: 50, PaidAt: now})
+	if err != nil {
+		t.Fatalf("RecordDebtPayment: %v", err)
+	}
+
+	var unlink request.UpdateExpenseRequest
+	if err := json.Unmarshal([]byte(`{"debt_id": null}`), &unlink); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	expense, err := finance.UpdateExpense(userID, payment.ID, 0, &unlink)
+	if err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	if expense.DebtID != nil {
+		t.Fatalf("expected a null debt_id to unlink the payment, got %+v", expense)
+	}
+	if card, _ = finance.GetDebt(userID, card.ID, now); card.Paid != 0 {
+		t.Fatalf("expected the unlinked payment to stop counting against the debt, got %+v", card)
+	}
+}
+
+func TestPlanDebtPayoff(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	card, err := finance.CreateDebt(userID, &request.CreateDebtRequest{Name: "Credit card", Kind: models.DebtCreditCard, Principal: 3000, APR: 24, MinimumPayment: 60, DueDay: 5}, now)
+	if err != nil {
+		t.Fatalf("CreateDebt card: %v", err)
+	}
+	student, err := finance.CreateDebt(userID, &request.CreateDebtRequest{Name: "Student loan", Kind: models.DebtLoan, Principal: 800, APR: 4, MinimumPayment: 40, DueDay: 20}, now)
+	if err != nil {
+		t.Fatalf("CreateDebt student: %v", err)
+	}
+
+	plan, err := finance.PlanDebtPayoff(userID, &request.DebtPayoffRequest{ExtraMonthly: 200}, now)
+	if err != nil {
+		t.Fatalf("PlanDebtPayoff: %v", err)
+	}
+	if plan.MonthlyBudget != 300 || plan.Balance != 3800 {
+		t.Fatalf("expected a budget of 300 against 3800 owed, got %+v", plan)
+	}
+	if plan.Snowball.Order[0] != student.ID || plan.Avalanche.Order[0] != card.ID {
+		t.Fatalf("expected snowball to start with the smaller balance and avalanche with the higher APR, got %v and %v", plan.Snowball.Order, plan.Avalanche.Order)
+	}
+	if plan.Recommended != PayoffAvalanche || plan.InterestSaved <= 0 || plan.Snowball.Debts[0].PaidOffMonth >= plan.Avalanche.Debts[1].PaidOffMonth {
+		t.Fatalf("expected the avalanche to save interest and the snowball to clear a debt sooner, got %+v", plan)
+	}
+	for _, p := range []struct {
+		name          string
+		months        int
+		paid          float64
+		interest      float64
+		lastBalance   float64
+		firstPayments float64
+	}{
+		{"snowball", plan.Snowball.Months, plan.Snowball.TotalPaid, plan.Snowball.TotalInterest, plan.Snowball.Schedule[len(plan.Snowball.Schedule)-1].Balance, plan.Snowball.Schedule[0].Payment},
+		{"avalanche", plan.Avalanche.Months, plan.Avalanche.TotalPaid, plan.Avalanche.TotalInterest, plan.Avalanche.Schedule[len(plan.Avalanche.Schedule)-1].Balance, plan.Avalanche.Schedule[0].Payment},
+	} {
+		if p.lastBalance != 0 || p.firstPayments != 300 || math.Abs(p.paid-3800-p.interest) > 0.001 {
+			t.Fatalf("%s: expected the whole budget paid until every debt is cleared, got %+v", p.name, p)
+		}
+	}
+
+	// Without the extra amount the minimums never catch up with the card's interest
+	minimum := 20.0
+	if _, err := finance.UpdateDebt(userID, card.ID, &request.UpdateDebtRequest{MinimumPayment: &minimum}, now); err != nil {
+		t.Fatalf("UpdateDebt: %v", err)
+	}
+	_, err = finance.PlanDebtPayoff(userID, &request.DebtPayoffRequest{}, now)
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected minimums below the interest to be rejected, got %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package services
 
 import (
+	stderrors "errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"finance-management/internal/dto/request"
@@ -11,26 +15,64 @@ import (
 	"finance-management/internal/validation"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // FinanceService handles business logic for finance operations
 type FinanceService struct {
 	financeRepo repository.FinanceRepositoryInterface
+	ruleRepo    repository.RuleRepositoryInterface
+	suggestions *SuggestionService
+	audit       auditor
 }
 
-// NewFinanceService creates a new finance service
-func NewFinanceService(financeRepo repository.FinanceRepositoryInterface) *FinanceService {
+// NewFinanceService creates a new finance service. Mutations are recorded in
+// auditRepo and new expenses are categorised by the rules in ruleRepo unless
+// the respective repository is nil.
+func NewFinanceService(financeRepo repository.FinanceRepositoryInterface, ruleRepo repository.RuleRepositoryInterface, auditRepo repository.AuditRepositoryInterface) *FinanceService {
 	return &FinanceService{
 		financeRepo: financeRepo,
+		ruleRepo:    ruleRepo,
+		audit:       newAuditor(auditRepo),
 	}
 }
 
+// WithSuggestions returns a copy of the service that keeps the category
+// suggestion models up to date as expenses change
+func (s *FinanceService) WithSuggestions(suggestions *SuggestionService) *FinanceService {
+	c := *s
+	c.suggestions = suggestions
+	return &c
+}
+
+// WithActor returns a copy of the service that attributes audit entries to actor
+func (s *FinanceService) WithActor(actor string) *FinanceService {
+	c := *s
+	c.audit.actor = actor
+	return &c
+}
+
+// transact runs fn in a transaction together with the audit entries it records; see
+// auditor.transact
+func (s *FinanceService) transact(fn func(tx *FinanceService) error) error {
+	return s.audit.transact(s.financeRepo, func(repo repository.FinanceRepositoryInterface, audit auditor) error {
+		c := *s
+		c.financeRepo = repo
+		c.audit = audit
+		return fn(&c)
+	})
+}
+
 // CreateIncome creates a new income entry
 func (s *FinanceService) CreateIncome(userID uuid.UUID, req *request.CreateIncomeRequest) (*response.IncomeResponse, error) {
 	// Validate amount
 	if err := validation.ValidateAmount(req.Amount); err != nil {
 		return nil, err
 	}
+	tags := normalizeTags(req.Tags)
+	if err := validation.ValidateTags(tags); err != nil {
+		return nil, err
+	}
 
 	// Create income model
 	income := &models.Income{
@@ -39,28 +81,47 @@ func (s *FinanceService) CreateIncome(userID uuid.UUID, req *request.CreateIncom
 		Source:     req.Source,
 		Amount:     req.Amount,
 		ReceivedAt: req.ReceivedAt,
+		SecurityID: req.SecurityID,
+		Tags:       tags,
 		CreatedAt:  time.Now().UTC(),
 	}
+	if err := s.checkSecurity(userID, income.SecurityID); err != nil {
+		return nil, err
+	}
 
-	// Save to database
-	if err := s.financeRepo.CreateIncome(income); err != nil {
+	// Save to database together with the contributions the user's funding rules take from it
+	rules, err := s.enabledFundingRules(userID, models.FundingPercentOfIncome)
+	if err != nil {
+		return nil, err
+	}
+	var funding []response.GoalContributionResponse
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateIncome(income); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityIncome, income.ID, AuditCreate, createdChanges(income))
+		funding, err = tx.fund(userID, rules, &income.ID, income.ReceivedAt, incomeFunding(income))
+		return err
+	})
+	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create income")
 	}
 
 	// Convert to response
-	return &response.IncomeResponse{
-		ID:         income.ID,
-		UserID:     income.UserID,
-		Source:     income.Source,
-		Amount:     income.Amount,
-		ReceivedAt: income.ReceivedAt,
-		CreatedAt:  income.CreatedAt,
-	}, nil
+	resp := incomeResponse(*income)
+	resp.Funding = funding
+	return &resp, nil
 }
 
-// ListIncomes retrieves user's income entries
-func (s *FinanceService) ListIncomes(userID uuid.UUID, limit int) ([]response.IncomeResponse, error) {
-	incomes, err := s.financeRepo.ListIncomes(userID, limit)
+// ListIncomes retrieves user's income entries, restricted to those matching filter when it names tags
+func (s *FinanceService) ListIncomes(userID uuid.UUID, limit int, filter repository.TagFilter) ([]response.IncomeResponse, error) {
+	var incomes []models.Income
+	var err error
+	if filter.Tags = normalizeTags(filter.Tags); len(filter.Tags) > 0 {
+		incomes, err = s.financeRepo.ListTaggedIncomes(userID, filter, limit)
+	} else {
+		incomes, err = s.financeRepo.ListIncomes(userID, limit)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list incomes")
 	}
@@ -68,21 +129,51 @@ func (s *FinanceService) ListIncomes(userID uuid.UUID, limit int) ([]response.In
 	// Convert to response
 	responses := make([]response.IncomeResponse, len(incomes))
 	for i, income := range incomes {
-		responses[i] = response.IncomeResponse{
-			ID:         income.ID,
-			UserID:     income.UserID,
-			Source:     income.Source,
-			Amount:     income.Amount,
-			ReceivedAt: income.ReceivedAt,
-			CreatedAt:  income.CreatedAt,
-		}
+		responses[i] = incomeResponse(income)
 	}
 
 	return responses, nil
 }
 
-// UpdateIncome updates an existing income entry
-func (s *FinanceService) UpdateIncome(userID, incomeID uuid.UUID, req *request.UpdateIncomeRequest) error {
+// UpdateIncome updates an existing income entry. A non-zero version must match the
+// stored one; otherwise a 412 error carrying the current income is returned
+func (s *FinanceService) UpdateIncome(userID, incomeID uuid.UUID, version int, req *request.UpdateIncomeRequest) (*response.IncomeResponse, error) {
+	updates, err := incomeUpdates(req)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.financeRepo.GetIncome(incomeID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrIncomeNotFound, "Failed to update income")
+	}
+	if version > 0 && before.Version != version {
+		return nil, errors.NewPreconditionFailed(incomeResponse(*before))
+	}
+	if req.SecurityID.Set {
+		if err := s.checkSecurity(userID, req.SecurityID.ID); err != nil {
+			return nil, err
+		}
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateIncome(incomeID, userID, version, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityIncome, incomeID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		if stderrors.Is(err, repository.ErrVersionConflict) {
+			return nil, s.incomeConflict(userID, incomeID)
+		}
+		return nil, lookupError(err, errors.ErrIncomeNotFound, "Failed to update income")
+	}
+
+	return s.GetIncome(userID, incomeID)
+}
+
+// incomeUpdates validates an income update request and returns the columns to change
+func incomeUpdates(req *request.UpdateIncomeRequest) (map[string]interface{}, error) {
 	updates := make(map[string]interface{})
 
 	if req.Source != nil {
@@ -90,29 +181,64 @@ func (s *FinanceService) UpdateIncome(userID, incomeID uuid.UUID, req *request.U
 	}
 	if req.Amount != nil {
 		if err := validation.ValidateAmount(*req.Amount); err != nil {
-			return err
+			return nil, err
 		}
 		updates["amount"] = *req.Amount
 	}
 	if req.ReceivedAt != nil {
 		updates["received_at"] = *req.ReceivedAt
 	}
+	if req.SecurityID.Set {
+		updates["security_id"] = req.SecurityID.ID
+	}
+	if req.Tags != nil {
+		tags, err := tagUpdate(*req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		updates["tags"] = tags
+	}
 
 	if len(updates) == 0 {
-		return errors.ErrInvalidInput
+		return nil, errors.ErrInvalidInput
 	}
+	return updates, nil
+}
 
-	if err := s.financeRepo.UpdateIncome(incomeID, userID, updates); err != nil {
-		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to update income")
+// GetIncome retrieves a single income
+func (s *FinanceService) GetIncome(userID, incomeID uuid.UUID) (*response.IncomeResponse, error) {
+	income, err := s.financeRepo.GetIncome(incomeID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrIncomeNotFound, "Failed to get income")
 	}
+	resp := incomeResponse(*income)
+	return &resp, nil
+}
 
-	return nil
+// incomeConflict builds the 412 error for an update that lost a race with another writer
+func (s *FinanceService) incomeConflict(userID, incomeID uuid.UUID) error {
+	current, err := s.GetIncome(userID, incomeID)
+	if err != nil {
+		return err
+	}
+	return errors.NewPreconditionFailed(*current)
 }
 
-// DeleteIncome deletes an income entry
+// DeleteIncome moves an income entry to the trash
 func (s *FinanceService) DeleteIncome(userID, incomeID uuid.UUID) error {
-	if err := s.financeRepo.DeleteIncome(incomeID, userID); err != nil {
-		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to delete income")
+	before, err := s.financeRepo.GetIncome(incomeID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrIncomeNotFound, "Failed to delete income")
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteIncome(incomeID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityIncome, incomeID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrIncomeNotFound, "Failed to delete income")
 	}
 	return nil
 }
@@ -123,40 +249,131 @@ func (s *FinanceService) CreateExpense(userID uuid.UUID, req *request.CreateExpe
 	if err := validation.ValidateAmount(req.Amount); err != nil {
 		return nil, err
 	}
+	tags := normalizeTags(req.Tags)
+	if err := validation.ValidateTags(tags); err != nil {
+		return nil, err
+	}
 
 	// Create expense model
 	expense := &models.Expense{
 		ID:          uuid.New(),
 		UserID:      userID,
 		Category:    req.Category,
+		CategoryID:  req.CategoryID,
 		Description: req.Description,
 		Amount:      req.Amount,
 		SpentAt:     req.SpentAt,
 		GoalID:      req.GoalID,
+		MerchantID:  req.MerchantID,
+		DebtID:      req.DebtID,
+		Tags:        tags,
 		CreatedAt:   time.Now().UTC(),
 	}
 
-	// Save to database
-	if err := s.financeRepo.CreateExpense(expense); err != nil {
+	// Link the expense to the merchant its description matches unless one was chosen
+	if expense.MerchantID != nil {
+		if err := s.checkMerchant(userID, expense.MerchantID); err != nil {
+			return nil, err
+		}
+	} else {
+		merchantID, err := s.resolveMerchant(userID, expense.Description)
+		if err != nil {
+			return nil, err
+		}
+		expense.MerchantID = merchantID
+	}
+	if err := s.checkDebt(userID, expense.DebtID); err != nil {
+		return nil, err
+	}
+
+	// Name the category chosen by ID so rules see it
+	if expense.CategoryID != nil {
+		if err := s.nameExpenseCategory(userID, expense); err != nil {
+			return nil, err
+		}
+	}
+
+	// Let the user's rules set the category and goal; a rule's category replaces the chosen one
+	var applied []uuid.UUID
+	if !req.SkipRules {
+		named := expense.Category
+		var err error
+		if applied, err = s.applyExpenseRules(userID, expense); err != nil {
+			return nil, err
+		}
+		if expense.Category != named {
+			expense.CategoryID = nil
+		}
+	}
+	if err := s.linkExpenseCategory(userID, expense); err != nil {
+		return nil, err
+	}
+
+	// Save to database together with the round-ups the user's funding rules take from it
+	rules, err := s.enabledFundingRules(userID, models.FundingRoundUp)
+	if err != nil {
+		return nil, err
+	}
+	var funding []response.GoalContributionResponse
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateExpense(expense); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityExpense, expense.ID, AuditCreate, createdChanges(expense))
+		funding, err = tx.fund(userID, rules, &expense.ID, expense.SpentAt, expenseFunding(expense))
+		return err
+	})
+	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create expense")
 	}
+	// Inside a batch, rule statistics and suggestions learn from the expense only once it is stored
+	s.audit.afterCommit(func() {
+		s.recordRuleMatches(userID, applied)
+		s.suggestions.ObserveExpense(userID, nil, expense)
+	})
 
 	// Convert to response
-	return &response.ExpenseResponse{
-		ID:          expense.ID,
-		UserID:      expense.UserID,
-		Category:    expense.Category,
-		Description: expense.Description,
-		Amount:      expense.Amount,
-		SpentAt:     expense.SpentAt,
-		GoalID:      expense.GoalID,
-		CreatedAt:   expense.CreatedAt,
-	}, nil
+	resp := expenseResponse(*expense)
+	resp.AppliedRules = applied
+	resp.Funding = funding
+	return &resp, nil
+}
+
+// applyExpenseRules runs the user's enabled rules against a new expense and returns the ids of those that matched
+func (s *FinanceService) applyExpenseRules(userID uuid.UUID, expense *models.Expense) ([]uuid.UUID, error) {
+	if s.ruleRepo == nil {
+		return nil, nil
+	}
+	rules, err := s.ruleRepo.ListRules(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load expense rules")
+	}
+	rules = enabledRules(rules)
+	active, err := ruleGoals(s.financeRepo, userID, rules)
+	if err != nil {
+		return nil, err
+	}
+	return applyRules(compileRules(rules, active), expense), nil
+}
+
+// recordRuleMatches counts one match for each rule that categorised a new expense
+func (s *FinanceService) recordRuleMatches(userID uuid.UUID, ruleIDs []uuid.UUID) {
+	matches := make(map[uuid.UUID]int, len(ruleIDs))
+	for _, id := range ruleIDs {
+		matches[id]++
+	}
+	recordRuleMatches(s.ruleRepo, userID, matches)
 }
 
 // ListExpenses retrieves user's expense entries
-func (s *FinanceService) ListExpenses(userID uuid.UUID, limit int) ([]response.ExpenseResponse, error) {
-	expenses, err := s.financeRepo.ListExpenses(userID, limit)
+func (s *FinanceService) ListExpenses(userID uuid.UUID, limit int, filter repository.TagFilter) ([]response.ExpenseResponse, error) {
+	var expenses []models.Expense
+	var err error
+	if filter.Tags = normalizeTags(filter.Tags); len(filter.Tags) > 0 {
+		expenses, err = s.financeRepo.ListTaggedExpenses(userID, filter, limit)
+	} else {
+		expenses, err = s.financeRepo.ListExpenses(userID, limit)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list expenses")
 	}
@@ -164,60 +381,148 @@ func (s *FinanceService) ListExpenses(userID uuid.UUID, limit int) ([]response.E
 	// Convert to response
 	responses := make([]response.ExpenseResponse, len(expenses))
 	for i, expense := range expenses {
-		responses[i] = response.ExpenseResponse{
-			ID:          expense.ID,
-			UserID:      expense.UserID,
-			Category:    expense.Category,
-			Description: expense.Description,
-			Amount:      expense.Amount,
-			SpentAt:     expense.SpentAt,
-			GoalID:      expense.GoalID,
-			CreatedAt:   expense.CreatedAt,
-		}
+		responses[i] = expenseResponse(expense)
 	}
 
 	return responses, nil
 }
 
-// UpdateExpense updates an existing expense entry
-func (s *FinanceService) UpdateExpense(userID, expenseID uuid.UUID, req *request.UpdateExpenseRequest) error {
+// UpdateExpense updates an existing expense entry. A non-zero version must match the
+// stored one; otherwise a 412 error carrying the current expense is returned
+func (s *FinanceService) UpdateExpense(userID, expenseID uuid.UUID, version int, req *request.UpdateExpenseRequest) (*response.ExpenseResponse, error) {
+	updates, err := expenseUpdates(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.MerchantID.Set {
+		if err := s.checkMerchant(userID, req.MerchantID.ID); err != nil {
+			return nil, err
+		}
+	}
+	if req.DebtID.Set {
+		if err := s.checkDebt(userID, req.DebtID.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.linkCategoryUpdates(userID, updates); err != nil {
+		return nil, err
+	}
+
+	before, err := s.financeRepo.GetExpense(expenseID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrExpenseNotFound, "Failed to update expense")
+	}
+	if version > 0 && before.Version != version {
+		return nil, errors.NewPreconditionFailed(expenseResponse(*before))
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateExpense(expenseID, userID, version, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityExpense, expenseID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		if stderrors.Is(err, repository.ErrVersionConflict) {
+			return nil, s.expenseConflict(userID, expenseID)
+		}
+		return nil, lookupError(err, errors.ErrExpenseNotFound, "Failed to update expense")
+	}
+
+	after, err := s.financeRepo.GetExpense(expenseID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrExpenseNotFound, "Failed to get expense")
+	}
+	s.audit.afterCommit(func() { s.suggestions.ObserveExpense(userID, before, after) })
+	resp := expenseResponse(*after)
+	return &resp, nil
+}
+
+// expenseUpdates validates an expense update request and returns the columns to change
+func expenseUpdates(req *request.UpdateExpenseRequest) (map[string]interface{}, error) {
 	updates := make(map[string]interface{})
 
 	if req.Category != nil {
+		if err := validation.ValidateCategoryName(*req.Category); err != nil {
+			return nil, err
+		}
 		updates["category"] = *req.Category
 	}
+	if req.CategoryID != nil {
+		// The category's name replaces any name sent alongside it
+		updates["category_id"] = *req.CategoryID
+	}
 	if req.Description != nil {
 		updates["description"] = *req.Description
 	}
 	if req.Amount != nil {
 		if err := validation.ValidateAmount(*req.Amount); err != nil {
-			return err
+			return nil, err
 		}
 		updates["amount"] = *req.Amount
 	}
 	if req.SpentAt != nil {
 		updates["spent_at"] = *req.SpentAt
 	}
-	if req.GoalID != nil {
-		updates["goal_id"] = req.GoalID
+	if req.GoalID.Set {
+		updates["goal_id"] = req.GoalID.ID
+	}
+	if req.MerchantID.Set {
+		updates["merchant_id"] = req.MerchantID.ID
+	}
+	if req.DebtID.Set {
+		updates["debt_id"] = req.DebtID.ID
+	}
+	if req.Tags != nil {
+		tags, err := tagUpdate(*req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		updates["tags"] = tags
 	}
 
 	if len(updates) == 0 {
-		return errors.ErrInvalidInput
+		return nil, errors.ErrInvalidInput
 	}
+	return updates, nil
+}
 
-	if err := s.financeRepo.UpdateExpense(expenseID, userID, updates); err != nil {
-		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to update expense")
+// GetExpense retrieves a single expense
+func (s *FinanceService) GetExpense(userID, expenseID uuid.UUID) (*response.ExpenseResponse, error) {
+	expense, err := s.financeRepo.GetExpense(expenseID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrExpenseNotFound, "Failed to get expense")
 	}
+	resp := expenseResponse(*expense)
+	return &resp, nil
+}
 
-	return nil
+// expenseConflict builds the 412 error for an update that lost a race with another writer
+func (s *FinanceService) expenseConflict(userID, expenseID uuid.UUID) error {
+	current, err := s.GetExpense(userID, expenseID)
+	if err != nil {
+		return err
+	}
+	return errors.NewPreconditionFailed(*current)
 }
 
-// DeleteExpense deletes an expense entry
+// DeleteExpense moves an expense entry to the trash
 func (s *FinanceService) DeleteExpense(userID, expenseID uuid.UUID) error {
-	if err := s.financeRepo.DeleteExpense(expenseID, userID); err != nil {
-		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to delete expense")
+	before, err := s.financeRepo.GetExpense(expenseID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrExpenseNotFound, "Failed to delete expense")
 	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteExpense(expenseID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityExpense, expenseID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrExpenseNotFound, "Failed to delete expense")
+	}
+	s.audit.afterCommit(func() { s.suggestions.ObserveExpense(userID, before, nil) })
 	return nil
 }
 
@@ -245,46 +550,92 @@ func (s *FinanceService) CreateGoal(userID uuid.UUID, req *request.CreateGoalReq
 		UserID:       userID,
 		Name:         req.Name,
 		Description:  req.Description,
-		Category:     req.Category,
 		TargetAmount: req.TargetAmount,
 		TargetDate:   req.TargetDate,
 		ParentGoalID: req.ParentGoalID,
 		IsMainGoal:   req.IsMainGoal,
-		CreatedAt:    time.Now().UTC(),
-		UpdatedAt:    time.Now().UTC(),
+		// Goals are allocated surplus first and with equal shares unless told otherwise
+		AllocationPriority: 1,
+		AllocationWeight:   1,
+		Status:             models.GoalActive,
+		CreatedAt:          time.Now().UTC(),
+		UpdatedAt:          time.Now().UTC(),
+	}
+	if req.AllocationPriority != nil {
+		goal.AllocationPriority = *req.AllocationPriority
+	}
+	if req.AllocationWeight != nil {
+		goal.AllocationWeight = *req.AllocationWeight
+	}
+	if req.CategoryID != nil || strings.TrimSpace(req.Category) != "" {
+		category, err := s.goalCategoryFor(userID, req.CategoryID, req.Category)
+		if err != nil {
+			return nil, err
+		}
+		goal.Category, goal.CategoryID = category.Name, &category.ID
 	}
 
 	// Save to database
-	if err := s.financeRepo.CreateGoal(goal); err != nil {
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateGoal(goal); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoal, goal.ID, AuditCreate, createdChanges(goal))
+		return nil
+	})
+	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create goal")
 	}
 
 	// Convert to response
-	return &response.GoalResponse{
-		ID:           goal.ID,
-		UserID:       goal.UserID,
-		Name:         goal.Name,
-		Description:  goal.Description,
-		Category:     goal.Category,
-		TargetAmount: goal.TargetAmount,
-		TargetDate:   goal.TargetDate,
-		ParentGoalID: goal.ParentGoalID,
-		IsMainGoal:   goal.IsMainGoal,
-		CreatedAt:    goal.CreatedAt,
-		UpdatedAt:    goal.UpdatedAt,
-	}, nil
+	resp := goalResponse(*goal)
+	return &resp, nil
 }
 
-// ListGoalsWithProgress retrieves user's goals with progress information
-func (s *FinanceService) ListGoalsWithProgress(userID uuid.UUID) ([]response.GoalWithProgressResponse, error) {
+// ListGoalsWithProgress retrieves user's goals with progress information and their
+// contribution pace, averaged over the paceWindow months before now. Only goals in
+// one of statuses are listed, or in one of DefaultGoalStatuses when it is empty.
+func (s *FinanceService) ListGoalsWithProgress(userID uuid.UUID, paceWindow int, statuses []string, now time.Time) ([]response.GoalWithProgressResponse, error) {
+	if paceWindow == 0 {
+		paceWindow = DefaultPaceWindow
+	}
+	if paceWindow < 1 || paceWindow > MaxPaceWindow {
+		return nil, errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Invalid pace window",
+			fmt.Sprintf("The pace window must be between 1 and %d months", MaxPaceWindow),
+		)
+	}
+	for _, status := range statuses {
+		if _, ok := goalStatusTransitions[status]; !ok {
+			return nil, errors.NewWithDetails(
+				errors.ErrInvalidInput.Code,
+				"Invalid goal status",
+				"status must be active, paused, completed, archived or abandoned",
+			)
+		}
+	}
+	if len(statuses) == 0 {
+		statuses = DefaultGoalStatuses
+	}
+
 	goalsWithProgress, err := s.financeRepo.ListGoalsWithProgress(userID)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list goals with progress")
 	}
+	today := startOfDay(now)
+	windowStart := today.AddDate(0, -paceWindow, 0)
+	recent, err := s.financeRepo.SumGoalContributions(userID, windowStart, today.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load recent goal contributions")
+	}
 
 	// Convert to response
-	responses := make([]response.GoalWithProgressResponse, len(goalsWithProgress))
-	for i, goalWithProgress := range goalsWithProgress {
+	responses := []response.GoalWithProgressResponse{}
+	for _, goalWithProgress := range goalsWithProgress {
+		if !slices.Contains(statuses, goalWithProgress.Goal.Status) {
+			continue
+		}
 		// Calculate progress percentage
 		progress := 0.0
 		if goalWithProgress.Goal.TargetAmount > 0 {
@@ -294,92 +645,218 @@ func (s *FinanceService) ListGoalsWithProgress(userID uuid.UUID) ([]response.Goa
 			}
 		}
 
-		responses[i] = response.GoalWithProgressResponse{
-			Goal: response.GoalResponse{
-				ID:           goalWithProgress.Goal.ID,
-				UserID:       goalWithProgress.Goal.UserID,
-				Name:         goalWithProgress.Goal.Name,
-				Description:  goalWithProgress.Goal.Description,
-				Category:     goalWithProgress.Goal.Category,
-				TargetAmount: goalWithProgress.Goal.TargetAmount,
-				TargetDate:   goalWithProgress.Goal.TargetDate,
-				ParentGoalID: goalWithProgress.Goal.ParentGoalID,
-				IsMainGoal:   goalWithProgress.Goal.IsMainGoal,
-				CreatedAt:    goalWithProgress.Goal.CreatedAt,
-				UpdatedAt:    goalWithProgress.Goal.UpdatedAt,
-			},
+		resp := response.GoalWithProgressResponse{
+			Goal:           goalResponse(goalWithProgress.Goal),
 			ContributedSum: goalWithProgress.ContributedSum,
 			ExpenseSum:     goalWithProgress.ExpenseSum,
 			Progress:       progress,
 		}
+		applyGoalPace(&resp, goalWithProgress, recent[goalWithProgress.Goal.ID], paceWindow, windowStart, now)
+		responses = append(responses, resp)
 	}
 
 	return responses, nil
 }
 
-// UpdateGoal updates an existing goal
-func (s *FinanceService) UpdateGoal(userID, goalID uuid.UUID, req *request.UpdateGoalRequest) error {
+// UpdateGoal updates an existing goal. A non-zero version must match the
+// stored one; otherwise a 412 error carrying the current goal is returned
+func (s *FinanceService) UpdateGoal(userID, goalID uuid.UUID, version int, req *request.UpdateGoalRequest) (*response.GoalResponse, error) {
 	updates := make(map[string]interface{})
 
 	if req.Name != nil {
 		if err := validation.ValidateGoalName(*req.Name); err != nil {
-			return err
+			return nil, err
 		}
 		updates["name"] = *req.Name
 	}
 	if req.Description != nil {
 		if err := validation.ValidateGoalDescription(*req.Description); err != nil {
-			return err
+			return nil, err
 		}
 		updates["description"] = *req.Description
 	}
-	if req.Category != nil {
-		updates["category"] = *req.Category
+	if req.CategoryID.Set || req.Category != nil {
+		if err := s.linkGoalCategoryUpdates(userID, req, updates); err != nil {
+			return nil, err
+		}
 	}
 	if req.TargetAmount != nil {
 		if err := validation.ValidateGoalTarget(*req.TargetAmount); err != nil {
-			return err
+			return nil, err
 		}
 		updates["target_amount"] = *req.TargetAmount
 	}
 	if req.TargetDate != nil {
 		if err := validation.ValidateGoalDate(*req.TargetDate); err != nil {
-			return err
+			return nil, err
 		}
 		updates["target_date"] = *req.TargetDate
 	}
-	if req.ParentGoalID != nil {
-		updates["parent_goal_id"] = req.ParentGoalID
+	if req.ParentGoalID.Set {
+		updates["parent_goal_id"] = req.ParentGoalID.ID
 	}
 	if req.IsMainGoal != nil {
 		updates["is_main_goal"] = *req.IsMainGoal
 	}
+	if req.AllocationPriority != nil {
+		updates["allocation_priority"] = *req.AllocationPriority
+	}
+	if req.AllocationWeight != nil {
+		updates["allocation_weight"] = *req.AllocationWeight
+	}
 
 	if len(updates) == 0 {
-		return errors.ErrInvalidInput
+		return nil, errors.ErrInvalidInput
+	}
+
+	before, err := s.financeRepo.GetGoal(goalID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrGoalNotFound, "Failed to update goal")
+	}
+	if version > 0 && before.Version != version {
+		return nil, errors.NewPreconditionFailed(goalResponse(*before))
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateGoal(goalID, userID, version, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoal, goalID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		if stderrors.Is(err, repository.ErrVersionConflict) {
+			return nil, s.goalConflict(userID, goalID)
+		}
+		return nil, lookupError(err, errors.ErrGoalNotFound, "Failed to update goal")
 	}
 
-	if err := s.financeRepo.UpdateGoal(goalID, userID, updates); err != nil {
-		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to update goal")
+	return s.GetGoal(userID, goalID)
+}
+
+// GetGoal retrieves a single goal
+func (s *FinanceService) GetGoal(userID, goalID uuid.UUID) (*response.GoalResponse, error) {
+	goal, err := s.financeRepo.GetGoal(goalID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrGoalNotFound, "Failed to get goal")
 	}
+	resp := goalResponse(*goal)
+	return &resp, nil
+}
 
-	return nil
+// goalConflict builds the 412 error for an update that lost a race with another writer
+func (s *FinanceService) goalConflict(userID, goalID uuid.UUID) error {
+	current, err := s.GetGoal(userID, goalID)
+	if err != nil {
+		return err
+	}
+	return errors.NewPreconditionFailed(*current)
 }
 
-// DeleteGoal deletes a goal
+// DeleteGoal moves a goal, its sub-goals and their contributions to the trash
 func (s *FinanceService) DeleteGoal(userID, goalID uuid.UUID) error {
-	if err := s.financeRepo.DeleteGoal(goalID, userID); err != nil {
-		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to delete goal")
+	before, err := s.financeRepo.GetGoal(goalID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrGoalNotFound, "Failed to delete goal")
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteGoal(goalID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoal, goalID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrGoalNotFound, "Failed to delete goal")
+	}
+	return nil
+}
+
+// RestoreIncome takes an income entry out of the trash
+func (s *FinanceService) RestoreIncome(userID, incomeID uuid.UUID) error {
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.RestoreIncome(incomeID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityIncome, incomeID, AuditRestore, nil)
+		return nil
+	})
+	if err != nil {
+		return restoreError(err, "Failed to restore income")
+	}
+	return nil
+}
+
+// RestoreExpense takes an expense entry out of the trash
+func (s *FinanceService) RestoreExpense(userID, expenseID uuid.UUID) error {
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.RestoreExpense(expenseID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityExpense, expenseID, AuditRestore, nil)
+		return nil
+	})
+	if err != nil {
+		return restoreError(err, "Failed to restore expense")
+	}
+	if restored, err := s.financeRepo.GetExpense(expenseID, userID); err == nil {
+		s.audit.afterCommit(func() { s.suggestions.ObserveExpense(userID, nil, restored) })
+	}
+	return nil
+}
+
+// RestoreGoal takes a goal out of the trash together with the sub-goals and
+// contributions that were deleted with it
+func (s *FinanceService) RestoreGoal(userID, goalID uuid.UUID) error {
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.RestoreGoal(goalID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoal, goalID, AuditRestore, nil)
+		return nil
+	})
+	if err != nil {
+		return restoreError(err, "Failed to restore goal")
 	}
 	return nil
 }
 
+// lookupError maps a missing row to notFound and wraps any other repository error
+func lookupError(err error, notFound *errors.AppError, message string) error {
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+	return errors.Wrap(err, errors.ErrDatabaseError.Code, message)
+}
+
+// restoreError maps repository restore errors to API errors
+func restoreError(err error, message string) error {
+	switch {
+	case stderrors.Is(err, gorm.ErrRecordNotFound):
+		return errors.ErrTrashItemNotFound
+	case stderrors.Is(err, repository.ErrParentGoalDeleted):
+		return errors.ErrParentGoalDeleted
+	}
+	return errors.Wrap(err, errors.ErrDatabaseError.Code, message)
+}
+
 // GetMonthlySummary retrieves monthly financial summary
 func (s *FinanceService) GetMonthlySummary(userID uuid.UUID, year, month int) (*response.MonthlySummaryResponse, error) {
 	summary, err := s.financeRepo.GetMonthlySummary(userID, year, month)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to get monthly summary")
 	}
+	categoryTotals, err := s.categoryTotals(userID, summary.CategorySpending)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to get monthly summary")
+	}
+	merchantSpending, err := s.merchantSpending(userID, summary)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to get monthly summary")
+	}
+	investments, err := s.investmentMonth(userID, year, month)
+	if err != nil {
+		return nil, err
+	}
 
 	// Convert to response
 	return &response.MonthlySummaryResponse{
@@ -389,8 +866,13 @@ func (s *FinanceService) GetMonthlySummary(userID uuid.UUID, year, month int) (*
 		TotalExpenses:     summary.TotalExpenses,
 		TotalSavings:      summary.TotalSavings,
 		CategoryBreakdown: summary.CategoryBreakdown,
+		CategoryTotals:    categoryTotals,
 		GoalSpending:      summary.GoalSpending,
 		GoalContributions: summary.GoalContributions,
+		MerchantSpending:  merchantSpending,
+		TagIncome:         summary.TagIncome,
+		TagSpending:       summary.TagSpending,
+		Investments:       investments,
 	}, nil
 }
 
@@ -400,6 +882,9 @@ func (s *FinanceService) CreateGoalContribution(userID uuid.UUID, req *request.C
 	if err := validation.ValidateAmount(req.Amount); err != nil {
 		return nil, err
 	}
+	if err := s.checkGoalAcceptsContributions(userID, req.GoalID); err != nil {
+		return nil, err
+	}
 
 	// Create goal contribution model
 	contribution := &models.GoalContribution{
@@ -412,48 +897,169 @@ func (s *FinanceService) CreateGoalContribution(userID uuid.UUID, req *request.C
 	}
 
 	// Save to database
-	if err := s.financeRepo.CreateGoalContribution(contribution); err != nil {
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateGoalContribution(contribution); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoalContribution, contribution.ID, AuditCreate, createdChanges(contribution))
+		return nil
+	})
+	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create goal contribution")
 	}
 
 	// Convert to response
-	return &response.GoalContributionResponse{
-		ID:            contribution.ID,
-		UserID:        contribution.UserID,
-		GoalID:        contribution.GoalID,
-		Amount:        contribution.Amount,
-		ContributedAt: contribution.ContributedAt,
-		CreatedAt:     contribution.CreatedAt,
-	}, nil
+	resp := goalContributionResponse(*contribution)
+	return &resp, nil
 }
 
-// CreateCategory creates a new expense category
+// UpdateGoalContribution updates an existing goal contribution
+func (s *FinanceService) UpdateGoalContribution(userID, contributionID uuid.UUID, req *request.UpdateGoalContributionRequest) (*response.GoalContributionResponse, error) {
+	updates, err := goalContributionUpdates(req)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.financeRepo.GetGoalContribution(contributionID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrContributionNotFound, "Failed to update goal contribution")
+	}
+	// Withdrawals are edited with positive amounts like they are created, but stored negative
+	if amount, ok := updates["amount"].(float64); ok && before.Amount < 0 {
+		updates["amount"] = -amount
+	}
+	if req.GoalID != nil && *req.GoalID != before.GoalID {
+		if err := s.checkGoalAcceptsContributions(userID, *req.GoalID); err != nil {
+			return nil, err
+		}
+	}
+	// A withdrawal's goal stays locked from the update until its balance is checked, so an
+	// edit cannot take out more than the goal holds, like WithdrawFromGoal
+	goalID := before.GoalID
+	if req.GoalID != nil {
+		goalID = *req.GoalID
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if before.Amount < 0 {
+			if _, err := tx.financeRepo.LockGoal(goalID, userID); err != nil {
+				return lookupError(err, errors.ErrGoalNotFound, "Failed to update goal contribution")
+			}
+		}
+		if err := tx.financeRepo.UpdateGoalContribution(contributionID, userID, updates); err != nil {
+			return lookupError(err, errors.ErrContributionNotFound, "Failed to update goal contribution")
+		}
+		if before.Amount < 0 {
+			balance, err := tx.goalBalance(userID, goalID)
+			if err != nil {
+				return err
+			}
+			if roundCents(balance) < 0 {
+				withdrawal := before.Amount
+				if amount, ok := updates["amount"].(float64); ok {
+					withdrawal = amount
+				}
+				return errors.NewWithDetails(
+					errors.ErrWithdrawalExceedsBalance.Code,
+					errors.ErrWithdrawalExceedsBalance.Message,
+					fmt.Sprintf("The goal holds %.2f", max(balance-withdrawal, 0)),
+				)
+			}
+		}
+		tx.audit.record(userID, EntityGoalContribution, contributionID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := s.financeRepo.GetGoalContribution(contributionID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrContributionNotFound, "Failed to get goal contribution")
+	}
+	resp := goalContributionResponse(*after)
+	return &resp, nil
+}
+
+// goalContributionUpdates validates a contribution update request and returns the columns to change
+func goalContributionUpdates(req *request.UpdateGoalContributionRequest) (map[string]interface{}, error) {
+	updates := make(map[string]interface{})
+
+	if req.GoalID != nil {
+		updates["goal_id"] = *req.GoalID
+	}
+	if req.Amount != nil {
+		if err := validation.ValidateAmount(*req.Amount); err != nil {
+			return nil, err
+		}
+		updates["amount"] = *req.Amount
+	}
+	if req.ContributedAt != nil {
+		updates["contributed_at"] = *req.ContributedAt
+	}
+
+	if len(updates) == 0 {
+		return nil, errors.ErrInvalidInput
+	}
+	return updates, nil
+}
+
+// DeleteGoalContribution moves a goal contribution to the trash
+func (s *FinanceService) DeleteGoalContribution(userID, contributionID uuid.UUID) error {
+	before, err := s.financeRepo.GetGoalContribution(contributionID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrContributionNotFound, "Failed to delete goal contribution")
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteGoalContribution(contributionID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoalContribution, contributionID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrContributionNotFound, "Failed to delete goal contribution")
+	}
+	return nil
+}
+
+// CreateCategory creates a new expense category, optionally nested under a parent
 func (s *FinanceService) CreateCategory(userID uuid.UUID, req *request.CreateCategoryRequest) (*response.CategoryResponse, error) {
 	// Validate category name
-	if req.Name == "" {
-		return nil, errors.ErrInvalidInput
+	name := strings.TrimSpace(req.Name)
+	if err := validation.ValidateCategoryName(name); err != nil {
+		return nil, err
+	}
+	if req.ParentID != nil {
+		if _, err := s.financeRepo.GetCategory(*req.ParentID, userID); err != nil {
+			return nil, lookupError(err, errors.ErrCategoryNotFound, "Failed to get parent category")
+		}
 	}
 
 	// Create category model
 	category := &models.Category{
 		ID:        uuid.New(),
 		UserID:    userID,
-		Name:      req.Name,
+		ParentID:  req.ParentID,
+		Name:      name,
 		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
 	}
 
 	// Save to database
-	if err := s.financeRepo.CreateCategory(category); err != nil {
-		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create category")
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateCategory(category); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityCategory, category.ID, AuditCreate, createdChanges(category))
+		return nil
+	})
+	if err != nil {
+		return nil, categoryError(err, "Failed to create category")
 	}
 
 	// Convert to response
-	return &response.CategoryResponse{
-		ID:        category.ID,
-		UserID:    category.UserID,
-		Name:      category.Name,
-		CreatedAt: category.CreatedAt,
-	}, nil
+	resp := categoryResponse(*category)
+	return &resp, nil
 }
 
 // ListCategories retrieves user's expense categories
@@ -466,33 +1072,7 @@ func (s *FinanceService) ListCategories(userID uuid.UUID) ([]response.CategoryRe
 	// Convert to response
 	responses := make([]response.CategoryResponse, len(categories))
 	for i, category := range categories {
-		responses[i] = response.CategoryResponse{
-			ID:        category.ID,
-			UserID:    category.UserID,
-			Name:      category.Name,
-			CreatedAt: category.CreatedAt,
-		}
-	}
-
-	return responses, nil
-}
-
-// ListGoalCategories retrieves all goal categories
-func (s *FinanceService) ListGoalCategories() ([]response.GoalCategoryResponse, error) {
-	categories, err := s.financeRepo.ListGoalCategories()
-	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list goal categories")
-	}
-
-	// Convert to response
-	responses := make([]response.GoalCategoryResponse, len(categories))
-	for i, category := range categories {
-		responses[i] = response.GoalCategoryResponse{
-			ID:          category.ID,
-			Name:        category.Name,
-			Description: category.Description,
-			CreatedAt:   category.CreatedAt,
-		}
+		responses[i] = categoryResponse(category)
 	}
 
 	return responses, nil
@@ -505,46 +1085,27 @@ func (s *FinanceService) ListMainGoalsWithSubgoals(userID uuid.UUID) ([]response
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list main goals with subgoals")
 	}
 
-	// Convert to response
-	responses := make([]response.GoalWithSubgoalsResponse, len(goalsWithSubgoals))
-	for i, goalWithSubgoals := range goalsWithSubgoals {
-		// Convert main goal
-		mainGoal := response.GoalResponse{
-			ID:           goalWithSubgoals.Goal.ID,
-			UserID:       goalWithSubgoals.Goal.UserID,
-			Name:         goalWithSubgoals.Goal.Name,
-			Description:  goalWithSubgoals.Goal.Description,
-			Category:     goalWithSubgoals.Goal.Category,
-			TargetAmount: goalWithSubgoals.Goal.TargetAmount,
-			TargetDate:   goalWithSubgoals.Goal.TargetDate,
-			ParentGoalID: goalWithSubgoals.Goal.ParentGoalID,
-			IsMainGoal:   goalWithSubgoals.Goal.IsMainGoal,
-			CreatedAt:    goalWithSubgoals.Goal.CreatedAt,
-			UpdatedAt:    goalWithSubgoals.Goal.UpdatedAt,
+	// Convert to response, leaving out paused and archived goals
+	responses := []response.GoalWithSubgoalsResponse{}
+	for _, goalWithSubgoals := range goalsWithSubgoals {
+		if !slices.Contains(DefaultGoalStatuses, goalWithSubgoals.Goal.Status) {
+			continue
 		}
+		// Convert main goal
+		mainGoal := goalResponse(goalWithSubgoals.Goal)
 
 		// Convert subgoals
-		subgoals := make([]response.GoalResponse, len(goalWithSubgoals.Subgoals))
-		for j, subgoal := range goalWithSubgoals.Subgoals {
-			subgoals[j] = response.GoalResponse{
-				ID:           subgoal.ID,
-				UserID:       subgoal.UserID,
-				Name:         subgoal.Name,
-				Description:  subgoal.Description,
-				Category:     subgoal.Category,
-				TargetAmount: subgoal.TargetAmount,
-				TargetDate:   subgoal.TargetDate,
-				ParentGoalID: subgoal.ParentGoalID,
-				IsMainGoal:   subgoal.IsMainGoal,
-				CreatedAt:    subgoal.CreatedAt,
-				UpdatedAt:    subgoal.UpdatedAt,
+		subgoals := []response.GoalResponse{}
+		for _, subgoal := range goalWithSubgoals.Subgoals {
+			if slices.Contains(DefaultGoalStatuses, subgoal.Status) {
+				subgoals = append(subgoals, goalResponse(subgoal))
 			}
 		}
 
-		responses[i] = response.GoalWithSubgoalsResponse{
+		responses = append(responses, response.GoalWithSubgoalsResponse{
 			Goal:     mainGoal,
 			Subgoals: subgoals,
-		}
+		})
 	}
 
 	return responses, nil
@@ -569,7 +1130,14 @@ func (s *FinanceService) CreateGoalExpense(userID uuid.UUID, req *request.Create
 	}
 
 	// Save to database
-	if err := s.financeRepo.CreateGoalExpense(goalExpense); err != nil {
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateGoalExpense(goalExpense); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoalExpense, goalExpense.ID, AuditCreate, createdChanges(goalExpense))
+		return nil
+	})
+	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create goal expense")
 	}
 
@@ -608,3 +1176,77 @@ func (s *FinanceService) ListGoalExpenses(userID, goalID uuid.UUID) ([]response.
 
 	return responses, nil
 }
+
+// incomeResponse converts an income model to its API representation
+func incomeResponse(income models.Income) response.IncomeResponse {
+	return response.IncomeResponse{
+		ID:         income.ID,
+		UserID:     income.UserID,
+		Source:     income.Source,
+		Amount:     income.Amount,
+		ReceivedAt: income.ReceivedAt,
+		Tags:       responseTags(income.Tags),
+		Version:    income.Version,
+		CreatedAt:  income.CreatedAt,
+		UpdatedAt:  income.UpdatedAt,
+		SecurityID: income.SecurityID,
+	}
+}
+
+// expenseResponse converts an expense model to its API representation
+func expenseResponse(expense models.Expense) response.ExpenseResponse {
+	return response.ExpenseResponse{
+		ID:          expense.ID,
+		UserID:      expense.UserID,
+		Category:    expense.Category,
+		CategoryID:  expense.CategoryID,
+		Description: expense.Description,
+		Amount:      expense.Amount,
+		SpentAt:     expense.SpentAt,
+		GoalID:      expense.GoalID,
+		MerchantID:  expense.MerchantID,
+		DebtID:      expense.DebtID,
+		Tags:        responseTags(expense.Tags),
+		Version:     expense.Version,
+		CreatedAt:   expense.CreatedAt,
+		UpdatedAt:   expense.UpdatedAt,
+	}
+}
+
+// goalContributionResponse converts a goal contribution model to its API representation
+func goalContributionResponse(contribution models.GoalContribution) response.GoalContributionResponse {
+	return response.GoalContributionResponse{
+		ID:            contribution.ID,
+		UserID:        contribution.UserID,
+		GoalID:        contribution.GoalID,
+		Amount:        contribution.Amount,
+		ContributedAt: contribution.ContributedAt,
+		FundingRuleID: contribution.FundingRuleID,
+		SourceID:      contribution.SourceID,
+		Reason:        contribution.Reason,
+		CreatedAt:     contribution.CreatedAt,
+	}
+}
+
+// goalResponse converts a goal model to its API representation
+func goalResponse(goal models.Goal) response.GoalResponse {
+	return response.GoalResponse{
+		ID:                 goal.ID,
+		UserID:             goal.UserID,
+		Name:               goal.Name,
+		Description:        goal.Description,
+		Category:           goal.Category,
+		CategoryID:         goal.CategoryID,
+		TargetAmount:       goal.TargetAmount,
+		TargetDate:         goal.TargetDate,
+		ParentGoalID:       goal.ParentGoalID,
+		IsMainGoal:         goal.IsMainGoal,
+		AllocationPriority: goal.AllocationPriority,
+		AllocationWeight:   goal.AllocationWeight,
+		Status:             goal.Status,
+		StatusChangedAt:    goal.StatusChangedAt,
+		Version:            goal.Version,
+		CreatedAt:          goal.CreatedAt,
+		UpdatedAt:          goal.UpdatedAt,
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func newTestFinanceService() *FinanceService {
+	return NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, nil)
+}
+
+func TestCreateIncomeRejectsNonPositiveAmount(t *testing.T) {
+	svc := newTestFinanceService()
+	_, err := svc.CreateIncome(uuid.New(), &request.CreateIncomeRequest{Source: "salary", Amount: 0, ReceivedAt: time.Now()})
+	if err != errors.ErrInvalidAmount {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestListGoalsWithProgressCapsAtHundredPercent(t *testing.T) {
+	svc := newTestFinanceService()
+	userID := uuid.New()
+
+	goal, err := svc.CreateGoal(userID, &request.CreateGoalRequest{Name: "Phone", TargetAmount: 500, IsMainGoal: true})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	for _, amount := range []float64{200, 450} {
+		if _, err := svc.CreateGoalContribution(userID, &request.CreateGoalContributionRequest{GoalID: goal.ID, Amount: amount, ContributedAt: time.Now()}); err != nil {
+			t.Fatalf("CreateGoalContribution: %v", err)
+		}
+	}
+
+	goals, err := svc.ListGoalsWithProgress(userID, 0, nil, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("ListGoalsWithProgress: %v", err)
+	}
+	if len(goals) != 1 {
+		t.Fatalf("expected 1 goal, got %d", len(goals))
+	}
+	if goals[0].ContributedSum != 650 || goals[0].Progress != 100 {
+		t.Fatalf("expected contributed 650 and progress 100, got %.2f and %.2f", goals[0].ContributedSum, goals[0].Progress)
+	}
+}
+
+func TestUpdateExpenseRequiresChanges(t *testing.T) {
+	svc := newTestFinanceService()
+	if _, err := svc.UpdateExpense(uuid.New(), uuid.New(), 0, &request.UpdateExpenseRequest{}); err != errors.ErrInvalidInput {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestUpdateIncomeVersionConflict(t *testing.T) {
+	svc := newTestFinanceService()
+	userID := uuid.New()
+	income, err := svc.CreateIncome(userID, &request.CreateIncomeRequest{Source: "salary", Amount: 1000, ReceivedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+
+	amount := 1100.0
+	updated, err := svc.UpdateIncome(userID, income.ID, income.Version, &request.UpdateIncomeRequest{Amount: &amount})
+	if err != nil {
+		t.Fatalf("UpdateIncome: %v", err)
+	}
+	if updated.Version != income.Version+1 || updated.Amount != amount {
+		t.Fatalf("expected version %d with amount %.2f, got %+v", income.Version+1, amount, updated)
+	}
+
+	// A second writer still holding the original version must be rejected
+	stale := 900.0
+	_, err = svc.UpdateIncome(userID, income.ID, income.Version, &request.UpdateIncomeRequest{Amount: &stale})
+	appErr, ok := err.(*errors.AppError)
+	if !ok || appErr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412, got %v", err)
+	}
+	current, ok := appErr.Current.(response.IncomeResponse)
+	if !ok || current.Version != updated.Version || current.Amount != amount {
+		t.Fatalf("412 should carry the current income, got %#v", appErr.Current)
+	}
+
+	_, err = svc.UpdateIncome(userID, uuid.New(), 1, &request.UpdateIncomeRequest{Amount: &amount})
+	if err != errors.ErrIncomeNotFound {
+		t.Fatalf("expected ErrIncomeNotFound, got %v", err)
+	}
+}
+
+func TestDeleteUnknownIDs(t *testing.T) {
+	svc := newTestFinanceService()
+	if err := svc.DeleteIncome(uuid.New(), uuid.New()); err != errors.ErrIncomeNotFound {
+		t.Fatalf("expected ErrIncomeNotFound, got %v", err)
+	}
+	if err := svc.DeleteExpense(uuid.New(), uuid.New()); err != errors.ErrExpenseNotFound {
+		t.Fatalf("expected ErrExpenseNotFound, got %v", err)
+	}
+	if err := svc.DeleteGoal(uuid.New(), uuid.New()); err != errors.ErrGoalNotFound {
+		t.Fatalf("expected ErrGoalNotFound, got %v", err)
+	}
+}
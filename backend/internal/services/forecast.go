@@ -0,0 +1,485 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultForecastMonths  = 6
+	maxForecastMonths      = 24
+	defaultForecastHistory = 12
+	minForecastHistory     = 3
+	maxForecastHistory     = 36
+
+	// recurringMinMonths and recurringCoverage decide when a payee or source is
+	// recurring: it must appear in at least that many months, and in that share of
+	// the months since it first appeared
+	recurringMinMonths = 3
+	recurringCoverage  = 0.75
+
+	// forecastConfidence is the coverage of the balance bands; forecastZ is the
+	// matching two-sided normal quantile
+	forecastConfidence = 0.8
+	forecastZ          = 1.2816
+
+	// outlierThreshold is how many scaled median absolute deviations a month's
+	// variable spending may be from the median before it counts as an outlier
+	outlierThreshold = 3.0
+	// madScale makes the median absolute deviation comparable to a standard deviation
+	madScale = 1.4826
+)
+
+// transactionSeries collects one income source's or payee's monthly totals over the history window
+type transactionSeries struct {
+	name       string
+	merchantID *uuid.UUID
+	categoryID *uuid.UUID
+	category   string
+	totals     []float64
+}
+
+// occurrences returns the number of months with a non-zero total
+func (t *transactionSeries) occurrences() int {
+	n := 0
+	for _, total := range t.totals {
+		if total != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// recurring reports whether the series turns up nearly every month since it first
+// appeared and is still active in one of the last two months
+func (t *transactionSeries) recurring() bool {
+	first := -1
+	for i, total := range t.totals {
+		if total != 0 {
+			first = i
+			break
+		}
+	}
+	n := len(t.totals)
+	if first < 0 || (t.totals[n-1] == 0 && t.totals[n-2] == 0) {
+		return false
+	}
+	count := t.occurrences()
+	return count >= recurringMinMonths && float64(count) >= recurringCoverage*float64(n-first)
+}
+
+// amount is the median of the months the series appeared in
+func (t *transactionSeries) amount() float64 {
+	var present []float64
+	for _, total := range t.totals {
+		if total != 0 {
+			present = append(present, total)
+		}
+	}
+	return median(present)
+}
+
+// Forecast projects income, expenses and balance for the months after now's month.
+// Recurring incomes and expenses are projected at their median monthly amount, the
+// rest of the spending at its per-category average and goals with a target date at
+// the even monthly saving that reaches them. Excluded and outlier months only drop
+// out of the averages; recurring items are still detected over the whole history.
+func (s *FinanceService) Forecast(userID uuid.UUID, req *request.ForecastRequest, now time.Time) (*response.ForecastResponse, error) {
+	months, history := req.Months, req.History
+	if months == 0 {
+		months = defaultForecastMonths
+	}
+	if history == 0 {
+		history = defaultForecastHistory
+	}
+	if months < 1 || months > maxForecastMonths {
+		return nil, errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid forecast",
+			fmt.Sprintf("months must be between 1 and %d", maxForecastMonths))
+	}
+	if history < minForecastHistory || history > maxForecastHistory {
+		return nil, errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid forecast",
+			fmt.Sprintf("history must be between %d and %d months", minForecastHistory, maxForecastHistory))
+	}
+
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	historyStart := current.AddDate(0, -history, 0)
+	forecastStart := current.AddDate(0, 1, 0)
+	monthIndex := func(t time.Time) int {
+		return (t.Year()-historyStart.Year())*12 + int(t.Month()) - int(historyStart.Month())
+	}
+
+	excluded := make([]bool, history)
+	for _, raw := range req.Exclude {
+		month, err := time.Parse("2006-01", strings.TrimSpace(raw))
+		if err != nil {
+			return nil, errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid forecast",
+				fmt.Sprintf("excluded month %q is not in YYYY-MM format", raw))
+		}
+		if i := monthIndex(month); i >= 0 && i < history {
+			excluded[i] = true
+		}
+	}
+
+	incomes, err := s.financeRepo.ListIncomesBetween(userID, historyStart, current)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load income history")
+	}
+	expenses, err := s.financeRepo.ListExpensesBetween(userID, historyStart, current)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load expense history")
+	}
+	merchants, err := s.financeRepo.ListMerchants(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list merchants")
+	}
+	merchantNames := make(map[uuid.UUID]string, len(merchants))
+	for _, m := range merchants {
+		merchantNames[m.ID] = m.Name
+	}
+	categories, err := s.financeRepo.ListCategories(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list categories")
+	}
+	categoryNames := make(map[uuid.UUID]string, len(categories))
+	for _, c := range categories {
+		categoryNames[c.ID] = c.Name
+	}
+
+	// Group incomes by source and expenses by merchant, falling back to the normalised
+	// description. Later entries win the display fields, so the newest name is shown.
+	incomeSeries := map[string]*transactionSeries{}
+	var incomeKeys []string
+	for _, income := range incomes {
+		key := strings.ToLower(strings.TrimSpace(income.Source))
+		series, ok := incomeSeries[key]
+		if !ok {
+			series = &transactionSeries{totals: make([]float64, history)}
+			incomeSeries[key] = series
+			incomeKeys = append(incomeKeys, key)
+		}
+		series.name = income.Source
+		series.totals[monthIndex(income.ReceivedAt)] += income.Amount
+	}
+	expenseSeries := map[string]*transactionSeries{}
+	var expenseKeys []string
+	for _, expense := range expenses {
+		key := expenseSeriesKey(expense)
+		if key == "" {
+			continue
+		}
+		series, ok := expenseSeries[key]
+		if !ok {
+			series = &transactionSeries{totals: make([]float64, history)}
+			expenseSeries[key] = series
+			expenseKeys = append(expenseKeys, key)
+		}
+		series.name = expense.Description
+		if expense.MerchantID != nil {
+			series.merchantID = expense.MerchantID
+			series.name = merchantNames[*expense.MerchantID]
+		}
+		series.categoryID = expense.CategoryID
+		series.category = expenseCategoryName(expense, categoryNames)
+		series.totals[monthIndex(expense.SpentAt)] += expense.Amount
+	}
+
+	resp := &response.ForecastResponse{
+		HistoryMonths:    history,
+		ExcludedMonths:   []string{},
+		ConfidenceLevel:  forecastConfidence,
+		Recurring:        []response.RecurringItemResponse{},
+		CategoryAverages: []response.CategoryAverageResponse{},
+		Months:           make([]response.ForecastMonthResponse, 0, months),
+	}
+
+	var recurringIncome, recurringExpenses float64
+	recurringIncomes := map[string]bool{}
+	for _, key := range incomeKeys {
+		series := incomeSeries[key]
+		if !series.recurring() {
+			continue
+		}
+		recurringIncomes[key] = true
+		amount := series.amount()
+		recurringIncome += amount
+		resp.Recurring = append(resp.Recurring, response.RecurringItemResponse{
+			Type:        "income",
+			Name:        series.name,
+			Amount:      roundCents(amount),
+			Occurrences: series.occurrences(),
+		})
+	}
+	recurringPayees := map[string]bool{}
+	for _, key := range expenseKeys {
+		series := expenseSeries[key]
+		if !series.recurring() {
+			continue
+		}
+		recurringPayees[key] = true
+		amount := series.amount()
+		recurringExpenses += amount
+		resp.Recurring = append(resp.Recurring, response.RecurringItemResponse{
+			Type:        "expense",
+			Name:        series.name,
+			MerchantID:  series.merchantID,
+			CategoryID:  series.categoryID,
+			Category:    series.category,
+			Amount:      roundCents(amount),
+			Occurrences: series.occurrences(),
+		})
+	}
+	sort.SliceStable(resp.Recurring, func(i, j int) bool { return resp.Recurring[i].Amount > resp.Recurring[j].Amount })
+
+	// Everything else is variable: spending is averaged per category, income as a whole
+	variableIncome := make([]float64, history)
+	for _, income := range incomes {
+		if !recurringIncomes[strings.ToLower(strings.TrimSpace(income.Source))] {
+			variableIncome[monthIndex(income.ReceivedAt)] += income.Amount
+		}
+	}
+	variableSpending := make([]float64, history)
+	categorySeries := map[string]*transactionSeries{}
+	var categoryKeys []string
+	for _, expense := range expenses {
+		if key := expenseSeriesKey(expense); key != "" && recurringPayees[key] {
+			continue
+		}
+		key := "name:" + strings.ToLower(expense.Category)
+		if expense.CategoryID != nil {
+			key = expense.CategoryID.String()
+		}
+		series, ok := categorySeries[key]
+		if !ok {
+			series = &transactionSeries{categoryID: expense.CategoryID, totals: make([]float64, history)}
+			categorySeries[key] = series
+			categoryKeys = append(categoryKeys, key)
+		}
+		series.category = expenseCategoryName(expense, categoryNames)
+		i := monthIndex(expense.SpentAt)
+		series.totals[i] += expense.Amount
+		variableSpending[i] += expense.Amount
+	}
+
+	if req.ExcludeOutliers {
+		for _, i := range outlierMonths(variableSpending, excluded) {
+			excluded[i] = true
+		}
+	}
+	var included []int
+	for i := range excluded {
+		if excluded[i] {
+			resp.ExcludedMonths = append(resp.ExcludedMonths, historyStart.AddDate(0, i, 0).Format("2006-01"))
+		} else {
+			included = append(included, i)
+		}
+	}
+	if len(included) == 0 {
+		return nil, errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid forecast",
+			"At least one history month must remain after exclusions")
+	}
+
+	var averageSpending float64
+	for _, key := range categoryKeys {
+		series := categorySeries[key]
+		average := meanOf(series.totals, included)
+		if average == 0 {
+			continue
+		}
+		averageSpending += average
+		resp.CategoryAverages = append(resp.CategoryAverages, response.CategoryAverageResponse{
+			CategoryID: series.categoryID,
+			Category:   series.category,
+			Average:    roundCents(average),
+		})
+	}
+	sort.SliceStable(resp.CategoryAverages, func(i, j int) bool {
+		return resp.CategoryAverages[i].Average > resp.CategoryAverages[j].Average
+	})
+	averageIncome := meanOf(variableIncome, included)
+	resp.VariableIncome = roundCents(averageIncome)
+
+	// The bands widen with the square root of the months ahead, as the variable
+	// net of each month is treated as independent of the others
+	variableNet := make([]float64, history)
+	for i := range variableNet {
+		variableNet[i] = variableIncome[i] - variableSpending[i]
+	}
+	sigma := stddevOf(variableNet, included)
+
+	goalSavings, err := s.goalSavingsPlan(userID, current, months)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := 0.0
+	if req.StartingBalance != nil {
+		balance = *req.StartingBalance
+	} else {
+		balance, err = s.financeRepo.GetBalance(userID, forecastStart)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to compute the balance")
+		}
+	}
+	resp.StartingBalance = roundCents(balance)
+
+	income := recurringIncome + averageIncome
+	spending := recurringExpenses + averageSpending
+	for k := 1; k <= months; k++ {
+		month := current.AddDate(0, k, 0)
+		net := income - spending - goalSavings[k-1]
+		balance += net
+		band := forecastZ * sigma * math.Sqrt(float64(k))
+		resp.Months = append(resp.Months, response.ForecastMonthResponse{
+			Year:        month.Year(),
+			Month:       int(month.Month()),
+			Income:      roundCents(income),
+			Expenses:    roundCents(spending),
+			GoalSavings: roundCents(goalSavings[k-1]),
+			Net:         roundCents(net),
+			Balance:     roundCents(balance),
+			BalanceLow:  roundCents(balance - band),
+			BalanceHigh: roundCents(balance + band),
+			Negative:    roundCents(balance) < 0,
+		})
+	}
+	return resp, nil
+}
+
+// goalSavingsPlan returns the saving needed in each of the months after current so
+// that every active goal with a target date reaches its target. Parent goals are skipped
+// as their subgoals carry the amounts, and so are goals already funded or due.
+func (s *FinanceService) goalSavingsPlan(userID uuid.UUID, current time.Time, months int) ([]float64, error) {
+	goals, err := s.financeRepo.ListGoalsWithProgress(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list goals")
+	}
+	goals = activeGoals(goals)
+	parents := map[uuid.UUID]bool{}
+	for _, g := range goals {
+		if g.Goal.ParentGoalID != nil {
+			parents[*g.Goal.ParentGoalID] = true
+		}
+	}
+
+	plan := make([]float64, months)
+	for _, g := range goals {
+		if parents[g.Goal.ID] || g.Goal.TargetDate == nil {
+			continue
+		}
+		remaining := g.Goal.TargetAmount - g.ContributedSum
+		target := g.Goal.TargetDate.UTC()
+		left := (target.Year()-current.Year())*12 + int(target.Month()) - int(current.Month())
+		if remaining <= 0 || left < 1 {
+			continue
+		}
+		for k := 0; k < left && k < months; k++ {
+			plan[k] += remaining / float64(left)
+		}
+	}
+	return plan, nil
+}
+
+// expenseSeriesKey identifies the payee of an expense, or returns "" when it has neither
+// a merchant nor a meaningful description
+func expenseSeriesKey(expense models.Expense) string {
+	if expense.MerchantID != nil {
+		return "merchant:" + expense.MerchantID.String()
+	}
+	if normalized := normalizeDescription(expense.Description); normalized != "" {
+		return "description:" + normalized
+	}
+	return ""
+}
+
+// expenseCategoryName prefers the current name of the expense's category
+func expenseCategoryName(expense models.Expense, names map[uuid.UUID]string) string {
+	if expense.CategoryID != nil {
+		if name, ok := names[*expense.CategoryID]; ok {
+			return name
+		}
+	}
+	return expense.Category
+}
+
+// outlierMonths returns the months, among those not already excluded, whose value is
+// more than outlierThreshold scaled median absolute deviations from the median. It
+// needs at least four months and some spread to judge.
+func outlierMonths(values []float64, excluded []bool) []int {
+	var kept []float64
+	for i, v := range values {
+		if !excluded[i] {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) < 4 {
+		return nil
+	}
+	mid := median(kept)
+	deviations := make([]float64, len(kept))
+	for i, v := range kept {
+		deviations[i] = math.Abs(v - mid)
+	}
+	mad := median(deviations) * madScale
+	if mad == 0 {
+		return nil
+	}
+	var outliers []int
+	for i, v := range values {
+		if !excluded[i] && math.Abs(v-mid) > outlierThreshold*mad {
+			outliers = append(outliers, i)
+		}
+	}
+	return outliers
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// meanOf averages values at the given indexes
+func meanOf(values []float64, indexes []int) float64 {
+	if len(indexes) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, i := range indexes {
+		sum += values[i]
+	}
+	return sum / float64(len(indexes))
+}
+
+// stddevOf is the sample standard deviation of values at the given indexes
+func stddevOf(values []float64, indexes []int) float64 {
+	if len(indexes) < 2 {
+		return 0
+	}
+	mean := meanOf(values, indexes)
+	var sum float64
+	for _, i := range indexes {
+		sum += (values[i] - mean) * (values[i] - mean)
+	}
+	return math.Sqrt(sum / float64(len(indexes)-1))
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
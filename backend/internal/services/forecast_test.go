@@ -0,0 +1,90 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestForecastProjectsRecurringAveragesAndGoals(t *testing.T) {
+	finance := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, nil)
+	userID := uuid.New()
+	now := time.Now().UTC()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	// historyDay returns a day in the i-th of the six months before the current one
+	historyDay := func(i, day int) time.Time { return current.AddDate(0, i-6, day-1) }
+
+	groceries := []float64{200, 220, 180, 200, 200, 1400}
+	for i := 0; i < 6; i++ {
+		if _, err := finance.CreateIncome(userID, &request.CreateIncomeRequest{Source: "Salary", Amount: 3000, ReceivedAt: historyDay(i, 25)}); err != nil {
+			t.Fatalf("CreateIncome: %v", err)
+		}
+		if _, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "housing", Description: "CITY LETTINGS 0042", Amount: 1000, SpentAt: historyDay(i, 1)}); err != nil {
+			t.Fatalf("CreateExpense rent: %v", err)
+		}
+		if _, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "food", Amount: groceries[i], SpentAt: historyDay(i, 12)}); err != nil {
+			t.Fatalf("CreateExpense groceries: %v", err)
+		}
+	}
+	// A one-off purchase is not recurring
+	finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "home", Description: "Lamp Shop", Amount: 60, SpentAt: historyDay(2, 5)})
+	target := current.AddDate(0, 6, -1)
+	if _, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Car", TargetAmount: 1200, TargetDate: &target}); err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+
+	forecast, err := finance.Forecast(userID, &request.ForecastRequest{History: 6, ExcludeOutliers: true}, now)
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+	if len(forecast.Recurring) != 2 || forecast.Recurring[0].Name != "Salary" || forecast.Recurring[1].Amount != 1000 {
+		t.Fatalf("expected the salary and rent to be recurring, got %+v", forecast.Recurring)
+	}
+	if len(forecast.ExcludedMonths) != 1 || forecast.ExcludedMonths[0] != historyDay(5, 1).Format("2006-01") {
+		t.Fatalf("expected the last month to be dropped as an outlier, got %v", forecast.ExcludedMonths)
+	}
+	// Groceries average 200 over the five kept months, the lamp 60 / 5
+	if len(forecast.CategoryAverages) != 2 || forecast.CategoryAverages[0].Average != 200 || forecast.CategoryAverages[1].Average != 12 {
+		t.Fatalf("unexpected category averages %+v", forecast.CategoryAverages)
+	}
+	if forecast.StartingBalance != 18000-6000-2400-60 {
+		t.Fatalf("expected the ledger balance, got %v", forecast.StartingBalance)
+	}
+	if len(forecast.Months) != defaultForecastMonths {
+		t.Fatalf("expected %d months, got %d", defaultForecastMonths, len(forecast.Months))
+	}
+	first, last := forecast.Months[0], forecast.Months[5]
+	if first.GoalSavings != 240 || last.GoalSavings != 0 {
+		t.Fatalf("expected the goal to be saved for over five months, got %v then %v", first.GoalSavings, last.GoalSavings)
+	}
+	if first.Net != 3000-1000-212-240 || first.Balance != forecast.StartingBalance+first.Net {
+		t.Fatalf("unexpected first month %+v", first)
+	}
+	if !(first.BalanceLow < first.Balance && first.Balance < first.BalanceHigh) {
+		t.Fatalf("expected a band around the balance, got %+v", first)
+	}
+	if math.Abs((last.BalanceHigh-last.Balance)-(first.BalanceHigh-first.Balance)*math.Sqrt(6)) > 0.02 {
+		t.Fatalf("expected the band to widen with the square root of the horizon, got %+v and %+v", first, last)
+	}
+
+	overdrawn := -3000.0
+	forecast, err = finance.Forecast(userID, &request.ForecastRequest{History: 6, Months: 3, ExcludeOutliers: true, StartingBalance: &overdrawn}, now)
+	if err != nil {
+		t.Fatalf("Forecast with starting balance: %v", err)
+	}
+	if !forecast.Months[0].Negative || forecast.Months[2].Negative {
+		t.Fatalf("expected only the first months to be flagged negative, got %+v", forecast.Months)
+	}
+
+	if _, err := finance.Forecast(userID, &request.ForecastRequest{Exclude: []string{"last-june"}}, now); err == nil {
+		t.Fatal("expected a malformed excluded month to be rejected")
+	}
+	if _, err := finance.Forecast(userID, &request.ForecastRequest{Months: maxForecastMonths + 1}, now); err == nil {
+		t.Fatal("expected a horizon over the maximum to be rejected")
+	}
+}
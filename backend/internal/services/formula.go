@@ -0,0 +1,145 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// targetFormula evaluates a goal template target for the given variable values
+type targetFormula func(vars map[string]float64) float64
+
+// formulaParser is a recursive descent parser for target formulas: numbers and
+// variables combined with + - * / and parentheses, such as "6 * avg_monthly_expenses"
+type formulaParser struct {
+	src     string
+	pos     int
+	allowed []string
+}
+
+// parseTargetFormula parses src, which may only use the allowed variables
+func parseTargetFormula(src string, allowed []string) (targetFormula, error) {
+	p := &formulaParser{src: src, allowed: allowed}
+	f, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.src[p.pos], p.pos+1)
+	}
+	return f, nil
+}
+
+// expr parses terms joined by + and -
+func (p *formulaParser) expr() (targetFormula, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == '+' {
+			left = func(vars map[string]float64) float64 { return l(vars) + right(vars) }
+		} else {
+			left = func(vars map[string]float64) float64 { return l(vars) - right(vars) }
+		}
+	}
+}
+
+// term parses factors joined by * and /
+func (p *formulaParser) term() (targetFormula, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == '*' {
+			left = func(vars map[string]float64) float64 { return l(vars) * right(vars) }
+		} else {
+			left = func(vars map[string]float64) float64 { return l(vars) / right(vars) }
+		}
+	}
+}
+
+// factor parses a number, a variable, a negated factor or a parenthesised expression
+func (p *formulaParser) factor() (targetFormula, error) {
+	switch c := p.peek(); {
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end of formula")
+	case c == '-':
+		p.pos++
+		f, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]float64) float64 { return -f(vars) }, nil
+	case c == '(':
+		p.pos++
+		f, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing ) at position %d", p.pos+1)
+		}
+		p.pos++
+		return f, nil
+	case c == '.' || c >= '0' && c <= '9':
+		start := p.pos
+		for p.pos < len(p.src) && (p.src[p.pos] == '.' || p.src[p.pos] >= '0' && p.src[p.pos] <= '9') {
+			p.pos++
+		}
+		n, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p.src[start:p.pos])
+		}
+		return func(map[string]float64) float64 { return n }, nil
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		start := p.pos
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || p.src[p.pos] >= 'a' && p.src[p.pos] <= 'z' || p.src[p.pos] >= 'A' && p.src[p.pos] <= 'Z') {
+			p.pos++
+		}
+		name := strings.ToLower(p.src[start:p.pos])
+		if !slices.Contains(p.allowed, name) {
+			return nil, fmt.Errorf("unknown variable %q; use %s", name, strings.Join(p.allowed, ", "))
+		}
+		return func(vars map[string]float64) float64 { return vars[name] }, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", c, p.pos+1)
+	}
+}
+
+// peek skips spaces and returns the next character, or 0 at the end
+func (p *formulaParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *formulaParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
@@ -0,0 +1,436 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/validation"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateFundingRule adds a rule that generates contributions to one of the user's goals
+func (s *FinanceService) CreateFundingRule(userID uuid.UUID, req *request.CreateFundingRuleRequest) (*response.FundingRuleResponse, error) {
+	if _, err := s.financeRepo.GetGoal(req.GoalID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrGoalNotFound, "Failed to create funding rule")
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	now := time.Now().UTC()
+	rule := &models.GoalFundingRule{
+		ID:           uuid.New(),
+		UserID:       userID,
+		GoalID:       req.GoalID,
+		Name:         strings.TrimSpace(req.Name),
+		Type:         req.Type,
+		Enabled:      enabled,
+		Percent:      req.Percent,
+		IncomeSource: req.IncomeSource,
+		RoundTo:      req.RoundTo,
+		Amount:       req.Amount,
+		DayOfMonth:   req.DayOfMonth,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := checkFundingRule(rule); err != nil {
+		return nil, err
+	}
+
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateFundingRule(rule); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityFundingRule, rule.ID, AuditCreate, createdChanges(rule))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create funding rule")
+	}
+
+	resp := fundingRuleResponse(*rule)
+	return &resp, nil
+}
+
+// ListFundingRules returns the user's funding rules, only those of goalID when it is set
+func (s *FinanceService) ListFundingRules(userID uuid.UUID, goalID *uuid.UUID) ([]response.FundingRuleResponse, error) {
+	rules, err := s.financeRepo.ListFundingRules(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list funding rules")
+	}
+	responses := []response.FundingRuleResponse{}
+	for _, rule := range rules {
+		if goalID == nil || rule.GoalID == *goalID {
+			responses = append(responses, fundingRuleResponse(rule))
+		}
+	}
+	return responses, nil
+}
+
+// GetFundingRule returns one funding rule
+func (s *FinanceService) GetFundingRule(userID, ruleID uuid.UUID) (*response.FundingRuleResponse, error) {
+	rule, err := s.financeRepo.GetFundingRule(ruleID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrFundingRuleNotFound, "Failed to get funding rule")
+	}
+	resp := fundingRuleResponse(*rule)
+	return &resp, nil
+}
+
+// UpdateFundingRule edits a funding rule; the result must still be a valid rule of its type
+func (s *FinanceService) UpdateFundingRule(userID, ruleID uuid.UUID, req *request.UpdateFundingRuleRequest) (*response.FundingRuleResponse, error) {
+	before, err := s.financeRepo.GetFundingRule(ruleID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrFundingRuleNotFound, "Failed to update funding rule")
+	}
+
+	after := *before
+	if req.Name != nil {
+		after.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Enabled != nil {
+		after.Enabled = *req.Enabled
+	}
+	if req.Percent != nil {
+		after.Percent = req.Percent
+	}
+	if req.IncomeSource != nil {
+		after.IncomeSource = req.IncomeSource
+	}
+	if req.RoundTo != nil {
+		after.RoundTo = req.RoundTo
+	}
+	if req.Amount != nil {
+		after.Amount = req.Amount
+	}
+	if req.DayOfMonth != nil {
+		after.DayOfMonth = req.DayOfMonth
+	}
+	if err := checkFundingRule(&after); err != nil {
+		return nil, err
+	}
+
+	// Only the columns sent are updated, with the values checkFundingRule normalised
+	updates := make(map[string]interface{})
+	for column, sent := range map[string]bool{
+		"name":          req.Name != nil,
+		"enabled":       req.Enabled != nil,
+		"percent":       req.Percent != nil,
+		"income_source": req.IncomeSource != nil,
+		"round_to":      req.RoundTo != nil,
+		"amount":        req.Amount != nil,
+		"day_of_month":  req.DayOfMonth != nil,
+	} {
+		if sent {
+			updates[column] = fundingRuleColumn(&after, column)
+		}
+	}
+	if len(updates) == 0 {
+		return nil, errors.ErrInvalidInput
+	}
+
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateFundingRule(ruleID, userID, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityFundingRule, ruleID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		return nil, lookupError(err, errors.ErrFundingRuleNotFound, "Failed to update funding rule")
+	}
+
+	return s.GetFundingRule(userID, ruleID)
+}
+
+// DeleteFundingRule removes a funding rule; the contributions it generated are kept
+func (s *FinanceService) DeleteFundingRule(userID, ruleID uuid.UUID) error {
+	before, err := s.financeRepo.GetFundingRule(ruleID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrFundingRuleNotFound, "Failed to delete funding rule")
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteFundingRule(ruleID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityFundingRule, ruleID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrFundingRuleNotFound, "Failed to delete funding rule")
+	}
+	return nil
+}
+
+// RunScheduledFunding generates the contributions of fixed_monthly rules that fell due
+// up to now and have not been funded yet, for one user or, with a nil userID, for everyone.
+// Each rule is funded in its own transaction that first moves its last funded date on,
+// on condition that no other run did so since the rules were listed, so concurrent runs
+// never fund a month twice. A rule that fails is logged and retried on the next run
+// without holding up the others.
+func (s *FinanceService) RunScheduledFunding(userID *uuid.UUID, now time.Time) ([]response.GoalContributionResponse, error) {
+	rules, err := s.financeRepo.ListScheduledFundingRules()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list scheduled funding rules")
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	generated := []response.GoalContributionResponse{}
+	for _, rule := range rules {
+		if userID != nil && rule.UserID != *userID {
+			continue
+		}
+		due := fundingDueDates(rule, today)
+		if len(due) == 0 {
+			continue
+		}
+
+		var funded []response.GoalContributionResponse
+		err := s.transact(func(tx *FinanceService) error {
+			// The rule moves on even when its goal is already funded, so reaching the
+			// target does not leave months to catch up on if the target is raised later
+			claimed, err := tx.financeRepo.ClaimScheduledFunding(rule.ID, rule.UserID, rule.LastFundedOn, due[len(due)-1])
+			if err != nil || !claimed {
+				return err
+			}
+			for _, date := range due {
+				contributions, err := tx.fund(rule.UserID, []models.GoalFundingRule{rule}, nil, date, func(rule models.GoalFundingRule) float64 {
+					return *rule.Amount
+				})
+				if err != nil {
+					return err
+				}
+				funded = append(funded, contributions...)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("⚠️  Scheduled funding of rule %s failed: %v", rule.ID, err)
+			continue
+		}
+		generated = append(generated, funded...)
+	}
+	return generated, nil
+}
+
+// RunFundingScheduler runs RunScheduledFunding for every user immediately and then every interval until ctx is cancelled
+func (s *FinanceService) RunFundingScheduler(ctx context.Context, interval time.Duration) {
+	runEvery(ctx, interval, func() {
+		if generated, err := s.RunScheduledFunding(nil, time.Now().UTC()); err != nil {
+			log.Printf("⚠️  Scheduled goal funding failed: %v", err)
+		} else if len(generated) > 0 {
+			log.Printf("💰 Generated %d scheduled goal contribution(s)", len(generated))
+		}
+	})
+}
+
+// enabledFundingRules returns the user's enabled rules of one type
+func (s *FinanceService) enabledFundingRules(userID uuid.UUID, ruleType string) ([]models.GoalFundingRule, error) {
+	rules, err := s.financeRepo.ListFundingRules(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load funding rules")
+	}
+	enabled := rules[:0]
+	for _, rule := range rules {
+		if rule.Enabled && rule.Type == ruleType {
+			enabled = append(enabled, rule)
+		}
+	}
+	return enabled, nil
+}
+
+// fund creates a contribution for each rule dated at, of the amount amountFor returns
+// but never more than the goal still needs. Rules whose goal has reached its target,
+// is no longer active or was deleted generate nothing. It runs inside a transaction,
+// where the rules' goals stay locked from reading what they need until the
+// contributions are stored, so concurrent funding cannot overshoot a target.
+func (s *FinanceService) fund(userID uuid.UUID, rules []models.GoalFundingRule, sourceID *uuid.UUID, at time.Time, amountFor func(rule models.GoalFundingRule) float64) ([]response.GoalContributionResponse, error) {
+	// Goals are locked in id order so two transactions funding the same goals cannot deadlock
+	goalIDs := make([]uuid.UUID, 0, len(rules))
+	for _, rule := range rules {
+		goalIDs = append(goalIDs, rule.GoalID)
+	}
+	slices.SortFunc(goalIDs, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	for _, goalID := range slices.Compact(goalIDs) {
+		if _, err := s.financeRepo.LockGoal(goalID, userID); err != nil && !stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	goals, err := s.financeRepo.ListGoalsWithProgress(userID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := make(map[uuid.UUID]float64, len(goals))
+	for _, g := range activeGoals(goals) {
+		remaining[g.Goal.ID] = g.Goal.TargetAmount - g.ContributedSum
+	}
+
+	var generated []response.GoalContributionResponse
+	for _, rule := range rules {
+		left, ok := remaining[rule.GoalID]
+		if !ok {
+			continue
+		}
+		amount := roundCents(min(amountFor(rule), left))
+		if amount <= 0 {
+			continue
+		}
+		ruleID := rule.ID
+		contribution := &models.GoalContribution{
+			ID:            uuid.New(),
+			UserID:        userID,
+			GoalID:        rule.GoalID,
+			Amount:        amount,
+			ContributedAt: at,
+			FundingRuleID: &ruleID,
+			SourceID:      sourceID,
+			CreatedAt:     time.Now().UTC(),
+		}
+		if err := s.financeRepo.CreateGoalContribution(contribution); err != nil {
+			return nil, err
+		}
+		s.audit.record(userID, EntityGoalContribution, contribution.ID, AuditCreate, createdChanges(contribution))
+		remaining[rule.GoalID] = left - amount
+		generated = append(generated, goalContributionResponse(*contribution))
+	}
+	return generated, nil
+}
+
+// incomeFunding is the share of an income a percent_of_income rule contributes
+func incomeFunding(income *models.Income) func(rule models.GoalFundingRule) float64 {
+	return func(rule models.GoalFundingRule) float64 {
+		if rule.IncomeSource != nil && !strings.EqualFold(strings.TrimSpace(income.Source), *rule.IncomeSource) {
+			return 0
+		}
+		return income.Amount * *rule.Percent / 100
+	}
+}
+
+// expenseFunding is the round-up of an expense to the next multiple of a round_up rule's step.
+// Amounts are compared in cents so 0.1 + 0.2 style errors cannot produce a full extra step.
+func expenseFunding(expense *models.Expense) func(rule models.GoalFundingRule) float64 {
+	return func(rule models.GoalFundingRule) float64 {
+		cents, step := int64(math.Round(expense.Amount*100)), int64(math.Round(*rule.RoundTo*100))
+		if step <= 0 {
+			return 0
+		}
+		return float64((step-cents%step)%step) / 100
+	}
+}
+
+// fundingDueDates returns the dates a fixed_monthly rule falls due after it was last
+// funded, or since it was created, up to and including today. Days past the end of a
+// short month fall on its last day.
+func fundingDueDates(rule models.GoalFundingRule, today time.Time) []time.Time {
+	start := time.Date(rule.CreatedAt.Year(), rule.CreatedAt.Month(), rule.CreatedAt.Day(), 0, 0, 0, 0, time.UTC)
+	if rule.LastFundedOn != nil {
+		start = rule.LastFundedOn.UTC().AddDate(0, 0, 1)
+	}
+	var dates []time.Time
+	for month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(today); month = month.AddDate(0, 1, 0) {
+		lastDay := month.AddDate(0, 1, -1).Day()
+		due := time.Date(month.Year(), month.Month(), min(*rule.DayOfMonth, lastDay), 0, 0, 0, 0, time.UTC)
+		if !due.Before(start) && !due.After(today) {
+			dates = append(dates, due)
+		}
+	}
+	return dates
+}
+
+// checkFundingRule validates the settings of a rule's type and clears those of other types
+func checkFundingRule(rule *models.GoalFundingRule) error {
+	invalid := func(details string) error {
+		return errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid funding rule", details)
+	}
+	if rule.Name == "" {
+		return invalid("Name is required")
+	}
+	if rule.IncomeSource != nil {
+		if source := strings.TrimSpace(*rule.IncomeSource); source == "" {
+			rule.IncomeSource = nil
+		} else {
+			rule.IncomeSource = &source
+		}
+	}
+
+	switch rule.Type {
+	case models.FundingPercentOfIncome:
+		if rule.Percent == nil || *rule.Percent <= 0 || *rule.Percent > 100 {
+			return invalid("percent_of_income rules need a percent above 0 and at most 100")
+		}
+		rule.RoundTo, rule.Amount, rule.DayOfMonth = nil, nil, nil
+	case models.FundingRoundUp:
+		if rule.RoundTo == nil || *rule.RoundTo < 0.01 {
+			return invalid("round_up rules need a round_to of at least 0.01")
+		}
+		rule.Percent, rule.IncomeSource, rule.Amount, rule.DayOfMonth = nil, nil, nil, nil
+	case models.FundingFixedMonthly:
+		if rule.Amount == nil {
+			return invalid("fixed_monthly rules need an amount")
+		}
+		if err := validation.ValidateAmount(*rule.Amount); err != nil {
+			return err
+		}
+		if rule.DayOfMonth == nil || *rule.DayOfMonth < 1 || *rule.DayOfMonth > 31 {
+			return invalid("fixed_monthly rules need a day_of_month between 1 and 31")
+		}
+		rule.Percent, rule.IncomeSource, rule.RoundTo = nil, nil, nil
+	default:
+		return invalid("type must be percent_of_income, round_up or fixed_monthly")
+	}
+	return nil
+}
+
+// fundingRuleColumn returns the value of one of a rule's editable columns
+func fundingRuleColumn(rule *models.GoalFundingRule, column string) interface{} {
+	switch column {
+	case "name":
+		return rule.Name
+	case "enabled":
+		return rule.Enabled
+	case "percent":
+		return rule.Percent
+	case "income_source":
+		return rule.IncomeSource
+	case "round_to":
+		return rule.RoundTo
+	case "amount":
+		return rule.Amount
+	case "day_of_month":
+		return rule.DayOfMonth
+	}
+	return nil
+}
+
+func fundingRuleResponse(rule models.GoalFundingRule) response.FundingRuleResponse {
+	return response.FundingRuleResponse{
+		ID:           rule.ID,
+		UserID:       rule.UserID,
+		GoalID:       rule.GoalID,
+		Name:         rule.Name,
+		Type:         rule.Type,
+		Enabled:      rule.Enabled,
+		Percent:      rule.Percent,
+		IncomeSource: rule.IncomeSource,
+		RoundTo:      rule.RoundTo,
+		Amount:       rule.Amount,
+		DayOfMonth:   rule.DayOfMonth,
+		LastFundedOn: rule.LastFundedOn,
+		CreatedAt:    rule.CreatedAt,
+		UpdatedAt:    rule.UpdatedAt,
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestIncomesAndExpensesFundGoals(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+
+	goal := &models.Goal{ID: uuid.New(), UserID: userID, Name: "Holiday", TargetAmount: 250, CreatedAt: time.Now()}
+	if err := repo.CreateGoal(goal); err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	percent, roundTo := 10.0, 1.0
+	salary := " salary "
+	if _, err := finance.CreateFundingRule(userID, &request.CreateFundingRuleRequest{
+		GoalID: goal.ID, Name: "Tithe", Type: models.FundingPercentOfIncome, Percent: &percent, IncomeSource: &salary,
+	}); err != nil {
+		t.Fatalf("CreateFundingRule percent: %v", err)
+	}
+	if _, err := finance.CreateFundingRule(userID, &request.CreateFundingRuleRequest{
+		GoalID: goal.ID, Name: "Spare change", Type: models.FundingRoundUp, RoundTo: &roundTo,
+	}); err != nil {
+		t.Fatalf("CreateFundingRule round-up: %v", err)
+	}
+
+	income, err := finance.CreateIncome(userID, &request.CreateIncomeRequest{Source: "Salary", Amount: 2000, ReceivedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+	if len(income.Funding) != 1 || income.Funding[0].Amount != 200 || *income.Funding[0].SourceID != income.ID {
+		t.Fatalf("expected 10%% of the salary to fund the goal, got %+v", income.Funding)
+	}
+	bonus, err := finance.CreateIncome(userID, &request.CreateIncomeRequest{Source: "Bonus", Amount: 500, ReceivedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+	if len(bonus.Funding) != 0 {
+		t.Fatalf("expected incomes from other sources not to fund the goal, got %+v", bonus.Funding)
+	}
+
+	expense, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Food", Amount: 3.3, SpentAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	if len(expense.Funding) != 1 || expense.Funding[0].Amount != 0.7 {
+		t.Fatalf("expected a 0.70 round-up, got %+v", expense.Funding)
+	}
+	exact, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Food", Amount: 4, SpentAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	if len(exact.Funding) != 0 {
+		t.Fatalf("expected whole amounts not to round up, got %+v", exact.Funding)
+	}
+
+	// Only 49.30 is left to reach the target
+	income, err = finance.CreateIncome(userID, &request.CreateIncomeRequest{Source: "SALARY", Amount: 2000, ReceivedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+	if len(income.Funding) != 1 || income.Funding[0].Amount != 49.3 {
+		t.Fatalf("expected the contribution to stop at the target, got %+v", income.Funding)
+	}
+	income, err = finance.CreateIncome(userID, &request.CreateIncomeRequest{Source: "Salary", Amount: 2000, ReceivedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+	if len(income.Funding) != 0 {
+		t.Fatalf("expected a funded goal to receive nothing more, got %+v", income.Funding)
+	}
+}
+
+func TestScheduledFundingCatchesUpMissedMonths(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+
+	goal := &models.Goal{ID: uuid.New(), UserID: userID, Name: "Car", TargetAmount: 1000, CreatedAt: time.Now()}
+	if err := repo.CreateGoal(goal); err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	amount, day := 100.0, 31
+	rule := &models.GoalFundingRule{
+		ID: uuid.New(), UserID: userID, GoalID: goal.ID, Name: "Monthly", Type: models.FundingFixedMonthly, Enabled: true,
+		Amount: &amount, DayOfMonth: &day, CreatedAt: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+	}
+	if err := repo.CreateFundingRule(rule); err != nil {
+		t.Fatalf("CreateFundingRule: %v", err)
+	}
+
+	generated, err := finance.RunScheduledFunding(&userID, time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("RunScheduledFunding: %v", err)
+	}
+	if len(generated) != 2 {
+		t.Fatalf("expected January and February to be funded, got %+v", generated)
+	}
+	if d := generated[1].ContributedAt; d.Month() != time.February || d.Day() != 28 {
+		t.Fatalf("expected the February contribution on its last day, got %v", d)
+	}
+
+	// Running again the same day generates nothing new
+	generated, err = finance.RunScheduledFunding(&userID, time.Date(2025, 3, 5, 18, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("RunScheduledFunding: %v", err)
+	}
+	if len(generated) != 0 {
+		t.Fatalf("expected no duplicate contributions, got %+v", generated)
+	}
+
+	// Disabled rules are skipped by the scheduler
+	enabled := false
+	if _, err := finance.UpdateFundingRule(userID, rule.ID, &request.UpdateFundingRuleRequest{Enabled: &enabled}); err != nil {
+		t.Fatalf("UpdateFundingRule: %v", err)
+	}
+	generated, err = finance.RunScheduledFunding(nil, time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("RunScheduledFunding: %v", err)
+	}
+	if len(generated) != 0 {
+		t.Fatalf("expected a disabled rule to generate nothing, got %+v", generated)
+	}
+}
+
+// staleRulesRepository lists the scheduled rules as they were before another run funded them
+type staleRulesRepository struct {
+	repository.FinanceRepositoryInterface
+	rules []models.GoalFundingRule
+}
+
+func (r staleRulesRepository) ListScheduledFundingRules() ([]models.GoalFundingRule, error) {
+	return r.rules, nil
+}
+
+func TestScheduledFundingSkipsRulesAnotherRunFunded(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+
+	goal := &models.Goal{ID: uuid.New(), UserID: userID, Name: "Car", TargetAmount: 1000, CreatedAt: time.Now()}
+	if err := repo.CreateGoal(goal); err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	amount, day := 100.0, 1
+	rule := &models.GoalFundingRule{
+		ID: uuid.New(), UserID: userID, GoalID: goal.ID, Name: "Monthly", Type: models.FundingFixedMonthly, Enabled: true,
+		Amount: &amount, DayOfMonth: &day, CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := repo.CreateFundingRule(rule); err != nil {
+		t.Fatalf("CreateFundingRule: %v", err)
+	}
+	stale, err := repo.ListScheduledFundingRules()
+	if err != nil {
+		t.Fatalf("ListScheduledFundingRules: %v", err)
+	}
+
+	now := time.Date(2025, 2, 5, 9, 0, 0, 0, time.UTC)
+	if generated, err := finance.RunScheduledFunding(nil, now); err != nil || len(generated) != 2 {
+		t.Fatalf("expected January and February to be funded, got %+v, %v", generated, err)
+	}
+	// A run that listed the rules before the first one committed funds nothing
+	concurrent := NewFinanceService(staleRulesRepository{FinanceRepositoryInterface: repo, rules: stale}, nil, nil)
+	if generated, err := concurrent.RunScheduledFunding(nil, now); err != nil || len(generated) != 0 {
+		t.Fatalf("expected the months already funded to be skipped, got %+v, %v", generated, err)
+	}
+	sums, err := repo.SumGoalContributions(userID, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), now)
+	if err != nil || sums[goal.ID] != 200 {
+		t.Fatalf("expected 200 contributed, got %v, %v", sums[goal.ID], err)
+	}
+}
+
+func TestCreateFundingRuleValidatesSettings(t *testing.T) {
+	finance := newTestFinanceService()
+	userID := uuid.New()
+	goal, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Bike", TargetAmount: 500})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+
+	percent, day := 150.0, 0
+	for name, req := range map[string]request.CreateFundingRuleRequest{
+		"percent above 100":    {GoalID: goal.ID, Name: "Too much", Type: models.FundingPercentOfIncome, Percent: &percent},
+		"missing round_to":     {GoalID: goal.ID, Name: "Round", Type: models.FundingRoundUp},
+		"day_of_month of 0":    {GoalID: goal.ID, Name: "Monthly", Type: models.FundingFixedMonthly, Amount: &percent, DayOfMonth: &day},
+		"missing goal":         {GoalID: uuid.New(), Name: "Orphan", Type: models.FundingRoundUp},
+		"fixed without amount": {GoalID: goal.ID, Name: "Monthly", Type: models.FundingFixedMonthly},
+	} {
+		if _, err := finance.CreateFundingRule(userID, &req); err == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+	"finance-management/internal/validation"
+
+	"github.com/google/uuid"
+)
+
+// ListGoalCategories retrieves the built-in goal categories and the user's own.
+// Hidden categories are only listed when includeHidden is set.
+func (s *FinanceService) ListGoalCategories(userID uuid.UUID, includeHidden bool) ([]response.GoalCategoryResponse, error) {
+	categories, err := s.financeRepo.ListGoalCategories(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list goal categories")
+	}
+
+	responses := []response.GoalCategoryResponse{}
+	for _, category := range categories {
+		if category.Hidden && !includeHidden {
+			continue
+		}
+		responses = append(responses, goalCategoryResponse(category))
+	}
+	return responses, nil
+}
+
+// GetGoalCategory retrieves a built-in goal category or one of the user's own
+func (s *FinanceService) GetGoalCategory(userID, categoryID uuid.UUID) (*response.GoalCategoryResponse, error) {
+	category, err := s.financeRepo.GetGoalCategory(categoryID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrGoalCategoryNotFound, "Failed to get goal category")
+	}
+	resp := goalCategoryResponse(*category)
+	return &resp, nil
+}
+
+// CreateGoalCategory adds a goal category of the user's own. Its name must differ
+// from the built-in categories' and the user's other ones, ignoring case.
+func (s *FinanceService) CreateGoalCategory(userID uuid.UUID, req *request.CreateGoalCategoryRequest) (*response.GoalCategoryResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := validation.ValidateCategoryName(name); err != nil {
+		return nil, err
+	}
+
+	owner := userID
+	category := &models.GoalCategory{
+		ID:          uuid.New(),
+		UserID:      &owner,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Icon:        strings.TrimSpace(req.Icon),
+		Color:       strings.ToLower(req.Color),
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateGoalCategory(category); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoalCategory, category.ID, AuditCreate, createdChanges(category))
+		return nil
+	})
+	if err != nil {
+		return nil, goalCategoryError(err, "Failed to create goal category")
+	}
+
+	resp := goalCategoryResponse(*category)
+	return &resp, nil
+}
+
+// UpdateGoalCategory edits or hides one of the user's goal categories. A new name
+// is copied to every goal filed under the category; built-ins cannot be changed.
+func (s *FinanceService) UpdateGoalCategory(userID, categoryID uuid.UUID, req *request.UpdateGoalCategoryRequest) (*response.GoalCategoryResponse, error) {
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := validation.ValidateCategoryName(name); err != nil {
+			return nil, err
+		}
+		updates["name"] = name
+	}
+	if req.Description != nil {
+		updates["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.Icon != nil {
+		updates["icon"] = strings.TrimSpace(*req.Icon)
+	}
+	if req.Color != nil {
+		updates["color"] = strings.ToLower(*req.Color)
+	}
+	if req.Hidden != nil {
+		updates["hidden"] = *req.Hidden
+	}
+	if len(updates) == 0 {
+		return nil, errors.ErrInvalidInput
+	}
+
+	before, err := s.financeRepo.GetGoalCategory(categoryID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrGoalCategoryNotFound, "Failed to update goal category")
+	}
+	if before.UserID == nil {
+		return nil, errors.ErrGoalCategoryBuiltIn
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateGoalCategory(categoryID, userID, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoalCategory, categoryID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		return nil, goalCategoryError(err, "Failed to update goal category")
+	}
+
+	return s.GetGoalCategory(userID, categoryID)
+}
+
+// goalCategoryFor finds the category a goal is filed under: the one given by ID,
+// or otherwise the one named name, ignoring case. Hidden categories take no goals.
+func (s *FinanceService) goalCategoryFor(userID uuid.UUID, categoryID *uuid.UUID, name string) (*models.GoalCategory, error) {
+	var category *models.GoalCategory
+	if categoryID != nil {
+		found, err := s.financeRepo.GetGoalCategory(*categoryID, userID)
+		if err != nil {
+			return nil, lookupError(err, errors.ErrGoalCategoryNotFound, "Failed to get goal category")
+		}
+		category = found
+	} else {
+		name = strings.TrimSpace(name)
+		categories, err := s.financeRepo.ListGoalCategories(userID)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list goal categories")
+		}
+		for i := range categories {
+			if strings.EqualFold(categories[i].Name, name) {
+				category = &categories[i]
+				break
+			}
+		}
+		if category == nil {
+			return nil, errors.NewWithDetails(
+				errors.ErrGoalCategoryNotFound.Code,
+				errors.ErrGoalCategoryNotFound.Message,
+				fmt.Sprintf("No goal category is named %q", name),
+			)
+		}
+	}
+	if category.Hidden {
+		return nil, errors.ErrGoalCategoryHidden
+	}
+	return category, nil
+}
+
+// linkGoalCategoryUpdates turns a goal update's category name or ID into updates
+// of both, keeping them consistent. Clearing either takes the goal out of its category.
+func (s *FinanceService) linkGoalCategoryUpdates(userID uuid.UUID, req *request.UpdateGoalRequest, updates map[string]interface{}) error {
+	var categoryID *uuid.UUID
+	name := ""
+	if req.CategoryID.Set {
+		categoryID = req.CategoryID.ID
+	} else {
+		name = strings.TrimSpace(*req.Category)
+	}
+	if categoryID == nil && name == "" {
+		updates["category"] = ""
+		updates["category_id"] = nil
+		return nil
+	}
+	category, err := s.goalCategoryFor(userID, categoryID, name)
+	if err != nil {
+		return err
+	}
+	updates["category"] = category.Name
+	updates["category_id"] = category.ID
+	return nil
+}
+
+// goalCategoryError maps goal category repository errors to API errors
+func goalCategoryError(err error, message string) error {
+	if stderrors.Is(err, repository.ErrGoalCategoryExists) {
+		return errors.ErrGoalCategoryExists
+	}
+	return lookupError(err, errors.ErrGoalCategoryNotFound, message)
+}
+
+// goalCategoryResponse converts a goal category model to its API representation
+func goalCategoryResponse(category models.GoalCategory) response.GoalCategoryResponse {
+	return response.GoalCategoryResponse{
+		ID:          category.ID,
+		UserID:      category.UserID,
+		Name:        category.Name,
+		Description: category.Description,
+		Icon:        category.Icon,
+		Color:       category.Color,
+		BuiltIn:     category.UserID == nil,
+		Hidden:      category.Hidden,
+		CreatedAt:   category.CreatedAt,
+		UpdatedAt:   category.UpdatedAt,
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestUserGoalCategories(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+
+	pets, err := finance.CreateGoalCategory(userID, &request.CreateGoalCategoryRequest{Name: " Pets ", Icon: "paw", Color: "#A855F7"})
+	if err != nil {
+		t.Fatalf("CreateGoalCategory: %v", err)
+	}
+	if pets.Name != "Pets" || pets.Color != "#a855f7" || pets.BuiltIn || pets.UserID == nil {
+		t.Fatalf("unexpected category %+v", pets)
+	}
+	if _, err := finance.CreateGoalCategory(userID, &request.CreateGoalCategoryRequest{Name: "travel"}); err != errors.ErrGoalCategoryExists {
+		t.Fatalf("expected a built-in name to be taken, got %v", err)
+	}
+
+	categories, err := finance.ListGoalCategories(userID, false)
+	if err != nil {
+		t.Fatalf("ListGoalCategories: %v", err)
+	}
+	var travel uuid.UUID
+	for _, c := range categories {
+		if c.Name == "Travel" {
+			travel = c.ID
+			if !c.BuiltIn || c.Icon != "plane" || c.Color != "#3b82f6" {
+				t.Fatalf("expected the built-in Travel category with its icon and colour, got %+v", c)
+			}
+		}
+	}
+	if len(categories) != 9 || travel == uuid.Nil {
+		t.Fatalf("expected the built-ins and the user's category, got %+v", categories)
+	}
+	hidden := true
+	if _, err := finance.UpdateGoalCategory(userID, travel, &request.UpdateGoalCategoryRequest{Hidden: &hidden}); err != errors.ErrGoalCategoryBuiltIn {
+		t.Fatalf("expected built-in categories to be read-only, got %v", err)
+	}
+
+	// Goals link to categories by name, ignoring case, or by ID
+	vet, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Vet bills", Category: "pets", TargetAmount: 500})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	if vet.CategoryID == nil || *vet.CategoryID != pets.ID || vet.Category != "Pets" {
+		t.Fatalf("expected the goal to be linked to Pets, got %+v", vet)
+	}
+	if _, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Boat", Category: "Sailing", TargetAmount: 500}); err == nil {
+		t.Fatal("expected an unknown category name to be rejected")
+	}
+
+	// Renaming follows through to goals; hiding drops the category from listings and new goals
+	name := "Animals"
+	if _, err := finance.UpdateGoalCategory(userID, pets.ID, &request.UpdateGoalCategoryRequest{Name: &name, Hidden: &hidden}); err != nil {
+		t.Fatalf("UpdateGoalCategory: %v", err)
+	}
+	goal, _ := finance.GetGoal(userID, vet.ID)
+	if goal.Category != "Animals" {
+		t.Fatalf("expected the goal to follow the rename, got %q", goal.Category)
+	}
+	if categories, _ := finance.ListGoalCategories(userID, false); len(categories) != 8 {
+		t.Fatalf("expected the hidden category to be left out, got %d", len(categories))
+	}
+	if categories, _ := finance.ListGoalCategories(userID, true); len(categories) != 9 {
+		t.Fatalf("expected the hidden category when asked for, got %d", len(categories))
+	}
+	if _, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Kitten", CategoryID: &pets.ID, TargetAmount: 100}); err != errors.ErrGoalCategoryHidden {
+		t.Fatalf("expected hidden categories to take no new goals, got %v", err)
+	}
+
+	moved, err := finance.UpdateGoal(userID, vet.ID, 0, &request.UpdateGoalRequest{CategoryID: request.SetID(&travel)})
+	if err != nil {
+		t.Fatalf("UpdateGoal: %v", err)
+	}
+	if moved.Category != "Travel" || moved.CategoryID == nil || *moved.CategoryID != travel {
+		t.Fatalf("expected the goal to move to Travel, got %+v", moved)
+	}
+	none := ""
+	cleared, err := finance.UpdateGoal(userID, vet.ID, 0, &request.UpdateGoalRequest{Category: &none})
+	if err != nil {
+		t.Fatalf("UpdateGoal: %v", err)
+	}
+	if cleared.Category != "" || cleared.CategoryID != nil {
+		t.Fatalf("expected the goal to leave its category, got %+v", cleared)
+	}
+
+	if _, err := finance.UpdateGoal(userID, vet.ID, 0, &request.UpdateGoalRequest{CategoryID: request.SetID(&travel)}); err != nil {
+		t.Fatalf("UpdateGoal: %v", err)
+	}
+	var unlink request.UpdateGoalRequest
+	if err := json.Unmarshal([]byte(`{"category_id": null}`), &unlink); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	unlinked, err := finance.UpdateGoal(userID, vet.ID, 0, &unlink)
+	if err != nil {
+		t.Fatalf("UpdateGoal: %v", err)
+	}
+	if unlinked.Category != "" || unlinked.CategoryID != nil {
+		t.Fatalf("expected a null category_id to take the goal out of its category, got %+v", unlinked)
+	}
+}
@@ -0,0 +1,436 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// templateAverageMonths is how many complete months before now the averages in
+	// template target formulas cover
+	templateAverageMonths = 6
+	// maxTemplateDepth bounds how deeply template sub-goals nest
+	maxTemplateDepth = 5
+	// maxTemplateGoals bounds how many goals one template creates
+	maxTemplateGoals = 50
+)
+
+// ListGoalTemplates retrieves the built-in goal templates and the user's own
+func (s *FinanceService) ListGoalTemplates(userID uuid.UUID) ([]response.GoalTemplateResponse, error) {
+	templates, err := s.financeRepo.ListGoalTemplates(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list goal templates")
+	}
+	responses := make([]response.GoalTemplateResponse, len(templates))
+	for i, template := range templates {
+		responses[i] = goalTemplateResponse(template)
+	}
+	return responses, nil
+}
+
+// GetGoalTemplate retrieves a built-in goal template or one of the user's own
+func (s *FinanceService) GetGoalTemplate(userID, templateID uuid.UUID) (*response.GoalTemplateResponse, error) {
+	template, err := s.financeRepo.GetGoalTemplate(templateID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrGoalTemplateNotFound, "Failed to get goal template")
+	}
+	resp := goalTemplateResponse(*template)
+	return &resp, nil
+}
+
+// CreateGoalTemplate adds a goal template of the user's own. It is defined by the
+// request's goal or, when goal_id is set, saved from that goal and its sub-goals.
+func (s *FinanceService) CreateGoalTemplate(userID uuid.UUID, req *request.CreateGoalTemplateRequest) (*response.GoalTemplateResponse, error) {
+	var goal models.TemplateGoal
+	switch {
+	case req.Goal != nil && req.GoalID != nil:
+		return nil, errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid goal template", "Send either goal or goal_id, not both")
+	case req.GoalID != nil:
+		saved, err := s.templateFromGoal(userID, *req.GoalID)
+		if err != nil {
+			return nil, err
+		}
+		goal = *saved
+	case req.Goal != nil:
+		goal = templateGoal(*req.Goal)
+	default:
+		return nil, errors.NewWithDetails(errors.ErrMissingField.Code, "Invalid goal template", "Send the template's goal or the goal_id to save")
+	}
+	definition, err := templateDefinition(goal)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := userID
+	template := &models.GoalTemplate{
+		ID:          uuid.New(),
+		UserID:      &owner,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Definition:  definition,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	if template.Name == "" {
+		return nil, errors.NewWithDetails(errors.ErrMissingField.Code, "Template name is required", "Template name cannot be empty")
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateGoalTemplate(template); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoalTemplate, template.ID, AuditCreate, createdChanges(template))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create goal template")
+	}
+
+	resp := goalTemplateResponse(*template)
+	return &resp, nil
+}
+
+// UpdateGoalTemplate edits one of the user's goal templates; built-ins cannot be changed
+func (s *FinanceService) UpdateGoalTemplate(userID, templateID uuid.UUID, req *request.UpdateGoalTemplateRequest) (*response.GoalTemplateResponse, error) {
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.NewWithDetails(errors.ErrMissingField.Code, "Template name is required", "Template name cannot be empty")
+		}
+		updates["name"] = name
+	}
+	if req.Description != nil {
+		updates["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.Goal != nil {
+		definition, err := templateDefinition(templateGoal(*req.Goal))
+		if err != nil {
+			return nil, err
+		}
+		updates["definition"] = definition
+	}
+	if len(updates) == 0 {
+		return nil, errors.ErrInvalidInput
+	}
+
+	before, err := s.financeRepo.GetGoalTemplate(templateID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrGoalTemplateNotFound, "Failed to update goal template")
+	}
+	if before.UserID == nil {
+		return nil, errors.ErrGoalTemplateBuiltIn
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateGoalTemplate(templateID, userID, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoalTemplate, templateID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		return nil, lookupError(err, errors.ErrGoalTemplateNotFound, "Failed to update goal template")
+	}
+
+	return s.GetGoalTemplate(userID, templateID)
+}
+
+// DeleteGoalTemplate removes one of the user's goal templates; goals created from it stay
+func (s *FinanceService) DeleteGoalTemplate(userID, templateID uuid.UUID) error {
+	before, err := s.financeRepo.GetGoalTemplate(templateID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrGoalTemplateNotFound, "Failed to delete goal template")
+	}
+	if before.UserID == nil {
+		return errors.ErrGoalTemplateBuiltIn
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteGoalTemplate(templateID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoalTemplate, templateID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrGoalTemplateNotFound, "Failed to delete goal template")
+	}
+	return nil
+}
+
+// InstantiateGoalTemplate creates a template's goals through CreateGoal in one
+// transaction. Targets are worked out from the user's average monthly expenses and
+// income over the complete months before now, and target dates count from today.
+func (s *FinanceService) InstantiateGoalTemplate(userID, templateID uuid.UUID, req *request.InstantiateGoalTemplateRequest, now time.Time) (*response.GoalTemplateInstanceResponse, error) {
+	template, err := s.financeRepo.GetGoalTemplate(templateID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrGoalTemplateNotFound, "Failed to get goal template")
+	}
+	var goal models.TemplateGoal
+	if err := json.Unmarshal([]byte(template.Definition), &goal); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to read goal template")
+	}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		goal.Name = name
+	}
+
+	expenses, income, err := s.templateAverages(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if req.AvgMonthlyExpenses != nil {
+		expenses = *req.AvgMonthlyExpenses
+	}
+	if req.AvgMonthlyIncome != nil {
+		income = *req.AvgMonthlyIncome
+	}
+	resp := &response.GoalTemplateInstanceResponse{
+		TemplateID:         templateID,
+		AvgMonthlyExpenses: roundCents(expenses),
+		AvgMonthlyIncome:   roundCents(income),
+		Goals:              []response.GoalResponse{},
+	}
+	vars := map[string]float64{models.TemplateAvgExpenses: resp.AvgMonthlyExpenses, models.TemplateAvgIncome: resp.AvgMonthlyIncome}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	err = s.transact(func(tx *FinanceService) error {
+		created, err := tx.createTemplateGoal(userID, goal, nil, vars, today)
+		resp.Goals = created
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// createTemplateGoal creates goal under parent, then its sub-goals, and returns them
+// all in creation order
+func (s *FinanceService) createTemplateGoal(userID uuid.UUID, goal models.TemplateGoal, parent *response.GoalResponse, vars map[string]float64, today time.Time) ([]response.GoalResponse, error) {
+	formula, err := parseTargetFormula(goal.Target, templateVariables(parent != nil))
+	if err != nil {
+		return nil, errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid goal template", fmt.Sprintf("Target of %q: %v", goal.Name, err))
+	}
+	if parent != nil {
+		vars[models.TemplateParent] = parent.TargetAmount
+	}
+	target := roundCents(formula(vars))
+	if math.IsNaN(target) || math.IsInf(target, 0) || target <= 0 {
+		return nil, errors.NewWithDetails(
+			errors.ErrInvalidAmount.Code,
+			"Goal target amount must be positive",
+			fmt.Sprintf("The target of %q, %s, works out at %.2f; pass the averages to use instead", goal.Name, goal.Target, target),
+		)
+	}
+
+	req := &request.CreateGoalRequest{
+		Name:         goal.Name,
+		Description:  goal.Description,
+		Category:     goal.Category,
+		TargetAmount: target,
+		IsMainGoal:   parent == nil,
+	}
+	if goal.Months > 0 {
+		due := today.AddDate(0, goal.Months, 0)
+		req.TargetDate = &due
+	}
+	if parent != nil {
+		req.ParentGoalID = &parent.ID
+	}
+	created, err := s.CreateGoal(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	goals := []response.GoalResponse{*created}
+	for _, sub := range goal.Subgoals {
+		subgoals, err := s.createTemplateGoal(userID, sub, created, vars, today)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, subgoals...)
+	}
+	return goals, nil
+}
+
+// templateAverages returns the user's average monthly expenses and income over the
+// templateAverageMonths complete months before now. A shorter history is averaged
+// over the months since its first transaction so it is not diluted.
+func (s *FinanceService) templateAverages(userID uuid.UUID, now time.Time) (float64, float64, error) {
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, -templateAverageMonths, 0)
+	incomes, err := s.financeRepo.ListIncomesBetween(userID, start, end)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load incomes")
+	}
+	expenses, err := s.financeRepo.ListExpensesBetween(userID, start, end)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load expenses")
+	}
+
+	first := end
+	var income, spent float64
+	for _, i := range incomes {
+		income += i.Amount
+		if i.ReceivedAt.Before(first) {
+			first = i.ReceivedAt
+		}
+	}
+	for _, e := range expenses {
+		spent += e.Amount
+		if e.SpentAt.Before(first) {
+			first = e.SpentAt
+		}
+	}
+	months := (end.Year()-first.Year())*12 + int(end.Month()) - int(first.Month())
+	if months == 0 {
+		return 0, 0, nil
+	}
+	return spent / float64(months), income / float64(months), nil
+}
+
+// templateFromGoal describes a goal and its sub-goals, with their current targets
+// and the months between their creation and target dates
+func (s *FinanceService) templateFromGoal(userID, goalID uuid.UUID) (*models.TemplateGoal, error) {
+	if _, err := s.financeRepo.GetGoal(goalID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrGoalNotFound, "Failed to get goal")
+	}
+	goals, err := s.financeRepo.ListGoalsWithProgress(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list goals")
+	}
+	byID := make(map[uuid.UUID]models.Goal, len(goals))
+	children := make(map[uuid.UUID][]models.Goal)
+	for _, g := range goals {
+		byID[g.Goal.ID] = g.Goal
+		if g.Goal.ParentGoalID != nil {
+			children[*g.Goal.ParentGoalID] = append(children[*g.Goal.ParentGoalID], g.Goal)
+		}
+	}
+	for _, siblings := range children {
+		sort.SliceStable(siblings, func(i, j int) bool { return siblings[i].CreatedAt.Before(siblings[j].CreatedAt) })
+	}
+
+	var describe func(goal models.Goal, depth int) models.TemplateGoal
+	describe = func(goal models.Goal, depth int) models.TemplateGoal {
+		t := models.TemplateGoal{
+			Name:        goal.Name,
+			Description: goal.Description,
+			Target:      strconv.FormatFloat(roundCents(goal.TargetAmount), 'f', -1, 64),
+		}
+		// Unlinked names from before goal categories had IDs might not resolve
+		if goal.CategoryID != nil {
+			t.Category = goal.Category
+		}
+		if goal.TargetDate != nil {
+			t.Months = max(int(math.Round(monthsBetween(goal.CreatedAt, *goal.TargetDate))), 1)
+		}
+		// The depth bound guards against a cycle in corrupt data
+		if depth < maxTemplateDepth {
+			for _, child := range children[goal.ID] {
+				t.Subgoals = append(t.Subgoals, describe(child, depth+1))
+			}
+		}
+		return t
+	}
+	t := describe(byID[goalID], 1)
+	return &t, nil
+}
+
+// templateDefinition validates a template's goals and encodes them for storage
+func templateDefinition(goal models.TemplateGoal) (string, error) {
+	count := 0
+	var check func(goal models.TemplateGoal, depth int) error
+	check = func(goal models.TemplateGoal, depth int) error {
+		if count++; count > maxTemplateGoals {
+			return fmt.Errorf("a template creates at most %d goals", maxTemplateGoals)
+		}
+		if depth > maxTemplateDepth {
+			return fmt.Errorf("sub-goals nest at most %d levels deep", maxTemplateDepth)
+		}
+		if goal.Name == "" {
+			return fmt.Errorf("every goal needs a name")
+		}
+		if _, err := parseTargetFormula(goal.Target, templateVariables(depth > 1)); err != nil {
+			return fmt.Errorf("target of %q: %v", goal.Name, err)
+		}
+		for _, sub := range goal.Subgoals {
+			if err := check(sub, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := check(goal, 1); err != nil {
+		return "", errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid goal template", err.Error())
+	}
+	definition, err := json.Marshal(goal)
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrInvalidInput.Code, "Invalid goal template")
+	}
+	return string(definition), nil
+}
+
+// templateVariables lists the variables a target formula may use; only sub-goals have a parent
+func templateVariables(subgoal bool) []string {
+	if subgoal {
+		return []string{models.TemplateAvgExpenses, models.TemplateAvgIncome, models.TemplateParent}
+	}
+	return []string{models.TemplateAvgExpenses, models.TemplateAvgIncome}
+}
+
+// templateGoal converts a requested template goal to its stored form
+func templateGoal(req request.TemplateGoalRequest) models.TemplateGoal {
+	goal := models.TemplateGoal{
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Category:    strings.TrimSpace(req.Category),
+		Target:      strings.TrimSpace(req.Target),
+		Months:      req.Months,
+	}
+	for _, sub := range req.Subgoals {
+		goal.Subgoals = append(goal.Subgoals, templateGoal(sub))
+	}
+	return goal
+}
+
+// goalTemplateResponse converts a goal template model to its API representation
+func goalTemplateResponse(template models.GoalTemplate) response.GoalTemplateResponse {
+	var goal models.TemplateGoal
+	_ = json.Unmarshal([]byte(template.Definition), &goal)
+	return response.GoalTemplateResponse{
+		ID:          template.ID,
+		UserID:      template.UserID,
+		Name:        template.Name,
+		Description: template.Description,
+		BuiltIn:     template.UserID == nil,
+		Goal:        templateGoalResponse(goal),
+		CreatedAt:   template.CreatedAt,
+		UpdatedAt:   template.UpdatedAt,
+	}
+}
+
+func templateGoalResponse(goal models.TemplateGoal) response.TemplateGoalResponse {
+	resp := response.TemplateGoalResponse{
+		Name:        goal.Name,
+		Description: goal.Description,
+		Category:    goal.Category,
+		Target:      goal.Target,
+		Months:      goal.Months,
+		Subgoals:    make([]response.TemplateGoalResponse, len(goal.Subgoals)),
+	}
+	for i, sub := range goal.Subgoals {
+		resp.Subgoals[i] = templateGoalResponse(sub)
+	}
+	return resp
+}
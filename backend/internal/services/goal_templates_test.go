@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestTargetFormula(t *testing.T) {
+	vars := map[string]float64{models.TemplateAvgExpenses: 2000, models.TemplateAvgIncome: 3000, models.TemplateParent: 500}
+	allowed := templateVariables(true)
+	for src, want := range map[string]float64{
+		"6 * avg_monthly_expenses":                        12000,
+		"1000 + avg_monthly_income / 2":                   2500,
+		"(avg_monthly_income - avg_monthly_expenses) * 3": 3000,
+		"parent * 0.4":                                    200,
+		"-parent + 1000":                                  500,
+	} {
+		f, err := parseTargetFormula(src, allowed)
+		if err != nil {
+			t.Fatalf("parseTargetFormula(%q): %v", src, err)
+		}
+		if got := f(vars); got != want {
+			t.Fatalf("%q: expected %v, got %v", src, want, got)
+		}
+	}
+	for _, src := range []string{"", "6 *", "(1 + 2", "salary * 2", "1 2", "3 % 2"} {
+		if _, err := parseTargetFormula(src, allowed); err == nil {
+			t.Fatalf("expected %q to be rejected", src)
+		}
+	}
+	if _, err := parseTargetFormula("parent / 2", templateVariables(false)); err == nil {
+		t.Fatal("expected main goals not to refer to a parent")
+	}
+}
+
+func TestGoalTemplates(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+	// Goals are created with the current time, so the template's months are measured from it
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	templates, err := finance.ListGoalTemplates(userID)
+	if err != nil {
+		t.Fatalf("ListGoalTemplates: %v", err)
+	}
+	builtIn := map[string]uuid.UUID{}
+	for _, tmpl := range templates {
+		builtIn[tmpl.Name] = tmpl.ID
+	}
+	if _, ok := builtIn["Emergency fund"]; !ok {
+		t.Fatalf("expected the built-in templates, got %+v", templates)
+	}
+
+	// Without any history the emergency fund has no target
+	if _, err := finance.InstantiateGoalTemplate(userID, builtIn["Emergency fund"], &request.InstantiateGoalTemplateRequest{}, now); err == nil {
+		t.Fatal("expected a zero target to be rejected")
+	}
+
+	// Three months of history average over those three months, not six
+	for i := 1; i <= 3; i++ {
+		month := thisMonth.AddDate(0, -i, 0)
+		if err := repo.CreateIncome(&models.Income{ID: uuid.New(), UserID: userID, Source: "Salary", Amount: 3000, ReceivedAt: month}); err != nil {
+			t.Fatalf("CreateIncome: %v", err)
+		}
+		if err := repo.CreateExpense(&models.Expense{ID: uuid.New(), UserID: userID, Category: "Rent", Amount: 1500, SpentAt: month.AddDate(0, 0, 2)}); err != nil {
+			t.Fatalf("CreateExpense: %v", err)
+		}
+	}
+	fund, err := finance.InstantiateGoalTemplate(userID, builtIn["Emergency fund"], &request.InstantiateGoalTemplateRequest{}, now)
+	if err != nil {
+		t.Fatalf("InstantiateGoalTemplate: %v", err)
+	}
+	if fund.AvgMonthlyExpenses != 1500 || len(fund.Goals) != 1 || fund.Goals[0].TargetAmount != 9000 || fund.Goals[0].Category != "Emergency" {
+		t.Fatalf("expected a 9000 emergency fund, got %+v", fund)
+	}
+	if due := fund.Goals[0].TargetDate; due == nil || !due.Equal(today.AddDate(1, 0, 0)) {
+		t.Fatalf("expected the fund to be due in 12 months, got %v", due)
+	}
+
+	income := 5000.0
+	wedding, err := finance.InstantiateGoalTemplate(userID, builtIn["Wedding"], &request.InstantiateGoalTemplateRequest{Name: "Our wedding", AvgMonthlyIncome: &income}, now)
+	if err != nil {
+		t.Fatalf("InstantiateGoalTemplate: %v", err)
+	}
+	if len(wedding.Goals) != 6 || wedding.Goals[0].Name != "Our wedding" || wedding.Goals[0].TargetAmount != 20000 || !wedding.Goals[0].IsMainGoal {
+		t.Fatalf("expected a 20000 wedding with five sub-goals, got %+v", wedding.Goals)
+	}
+	if venue := wedding.Goals[1]; venue.Name != "Venue" || venue.TargetAmount != 8000 || venue.ParentGoalID == nil || *venue.ParentGoalID != wedding.Goals[0].ID {
+		t.Fatalf("expected the venue to take 40%% under the wedding, got %+v", venue)
+	}
+
+	// Users save their own goal trees and reuse them
+	saved, err := finance.CreateGoalTemplate(userID, &request.CreateGoalTemplateRequest{Name: "Wedding, our way", GoalID: &wedding.Goals[0].ID})
+	if err != nil {
+		t.Fatalf("CreateGoalTemplate: %v", err)
+	}
+	if saved.BuiltIn || saved.Goal.Target != "20000" || len(saved.Goal.Subgoals) != 5 || saved.Goal.Months != 18 {
+		t.Fatalf("expected the saved tree with fixed targets, got %+v", saved.Goal)
+	}
+	again, err := finance.InstantiateGoalTemplate(userID, saved.ID, &request.InstantiateGoalTemplateRequest{Name: "Vow renewal"}, now)
+	if err != nil {
+		t.Fatalf("InstantiateGoalTemplate saved: %v", err)
+	}
+	if len(again.Goals) != 6 || again.Goals[0].TargetAmount != 20000 {
+		t.Fatalf("expected the saved tree to be recreated, got %+v", again.Goals)
+	}
+
+	if _, err := finance.CreateGoalTemplate(userID, &request.CreateGoalTemplateRequest{Name: "Bad", Goal: &request.TemplateGoalRequest{Name: "Bad", Target: "salary * 2"}}); err == nil {
+		t.Fatal("expected an unknown variable to be rejected")
+	}
+	name := "Mine now"
+	if _, err := finance.UpdateGoalTemplate(userID, builtIn["Holiday"], &request.UpdateGoalTemplateRequest{Name: &name}); err != errors.ErrGoalTemplateBuiltIn {
+		t.Fatalf("expected built-in templates to be read-only, got %v", err)
+	}
+	if err := finance.DeleteGoalTemplate(userID, saved.ID); err != nil {
+		t.Fatalf("DeleteGoalTemplate: %v", err)
+	}
+
+	goals, _ := repo.ListGoalsWithProgress(userID)
+	if len(goals) != 13 {
+		t.Fatalf("expected only the successful instantiations to create goals, got %d", len(goals))
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"time"
+
+	"finance-management/internal/dto/response"
+	"finance-management/internal/repository"
+)
+
+// Goal pace statuses compare the recent contribution pace with the pace a goal's target date needs
+const (
+	GoalPaceCompleted   = "completed"
+	GoalPaceAhead       = "ahead"
+	GoalPaceOnTrack     = "on_track"
+	GoalPaceBehind      = "behind"
+	GoalPaceUnscheduled = "unscheduled"
+)
+
+const (
+	// DefaultPaceWindow is the number of months the contribution pace is averaged over
+	DefaultPaceWindow = 3
+	// MaxPaceWindow bounds the pace window a caller may ask for
+	MaxPaceWindow = 24
+
+	// paceTolerance is how far the pace may differ from the required contribution
+	// while the goal still counts as on track
+	paceTolerance = 0.1
+	// daysPerMonth converts between durations and fractional months
+	daysPerMonth = 365.25 / 12
+	// maxProjectionMonths stops completion dates from running off into the centuries
+	maxProjectionMonths = 1200
+)
+
+// monthsBetween returns the fractional number of months from a to b
+func monthsBetween(a, b time.Time) float64 {
+	return b.Sub(a).Hours() / 24 / daysPerMonth
+}
+
+// addMonths moves t by a fractional number of months and truncates it to a date
+func addMonths(t time.Time, months float64) time.Time {
+	d := t.Add(time.Duration(months * daysPerMonth * 24 * float64(time.Hour)))
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// applyGoalPace fills in the pace fields of resp. recent is the sum of the goal's
+// contributions since windowStart; goals created inside the window are averaged over
+// their lifetime, and at least one month, so a first contribution is not inflated.
+func applyGoalPace(resp *response.GoalWithProgressResponse, g repository.GoalWithProgress, recent float64, window int, windowStart, now time.Time) {
+	resp.PaceWindowMonths = window
+	resp.Remaining = max(g.Goal.TargetAmount-g.ContributedSum, 0)
+
+	months := float64(window)
+	if g.Goal.CreatedAt.After(windowStart) {
+		months = max(monthsBetween(g.Goal.CreatedAt, now), 1)
+	}
+	resp.Pace = roundCents(recent / months)
+
+	if g.Goal.TargetDate != nil {
+		monthsLeft := monthsBetween(now, *g.Goal.TargetDate)
+		required := roundCents(resp.Remaining / max(monthsLeft, 1))
+		resp.RequiredMonthly = &required
+	}
+
+	switch {
+	case resp.Remaining == 0:
+		resp.PaceStatus = GoalPaceCompleted
+		return
+	case resp.RequiredMonthly == nil:
+		resp.PaceStatus = GoalPaceUnscheduled
+	case resp.Pace < *resp.RequiredMonthly*(1-paceTolerance):
+		resp.PaceStatus = GoalPaceBehind
+	case resp.Pace > *resp.RequiredMonthly*(1+paceTolerance):
+		resp.PaceStatus = GoalPaceAhead
+	default:
+		resp.PaceStatus = GoalPaceOnTrack
+	}
+
+	if resp.Pace > 0 {
+		if months := resp.Remaining / resp.Pace; months <= maxProjectionMonths {
+			completion := addMonths(now, months)
+			resp.ProjectedCompletion = &completion
+		}
+	}
+}
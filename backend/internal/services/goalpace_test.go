@@ -0,0 +1,104 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestListGoalsReportsPaceAndStatus(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	goal := func(name string, target float64, due *time.Time) uuid.UUID {
+		g := &models.Goal{ID: uuid.New(), UserID: userID, Name: name, TargetAmount: target, TargetDate: due, CreatedAt: created}
+		if err := repo.CreateGoal(g); err != nil {
+			t.Fatalf("CreateGoal: %v", err)
+		}
+		return g.ID
+	}
+	contribute := func(goalID uuid.UUID, amount float64, at time.Time) {
+		c := &models.GoalContribution{ID: uuid.New(), UserID: userID, GoalID: goalID, Amount: amount, ContributedAt: at}
+		if err := repo.CreateGoalContribution(c); err != nil {
+			t.Fatalf("CreateGoalContribution: %v", err)
+		}
+	}
+
+	tripDue := time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC)
+	trip := goal("Trip", 1200, &tripDue)
+	contribute(trip, 300, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	for m := time.April; m <= time.June; m++ {
+		contribute(trip, 100, time.Date(2025, m, 1, 0, 0, 0, 0, time.UTC))
+	}
+	laptopDue := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)
+	laptop := goal("Laptop", 2000, &laptopDue)
+	contribute(laptop, 100, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	rainyDay := goal("Rainy day", 5000, nil)
+	contribute(rainyDay, 150, time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC))
+	// Contributions dated after today do not count towards the pace yet
+	contribute(rainyDay, 900, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	boots := goal("Boots", 100, &laptopDue)
+	contribute(boots, 100, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	bike := goal("Bike", 1000, nil)
+	contribute(bike, 100, time.Date(2025, 5, 15, 0, 0, 0, 0, time.UTC))
+
+	goals, err := finance.ListGoalsWithProgress(userID, 0, nil, now)
+	if err != nil {
+		t.Fatalf("ListGoalsWithProgress: %v", err)
+	}
+	byID := map[uuid.UUID]int{}
+	for i, g := range goals {
+		byID[g.Goal.ID] = i
+		if g.PaceWindowMonths != DefaultPaceWindow {
+			t.Fatalf("expected the default pace window, got %d", g.PaceWindowMonths)
+		}
+	}
+
+	g := goals[byID[trip]]
+	if g.Pace != 100 || g.Remaining != 600 || g.RequiredMonthly == nil || g.PaceStatus != GoalPaceOnTrack {
+		t.Fatalf("expected the trip to be on track at 100 a month, got %+v", g)
+	}
+	if g.ProjectedCompletion == nil || g.ProjectedCompletion.Before(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)) || g.ProjectedCompletion.After(tripDue) {
+		t.Fatalf("expected the trip to complete in December, got %v", g.ProjectedCompletion)
+	}
+
+	g = goals[byID[laptop]]
+	if g.Pace != 0 || g.PaceStatus != GoalPaceBehind || g.ProjectedCompletion != nil || *g.RequiredMonthly < 900 {
+		t.Fatalf("expected the stalled laptop goal to be behind, got %+v", g)
+	}
+
+	g = goals[byID[rainyDay]]
+	if g.Pace != 50 || g.PaceStatus != GoalPaceUnscheduled || g.RequiredMonthly != nil || g.ProjectedCompletion == nil {
+		t.Fatalf("expected an unscheduled goal paced at 50 a month, got %+v", g)
+	}
+
+	g = goals[byID[boots]]
+	if g.PaceStatus != GoalPaceCompleted || g.Remaining != 0 || g.ProjectedCompletion != nil {
+		t.Fatalf("expected the funded goal to be completed, got %+v", g)
+	}
+
+	// A one-month window only sees June's contribution to the trip, and starts at
+	// the beginning of the day so a contribution made on its first day counts
+	goals, err = finance.ListGoalsWithProgress(userID, 1, nil, now)
+	if err != nil {
+		t.Fatalf("ListGoalsWithProgress window 1: %v", err)
+	}
+	for _, g := range goals {
+		if g.Goal.ID == trip && (g.Pace != 100 || g.PaceWindowMonths != 1) {
+			t.Fatalf("expected a one-month pace of 100, got %+v", g)
+		}
+		if g.Goal.ID == bike && g.Pace != 100 {
+			t.Fatalf("expected the contribution on the window's first day to count, got %+v", g)
+		}
+	}
+	if _, err := finance.ListGoalsWithProgress(userID, MaxPaceWindow+1, nil, now); err == nil {
+		t.Fatal("expected a pace window over the maximum to be rejected")
+	}
+}
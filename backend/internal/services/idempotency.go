@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"log"
+	"net/http"
+	"time"
+
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IdempotentRequest identifies a request sent with an Idempotency-Key header
+type IdempotentRequest struct {
+	UserID uuid.UUID
+	Key    string
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// hash fingerprints the request so a key reused for a different request is detected
+func (r IdempotentRequest) hash() string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.Path + "\n"))
+	h.Write(r.Body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// IdempotencyService reserves Idempotency-Keys and stores responses so retried
+// requests are replayed instead of being applied twice
+type IdempotencyService struct {
+	repo      repository.IdempotencyRepositoryInterface
+	retention time.Duration
+}
+
+// NewIdempotencyService creates a new idempotency service
+func NewIdempotencyService(repo repository.IdempotencyRepositoryInterface, retention time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo:      repo,
+		retention: retention,
+	}
+}
+
+// Begin reserves the request's key. It returns nil when the request should be
+// handled, or the stored key when it is a retry whose response must be replayed.
+func (s *IdempotencyService) Begin(req IdempotentRequest) (*models.IdempotencyKey, error) {
+	now := time.Now().UTC()
+	hash := req.hash()
+
+	// A second attempt is needed when an expired key is cleared out of the way
+	for attempt := 0; attempt < 2; attempt++ {
+		err := s.repo.CreateIdempotencyKey(&models.IdempotencyKey{
+			UserID:      req.UserID,
+			Key:         req.Key,
+			Method:      req.Method,
+			Path:        req.Path,
+			RequestHash: hash,
+			CreatedAt:   now,
+		})
+		if err == nil {
+			return nil, nil
+		}
+		if !stderrors.Is(err, repository.ErrIdempotencyKeyExists) {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to reserve idempotency key")
+		}
+
+		existing, err := s.repo.GetIdempotencyKey(req.UserID, req.Key)
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to read idempotency key")
+		}
+		if existing.CreatedAt.Before(now.Add(-s.retention)) {
+			if err := s.repo.DeleteIdempotencyKey(req.UserID, req.Key); err != nil {
+				return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to expire idempotency key")
+			}
+			continue
+		}
+		if existing.RequestHash != hash {
+			return nil, errors.ErrIdempotencyKeyReused
+		}
+		if !existing.Completed() {
+			return nil, errors.ErrIdempotencyKeyInProgress
+		}
+		return existing, nil
+	}
+	return nil, errors.ErrIdempotencyKeyInProgress
+}
+
+// Complete stores the response for replay. Server errors release the key
+// instead, so the client can retry the request.
+func (s *IdempotencyService) Complete(userID uuid.UUID, key string, statusCode int, contentType string, body []byte) {
+	if statusCode >= http.StatusInternalServerError {
+		s.Release(userID, key)
+		return
+	}
+	if err := s.repo.CompleteIdempotencyKey(userID, key, statusCode, contentType, body); err != nil {
+		log.Printf("⚠️  Failed to store response for idempotency key %q: %v", key, err)
+	}
+}
+
+// Release forgets a reserved key whose request did not complete
+func (s *IdempotencyService) Release(userID uuid.UUID, key string) {
+	if err := s.repo.DeleteIdempotencyKey(userID, key); err != nil {
+		log.Printf("⚠️  Failed to release idempotency key %q: %v", key, err)
+	}
+}
+
+// Purge removes keys older than the retention window and returns how many were removed
+func (s *IdempotencyService) Purge(now time.Time) (int64, error) {
+	purged, err := s.repo.PurgeIdempotencyKeys(now.Add(-s.retention))
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to purge idempotency keys")
+	}
+	return purged, nil
+}
+
+// RunPurger runs Purge immediately and then every interval until ctx is cancelled
+func (s *IdempotencyService) RunPurger(ctx context.Context, interval time.Duration) {
+	runEvery(ctx, interval, func() {
+		if purged, err := s.Purge(time.Now().UTC()); err != nil {
+			log.Printf("⚠️  Idempotency key purge failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("🧹 Purged %d expired idempotency key(s)", purged)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestIdempotencyKeyInProgressAndExpiry(t *testing.T) {
+	repo := repository.NewInMemoryIdempotencyRepository()
+	svc := NewIdempotencyService(repo, time.Hour)
+	req := IdempotentRequest{UserID: uuid.New(), Key: "k1", Method: "POST", Path: "/api/finance/goals/contributions", Body: []byte(`{"amount":5}`)}
+
+	if replay, err := svc.Begin(req); err != nil || replay != nil {
+		t.Fatalf("first Begin: expected to proceed, got %v, %v", replay, err)
+	}
+	if _, err := svc.Begin(req); err != errors.ErrIdempotencyKeyInProgress {
+		t.Fatalf("Begin while in flight: expected ErrIdempotencyKeyInProgress, got %v", err)
+	}
+
+	// Server errors release the key so the retry is handled again
+	svc.Complete(req.UserID, req.Key, 503, "application/json", []byte(`{}`))
+	if replay, err := svc.Begin(req); err != nil || replay != nil {
+		t.Fatalf("Begin after a server error: expected to proceed, got %v, %v", replay, err)
+	}
+	svc.Complete(req.UserID, req.Key, 201, "application/json", []byte(`{"id":1}`))
+	replay, err := svc.Begin(req)
+	if err != nil || replay == nil || replay.StatusCode != 201 {
+		t.Fatalf("Begin after completion: expected a replay, got %v, %v", replay, err)
+	}
+
+	// Keys older than the retention window no longer apply
+	expired := &models.IdempotencyKey{UserID: req.UserID, Key: "old", Method: "POST", Path: req.Path, RequestHash: "stale", CreatedAt: time.Now().UTC().Add(-2 * time.Hour)}
+	if err := repo.CreateIdempotencyKey(expired); err != nil {
+		t.Fatalf("CreateIdempotencyKey: %v", err)
+	}
+	req.Key = "old"
+	if replay, err := svc.Begin(req); err != nil || replay != nil {
+		t.Fatalf("Begin on an expired key: expected to proceed, got %v, %v", replay, err)
+	}
+}
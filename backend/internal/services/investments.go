@@ -0,0 +1,806 @@
+package services
+
+import (
+	"encoding/csv"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// quantityEpsilon absorbs floating point error when quantities of a security are compared
+const quantityEpsilon = 1e-9
+
+// NetWorthInvestments is the net worth type of the market value of the user's holdings
+const NetWorthInvestments = "investment"
+
+// endOfTime is a day after every lot and sale, for replaying a holding's whole history
+var endOfTime = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// CSV price import columns, matched ignoring case; the first of each list present is used
+var (
+	priceSymbolColumns = []string{"symbol", "ticker"}
+	priceDateColumns   = []string{"date"}
+	priceCloseColumns  = []string{"close", "price"}
+)
+
+// CreateSecurity adds a security the user holds
+func (s *FinanceService) CreateSecurity(userID uuid.UUID, req *request.CreateSecurityRequest) (*response.SecurityResponse, error) {
+	now := time.Now().UTC()
+	security := &models.Security{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Symbol:     normalizeSymbol(req.Symbol),
+		Name:       strings.TrimSpace(req.Name),
+		AssetClass: req.AssetClass,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if security.Symbol == "" {
+		return nil, errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid security", "Symbol is required")
+	}
+	if security.Name == "" {
+		security.Name = security.Symbol
+	}
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateSecurity(security); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntitySecurity, security.ID, AuditCreate, createdChanges(security))
+		return nil
+	})
+	if err != nil {
+		if stderrors.Is(err, repository.ErrSecurityExists) {
+			return nil, errors.ErrSecurityExists
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create security")
+	}
+
+	resp := securityResponse(*security)
+	return &resp, nil
+}
+
+// ListSecurities returns the user's securities ordered by symbol
+func (s *FinanceService) ListSecurities(userID uuid.UUID) ([]response.SecurityResponse, error) {
+	securities, err := s.financeRepo.ListSecurities(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list securities")
+	}
+	responses := make([]response.SecurityResponse, len(securities))
+	for i, security := range securities {
+		responses[i] = securityResponse(security)
+	}
+	return responses, nil
+}
+
+// GetSecurity returns one of the user's securities
+func (s *FinanceService) GetSecurity(userID, securityID uuid.UUID) (*response.SecurityResponse, error) {
+	security, err := s.financeRepo.GetSecurity(securityID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrSecurityNotFound, "Failed to get security")
+	}
+	resp := securityResponse(*security)
+	return &resp, nil
+}
+
+// UpdateSecurity changes a security's symbol, name or asset class
+func (s *FinanceService) UpdateSecurity(userID, securityID uuid.UUID, req *request.UpdateSecurityRequest) (*response.SecurityResponse, error) {
+	before, err := s.financeRepo.GetSecurity(securityID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrSecurityNotFound, "Failed to update security")
+	}
+
+	updates := make(map[string]interface{})
+	if req.Symbol != nil {
+		symbol := normalizeSymbol(*req.Symbol)
+		if symbol == "" {
+			return nil, errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid security", "Symbol is required")
+		}
+		updates["symbol"] = symbol
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid security", "Name is required")
+		}
+		updates["name"] = name
+	}
+	if req.AssetClass != nil {
+		updates["asset_class"] = *req.AssetClass
+	}
+	if len(updates) == 0 {
+		return nil, errors.ErrInvalidInput
+	}
+
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateSecurity(securityID, userID, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntitySecurity, securityID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		if stderrors.Is(err, repository.ErrSecurityExists) {
+			return nil, errors.ErrSecurityExists
+		}
+		return nil, lookupError(err, errors.ErrSecurityNotFound, "Failed to update security")
+	}
+
+	return s.GetSecurity(userID, securityID)
+}
+
+// DeleteSecurity removes a security with its lots, sales and prices. Its dividends
+// are kept as ordinary incomes.
+func (s *FinanceService) DeleteSecurity(userID, securityID uuid.UUID) error {
+	before, err := s.financeRepo.GetSecurity(securityID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrSecurityNotFound, "Failed to delete security")
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteSecurity(securityID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntitySecurity, securityID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrSecurityNotFound, "Failed to delete security")
+	}
+	return nil
+}
+
+// ListLots returns the purchases of a security, oldest first
+func (s *FinanceService) ListLots(userID, securityID uuid.UUID) ([]response.LotResponse, error) {
+	if _, err := s.financeRepo.GetSecurity(securityID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrSecurityNotFound, "Failed to list investment lots")
+	}
+	lots, err := s.financeRepo.ListInvestmentLots(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list investment lots")
+	}
+	responses := []response.LotResponse{}
+	for _, lot := range lots {
+		if lot.SecurityID == securityID {
+			responses = append(responses, lotResponse(lot))
+		}
+	}
+	return responses, nil
+}
+
+// CreateLot records a purchase of a security
+func (s *FinanceService) CreateLot(userID, securityID uuid.UUID, req *request.CreateLotRequest) (*response.LotResponse, error) {
+	if _, err := s.financeRepo.GetSecurity(securityID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrSecurityNotFound, "Failed to record investment lot")
+	}
+	lot := &models.InvestmentLot{
+		ID:         uuid.New(),
+		UserID:     userID,
+		SecurityID: securityID,
+		BoughtOn:   startOfDay(req.BoughtOn),
+		Quantity:   req.Quantity,
+		Cost:       roundCents(req.Cost),
+		CreatedAt:  time.Now().UTC(),
+	}
+	err := s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateInvestmentLot(lot); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityInvestmentLot, lot.ID, AuditCreate, createdChanges(lot))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to record investment lot")
+	}
+
+	resp := lotResponse(*lot)
+	return &resp, nil
+}
+
+// DeleteLot removes a purchase of a security unless a later sale needs it
+func (s *FinanceService) DeleteLot(userID, securityID, lotID uuid.UUID) error {
+	lots, sales, err := s.lotsAndSales(userID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(lots, func(l models.InvestmentLot) bool { return l.ID == lotID && l.SecurityID == securityID })
+	if i < 0 {
+		return errors.ErrLotNotFound
+	}
+	lot := lots[i]
+	if _, err := replayPositions(slices.Delete(slices.Clone(lots), i, i+1), sales, endOfTime); err != nil {
+		return err
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteInvestmentLot(lotID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityInvestmentLot, lotID, AuditDelete, deletedChanges(lot))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrLotNotFound, "Failed to delete investment lot")
+	}
+	return nil
+}
+
+// ListSales returns the sales of a security, oldest first
+func (s *FinanceService) ListSales(userID, securityID uuid.UUID) ([]response.SaleResponse, error) {
+	if _, err := s.financeRepo.GetSecurity(securityID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrSecurityNotFound, "Failed to list investment sales")
+	}
+	sales, err := s.financeRepo.ListInvestmentSales(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list investment sales")
+	}
+	responses := []response.SaleResponse{}
+	for _, sale := range sales {
+		if sale.SecurityID == securityID {
+			responses = append(responses, saleResponse(sale))
+		}
+	}
+	return responses, nil
+}
+
+// CreateSale records a sale of part of a holding. The sale may not be of more than
+// was held on its day, nor leave a later sale selling more than is held.
+func (s *FinanceService) CreateSale(userID, securityID uuid.UUID, req *request.CreateSaleRequest) (*response.SaleResponse, error) {
+	if _, err := s.financeRepo.GetSecurity(securityID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrSecurityNotFound, "Failed to record investment sale")
+	}
+	sale := &models.InvestmentSale{
+		ID:         uuid.New(),
+		UserID:     userID,
+		SecurityID: securityID,
+		SoldOn:     startOfDay(req.SoldOn),
+		Quantity:   req.Quantity,
+		Proceeds:   roundCents(req.Proceeds),
+		CreatedAt:  time.Now().UTC(),
+	}
+	lots, sales, err := s.lotsAndSales(userID)
+	if err != nil {
+		return nil, err
+	}
+	// The new sale is the last made on its day
+	i := sort.Search(len(sales), func(i int) bool { return sales[i].SoldOn.After(sale.SoldOn) })
+	if _, err := replayPositions(lots, slices.Insert(sales, i, *sale), endOfTime); err != nil {
+		return nil, err
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateInvestmentSale(sale); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityInvestmentSale, sale.ID, AuditCreate, createdChanges(sale))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to record investment sale")
+	}
+
+	resp := saleResponse(*sale)
+	return &resp, nil
+}
+
+// DeleteSale removes a sale of a security
+func (s *FinanceService) DeleteSale(userID, securityID, saleID uuid.UUID) error {
+	_, sales, err := s.lotsAndSales(userID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(sales, func(sale models.InvestmentSale) bool { return sale.ID == saleID && sale.SecurityID == securityID })
+	if i < 0 {
+		return errors.ErrSaleNotFound
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteInvestmentSale(saleID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityInvestmentSale, saleID, AuditDelete, deletedChanges(sales[i]))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrSaleNotFound, "Failed to delete investment sale")
+	}
+	return nil
+}
+
+// RecordDividend records a dividend paid by a security as an income linked to it
+func (s *FinanceService) RecordDividend(userID, securityID uuid.UUID, req *request.CreateDividendRequest) (*response.IncomeResponse, error) {
+	security, err := s.financeRepo.GetSecurity(securityID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrSecurityNotFound, "Failed to record dividend")
+	}
+	source := strings.TrimSpace(req.Source)
+	if source == "" {
+		source = security.Symbol + " dividend"
+	}
+	return s.CreateIncome(userID, &request.CreateIncomeRequest{
+		Source:     source,
+		Amount:     req.Amount,
+		ReceivedAt: req.ReceivedAt,
+		Tags:       req.Tags,
+		SecurityID: &securityID,
+	})
+}
+
+// ListDividends returns the incomes a security paid, oldest first
+func (s *FinanceService) ListDividends(userID, securityID uuid.UUID) ([]response.IncomeResponse, error) {
+	if _, err := s.financeRepo.GetSecurity(securityID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrSecurityNotFound, "Failed to list dividends")
+	}
+	dividends, err := s.financeRepo.ListDividends(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list dividends")
+	}
+	responses := []response.IncomeResponse{}
+	for _, dividend := range dividends {
+		if *dividend.SecurityID == securityID {
+			responses = append(responses, incomeResponse(dividend))
+		}
+	}
+	return responses, nil
+}
+
+// ListSecurityPrices returns a security's imported closing prices, oldest first
+func (s *FinanceService) ListSecurityPrices(userID, securityID uuid.UUID) ([]response.SecurityPriceResponse, error) {
+	if _, err := s.financeRepo.GetSecurity(securityID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrSecurityNotFound, "Failed to list prices")
+	}
+	prices, err := s.financeRepo.ListSecurityPrices(securityID, userID, time.Time{}, endOfTime)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list prices")
+	}
+	responses := make([]response.SecurityPriceResponse, len(prices))
+	for i, price := range prices {
+		responses[i] = response.SecurityPriceResponse{PricedOn: price.PricedOn, Close: price.Close}
+	}
+	return responses, nil
+}
+
+// ImportSecurityPrices stores closing prices read from a CSV file. The header names
+// a date column (YYYY-MM-DD), a close or price column and, unless symbol names the
+// security every row prices, a symbol or ticker column. A price replaces any the
+// security already has that day. Rows that cannot be read are skipped and reported.
+func (s *FinanceService) ImportSecurityPrices(userID uuid.UUID, file io.Reader, symbol string) (*response.ImportPricesResponse, error) {
+	invalid := func(details string) error {
+		return errors.NewWithDetails(errors.ErrInvalidInput.Code, "Invalid price file", details)
+	}
+	securities, err := s.financeRepo.ListSecurities(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to import prices")
+	}
+	bySymbol := make(map[string]models.Security, len(securities))
+	for _, security := range securities {
+		bySymbol[security.Symbol] = security
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, invalid("The file is empty")
+	}
+	if err != nil {
+		return nil, invalid(err.Error())
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	dateCol, hasDate := csvColumn(columns, priceDateColumns)
+	closeCol, hasClose := csvColumn(columns, priceCloseColumns)
+	if !hasDate || !hasClose {
+		return nil, invalid("The header must name a date column and a close or price column")
+	}
+	symbolCol, hasSymbol := csvColumn(columns, priceSymbolColumns)
+	var fixed *models.Security
+	if symbol = normalizeSymbol(symbol); symbol != "" {
+		security, ok := bySymbol[symbol]
+		if !ok {
+			return nil, errors.ErrSecurityNotFound
+		}
+		fixed = &security
+	} else if !hasSymbol {
+		return nil, invalid("Name the security in a symbol or ticker column or with the symbol parameter")
+	}
+
+	resp := &response.ImportPricesResponse{Errors: []response.ImportPriceRowResponse{}}
+	skip := func(line int, format string, args ...interface{}) {
+		resp.Errors = append(resp.Errors, response.ImportPriceRowResponse{Line: line, Error: fmt.Sprintf(format, args...)})
+	}
+	var prices []models.SecurityPrice
+	// A security priced twice on a day keeps the file's last price
+	index := make(map[priceDay]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if stderrors.As(err, &parseErr) {
+				line = parseErr.Line
+			}
+			skip(line, "%v", err)
+			continue
+		}
+		field := func(col int) string {
+			if col < len(record) {
+				return strings.TrimSpace(record[col])
+			}
+			return ""
+		}
+
+		security := fixed
+		if security == nil {
+			rowSymbol := normalizeSymbol(field(symbolCol))
+			found, ok := bySymbol[rowSymbol]
+			if !ok {
+				skip(line, "Unknown symbol %q", rowSymbol)
+				continue
+			}
+			security = &found
+		}
+		day, err := time.Parse("2006-01-02", field(dateCol))
+		if err != nil {
+			skip(line, "Date %q is not in YYYY-MM-DD form", field(dateCol))
+			continue
+		}
+		closePrice, err := strconv.ParseFloat(field(closeCol), 64)
+		if err != nil || closePrice < 0 || math.IsNaN(closePrice) || math.IsInf(closePrice, 0) {
+			skip(line, "Price %q is not a number of zero or more", field(closeCol))
+			continue
+		}
+
+		price := models.SecurityPrice{SecurityID: security.ID, UserID: userID, PricedOn: day, Close: closePrice}
+		key := priceDay{securityID: security.ID, day: day}
+		if i, ok := index[key]; ok {
+			prices[i] = price
+			continue
+		}
+		index[key] = len(prices)
+		prices = append(prices, price)
+	}
+
+	if err := s.financeRepo.SaveSecurityPrices(prices); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to import prices")
+	}
+	resp.Imported = len(prices)
+	return resp, nil
+}
+
+// GetPortfolio values the user's holdings at the end of a day, today unless the request names one
+func (s *FinanceService) GetPortfolio(userID uuid.UUID, req *request.PortfolioRequest, now time.Time) (*response.PortfolioResponse, error) {
+	day := startOfDay(now)
+	if req.Date != nil {
+		day = startOfDay(*req.Date)
+	}
+	return s.portfolioOn(userID, day)
+}
+
+// investmentMonth sums up the month's dividends and realised gains and values the
+// holdings at its end
+func (s *FinanceService) investmentMonth(userID uuid.UUID, year, month int) (response.InvestmentMonthResponse, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	before, err := s.portfolioOn(userID, start.AddDate(0, 0, -1))
+	if err != nil {
+		return response.InvestmentMonthResponse{}, err
+	}
+	after, err := s.portfolioOn(userID, start.AddDate(0, 1, -1))
+	if err != nil {
+		return response.InvestmentMonthResponse{}, err
+	}
+	return response.InvestmentMonthResponse{
+		MarketValue:         after.MarketValue,
+		Dividends:           roundCents(after.Dividends - before.Dividends),
+		RealisedFIFO:        roundCents(after.FIFO.RealisedGain - before.FIFO.RealisedGain),
+		RealisedAverageCost: roundCents(after.AverageCost.RealisedGain - before.AverageCost.RealisedGain),
+	}, nil
+}
+
+// portfolioOn values each of the user's securities at the end of day from its lots,
+// sales, dividends and latest price up to the day
+func (s *FinanceService) portfolioOn(userID uuid.UUID, day time.Time) (*response.PortfolioResponse, error) {
+	resp := &response.PortfolioResponse{Date: day, Allocation: []response.AllocationResponse{}, Holdings: []response.HoldingResponse{}}
+	securities, err := s.financeRepo.ListSecurities(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list securities")
+	}
+	if len(securities) == 0 {
+		return resp, nil
+	}
+	lots, sales, err := s.lotsAndSales(userID)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := replayPositions(lots, sales, day)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.financeRepo.LatestSecurityPrices(userID, day)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to get prices")
+	}
+	incomes, err := s.financeRepo.ListDividends(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list dividends")
+	}
+	dividends := make(map[uuid.UUID]int64)
+	for _, income := range incomes {
+		if !income.ReceivedAt.After(day) {
+			dividends[*income.SecurityID] += toCents(income.Amount)
+		}
+	}
+
+	var marketValue, dividendTotal int64
+	var fifo, average gainsCents
+	allocation := make(map[string]int64)
+	for _, security := range securities {
+		p := positions[security.ID]
+		if p == nil {
+			p = &position{}
+		}
+		holding := response.HoldingResponse{
+			Security:  securityResponse(security),
+			Quantity:  math.Round(p.quantity*1e8) / 1e8,
+			Dividends: float64(dividends[security.ID]) / 100,
+		}
+		fifoGains := gainsCents{cost: toCents(p.fifoCost()), realised: toCents(p.realisedFIFO)}
+		averageGains := gainsCents{cost: toCents(p.averageCost), realised: toCents(p.realisedAverage)}
+		value := fifoGains.cost
+		if price, ok := prices[security.ID]; ok {
+			holding.Price = &price.Close
+			holding.PricedOn = &price.PricedOn
+			value = toCents(p.quantity * price.Close)
+			fifoGains.unrealised = value - fifoGains.cost
+			averageGains.unrealised = value - averageGains.cost
+		}
+		holding.MarketValue = float64(value) / 100
+		holding.FIFO = fifoGains.response()
+		holding.AverageCost = averageGains.response()
+		resp.Holdings = append(resp.Holdings, holding)
+
+		marketValue += value
+		dividendTotal += dividends[security.ID]
+		fifo.add(fifoGains)
+		average.add(averageGains)
+		if value > 0 {
+			allocation[security.AssetClass] += value
+		}
+	}
+
+	resp.MarketValue = float64(marketValue) / 100
+	resp.Dividends = float64(dividendTotal) / 100
+	resp.FIFO = fifo.response()
+	resp.AverageCost = average.response()
+	for class, value := range allocation {
+		resp.Allocation = append(resp.Allocation, response.AllocationResponse{
+			AssetClass:  class,
+			MarketValue: float64(value) / 100,
+			Share:       roundCents(float64(value) / float64(marketValue) * 100),
+		})
+	}
+	sort.Slice(resp.Allocation, func(i, j int) bool {
+		if resp.Allocation[i].MarketValue != resp.Allocation[j].MarketValue {
+			return resp.Allocation[i].MarketValue > resp.Allocation[j].MarketValue
+		}
+		return resp.Allocation[i].AssetClass < resp.Allocation[j].AssetClass
+	})
+	return resp, nil
+}
+
+// lotsAndSales loads the lots and sales of all the user's securities, oldest first
+func (s *FinanceService) lotsAndSales(userID uuid.UUID) ([]models.InvestmentLot, []models.InvestmentSale, error) {
+	lots, err := s.financeRepo.ListInvestmentLots(userID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list investment lots")
+	}
+	sales, err := s.financeRepo.ListInvestmentSales(userID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list investment sales")
+	}
+	return lots, sales, nil
+}
+
+// checkSecurity confirms a security an income is linked to belongs to the user
+func (s *FinanceService) checkSecurity(userID uuid.UUID, securityID *uuid.UUID) error {
+	if securityID == nil {
+		return nil
+	}
+	if _, err := s.financeRepo.GetSecurity(*securityID, userID); err != nil {
+		return lookupError(err, errors.ErrSecurityNotFound, "Failed to get security")
+	}
+	return nil
+}
+
+// openLot is what remains of a purchase after the sales matched against it first in, first out
+type openLot struct {
+	quantity float64
+	cost     float64
+}
+
+// position is a holding of one security replayed from its lots and sales
+type position struct {
+	quantity float64
+	// open holds the unsold part of each lot, oldest first
+	open []openLot
+	// averageCost is the cost of what is held when every unit costs the average
+	averageCost     float64
+	realisedFIFO    float64
+	realisedAverage float64
+}
+
+func (p *position) buy(lot models.InvestmentLot) {
+	p.quantity += lot.Quantity
+	p.open = append(p.open, openLot{quantity: lot.Quantity, cost: lot.Cost})
+	p.averageCost += lot.Cost
+}
+
+// sell matches a sale against the oldest open lots for FIFO and against the average
+// cost of the holding, returning ErrSaleExceedsHolding when more is sold than is held
+func (p *position) sell(sale models.InvestmentSale) error {
+	if sale.Quantity > p.quantity+quantityEpsilon {
+		return errors.ErrSaleExceedsHolding
+	}
+	averageCost := p.averageCost * math.Min(sale.Quantity/p.quantity, 1)
+	p.averageCost -= averageCost
+	p.realisedAverage += sale.Proceeds - averageCost
+
+	var fifoCost float64
+	remaining := sale.Quantity
+	for remaining > quantityEpsilon && len(p.open) > 0 {
+		lot := &p.open[0]
+		if lot.quantity <= remaining+quantityEpsilon {
+			fifoCost += lot.cost
+			remaining -= lot.quantity
+			p.open = p.open[1:]
+			continue
+		}
+		part := lot.cost * remaining / lot.quantity
+		lot.cost -= part
+		lot.quantity -= remaining
+		fifoCost += part
+		remaining = 0
+	}
+	p.realisedFIFO += sale.Proceeds - fifoCost
+
+	p.quantity -= sale.Quantity
+	if p.quantity < quantityEpsilon {
+		p.quantity, p.averageCost, p.open = 0, 0, nil
+	}
+	return nil
+}
+
+// fifoCost is the cost of the unsold parts of the lots
+func (p *position) fifoCost() float64 {
+	var cost float64
+	for _, lot := range p.open {
+		cost += lot.cost
+	}
+	return cost
+}
+
+// replayPositions works out each security's position at the end of day from lots and
+// sales sorted oldest first. Purchases count before sales made on the same day.
+func replayPositions(lots []models.InvestmentLot, sales []models.InvestmentSale, day time.Time) (map[uuid.UUID]*position, error) {
+	positions := make(map[uuid.UUID]*position)
+	get := func(securityID uuid.UUID) *position {
+		if positions[securityID] == nil {
+			positions[securityID] = &position{}
+		}
+		return positions[securityID]
+	}
+	i := 0
+	buyUntil := func(until time.Time) {
+		for ; i < len(lots) && !lots[i].BoughtOn.After(until); i++ {
+			get(lots[i].SecurityID).buy(lots[i])
+		}
+	}
+	for _, sale := range sales {
+		if sale.SoldOn.After(day) {
+			break
+		}
+		buyUntil(sale.SoldOn)
+		if err := get(sale.SecurityID).sell(sale); err != nil {
+			return nil, err
+		}
+	}
+	buyUntil(day)
+	return positions, nil
+}
+
+// gainsCents is a GainsResponse in cents, so totals add up exactly
+type gainsCents struct {
+	cost       int64
+	unrealised int64
+	realised   int64
+}
+
+func (g *gainsCents) add(other gainsCents) {
+	g.cost += other.cost
+	g.unrealised += other.unrealised
+	g.realised += other.realised
+}
+
+func (g gainsCents) response() response.GainsResponse {
+	return response.GainsResponse{
+		CostBasis:      float64(g.cost) / 100,
+		UnrealisedGain: float64(g.unrealised) / 100,
+		RealisedGain:   float64(g.realised) / 100,
+	}
+}
+
+// priceDay identifies one security's price on one day in a CSV import
+type priceDay struct {
+	securityID uuid.UUID
+	day        time.Time
+}
+
+// csvColumn returns the index of the first of names in the header's columns
+func csvColumn(columns map[string]int, names []string) (int, bool) {
+	for _, name := range names {
+		if i, ok := columns[name]; ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// normalizeSymbol trims a security symbol and upper-cases it
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// securityResponse converts a security to its API representation
+func securityResponse(security models.Security) response.SecurityResponse {
+	return response.SecurityResponse{
+		ID:         security.ID,
+		UserID:     security.UserID,
+		Symbol:     security.Symbol,
+		Name:       security.Name,
+		AssetClass: security.AssetClass,
+		CreatedAt:  security.CreatedAt,
+		UpdatedAt:  security.UpdatedAt,
+	}
+}
+
+// lotResponse converts a lot to its API representation
+func lotResponse(lot models.InvestmentLot) response.LotResponse {
+	return response.LotResponse{
+		ID:         lot.ID,
+		SecurityID: lot.SecurityID,
+		BoughtOn:   lot.BoughtOn,
+		Quantity:   lot.Quantity,
+		Cost:       lot.Cost,
+		CreatedAt:  lot.CreatedAt,
+	}
+}
+
+// saleResponse converts a sale to its API representation
+func saleResponse(sale models.InvestmentSale) response.SaleResponse {
+	return response.SaleResponse{
+		ID:         sale.ID,
+		SecurityID: sale.SecurityID,
+		SoldOn:     sale.SoldOn,
+		Quantity:   sale.Quantity,
+		Proceeds:   sale.Proceeds,
+		CreatedAt:  sale.CreatedAt,
+	}
+}
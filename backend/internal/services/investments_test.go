@@ -0,0 +1,183 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestNullSecurityIDUnlinksADividend(t *testing.T) {
+	finance := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, nil)
+	userID := uuid.New()
+
+	fund, err := finance.CreateSecurity(userID, &request.CreateSecurityRequest{Symbol: "VWRL", AssetClass: models.AssetClassFund})
+	if err != nil {
+		t.Fatalf("CreateSecurity: %v", err)
+	}
+	dividend, err := finance.RecordDividend(userID, fund.ID, &request.CreateDividendRequest{Amount: 25, ReceivedAt: time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("RecordDividend: %v", err)
+	}
+
+	var unlink request.UpdateIncomeRequest
+	if err := json.Unmarshal([]byte(`{"security_id": null}`), &unlink); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	income, err := finance.UpdateIncome(userID, dividend.ID, 0, &unlink)
+	if err != nil {
+		t.Fatalf("UpdateIncome: %v", err)
+	}
+	if income.SecurityID != nil {
+		t.Fatalf("expected a null security_id to unlink the dividend, got %+v", income)
+	}
+}
+
+func TestInvestmentGains(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+	now := time.Date(2024, 3, 29, 9, 0, 0, 0, time.UTC)
+
+	fund, err := finance.CreateSecurity(userID, &request.CreateSecurityRequest{Symbol: " vwrl ", AssetClass: models.AssetClassFund})
+	if err != nil {
+		t.Fatalf("CreateSecurity: %v", err)
+	}
+	if fund.Symbol != "VWRL" || fund.Name != "VWRL" {
+		t.Fatalf("expected the symbol upper-cased and used as the name, got %+v", fund)
+	}
+	if _, err := finance.CreateSecurity(userID, &request.CreateSecurityRequest{Symbol: "VWRL", AssetClass: models.AssetClassStock}); err != errors.ErrSecurityExists {
+		t.Fatalf("expected a duplicate symbol to be rejected, got %v", err)
+	}
+	bond, err := finance.CreateSecurity(userID, &request.CreateSecurityRequest{Symbol: "AGGG", Name: "Global bonds", AssetClass: models.AssetClassBond})
+	if err != nil {
+		t.Fatalf("CreateSecurity: %v", err)
+	}
+
+	january, err := finance.CreateLot(userID, fund.ID, &request.CreateLotRequest{Quantity: 10, Cost: 1000, BoughtOn: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("CreateLot: %v", err)
+	}
+	for _, lot := range []struct {
+		securityID uuid.UUID
+		req        request.CreateLotRequest
+	}{
+		{fund.ID, request.CreateLotRequest{Quantity: 10, Cost: 1200, BoughtOn: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)}},
+		{bond.ID, request.CreateLotRequest{Quantity: 20, Cost: 2000, BoughtOn: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}},
+	} {
+		if _, err := finance.CreateLot(userID, lot.securityID, &lot.req); err != nil {
+			t.Fatalf("CreateLot: %v", err)
+		}
+	}
+
+	// 15 units sold: FIFO takes all of January's lot and half of February's, average
+	// cost 15 units at 110
+	if _, err := finance.CreateSale(userID, fund.ID, &request.CreateSaleRequest{Quantity: 15, Proceeds: 1950, SoldOn: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatalf("CreateSale: %v", err)
+	}
+	for _, sale := range []request.CreateSaleRequest{
+		{Quantity: 10, Proceeds: 1300, SoldOn: time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		// January's lot covers this, but it would leave March's sale short
+		{Quantity: 6, Proceeds: 600, SoldOn: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+	} {
+		if _, err := finance.CreateSale(userID, fund.ID, &sale); err != errors.ErrSaleExceedsHolding {
+			t.Fatalf("expected a sale of %+v to exceed the holding, got %v", sale, err)
+		}
+	}
+	if err := finance.DeleteLot(userID, fund.ID, january.ID); err != errors.ErrSaleExceedsHolding {
+		t.Fatalf("expected a lot a sale needs to be kept, got %v", err)
+	}
+	if err := finance.DeleteLot(userID, bond.ID, january.ID); err != errors.ErrLotNotFound {
+		t.Fatalf("expected another security's lot to be not found, got %v", err)
+	}
+
+	csv := "Date,Symbol,Close\n" +
+		"2024-03-01,vwrl,125\n" +
+		"2024-03-28,VWRL,130\n" +
+		"2024-03-28,AGGG,n/a\n" +
+		"2024-03-28,XXXX,1\n" +
+		"28/03/2024,VWRL,130\n"
+	imported, err := finance.ImportSecurityPrices(userID, strings.NewReader(csv), "")
+	if err != nil {
+		t.Fatalf("ImportSecurityPrices: %v", err)
+	}
+	if imported.Imported != 2 || len(imported.Errors) != 3 || imported.Errors[0].Line != 4 || imported.Errors[2].Line != 6 {
+		t.Fatalf("expected 2 prices imported and 3 rows skipped, got %+v", imported)
+	}
+	imported, err = finance.ImportSecurityPrices(userID, strings.NewReader("Date,Open,Close\n2024-03-28,99,101\n"), "aggg")
+	if err != nil || imported.Imported != 1 {
+		t.Fatalf("expected the symbol parameter to name the security, got %+v, %v", imported, err)
+	}
+	if _, err := finance.ImportSecurityPrices(userID, strings.NewReader("Date,Close\n2024-03-28,101\n"), ""); err == nil {
+		t.Fatal("expected a file without symbols to need the symbol parameter")
+	}
+
+	dividend, err := finance.RecordDividend(userID, fund.ID, &request.CreateDividendRequest{Amount: 25, ReceivedAt: time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("RecordDividend: %v", err)
+	}
+	if dividend.SecurityID == nil || *dividend.SecurityID != fund.ID || dividend.Source != "VWRL dividend" {
+		t.Fatalf("expected a linked income with the default source, got %+v", dividend)
+	}
+
+	portfolio, err := finance.GetPortfolio(userID, &request.PortfolioRequest{}, now)
+	if err != nil {
+		t.Fatalf("GetPortfolio: %v", err)
+	}
+	if portfolio.MarketValue != 2670 || portfolio.Dividends != 25 {
+		t.Fatalf("expected 5 units at 130 and 20 at 101, got %+v", portfolio)
+	}
+	if portfolio.FIFO.CostBasis != 2600 || portfolio.FIFO.UnrealisedGain != 70 || portfolio.FIFO.RealisedGain != 350 {
+		t.Fatalf("unexpected FIFO gains %+v", portfolio.FIFO)
+	}
+	if portfolio.AverageCost.CostBasis != 2550 || portfolio.AverageCost.UnrealisedGain != 120 || portfolio.AverageCost.RealisedGain != 300 {
+		t.Fatalf("unexpected average cost gains %+v", portfolio.AverageCost)
+	}
+	if len(portfolio.Allocation) != 2 || portfolio.Allocation[0].AssetClass != models.AssetClassBond || portfolio.Allocation[0].Share != 75.66 || portfolio.Allocation[1].Share != 24.34 {
+		t.Fatalf("unexpected allocation %+v", portfolio.Allocation)
+	}
+
+	// Before any price was imported the holdings are valued at cost
+	february := time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)
+	portfolio, err = finance.GetPortfolio(userID, &request.PortfolioRequest{Date: &february}, now)
+	if err != nil {
+		t.Fatalf("GetPortfolio: %v", err)
+	}
+	if portfolio.MarketValue != 4200 || portfolio.FIFO.UnrealisedGain != 0 || portfolio.Holdings[1].Price != nil || portfolio.Holdings[1].Quantity != 20 {
+		t.Fatalf("expected unpriced holdings at cost, got %+v", portfolio)
+	}
+
+	summary, err := finance.GetMonthlySummary(userID, 2024, 3)
+	if err != nil {
+		t.Fatalf("GetMonthlySummary: %v", err)
+	}
+	if summary.TotalIncome != 25 || summary.Investments.MarketValue != 2670 || summary.Investments.Dividends != 25 || summary.Investments.RealisedFIFO != 350 || summary.Investments.RealisedAverageCost != 300 {
+		t.Fatalf("unexpected investments in the monthly summary %+v, income %v", summary.Investments, summary.TotalIncome)
+	}
+	worth, err := finance.GetNetWorth(userID, now)
+	if err != nil {
+		t.Fatalf("GetNetWorth: %v", err)
+	}
+	if worth.Assets != 2695 || worth.Breakdown[len(worth.Breakdown)-1].Type != NetWorthInvestments {
+		t.Fatalf("expected the holdings in net worth, got %+v", worth)
+	}
+
+	// Deleting the security keeps its dividend as an ordinary income
+	if err := finance.DeleteSecurity(userID, fund.ID); err != nil {
+		t.Fatalf("DeleteSecurity: %v", err)
+	}
+	income, err := finance.GetIncome(userID, dividend.ID)
+	if err != nil || income.SecurityID != nil {
+		t.Fatalf("expected the dividend to be kept without its security, got %+v, %v", income, err)
+	}
+	if _, err := finance.ListLots(userID, fund.ID); err != errors.ErrSecurityNotFound {
+		t.Fatalf("expected the deleted security to be not found, got %v", err)
+	}
+}
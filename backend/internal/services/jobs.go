@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// runEvery runs job immediately and then every interval until ctx is cancelled.
+// A run in progress is finished before it returns.
+func runEvery(ctx context.Context, interval time.Duration, job func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunEveryStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runEvery(ctx, time.Hour, func() {
+			runs++
+			cancel()
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected runEvery to return once cancelled")
+	}
+	if runs != 1 {
+		t.Fatalf("expected the job to run once straight away, got %d runs", runs)
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	stderrors "errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// goalStatusTransitions lists the statuses a goal may move to from each status
+var goalStatusTransitions = map[string][]string{
+	models.GoalActive:    {models.GoalPaused, models.GoalCompleted, models.GoalArchived, models.GoalAbandoned},
+	models.GoalPaused:    {models.GoalActive, models.GoalCompleted, models.GoalArchived, models.GoalAbandoned},
+	models.GoalCompleted: {models.GoalActive, models.GoalArchived},
+	models.GoalAbandoned: {models.GoalActive, models.GoalArchived},
+	models.GoalArchived:  {models.GoalActive},
+}
+
+// DefaultGoalStatuses are the statuses goal listings show unless asked for others;
+// paused and archived goals stay out of the way on dashboards
+var DefaultGoalStatuses = []string{models.GoalActive, models.GoalCompleted, models.GoalAbandoned}
+
+// ChangeGoalStatus moves a goal to another lifecycle status and records the change in
+// its history, in one transaction. A non-zero version must match the stored one.
+func (s *FinanceService) ChangeGoalStatus(userID, goalID uuid.UUID, version int, req *request.ChangeGoalStatusRequest) (*response.GoalResponse, error) {
+	before, err := s.financeRepo.GetGoal(goalID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrGoalNotFound, "Failed to change goal status")
+	}
+	if version > 0 && before.Version != version {
+		return nil, errors.NewPreconditionFailed(goalResponse(*before))
+	}
+	if !slices.Contains(goalStatusTransitions[before.Status], req.Status) {
+		return nil, errors.NewWithDetails(
+			errors.ErrGoalStatusTransition.Code,
+			errors.ErrGoalStatusTransition.Message,
+			fmt.Sprintf("A %s goal cannot become %s", before.Status, req.Status),
+		)
+	}
+
+	now := time.Now().UTC()
+	updates := map[string]interface{}{"status": req.Status, "status_changed_at": now}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateGoal(goalID, userID, version, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityGoal, goalID, AuditUpdate, updatedChanges(before, updates))
+		return tx.financeRepo.CreateGoalStatusChange(&models.GoalStatusChange{
+			ID:         uuid.New(),
+			UserID:     userID,
+			GoalID:     goalID,
+			FromStatus: before.Status,
+			ToStatus:   req.Status,
+			Note:       strings.TrimSpace(req.Note),
+			ChangedAt:  now,
+		})
+	})
+	if err != nil {
+		if stderrors.Is(err, repository.ErrVersionConflict) {
+			return nil, s.goalConflict(userID, goalID)
+		}
+		return nil, lookupError(err, errors.ErrGoalNotFound, "Failed to change goal status")
+	}
+
+	return s.GetGoal(userID, goalID)
+}
+
+// ListGoalStatusHistory returns a goal's status changes oldest first
+func (s *FinanceService) ListGoalStatusHistory(userID, goalID uuid.UUID) ([]response.GoalStatusChangeResponse, error) {
+	if _, err := s.financeRepo.GetGoal(goalID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrGoalNotFound, "Failed to get goal status history")
+	}
+	changes, err := s.financeRepo.ListGoalStatusChanges(goalID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to get goal status history")
+	}
+	responses := make([]response.GoalStatusChangeResponse, len(changes))
+	for i, change := range changes {
+		responses[i] = response.GoalStatusChangeResponse{
+			ID:         change.ID,
+			GoalID:     change.GoalID,
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			Note:       change.Note,
+			ChangedAt:  change.ChangedAt,
+		}
+	}
+	return responses, nil
+}
+
+// WithdrawFromGoal takes money back out of a goal, whatever its status. The withdrawal
+// is stored as a contribution with a negative amount so it reduces the goal's progress
+// and the month's savings.
+func (s *FinanceService) WithdrawFromGoal(userID, goalID uuid.UUID, req *request.CreateGoalWithdrawalRequest) (*response.GoalContributionResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.NewWithDetails(errors.ErrMissingField.Code, "Withdrawal reason is required", "Say why the money is taken out of the goal")
+	}
+	var contribution *models.GoalContribution
+	// The goal stays locked from reading its balance until the withdrawal is stored, so
+	// concurrent withdrawals cannot together take out more than it holds
+	err := s.transact(func(tx *FinanceService) error {
+		if _, err := tx.financeRepo.LockGoal(goalID, userID); err != nil {
+			return lookupError(err, errors.ErrGoalNotFound, "Failed to withdraw from goal")
+		}
+		balance, err := tx.goalBalance(userID, goalID)
+		if err != nil {
+			return err
+		}
+		if roundCents(req.Amount) > roundCents(balance) {
+			return errors.NewWithDetails(
+				errors.ErrWithdrawalExceedsBalance.Code,
+				errors.ErrWithdrawalExceedsBalance.Message,
+				fmt.Sprintf("The goal holds %.2f", max(balance, 0)),
+			)
+		}
+
+		contribution = &models.GoalContribution{
+			ID:            uuid.New(),
+			UserID:        userID,
+			GoalID:        goalID,
+			Amount:        -roundCents(req.Amount),
+			ContributedAt: req.WithdrawnAt,
+			Reason:        &reason,
+			CreatedAt:     time.Now().UTC(),
+		}
+		if err := tx.financeRepo.CreateGoalContribution(contribution); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to withdraw from goal")
+		}
+		tx.audit.record(userID, EntityGoalContribution, contribution.ID, AuditCreate, createdChanges(contribution))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := goalContributionResponse(*contribution)
+	return &resp, nil
+}
+
+// goalBalance returns what a goal holds: its contributions less its withdrawals
+func (s *FinanceService) goalBalance(userID, goalID uuid.UUID) (float64, error) {
+	goals, err := s.financeRepo.ListGoalsWithProgress(userID)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load goal balance")
+	}
+	for _, g := range goals {
+		if g.Goal.ID == goalID {
+			return g.ContributedSum, nil
+		}
+	}
+	return 0, nil
+}
+
+// checkGoalAcceptsContributions rejects contributions to goals that were archived or abandoned
+func (s *FinanceService) checkGoalAcceptsContributions(userID, goalID uuid.UUID) error {
+	goal, err := s.financeRepo.GetGoal(goalID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrGoalNotFound, "Failed to get goal")
+	}
+	if goal.Status == models.GoalArchived || goal.Status == models.GoalAbandoned {
+		return errors.ErrGoalNotActive
+	}
+	return nil
+}
+
+// activeGoals keeps the goals that are active and whose parents, if any, are active too,
+// so pausing or archiving a goal takes its sub-goals out of funding and allocations
+func activeGoals(goals []repository.GoalWithProgress) []repository.GoalWithProgress {
+	byID := make(map[uuid.UUID]models.Goal, len(goals))
+	for _, g := range goals {
+		byID[g.Goal.ID] = g.Goal
+	}
+	var active []repository.GoalWithProgress
+	for _, g := range goals {
+		keep := true
+		// The depth bound guards against a cycle in corrupt data
+		for goal, depth := g.Goal, 0; keep && depth <= len(goals); depth++ {
+			keep = goal.Status == models.GoalActive
+			if goal.ParentGoalID == nil {
+				break
+			}
+			parent, ok := byID[*goal.ParentGoalID]
+			if !ok {
+				break
+			}
+			goal = parent
+		}
+		if keep {
+			active = append(active, g)
+		}
+	}
+	return active
+}
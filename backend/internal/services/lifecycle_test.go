@@ -0,0 +1,159 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestGoalWithdrawals(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+
+	goal, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Emergency fund", TargetAmount: 1000})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	if _, err := finance.CreateGoalContribution(userID, &request.CreateGoalContributionRequest{GoalID: goal.ID, Amount: 500, ContributedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateGoalContribution: %v", err)
+	}
+
+	withdrawal, err := finance.WithdrawFromGoal(userID, goal.ID, &request.CreateGoalWithdrawalRequest{Amount: 200, Reason: " Car repair ", WithdrawnAt: time.Now()})
+	if err != nil {
+		t.Fatalf("WithdrawFromGoal: %v", err)
+	}
+	if withdrawal.Amount != -200 || withdrawal.Reason == nil || *withdrawal.Reason != "Car repair" {
+		t.Fatalf("expected a negative contribution with the reason, got %+v", withdrawal)
+	}
+	_, err = finance.WithdrawFromGoal(userID, goal.ID, &request.CreateGoalWithdrawalRequest{Amount: 300.01, Reason: "Rent", WithdrawnAt: time.Now()})
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Message != errors.ErrWithdrawalExceedsBalance.Message {
+		t.Fatalf("expected withdrawing more than the balance to fail, got %v", err)
+	}
+	if _, err := finance.WithdrawFromGoal(userID, goal.ID, &request.CreateGoalWithdrawalRequest{Amount: 10, Reason: "  ", WithdrawnAt: time.Now()}); err == nil {
+		t.Fatal("expected a withdrawal without a reason to fail")
+	}
+
+	// Withdrawals are edited with positive amounts
+	amount := 150.0
+	edited, err := finance.UpdateGoalContribution(userID, withdrawal.ID, &request.UpdateGoalContributionRequest{Amount: &amount})
+	if err != nil {
+		t.Fatalf("UpdateGoalContribution: %v", err)
+	}
+	if edited.Amount != -150 {
+		t.Fatalf("expected the withdrawal to stay negative, got %v", edited.Amount)
+	}
+	goals, err := finance.ListGoalsWithProgress(userID, 0, nil, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("ListGoalsWithProgress: %v", err)
+	}
+	if len(goals) != 1 || goals[0].ContributedSum != 350 {
+		t.Fatalf("expected the withdrawal to reduce progress to 350, got %+v", goals)
+	}
+
+	tooMuch := 500.01
+	_, err = finance.UpdateGoalContribution(userID, withdrawal.ID, &request.UpdateGoalContributionRequest{Amount: &tooMuch})
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Message != errors.ErrWithdrawalExceedsBalance.Message || appErr.Details != "The goal holds 500.00" {
+		t.Fatalf("expected raising a withdrawal past the balance to fail, got %v", err)
+	}
+	empty, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Holiday", TargetAmount: 800})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	_, err = finance.UpdateGoalContribution(userID, withdrawal.ID, &request.UpdateGoalContributionRequest{GoalID: &empty.ID})
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Message != errors.ErrWithdrawalExceedsBalance.Message {
+		t.Fatalf("expected moving a withdrawal to a goal without the money to fail, got %v", err)
+	}
+}
+
+func TestGoalLifecycle(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+
+	house, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "House", TargetAmount: 10000, IsMainGoal: true})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	deposit, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Deposit", TargetAmount: 8000, ParentGoalID: &house.ID})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	holiday, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Holiday", TargetAmount: 1000})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	if house.Status != models.GoalActive {
+		t.Fatalf("expected new goals to be active, got %q", house.Status)
+	}
+
+	paused, err := finance.ChangeGoalStatus(userID, house.ID, house.Version, &request.ChangeGoalStatusRequest{Status: models.GoalPaused, Note: "Renting for now"})
+	if err != nil {
+		t.Fatalf("ChangeGoalStatus: %v", err)
+	}
+	if paused.Status != models.GoalPaused || paused.StatusChangedAt == nil || paused.Version != house.Version+1 {
+		t.Fatalf("expected the house to be paused, got %+v", paused)
+	}
+	_, err = finance.ChangeGoalStatus(userID, house.ID, house.Version, &request.ChangeGoalStatusRequest{Status: models.GoalActive})
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected a stale version to be rejected, got %v", err)
+	}
+
+	// Paused goals are hidden from listings unless asked for, and from allocations with their sub-goals
+	listed, err := finance.ListGoalsWithProgress(userID, 0, nil, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("ListGoalsWithProgress: %v", err)
+	}
+	if len(listed) != 2 {
+		t.Fatalf("expected only the paused house to be hidden, got %d goals", len(listed))
+	}
+	listed, _ = finance.ListGoalsWithProgress(userID, 0, []string{models.GoalPaused}, time.Now().UTC())
+	if len(listed) != 1 || listed[0].Goal.ID != house.ID {
+		t.Fatalf("expected the paused house when asked for, got %+v", listed)
+	}
+	if _, err := finance.ListGoalsWithProgress(userID, 0, []string{"sleeping"}, time.Now().UTC()); err == nil {
+		t.Fatal("expected an unknown status to be rejected")
+	}
+	if err := repo.CreateIncome(&models.Income{ID: uuid.New(), UserID: userID, Source: "Salary", Amount: 5000, ReceivedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+	plan, err := finance.PreviewSurplusAllocation(userID, &request.AllocateSurplusRequest{}, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("PreviewSurplusAllocation: %v", err)
+	}
+	for _, a := range plan.Allocations {
+		if a.GoalID == house.ID || a.GoalID == deposit.ID {
+			t.Fatalf("expected the paused house and its deposit to be left out, got %+v", plan.Allocations)
+		}
+	}
+
+	// Archived goals take withdrawals but no contributions and can only be reactivated
+	if _, err := finance.ChangeGoalStatus(userID, holiday.ID, 0, &request.ChangeGoalStatusRequest{Status: models.GoalArchived}); err != nil {
+		t.Fatalf("ChangeGoalStatus archive: %v", err)
+	}
+	if _, err := finance.CreateGoalContribution(userID, &request.CreateGoalContributionRequest{GoalID: holiday.ID, Amount: 50, ContributedAt: time.Now()}); err != errors.ErrGoalNotActive {
+		t.Fatalf("expected contributions to an archived goal to be rejected, got %v", err)
+	}
+	_, err = finance.ChangeGoalStatus(userID, holiday.ID, 0, &request.ChangeGoalStatusRequest{Status: models.GoalPaused})
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Message != errors.ErrGoalStatusTransition.Message {
+		t.Fatalf("expected archived goals not to be paused, got %v", err)
+	}
+	if _, err := finance.ChangeGoalStatus(userID, house.ID, 0, &request.ChangeGoalStatusRequest{Status: models.GoalActive}); err != nil {
+		t.Fatalf("ChangeGoalStatus resume: %v", err)
+	}
+
+	history, err := finance.ListGoalStatusHistory(userID, house.ID)
+	if err != nil {
+		t.Fatalf("ListGoalStatusHistory: %v", err)
+	}
+	if len(history) != 2 || history[0].ToStatus != models.GoalPaused || history[0].Note != "Renting for now" || history[1].FromStatus != models.GoalPaused || history[1].ToStatus != models.GoalActive {
+		t.Fatalf("expected the pause and resume in order, got %+v", history)
+	}
+}
@@ -0,0 +1,535 @@
+package services
+
+import (
+	stderrors "errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+	"finance-management/internal/validation"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// descriptionNoise lists words that card processors add to descriptions and that say nothing about the merchant
+var descriptionNoise = map[string]bool{
+	"www": true, "com": true, "net": true, "org": true, "co": true,
+	"inc": true, "llc": true, "ltd": true,
+	"pos": true, "purchase": true, "payment": true, "debit": true, "card": true,
+}
+
+// normalizeDescription reduces a raw expense description to the lower-case words that
+// identify its merchant, dropping punctuation, reference numbers and processor noise,
+// so "AMZN Mktp US*2K3" becomes "amzn mktp us" and "Amazon.com" becomes "amazon"
+func normalizeDescription(description string) string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, word := range words {
+		if descriptionNoise[word] || strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			continue
+		}
+		kept = append(kept, word)
+	}
+	return strings.Join(kept, " ")
+}
+
+// merchantMatcher resolves descriptions to merchants using the user's patterns
+type merchantMatcher []models.MerchantPattern
+
+// newMerchantMatcher orders patterns so the most specific one wins: longer patterns
+// first, and for equal lengths exact before prefix before contains
+func newMerchantMatcher(patterns []models.MerchantPattern) merchantMatcher {
+	rank := map[string]int{models.MatchExact: 0, models.MatchPrefix: 1, models.MatchContains: 2}
+	m := append(merchantMatcher(nil), patterns...)
+	sort.SliceStable(m, func(i, j int) bool {
+		if len(m[i].Pattern) != len(m[j].Pattern) {
+			return len(m[i].Pattern) > len(m[j].Pattern)
+		}
+		return rank[m[i].MatchType] < rank[m[j].MatchType]
+	})
+	return m
+}
+
+// match returns the merchant whose pattern best matches description, or nil
+func (m merchantMatcher) match(description string) *uuid.UUID {
+	normalized := normalizeDescription(description)
+	if normalized == "" {
+		return nil
+	}
+	for _, p := range m {
+		var ok bool
+		switch p.MatchType {
+		case models.MatchExact:
+			ok = normalized == p.Pattern
+		case models.MatchPrefix:
+			ok = normalized == p.Pattern || strings.HasPrefix(normalized, p.Pattern+" ")
+		case models.MatchContains:
+			// Match whole words only so "target" does not match "targeted"
+			ok = strings.Contains(" "+normalized+" ", " "+p.Pattern+" ")
+		}
+		if ok {
+			id := p.MerchantID
+			return &id
+		}
+	}
+	return nil
+}
+
+// resolveMerchant finds the merchant for a new expense description
+func (s *FinanceService) resolveMerchant(userID uuid.UUID, description string) (*uuid.UUID, error) {
+	patterns, err := s.financeRepo.ListMerchantPatterns(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load merchant patterns")
+	}
+	return newMerchantMatcher(patterns).match(description), nil
+}
+
+// checkMerchant verifies that an expense's merchant belongs to the user
+func (s *FinanceService) checkMerchant(userID uuid.UUID, merchantID *uuid.UUID) error {
+	if merchantID == nil {
+		return nil
+	}
+	if _, err := s.financeRepo.GetMerchant(*merchantID, userID); err != nil {
+		return lookupError(err, errors.ErrMerchantNotFound, "Failed to get merchant")
+	}
+	return nil
+}
+
+// CreateMerchant creates a merchant together with its patterns. The normalised name is
+// always matched as well, unless another merchant already claims it.
+func (s *FinanceService) CreateMerchant(userID uuid.UUID, req *request.CreateMerchantRequest) (*response.MerchantResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := validation.ValidateMerchantName(name); err != nil {
+		return nil, err
+	}
+
+	merchant := &models.Merchant{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	var patterns []models.MerchantPattern
+	for i := range req.Patterns {
+		pattern, err := newMerchantPattern(userID, merchant.ID, &req.Patterns[i])
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, *pattern)
+	}
+
+	var created []models.MerchantPattern
+	err := s.audit.transact(s.financeRepo, func(repo repository.FinanceRepositoryInterface, audit auditor) error {
+		if err := repo.CreateMerchant(merchant); err != nil {
+			return err
+		}
+		audit.record(userID, EntityMerchant, merchant.ID, AuditCreate, createdChanges(merchant))
+
+		for i := range patterns {
+			if err := repo.CreateMerchantPattern(&patterns[i]); err != nil {
+				return err
+			}
+			created = append(created, patterns[i])
+		}
+
+		if normalized := normalizeDescription(name); normalized != "" && !hasPattern(patterns, normalized, models.MatchContains) {
+			pattern := models.MerchantPattern{
+				ID:         uuid.New(),
+				UserID:     userID,
+				MerchantID: merchant.ID,
+				Pattern:    normalized,
+				MatchType:  models.MatchContains,
+				CreatedAt:  time.Now().UTC(),
+			}
+			switch err := repo.CreateMerchantPattern(&pattern); {
+			case err == nil:
+				created = append(created, pattern)
+			case !stderrors.Is(err, repository.ErrMerchantPatternExists):
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, merchantError(err, "Failed to create merchant")
+	}
+
+	resp := merchantResponse(*merchant, created)
+	return &resp, nil
+}
+
+// hasPattern reports whether patterns already contain the given pattern and match type
+func hasPattern(patterns []models.MerchantPattern, pattern, matchType string) bool {
+	for _, p := range patterns {
+		if p.Pattern == pattern && p.MatchType == matchType {
+			return true
+		}
+	}
+	return false
+}
+
+// newMerchantPattern normalises and validates a pattern request
+func newMerchantPattern(userID, merchantID uuid.UUID, req *request.MerchantPatternRequest) (*models.MerchantPattern, error) {
+	matchType := req.MatchType
+	if matchType == "" {
+		matchType = models.MatchContains
+	}
+	pattern := normalizeDescription(req.Pattern)
+	if err := validation.ValidateMerchantPattern(pattern, matchType); err != nil {
+		return nil, err
+	}
+	return &models.MerchantPattern{
+		ID:         uuid.New(),
+		UserID:     userID,
+		MerchantID: merchantID,
+		Pattern:    pattern,
+		MatchType:  matchType,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// ListMerchants retrieves the user's merchants with their patterns
+func (s *FinanceService) ListMerchants(userID uuid.UUID) ([]response.MerchantResponse, error) {
+	merchants, err := s.financeRepo.ListMerchants(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list merchants")
+	}
+	patterns, err := s.financeRepo.ListMerchantPatterns(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list merchant patterns")
+	}
+
+	byMerchant := make(map[uuid.UUID][]models.MerchantPattern)
+	for _, p := range patterns {
+		byMerchant[p.MerchantID] = append(byMerchant[p.MerchantID], p)
+	}
+
+	responses := make([]response.MerchantResponse, len(merchants))
+	for i, merchant := range merchants {
+		responses[i] = merchantResponse(merchant, byMerchant[merchant.ID])
+	}
+	return responses, nil
+}
+
+// GetMerchant retrieves a single merchant with its patterns
+func (s *FinanceService) GetMerchant(userID, merchantID uuid.UUID) (*response.MerchantResponse, error) {
+	merchant, err := s.financeRepo.GetMerchant(merchantID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrMerchantNotFound, "Failed to get merchant")
+	}
+	patterns, err := s.merchantPatterns(userID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	resp := merchantResponse(*merchant, patterns)
+	return &resp, nil
+}
+
+// merchantPatterns returns the patterns of one merchant
+func (s *FinanceService) merchantPatterns(userID, merchantID uuid.UUID) ([]models.MerchantPattern, error) {
+	patterns, err := s.financeRepo.ListMerchantPatterns(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list merchant patterns")
+	}
+	var own []models.MerchantPattern
+	for _, p := range patterns {
+		if p.MerchantID == merchantID {
+			own = append(own, p)
+		}
+	}
+	return own, nil
+}
+
+// UpdateMerchant renames a merchant
+func (s *FinanceService) UpdateMerchant(userID, merchantID uuid.UUID, req *request.UpdateMerchantRequest) (*response.MerchantResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := validation.ValidateMerchantName(name); err != nil {
+		return nil, err
+	}
+
+	before, err := s.financeRepo.GetMerchant(merchantID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrMerchantNotFound, "Failed to update merchant")
+	}
+
+	// Names are unique regardless of case
+	merchants, err := s.financeRepo.ListMerchants(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to update merchant")
+	}
+	for _, m := range merchants {
+		if m.ID != merchantID && strings.EqualFold(m.Name, name) {
+			return nil, errors.ErrMerchantExists
+		}
+	}
+
+	updates := map[string]interface{}{"name": name}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.UpdateMerchant(merchantID, userID, updates); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityMerchant, merchantID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		return nil, lookupError(err, errors.ErrMerchantNotFound, "Failed to update merchant")
+	}
+
+	return s.GetMerchant(userID, merchantID)
+}
+
+// DeleteMerchant removes a merchant and its patterns; its expenses are kept but unlinked
+func (s *FinanceService) DeleteMerchant(userID, merchantID uuid.UUID) error {
+	before, err := s.financeRepo.GetMerchant(merchantID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrMerchantNotFound, "Failed to delete merchant")
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.DeleteMerchant(merchantID, userID); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityMerchant, merchantID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrMerchantNotFound, "Failed to delete merchant")
+	}
+	return nil
+}
+
+// AddMerchantPattern adds a description pattern to a merchant
+func (s *FinanceService) AddMerchantPattern(userID, merchantID uuid.UUID, req *request.MerchantPatternRequest) (*response.MerchantResponse, error) {
+	if _, err := s.financeRepo.GetMerchant(merchantID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrMerchantNotFound, "Failed to add merchant pattern")
+	}
+	pattern, err := newMerchantPattern(userID, merchantID, req)
+	if err != nil {
+		return nil, err
+	}
+	err = s.transact(func(tx *FinanceService) error {
+		if err := tx.financeRepo.CreateMerchantPattern(pattern); err != nil {
+			return err
+		}
+		tx.audit.record(userID, EntityMerchantPattern, pattern.ID, AuditCreate, createdChanges(pattern))
+		return nil
+	})
+	if err != nil {
+		return nil, merchantError(err, "Failed to add merchant pattern")
+	}
+	return s.GetMerchant(userID, merchantID)
+}
+
+// DeleteMerchantPattern removes one of a merchant's patterns
+func (s *FinanceService) DeleteMerchantPattern(userID, merchantID, patternID uuid.UUID) error {
+	patterns, err := s.merchantPatterns(userID, merchantID)
+	if err != nil {
+		return err
+	}
+	for _, p := range patterns {
+		if p.ID == patternID {
+			err := s.transact(func(tx *FinanceService) error {
+				if err := tx.financeRepo.DeleteMerchantPattern(patternID, userID); err != nil {
+					return err
+				}
+				tx.audit.record(userID, EntityMerchantPattern, patternID, AuditDelete, deletedChanges(&p))
+				return nil
+			})
+			if err != nil {
+				return lookupError(err, errors.ErrPatternNotFound, "Failed to delete merchant pattern")
+			}
+			return nil
+		}
+	}
+	return errors.ErrPatternNotFound
+}
+
+// MergeMerchants folds the source merchants into the target: their expenses and
+// patterns move to the target and the sources are deleted
+func (s *FinanceService) MergeMerchants(userID, targetID uuid.UUID, req *request.MergeMerchantsRequest) (*response.MergeMerchantsResponse, error) {
+	seen := map[uuid.UUID]bool{targetID: true}
+	for _, id := range req.SourceIDs {
+		if seen[id] {
+			return nil, errors.NewWithDetails(
+				errors.ErrInvalidInput.Code,
+				"Invalid merge",
+				"Source merchants must be distinct and must not include the target",
+			)
+		}
+		seen[id] = true
+	}
+
+	if _, err := s.financeRepo.GetMerchant(targetID, userID); err != nil {
+		return nil, lookupError(err, errors.ErrMerchantNotFound, "Failed to merge merchants")
+	}
+	sources := make([]*models.Merchant, len(req.SourceIDs))
+	for i, id := range req.SourceIDs {
+		source, err := s.financeRepo.GetMerchant(id, userID)
+		if err != nil {
+			return nil, lookupError(err, errors.ErrMerchantNotFound, "Failed to merge merchants")
+		}
+		sources[i] = source
+	}
+
+	var moved int64
+	err := s.transact(func(tx *FinanceService) error {
+		var err error
+		if moved, err = tx.financeRepo.MergeMerchants(userID, targetID, req.SourceIDs); err != nil {
+			return err
+		}
+		for _, source := range sources {
+			tx.audit.record(userID, EntityMerchant, source.ID, AuditDelete, deletedChanges(source))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, lookupError(err, errors.ErrMerchantNotFound, "Failed to merge merchants")
+	}
+
+	merchant, err := s.GetMerchant(userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &response.MergeMerchantsResponse{Merchant: *merchant, MovedExpenses: moved}, nil
+}
+
+// ResolveMerchants links existing expenses to merchants using the current patterns.
+// Expenses that already have a merchant are only re-linked with Overwrite, and an
+// expense no pattern matches keeps its merchant.
+func (s *FinanceService) ResolveMerchants(userID uuid.UUID, req *request.ResolveMerchantsRequest) (*response.ResolveMerchantsResponse, error) {
+	patterns, err := s.financeRepo.ListMerchantPatterns(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load merchant patterns")
+	}
+	matcher := newMerchantMatcher(patterns)
+
+	expenses, err := s.financeRepo.ListExpenses(userID, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list expenses")
+	}
+
+	resp := &response.ResolveMerchantsResponse{DryRun: req.DryRun}
+	links := make(map[uuid.UUID]uuid.UUID)
+	for _, expense := range expenses {
+		if expense.MerchantID != nil && !req.Overwrite {
+			continue
+		}
+		resp.Evaluated++
+		merchantID := matcher.match(expense.Description)
+		if merchantID == nil || (expense.MerchantID != nil && *expense.MerchantID == *merchantID) {
+			continue
+		}
+		links[expense.ID] = *merchantID
+	}
+	resp.Linked = len(links)
+
+	if req.DryRun || len(links) == 0 {
+		return resp, nil
+	}
+
+	// Save every link together so a failure does not leave the expenses half updated.
+	// Each update carries the version the expense was matched at, so an expense edited
+	// since is skipped rather than overwritten.
+	err = s.audit.transact(s.financeRepo, func(repo repository.FinanceRepositoryInterface, audit auditor) error {
+		for _, expense := range expenses {
+			merchantID, ok := links[expense.ID]
+			if !ok {
+				continue
+			}
+			updates := map[string]interface{}{"merchant_id": &merchantID}
+			if err := repo.UpdateExpense(expense.ID, userID, expense.Version, updates); err != nil {
+				if stderrors.Is(err, repository.ErrVersionConflict) || stderrors.Is(err, gorm.ErrRecordNotFound) {
+					resp.Skipped++
+					continue
+				}
+				return err
+			}
+			audit.record(userID, EntityExpense, expense.ID, AuditUpdate, updatedChanges(&expense, updates))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to resolve merchants")
+	}
+	resp.Linked -= resp.Skipped
+
+	return resp, nil
+}
+
+// merchantSpending compares each merchant's spending in a month with the month before
+func (s *FinanceService) merchantSpending(userID uuid.UUID, summary *models.MonthlySummary) ([]response.MerchantSpendingResponse, error) {
+	previousMonth := time.Date(summary.Year, time.Month(summary.Month)-1, 1, 0, 0, 0, 0, time.UTC)
+	previous, err := s.financeRepo.GetMonthlySummary(userID, previousMonth.Year(), int(previousMonth.Month()))
+	if err != nil {
+		return nil, err
+	}
+	merchants, err := s.financeRepo.ListMerchants(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	spending := []response.MerchantSpendingResponse{}
+	for _, merchant := range merchants {
+		total, prior := summary.MerchantSpending[merchant.ID], previous.MerchantSpending[merchant.ID]
+		if total == 0 && prior == 0 {
+			continue
+		}
+		item := response.MerchantSpendingResponse{
+			MerchantID:    merchant.ID,
+			Name:          merchant.Name,
+			Total:         total,
+			PreviousTotal: prior,
+			Change:        total - prior,
+		}
+		if prior > 0 {
+			percent := math.Round((total-prior)/prior*10000) / 100
+			item.ChangePercent = &percent
+		}
+		spending = append(spending, item)
+	}
+	sort.SliceStable(spending, func(i, j int) bool { return spending[i].Total > spending[j].Total })
+	return spending, nil
+}
+
+// merchantError maps repository merchant errors to API errors
+func merchantError(err error, message string) error {
+	switch {
+	case stderrors.Is(err, repository.ErrMerchantExists):
+		return errors.ErrMerchantExists
+	case stderrors.Is(err, repository.ErrMerchantPatternExists):
+		return errors.ErrPatternExists
+	}
+	return errors.Wrap(err, errors.ErrDatabaseError.Code, message)
+}
+
+// merchantResponse converts a merchant and its patterns to their API representation
+func merchantResponse(merchant models.Merchant, patterns []models.MerchantPattern) response.MerchantResponse {
+	resp := response.MerchantResponse{
+		ID:        merchant.ID,
+		UserID:    merchant.UserID,
+		Name:      merchant.Name,
+		Patterns:  make([]response.MerchantPatternResponse, len(patterns)),
+		CreatedAt: merchant.CreatedAt,
+		UpdatedAt: merchant.UpdatedAt,
+	}
+	for i, p := range patterns {
+		resp.Patterns[i] = response.MerchantPatternResponse{
+			ID:        p.ID,
+			Pattern:   p.Pattern,
+			MatchType: p.MatchType,
+			CreatedAt: p.CreatedAt,
+		}
+	}
+	return resp
+}
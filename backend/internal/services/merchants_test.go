@@ -0,0 +1,212 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeDescription(t *testing.T) {
+	cases := map[string]string{
+		"AMZN Mktp US*2K3":         "amzn mktp us",
+		"Amazon.com":               "amazon",
+		"POS PURCHASE Tesco #4421": "tesco",
+		"www.netflix.com 12/03":    "netflix",
+		"  ":                       "",
+	}
+	for in, want := range cases {
+		if got := normalizeDescription(in); got != want {
+			t.Errorf("normalizeDescription(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCreateExpenseResolvesMerchant(t *testing.T) {
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(financeRepo, nil, nil)
+	userID := uuid.New()
+
+	amazon, err := finance.CreateMerchant(userID, &request.CreateMerchantRequest{
+		Name:     "Amazon",
+		Patterns: []request.MerchantPatternRequest{{Pattern: "AMZN Mktp", MatchType: "prefix"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateMerchant: %v", err)
+	}
+	if len(amazon.Patterns) != 2 {
+		t.Fatalf("expected the given pattern and the name pattern, got %+v", amazon.Patterns)
+	}
+
+	var linked uuid.UUID
+	for _, description := range []string{"AMZN Mktp US*2K3", "Amazon.com"} {
+		expense, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Shopping", Description: description, Amount: 10, SpentAt: time.Now()})
+		if err != nil {
+			t.Fatalf("CreateExpense %q: %v", description, err)
+		}
+		if expense.MerchantID == nil || *expense.MerchantID != amazon.ID {
+			t.Fatalf("expected %q to be linked to amazon, got %+v", description, expense.MerchantID)
+		}
+		linked = expense.ID
+	}
+
+	var unlink request.UpdateExpenseRequest
+	if err := json.Unmarshal([]byte(`{"merchant_id": null}`), &unlink); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	unlinked, err := finance.UpdateExpense(userID, linked, 0, &unlink)
+	if err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	if unlinked.MerchantID != nil {
+		t.Fatalf("expected a null merchant_id to unlink the merchant, got %v", *unlinked.MerchantID)
+	}
+
+	other, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Shopping", Description: "Amazing cafe", Amount: 5, SpentAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateExpense other: %v", err)
+	}
+	if other.MerchantID != nil {
+		t.Fatalf("expected no merchant for a partial word match, got %v", *other.MerchantID)
+	}
+
+	missing := uuid.New()
+	_, err = finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Shopping", Amount: 5, SpentAt: time.Now(), MerchantID: &missing})
+	if err != errors.ErrMerchantNotFound {
+		t.Fatalf("expected ErrMerchantNotFound for an unknown merchant, got %v", err)
+	}
+}
+
+func TestResolveMerchantsSkipsExpensesEditedMeanwhile(t *testing.T) {
+	financeRepo := &racingFinanceRepository{InMemoryFinanceRepository: repository.NewInMemoryFinanceRepository()}
+	auditRepo := repository.NewInMemoryAuditRepository()
+	finance := NewFinanceService(financeRepo, nil, auditRepo)
+	userID := uuid.New()
+
+	metro, _ := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Groceries", Description: "Tesco Metro", Amount: 12, SpentAt: time.Now()})
+	express, _ := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Groceries", Description: "Tesco Express", Amount: 8, SpentAt: time.Now()})
+	supermarket, err := finance.CreateMerchant(userID, &request.CreateMerchantRequest{Name: "Supermarket"})
+	if err != nil {
+		t.Fatalf("CreateMerchant: %v", err)
+	}
+	withPattern, err := finance.AddMerchantPattern(userID, supermarket.ID, &request.MerchantPatternRequest{Pattern: "Tesco"})
+	if err != nil {
+		t.Fatalf("AddMerchantPattern: %v", err)
+	}
+
+	amount := 9.0
+	financeRepo.afterList = func() {
+		if _, err := finance.UpdateExpense(userID, express.ID, 0, &request.UpdateExpenseRequest{Amount: &amount}); err != nil {
+			t.Fatalf("UpdateExpense: %v", err)
+		}
+	}
+	resolved, err := finance.ResolveMerchants(userID, &request.ResolveMerchantsRequest{})
+	if err != nil {
+		t.Fatalf("ResolveMerchants: %v", err)
+	}
+	if resolved.Linked != 1 || resolved.Skipped != 1 {
+		t.Fatalf("expected the edited expense to be skipped, got %+v", resolved)
+	}
+	if linked, _ := finance.GetExpense(userID, metro.ID); linked.MerchantID == nil || *linked.MerchantID != supermarket.ID {
+		t.Fatalf("expected the untouched expense to be linked, got %+v", linked.MerchantID)
+	}
+	if edited, _ := finance.GetExpense(userID, express.ID); edited.MerchantID != nil || edited.Amount != 9 {
+		t.Fatalf("expected the concurrent edit to survive, got %+v", edited)
+	}
+
+	// Pattern changes are audited like the merchant they belong to
+	for _, p := range withPattern.Patterns {
+		if p.Pattern == "tesco" {
+			if err := finance.DeleteMerchantPattern(userID, supermarket.ID, p.ID); err != nil {
+				t.Fatalf("DeleteMerchantPattern: %v", err)
+			}
+		}
+	}
+	entries, _ := auditRepo.ListAuditEntries(userID, repository.AuditFilter{EntityType: EntityMerchantPattern})
+	if len(entries) != 2 {
+		t.Fatalf("expected audit entries for adding and deleting the pattern, got %d", len(entries))
+	}
+}
+
+func TestMergeMerchantsAndSummaryTrends(t *testing.T) {
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(financeRepo, nil, nil)
+	userID := uuid.New()
+
+	// Expenses recorded before any merchant exists are linked by ResolveMerchants
+	lastMonth := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+	thisMonth := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	for _, e := range []struct {
+		description string
+		amount      float64
+		at          time.Time
+	}{
+		{"Amazon.com", 100, lastMonth},
+		{"AMZN Mktp US*2K3", 30, thisMonth},
+		{"Amazon.com", 120, thisMonth},
+	} {
+		if _, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Shopping", Description: e.description, Amount: e.amount, SpentAt: e.at}); err != nil {
+			t.Fatalf("CreateExpense: %v", err)
+		}
+	}
+
+	amazon, err := finance.CreateMerchant(userID, &request.CreateMerchantRequest{Name: "Amazon"})
+	if err != nil {
+		t.Fatalf("CreateMerchant amazon: %v", err)
+	}
+	amzn, err := finance.CreateMerchant(userID, &request.CreateMerchantRequest{Name: "AMZN Mktp"})
+	if err != nil {
+		t.Fatalf("CreateMerchant amzn: %v", err)
+	}
+	if _, err := finance.CreateMerchant(userID, &request.CreateMerchantRequest{Name: "amazon"}); err != errors.ErrMerchantExists {
+		t.Fatalf("expected ErrMerchantExists for a name differing only in case, got %v", err)
+	}
+
+	preview, err := finance.ResolveMerchants(userID, &request.ResolveMerchantsRequest{DryRun: true})
+	if err != nil {
+		t.Fatalf("ResolveMerchants dry run: %v", err)
+	}
+	if preview.Linked != 3 {
+		t.Fatalf("expected 3 expenses to be linked, got %+v", preview)
+	}
+	if summary, _ := finance.GetMonthlySummary(userID, 2024, 5); len(summary.MerchantSpending) != 0 {
+		t.Fatalf("expected a dry run to leave expenses unlinked, got %+v", summary.MerchantSpending)
+	}
+	if _, err := finance.ResolveMerchants(userID, &request.ResolveMerchantsRequest{}); err != nil {
+		t.Fatalf("ResolveMerchants: %v", err)
+	}
+
+	merged, err := finance.MergeMerchants(userID, amazon.ID, &request.MergeMerchantsRequest{SourceIDs: []uuid.UUID{amzn.ID}})
+	if err != nil {
+		t.Fatalf("MergeMerchants: %v", err)
+	}
+	if merged.MovedExpenses != 1 || len(merged.Merchant.Patterns) != 2 {
+		t.Fatalf("expected one expense and the source pattern to move, got %+v", merged)
+	}
+	if _, err := finance.GetMerchant(userID, amzn.ID); err != errors.ErrMerchantNotFound {
+		t.Fatalf("expected the source merchant to be deleted, got %v", err)
+	}
+	if _, err := finance.MergeMerchants(userID, amazon.ID, &request.MergeMerchantsRequest{SourceIDs: []uuid.UUID{amazon.ID}}); err == nil {
+		t.Fatal("expected merging a merchant into itself to fail")
+	}
+
+	summary, err := finance.GetMonthlySummary(userID, 2024, 5)
+	if err != nil {
+		t.Fatalf("GetMonthlySummary: %v", err)
+	}
+	if len(summary.MerchantSpending) != 1 {
+		t.Fatalf("expected one merchant in the summary, got %+v", summary.MerchantSpending)
+	}
+	spending := summary.MerchantSpending[0]
+	if spending.MerchantID != amazon.ID || spending.Total != 150 || spending.PreviousTotal != 100 || spending.Change != 50 {
+		t.Fatalf("unexpected merchant spending %+v", spending)
+	}
+	if spending.ChangePercent == nil || *spending.ChangePercent != 50 {
+		t.Fatalf("expected a 50%% increase, got %v", spending.ChangePercent)
+	}
+}
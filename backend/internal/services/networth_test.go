@@ -0,0 +1,208 @@
+package services
+
+import (
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestNetWorthItems(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+	now := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	for _, req := range []request.CreateNetWorthItemRequest{
+		{Name: "Savings account", Class: models.NetWorthAsset, Type: NetWorthSavings},
+		{Name: "House", Class: models.NetWorthAsset, Type: "mortgage"},
+		{Name: "  ", Class: models.NetWorthLiability, Type: "mortgage"},
+	} {
+		if _, err := finance.CreateNetWorthItem(userID, &req, now); err == nil {
+			t.Fatalf("expected %+v to be rejected", req)
+		}
+	}
+
+	value := 200000.0
+	house, err := finance.CreateNetWorthItem(userID, &request.CreateNetWorthItemRequest{Name: "House", Class: models.NetWorthAsset, Type: "property", Value: &value}, now)
+	if err != nil {
+		t.Fatalf("CreateNetWorthItem: %v", err)
+	}
+	if house.Value == nil || *house.Value != 200000 || !house.ValuedOn.Equal(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the house to be valued today, got %+v", house)
+	}
+
+	// A second valuation on the same day replaces the first; a later one is not yet current
+	revalued := 205000.0
+	if _, err := finance.CreateValuation(userID, house.ID, &request.CreateValuationRequest{Value: &revalued, Note: "Survey"}, now); err != nil {
+		t.Fatalf("CreateValuation: %v", err)
+	}
+	later := now.AddDate(0, 1, 0)
+	future, err := finance.CreateValuation(userID, house.ID, &request.CreateValuationRequest{Value: &value, ValuedOn: &later}, now)
+	if err != nil {
+		t.Fatalf("CreateValuation: %v", err)
+	}
+	valuations, err := finance.ListValuations(userID, house.ID)
+	if err != nil {
+		t.Fatalf("ListValuations: %v", err)
+	}
+	if len(valuations) != 2 || valuations[0].Value != 205000 || valuations[0].Note != "Survey" {
+		t.Fatalf("expected the same-day valuation to be replaced, got %+v", valuations)
+	}
+	house, err = finance.GetNetWorthItem(userID, house.ID, now)
+	if err != nil || *house.Value != 205000 {
+		t.Fatalf("expected today's valuation to be current, got %+v, %v", house, err)
+	}
+
+	car, err := finance.CreateNetWorthItem(userID, &request.CreateNetWorthItemRequest{Name: "Car", Class: models.NetWorthAsset, Type: "vehicle"}, now)
+	if err != nil {
+		t.Fatalf("CreateNetWorthItem: %v", err)
+	}
+	if car.Value != nil {
+		t.Fatalf("expected an item without valuations to have no value, got %+v", car)
+	}
+	if err := finance.DeleteValuation(userID, car.ID, future.ID); err != errors.ErrValuationNotFound {
+		t.Fatalf("expected another item's valuation to be not found, got %v", err)
+	}
+	if err := finance.DeleteValuation(userID, house.ID, future.ID); err != nil {
+		t.Fatalf("DeleteValuation: %v", err)
+	}
+
+	mortgage := "mortgage"
+	if _, err := finance.UpdateNetWorthItem(userID, car.ID, &request.UpdateNetWorthItemRequest{Type: &mortgage}, now); err == nil {
+		t.Fatal("expected a liability type on an asset to be rejected")
+	}
+	if err := finance.DeleteNetWorthItem(userID, house.ID); err != nil {
+		t.Fatalf("DeleteNetWorthItem: %v", err)
+	}
+	if _, err := finance.ListValuations(userID, house.ID); err != errors.ErrNetWorthItemNotFound {
+		t.Fatalf("expected the deleted item to be not found, got %v", err)
+	}
+}
+
+func TestNetWorthSnapshots(t *testing.T) {
+	repo := repository.NewInMemoryFinanceRepository()
+	finance := NewFinanceService(repo, nil, nil)
+	userID := uuid.New()
+	now := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := finance.CreateIncome(userID, &request.CreateIncomeRequest{Source: "Salary", Amount: 3000, ReceivedAt: february}); err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+	if _, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Rent", Amount: 500, SpentAt: february.AddDate(0, 0, 4)}); err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	goal, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Holiday", TargetAmount: 2000})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	if _, err := finance.CreateGoalContribution(userID, &request.CreateGoalContributionRequest{GoalID: goal.ID, Amount: 400, ContributedAt: february.AddDate(0, 0, 9)}); err != nil {
+		t.Fatalf("CreateGoalContribution: %v", err)
+	}
+	startedOn := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	if _, err := finance.CreateDebt(userID, &request.CreateDebtRequest{Name: "Car loan", Kind: models.DebtLoan, Principal: 1000, MinimumPayment: 100, DueDay: 15, StartedOn: &startedOn}, now); err != nil {
+		t.Fatalf("CreateDebt: %v", err)
+	}
+
+	houseValue, mortgageValue := 200000.0, 150000.0
+	house, err := finance.CreateNetWorthItem(userID, &request.CreateNetWorthItemRequest{Name: "House", Class: models.NetWorthAsset, Type: "property", Value: &houseValue, ValuedOn: &february}, now)
+	if err != nil {
+		t.Fatalf("CreateNetWorthItem: %v", err)
+	}
+	if _, err := finance.CreateNetWorthItem(userID, &request.CreateNetWorthItemRequest{Name: "Mortgage", Class: models.NetWorthLiability, Type: "mortgage", Value: &mortgageValue, ValuedOn: &february}, now); err != nil {
+		t.Fatalf("CreateNetWorthItem: %v", err)
+	}
+	march := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	houseValue = 210000
+	if _, err := finance.CreateValuation(userID, house.ID, &request.CreateValuationRequest{Value: &houseValue, ValuedOn: &march}, now); err != nil {
+		t.Fatalf("CreateValuation: %v", err)
+	}
+
+	stored, err := finance.RunNetWorthSnapshots(nil, now)
+	if err != nil {
+		t.Fatalf("RunNetWorthSnapshots: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("expected one snapshot, got %+v", stored)
+	}
+	snapshot := stored[0]
+	if !snapshot.Date.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) || snapshot.Assets != 202500 || snapshot.Liabilities != 151000 || snapshot.NetWorth != 51500 {
+		t.Fatalf("expected February's month-end net worth, got %+v", snapshot)
+	}
+	amounts := make(map[string]float64)
+	for _, c := range snapshot.Breakdown {
+		amounts[c.Class+"/"+c.Type] = c.Amount
+	}
+	if amounts["asset/cash"] != 2100 || amounts["asset/savings"] != 400 || amounts["asset/property"] != 200000 || amounts["liability/mortgage"] != 150000 || amounts["liability/loan"] != 1000 {
+		t.Fatalf("unexpected breakdown %+v", snapshot.Breakdown)
+	}
+
+	// Running again for the same month stores nothing
+	stored, err = finance.RunNetWorthSnapshots(&userID, now.Add(time.Hour))
+	if err != nil || len(stored) != 0 {
+		t.Fatalf("expected the snapshot not to be taken twice, got %+v, %v", stored, err)
+	}
+
+	history, err := finance.GetNetWorthHistory(userID, &request.NetWorthHistoryRequest{Months: 1}, now)
+	if err != nil {
+		t.Fatalf("GetNetWorthHistory: %v", err)
+	}
+	if len(history.Snapshots) != 1 || history.Snapshots[0].NetWorth != 51500 || len(history.Snapshots[0].Breakdown) != len(snapshot.Breakdown) {
+		t.Fatalf("expected the stored snapshot in the history, got %+v", history.Snapshots)
+	}
+	if history.Current.NetWorth != 61500 {
+		t.Fatalf("expected today's net worth to use the March valuation, got %+v", history.Current)
+	}
+	if _, err := finance.GetNetWorthHistory(userID, &request.NetWorthHistoryRequest{Months: 121}, now); err == nil {
+		t.Fatal("expected too long a history to be rejected")
+	}
+}
+
+// failingSnapshotRepository fails to store the net worth snapshot of one user
+type failingSnapshotRepository struct {
+	*repository.InMemoryFinanceRepository
+	failFor uuid.UUID
+}
+
+func (r *failingSnapshotRepository) CreateNetWorthSnapshot(snapshot *models.NetWorthSnapshot) error {
+	if snapshot.UserID == r.failFor {
+		return stderrors.New("disk full")
+	}
+	return r.InMemoryFinanceRepository.CreateNetWorthSnapshot(snapshot)
+}
+
+func TestNetWorthSnapshotsContinuePastAFailingUser(t *testing.T) {
+	failing, healthy := uuid.New(), uuid.New()
+	repo := &failingSnapshotRepository{InMemoryFinanceRepository: repository.NewInMemoryFinanceRepository(), failFor: failing}
+	finance := NewFinanceService(repo, nil, nil)
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	for _, user := range []uuid.UUID{failing, healthy} {
+		if _, err := finance.CreateIncome(user, &request.CreateIncomeRequest{Source: "Salary", Amount: 1000, ReceivedAt: february}); err != nil {
+			t.Fatalf("CreateIncome: %v", err)
+		}
+	}
+
+	now := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	stored, err := finance.RunNetWorthSnapshots(nil, now)
+	if err == nil {
+		t.Fatal("expected the failed snapshot to be reported")
+	}
+	if len(stored) != 1 {
+		t.Fatalf("expected the other user's snapshot to be stored, got %+v", stored)
+	}
+	snapshots, err := repo.ListNetWorthSnapshots(healthy, now.AddDate(0, -1, 0), now)
+	if err != nil || len(snapshots) != 1 {
+		t.Fatalf("expected a snapshot for the healthy user, got %+v, %v", snapshots, err)
+	}
+
+	if _, err := finance.RunNetWorthSnapshots(&failing, now); err == nil {
+		t.Fatal("expected a single user's failure to be returned")
+	}
+}
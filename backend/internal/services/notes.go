@@ -1,6 +1,7 @@
 package services
 
 import (
+	stderrors "errors"
 	"time"
 
 	"finance-management/internal/dto/request"
@@ -16,15 +17,39 @@ import (
 // NotesService handles business logic for notes operations
 type NotesService struct {
 	notesRepo repository.NotesRepositoryInterface
+	audit     auditor
 }
 
-// NewNotesService creates a new notes service
-func NewNotesService(notesRepo repository.NotesRepositoryInterface) *NotesService {
+// NewNotesService creates a new notes service. Mutations are recorded in
+// auditRepo unless it is nil.
+func NewNotesService(notesRepo repository.NotesRepositoryInterface, auditRepo repository.AuditRepositoryInterface) *NotesService {
 	return &NotesService{
 		notesRepo: notesRepo,
+		audit:     newAuditor(auditRepo),
 	}
 }
 
+// WithActor returns a copy of the service that attributes audit entries to actor
+func (s *NotesService) WithActor(actor string) *NotesService {
+	c := *s
+	c.audit.actor = actor
+	return &c
+}
+
+// transact runs fn in a transaction together with the audit entries it records through
+// audit, so a change is stored with its audit trail or not at all
+func (s *NotesService) transact(fn func(repo repository.NotesRepositoryInterface, audit auditor) error) error {
+	return s.notesRepo.Transaction(func(repo repository.NotesRepositoryInterface) error {
+		var pending pendingWork
+		audit := s.audit
+		audit.pending = &pending
+		if err := fn(repo, audit); err != nil {
+			return err
+		}
+		return s.audit.flush(repo, &pending)
+	})
+}
+
 // CreateNote creates a new note
 func (s *NotesService) CreateNote(userID uuid.UUID, req *request.CreateNoteRequest) (*response.NoteResponse, error) {
 	// Validate note data
@@ -53,22 +78,20 @@ func (s *NotesService) CreateNote(userID uuid.UUID, req *request.CreateNoteReque
 	}
 
 	// Save to database
-	if err := s.notesRepo.CreateNote(note); err != nil {
+	err := s.transact(func(repo repository.NotesRepositoryInterface, audit auditor) error {
+		if err := repo.CreateNote(note); err != nil {
+			return err
+		}
+		audit.record(userID, EntityNote, note.ID, AuditCreate, createdChanges(note))
+		return nil
+	})
+	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create note")
 	}
 
 	// Convert to response
-	return &response.NoteResponse{
-		ID:         note.ID,
-		Title:      note.Title,
-		Content:    note.Content,
-		Category:   note.Category,
-		Tags:       note.Tags,
-		IsFavorite: note.IsFavorite,
-		IsArchived: note.IsArchived,
-		CreatedAt:  note.CreatedAt,
-		UpdatedAt:  note.UpdatedAt,
-	}, nil
+	resp := noteResponse(*note)
+	return &resp, nil
 }
 
 // GetNote retrieves a specific note by ID
@@ -82,17 +105,8 @@ func (s *NotesService) GetNote(userID, noteID uuid.UUID) (*response.NoteResponse
 	}
 
 	// Convert to response
-	return &response.NoteResponse{
-		ID:         note.ID,
-		Title:      note.Title,
-		Content:    note.Content,
-		Category:   note.Category,
-		Tags:       note.Tags,
-		IsFavorite: note.IsFavorite,
-		IsArchived: note.IsArchived,
-		CreatedAt:  note.CreatedAt,
-		UpdatedAt:  note.UpdatedAt,
-	}, nil
+	resp := noteResponse(*note)
+	return &resp, nil
 }
 
 // ListNotes retrieves all notes for a user
@@ -105,24 +119,15 @@ func (s *NotesService) ListNotes(userID uuid.UUID) ([]response.NoteResponse, err
 	// Convert to response
 	responses := make([]response.NoteResponse, len(notes))
 	for i, note := range notes {
-		responses[i] = response.NoteResponse{
-			ID:         note.ID,
-			Title:      note.Title,
-			Content:    note.Content,
-			Category:   note.Category,
-			Tags:       note.Tags,
-			IsFavorite: note.IsFavorite,
-			IsArchived: note.IsArchived,
-			CreatedAt:  note.CreatedAt,
-			UpdatedAt:  note.UpdatedAt,
-		}
+		responses[i] = noteResponse(note)
 	}
 
 	return responses, nil
 }
 
-// UpdateNote updates an existing note
-func (s *NotesService) UpdateNote(userID, noteID uuid.UUID, req *request.UpdateNoteRequest) (*response.NoteResponse, error) {
+// UpdateNote updates an existing note. A non-zero version must match the stored
+// one; otherwise a 412 error carrying the current note is returned
+func (s *NotesService) UpdateNote(userID, noteID uuid.UUID, version int, req *request.UpdateNoteRequest) (*response.NoteResponse, error) {
 	updates := make(map[string]interface{})
 
 	if req.Title != nil {
@@ -160,11 +165,36 @@ func (s *NotesService) UpdateNote(userID, noteID uuid.UUID, req *request.UpdateN
 		return nil, errors.ErrInvalidInput
 	}
 
+	before, err := s.notesRepo.GetNoteByID(noteID, userID)
+	if err != nil {
+		if err.Error() == "note not found" {
+			return nil, errors.ErrNoteNotFound
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to update note")
+	}
+	if version > 0 && before.Version != version {
+		return nil, errors.NewPreconditionFailed(noteResponse(*before))
+	}
+
 	// Update the note
-	if err := s.notesRepo.UpdateNote(noteID, userID, updates); err != nil {
+	err = s.transact(func(repo repository.NotesRepositoryInterface, audit auditor) error {
+		if err := repo.UpdateNote(noteID, userID, version, updates); err != nil {
+			return err
+		}
+		audit.record(userID, EntityNote, noteID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
 		if err.Error() == "note not found or no changes made" {
 			return nil, errors.ErrNoteNotFound
 		}
+		if stderrors.Is(err, repository.ErrVersionConflict) {
+			current, getErr := s.GetNote(userID, noteID)
+			if getErr != nil {
+				return nil, getErr
+			}
+			return nil, errors.NewPreconditionFailed(*current)
+		}
 		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to update note")
 	}
 
@@ -175,7 +205,56 @@ func (s *NotesService) UpdateNote(userID, noteID uuid.UUID, req *request.UpdateN
 	}
 
 	// Convert to response
-	return &response.NoteResponse{
+	resp := noteResponse(*note)
+	return &resp, nil
+}
+
+// DeleteNote moves a note to the trash
+func (s *NotesService) DeleteNote(userID, noteID uuid.UUID) error {
+	before, err := s.notesRepo.GetNoteByID(noteID, userID)
+	if err != nil {
+		if err.Error() == "note not found" {
+			return errors.ErrNoteNotFound
+		}
+		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to delete note")
+	}
+	err = s.transact(func(repo repository.NotesRepositoryInterface, audit auditor) error {
+		if err := repo.DeleteNote(noteID, userID); err != nil {
+			return err
+		}
+		audit.record(userID, EntityNote, noteID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		if err.Error() == "note not found" {
+			return errors.ErrNoteNotFound
+		}
+		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to delete note")
+	}
+	return nil
+}
+
+// RestoreNote takes a note out of the trash
+func (s *NotesService) RestoreNote(userID, noteID uuid.UUID) error {
+	err := s.transact(func(repo repository.NotesRepositoryInterface, audit auditor) error {
+		if err := repo.RestoreNote(noteID, userID); err != nil {
+			return err
+		}
+		audit.record(userID, EntityNote, noteID, AuditRestore, nil)
+		return nil
+	})
+	if err != nil {
+		if err.Error() == "note not found" {
+			return errors.ErrTrashItemNotFound
+		}
+		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to restore note")
+	}
+	return nil
+}
+
+// noteResponse converts a note model to its API representation
+func noteResponse(note models.Note) response.NoteResponse {
+	return response.NoteResponse{
 		ID:         note.ID,
 		Title:      note.Title,
 		Content:    note.Content,
@@ -183,18 +262,8 @@ func (s *NotesService) UpdateNote(userID, noteID uuid.UUID, req *request.UpdateN
 		Tags:       note.Tags,
 		IsFavorite: note.IsFavorite,
 		IsArchived: note.IsArchived,
+		Version:    note.Version,
 		CreatedAt:  note.CreatedAt,
 		UpdatedAt:  note.UpdatedAt,
-	}, nil
-}
-
-// DeleteNote deletes a note
-func (s *NotesService) DeleteNote(userID, noteID uuid.UUID) error {
-	if err := s.notesRepo.DeleteNote(noteID, userID); err != nil {
-		if err.Error() == "note not found" {
-			return errors.ErrNoteNotFound
-		}
-		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to delete note")
 	}
-	return nil
 }
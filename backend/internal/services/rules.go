@@ -0,0 +1,580 @@
+package services
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+	"finance-management/internal/validation"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RulesService manages expense rules and re-applies them to existing expenses
+type RulesService struct {
+	ruleRepo    repository.RuleRepositoryInterface
+	financeRepo repository.FinanceRepositoryInterface
+	audit       auditor
+}
+
+// NewRulesService creates a new rules service. Mutations are recorded in
+// auditRepo unless it is nil.
+func NewRulesService(ruleRepo repository.RuleRepositoryInterface, financeRepo repository.FinanceRepositoryInterface, auditRepo repository.AuditRepositoryInterface) *RulesService {
+	return &RulesService{
+		ruleRepo:    ruleRepo,
+		financeRepo: financeRepo,
+		audit:       newAuditor(auditRepo),
+	}
+}
+
+// WithActor returns a copy of the service that attributes audit entries to actor
+func (s *RulesService) WithActor(actor string) *RulesService {
+	c := *s
+	c.audit.actor = actor
+	return &c
+}
+
+// transact runs fn in a transaction together with the audit entries it records through
+// audit, so a change is stored with its audit trail or not at all
+func (s *RulesService) transact(fn func(repo repository.RuleRepositoryInterface, audit auditor) error) error {
+	return s.ruleRepo.Transaction(func(repo repository.RuleRepositoryInterface) error {
+		var pending pendingWork
+		audit := s.audit
+		audit.pending = &pending
+		if err := fn(repo, audit); err != nil {
+			return err
+		}
+		return s.audit.flush(repo, &pending)
+	})
+}
+
+// CreateRule creates a new expense rule
+func (s *RulesService) CreateRule(userID uuid.UUID, req *request.CreateRuleRequest) (*response.RuleResponse, error) {
+	if err := validation.ValidateRuleName(req.Name); err != nil {
+		return nil, err
+	}
+	conditions, err := ruleConditions(req.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateRuleActions(req.SetCategory, req.SetGoalID, req.ClearGoal); err != nil {
+		return nil, err
+	}
+	if err := s.checkRuleGoal(userID, req.SetGoalID); err != nil {
+		return nil, err
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	now := time.Now().UTC()
+	rule := &models.ExpenseRule{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		Priority:    req.Priority,
+		Enabled:     enabled,
+		Conditions:  conditions,
+		SetCategory: req.SetCategory,
+		SetGoalID:   req.SetGoalID,
+		ClearGoal:   req.ClearGoal,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	err = s.transact(func(repo repository.RuleRepositoryInterface, audit auditor) error {
+		if err := repo.CreateRule(rule); err != nil {
+			return err
+		}
+		audit.record(userID, EntityExpenseRule, rule.ID, AuditCreate, createdChanges(rule))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to create rule")
+	}
+
+	resp := ruleResponse(*rule)
+	return &resp, nil
+}
+
+// ListRules retrieves the user's rules in evaluation order
+func (s *RulesService) ListRules(userID uuid.UUID) ([]response.RuleResponse, error) {
+	rules, err := s.ruleRepo.ListRules(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list rules")
+	}
+
+	responses := make([]response.RuleResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = ruleResponse(rule)
+	}
+	return responses, nil
+}
+
+// GetRule retrieves a single rule
+func (s *RulesService) GetRule(userID, ruleID uuid.UUID) (*response.RuleResponse, error) {
+	rule, err := s.ruleRepo.GetRule(ruleID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrRuleNotFound, "Failed to get rule")
+	}
+	resp := ruleResponse(*rule)
+	return &resp, nil
+}
+
+// UpdateRule updates an existing rule
+func (s *RulesService) UpdateRule(userID, ruleID uuid.UUID, req *request.UpdateRuleRequest) (*response.RuleResponse, error) {
+	before, err := s.ruleRepo.GetRule(ruleID, userID)
+	if err != nil {
+		return nil, lookupError(err, errors.ErrRuleNotFound, "Failed to update rule")
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		if err := validation.ValidateRuleName(*req.Name); err != nil {
+			return nil, err
+		}
+		updates["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.Priority != nil {
+		updates["priority"] = *req.Priority
+	}
+	if req.Enabled != nil {
+		updates["enabled"] = *req.Enabled
+	}
+	if req.Conditions != nil {
+		conditions, err := ruleConditions(req.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		updates["conditions"] = conditions
+	}
+
+	// Validate the actions the rule will have after the update
+	setCategory, setGoalID, clearGoal := before.SetCategory, before.SetGoalID, before.ClearGoal
+	if req.SetCategory != nil {
+		setCategory = req.SetCategory
+		if *req.SetCategory == "" {
+			setCategory = nil
+		}
+		updates["set_category"] = setCategory
+	}
+	if req.SetGoalID != nil {
+		setGoalID = req.SetGoalID
+		if *req.SetGoalID == uuid.Nil {
+			setGoalID = nil
+		}
+		updates["set_goal_id"] = setGoalID
+	}
+	if req.ClearGoal != nil {
+		clearGoal = *req.ClearGoal
+		updates["clear_goal"] = clearGoal
+	}
+	if len(updates) == 0 {
+		return nil, errors.ErrInvalidInput
+	}
+	if err := validation.ValidateRuleActions(setCategory, setGoalID, clearGoal); err != nil {
+		return nil, err
+	}
+	if req.SetGoalID != nil {
+		if err := s.checkRuleGoal(userID, setGoalID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.transact(func(repo repository.RuleRepositoryInterface, audit auditor) error {
+		if err := repo.UpdateRule(ruleID, userID, updates); err != nil {
+			return err
+		}
+		audit.record(userID, EntityExpenseRule, ruleID, AuditUpdate, updatedChanges(before, updates))
+		return nil
+	})
+	if err != nil {
+		return nil, lookupError(err, errors.ErrRuleNotFound, "Failed to update rule")
+	}
+
+	return s.GetRule(userID, ruleID)
+}
+
+// DeleteRule permanently removes a rule; expenses it already changed keep their values
+func (s *RulesService) DeleteRule(userID, ruleID uuid.UUID) error {
+	before, err := s.ruleRepo.GetRule(ruleID, userID)
+	if err != nil {
+		return lookupError(err, errors.ErrRuleNotFound, "Failed to delete rule")
+	}
+	err = s.transact(func(repo repository.RuleRepositoryInterface, audit auditor) error {
+		if err := repo.DeleteRule(ruleID, userID); err != nil {
+			return err
+		}
+		audit.record(userID, EntityExpenseRule, ruleID, AuditDelete, deletedChanges(before))
+		return nil
+	})
+	if err != nil {
+		return lookupError(err, errors.ErrRuleNotFound, "Failed to delete rule")
+	}
+	return nil
+}
+
+// ApplyRules re-runs rules over the user's existing expenses. A dry run reports the
+// changes without saving them or counting matches. Rules named in req.RuleIDs run
+// even when disabled, so a new rule can be previewed before it is enabled.
+func (s *RulesService) ApplyRules(userID uuid.UUID, req *request.ApplyRulesRequest) (*response.ApplyRulesResponse, error) {
+	rules, err := s.selectRules(userID, req.RuleIDs)
+	if err != nil {
+		return nil, err
+	}
+	active, err := ruleGoals(s.financeRepo, userID, rules)
+	if err != nil {
+		return nil, err
+	}
+	compiled := compileRules(rules, active)
+
+	expenses, err := s.financeRepo.ListExpenses(userID, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list expenses")
+	}
+
+	resp := &response.ApplyRulesResponse{DryRun: req.DryRun, Changes: []response.RuleChange{}}
+	matches := make(map[uuid.UUID]int)
+	var changed []models.Expense
+	for _, expense := range expenses {
+		if req.From != nil && expense.SpentAt.Before(*req.From) {
+			continue
+		}
+		if req.To != nil && !expense.SpentAt.Before(*req.To) {
+			continue
+		}
+		resp.Evaluated++
+
+		after := expense
+		matched := applyRules(compiled, &after)
+		if len(matched) == 0 {
+			continue
+		}
+		resp.Matched++
+		for _, id := range matched {
+			matches[id]++
+		}
+		if after.Category == expense.Category && sameGoal(after.GoalID, expense.GoalID) {
+			continue
+		}
+		changed = append(changed, after)
+		resp.Changes = append(resp.Changes, response.RuleChange{
+			ExpenseID:   expense.ID,
+			Description: expense.Description,
+			Amount:      expense.Amount,
+			SpentAt:     expense.SpentAt,
+			Before:      response.RuleOutcome{Category: expense.Category, GoalID: expense.GoalID},
+			After:       response.RuleOutcome{Category: after.Category, GoalID: after.GoalID},
+			RuleIDs:     matched,
+		})
+	}
+	resp.Changed = len(changed)
+
+	resp.Rules = make([]response.RuleMatchStats, len(compiled))
+	for i, rule := range compiled {
+		resp.Rules[i] = response.RuleMatchStats{RuleID: rule.rule.ID, Name: rule.rule.Name, Matches: matches[rule.rule.ID]}
+	}
+
+	if req.DryRun {
+		return resp, nil
+	}
+
+	// Save every change together so a failure does not leave the expenses half updated.
+	// Each update carries the version rules saw, so an expense edited since is skipped
+	// rather than overwritten.
+	skipped := make(map[uuid.UUID]bool)
+	err = s.audit.transact(s.financeRepo, func(repo repository.FinanceRepositoryInterface, audit auditor) error {
+		for i := range changed {
+			expense := &changed[i]
+			before, err := repo.GetExpense(expense.ID, userID)
+			if stderrors.Is(err, gorm.ErrRecordNotFound) {
+				skipped[expense.ID] = true
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if before.Version != expense.Version {
+				skipped[expense.ID] = true
+				continue
+			}
+			category, err := categoryByName(repo, audit, userID, expense.Category)
+			if err != nil {
+				return err
+			}
+			updates := map[string]interface{}{"category": category.Name, "category_id": category.ID, "goal_id": expense.GoalID}
+			if err := repo.UpdateExpense(expense.ID, userID, expense.Version, updates); err != nil {
+				if stderrors.Is(err, repository.ErrVersionConflict) || stderrors.Is(err, gorm.ErrRecordNotFound) {
+					skipped[expense.ID] = true
+					continue
+				}
+				return err
+			}
+			audit.record(userID, EntityExpense, expense.ID, AuditUpdate, updatedChanges(before, updates))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to apply rules")
+	}
+	recordRuleMatches(s.ruleRepo, userID, matches)
+
+	if len(skipped) > 0 {
+		applied := resp.Changes[:0]
+		for _, change := range resp.Changes {
+			if !skipped[change.ExpenseID] {
+				applied = append(applied, change)
+			}
+		}
+		resp.Changes = applied
+		resp.Changed = len(applied)
+		resp.Skipped = len(skipped)
+	}
+
+	return resp, nil
+}
+
+// selectRules returns the rules named by ids, or every enabled rule when ids is empty
+func (s *RulesService) selectRules(userID uuid.UUID, ids []uuid.UUID) ([]models.ExpenseRule, error) {
+	rules, err := s.ruleRepo.ListRules(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list rules")
+	}
+	if len(ids) == 0 {
+		return enabledRules(rules), nil
+	}
+
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	var selected []models.ExpenseRule
+	for _, rule := range rules {
+		if wanted[rule.ID] {
+			selected = append(selected, rule)
+			delete(wanted, rule.ID)
+		}
+	}
+	if len(wanted) > 0 {
+		return nil, errors.ErrRuleNotFound
+	}
+	return selected, nil
+}
+
+// checkRuleGoal verifies that a goal a rule links expenses to belongs to the user
+func (s *RulesService) checkRuleGoal(userID uuid.UUID, goalID *uuid.UUID) error {
+	if goalID == nil {
+		return nil
+	}
+	if _, err := s.financeRepo.GetGoal(*goalID, userID); err != nil {
+		return lookupError(err, errors.ErrGoalNotFound, "Failed to check rule goal")
+	}
+	return nil
+}
+
+// ruleGoals returns the ids of the active goals rules may link expenses to, loading
+// them only when one of rules sets a goal
+func ruleGoals(repo repository.FinanceRepositoryInterface, userID uuid.UUID, rules []models.ExpenseRule) (map[uuid.UUID]bool, error) {
+	if !slices.ContainsFunc(rules, func(rule models.ExpenseRule) bool { return rule.SetGoalID != nil }) {
+		return nil, nil
+	}
+	goals, err := repo.ListGoalsWithProgress(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load rule goals")
+	}
+	active := make(map[uuid.UUID]bool, len(goals))
+	for _, g := range activeGoals(goals) {
+		active[g.Goal.ID] = true
+	}
+	return active, nil
+}
+
+// ruleConditions validates conditions and encodes them for storage
+func ruleConditions(reqs []request.RuleConditionRequest) (string, error) {
+	conditions := make([]models.RuleCondition, len(reqs))
+	for i, req := range reqs {
+		conditions[i] = models.RuleCondition{
+			Field:    strings.ToLower(strings.TrimSpace(req.Field)),
+			Operator: strings.ToLower(strings.TrimSpace(req.Operator)),
+			Value:    strings.TrimSpace(req.Value),
+		}
+		if err := validation.ValidateRuleCondition(conditions[i]); err != nil {
+			return "", err
+		}
+	}
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrInvalidInput.Code, "Invalid rule conditions")
+	}
+	return string(data), nil
+}
+
+// compiledRule is a rule with its conditions decoded for evaluation
+type compiledRule struct {
+	rule       models.ExpenseRule
+	conditions []models.RuleCondition
+}
+
+// enabledRules drops disabled rules, keeping evaluation order
+func enabledRules(rules []models.ExpenseRule) []models.ExpenseRule {
+	var enabled []models.ExpenseRule
+	for _, rule := range rules {
+		if rule.Enabled {
+			enabled = append(enabled, rule)
+		}
+	}
+	return enabled
+}
+
+// compileRules decodes each rule's conditions. Rules whose conditions cannot be
+// decoded are skipped so one bad row does not stop categorisation. A rule linking
+// expenses to a goal that is not in activeGoals, because it was trashed, archived or
+// otherwise stopped, leaves the goal alone and only sets its category.
+func compileRules(rules []models.ExpenseRule, activeGoals map[uuid.UUID]bool) []compiledRule {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		var conditions []models.RuleCondition
+		if err := json.Unmarshal([]byte(rule.Conditions), &conditions); err != nil || len(conditions) == 0 {
+			log.Printf("⚠️  Skipping expense rule %s with invalid conditions: %v", rule.ID, err)
+			continue
+		}
+		if rule.SetGoalID != nil && !activeGoals[*rule.SetGoalID] {
+			rule.SetGoalID = nil
+		}
+		compiled = append(compiled, compiledRule{rule: rule, conditions: conditions})
+	}
+	return compiled
+}
+
+// applyRules runs rules against expense in order and returns the ids of those that
+// matched. The first matching rule to set the category or goal wins; conditions
+// always see the expense as it was before any rule changed it.
+func applyRules(rules []compiledRule, expense *models.Expense) []uuid.UUID {
+	original := *expense
+	var matched []uuid.UUID
+	categorySet, goalSet := false, false
+	for _, r := range rules {
+		if !ruleMatches(r.conditions, &original) {
+			continue
+		}
+		matched = append(matched, r.rule.ID)
+		if r.rule.SetCategory != nil && !categorySet {
+			expense.Category = *r.rule.SetCategory
+			categorySet = true
+		}
+		if (r.rule.SetGoalID != nil || r.rule.ClearGoal) && !goalSet {
+			expense.GoalID = r.rule.SetGoalID
+			goalSet = true
+		}
+	}
+	return matched
+}
+
+// ruleMatches reports whether expense satisfies every condition
+func ruleMatches(conditions []models.RuleCondition, expense *models.Expense) bool {
+	for _, c := range conditions {
+		if !conditionMatches(c, expense) {
+			return false
+		}
+	}
+	return true
+}
+
+func conditionMatches(c models.RuleCondition, expense *models.Expense) bool {
+	if c.Field == models.RuleFieldAmount {
+		value, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return false
+		}
+		switch c.Operator {
+		case models.RuleOpEquals:
+			return expense.Amount == value
+		case models.RuleOpGreater:
+			return expense.Amount > value
+		case models.RuleOpGreaterEq:
+			return expense.Amount >= value
+		case models.RuleOpLess:
+			return expense.Amount < value
+		case models.RuleOpLessEq:
+			return expense.Amount <= value
+		}
+		return false
+	}
+
+	var text string
+	switch c.Field {
+	case models.RuleFieldDescription:
+		text = expense.Description
+	case models.RuleFieldCategory:
+		text = expense.Category
+	default:
+		return false
+	}
+	text, value := strings.ToLower(text), strings.ToLower(c.Value)
+	switch c.Operator {
+	case models.RuleOpContains:
+		return strings.Contains(text, value)
+	case models.RuleOpEquals:
+		return text == value
+	case models.RuleOpStartsWith:
+		return strings.HasPrefix(text, value)
+	case models.RuleOpEndsWith:
+		return strings.HasSuffix(text, value)
+	}
+	return false
+}
+
+// recordRuleMatches bumps the match statistics of the rules that matched. The
+// counters are informational, so failures are logged rather than returned.
+func recordRuleMatches(repo repository.RuleRepositoryInterface, userID uuid.UUID, matches map[uuid.UUID]int) {
+	if repo == nil || len(matches) == 0 {
+		return
+	}
+	if err := repo.RecordRuleMatches(userID, matches, time.Now().UTC()); err != nil {
+		log.Printf("⚠️  Failed to record expense rule matches: %v", err)
+	}
+}
+
+// sameGoal reports whether two optional goal ids are equal
+func sameGoal(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// ruleResponse converts a rule model to its API representation
+func ruleResponse(rule models.ExpenseRule) response.RuleResponse {
+	var conditions []models.RuleCondition
+	_ = json.Unmarshal([]byte(rule.Conditions), &conditions)
+	resp := response.RuleResponse{
+		ID:            rule.ID,
+		UserID:        rule.UserID,
+		Name:          rule.Name,
+		Priority:      rule.Priority,
+		Enabled:       rule.Enabled,
+		Conditions:    make([]response.RuleConditionResponse, len(conditions)),
+		SetCategory:   rule.SetCategory,
+		SetGoalID:     rule.SetGoalID,
+		ClearGoal:     rule.ClearGoal,
+		MatchCount:    rule.MatchCount,
+		LastMatchedAt: rule.LastMatchedAt,
+		CreatedAt:     rule.CreatedAt,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+	for i, c := range conditions {
+		resp.Conditions[i] = response.RuleConditionResponse{Field: c.Field, Operator: c.Operator, Value: c.Value}
+	}
+	return resp
+}
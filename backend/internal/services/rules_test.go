@@ -0,0 +1,293 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestCreateExpenseAppliesRulesInPriorityOrder(t *testing.T) {
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	ruleRepo := repository.NewInMemoryRuleRepository()
+	finance := NewFinanceService(financeRepo, ruleRepo, nil)
+	rules := NewRulesService(ruleRepo, financeRepo, nil)
+	userID := uuid.New()
+
+	goal, err := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Japan trip", TargetAmount: 5000, IsMainGoal: true})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	uber, err := rules.CreateRule(userID, &request.CreateRuleRequest{
+		Name:        "Uber",
+		Priority:    1,
+		Conditions:  []request.RuleConditionRequest{{Field: "description", Operator: "contains", Value: "UBER"}},
+		SetCategory: strPtr("Transport"),
+		ClearGoal:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateRule uber: %v", err)
+	}
+	travel, err := rules.CreateRule(userID, &request.CreateRuleRequest{
+		Name:     "Big travel",
+		Priority: 2,
+		Conditions: []request.RuleConditionRequest{
+			{Field: "amount", Operator: "gt", Value: "1000"},
+			{Field: "category", Operator: "equals", Value: "travel"},
+		},
+		SetCategory: strPtr("Holiday"),
+		SetGoalID:   &goal.ID,
+	})
+	if err != nil {
+		t.Fatalf("CreateRule travel: %v", err)
+	}
+
+	ride, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "general", Description: "Uber to airport", Amount: 40, SpentAt: time.Now(), GoalID: &goal.ID})
+	if err != nil {
+		t.Fatalf("CreateExpense ride: %v", err)
+	}
+	if ride.Category != "Transport" || ride.GoalID != nil || len(ride.AppliedRules) != 1 || ride.AppliedRules[0] != uber.ID {
+		t.Fatalf("expected the uber rule to categorise the ride and clear its goal, got %+v", ride)
+	}
+
+	flight, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Travel", Description: "Flights", Amount: 1400, SpentAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateExpense flight: %v", err)
+	}
+	if flight.Category != "Holiday" || flight.GoalID == nil || *flight.GoalID != goal.ID {
+		t.Fatalf("expected the travel rule to link the flight to the goal, got %+v", flight)
+	}
+
+	kept, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "general", Description: "Uber eats", Amount: 20, SpentAt: time.Now(), SkipRules: true})
+	if err != nil {
+		t.Fatalf("CreateExpense skip rules: %v", err)
+	}
+	if kept.Category != "general" || len(kept.AppliedRules) != 0 {
+		t.Fatalf("expected skip_rules to keep the category, got %+v", kept)
+	}
+
+	stats, err := rules.GetRule(userID, travel.ID)
+	if err != nil {
+		t.Fatalf("GetRule: %v", err)
+	}
+	if stats.MatchCount != 1 || stats.LastMatchedAt == nil {
+		t.Fatalf("expected one recorded match, got %d", stats.MatchCount)
+	}
+}
+
+func TestApplyRulesDryRunThenApply(t *testing.T) {
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	ruleRepo := repository.NewInMemoryRuleRepository()
+	auditRepo := repository.NewInMemoryAuditRepository()
+	finance := NewFinanceService(financeRepo, ruleRepo, nil)
+	rules := NewRulesService(ruleRepo, financeRepo, auditRepo)
+	userID := uuid.New()
+
+	for _, description := range []string{"UBER trip", "uber eats", "Groceries"} {
+		if _, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "general", Description: description, Amount: 10, SpentAt: time.Now()}); err != nil {
+			t.Fatalf("CreateExpense: %v", err)
+		}
+	}
+
+	disabled := false
+	rule, err := rules.CreateRule(userID, &request.CreateRuleRequest{
+		Name:        "Uber",
+		Enabled:     &disabled,
+		Conditions:  []request.RuleConditionRequest{{Field: "description", Operator: "starts_with", Value: "uber"}},
+		SetCategory: strPtr("Transport"),
+	})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+
+	// Disabled rules only run when named explicitly
+	result, err := rules.ApplyRules(userID, &request.ApplyRulesRequest{DryRun: true})
+	if err != nil {
+		t.Fatalf("ApplyRules all: %v", err)
+	}
+	if result.Evaluated != 3 || result.Matched != 0 {
+		t.Fatalf("expected no enabled rules to match, got %+v", result)
+	}
+
+	preview, err := rules.ApplyRules(userID, &request.ApplyRulesRequest{DryRun: true, RuleIDs: []uuid.UUID{rule.ID}})
+	if err != nil {
+		t.Fatalf("ApplyRules dry run: %v", err)
+	}
+	if preview.Matched != 2 || preview.Changed != 2 || preview.Rules[0].Matches != 2 {
+		t.Fatalf("expected 2 previewed changes, got %+v", preview)
+	}
+	if expenses, _ := financeRepo.ListExpenses(userID, 0); expenses[0].Category != "general" {
+		t.Fatalf("dry run must not change expenses")
+	}
+
+	applied, err := rules.ApplyRules(userID, &request.ApplyRulesRequest{RuleIDs: []uuid.UUID{rule.ID}})
+	if err != nil {
+		t.Fatalf("ApplyRules: %v", err)
+	}
+	if applied.Changed != 2 {
+		t.Fatalf("expected 2 changes, got %+v", applied)
+	}
+	expenses, _ := financeRepo.ListExpenses(userID, 0)
+	transport := 0
+	for _, expense := range expenses {
+		if expense.Category == "Transport" {
+			transport++
+		}
+	}
+	if transport != 2 {
+		t.Fatalf("expected 2 expenses recategorised, got %d", transport)
+	}
+
+	stored, _ := rules.GetRule(userID, rule.ID)
+	if stored.MatchCount != 2 {
+		t.Fatalf("expected the applied run to count 2 matches, got %d", stored.MatchCount)
+	}
+	entries, _ := auditRepo.ListAuditEntries(userID, repository.AuditFilter{EntityType: EntityExpense})
+	if len(entries) != 2 {
+		t.Fatalf("expected an audit entry per changed expense, got %d", len(entries))
+	}
+
+	if _, err := rules.ApplyRules(userID, &request.ApplyRulesRequest{RuleIDs: []uuid.UUID{uuid.New()}}); err != errors.ErrRuleNotFound {
+		t.Fatalf("expected ErrRuleNotFound for an unknown rule, got %v", err)
+	}
+}
+
+// racingFinanceRepository runs afterList once expenses were listed, standing in for a
+// request that edits an expense while a bulk change is being worked out
+type racingFinanceRepository struct {
+	*repository.InMemoryFinanceRepository
+	afterList func()
+}
+
+func (r *racingFinanceRepository) ListExpenses(userID uuid.UUID, limit int) ([]models.Expense, error) {
+	expenses, err := r.InMemoryFinanceRepository.ListExpenses(userID, limit)
+	if r.afterList != nil {
+		r.afterList()
+		r.afterList = nil
+	}
+	return expenses, err
+}
+
+func TestApplyRulesSkipsExpensesEditedMeanwhile(t *testing.T) {
+	financeRepo := &racingFinanceRepository{InMemoryFinanceRepository: repository.NewInMemoryFinanceRepository()}
+	ruleRepo := repository.NewInMemoryRuleRepository()
+	finance := NewFinanceService(financeRepo, ruleRepo, nil)
+	rules := NewRulesService(ruleRepo, financeRepo, nil)
+	userID := uuid.New()
+
+	trip, _ := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "general", Description: "UBER trip", Amount: 10, SpentAt: time.Now()})
+	eats, _ := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "general", Description: "uber eats", Amount: 10, SpentAt: time.Now()})
+	if _, err := rules.CreateRule(userID, &request.CreateRuleRequest{
+		Name:        "Uber",
+		Conditions:  []request.RuleConditionRequest{{Field: "description", Operator: "starts_with", Value: "uber"}},
+		SetCategory: strPtr("Transport"),
+	}); err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+
+	financeRepo.afterList = func() {
+		if _, err := finance.UpdateExpense(userID, eats.ID, 0, &request.UpdateExpenseRequest{Category: strPtr("Eating out")}); err != nil {
+			t.Fatalf("UpdateExpense: %v", err)
+		}
+	}
+	applied, err := rules.ApplyRules(userID, &request.ApplyRulesRequest{})
+	if err != nil {
+		t.Fatalf("ApplyRules: %v", err)
+	}
+	if applied.Changed != 1 || applied.Skipped != 1 || applied.Changes[0].ExpenseID != trip.ID {
+		t.Fatalf("expected the edited expense to be skipped, got %+v", applied)
+	}
+	if kept, _ := finance.GetExpense(userID, eats.ID); kept.Category != "Eating out" {
+		t.Fatalf("expected the concurrent edit to survive, got %q", kept.Category)
+	}
+	if moved, _ := finance.GetExpense(userID, trip.ID); moved.Category != "Transport" {
+		t.Fatalf("expected the untouched expense to be recategorised, got %q", moved.Category)
+	}
+}
+
+func TestCreateRuleValidatesConditionsAndActions(t *testing.T) {
+	rules := NewRulesService(repository.NewInMemoryRuleRepository(), repository.NewInMemoryFinanceRepository(), nil)
+	userID := uuid.New()
+
+	cases := map[string]*request.CreateRuleRequest{
+		"unknown field":    {Name: "x", Conditions: []request.RuleConditionRequest{{Field: "merchant", Operator: "equals", Value: "a"}}, SetCategory: strPtr("a")},
+		"text operator":    {Name: "x", Conditions: []request.RuleConditionRequest{{Field: "amount", Operator: "contains", Value: "1"}}, SetCategory: strPtr("a")},
+		"amount value":     {Name: "x", Conditions: []request.RuleConditionRequest{{Field: "amount", Operator: "gt", Value: "lots"}}, SetCategory: strPtr("a")},
+		"no actions":       {Name: "x", Conditions: []request.RuleConditionRequest{{Field: "description", Operator: "equals", Value: "a"}}},
+		"set and clear":    {Name: "x", Conditions: []request.RuleConditionRequest{{Field: "description", Operator: "equals", Value: "a"}}, SetGoalID: &userID, ClearGoal: true},
+		"blank rule name":  {Name: " ", Conditions: []request.RuleConditionRequest{{Field: "description", Operator: "equals", Value: "a"}}, SetCategory: strPtr("a")},
+		"blank text value": {Name: "x", Conditions: []request.RuleConditionRequest{{Field: "description", Operator: "equals", Value: ""}}, SetCategory: strPtr("a")},
+	}
+	for name, req := range cases {
+		if _, err := rules.CreateRule(userID, req); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+
+	missingGoal := uuid.New()
+	_, err := rules.CreateRule(userID, &request.CreateRuleRequest{Name: "x", Conditions: []request.RuleConditionRequest{{Field: "description", Operator: "equals", Value: "a"}}, SetGoalID: &missingGoal})
+	if err != errors.ErrGoalNotFound {
+		t.Fatalf("expected ErrGoalNotFound, got %v", err)
+	}
+}
+
+func TestRulesLeaveInactiveGoalsAlone(t *testing.T) {
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	ruleRepo := repository.NewInMemoryRuleRepository()
+	finance := NewFinanceService(financeRepo, ruleRepo, nil)
+	rules := NewRulesService(ruleRepo, financeRepo, nil)
+	userID := uuid.New()
+
+	archived, _ := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Old car", TargetAmount: 3000, IsMainGoal: true})
+	trashed, _ := finance.CreateGoal(userID, &request.CreateGoalRequest{Name: "Boat", TargetAmount: 9000, IsMainGoal: true})
+	for name, goal := range map[string]uuid.UUID{"garage": archived.ID, "marina": trashed.ID} {
+		if _, err := rules.CreateRule(userID, &request.CreateRuleRequest{
+			Name:        name,
+			Conditions:  []request.RuleConditionRequest{{Field: "description", Operator: "contains", Value: name}},
+			SetCategory: strPtr("Vehicles"),
+			SetGoalID:   &goal,
+		}); err != nil {
+			t.Fatalf("CreateRule %s: %v", name, err)
+		}
+	}
+	before, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "general", Description: "garage repair", Amount: 80, SpentAt: time.Now(), SkipRules: true})
+	if err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	if _, err := finance.ChangeGoalStatus(userID, archived.ID, 0, &request.ChangeGoalStatusRequest{Status: "archived"}); err != nil {
+		t.Fatalf("ChangeGoalStatus: %v", err)
+	}
+	if err := finance.DeleteGoal(userID, trashed.ID); err != nil {
+		t.Fatalf("DeleteGoal: %v", err)
+	}
+
+	for _, description := range []string{"garage repair", "marina fees"} {
+		expense, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "general", Description: description, Amount: 50, SpentAt: time.Now()})
+		if err != nil {
+			t.Fatalf("CreateExpense %s: %v", description, err)
+		}
+		if expense.Category != "Vehicles" || expense.GoalID != nil {
+			t.Fatalf("expected %q to be categorised without a goal, got %+v", description, expense)
+		}
+	}
+
+	applied, err := rules.ApplyRules(userID, &request.ApplyRulesRequest{})
+	if err != nil {
+		t.Fatalf("ApplyRules: %v", err)
+	}
+	for _, change := range applied.Changes {
+		if change.After.GoalID != nil {
+			t.Fatalf("expected re-applied rules not to link %s to a goal, got %v", change.ExpenseID, *change.After.GoalID)
+		}
+	}
+	if expense, _ := financeRepo.GetExpense(before.ID, userID); expense.Category != "Vehicles" || expense.GoalID != nil {
+		t.Fatalf("expected the existing expense to be recategorised only, got %+v", expense)
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"finance-management/internal/classifier"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// suggestionModelMaxAge bounds how long a user's model is only updated
+// incrementally before it is rebuilt from their expenses, which also picks up
+// changes made outside the finance service (rule re-runs, rolled back batches)
+const suggestionModelMaxAge = 24 * time.Hour
+
+// maxSuggestionAlternatives is the number of runner-up categories returned with a suggestion
+const maxSuggestionAlternatives = 3
+
+// SuggestionService suggests expense categories with a naive Bayes model trained
+// per user on their own expenses. Models live in memory, are built on first use
+// and then follow expense changes reported through ObserveExpense.
+type SuggestionService struct {
+	financeRepo repository.FinanceRepositoryInterface
+
+	mu     sync.Mutex
+	models map[uuid.UUID]*categoryModel
+}
+
+// categoryModel is one user's trained classifier
+type categoryModel struct {
+	nb        *classifier.NaiveBayes
+	trainedAt time.Time
+}
+
+// NewSuggestionService creates a new suggestion service
+func NewSuggestionService(financeRepo repository.FinanceRepositoryInterface) *SuggestionService {
+	return &SuggestionService{
+		financeRepo: financeRepo,
+		models:      make(map[uuid.UUID]*categoryModel),
+	}
+}
+
+// SuggestCategory predicts the category of an expense from its description and,
+// when positive, its amount. An empty suggestion is returned until the user has
+// categorised expenses to learn from.
+func (s *SuggestionService) SuggestCategory(userID uuid.UUID, description string, amount float64) (*response.CategorySuggestionResponse, error) {
+	if strings.TrimSpace(description) == "" {
+		return nil, errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"Description is required",
+			"Pass the expense description to suggest a category for",
+		)
+	}
+	if amount < 0 {
+		return nil, errors.ErrInvalidAmount
+	}
+
+	features := classifier.Features(description, amount)
+
+	s.mu.Lock()
+	model, ok := s.models[userID]
+	stale := !ok || time.Since(model.trainedAt) >= suggestionModelMaxAge
+	s.mu.Unlock()
+	if stale {
+		if err := s.train(userID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	model = s.models[userID]
+	predictions := model.nb.Predict(features)
+	trainedOn := model.nb.Documents()
+	s.mu.Unlock()
+
+	resp := &response.CategorySuggestionResponse{TrainedOn: trainedOn, Alternatives: []response.CategoryScore{}}
+	if len(predictions) == 0 {
+		return resp, nil
+	}
+	resp.Category = predictions[0].Category
+	resp.Confidence = predictions[0].Probability
+	for _, p := range predictions[1:] {
+		if len(resp.Alternatives) == maxSuggestionAlternatives {
+			break
+		}
+		resp.Alternatives = append(resp.Alternatives, response.CategoryScore{Category: p.Category, Confidence: p.Probability})
+	}
+	return resp, nil
+}
+
+// ObserveExpense updates a user's model after an expense changed: before is the
+// expense as it was (nil when created) and after as it is now (nil when deleted).
+// Users without a model are skipped; theirs is built from scratch on first use.
+func (s *SuggestionService) ObserveExpense(userID uuid.UUID, before, after *models.Expense) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	model, ok := s.models[userID]
+	if !ok {
+		return
+	}
+	if before != nil {
+		model.nb.Forget(before.Category, classifier.Features(before.Description, before.Amount))
+	}
+	if after != nil {
+		model.nb.Learn(after.Category, classifier.Features(after.Description, after.Amount))
+	}
+}
+
+// Invalidate drops a user's model after a change that recategorised their expenses
+// in bulk, such as a category merge or rename. It is rebuilt on first use.
+func (s *SuggestionService) Invalidate(userID uuid.UUID) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	delete(s.models, userID)
+	s.mu.Unlock()
+}
+
+// train builds a user's model from all of their expenses and caches it
+func (s *SuggestionService) train(userID uuid.UUID) error {
+	expenses, err := s.financeRepo.ListExpenses(userID, 0)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to load expenses for category suggestions")
+	}
+
+	nb := classifier.New()
+	for _, expense := range expenses {
+		nb.Learn(expense.Category, classifier.Features(expense.Description, expense.Amount))
+	}
+
+	s.mu.Lock()
+	s.models[userID] = &categoryModel{nb: nb, trainedAt: time.Now()}
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestCategoryRenamesAndMergesReachSuggestions(t *testing.T) {
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	suggestions := NewSuggestionService(financeRepo)
+	finance := NewFinanceService(financeRepo, nil, nil).WithSuggestions(suggestions)
+	userID := uuid.New()
+
+	for _, description := range []string{"Tesco weekly shop", "Tesco metro"} {
+		if _, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Groceries", Description: description, Amount: 30, SpentAt: time.Now()}); err != nil {
+			t.Fatalf("CreateExpense: %v", err)
+		}
+	}
+	cafe, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "Coffee", Description: "Pret coffee", Amount: 4, SpentAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	suggestCategory := func(description string) string {
+		t.Helper()
+		suggestion, err := suggestions.SuggestCategory(userID, description, 0)
+		if err != nil {
+			t.Fatalf("SuggestCategory: %v", err)
+		}
+		return suggestion.Category
+	}
+	if got := suggestCategory("Tesco"); got != "Groceries" {
+		t.Fatalf("expected Groceries before the rename, got %q", got)
+	}
+
+	categories, _ := finance.ListCategories(userID)
+	var groceriesID uuid.UUID
+	for _, c := range categories {
+		if c.Name == "Groceries" {
+			groceriesID = c.ID
+		}
+	}
+	if _, err := finance.UpdateCategory(userID, groceriesID, &request.UpdateCategoryRequest{Name: strPtr("Supermarket")}); err != nil {
+		t.Fatalf("UpdateCategory: %v", err)
+	}
+	if got := suggestCategory("Tesco"); got != "Supermarket" {
+		t.Fatalf("expected the renamed category to be suggested, got %q", got)
+	}
+
+	if _, err := finance.MergeCategories(userID, groceriesID, &request.MergeCategoriesRequest{SourceIDs: []uuid.UUID{*cafe.CategoryID}}); err != nil {
+		t.Fatalf("MergeCategories: %v", err)
+	}
+	if got := suggestCategory("Pret coffee"); got != "Supermarket" {
+		t.Fatalf("expected the merged category to be suggested, got %q", got)
+	}
+}
+
+func TestSuggestCategoryLearnsIncrementally(t *testing.T) {
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	suggestions := NewSuggestionService(financeRepo)
+	finance := NewFinanceService(financeRepo, nil, nil).WithSuggestions(suggestions)
+	userID := uuid.New()
+
+	empty, err := suggestions.SuggestCategory(userID, "Uber to work", 15)
+	if err != nil {
+		t.Fatalf("SuggestCategory without history: %v", err)
+	}
+	if empty.Category != "" || empty.TrainedOn != 0 {
+		t.Fatalf("expected no suggestion without history, got %+v", empty)
+	}
+
+	history := []request.CreateExpenseRequest{
+		{Category: "Transport", Description: "Uber trip home", Amount: 18},
+		{Category: "Transport", Description: "Uber airport", Amount: 42},
+		{Category: "Groceries", Description: "Farmers market", Amount: 35},
+	}
+	for i := range history {
+		history[i].SpentAt = time.Now()
+		if _, err := finance.CreateExpense(userID, &history[i]); err != nil {
+			t.Fatalf("CreateExpense: %v", err)
+		}
+	}
+
+	// Expenses created after the model was built are learned without retraining
+	suggestion, err := suggestions.SuggestCategory(userID, "UBER ride", 20)
+	if err != nil {
+		t.Fatalf("SuggestCategory: %v", err)
+	}
+	if suggestion.Category != "Transport" || suggestion.TrainedOn != 3 || suggestion.Confidence <= 0.5 {
+		t.Fatalf("expected a confident Transport suggestion from 3 expenses, got %+v", suggestion)
+	}
+	if len(suggestion.Alternatives) != 1 || suggestion.Alternatives[0].Category != "Groceries" {
+		t.Fatalf("expected Groceries as the alternative, got %+v", suggestion.Alternatives)
+	}
+
+	expenses, _ := financeRepo.ListExpenses(userID, 0)
+	for _, expense := range expenses {
+		if expense.Category != "Transport" {
+			continue
+		}
+		category := "Taxi"
+		if _, err := finance.UpdateExpense(userID, expense.ID, 0, &request.UpdateExpenseRequest{Category: &category}); err != nil {
+			t.Fatalf("UpdateExpense: %v", err)
+		}
+	}
+	suggestion, err = suggestions.SuggestCategory(userID, "uber", 0)
+	if err != nil {
+		t.Fatalf("SuggestCategory after recategorising: %v", err)
+	}
+	if suggestion.Category != "Taxi" || suggestion.TrainedOn != 3 {
+		t.Fatalf("expected the model to follow the recategorised expenses, got %+v", suggestion)
+	}
+
+	if _, err := suggestions.SuggestCategory(userID, "  ", 0); err == nil {
+		t.Fatalf("expected an error for an empty description")
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/repository"
+	"finance-management/internal/validation"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BulkTag adds and removes tags on the given incomes and expenses in one
+// transaction. Transactions whose tags would not change are left untouched.
+func (s *FinanceService) BulkTag(userID uuid.UUID, req *request.BulkTagRequest) (*response.BulkTagResponse, error) {
+	if err := validation.ValidateBatchSize(len(req.IncomeIDs) + len(req.ExpenseIDs)); err != nil {
+		return nil, err
+	}
+	add, remove := normalizeTags(req.Add), normalizeTags(req.Remove)
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"No tags to change",
+			"At least one tag to add or remove is required",
+		)
+	}
+	for _, tags := range [][]string{add, remove} {
+		if err := validation.ValidateTags(tags); err != nil {
+			return nil, err
+		}
+	}
+
+	// Tags are worked out from the rows as read in the transaction and written with their
+	// version, so tags another request changed meanwhile are kept rather than overwritten.
+	// An unknown id rolls back the whole request.
+	resp := &response.BulkTagResponse{}
+	err := s.audit.transact(s.financeRepo, func(repo repository.FinanceRepositoryInterface, audit auditor) error {
+		for _, id := range req.IncomeIDs {
+			income, err := repo.GetIncome(id, userID)
+			if err != nil {
+				return lookupError(err, errors.ErrIncomeNotFound, "Failed to get income")
+			}
+			retagged := retag(income.Tags, add, remove)
+			if sameTags(income.Tags, retagged) {
+				continue
+			}
+			if err := validation.ValidateTags(retagged); err != nil {
+				return err
+			}
+			updates := map[string]interface{}{"tags": retagged}
+			if err := repo.UpdateIncome(id, userID, income.Version, updates); err != nil {
+				return err
+			}
+			audit.record(userID, EntityIncome, id, AuditUpdate, updatedChanges(income, updates))
+			resp.UpdatedIncomes++
+		}
+		for _, id := range req.ExpenseIDs {
+			expense, err := repo.GetExpense(id, userID)
+			if err != nil {
+				return lookupError(err, errors.ErrExpenseNotFound, "Failed to get expense")
+			}
+			retagged := retag(expense.Tags, add, remove)
+			if sameTags(expense.Tags, retagged) {
+				continue
+			}
+			if err := validation.ValidateTags(retagged); err != nil {
+				return err
+			}
+			updates := map[string]interface{}{"tags": retagged}
+			if err := repo.UpdateExpense(id, userID, expense.Version, updates); err != nil {
+				return err
+			}
+			audit.record(userID, EntityExpense, id, AuditUpdate, updatedChanges(expense, updates))
+			resp.UpdatedExpenses++
+		}
+		return nil
+	})
+	if err != nil {
+		var appErr *errors.AppError
+		switch {
+		case stderrors.As(err, &appErr):
+			return nil, appErr
+		case stderrors.Is(err, repository.ErrVersionConflict):
+			return nil, errors.NewWithDetails(http.StatusConflict, "Tags changed while they were being updated", "Retry the request")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to update tags")
+	}
+
+	return resp, nil
+}
+
+// normalizeTags trims and lower-cases tags and drops repeats, keeping the first
+// occurrence's position. The result is never nil so it can be stored as is.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// tagUpdate normalises and validates the tags of an update request
+func tagUpdate(tags []string) (pq.StringArray, error) {
+	normalized := normalizeTags(tags)
+	if err := validation.ValidateTags(normalized); err != nil {
+		return nil, err
+	}
+	return pq.StringArray(normalized), nil
+}
+
+// retag returns tags with remove taken out and add appended
+func retag(tags pq.StringArray, add, remove []string) pq.StringArray {
+	removed := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removed[tag] = true
+	}
+	retagged := pq.StringArray{}
+	for _, tag := range tags {
+		if !removed[tag] {
+			retagged = append(retagged, tag)
+		}
+	}
+	return pq.StringArray(normalizeTags(append(retagged, add...)))
+}
+
+// sameTags reports whether two tag lists hold the same tags in the same order
+func sameTags(a, b pq.StringArray) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// responseTags returns tags for an API response, where they are never null
+func responseTags(tags pq.StringArray) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestTagsAreNormalisedAndFilterLedger(t *testing.T) {
+	finance := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, nil)
+	userID := uuid.New()
+	day := time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+
+	flight, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "travel", Amount: 400, SpentAt: day, Tags: []string{" Vacation ", "reimbursable", "vacation"}})
+	if err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	if len(flight.Tags) != 2 || flight.Tags[0] != "vacation" || flight.Tags[1] != "reimbursable" {
+		t.Fatalf("expected trimmed, lower-cased and deduplicated tags, got %v", flight.Tags)
+	}
+	lunch, _ := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "food", Amount: 20, SpentAt: day})
+	if lunch.Tags == nil {
+		t.Fatal("expected untagged expenses to report an empty tag list")
+	}
+	if _, err := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "food", Amount: 5, SpentAt: day, Tags: []string{" "}}); err == nil {
+		t.Fatal("expected a blank tag to be rejected")
+	}
+
+	tagged, err := finance.ListExpenses(userID, 0, repository.TagFilter{Tags: []string{"VACATION", "reimbursable"}})
+	if err != nil {
+		t.Fatalf("ListExpenses: %v", err)
+	}
+	if len(tagged) != 1 || tagged[0].ID != flight.ID {
+		t.Fatalf("expected only the flight to carry both tags, got %+v", tagged)
+	}
+
+	cleared, err := finance.UpdateExpense(userID, flight.ID, 0, &request.UpdateExpenseRequest{Tags: &[]string{}})
+	if err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	if len(cleared.Tags) != 0 {
+		t.Fatalf("expected an empty list to clear the tags, got %v", cleared.Tags)
+	}
+}
+
+func TestBulkTagAddsAndRemovesTags(t *testing.T) {
+	finance := NewFinanceService(repository.NewInMemoryFinanceRepository(), nil, nil)
+	userID := uuid.New()
+	day := time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+
+	salary, _ := finance.CreateIncome(userID, &request.CreateIncomeRequest{Source: "salary", Amount: 3000, ReceivedAt: day})
+	hotel, _ := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "travel", Amount: 250, SpentAt: day, Tags: []string{"pending"}})
+	taxi, _ := finance.CreateExpense(userID, &request.CreateExpenseRequest{Category: "travel", Amount: 30, SpentAt: day, Tags: []string{"trip"}})
+
+	if _, err := finance.BulkTag(userID, &request.BulkTagRequest{ExpenseIDs: []uuid.UUID{hotel.ID, uuid.New()}, Add: []string{"trip"}}); err != errors.ErrExpenseNotFound {
+		t.Fatalf("expected ErrExpenseNotFound for an unknown expense, got %v", err)
+	}
+	if current, _ := finance.GetExpense(userID, hotel.ID); len(current.Tags) != 1 {
+		t.Fatalf("expected a failed bulk edit to change nothing, got %v", current.Tags)
+	}
+
+	result, err := finance.BulkTag(userID, &request.BulkTagRequest{
+		IncomeIDs:  []uuid.UUID{salary.ID},
+		ExpenseIDs: []uuid.UUID{hotel.ID, taxi.ID},
+		Add:        []string{"Trip"},
+		Remove:     []string{"pending"},
+	})
+	if err != nil {
+		t.Fatalf("BulkTag: %v", err)
+	}
+	if result.UpdatedIncomes != 1 || result.UpdatedExpenses != 1 {
+		t.Fatalf("expected the already tagged taxi to be skipped, got %+v", result)
+	}
+	if current, _ := finance.GetExpense(userID, hotel.ID); len(current.Tags) != 1 || current.Tags[0] != "trip" {
+		t.Fatalf("expected the hotel to swap pending for trip, got %v", current.Tags)
+	}
+
+	summary, err := finance.GetMonthlySummary(userID, 2024, 5)
+	if err != nil {
+		t.Fatalf("GetMonthlySummary: %v", err)
+	}
+	if summary.TagSpending["trip"] != 280 || summary.TagIncome["trip"] != 3000 {
+		t.Fatalf("expected per-tag totals for trip, got %v and %v", summary.TagSpending, summary.TagIncome)
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	_ "image/png" // registers the PNG decoder
+)
+
+const (
+	// thumbnailSize bounds the width and height of attachment thumbnails
+	thumbnailSize = 256
+	// maxThumbnailPixels keeps decoding an uploaded image within a sane amount of memory
+	maxThumbnailPixels = 40_000_000
+)
+
+// thumbnailable lists the image types a thumbnail can be decoded from
+var thumbnailable = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// makeThumbnail scales an image down to fit a thumbnailSize square and encodes it
+// as JPEG. Transparent areas are flattened onto white.
+func makeThumbnail(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxThumbnailPixels {
+		return nil, fmt.Errorf("image of %dx%d pixels is too large for a thumbnail", cfg.Width, cfg.Height)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scaleToFit(src, thumbnailSize), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleToFit shrinks src, keeping its aspect ratio, so neither side exceeds size.
+// Each target pixel averages the block of source pixels it covers.
+func scaleToFit(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for ty := 0; ty < th; ty++ {
+		y0, y1 := b.Min.Y+ty*h/th, b.Min.Y+(ty+1)*h/th
+		for tx := 0; tx < tw; tx++ {
+			x0, x1 := b.Min.X+tx*w/tw, b.Min.X+(tx+1)*w/tw
+			var r, g, bl, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := src.At(x, y).RGBA()
+					r, g, bl, a = r+uint64(pr), g+uint64(pg), bl+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			// Colours are alpha-premultiplied, so adding the missing coverage composites onto white
+			white := 0xffff - a/n
+			i := dst.PixOffset(tx, ty)
+			dst.Pix[i+0] = uint8((r/n + white) >> 8)
+			dst.Pix[i+1] = uint8((g/n + white) >> 8)
+			dst.Pix[i+2] = uint8((bl/n + white) >> 8)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
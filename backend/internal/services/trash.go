@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"finance-management/internal/dto/response"
+	"finance-management/internal/errors"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TrashService lists soft-deleted records and purges them once the retention window has passed
+type TrashService struct {
+	financeRepo repository.FinanceRepositoryInterface
+	notesRepo   repository.NotesRepositoryInterface
+	retention   time.Duration
+	attachments *AttachmentService
+}
+
+// NewTrashService creates a new trash service
+func NewTrashService(financeRepo repository.FinanceRepositoryInterface, notesRepo repository.NotesRepositoryInterface, retention time.Duration) *TrashService {
+	return &TrashService{
+		financeRepo: financeRepo,
+		notesRepo:   notesRepo,
+		retention:   retention,
+	}
+}
+
+// WithAttachments returns a copy of the service that also removes the
+// attachments of purged records, along with their files
+func (s *TrashService) WithAttachments(attachments *AttachmentService) *TrashService {
+	c := *s
+	c.attachments = attachments
+	return &c
+}
+
+// ListTrash retrieves everything the user has deleted that has not been purged yet
+func (s *TrashService) ListTrash(userID uuid.UUID) (*response.TrashResponse, error) {
+	trash, err := s.financeRepo.ListTrash(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list trash")
+	}
+	notes, err := s.notesRepo.ListDeletedNotes(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to list trash")
+	}
+
+	resp := &response.TrashResponse{
+		RetentionDays: int(s.retention / (24 * time.Hour)),
+		Incomes:       make([]response.TrashedIncomeResponse, len(trash.Incomes)),
+		Expenses:      make([]response.TrashedExpenseResponse, len(trash.Expenses)),
+		Goals:         make([]response.TrashedGoalResponse, len(trash.Goals)),
+		Notes:         make([]response.TrashedNoteResponse, len(notes)),
+	}
+	for i, income := range trash.Incomes {
+		resp.Incomes[i] = response.TrashedIncomeResponse{
+			IncomeResponse: incomeResponse(income),
+			TrashInfo:      s.trashInfo(income.DeletedAt.Time),
+		}
+	}
+	for i, expense := range trash.Expenses {
+		resp.Expenses[i] = response.TrashedExpenseResponse{
+			ExpenseResponse: expenseResponse(expense),
+			TrashInfo:       s.trashInfo(expense.DeletedAt.Time),
+		}
+	}
+	for i, goal := range trash.Goals {
+		resp.Goals[i] = response.TrashedGoalResponse{
+			GoalResponse: goalResponse(goal),
+			TrashInfo:    s.trashInfo(goal.DeletedAt.Time),
+		}
+	}
+	for i, note := range notes {
+		resp.Notes[i] = response.TrashedNoteResponse{
+			NoteResponse: noteResponse(note),
+			TrashInfo:    s.trashInfo(note.DeletedAt.Time),
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *TrashService) trashInfo(deletedAt time.Time) response.TrashInfo {
+	return response.TrashInfo{DeletedAt: deletedAt, PurgeAt: deletedAt.Add(s.retention)}
+}
+
+// Purge permanently removes records of every user that have been in the trash
+// longer than the retention window, together with their attachments, and returns
+// how many were removed
+func (s *TrashService) Purge(now time.Time) (int64, error) {
+	cutoff := now.Add(-s.retention)
+	finance, err := s.financeRepo.PurgeDeleted(cutoff)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to purge trash")
+	}
+	notes, err := s.notesRepo.PurgeDeletedNotes(cutoff)
+	if err != nil {
+		return finance, errors.Wrap(err, errors.ErrDatabaseError.Code, "Failed to purge trash")
+	}
+	if s.attachments == nil {
+		return finance + notes, nil
+	}
+	attachments, err := s.attachments.PurgeOrphans(context.Background())
+	return finance + notes + attachments, err
+}
+
+// RunPurger runs Purge immediately and then every interval until ctx is cancelled
+func (s *TrashService) RunPurger(ctx context.Context, interval time.Duration) {
+	runEvery(ctx, interval, func() {
+		if purged, err := s.Purge(time.Now().UTC()); err != nil {
+			log.Printf("⚠️  Trash purge failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("🗑️  Purged %d record(s) from the trash", purged)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"finance-management/internal/dto/request"
+	"finance-management/internal/errors"
+	"finance-management/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestTrashListsAndPurgesAfterRetention(t *testing.T) {
+	financeRepo := repository.NewInMemoryFinanceRepository()
+	notesRepo := repository.NewInMemoryNotesRepository()
+	finance := NewFinanceService(financeRepo, nil, nil)
+	trash := NewTrashService(financeRepo, notesRepo, 30*24*time.Hour)
+	userID := uuid.New()
+
+	income, err := finance.CreateIncome(userID, &request.CreateIncomeRequest{Source: "salary", Amount: 100, ReceivedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateIncome: %v", err)
+	}
+	if err := finance.DeleteIncome(userID, income.ID); err != nil {
+		t.Fatalf("DeleteIncome: %v", err)
+	}
+
+	listed, err := trash.ListTrash(userID)
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if listed.RetentionDays != 30 || len(listed.Incomes) != 1 {
+		t.Fatalf("expected one trashed income with 30 day retention, got %+v", listed)
+	}
+	if got := listed.Incomes[0].PurgeAt.Sub(listed.Incomes[0].DeletedAt); got != 30*24*time.Hour {
+		t.Fatalf("purge_at should be deleted_at plus retention, got %s", got)
+	}
+
+	if purged, _ := trash.Purge(time.Now().Add(29 * 24 * time.Hour)); purged != 0 {
+		t.Fatalf("nothing should be purged inside the retention window, purged %d", purged)
+	}
+	if purged, _ := trash.Purge(time.Now().Add(31 * 24 * time.Hour)); purged != 1 {
+		t.Fatalf("expected the income to be purged, purged %d", purged)
+	}
+
+	err = finance.RestoreIncome(userID, income.ID)
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != http.StatusNotFound {
+		t.Fatalf("restoring a purged income should be a 404, got %v", err)
+	}
+}
+
+func TestRestoreSubgoalOfTrashedParentConflicts(t *testing.T) {
+	svc := newTestFinanceService()
+	userID := uuid.New()
+	parent, err := svc.CreateGoal(userID, &request.CreateGoalRequest{Name: "Trip", TargetAmount: 1000, IsMainGoal: true})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	child, err := svc.CreateGoal(userID, &request.CreateGoalRequest{Name: "Flights", TargetAmount: 400, ParentGoalID: &parent.ID})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	if err := svc.DeleteGoal(userID, parent.ID); err != nil {
+		t.Fatalf("DeleteGoal: %v", err)
+	}
+
+	if err := svc.RestoreGoal(userID, child.ID); err != errors.ErrParentGoalDeleted {
+		t.Fatalf("expected ErrParentGoalDeleted, got %v", err)
+	}
+	if err := svc.RestoreGoal(userID, parent.ID); err != nil {
+		t.Fatalf("RestoreGoal: %v", err)
+	}
+	goals, _ := svc.ListGoalsWithProgress(userID, 0, nil, time.Now().UTC())
+	if len(goals) != 2 {
+		t.Fatalf("restoring the parent should restore its sub-goal, got %d goals", len(goals))
+	}
+}
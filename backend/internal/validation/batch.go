@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"fmt"
+
+	"finance-management/internal/errors"
+
+	"github.com/google/uuid"
+)
+
+// MaxBatchOperations limits how many operations one batch request may contain
+const MaxBatchOperations = 500
+
+// ValidateBatchSize validates the number of operations in a batch request
+func ValidateBatchSize(count int) error {
+	if count == 0 {
+		return errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"Batch is empty",
+			"At least one operation is required",
+		)
+	}
+
+	if count > MaxBatchOperations {
+		return errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Batch too large",
+			fmt.Sprintf("A batch may contain at most %d operations", MaxBatchOperations),
+		)
+	}
+
+	return nil
+}
+
+// ValidateBatchTarget validates that update and delete operations name the row they change
+func ValidateBatchTarget(op string, id *uuid.UUID) error {
+	if op == "create" {
+		if id != nil {
+			return errors.NewWithDetails(
+				errors.ErrInvalidInput.Code,
+				"Unexpected id",
+				"Create operations must not specify an id",
+			)
+		}
+		return nil
+	}
+
+	if id == nil || *id == uuid.Nil {
+		return errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"Operation id is required",
+			fmt.Sprintf("%s operations must specify the id of the row to change", op),
+		)
+	}
+
+	return nil
+}
+
+// ValidateBatchVersion validates that an update of a versioned row carries the version
+// it was based on, as a single update must send an If-Match header
+func ValidateBatchVersion(op string, version int) error {
+	if op == "update" && version < 1 {
+		return errors.NewWithDetails(
+			errors.ErrPreconditionRequired.Code,
+			"Operation version is required",
+			"Update operations must specify the version of the row they change",
+		)
+	}
+	return nil
+}
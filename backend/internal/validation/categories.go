@@ -0,0 +1,28 @@
+package validation
+
+import (
+	"strings"
+
+	"finance-management/internal/errors"
+)
+
+// ValidateCategoryName validates expense category name
+func ValidateCategoryName(name string) error {
+	if len(strings.TrimSpace(name)) == 0 {
+		return errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"Category name is required",
+			"Category name cannot be empty",
+		)
+	}
+
+	if len(name) > 100 {
+		return errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Category name too long",
+			"Category name must be 100 characters or less",
+		)
+	}
+
+	return nil
+}
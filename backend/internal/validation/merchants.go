@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"strings"
+
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+)
+
+// ValidateMerchantName validates merchant name
+func ValidateMerchantName(name string) error {
+	if len(strings.TrimSpace(name)) == 0 {
+		return errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"Merchant name is required",
+			"Merchant name cannot be empty",
+		)
+	}
+
+	if len(name) > 200 {
+		return errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Merchant name too long",
+			"Merchant name must be 200 characters or less",
+		)
+	}
+
+	return nil
+}
+
+// ValidateMerchantPattern validates a normalised merchant pattern and its match type
+func ValidateMerchantPattern(pattern, matchType string) error {
+	if len(pattern) == 0 {
+		return errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Invalid merchant pattern",
+			"Pattern must contain at least one word once digits and punctuation are removed",
+		)
+	}
+
+	if len(pattern) > 200 {
+		return errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Merchant pattern too long",
+			"Merchant pattern must be 200 characters or less",
+		)
+	}
+
+	switch matchType {
+	case models.MatchExact, models.MatchPrefix, models.MatchContains:
+		return nil
+	}
+	return errors.NewWithDetails(
+		errors.ErrInvalidInput.Code,
+		"Invalid match type",
+		"Match type must be exact, prefix or contains",
+	)
+}
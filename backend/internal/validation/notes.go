@@ -51,7 +51,7 @@ func ValidateNoteCategory(category string) error {
 	return nil
 }
 
-// ValidateTags validates note tags
+// ValidateTags validates the tags of a note, income or expense
 func ValidateTags(tags []string) error {
 	if len(tags) > 20 {
 		return errors.NewWithDetails(
@@ -0,0 +1,117 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"finance-management/internal/errors"
+	"finance-management/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ruleOperators lists the operators each condition field supports
+var ruleOperators = map[string][]string{
+	models.RuleFieldDescription: {models.RuleOpContains, models.RuleOpEquals, models.RuleOpStartsWith, models.RuleOpEndsWith},
+	models.RuleFieldCategory:    {models.RuleOpContains, models.RuleOpEquals, models.RuleOpStartsWith, models.RuleOpEndsWith},
+	models.RuleFieldAmount:      {models.RuleOpEquals, models.RuleOpGreater, models.RuleOpGreaterEq, models.RuleOpLess, models.RuleOpLessEq},
+}
+
+// ValidateRuleName validates expense rule name
+func ValidateRuleName(name string) error {
+	if len(strings.TrimSpace(name)) == 0 {
+		return errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"Rule name is required",
+			"Rule name cannot be empty",
+		)
+	}
+
+	if len(name) > 200 {
+		return errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Rule name too long",
+			"Rule name must be 200 characters or less",
+		)
+	}
+
+	return nil
+}
+
+// ValidateRuleCondition validates one expense rule condition
+func ValidateRuleCondition(condition models.RuleCondition) error {
+	operators, ok := ruleOperators[condition.Field]
+	if !ok {
+		return errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Unknown rule field",
+			fmt.Sprintf("Field %q is not supported; use description, category or amount", condition.Field),
+		)
+	}
+
+	supported := false
+	for _, op := range operators {
+		if op == condition.Operator {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Unsupported rule operator",
+			fmt.Sprintf("Field %q supports %s", condition.Field, strings.Join(operators, ", ")),
+		)
+	}
+
+	if condition.Field == models.RuleFieldAmount {
+		if _, err := strconv.ParseFloat(condition.Value, 64); err != nil {
+			return errors.NewWithDetails(
+				errors.ErrInvalidInput.Code,
+				"Invalid rule value",
+				fmt.Sprintf("Amount conditions need a number, got %q", condition.Value),
+			)
+		}
+		return nil
+	}
+
+	if len(strings.TrimSpace(condition.Value)) == 0 {
+		return errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"Rule value is required",
+			fmt.Sprintf("Conditions on %s need a value to compare with", condition.Field),
+		)
+	}
+
+	return nil
+}
+
+// ValidateRuleActions validates that a rule changes something and does not both set and clear the goal
+func ValidateRuleActions(setCategory *string, setGoalID *uuid.UUID, clearGoal bool) error {
+	if setCategory == nil && setGoalID == nil && !clearGoal {
+		return errors.NewWithDetails(
+			errors.ErrMissingField.Code,
+			"Rule has no actions",
+			"Set a category, a goal, or clear the goal",
+		)
+	}
+
+	if setCategory != nil && len(strings.TrimSpace(*setCategory)) == 0 {
+		return errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Invalid rule category",
+			"The category to set cannot be empty",
+		)
+	}
+
+	if setGoalID != nil && clearGoal {
+		return errors.NewWithDetails(
+			errors.ErrInvalidInput.Code,
+			"Conflicting rule actions",
+			"A rule cannot both link a goal and clear it",
+		)
+	}
+
+	return nil
+}
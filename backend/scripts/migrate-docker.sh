@@ -39,11 +39,7 @@ run_migrations() {
 # Function to check migration status
 check_migrations() {
     echo "📊 Checking migration status..."
-    docker-compose exec -T postgres psql -U finance_user -d finance_management -c "
-        SELECT version, applied_at 
-        FROM app_schema_migrations 
-        ORDER BY applied_at DESC;
-    "
+    docker-compose run --rm migrate ./migrate status
 }
 
 # Main execution
@@ -56,6 +52,10 @@ case "${1:-migrate}" in
         wait_for_db
         check_migrations
         ;;
+    "down")
+        wait_for_db
+        docker-compose run --rm migrate ./migrate down "${2:-1}"
+        ;;
     "reset")
         echo "⚠️  Resetting database (this will delete all data!)"
         read -p "Are you sure? (y/N): " -n 1 -r
@@ -70,9 +70,10 @@ case "${1:-migrate}" in
         fi
         ;;
     "help")
-        echo "Usage: $0 [migrate|status|reset|help]"
+        echo "Usage: $0 [migrate|status|down [N]|reset|help]"
         echo "  migrate - Run pending migrations (default)"
         echo "  status  - Show migration status"
+        echo "  down    - Roll back the last N migrations (default 1)"
         echo "  reset   - Reset database and run all migrations"
         echo "  help    - Show this help message"
         ;;
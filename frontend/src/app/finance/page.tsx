@@ -153,12 +153,17 @@ export default function FinancePage() {
   };
 
   const updateIncome = async (row: any) => {
-    await financeApi.updateIncome(row.id, { 
+    const res = await financeApi.updateIncome(row.id, { 
       amount: row.amount, 
       source: row.source, 
       received_at: row.received_at 
-    });
+    }, row.version);
     setEditingIncome(null);
+    await loadLists();
+    if (!res.success) {
+      showError('Failed to update income', res.error?.status === 412 ? 'It was changed elsewhere; the latest values are shown' : 'Please try again');
+      return;
+    }
     showSuccess('Income updated');
   };
 
@@ -168,13 +173,18 @@ export default function FinancePage() {
   };
 
   const updateExpense = async (row: any) => {
-    await financeApi.updateExpense(row.id, { 
+    const res = await financeApi.updateExpense(row.id, { 
       amount: row.amount, 
       category: row.category, 
       spent_at: row.spent_at, 
       description: row.description 
-    });
+    }, row.version);
     setEditingExpense(null);
+    await loadLists();
+    if (!res.success) {
+      showError('Failed to update expense', res.error?.status === 412 ? 'It was changed elsewhere; the latest values are shown' : 'Please try again');
+      return;
+    }
     showSuccess('Expense updated');
   };
 
@@ -24,7 +24,7 @@ export default function NoteEditorPage() {
   const [hasChanges, setHasChanges] = useState(false);
 
   const { data: noteData, loading: isLoading, execute: loadNote } = useApi<Note>();
-  const { loading: isSaving, execute: saveNote } = useApi({
+  const { data: savedNote, loading: isSaving, execute: saveNote } = useApi<Note>({
     successMessage: 'Note updated successfully',
     showErrorMessage: true,
   });
@@ -52,6 +52,13 @@ export default function NoteEditorPage() {
     }
   }, [noteData]);
 
+  // Keep the latest version after a save so the next one is not rejected as stale
+  useEffect(() => {
+    if (savedNote) {
+      setNote(savedNote);
+    }
+  }, [savedNote]);
+
   // Track changes
   useEffect(() => {
     if (note) {
@@ -77,7 +84,7 @@ export default function NoteEditorPage() {
       category,
       tags,
       is_favorite,
-    }));
+    }, note?.version));
     
     setHasChanges(false);
   };
@@ -47,8 +47,8 @@ export const useGoals = () => {
     }
   };
 
-  const updateGoal = async (id: string, updates: Partial<GoalPayload>) => {
-    const res = await goalsApi.updateGoal(id, updates);
+  const updateGoal = async (id: string, updates: Partial<GoalPayload>, version?: number) => {
+    const res = await goalsApi.updateGoal(id, updates, version);
     if (res.success) {
       showSuccess('Goal updated successfully');
       await loadGoals();
@@ -0,0 +1,40 @@
+import { apiClient, apiRequest } from './client';
+
+export type AttachmentOwner = 'expense' | 'income' | 'note';
+
+export interface Attachment {
+  id: string;
+  owner_type: AttachmentOwner;
+  owner_id: string;
+  file_name: string;
+  // Sniffed from the file's content by the server
+  content_type: string;
+  size: number;
+  has_thumbnail: boolean;
+  created_at: string;
+}
+
+const API_BASE_URL = process.env['NEXT_PUBLIC_API_URL'] || 'http://localhost:8080';
+
+const ownerPath = (owner: AttachmentOwner, ownerId: string) =>
+  owner === 'note' ? `/api/notes/${ownerId}/attachments` : `/api/finance/${owner}s/${ownerId}/attachments`;
+
+export const attachmentsApi = {
+  list: (owner: AttachmentOwner, ownerId: string) =>
+    apiRequest<Attachment[]>(() => apiClient.get(ownerPath(owner, ownerId))),
+  // Receipts accept images and PDFs; notes also accept plain text
+  upload: (owner: AttachmentOwner, ownerId: string, file: File) => {
+    const form = new FormData();
+    form.append('file', file);
+    return apiRequest<Attachment>(() =>
+      apiClient.post(ownerPath(owner, ownerId), form, {
+        headers: { 'Content-Type': 'multipart/form-data' },
+        timeout: 60000,
+      })
+    );
+  },
+  delete: (id: string) => apiRequest(() => apiClient.delete(`/api/attachments/${id}`)),
+  // URLs for links and <img> tags; the file is sent as a download, the thumbnail inline
+  downloadUrl: (id: string) => `${API_BASE_URL}/api/attachments/${id}`,
+  thumbnailUrl: (id: string) => `${API_BASE_URL}/api/attachments/${id}/thumbnail`,
+};
@@ -24,6 +24,11 @@ const createApiClient = (): AxiosInstance => {
         if (token) {
           config.headers.Authorization = `Bearer ${token}`;
         }
+        // Household member name, recorded in the audit log
+        const actor = localStorage.getItem('actor_name');
+        if (actor) {
+          config.headers['X-Actor'] = actor;
+        }
       }
       return config;
     },
@@ -45,6 +50,8 @@ const createApiClient = (): AxiosInstance => {
         const apiError: ApiError = {
           error: responseData?.error || 'An error occurred',
           details: responseData?.details,
+          status: error.response.status,
+          current: responseData?.current,
         };
         return Promise.reject(apiError);
       } else if (error.request) {
@@ -87,6 +94,13 @@ export const apiRequest = async <T>(
   }
 };
 
+// ifMatch builds the If-Match header for updates; the backend rejects stale
+// versions with 412 and returns the current record. Without a version the
+// update is applied unconditionally.
+export const ifMatch = (version?: number) => ({
+  headers: { 'If-Match': version ? `"${version}"` : '*' },
+});
+
 // Health check function
 export const checkApiHealth = async (): Promise<boolean> => {
   try {
@@ -0,0 +1,106 @@
+import { apiClient, apiRequest } from './client';
+
+export type DebtKind = 'loan' | 'credit_card';
+
+export type PayoffStrategy = 'snowball' | 'avalanche';
+
+export interface DebtPayload {
+  name: string;
+  kind: DebtKind;
+  // What is owed on started_on, which defaults to today
+  principal: number;
+  apr: number;
+  minimum_payment: number;
+  due_day: number;
+  started_on?: string;
+}
+
+export interface Debt extends Required<DebtPayload> {
+  id: string;
+  user_id: string;
+  // Payments made and interest charged since started_on
+  paid: number;
+  interest: number;
+  balance: number;
+  next_due_date: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface DebtPaymentPayload {
+  amount: number;
+  paid_at: string;
+  // Default to "Debt repayment" and the debt's name
+  category?: string;
+  description?: string;
+}
+
+export interface DebtScheduleEntry {
+  month: number;
+  due_date: string;
+  payment: number;
+  interest: number;
+  principal: number;
+  balance: number;
+}
+
+export interface DebtSchedule {
+  debt_id: string;
+  balance: number;
+  payment: number;
+  months: number;
+  total_interest: number;
+  total_paid: number;
+  payoff_date: string | null;
+  schedule: DebtScheduleEntry[];
+}
+
+export interface DebtPayoffMonth {
+  index: number;
+  year: number;
+  month: number;
+  payment: number;
+  interest: number;
+  balance: number;
+  debts: { debt_id: string; payment: number; interest: number; balance: number }[];
+}
+
+export interface DebtPayoffPlan {
+  strategy: PayoffStrategy;
+  // Debts in the order extra payments go to them
+  order: string[];
+  months: number;
+  total_interest: number;
+  total_paid: number;
+  debts: { debt_id: string; name: string; paid_off_month: number; interest: number; paid: number }[];
+  schedule: DebtPayoffMonth[];
+}
+
+export interface DebtPayoff {
+  extra_monthly: number;
+  monthly_budget: number;
+  balance: number;
+  snowball: DebtPayoffPlan;
+  avalanche: DebtPayoffPlan;
+  interest_saved: number;
+  recommended: PayoffStrategy;
+}
+
+export const debtsApi = {
+  listDebts: () => apiRequest<Debt[]>(() => apiClient.get('/api/finance/debts')),
+  getDebt: (id: string) => apiRequest<Debt>(() => apiClient.get(`/api/finance/debts/${id}`)),
+  createDebt: (payload: DebtPayload) => apiRequest<Debt>(() => apiClient.post('/api/finance/debts', payload)),
+  updateDebt: (id: string, updates: Partial<DebtPayload>) =>
+    apiRequest<Debt>(() => apiClient.put(`/api/finance/debts/${id}`, updates)),
+  // Payments are kept as ordinary expenses
+  deleteDebt: (id: string) => apiRequest(() => apiClient.delete(`/api/finance/debts/${id}`)),
+  listPayments: (id: string) => apiRequest(() => apiClient.get(`/api/finance/debts/${id}/payments`)),
+  recordPayment: (id: string, payload: DebtPaymentPayload) =>
+    apiRequest(() => apiClient.post(`/api/finance/debts/${id}/payments`, payload)),
+  // payment replaces the minimum payment on every due date
+  getSchedule: (id: string, payment?: number) =>
+    apiRequest<DebtSchedule>(() => apiClient.get(`/api/finance/debts/${id}/schedule`, { params: { payment } })),
+  // Compares snowball and avalanche with extra paid on top of the minimums each month
+  planPayoff: (extra?: number) =>
+    apiRequest<DebtPayoff>(() => apiClient.get('/api/finance/debts/payoff', { params: { extra } })),
+};
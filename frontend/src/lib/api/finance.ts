@@ -1,36 +1,196 @@
-import { apiClient, apiRequest } from './client';
+import { apiClient, apiRequest, ifMatch } from './client';
 
 export interface IncomePayload {
   source?: string;
   amount: number;
   received_at: string;
+  tags?: string[];
+  // Links the income to a security as a dividend
+  security_id?: string | null;
 }
 
 export interface ExpensePayload {
   category?: string;
+  // Takes precedence over category when both are sent
+  category_id?: string;
   description?: string;
   amount: number;
   spent_at: string;
   goal_id?: string | null;
+  // Omit to link the merchant matching the description
+  merchant_id?: string | null;
+  // Records the expense as a payment towards a debt
+  debt_id?: string | null;
+  tags?: string[];
+}
+
+export interface TagFilter {
+  tags?: string[];
+  // 'all' (the default) requires every tag, 'any' at least one
+  match?: 'any' | 'all';
+}
+
+const tagParams = (filter?: TagFilter) =>
+  filter?.tags?.length ? { tag: filter.tags.join(','), tag_match: filter.match } : undefined;
+
+export interface ForecastOptions {
+  months?: number;
+  history?: number;
+  // YYYY-MM months left out of the averages
+  exclude?: string[];
+  excludeOutliers?: boolean;
+  // Replaces the balance derived from the ledger
+  startingBalance?: number;
+}
+
+export interface ForecastMonth {
+  year: number;
+  month: number;
+  income: number;
+  expenses: number;
+  goal_savings: number;
+  net: number;
+  balance: number;
+  balance_low: number;
+  balance_high: number;
+  negative: boolean;
+}
+
+export interface Forecast {
+  starting_balance: number;
+  history_months: number;
+  excluded_months: string[];
+  confidence_level: number;
+  recurring: {
+    type: 'income' | 'expense';
+    name: string;
+    merchant_id?: string;
+    category_id?: string;
+    category?: string;
+    amount: number;
+    occurrences: number;
+  }[];
+  category_averages: { category_id: string | null; category: string; average: number }[];
+  variable_income: number;
+  months: ForecastMonth[];
+}
+
+export type BatchMode = 'all_or_nothing' | 'best_effort';
+
+export interface BatchOperation<T> {
+  op: 'create' | 'update' | 'delete';
+  id?: string;
+  version?: number;
+  data?: T;
+}
+
+export interface BatchItemResult {
+  index: number;
+  op: BatchOperation<unknown>['op'];
+  id?: string;
+  status: number;
+  data?: unknown;
+  error?: string;
+  details?: string;
+  current?: unknown;
+}
+
+export interface BatchResult {
+  mode: BatchMode;
+  succeeded: number;
+  failed: number;
+  results: BatchItemResult[];
+}
+
+export interface CategorySuggestion {
+  category: string;
+  confidence: number;
+  alternatives: { category: string; confidence: number }[];
+  trained_on: number;
+}
+
+export interface Category {
+  id: string;
+  user_id: string;
+  parent_id: string | null;
+  name: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface CategoryTotal {
+  category_id: string;
+  name: string;
+  total: number;
+  subtotal: number;
+  children: CategoryTotal[];
 }
 
 export const financeApi = {
   createIncome: (payload: IncomePayload) =>
     apiRequest(() => apiClient.post('/api/finance/incomes', payload)),
-  listIncomes: () => apiRequest(() => apiClient.get('/api/finance/incomes')),
-  updateIncome: (id: string, updates: Partial<IncomePayload>) =>
-    apiRequest(() => apiClient.put(`/api/finance/incomes/${id}`, updates)),
+  listIncomes: (filter?: TagFilter) =>
+    apiRequest(() => apiClient.get('/api/finance/incomes', { params: tagParams(filter) })),
+  getIncome: (id: string) => apiRequest(() => apiClient.get(`/api/finance/incomes/${id}`)),
+  updateIncome: (id: string, updates: Partial<IncomePayload>, version?: number) =>
+    apiRequest(() => apiClient.put(`/api/finance/incomes/${id}`, updates, ifMatch(version))),
   deleteIncome: (id: string) => apiRequest(() => apiClient.delete(`/api/finance/incomes/${id}`)),
+  restoreIncome: (id: string) => apiRequest(() => apiClient.post(`/api/finance/incomes/${id}/restore`)),
+  batchIncomes: (operations: BatchOperation<Partial<IncomePayload>>[], mode?: BatchMode) =>
+    apiRequest<BatchResult>(() => apiClient.post('/api/finance/incomes/batch', { mode, operations })),
 
   createExpense: (payload: ExpensePayload) =>
     apiRequest(() => apiClient.post('/api/finance/expenses', payload)),
-  listExpenses: () => apiRequest(() => apiClient.get('/api/finance/expenses')),
-  updateExpense: (id: string, updates: Partial<ExpensePayload>) =>
-    apiRequest(() => apiClient.put(`/api/finance/expenses/${id}`, updates)),
+  listExpenses: (filter?: TagFilter) =>
+    apiRequest(() => apiClient.get('/api/finance/expenses', { params: tagParams(filter) })),
+  getExpense: (id: string) => apiRequest(() => apiClient.get(`/api/finance/expenses/${id}`)),
+  updateExpense: (id: string, updates: Partial<ExpensePayload>, version?: number) =>
+    apiRequest(() => apiClient.put(`/api/finance/expenses/${id}`, updates, ifMatch(version))),
   deleteExpense: (id: string) => apiRequest(() => apiClient.delete(`/api/finance/expenses/${id}`)),
+  restoreExpense: (id: string) => apiRequest(() => apiClient.post(`/api/finance/expenses/${id}/restore`)),
+  suggestCategory: (description: string, amount?: number) =>
+    apiRequest<CategorySuggestion>(() =>
+      apiClient.get('/api/finance/expenses/suggest', { params: { description, amount } })
+    ),
+  batchExpenses: (operations: BatchOperation<Partial<ExpensePayload>>[], mode?: BatchMode) =>
+    apiRequest<BatchResult>(() => apiClient.post('/api/finance/expenses/batch', { mode, operations })),
+
+  listCategories: () => apiRequest<Category[]>(() => apiClient.get('/api/finance/categories')),
+  createCategory: (name: string, parentId?: string) =>
+    apiRequest<Category>(() => apiClient.post('/api/finance/categories', { name, parent_id: parentId })),
+  getCategory: (id: string) => apiRequest<Category>(() => apiClient.get(`/api/finance/categories/${id}`)),
+  // parent_id: null moves the category to the top level
+  updateCategory: (id: string, updates: { name?: string; parent_id?: string | null }) =>
+    apiRequest<Category>(() => apiClient.put(`/api/finance/categories/${id}`, updates)),
+  // A category still in use needs reassignTo, which takes over its expenses and sub-categories
+  deleteCategory: (id: string, reassignTo?: string) =>
+    apiRequest(() =>
+      apiClient.delete(`/api/finance/categories/${id}`, { params: reassignTo ? { reassign_to: reassignTo } : undefined })
+    ),
+  mergeCategories: (targetId: string, sourceIds: string[]) =>
+    apiRequest<{ category: Category; moved_expenses: number }>(() =>
+      apiClient.post(`/api/finance/categories/${targetId}/merge`, { source_ids: sourceIds })
+    ),
+
+  bulkTags: (changes: { income_ids?: string[]; expense_ids?: string[]; add?: string[]; remove?: string[] }) =>
+    apiRequest<{ updated_incomes: number; updated_expenses: number }>(() =>
+      apiClient.post('/api/finance/tags/bulk', changes)
+    ),
+
+  forecast: (options: ForecastOptions = {}) =>
+    apiRequest<Forecast>(() =>
+      apiClient.get('/api/finance/forecast', {
+        params: {
+          months: options.months,
+          history: options.history,
+          exclude: options.exclude?.length ? options.exclude.join(',') : undefined,
+          exclude_outliers: options.excludeOutliers,
+          starting_balance: options.startingBalance,
+        },
+      })
+    ),
 
-  listCategories: () => apiRequest(() => apiClient.get('/api/finance/categories')),
-  createCategory: (name: string) => apiRequest(() => apiClient.post('/api/finance/categories', { name })),
+  listTrash: () => apiRequest(() => apiClient.get('/api/trash')),
 };
 
 
@@ -1,9 +1,12 @@
-import { apiClient, apiRequest } from './client';
+import { apiClient, apiRequest, ifMatch } from './client';
+import type { BatchMode, BatchOperation, BatchResult } from './finance';
 
 export interface GoalPayload {
   name: string;
   description?: string;
+  // category names a goal category; category_id takes precedence when both are sent
   category?: string;
+  category_id?: string | null;
   target_amount?: number; // For financial goals
   target_value?: number; // For numeric goals (books to read, etc.)
   target_date?: string;
@@ -11,14 +14,34 @@ export interface GoalPayload {
   is_main_goal?: boolean;
   goal_type?: 'financial' | 'numeric' | 'boolean' | 'habit'; // Type of goal
   progress_type?: 'amount' | 'percentage' | 'count' | 'completion'; // How progress is tracked
+  // Month-end surplus fills goals in priority order (1 first) and splits by weight within a priority
+  allocation_priority?: number;
+  allocation_weight?: number;
 }
 
+export type GoalStatus = 'active' | 'paused' | 'completed' | 'archived' | 'abandoned';
+
 export interface GoalContributionPayload {
   goal_id: string;
   amount: number;
   contributed_at: string;
 }
 
+export interface GoalWithdrawalPayload {
+  amount: number;
+  reason: string;
+  withdrawn_at: string;
+}
+
+export interface GoalStatusChange {
+  id: string;
+  goal_id: string;
+  from_status: GoalStatus;
+  to_status: GoalStatus;
+  note: string;
+  changed_at: string;
+}
+
 export interface GoalProgressPayload {
   goal_id: string;
   progress_value: number; // Could be amount, count, percentage, etc.
@@ -26,13 +49,26 @@ export interface GoalProgressPayload {
   recorded_at: string;
 }
 
+// Built-in categories have no user_id and cannot be changed; users hide their own instead of deleting them
 export interface GoalCategory {
   id: string;
+  user_id: string | null;
   name: string;
   description: string;
   icon: string;
   color: string;
+  built_in: boolean;
+  hidden: boolean;
   created_at: string;
+  updated_at: string;
+}
+
+export interface GoalCategoryPayload {
+  name: string;
+  description?: string;
+  icon?: string;
+  color?: string;
+  hidden?: boolean;
 }
 
 export interface GoalWithSubgoals {
@@ -41,6 +77,7 @@ export interface GoalWithSubgoals {
     name: string;
     description: string;
     category: string;
+    category_id: string | null;
     target_amount?: number;
     target_value?: number;
     target_date?: string;
@@ -58,6 +95,7 @@ export interface GoalWithSubgoals {
     name: string;
     description: string;
     category: string;
+    category_id: string | null;
     target_amount?: number;
     target_value?: number;
     target_date?: string;
@@ -72,6 +110,144 @@ export interface GoalWithSubgoals {
   }>;
 }
 
+export type GoalPaceStatus = 'completed' | 'ahead' | 'on_track' | 'behind' | 'unscheduled';
+
+export interface GoalWithProgress {
+  goal: GoalWithSubgoals['goal'] & { version: number; status: GoalStatus; status_changed_at: string | null };
+  contributed_sum: number;
+  expense_sum: number;
+  progress: number;
+  remaining: number;
+  // Monthly contribution that reaches the target by its date; null without a target date
+  required_monthly: number | null;
+  // Average monthly contribution over the last pace_window_months months
+  pace: number;
+  pace_window_months: number;
+  projected_completion: string | null;
+  pace_status: GoalPaceStatus;
+}
+
+export type FundingRuleType = 'percent_of_income' | 'round_up' | 'fixed_monthly';
+
+// percent_of_income uses percent and income_source, round_up uses round_to,
+// fixed_monthly uses amount and day_of_month
+export interface FundingRulePayload {
+  goal_id: string;
+  name: string;
+  type: FundingRuleType;
+  enabled?: boolean;
+  percent?: number;
+  // Omit to fund from every income
+  income_source?: string;
+  round_to?: number;
+  amount?: number;
+  day_of_month?: number;
+}
+
+export interface FundingRule {
+  id: string;
+  goal_id: string;
+  name: string;
+  type: FundingRuleType;
+  enabled: boolean;
+  percent: number | null;
+  income_source: string | null;
+  round_to: number | null;
+  amount: number | null;
+  day_of_month: number | null;
+  last_funded_on: string | null;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface GoalContribution {
+  id: string;
+  goal_id: string;
+  amount: number;
+  contributed_at: string;
+  funding_rule_id?: string;
+  source_id?: string;
+  // Set on withdrawals, whose amount is negative
+  reason?: string | null;
+}
+
+// Parent goals pass their amount on to their sub-goals and are listed before them
+export interface GoalAllocation {
+  goal_id: string;
+  name: string;
+  parent_goal_id: string | null;
+  priority: number;
+  weight: number;
+  monthly_need: number;
+  amount: number;
+}
+
+export interface SurplusAllocationPayload {
+  year?: number;
+  month?: number;
+  // Allocate only part of the surplus
+  amount?: number;
+}
+
+export interface SurplusAllocation {
+  year: number;
+  month: number;
+  income: number;
+  expenses: number;
+  contributed: number;
+  surplus: number;
+  available: number;
+  allocated: number;
+  unallocated: number;
+  allocations: GoalAllocation[];
+  committed: boolean;
+  contributions?: GoalContribution[];
+}
+
+// Targets are formulas over avg_monthly_expenses, avg_monthly_income and, in sub-goals, parent
+export interface TemplateGoal {
+  name: string;
+  description?: string;
+  category?: string;
+  target: string;
+  // The target date is this many months after the goal is created; 0 leaves it open
+  months?: number;
+  subgoals?: TemplateGoal[];
+}
+
+export interface GoalTemplate {
+  id: string;
+  user_id: string | null;
+  name: string;
+  description: string;
+  built_in: boolean;
+  goal: Required<TemplateGoal>;
+  created_at: string;
+  updated_at: string;
+}
+
+// Either a goal definition or the goal_id of an existing goal tree to save
+export interface GoalTemplatePayload {
+  name: string;
+  description?: string;
+  goal?: TemplateGoal;
+  goal_id?: string;
+}
+
+// The averages replace the user's own in the target formulas
+export interface GoalTemplateInstancePayload {
+  name?: string;
+  avg_monthly_expenses?: number;
+  avg_monthly_income?: number;
+}
+
+export interface GoalTemplateInstance {
+  template_id: string;
+  avg_monthly_expenses: number;
+  avg_monthly_income: number;
+  goals: GoalWithProgress['goal'][];
+}
+
 export interface GoalExpensePayload {
   goal_id: string;
   expense_id: string;
@@ -82,13 +258,60 @@ export interface GoalExpensePayload {
 export const goalsApi = {
   createGoal: (payload: GoalPayload) =>
     apiRequest(() => apiClient.post('/api/finance/goals', payload)),
-  updateGoal: (id: string, updates: Partial<GoalPayload>) =>
-    apiRequest(() => apiClient.put(`/api/finance/goals/${id}`, updates)),
+  getGoal: (id: string) => apiRequest(() => apiClient.get(`/api/finance/goals/${id}`)),
+  updateGoal: (id: string, updates: Partial<GoalPayload>, version?: number) =>
+    apiRequest(() => apiClient.put(`/api/finance/goals/${id}`, updates, ifMatch(version))),
   deleteGoal: (id: string) => apiRequest(() => apiClient.delete(`/api/finance/goals/${id}`)),
-  listGoals: () => apiRequest(() => apiClient.get('/api/finance/goals')),
+  restoreGoal: (id: string) => apiRequest(() => apiClient.post(`/api/finance/goals/${id}/restore`)),
+  // Paused and archived goals are only listed when their status is asked for
+  listGoals: (paceWindow?: number, statuses?: GoalStatus[]) =>
+    apiRequest<GoalWithProgress[]>(() =>
+      apiClient.get('/api/finance/goals', {
+        params: { pace_window: paceWindow || undefined, status: statuses?.length ? statuses.join(',') : undefined },
+      })
+    ),
+  changeGoalStatus: (id: string, status: GoalStatus, note?: string, version?: number) =>
+    apiRequest(() => apiClient.post(`/api/finance/goals/${id}/status`, { status, note }, ifMatch(version))),
+  getGoalStatusHistory: (id: string) =>
+    apiRequest<GoalStatusChange[]>(() => apiClient.get(`/api/finance/goals/${id}/status-history`)),
+  withdrawFromGoal: (id: string, payload: GoalWithdrawalPayload) =>
+    apiRequest<GoalContribution>(() => apiClient.post(`/api/finance/goals/${id}/withdrawals`, payload)),
   
   contributeToGoal: (payload: GoalContributionPayload) =>
     apiRequest(() => apiClient.post('/api/finance/goals/contributions', payload)),
+  batchContributions: (operations: BatchOperation<Partial<GoalContributionPayload>>[], mode?: BatchMode) =>
+    apiRequest<BatchResult>(() => apiClient.post('/api/finance/goals/contributions/batch', { mode, operations })),
+  
+  listFundingRules: (goalId?: string) =>
+    apiRequest<FundingRule[]>(() =>
+      apiClient.get('/api/finance/funding-rules', { params: goalId ? { goal_id: goalId } : undefined })
+    ),
+  createFundingRule: (payload: FundingRulePayload) =>
+    apiRequest<FundingRule>(() => apiClient.post('/api/finance/funding-rules', payload)),
+  getFundingRule: (id: string) => apiRequest<FundingRule>(() => apiClient.get(`/api/finance/funding-rules/${id}`)),
+  updateFundingRule: (id: string, updates: Partial<Omit<FundingRulePayload, 'goal_id' | 'type'>>) =>
+    apiRequest<FundingRule>(() => apiClient.put(`/api/finance/funding-rules/${id}`, updates)),
+  deleteFundingRule: (id: string) => apiRequest(() => apiClient.delete(`/api/finance/funding-rules/${id}`)),
+  // Generates the fixed monthly contributions that are due now
+  runFundingRules: () =>
+    apiRequest<{ contributions: GoalContribution[] }>(() => apiClient.post('/api/finance/funding-rules/run')),
+  
+  // Month-end surplus allocation across goals
+  previewAllocation: (payload: SurplusAllocationPayload = {}) =>
+    apiRequest<SurplusAllocation>(() => apiClient.get('/api/finance/goals/allocation', { params: payload })),
+  commitAllocation: (payload: SurplusAllocationPayload = {}) =>
+    apiRequest<SurplusAllocation>(() => apiClient.post('/api/finance/goals/allocation', payload)),
+  
+  // Goal templates create a main goal with its sub-goals
+  listGoalTemplates: () => apiRequest<GoalTemplate[]>(() => apiClient.get('/api/finance/goals/templates')),
+  createGoalTemplate: (payload: GoalTemplatePayload) =>
+    apiRequest<GoalTemplate>(() => apiClient.post('/api/finance/goals/templates', payload)),
+  getGoalTemplate: (id: string) => apiRequest<GoalTemplate>(() => apiClient.get(`/api/finance/goals/templates/${id}`)),
+  updateGoalTemplate: (id: string, updates: Partial<Omit<GoalTemplatePayload, 'goal_id'>>) =>
+    apiRequest<GoalTemplate>(() => apiClient.put(`/api/finance/goals/templates/${id}`, updates)),
+  deleteGoalTemplate: (id: string) => apiRequest(() => apiClient.delete(`/api/finance/goals/templates/${id}`)),
+  instantiateGoalTemplate: (id: string, payload: GoalTemplateInstancePayload = {}) =>
+    apiRequest<GoalTemplateInstance>(() => apiClient.post(`/api/finance/goals/templates/${id}/instantiate`, payload)),
   
   updateGoalProgress: (payload: GoalProgressPayload) =>
     apiRequest(() => apiClient.post('/api/finance/goals/progress', payload)),
@@ -96,7 +319,15 @@ export const goalsApi = {
   completeGoal: (goalId: string) =>
     apiRequest(() => apiClient.post(`/api/finance/goals/${goalId}/complete`)),
   
-  listGoalCategories: () => apiRequest<GoalCategory[]>(() => apiClient.get('/api/finance/goals/categories')),
+  listGoalCategories: (includeHidden?: boolean) =>
+    apiRequest<GoalCategory[]>(() =>
+      apiClient.get('/api/finance/goals/categories', { params: includeHidden ? { include_hidden: true } : undefined })
+    ),
+  createGoalCategory: (payload: Omit<GoalCategoryPayload, 'hidden'>) =>
+    apiRequest<GoalCategory>(() => apiClient.post('/api/finance/goals/categories', payload)),
+  getGoalCategory: (id: string) => apiRequest<GoalCategory>(() => apiClient.get(`/api/finance/goals/categories/${id}`)),
+  updateGoalCategory: (id: string, updates: Partial<GoalCategoryPayload>) =>
+    apiRequest<GoalCategory>(() => apiClient.put(`/api/finance/goals/categories/${id}`, updates)),
   listMainGoalsWithSubgoals: () => apiRequest<GoalWithSubgoals[]>(() => apiClient.get('/api/finance/goals/hierarchical')),
   
   createGoalExpense: (payload: GoalExpensePayload) =>
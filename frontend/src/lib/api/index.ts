@@ -3,10 +3,37 @@ export { apiClient, apiRequest, checkApiHealth } from './client';
 export { notesApi } from './notes';
 export { financeApi } from './finance';
 export { goalsApi } from './goals';
+export { rulesApi } from './rules';
+export { merchantsApi } from './merchants';
+export { attachmentsApi } from './attachments';
+export { debtsApi } from './debts';
+export { netWorthApi } from './networth';
+export { investmentsApi } from './investments';
 export type { 
   GoalPayload, 
   GoalContributionPayload, 
   GoalCategory, 
+  GoalCategoryPayload,
   GoalWithSubgoals, 
+  GoalWithProgress,
+  GoalPaceStatus,
+  GoalStatus,
+  GoalStatusChange,
+  GoalWithdrawalPayload,
+  GoalContribution,
+  FundingRule,
+  FundingRulePayload,
+  FundingRuleType,
+  GoalAllocation,
+  SurplusAllocation,
+  SurplusAllocationPayload,
+  TemplateGoal,
+  GoalTemplate,
+  GoalTemplatePayload,
+  GoalTemplateInstance,
+  GoalTemplateInstancePayload,
   GoalExpensePayload 
-} from './goals';
\ No newline at end of file
+} from './goals';
+export type { Debt, DebtKind, DebtPayload, DebtPaymentPayload, DebtSchedule, DebtPayoff, DebtPayoffPlan, PayoffStrategy } from './debts';
+export type { NetWorth, NetWorthClass, NetWorthHistory, NetWorthItem, NetWorthItemPayload, Valuation, ValuationPayload } from './networth';
+export type { AssetClass, DividendPayload, Gains, Holding, Lot, LotPayload, Portfolio, PriceImport, Sale, SalePayload, Security, SecurityPayload, SecurityPrice } from './investments';
@@ -0,0 +1,137 @@
+import { apiClient, apiRequest } from './client';
+
+export type AssetClass = 'stock' | 'bond' | 'fund' | 'property' | 'commodity' | 'crypto' | 'cash' | 'other';
+
+export interface SecurityPayload {
+  // Stored upper case; unique among the user's securities
+  symbol: string;
+  // Defaults to the symbol
+  name?: string;
+  asset_class: AssetClass;
+}
+
+export interface Security extends Required<SecurityPayload> {
+  id: string;
+  user_id: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface LotPayload {
+  quantity: number;
+  // Total paid, fees included
+  cost: number;
+  bought_on: string;
+}
+
+export interface Lot extends LotPayload {
+  id: string;
+  security_id: string;
+  created_at: string;
+}
+
+export interface SalePayload {
+  quantity: number;
+  // Total received, net of fees
+  proceeds: number;
+  sold_on: string;
+}
+
+export interface Sale extends SalePayload {
+  id: string;
+  security_id: string;
+  created_at: string;
+}
+
+export interface DividendPayload {
+  amount: number;
+  received_at: string;
+  // Defaults to "<SYMBOL> dividend"
+  source?: string;
+  tags?: string[];
+}
+
+export interface SecurityPrice {
+  priced_on: string;
+  close: number;
+}
+
+export interface PriceImport {
+  imported: number;
+  // Lines of the file that were skipped
+  errors: { line: number; error: string }[];
+}
+
+export interface Gains {
+  cost_basis: number;
+  unrealised_gain: number;
+  realised_gain: number;
+}
+
+export interface Holding {
+  security: Security;
+  quantity: number;
+  // Null until a price is imported; the holding is then valued at its FIFO cost
+  price: number | null;
+  priced_on: string | null;
+  market_value: number;
+  dividends: number;
+  fifo: Gains;
+  average_cost: Gains;
+}
+
+export interface Portfolio {
+  date: string;
+  market_value: number;
+  dividends: number;
+  fifo: Gains;
+  average_cost: Gains;
+  // Largest first; share is a percent of the market value
+  allocation: { asset_class: AssetClass; market_value: number; share: number }[];
+  holdings: Holding[];
+}
+
+const securityPath = (id: string) => `/api/finance/investments/securities/${id}`;
+
+export const investmentsApi = {
+  // Values the holdings at the end of date, today by default
+  getPortfolio: (date?: string) =>
+    apiRequest<Portfolio>(() => apiClient.get('/api/finance/investments', { params: { date } })),
+
+  listSecurities: () => apiRequest<Security[]>(() => apiClient.get('/api/finance/investments/securities')),
+  getSecurity: (id: string) => apiRequest<Security>(() => apiClient.get(securityPath(id))),
+  createSecurity: (payload: SecurityPayload) =>
+    apiRequest<Security>(() => apiClient.post('/api/finance/investments/securities', payload)),
+  updateSecurity: (id: string, updates: Partial<SecurityPayload>) =>
+    apiRequest<Security>(() => apiClient.put(securityPath(id), updates)),
+  // Removes its lots, sales and prices; its dividends are kept as incomes
+  deleteSecurity: (id: string) => apiRequest(() => apiClient.delete(securityPath(id))),
+
+  listLots: (id: string) => apiRequest<Lot[]>(() => apiClient.get(`${securityPath(id)}/lots`)),
+  createLot: (id: string, payload: LotPayload) =>
+    apiRequest<Lot>(() => apiClient.post(`${securityPath(id)}/lots`, payload)),
+  deleteLot: (id: string, lotId: string) => apiRequest(() => apiClient.delete(`${securityPath(id)}/lots/${lotId}`)),
+
+  listSales: (id: string) => apiRequest<Sale[]>(() => apiClient.get(`${securityPath(id)}/sales`)),
+  createSale: (id: string, payload: SalePayload) =>
+    apiRequest<Sale>(() => apiClient.post(`${securityPath(id)}/sales`, payload)),
+  deleteSale: (id: string, saleId: string) => apiRequest(() => apiClient.delete(`${securityPath(id)}/sales/${saleId}`)),
+
+  listDividends: (id: string) => apiRequest(() => apiClient.get(`${securityPath(id)}/dividends`)),
+  recordDividend: (id: string, payload: DividendPayload) =>
+    apiRequest(() => apiClient.post(`${securityPath(id)}/dividends`, payload)),
+
+  listPrices: (id: string) => apiRequest<SecurityPrice[]>(() => apiClient.get(`${securityPath(id)}/prices`)),
+  // CSV with date, close and symbol columns; symbol names the security when the file has none
+  importPrices: (file: File, symbol?: string) => {
+    const form = new FormData();
+    form.append('file', file);
+    return apiRequest<PriceImport>(() =>
+      apiClient.post('/api/finance/investments/prices/import', form, {
+        params: { symbol },
+        headers: { 'Content-Type': 'multipart/form-data' },
+        timeout: 60000,
+      })
+    );
+  },
+};
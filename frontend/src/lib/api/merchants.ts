@@ -0,0 +1,62 @@
+import { apiClient, apiRequest } from './client';
+
+export type MerchantMatchType = 'exact' | 'prefix' | 'contains';
+
+export interface MerchantPatternPayload {
+  pattern: string;
+  match_type?: MerchantMatchType;
+}
+
+export interface MerchantPattern {
+  id: string;
+  pattern: string;
+  match_type: MerchantMatchType;
+  created_at: string;
+}
+
+export interface Merchant {
+  id: string;
+  user_id: string;
+  name: string;
+  patterns: MerchantPattern[];
+  created_at: string;
+  updated_at: string;
+}
+
+export interface MerchantSpending {
+  merchant_id: string;
+  name: string;
+  total: number;
+  previous_total: number;
+  change: number;
+  change_percent: number | null;
+}
+
+export interface ResolveMerchantsResult {
+  dry_run: boolean;
+  evaluated: number;
+  linked: number;
+  skipped: number;
+}
+
+export const merchantsApi = {
+  listMerchants: () => apiRequest<Merchant[]>(() => apiClient.get('/api/finance/merchants')),
+  getMerchant: (id: string) => apiRequest<Merchant>(() => apiClient.get(`/api/finance/merchants/${id}`)),
+  createMerchant: (payload: { name: string; patterns?: MerchantPatternPayload[] }) =>
+    apiRequest<Merchant>(() => apiClient.post('/api/finance/merchants', payload)),
+  renameMerchant: (id: string, name: string) =>
+    apiRequest<Merchant>(() => apiClient.put(`/api/finance/merchants/${id}`, { name })),
+  deleteMerchant: (id: string) => apiRequest(() => apiClient.delete(`/api/finance/merchants/${id}`)),
+  addPattern: (id: string, payload: MerchantPatternPayload) =>
+    apiRequest<Merchant>(() => apiClient.post(`/api/finance/merchants/${id}/patterns`, payload)),
+  deletePattern: (id: string, patternId: string) =>
+    apiRequest(() => apiClient.delete(`/api/finance/merchants/${id}/patterns/${patternId}`)),
+  // Fold duplicate merchants into the target, moving their expenses and patterns
+  mergeMerchants: (targetId: string, sourceIds: string[]) =>
+    apiRequest<{ merchant: Merchant; moved_expenses: number }>(() =>
+      apiClient.post(`/api/finance/merchants/${targetId}/merge`, { source_ids: sourceIds })
+    ),
+  // Link existing expenses to merchants; dry_run only counts them
+  resolveMerchants: (payload: { overwrite?: boolean; dry_run?: boolean }) =>
+    apiRequest<ResolveMerchantsResult>(() => apiClient.post('/api/finance/merchants/resolve', payload)),
+};
@@ -0,0 +1,80 @@
+import { apiClient, apiRequest } from './client';
+
+export type NetWorthClass = 'asset' | 'liability';
+
+export interface NetWorthItemPayload {
+  name: string;
+  class: NetWorthClass;
+  // Assets: cash, property, vehicle, investment or other; liabilities: mortgage, loan, credit_card or other
+  type: string;
+  description?: string;
+  // Records the first valuation, dated valued_on or today
+  value?: number;
+  valued_on?: string;
+}
+
+export interface NetWorthItem {
+  id: string;
+  user_id: string;
+  name: string;
+  class: NetWorthClass;
+  type: string;
+  description: string;
+  // Null until the item is first valued
+  value: number | null;
+  valued_on: string | null;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ValuationPayload {
+  value: number;
+  // Defaults to today; replaces any valuation already made that day
+  valued_on?: string;
+  note?: string;
+}
+
+export interface Valuation extends Required<ValuationPayload> {
+  id: string;
+  item_id: string;
+  created_at: string;
+}
+
+export interface NetWorth {
+  date: string;
+  assets: number;
+  liabilities: number;
+  net_worth: number;
+  // cash is the ledger balance, savings what goals hold; loan and credit_card include tracked debts
+  breakdown: { class: NetWorthClass; type: string; amount: number }[];
+}
+
+export interface NetWorthHistory {
+  // Month-end snapshots, oldest first
+  snapshots: NetWorth[];
+  current: NetWorth;
+}
+
+export const netWorthApi = {
+  getNetWorth: () => apiRequest<NetWorth>(() => apiClient.get('/api/finance/net-worth')),
+  getHistory: (months?: number) =>
+    apiRequest<NetWorthHistory>(() => apiClient.get('/api/finance/net-worth/history', { params: { months } })),
+  // Stores last month's snapshot if the scheduler has not yet
+  runSnapshots: () =>
+    apiRequest<{ snapshots: NetWorth[] }>(() => apiClient.post('/api/finance/net-worth/snapshots/run')),
+
+  listItems: () => apiRequest<NetWorthItem[]>(() => apiClient.get('/api/finance/net-worth/items')),
+  getItem: (id: string) => apiRequest<NetWorthItem>(() => apiClient.get(`/api/finance/net-worth/items/${id}`)),
+  createItem: (payload: NetWorthItemPayload) =>
+    apiRequest<NetWorthItem>(() => apiClient.post('/api/finance/net-worth/items', payload)),
+  updateItem: (id: string, updates: { name?: string; type?: string; description?: string }) =>
+    apiRequest<NetWorthItem>(() => apiClient.put(`/api/finance/net-worth/items/${id}`, updates)),
+  deleteItem: (id: string) => apiRequest(() => apiClient.delete(`/api/finance/net-worth/items/${id}`)),
+
+  listValuations: (id: string) =>
+    apiRequest<Valuation[]>(() => apiClient.get(`/api/finance/net-worth/items/${id}/valuations`)),
+  createValuation: (id: string, payload: ValuationPayload) =>
+    apiRequest<Valuation>(() => apiClient.post(`/api/finance/net-worth/items/${id}/valuations`, payload)),
+  deleteValuation: (id: string, valuationId: string) =>
+    apiRequest(() => apiClient.delete(`/api/finance/net-worth/items/${id}/valuations/${valuationId}`)),
+};
@@ -5,7 +5,7 @@ import type {
   SearchNotesParams,
   ApiResponse 
 } from '@/types/notes';
-import { apiClient, apiRequest } from './client';
+import { apiClient, apiRequest, ifMatch } from './client';
 
 // Utility function to build query parameters
 const buildQueryParams = (params: SearchNotesParams): string => {
@@ -39,9 +39,9 @@ export const notesApi = {
     return apiRequest(() => apiClient.post<Note>('/api/notes', noteData));
   },
 
-  // Update an existing note
-  async updateNote(id: string, noteData: UpdateNoteRequest): Promise<ApiResponse<Note>> {
-    return apiRequest(() => apiClient.put<Note>(`/api/notes/${id}`, noteData));
+  // Update an existing note; pass the loaded version to detect concurrent edits
+  async updateNote(id: string, noteData: UpdateNoteRequest, version?: number): Promise<ApiResponse<Note>> {
+    return apiRequest(() => apiClient.put<Note>(`/api/notes/${id}`, noteData, ifMatch(version)));
   },
 
   // Delete a note
@@ -49,6 +49,11 @@ export const notesApi = {
     return apiRequest(() => apiClient.delete(`/api/notes/${id}`));
   },
 
+  // Restore a note from the trash
+  async restoreNote(id: string): Promise<ApiResponse<{ message: string }>> {
+    return apiRequest(() => apiClient.post(`/api/notes/${id}/restore`));
+  },
+
   // Toggle favorite status
   async toggleFavorite(id: string, isFavorite: boolean): Promise<ApiResponse<Note>> {
     return this.updateNote(id, { is_favorite: isFavorite });
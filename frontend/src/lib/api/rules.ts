@@ -0,0 +1,73 @@
+import { apiClient, apiRequest } from './client';
+
+export type RuleField = 'description' | 'category' | 'amount';
+export type RuleOperator = 'contains' | 'equals' | 'starts_with' | 'ends_with' | 'gt' | 'gte' | 'lt' | 'lte';
+
+export interface RuleCondition {
+  field: RuleField;
+  operator: RuleOperator;
+  value: string;
+}
+
+export interface RulePayload {
+  name: string;
+  priority?: number;
+  enabled?: boolean;
+  conditions: RuleCondition[];
+  set_category?: string;
+  set_goal_id?: string;
+  clear_goal?: boolean;
+}
+
+export interface ExpenseRule extends Required<Omit<RulePayload, 'set_category' | 'set_goal_id'>> {
+  id: string;
+  set_category: string | null;
+  set_goal_id: string | null;
+  match_count: number;
+  last_matched_at: string | null;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ApplyRulesPayload {
+  dry_run?: boolean;
+  rule_ids?: string[];
+  from?: string;
+  to?: string;
+}
+
+export interface RuleOutcome {
+  category: string;
+  goal_id: string | null;
+}
+
+export interface ApplyRulesResult {
+  dry_run: boolean;
+  evaluated: number;
+  matched: number;
+  changed: number;
+  skipped: number;
+  changes: {
+    expense_id: string;
+    description: string;
+    amount: number;
+    spent_at: string;
+    before: RuleOutcome;
+    after: RuleOutcome;
+    rule_ids: string[];
+  }[];
+  rules: { rule_id: string; name: string; matches: number }[];
+}
+
+export const rulesApi = {
+  listRules: () => apiRequest<ExpenseRule[]>(() => apiClient.get('/api/finance/rules')),
+  getRule: (id: string) => apiRequest<ExpenseRule>(() => apiClient.get(`/api/finance/rules/${id}`)),
+  createRule: (payload: RulePayload) =>
+    apiRequest<ExpenseRule>(() => apiClient.post('/api/finance/rules', payload)),
+  updateRule: (id: string, updates: Partial<RulePayload>) =>
+    apiRequest<ExpenseRule>(() => apiClient.put(`/api/finance/rules/${id}`, updates)),
+  deleteRule: (id: string) => apiRequest(() => apiClient.delete(`/api/finance/rules/${id}`)),
+  // Re-run rules over existing expenses; dry_run previews the changes
+  applyRules: (payload: ApplyRulesPayload) =>
+    apiRequest<ApplyRulesResult>(() => apiClient.post('/api/finance/rules/apply', payload)),
+};
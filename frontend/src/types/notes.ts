@@ -6,6 +6,7 @@ export interface Note {
   tags: string[];
   is_favorite: boolean;
   is_archived: boolean;
+  version: number;
   created_at: string;
   updated_at: string;
 }
@@ -53,6 +54,8 @@ export interface SearchNotesParams {
 export interface ApiError {
   error: string;
   details?: string;
+  status?: number;
+  current?: unknown; // Current server record on 412 conflicts
 }
 
 // API Response wrapper